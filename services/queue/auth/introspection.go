@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"gin-quickstart/config"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Auth modes selectable with AUTH_MODE
+const (
+	// ModeLocal decodes tokens in the service
+	ModeLocal = "local"
+	// ModeIntrospect asks the auth service whether a token is active
+	ModeIntrospect = "introspect"
+)
+
+// introspectPath is the auth service's token introspection endpoint (RFC 7662)
+const introspectPath = "/api/auth/introspect"
+
+// maxCachedTokens bounds the introspection cache; a full cache drops its
+// expired results, then everything
+const maxCachedTokens = 10000
+
+// ErrInactiveToken is returned for tokens the auth service doesn't consider
+// active (expired, revoked or never issued)
+var ErrInactiveToken = errors.New("token is not active")
+
+// Introspector validates tokens against the auth service, caching each
+// answer briefly so a burst of requests with one token costs one call
+type Introspector struct {
+	endpoint string
+	token    string
+	cacheTTL time.Duration
+	client   *http.Client
+
+	mu    sync.Mutex
+	cache map[string]introspection
+	fills singleflight.Group
+}
+
+type introspection struct {
+	claims    map[string]interface{} // nil for an inactive token
+	expiresAt time.Time
+}
+
+// NewIntrospector creates the introspection client. Calls share one timeout,
+// so an unreachable auth service fails requests after AUTH_INTROSPECTION_TIMEOUT_MS.
+func NewIntrospector(cfg *config.Config) *Introspector {
+	return &Introspector{
+		endpoint: strings.TrimRight(cfg.AuthServiceURL, "/") + introspectPath,
+		token:    cfg.AuthIntrospectionToken,
+		cacheTTL: time.Duration(cfg.AuthIntrospectionCacheSeconds) * time.Second,
+		client:   &http.Client{Timeout: time.Duration(cfg.AuthIntrospectionTimeoutMs) * time.Millisecond},
+		cache:    make(map[string]introspection),
+	}
+}
+
+// Introspect returns the claims of an active token (id, name, email, role or
+// roles, ...), or ErrInactiveToken
+func (i *Introspector) Introspect(ctx context.Context, token string) (map[string]interface{}, error) {
+	sum := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(sum[:])
+
+	if result, ok := i.cached(key); ok {
+		return result.claims, result.err()
+	}
+
+	value, err, _ := i.fills.Do(key, func() (interface{}, error) {
+		result, err := i.introspect(context.WithoutCancel(ctx), token)
+		if err != nil {
+			return nil, err
+		}
+		i.store(key, result)
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := value.(introspection)
+	return result.claims, result.err()
+}
+
+func (r introspection) err() error {
+	if r.claims == nil {
+		return ErrInactiveToken
+	}
+	return nil
+}
+
+func (i *Introspector) cached(key string) (introspection, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	result, ok := i.cache[key]
+	if !ok || !time.Now().Before(result.expiresAt) {
+		return introspection{}, false
+	}
+	return result, true
+}
+
+func (i *Introspector) store(key string, result introspection) {
+	if i.cacheTTL <= 0 {
+		return
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if len(i.cache) >= maxCachedTokens {
+		now := time.Now()
+		for k, cached := range i.cache {
+			if !now.Before(cached.expiresAt) {
+				delete(i.cache, k)
+			}
+		}
+		if len(i.cache) >= maxCachedTokens {
+			clear(i.cache)
+		}
+	}
+	i.cache[key] = result
+}
+
+// introspect asks the auth service about token. An active token is cached
+// until the cache TTL or its own expiry, whichever comes first.
+func (i *Introspector) introspect(ctx context.Context, token string) (introspection, error) {
+	form := url.Values{"token": {token}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return introspection{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if i.token != "" {
+		req.Header.Set("Authorization", "Bearer "+i.token)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return introspection{}, fmt.Errorf("auth service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return introspection{}, fmt.Errorf("auth service returned HTTP %d", resp.StatusCode)
+	}
+
+	var claims map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&claims); err != nil {
+		return introspection{}, fmt.Errorf("invalid auth service response: %w", err)
+	}
+
+	expiresAt := time.Now().Add(i.cacheTTL)
+	if active, _ := claims["active"].(bool); !active {
+		return introspection{expiresAt: expiresAt}, nil
+	}
+
+	if exp, ok := claims["exp"].(float64); ok {
+		if tokenExpiry := time.Unix(int64(exp), 0); tokenExpiry.Before(expiresAt) {
+			expiresAt = tokenExpiry
+		}
+	}
+	// RFC 7662 names the user sub; the rest of the service reads id
+	if _, ok := claims["id"]; !ok {
+		if sub, ok := claims["sub"].(string); ok {
+			claims["id"] = sub
+		}
+	}
+	return introspection{claims: claims, expiresAt: expiresAt}, nil
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gin-quickstart/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// revokedTokenTTL keeps a revocation whose token expiry is unknown; it
+// should outlive the longest-lived token the auth service issues
+const revokedTokenTTL = 7 * 24 * time.Hour
+
+func revokedTokenKey(jti string) string {
+	return fmt.Sprintf("auth:revoked:%s", jti)
+}
+
+// revokedUserKey holds when all of a user's tokens were revoked (logout
+// everywhere, compromised account); tokens issued before then are refused
+func revokedUserKey(userID string) string {
+	return fmt.Sprintf("auth:revoked:user:%s", userID)
+}
+
+// RevokeToken blacklists a token by its jti until it expires. A zero
+// expiresAt keeps the revocation for the longest token lifetime.
+func RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return errors.New("redis not initialized")
+	}
+
+	ttl := revokedTokenTTL
+	if !expiresAt.IsZero() {
+		ttl = time.Until(expiresAt)
+		if ttl <= 0 {
+			// Already expired, so already refused
+			return nil
+		}
+	}
+	return rdb.Set(ctx, revokedTokenKey(jti), 1, ttl).Err()
+}
+
+// RevokeUserTokens refuses every token of a user issued before revokedAt. A
+// later revocation of the same user replaces an earlier one.
+func RevokeUserTokens(ctx context.Context, userID string, revokedAt time.Time) error {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return errors.New("redis not initialized")
+	}
+	return rdb.Set(ctx, revokedUserKey(userID), revokedAt.Unix(), revokedTokenTTL).Err()
+}
+
+// IsRevoked reports whether the token with these claims was revoked, by its
+// jti or by a revocation of all its user's tokens. Without Redis no token is
+// revoked.
+func IsRevoked(ctx context.Context, claims map[string]interface{}) (bool, error) {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return false, nil
+	}
+
+	jti, _ := claims["jti"].(string)
+	userID, _ := claims["id"].(string)
+	if jti == "" && userID == "" {
+		return false, nil
+	}
+
+	values, err := rdb.MGet(ctx, revokedTokenKey(jti), revokedUserKey(userID)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, err
+	}
+	if jti != "" && values[0] != nil {
+		return true, nil
+	}
+
+	if userID == "" || values[1] == nil {
+		return false, nil
+	}
+	revokedAt, err := strconv.ParseInt(fmt.Sprint(values[1]), 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("invalid user revocation of %s: %w", userID, err)
+	}
+	// Tokens without an issue time can't be told apart, so they are all refused
+	issuedAt, ok := claims["iat"].(float64)
+	return !ok || int64(issuedAt) < revokedAt, nil
+}
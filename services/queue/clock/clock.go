@@ -0,0 +1,140 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts the current time so expiry, estimation and scheduling logic
+// can run against simulated time in the standalone/test deployment
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks like time.Ticker
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real returns a Clock backed by the system clock
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                  { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.ticker.C }
+func (t realTicker) Stop()               { t.ticker.Stop() }
+
+// Simulated is a Clock that only moves when advanced. Tickers fire when
+// Advance or Set moves time past their next tick.
+type Simulated struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers map[*simulatedTicker]struct{}
+}
+
+// NewSimulated returns a simulated clock starting at start
+func NewSimulated(start time.Time) *Simulated {
+	return &Simulated{
+		now:     start,
+		tickers: make(map[*simulatedTicker]struct{}),
+	}
+}
+
+// Now returns the simulated time
+func (s *Simulated) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// Since returns the simulated time elapsed since t
+func (s *Simulated) Since(t time.Time) time.Duration {
+	return s.Now().Sub(t)
+}
+
+// NewTicker returns a ticker driven by the simulated time
+func (s *Simulated) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := &simulatedTicker{
+		clock:  s,
+		period: d,
+		next:   s.now.Add(d),
+		c:      make(chan time.Time, 1),
+	}
+	s.tickers[t] = struct{}{}
+	return t
+}
+
+// Advance moves the simulated time forward by d
+func (s *Simulated) Advance(d time.Duration) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.setLocked(s.now.Add(d))
+	return s.now
+}
+
+// Set moves the simulated time to t; it never moves backwards
+func (s *Simulated) Set(t time.Time) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.After(s.now) {
+		s.setLocked(t)
+	}
+	return s.now
+}
+
+func (s *Simulated) setLocked(now time.Time) {
+	s.now = now
+	for t := range s.tickers {
+		if now.Before(t.next) {
+			continue
+		}
+		// Like time.Ticker, drop ticks a slow receiver has not drained
+		select {
+		case t.c <- now:
+		default:
+		}
+		for !now.Before(t.next) {
+			t.next = t.next.Add(t.period)
+		}
+	}
+}
+
+type simulatedTicker struct {
+	clock  *Simulated
+	period time.Duration
+	next   time.Time
+	c      chan time.Time
+}
+
+func (t *simulatedTicker) C() <-chan time.Time { return t.c }
+
+func (t *simulatedTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	delete(t.clock.tickers, t)
+}
@@ -0,0 +1,43 @@
+package clock
+
+import "time"
+
+// zone is the restaurant's time zone; business days run from midnight to
+// midnight in it
+var zone = time.UTC
+
+// SetZone sets the restaurant's time zone
+func SetZone(loc *time.Location) {
+	zone = loc
+}
+
+// Zone returns the restaurant's time zone
+func Zone() *time.Location {
+	return zone
+}
+
+// Date returns the business day t falls on, as midnight UTC of that calendar
+// date (how DATE columns and the YYYY-MM-DD query parameters hold it)
+func Date(t time.Time) time.Time {
+	year, month, day := t.In(zone).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// DayStart returns the instant the business day of date (as Date returns
+// it) begins
+func DayStart(date time.Time) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, zone).UTC()
+}
+
+// DayBounds returns the instants the business day of date begins and ends,
+// for created_at >= start AND created_at < end queries. Days around a
+// daylight saving change are 23 or 25 hours long.
+func DayBounds(date time.Time) (start, end time.Time) {
+	return DayStart(date), DayStart(date.AddDate(0, 0, 1))
+}
+
+// Local returns t in the restaurant's time zone, for display
+func Local(t time.Time) time.Time {
+	return t.In(zone)
+}
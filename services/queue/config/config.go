@@ -1,20 +1,53 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
 	// Server
-	Port string
+	Port        string
+	ServiceName string
 
-	// Database
+	// Proxies (IPs or CIDRs, comma-separated) whose X-Forwarded-For is
+	// trusted for the client IP; none by default
+	TrustedProxies []string
+
+	// Networks (IPs or CIDRs, comma-separated) admin routes may be called
+	// from; empty allows any
+	AdminAllowedNetworks []string
+
+	// HTTP server TLS (plain HTTP without a certificate); with a client CA,
+	// callers must present a certificate it signed (mutual TLS)
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+
+	// Database ("mysql" or "postgres")
+	DBDriver   string
 	DBHost     string
 	DBPort     string
 	DBUser     string
 	DBPassword string
 	DBName     string
+	DBSSLMode  string
+	// Read replica DSN in the driver's format; empty reads from the primary
+	DBReplicaDSN string
+
+	// Database connection pool
+	DBMaxOpenConns           int
+	DBMaxIdleConns           int
+	DBConnMaxLifetimeSeconds int
+
+	// Database resilience
+	DBMaxRetries          int
+	DBRetryBackoffMs      int
+	DBBreakerMinRequests  int
+	DBBreakerFailureRatio float64
+	DBBreakerOpenSeconds  int
 
 	// Redis
 	RedisHost     string
@@ -22,16 +55,222 @@ type Config struct {
 	RedisPassword string
 	RedisDB       int
 
+	// Real-time queue updates over Redis ("pubsub" or "streams")
+	RealtimeTransport    string
+	RealtimeStreamMaxLen int
+	RealtimeStreamGroup  string
+
 	// Kafka
 	KafkaBrokers []string
 	KafkaGroupID string
 
-	// Auth Service
-	AuthServiceURL string
+	// How often the shared producer probes the brokers
+	KafkaProducerHealthIntervalSeconds int
+
+	// Kafka topic names
+	KafkaTopicOrderCreated       string
+	KafkaTopicOrderStatusChanged string
+	KafkaTopicOrderCancelled     string
+	KafkaTopicPaymentCompleted   string
+	KafkaTopicMenuUpdated        string
+	KafkaTopicTokenRevoked       string
+	KafkaTopicQueueEvents        string
+	KafkaTopicNotificationEvents string
+	KafkaTopicKitchenEvents      string
+	KafkaTopicDeadLetter         string
+
+	// Startup creation of missing topics
+	KafkaAutoCreateTopics       bool
+	KafkaTopicPartitions        int
+	KafkaTopicReplicationFactor int
+	KafkaTopicRetentionHours    int
+
+	// Kafka consumer retries for transient failures
+	KafkaConsumerMaxRetries     int
+	KafkaConsumerRetryBackoffMs int
+	KafkaConsumerMaxBackoffMs   int
+
+	// Event encoding (json, avro or protobuf) and Schema Registry for avro
+	KafkaEventEncoding     string
+	KafkaConsumerEncoding  string
+	SchemaRegistryURL      string
+	SchemaRegistryUsername string
+	SchemaRegistryPassword string
+
+	// Auth Service. AuthMode local decodes tokens in the service; introspect
+	// validates every token with the auth service, caching each answer for
+	// AuthIntrospectionCacheSeconds
+	AuthServiceURL                string
+	AuthMode                      string
+	AuthIntrospectionToken        string
+	AuthIntrospectionTimeoutMs    int
+	AuthIntrospectionCacheSeconds int
+
+	// User Service customer tiers; customers of a VIP tier are queued as VIP
+	// when VIP detection is enabled
+	VIPDetectionEnabled  bool
+	UserServiceURL       string
+	UserServiceToken     string
+	UserServiceTimeoutMs int
+	VIPCustomerTiers     string // comma-separated
 
 	// gRPC Menu Service
-	MenuServiceHost string
-	MenuServicePort string
+	MenuServiceHost  string
+	MenuServicePort  string
+	MenuClientMock   bool // always use the mock client
+	MenuMockFallback bool // use the mock client when the Menu Service is unreachable
+
+	// Menu Service TLS; the CA verifies the server (system roots when empty)
+	// and the certificate authenticates this service to it
+	MenuTLSEnabled    bool
+	MenuTLSCAFile     string
+	MenuTLSCertFile   string
+	MenuTLSKeyFile    string
+	MenuTLSServerName string
+
+	// Menu Service resilience
+	MenuConnectTimeoutSeconds   int
+	MenuCallTimeoutMs           int
+	MenuCallMaxRetries          int
+	MenuRetryBackoffMs          int
+	MenuKeepaliveTimeSeconds    int
+	MenuKeepaliveTimeoutSeconds int
+	MenuBreakerFailureThreshold int
+	MenuBreakerOpenSeconds      int
+	MenuDefaultPrepTimeMinutes  int
+	MenuPrepTimeCacheTTLSeconds int
+	MenuPrefetchOnStartup       bool
+
+	// gRPC Queue API (empty port disables the server) and its REST gateway
+	// (empty port disables the gateway)
+	GRPCPort              string
+	GRPCReflectionEnabled bool
+	GRPCGatewayPort       string
+
+	// Tracing
+	TracingEnabled     bool
+	OTLPEndpoint       string
+	TracingSampleRatio float64
+
+	// Data retention
+	TombstoneRetentionHours      int
+	ProcessedEventRetentionHours int
+	EntryArchiveAfterDays        int
+
+	// Statistics summary flush to MySQL
+	StatsFlushIntervalSeconds int
+
+	// Nightly recomputation of the previous days' statistics from queue
+	// entries, after the given hour (in Timezone)
+	StatsReconcileHour int
+	StatsReconcileDays int
+
+	// Queue length and kitchen load sampling for the load curve
+	LoadSampleIntervalSeconds int
+	LoadSampleRetentionDays   int
+
+	// Token counter persistence from Redis to MySQL
+	TokenCounterPersistIntervalSeconds int
+
+	// End-of-day rollover, due once a location's closing time (from its
+	// working hours) plus the grace period has passed
+	DayRolloverEnabled              bool
+	DayRolloverCheckIntervalSeconds int
+	DayRolloverGraceMinutes         int
+	DayRolloverRotateTokenPrefix    bool
+
+	// In-process queue configuration cache, dropped on every instance
+	// through Redis pub/sub when a configuration changes (0 disables it)
+	ConfigLocalCacheSeconds int
+
+	// How often skipped entries are checked for restoring
+	SkipRestoreIntervalSeconds int
+
+	// How often scheduled entries are checked for joining the queue
+	ScheduledActivationIntervalSeconds int
+
+	// How often active entries are checked against their wait SLA
+	SLACheckIntervalSeconds int
+
+	// Wait time prediction: "regression" fits a model to completed entries
+	// every interval, "linear" keeps position × average preparation time
+	WaitPredictor                 string
+	WaitModelTrainIntervalSeconds int
+	WaitModelLookbackDays         int
+	WaitModelMinSamples           int
+
+	// Data integrity check at startup, optionally repairing what it finds
+	IntegrityCheckOnStartup  bool
+	IntegrityRepairOnStartup bool
+
+	// Clock mode: real, or simulated for the standalone/test deployment
+	ClockMode           string
+	ClockSimulatedStart string
+
+	// Restaurant time zone (IANA name): business days, token numbering,
+	// working hours and the times shown to customers follow it
+	Timezone string
+
+	// Customer SMS notifications (empty provider disables SMS)
+	SMSProvider               string
+	SMSTemplateAlmostReady    string
+	SMSTemplateReady          string
+	SMSTemplateReminder       string
+	TwilioAccountSID          string
+	TwilioAuthToken           string
+	TwilioFromNumber          string
+	TwilioMessagingServiceSID string
+	TwilioStatusCallbackURL   string
+
+	// Customer email notifications (empty provider disables email)
+	EmailProvider string
+	EmailFrom     string
+	SMTPHost      string
+	SMTPPort      int
+	SMTPUsername  string
+	SMTPPassword  string
+	SESRegion     string
+
+	// Mobile push notifications through FCM (empty project disables push)
+	FCMProjectID       string
+	FCMCredentialsFile string
+	FCMMaxRetries      int
+
+	// Outbound webhooks
+	WebhookDispatchIntervalSeconds int
+	WebhookTimeoutSeconds          int
+	WebhookMaxAttempts             int
+
+	// Request timeouts, after which the request's database and Redis calls
+	// are cancelled; the long one covers exports, replays and other bulk
+	// routes (0 disables)
+	RequestTimeoutSeconds     int
+	LongRequestTimeoutSeconds int
+
+	// Idempotency-Key responses are replayed for this long
+	IdempotencyKeyTTLSeconds int
+
+	// Record staff and admin changes in the audit log
+	AuditLogEnabled bool
+
+	// Per-user caps on POST /api/queue: unfinished entries and creations in
+	// the last minute (0 disables)
+	MaxActiveEntriesPerUser    int
+	MaxEntriesPerUserPerMinute int
+
+	// Public token lookups: per client IP per minute (0 disables), and signed
+	// lookup codes (a secret enables them; required refuses bare tokens)
+	LookupRateLimitPerMinute int
+	LookupCodeSecret         string
+	LookupCodeRequired       bool
+
+	// gzip/deflate compression of JSON and CSV responses of at least this size
+	CompressionEnabled  bool
+	CompressionMinBytes int
+
+	// Language of responses and notifications for customers who don't ask
+	// for a supported one (see i18n.Languages)
+	DefaultLanguage string
 
 	// Queue Configuration
 	MaxConcurrentOrders          int
@@ -41,30 +280,216 @@ type Config struct {
 	MaxWaitTimeAlert             int
 	TokenExpiryTime              int
 	NotificationPositionThreshold int
+
+	// Values Load couldn't parse, reported by Validate
+	loadErrors []error
 }
 
+// invalidValues collects the values a Load couldn't parse
+var invalidValues []error
+
 func Load() *Config {
-	return &Config{
-		Port: getEnv("PORT", "3004"),
+	invalidValues = nil
+	dbDriver := getEnv("DB_DRIVER", "mysql")
+
+	cfg := &Config{
+		Port:        getEnv("PORT", "3004"),
+		ServiceName: getEnv("SERVICE_NAME", "queue-service"),
+
+		TrustedProxies: getEnvAsList("TRUSTED_PROXIES", ""),
+
+		AdminAllowedNetworks: getEnvAsList("ADMIN_ALLOWED_NETWORKS", ""),
 
-		DBHost:     getEnv("DB_HOST", "mysql"),
-		DBPort:     getEnv("DB_PORT", "3306"),
+		TLSCertFile:     getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:      getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile: getEnv("TLS_CLIENT_CA_FILE", ""),
+
+		DBDriver:   dbDriver,
+		DBHost:     getEnv("DB_HOST", dbDriver),
+		DBPort:     getEnv("DB_PORT", defaultDBPort(dbDriver)),
 		DBUser:     getEnv("DB_USER", "root"),
 		DBPassword: getEnv("DB_PASSWORD", "root"),
 		DBName:     getEnv("DB_NAME", "queue_db"),
+		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
+		DBReplicaDSN: getEnv("DB_REPLICA_DSN", ""),
+
+		DBMaxOpenConns:           getEnvAsInt("DB_MAX_OPEN_CONNS", 100),
+		DBMaxIdleConns:           getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
+		DBConnMaxLifetimeSeconds: getEnvAsInt("DB_CONN_MAX_LIFETIME_SECONDS", 3600),
+
+		DBMaxRetries:          getEnvAsInt("DB_MAX_RETRIES", 2),
+		DBRetryBackoffMs:      getEnvAsInt("DB_RETRY_BACKOFF_MS", 50),
+		DBBreakerMinRequests:  getEnvAsInt("DB_BREAKER_MIN_REQUESTS", 20),
+		DBBreakerFailureRatio: getEnvAsFloat("DB_BREAKER_FAILURE_RATIO", 0.5),
+		DBBreakerOpenSeconds:  getEnvAsInt("DB_BREAKER_OPEN_SECONDS", 30),
 
 		RedisHost:     getEnv("REDIS_HOST", "redis"),
 		RedisPort:     getEnv("REDIS_PORT", "6379"),
 		RedisPassword: getEnv("REDIS_PASSWORD", ""),
 		RedisDB:       getEnvAsInt("REDIS_DB", 0),
 
-		KafkaBrokers: []string{getEnv("KAFKA_BROKERS", "kafka:9092")},
+		RealtimeTransport:    getEnv("REALTIME_TRANSPORT", "pubsub"),
+		RealtimeStreamMaxLen: getEnvAsInt("REALTIME_STREAM_MAX_LEN", 1000),
+		RealtimeStreamGroup:  getEnv("REALTIME_STREAM_GROUP", "queue-service"),
+
+		KafkaBrokers: getEnvAsList("KAFKA_BROKERS", "kafka:9092"),
 		KafkaGroupID: getEnv("KAFKA_GROUP_ID", "queue-service-group"),
 
-		AuthServiceURL: getEnv("AUTH_SERVICE_URL", "http://auth-service:3001"),
+		KafkaProducerHealthIntervalSeconds: getEnvAsInt("KAFKA_PRODUCER_HEALTH_INTERVAL_SECONDS", 15),
+
+		KafkaTopicOrderCreated:       getEnv("KAFKA_TOPIC_ORDER_CREATED", "order.created"),
+		KafkaTopicOrderStatusChanged: getEnv("KAFKA_TOPIC_ORDER_STATUS_CHANGED", "order.status.changed"),
+		KafkaTopicOrderCancelled:     getEnv("KAFKA_TOPIC_ORDER_CANCELLED", "order.cancelled"),
+		KafkaTopicPaymentCompleted:   getEnv("KAFKA_TOPIC_PAYMENT_COMPLETED", "payment.completed"),
+		KafkaTopicMenuUpdated:        getEnv("KAFKA_TOPIC_MENU_UPDATED", "menu.updated"),
+		KafkaTopicTokenRevoked:       getEnv("KAFKA_TOPIC_TOKEN_REVOKED", "auth.token.revoked"),
+		KafkaTopicQueueEvents:        getEnv("KAFKA_TOPIC_QUEUE_EVENTS", "queue.events"),
+		KafkaTopicNotificationEvents: getEnv("KAFKA_TOPIC_NOTIFICATION_EVENTS", "notification.events"),
+		KafkaTopicKitchenEvents:      getEnv("KAFKA_TOPIC_KITCHEN_EVENTS", "kitchen.events"),
+		KafkaTopicDeadLetter:         getEnv("KAFKA_TOPIC_DEAD_LETTER", "queue.events.dlq"),
+
+		KafkaAutoCreateTopics:       getEnvAsBool("KAFKA_AUTO_CREATE_TOPICS", true),
+		KafkaTopicPartitions:        getEnvAsInt("KAFKA_TOPIC_PARTITIONS", 3),
+		KafkaTopicReplicationFactor: getEnvAsInt("KAFKA_TOPIC_REPLICATION_FACTOR", 1),
+		KafkaTopicRetentionHours:    getEnvAsInt("KAFKA_TOPIC_RETENTION_HOURS", 168),
 
-		MenuServiceHost: getEnv("MENU_SERVICE_HOST", "menu-service"),
-		MenuServicePort: getEnv("MENU_SERVICE_PORT", "50051"),
+		KafkaConsumerMaxRetries:     getEnvAsInt("KAFKA_CONSUMER_MAX_RETRIES", 3),
+		KafkaConsumerRetryBackoffMs: getEnvAsInt("KAFKA_CONSUMER_RETRY_BACKOFF_MS", 200),
+		KafkaConsumerMaxBackoffMs:   getEnvAsInt("KAFKA_CONSUMER_MAX_BACKOFF_MS", 5000),
+
+		KafkaEventEncoding:     getEnv("KAFKA_EVENT_ENCODING", "json"),
+		KafkaConsumerEncoding:  getEnv("KAFKA_CONSUMER_ENCODING", "json"),
+		SchemaRegistryURL:      getEnv("SCHEMA_REGISTRY_URL", "http://schema-registry:8081"),
+		SchemaRegistryUsername: getEnv("SCHEMA_REGISTRY_USERNAME", ""),
+		SchemaRegistryPassword: getEnv("SCHEMA_REGISTRY_PASSWORD", ""),
+
+		AuthServiceURL:                getEnv("AUTH_SERVICE_URL", "http://auth-service:3001"),
+		AuthMode:                      getEnv("AUTH_MODE", "local"),
+		AuthIntrospectionToken:        getEnv("AUTH_INTROSPECTION_TOKEN", ""),
+		AuthIntrospectionTimeoutMs:    getEnvAsInt("AUTH_INTROSPECTION_TIMEOUT_MS", 500),
+		AuthIntrospectionCacheSeconds: getEnvAsInt("AUTH_INTROSPECTION_CACHE_SECONDS", 30),
+
+		VIPDetectionEnabled:  getEnvAsBool("VIP_DETECTION_ENABLED", false),
+		UserServiceURL:       getEnv("USER_SERVICE_URL", getEnv("AUTH_SERVICE_URL", "http://auth-service:3001")),
+		UserServiceToken:     getEnv("USER_SERVICE_TOKEN", ""),
+		UserServiceTimeoutMs: getEnvAsInt("USER_SERVICE_TIMEOUT_MS", 300),
+		VIPCustomerTiers:     getEnv("VIP_CUSTOMER_TIERS", "VIP"),
+
+		MenuServiceHost:  getEnv("MENU_SERVICE_HOST", "menu-service"),
+		MenuServicePort:  getEnv("MENU_SERVICE_PORT", "50051"),
+		MenuClientMock:   getEnvAsBool("MENU_CLIENT_MOCK", false),
+		MenuMockFallback: getEnvAsBool("MENU_MOCK_FALLBACK", true),
+
+		MenuTLSEnabled:    getEnvAsBool("MENU_TLS_ENABLED", false),
+		MenuTLSCAFile:     getEnv("MENU_TLS_CA_FILE", ""),
+		MenuTLSCertFile:   getEnv("MENU_TLS_CERT_FILE", ""),
+		MenuTLSKeyFile:    getEnv("MENU_TLS_KEY_FILE", ""),
+		MenuTLSServerName: getEnv("MENU_TLS_SERVER_NAME", ""),
+
+		MenuConnectTimeoutSeconds:   getEnvAsInt("MENU_CONNECT_TIMEOUT_SECONDS", 5),
+		MenuCallTimeoutMs:           getEnvAsInt("MENU_CALL_TIMEOUT_MS", 500),
+		MenuCallMaxRetries:          getEnvAsInt("MENU_CALL_MAX_RETRIES", 2),
+		MenuRetryBackoffMs:          getEnvAsInt("MENU_RETRY_BACKOFF_MS", 50),
+		MenuKeepaliveTimeSeconds:    getEnvAsInt("MENU_KEEPALIVE_TIME_SECONDS", 300),
+		MenuKeepaliveTimeoutSeconds: getEnvAsInt("MENU_KEEPALIVE_TIMEOUT_SECONDS", 20),
+		MenuBreakerFailureThreshold: getEnvAsInt("MENU_BREAKER_FAILURE_THRESHOLD", 5),
+		MenuBreakerOpenSeconds:      getEnvAsInt("MENU_BREAKER_OPEN_SECONDS", 30),
+		MenuDefaultPrepTimeMinutes:  getEnvAsInt("MENU_DEFAULT_PREP_TIME_MINUTES", 10),
+		MenuPrepTimeCacheTTLSeconds: getEnvAsInt("MENU_PREP_TIME_CACHE_TTL_SECONDS", 3600),
+		MenuPrefetchOnStartup:       getEnvAsBool("MENU_PREFETCH_ON_STARTUP", true),
+
+		GRPCPort:              getEnv("GRPC_PORT", "50052"),
+		GRPCReflectionEnabled: getEnvAsBool("GRPC_REFLECTION_ENABLED", true),
+		GRPCGatewayPort:       getEnv("GRPC_GATEWAY_PORT", ""),
+
+		TracingEnabled:     getEnvAsBool("OTEL_TRACING_ENABLED", false),
+		OTLPEndpoint:       getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "otel-collector:4317"),
+		TracingSampleRatio: getEnvAsFloat("OTEL_TRACES_SAMPLE_RATIO", 1.0),
+
+		TombstoneRetentionHours:      getEnvAsInt("TOMBSTONE_RETENTION_HOURS", 72),
+		ProcessedEventRetentionHours: getEnvAsInt("PROCESSED_EVENT_RETENTION_HOURS", 168),
+		EntryArchiveAfterDays:        getEnvAsInt("ENTRY_ARCHIVE_AFTER_DAYS", 90),
+
+		StatsFlushIntervalSeconds: getEnvAsInt("STATS_FLUSH_INTERVAL_SECONDS", 30),
+
+		StatsReconcileHour: getEnvAsInt("STATS_RECONCILE_HOUR", 3),
+		StatsReconcileDays: getEnvAsInt("STATS_RECONCILE_DAYS", 2),
+
+		LoadSampleIntervalSeconds: getEnvAsInt("LOAD_SAMPLE_INTERVAL_SECONDS", 300),
+		LoadSampleRetentionDays:   getEnvAsInt("LOAD_SAMPLE_RETENTION_DAYS", 90),
+
+		TokenCounterPersistIntervalSeconds: getEnvAsInt("TOKEN_COUNTER_PERSIST_INTERVAL_SECONDS", 10),
+
+		DayRolloverEnabled:              getEnvAsBool("DAY_ROLLOVER_ENABLED", true),
+		DayRolloverCheckIntervalSeconds: getEnvAsInt("DAY_ROLLOVER_CHECK_INTERVAL_SECONDS", 60),
+		DayRolloverGraceMinutes:         getEnvAsInt("DAY_ROLLOVER_GRACE_MINUTES", 30),
+		DayRolloverRotateTokenPrefix:    getEnvAsBool("DAY_ROLLOVER_ROTATE_TOKEN_PREFIX", false),
+
+		ConfigLocalCacheSeconds: getEnvAsInt("CONFIG_LOCAL_CACHE_SECONDS", 60),
+
+		SkipRestoreIntervalSeconds: getEnvAsInt("SKIP_RESTORE_INTERVAL_SECONDS", 15),
+
+		ScheduledActivationIntervalSeconds: getEnvAsInt("SCHEDULED_ACTIVATION_INTERVAL_SECONDS", 30),
+
+		SLACheckIntervalSeconds: getEnvAsInt("SLA_CHECK_INTERVAL_SECONDS", 30),
+
+		WaitPredictor:                 getEnv("WAIT_PREDICTOR", "regression"),
+		WaitModelTrainIntervalSeconds: getEnvAsInt("WAIT_MODEL_TRAIN_INTERVAL_SECONDS", 3600),
+		WaitModelLookbackDays:         getEnvAsInt("WAIT_MODEL_LOOKBACK_DAYS", 28),
+		WaitModelMinSamples:           getEnvAsInt("WAIT_MODEL_MIN_SAMPLES", 200),
+
+		IntegrityCheckOnStartup:  getEnvAsBool("INTEGRITY_CHECK_ON_STARTUP", true),
+		IntegrityRepairOnStartup: getEnvAsBool("INTEGRITY_REPAIR_ON_STARTUP", false),
+
+		ClockMode:           getEnv("CLOCK_MODE", "real"),
+		ClockSimulatedStart: getEnv("CLOCK_SIMULATED_START", ""),
+
+		Timezone: getEnv("TIMEZONE", "UTC"),
+
+		SMSProvider:               getEnv("SMS_PROVIDER", ""),
+		SMSTemplateAlmostReady:    getEnv("SMS_TEMPLATE_ALMOST_READY", ""),
+		SMSTemplateReady:          getEnv("SMS_TEMPLATE_READY", ""),
+		SMSTemplateReminder:       getEnv("SMS_TEMPLATE_REMINDER", ""),
+		TwilioAccountSID:          getEnv("TWILIO_ACCOUNT_SID", ""),
+		TwilioAuthToken:           getEnv("TWILIO_AUTH_TOKEN", ""),
+		TwilioFromNumber:          getEnv("TWILIO_FROM_NUMBER", ""),
+		TwilioMessagingServiceSID: getEnv("TWILIO_MESSAGING_SERVICE_SID", ""),
+		TwilioStatusCallbackURL:   getEnv("TWILIO_STATUS_CALLBACK_URL", ""),
+
+		EmailProvider: getEnv("EMAIL_PROVIDER", ""),
+		EmailFrom:     getEnv("EMAIL_FROM", ""),
+		SMTPHost:      getEnv("SMTP_HOST", ""),
+		SMTPPort:      getEnvAsInt("SMTP_PORT", 587),
+		SMTPUsername:  getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:  getEnv("SMTP_PASSWORD", ""),
+		SESRegion:     getEnv("SES_REGION", ""),
+
+		FCMProjectID:       getEnv("FCM_PROJECT_ID", ""),
+		FCMCredentialsFile: getEnv("FCM_CREDENTIALS_FILE", ""),
+		FCMMaxRetries:      getEnvAsInt("FCM_MAX_RETRIES", 3),
+
+		WebhookDispatchIntervalSeconds: getEnvAsInt("WEBHOOK_DISPATCH_INTERVAL_SECONDS", 5),
+		WebhookTimeoutSeconds:          getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+		WebhookMaxAttempts:             getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+
+		RequestTimeoutSeconds:     getEnvAsInt("REQUEST_TIMEOUT_SECONDS", 15),
+		LongRequestTimeoutSeconds: getEnvAsInt("LONG_REQUEST_TIMEOUT_SECONDS", 120),
+
+		IdempotencyKeyTTLSeconds: getEnvAsInt("IDEMPOTENCY_KEY_TTL_SECONDS", 86400),
+
+		AuditLogEnabled: getEnvAsBool("AUDIT_LOG_ENABLED", true),
+
+		MaxActiveEntriesPerUser:    getEnvAsInt("MAX_ACTIVE_ENTRIES_PER_USER", 3),
+		MaxEntriesPerUserPerMinute: getEnvAsInt("MAX_ENTRIES_PER_USER_PER_MINUTE", 5),
+
+		LookupRateLimitPerMinute: getEnvAsInt("LOOKUP_RATE_LIMIT_PER_MINUTE", 60),
+		LookupCodeSecret:         getEnv("LOOKUP_CODE_SECRET", ""),
+		LookupCodeRequired:       getEnvAsBool("LOOKUP_CODE_REQUIRED", false),
+
+		CompressionEnabled:  getEnvAsBool("COMPRESSION_ENABLED", true),
+		CompressionMinBytes: getEnvAsInt("COMPRESSION_MIN_BYTES", 1024),
+
+		DefaultLanguage: getEnv("DEFAULT_LANGUAGE", "en"),
 
 		MaxConcurrentOrders:          getEnvAsInt("MAX_CONCURRENT_ORDERS", 10),
 		AvgPreparationTimePerItem:    getEnvAsInt("AVG_PREP_TIME_PER_ITEM", 5),
@@ -74,6 +499,16 @@ func Load() *Config {
 		TokenExpiryTime:              getEnvAsInt("TOKEN_EXPIRY_TIME", 60),
 		NotificationPositionThreshold: getEnvAsInt("NOTIFICATION_POSITION_THRESHOLD", 5),
 	}
+	cfg.loadErrors = invalidValues
+	return cfg
+}
+
+// defaultDBPort is the port the database driver's server listens on by default
+func defaultDBPort(driver string) string {
+	if driver == "postgres" {
+		return "5432"
+	}
+	return "3306"
 }
 
 func getEnv(key, defaultValue string) string {
@@ -87,6 +522,39 @@ func getEnvAsInt(key string, defaultValue int) int {
 	valueStr := getEnv(key, "")
 	if value, err := strconv.Atoi(valueStr); err == nil {
 		return value
+	} else if valueStr != "" {
+		invalidValues = append(invalidValues, fmt.Errorf("%s %q is not an integer", key, valueStr))
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	} else if valueStr != "" {
+		invalidValues = append(invalidValues, fmt.Errorf("%s %q is not a boolean", key, valueStr))
+	}
+	return defaultValue
+}
+
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseFloat(valueStr, 64); err == nil {
+		return value
+	} else if valueStr != "" {
+		invalidValues = append(invalidValues, fmt.Errorf("%s %q is not a number", key, valueStr))
+	}
+	return defaultValue
+}
+
+// getEnvAsList splits a comma-separated value, dropping empty items
+func getEnvAsList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
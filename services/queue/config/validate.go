@@ -0,0 +1,333 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gin-quickstart/i18n"
+)
+
+// Validate checks the loaded configuration before anything connects with it,
+// returning every problem found joined into one error
+func (c *Config) Validate() error {
+	v := &validator{errs: append([]error(nil), c.loadErrors...)}
+
+	v.required("PORT", c.Port)
+	v.port("PORT", c.Port)
+	v.required("SERVICE_NAME", c.ServiceName)
+	for _, proxy := range c.TrustedProxies {
+		v.ipOrCIDR("TRUSTED_PROXIES", proxy)
+	}
+	for _, network := range c.AdminAllowedNetworks {
+		v.ipOrCIDR("ADMIN_ALLOWED_NETWORKS", network)
+	}
+	if c.TLSCertFile != "" || c.TLSKeyFile != "" || c.TLSClientCAFile != "" {
+		v.file("TLS_CERT_FILE", c.TLSCertFile)
+		v.file("TLS_KEY_FILE", c.TLSKeyFile)
+		if c.TLSClientCAFile != "" {
+			v.file("TLS_CLIENT_CA_FILE", c.TLSClientCAFile)
+		}
+	}
+
+	// Database
+	v.oneOf("DB_DRIVER", c.DBDriver, "mysql", "postgres")
+	v.required("DB_HOST", c.DBHost)
+	v.port("DB_PORT", c.DBPort)
+	v.required("DB_USER", c.DBUser)
+	v.required("DB_NAME", c.DBName)
+	if c.DBDriver == "postgres" {
+		v.oneOf("DB_SSLMODE", c.DBSSLMode, "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	}
+	v.atLeast("DB_MAX_OPEN_CONNS", c.DBMaxOpenConns, 0)
+	v.atLeast("DB_MAX_IDLE_CONNS", c.DBMaxIdleConns, 0)
+	if c.DBMaxOpenConns > 0 && c.DBMaxIdleConns > c.DBMaxOpenConns {
+		v.fail("DB_MAX_IDLE_CONNS (%d) exceeds DB_MAX_OPEN_CONNS (%d)", c.DBMaxIdleConns, c.DBMaxOpenConns)
+	}
+	v.atLeast("DB_CONN_MAX_LIFETIME_SECONDS", c.DBConnMaxLifetimeSeconds, 0)
+	v.atLeast("DB_MAX_RETRIES", c.DBMaxRetries, 0)
+	v.atLeast("DB_RETRY_BACKOFF_MS", c.DBRetryBackoffMs, 0)
+	v.atLeast("DB_BREAKER_MIN_REQUESTS", c.DBBreakerMinRequests, 1)
+	v.ratio("DB_BREAKER_FAILURE_RATIO", c.DBBreakerFailureRatio, false)
+	v.atLeast("DB_BREAKER_OPEN_SECONDS", c.DBBreakerOpenSeconds, 1)
+
+	// Redis
+	v.required("REDIS_HOST", c.RedisHost)
+	v.port("REDIS_PORT", c.RedisPort)
+	v.atLeast("REDIS_DB", c.RedisDB, 0)
+	v.oneOf("REALTIME_TRANSPORT", strings.ToLower(c.RealtimeTransport), "", "pubsub", "streams")
+	v.atLeast("REALTIME_STREAM_MAX_LEN", c.RealtimeStreamMaxLen, 1)
+
+	// Kafka
+	if len(c.KafkaBrokers) == 0 {
+		v.fail("KAFKA_BROKERS is required")
+	}
+	for _, broker := range c.KafkaBrokers {
+		v.hostPort("KAFKA_BROKERS", broker)
+	}
+	v.required("KAFKA_GROUP_ID", c.KafkaGroupID)
+	v.atLeast("KAFKA_PRODUCER_HEALTH_INTERVAL_SECONDS", c.KafkaProducerHealthIntervalSeconds, 1)
+	for name, topic := range map[string]string{
+		"KAFKA_TOPIC_ORDER_CREATED":        c.KafkaTopicOrderCreated,
+		"KAFKA_TOPIC_ORDER_STATUS_CHANGED": c.KafkaTopicOrderStatusChanged,
+		"KAFKA_TOPIC_ORDER_CANCELLED":      c.KafkaTopicOrderCancelled,
+		"KAFKA_TOPIC_PAYMENT_COMPLETED":    c.KafkaTopicPaymentCompleted,
+		"KAFKA_TOPIC_MENU_UPDATED":         c.KafkaTopicMenuUpdated,
+		"KAFKA_TOPIC_TOKEN_REVOKED":        c.KafkaTopicTokenRevoked,
+		"KAFKA_TOPIC_QUEUE_EVENTS":         c.KafkaTopicQueueEvents,
+		"KAFKA_TOPIC_NOTIFICATION_EVENTS":  c.KafkaTopicNotificationEvents,
+		"KAFKA_TOPIC_KITCHEN_EVENTS":       c.KafkaTopicKitchenEvents,
+		"KAFKA_TOPIC_DEAD_LETTER":          c.KafkaTopicDeadLetter,
+	} {
+		v.required(name, topic)
+	}
+	v.atLeast("KAFKA_TOPIC_PARTITIONS", c.KafkaTopicPartitions, 1)
+	v.atLeast("KAFKA_TOPIC_REPLICATION_FACTOR", c.KafkaTopicReplicationFactor, 1)
+	v.atLeast("KAFKA_CONSUMER_MAX_RETRIES", c.KafkaConsumerMaxRetries, 0)
+	v.atLeast("KAFKA_CONSUMER_RETRY_BACKOFF_MS", c.KafkaConsumerRetryBackoffMs, 0)
+	v.atLeast("KAFKA_CONSUMER_MAX_BACKOFF_MS", c.KafkaConsumerMaxBackoffMs, c.KafkaConsumerRetryBackoffMs)
+	v.oneOf("KAFKA_EVENT_ENCODING", strings.ToLower(c.KafkaEventEncoding), "", "json", "avro", "protobuf")
+	v.oneOf("KAFKA_CONSUMER_ENCODING", strings.ToLower(c.KafkaConsumerEncoding), "", "json", "protobuf")
+	if strings.EqualFold(c.KafkaEventEncoding, "avro") {
+		v.httpURL("SCHEMA_REGISTRY_URL", c.SchemaRegistryURL)
+	}
+
+	// Other services
+	v.httpURL("AUTH_SERVICE_URL", c.AuthServiceURL)
+	v.oneOf("AUTH_MODE", strings.ToLower(c.AuthMode), "local", "introspect")
+	if strings.EqualFold(c.AuthMode, "introspect") {
+		v.atLeast("AUTH_INTROSPECTION_TIMEOUT_MS", c.AuthIntrospectionTimeoutMs, 1)
+		v.atLeast("AUTH_INTROSPECTION_CACHE_SECONDS", c.AuthIntrospectionCacheSeconds, 0)
+	}
+	if c.VIPDetectionEnabled {
+		v.httpURL("USER_SERVICE_URL", c.UserServiceURL)
+		v.atLeast("USER_SERVICE_TIMEOUT_MS", c.UserServiceTimeoutMs, 1)
+	}
+	if !c.MenuClientMock {
+		v.required("MENU_SERVICE_HOST", c.MenuServiceHost)
+		v.port("MENU_SERVICE_PORT", c.MenuServicePort)
+	}
+	if c.MenuTLSEnabled {
+		if c.MenuTLSCAFile != "" {
+			v.file("MENU_TLS_CA_FILE", c.MenuTLSCAFile)
+		}
+		if c.MenuTLSCertFile != "" || c.MenuTLSKeyFile != "" {
+			v.file("MENU_TLS_CERT_FILE", c.MenuTLSCertFile)
+			v.file("MENU_TLS_KEY_FILE", c.MenuTLSKeyFile)
+		}
+	}
+	v.atLeast("MENU_CONNECT_TIMEOUT_SECONDS", c.MenuConnectTimeoutSeconds, 1)
+	v.atLeast("MENU_CALL_TIMEOUT_MS", c.MenuCallTimeoutMs, 1)
+	v.atLeast("MENU_CALL_MAX_RETRIES", c.MenuCallMaxRetries, 0)
+	v.atLeast("MENU_RETRY_BACKOFF_MS", c.MenuRetryBackoffMs, 0)
+	v.atLeast("MENU_KEEPALIVE_TIME_SECONDS", c.MenuKeepaliveTimeSeconds, 1)
+	v.atLeast("MENU_KEEPALIVE_TIMEOUT_SECONDS", c.MenuKeepaliveTimeoutSeconds, 1)
+	v.atLeast("MENU_BREAKER_FAILURE_THRESHOLD", c.MenuBreakerFailureThreshold, 1)
+	v.atLeast("MENU_BREAKER_OPEN_SECONDS", c.MenuBreakerOpenSeconds, 1)
+	v.atLeast("MENU_DEFAULT_PREP_TIME_MINUTES", c.MenuDefaultPrepTimeMinutes, 0)
+	v.atLeast("MENU_PREP_TIME_CACHE_TTL_SECONDS", c.MenuPrepTimeCacheTTLSeconds, 0)
+	if c.GRPCPort != "" {
+		v.port("GRPC_PORT", c.GRPCPort)
+	}
+	if c.GRPCGatewayPort != "" {
+		v.port("GRPC_GATEWAY_PORT", c.GRPCGatewayPort)
+		if c.GRPCPort == "" {
+			v.fail("GRPC_GATEWAY_PORT needs the gRPC server on GRPC_PORT")
+		}
+	}
+
+	if c.TracingEnabled {
+		v.hostPort("OTEL_EXPORTER_OTLP_ENDPOINT", c.OTLPEndpoint)
+	}
+	v.ratio("OTEL_TRACES_SAMPLE_RATIO", c.TracingSampleRatio, true)
+
+	// Background workers; their intervals drive tickers, which must be positive
+	v.atLeast("TOMBSTONE_RETENTION_HOURS", c.TombstoneRetentionHours, 1)
+	v.atLeast("PROCESSED_EVENT_RETENTION_HOURS", c.ProcessedEventRetentionHours, 1)
+	v.atLeast("ENTRY_ARCHIVE_AFTER_DAYS", c.EntryArchiveAfterDays, 0)
+	v.atLeast("STATS_FLUSH_INTERVAL_SECONDS", c.StatsFlushIntervalSeconds, 1)
+	v.between("STATS_RECONCILE_HOUR", c.StatsReconcileHour, 0, 23)
+	v.atLeast("STATS_RECONCILE_DAYS", c.StatsReconcileDays, 1)
+	v.atLeast("LOAD_SAMPLE_INTERVAL_SECONDS", c.LoadSampleIntervalSeconds, 1)
+	v.atLeast("LOAD_SAMPLE_RETENTION_DAYS", c.LoadSampleRetentionDays, 1)
+	v.atLeast("TOKEN_COUNTER_PERSIST_INTERVAL_SECONDS", c.TokenCounterPersistIntervalSeconds, 1)
+	if c.DayRolloverEnabled {
+		v.atLeast("DAY_ROLLOVER_CHECK_INTERVAL_SECONDS", c.DayRolloverCheckIntervalSeconds, 1)
+		v.atLeast("DAY_ROLLOVER_GRACE_MINUTES", c.DayRolloverGraceMinutes, 0)
+	}
+	v.atLeast("CONFIG_LOCAL_CACHE_SECONDS", c.ConfigLocalCacheSeconds, 0)
+	v.atLeast("SKIP_RESTORE_INTERVAL_SECONDS", c.SkipRestoreIntervalSeconds, 1)
+	v.atLeast("SCHEDULED_ACTIVATION_INTERVAL_SECONDS", c.ScheduledActivationIntervalSeconds, 1)
+	v.atLeast("SLA_CHECK_INTERVAL_SECONDS", c.SLACheckIntervalSeconds, 1)
+	v.oneOf("WAIT_PREDICTOR", c.WaitPredictor, "regression", "linear")
+	v.atLeast("WAIT_MODEL_TRAIN_INTERVAL_SECONDS", c.WaitModelTrainIntervalSeconds, 1)
+	v.atLeast("WAIT_MODEL_LOOKBACK_DAYS", c.WaitModelLookbackDays, 1)
+	v.atLeast("WAIT_MODEL_MIN_SAMPLES", c.WaitModelMinSamples, 1)
+
+	v.oneOf("CLOCK_MODE", c.ClockMode, "", "real", "simulated")
+	if c.ClockSimulatedStart != "" {
+		if _, err := time.Parse(time.RFC3339, c.ClockSimulatedStart); err != nil {
+			v.fail("CLOCK_SIMULATED_START %q is not an RFC 3339 time", c.ClockSimulatedStart)
+		}
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil || c.Timezone == "" || strings.EqualFold(c.Timezone, "Local") {
+		v.fail("TIMEZONE %q is not an IANA time zone such as Europe/Madrid", c.Timezone)
+	}
+
+	// Notifications
+	switch strings.ToLower(c.SMSProvider) {
+	case "", "log":
+	case "twilio":
+		if c.TwilioAccountSID == "" || c.TwilioAuthToken == "" {
+			v.fail("TWILIO_ACCOUNT_SID and TWILIO_AUTH_TOKEN are required for the twilio SMS provider")
+		}
+		if c.TwilioFromNumber == "" && c.TwilioMessagingServiceSID == "" {
+			v.fail("TWILIO_FROM_NUMBER or TWILIO_MESSAGING_SERVICE_SID is required for the twilio SMS provider")
+		}
+		if c.TwilioStatusCallbackURL != "" {
+			v.httpURL("TWILIO_STATUS_CALLBACK_URL", c.TwilioStatusCallbackURL)
+		}
+	default:
+		v.fail("SMS_PROVIDER %q is not one of twilio, log", c.SMSProvider)
+	}
+	switch strings.ToLower(c.EmailProvider) {
+	case "", "log":
+	case "smtp":
+		v.required("SMTP_HOST", c.SMTPHost)
+		v.required("EMAIL_FROM", c.EmailFrom)
+		v.between("SMTP_PORT", c.SMTPPort, 1, 65535)
+	case "ses":
+		v.required("SES_REGION", c.SESRegion)
+		v.required("EMAIL_FROM", c.EmailFrom)
+	default:
+		v.fail("EMAIL_PROVIDER %q is not one of smtp, ses, log", c.EmailProvider)
+	}
+	v.atLeast("FCM_MAX_RETRIES", c.FCMMaxRetries, 0)
+
+	v.atLeast("WEBHOOK_DISPATCH_INTERVAL_SECONDS", c.WebhookDispatchIntervalSeconds, 1)
+	v.atLeast("WEBHOOK_TIMEOUT_SECONDS", c.WebhookTimeoutSeconds, 1)
+	v.atLeast("WEBHOOK_MAX_ATTEMPTS", c.WebhookMaxAttempts, 1)
+	v.atLeast("REQUEST_TIMEOUT_SECONDS", c.RequestTimeoutSeconds, 0)
+	v.atLeast("LONG_REQUEST_TIMEOUT_SECONDS", c.LongRequestTimeoutSeconds, 0)
+	v.atLeast("IDEMPOTENCY_KEY_TTL_SECONDS", c.IdempotencyKeyTTLSeconds, 1)
+	v.atLeast("MAX_ACTIVE_ENTRIES_PER_USER", c.MaxActiveEntriesPerUser, 0)
+	v.atLeast("MAX_ENTRIES_PER_USER_PER_MINUTE", c.MaxEntriesPerUserPerMinute, 0)
+	v.atLeast("LOOKUP_RATE_LIMIT_PER_MINUTE", c.LookupRateLimitPerMinute, 0)
+	if c.LookupCodeRequired {
+		v.required("LOOKUP_CODE_SECRET", c.LookupCodeSecret)
+	}
+	v.atLeast("COMPRESSION_MIN_BYTES", c.CompressionMinBytes, 0)
+	v.oneOf("DEFAULT_LANGUAGE", c.DefaultLanguage, i18n.Languages()...)
+
+	// Queue defaults
+	v.atLeast("MAX_CONCURRENT_ORDERS", c.MaxConcurrentOrders, 1)
+	v.atLeast("AVG_PREP_TIME_PER_ITEM", c.AvgPreparationTimePerItem, 0)
+	v.atLeast("BUFFER_TIME", c.BufferTime, 0)
+	v.atLeast("EXPRESS_QUEUE_MAX_ITEMS", c.ExpressQueueMaxItems, 0)
+	v.atLeast("MAX_WAIT_TIME_ALERT", c.MaxWaitTimeAlert, 1)
+	v.atLeast("TOKEN_EXPIRY_TIME", c.TokenExpiryTime, 1)
+	v.atLeast("NOTIFICATION_POSITION_THRESHOLD", c.NotificationPositionThreshold, 0)
+
+	return errors.Join(v.errs...)
+}
+
+// validator collects the problems Validate finds
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(format string, args ...interface{}) {
+	v.errs = append(v.errs, fmt.Errorf(format, args...))
+}
+
+func (v *validator) required(name, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.fail("%s is required", name)
+	}
+}
+
+func (v *validator) atLeast(name string, value, min int) {
+	if value < min {
+		v.fail("%s must be at least %d, got %d", name, min, value)
+	}
+}
+
+func (v *validator) between(name string, value, min, max int) {
+	if value < min || value > max {
+		v.fail("%s must be between %d and %d, got %d", name, min, max, value)
+	}
+}
+
+// ratio checks value lies in (0, 1], or [0, 1] when zero is allowed
+func (v *validator) ratio(name string, value float64, zeroAllowed bool) {
+	if value > 1 || value < 0 || (value == 0 && !zeroAllowed) {
+		v.fail("%s must be a ratio between 0 and 1, got %g", name, value)
+	}
+}
+
+func (v *validator) oneOf(name, value string, allowed ...string) {
+	for _, option := range allowed {
+		if value == option {
+			return
+		}
+	}
+
+	var named []string
+	for _, option := range allowed {
+		if option != "" {
+			named = append(named, option)
+		}
+	}
+	v.fail("%s %q is not one of %s", name, value, strings.Join(named, ", "))
+}
+
+func (v *validator) port(name, value string) {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		v.fail("%s %q is not a port number", name, value)
+	}
+}
+
+// hostPort checks an address has the host:port form brokers and collectors take
+func (v *validator) hostPort(name, value string) {
+	host, port, err := net.SplitHostPort(strings.TrimSpace(value))
+	if err != nil || host == "" {
+		v.fail("%s %q is not a host:port address", name, value)
+		return
+	}
+	v.port(name, port)
+}
+
+// ipOrCIDR checks an address is an IP or a CIDR network
+func (v *validator) ipOrCIDR(name, value string) {
+	if net.ParseIP(value) != nil {
+		return
+	}
+	if _, _, err := net.ParseCIDR(value); err != nil {
+		v.fail("%s %q is not an IP address or CIDR network", name, value)
+	}
+}
+
+// file checks a path names a readable regular file, such as a certificate
+func (v *validator) file(name, path string) {
+	if path == "" {
+		v.fail("%s is required", name)
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		v.fail("%s %q is not a readable file", name, path)
+	}
+}
+
+func (v *validator) httpURL(name, value string) {
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		v.fail("%s %q is not an http(s) URL", name, value)
+	}
+}
@@ -1,50 +1,189 @@
 package database
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"gin-quickstart/config"
+	"gin-quickstart/metrics"
 
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+	gormtracing "gorm.io/plugin/opentelemetry/tracing"
 )
 
 var DB *gorm.DB
 
-// InitDB initializes the database connection
-func InitDB(cfg *config.Config) error {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+// resilience retries DB's statements and holds its breaker
+var resilience *resilientPool
+
+// replicaResolver names the dbresolver resolver whose reads go to the replica
+const replicaResolver = "replica"
+
+var useReplicaResolver = dbresolver.Use(replicaResolver).(gorm.StatementModifier)
+
+type replicaReadsKey struct{}
+
+// WithReplicaReads marks ctx's queries to read from the read replica, when
+// one is configured. Writes stay on the primary.
+func WithReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, true)
+}
+
+// primaryDSN builds the primary database's DSN in the driver's format
+func primaryDSN(cfg *config.Config) string {
+	if cfg.DBDriver == "postgres" {
+		return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
+			cfg.DBHost,
+			cfg.DBPort,
+			cfg.DBUser,
+			cfg.DBPassword,
+			cfg.DBName,
+			cfg.DBSSLMode,
+		)
+	}
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 		cfg.DBUser,
 		cfg.DBPassword,
 		cfg.DBHost,
 		cfg.DBPort,
 		cfg.DBName,
 	)
+}
+
+// dialector opens dsn with the configured database driver
+func dialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "mysql":
+		return mysql.Open(dsn), nil
+	case "postgres":
+		return postgres.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// poolSettings sizes a database connection pool
+type poolSettings struct {
+	maxOpenConns    int
+	maxIdleConns    int
+	connMaxLifetime time.Duration
+}
 
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
+// apply sets the settings on a pool
+func (p poolSettings) apply(sqlDB *sql.DB) {
+	sqlDB.SetMaxOpenConns(p.maxOpenConns)
+	sqlDB.SetMaxIdleConns(p.maxIdleConns)
+	sqlDB.SetConnMaxLifetime(p.connMaxLifetime)
+}
+
+// useReadReplica routes the reads of queries marked by WithReplicaReads to the
+// replica at dsn
+func useReadReplica(db *gorm.DB, driver, dsn string, pool poolSettings) error {
+	replica, err := dialector(driver, dsn)
+	if err != nil {
+		return err
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{Replicas: []gorm.Dialector{replica}}, replicaResolver)
+	if err := db.Use(resolver); err != nil {
+		return err
+	}
+
+	// The primary's pool sits behind the resilient pool; the bare one is the replica's
+	err = resolver.Call(func(connPool gorm.ConnPool) error {
+		if sqlDB, ok := connPool.(*sql.DB); ok {
+			pool.apply(sqlDB)
+			return metrics.RegisterDBStats(sqlDB, "replica")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	useReplica := func(db *gorm.DB) {
+		if marked, _ := db.Statement.Context.Value(replicaReadsKey{}).(bool); marked {
+			useReplicaResolver.ModifyStatement(db.Statement)
+		}
+	}
+	if err := db.Callback().Query().After("gorm:db_resolver").Before("gorm:query").Register("queue:replica_reads", useReplica); err != nil {
+		return err
+	}
+	return db.Callback().Row().After("gorm:db_resolver").Before("gorm:row").Register("queue:replica_reads", useReplica)
+}
+
+// InitDB initializes the database connection
+func InitDB(cfg *config.Config) error {
+	dialect, err := dialector(cfg.DBDriver, primaryDSN(cfg))
+	if err != nil {
+		return err
+	}
+
+	DB, err = gorm.Open(dialect, &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
 		},
+		// Surface unique-key violations as gorm.ErrDuplicatedKey
+		TranslateError: true,
 	})
 
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	// Retry transient errors and stop hammering a struggling database
+	resilience, err = useResilientPool(DB, ResilienceSettings{
+		MaxRetries:          cfg.DBMaxRetries,
+		RetryBackoff:        time.Duration(cfg.DBRetryBackoffMs) * time.Millisecond,
+		BreakerMinRequests:  cfg.DBBreakerMinRequests,
+		BreakerFailureRatio: cfg.DBBreakerFailureRatio,
+		BreakerOpenFor:      time.Duration(cfg.DBBreakerOpenSeconds) * time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get database instance: %w", err)
+	}
+
+	// Count failed queries for Prometheus
+	if err := metrics.RegisterGormCallbacks(DB); err != nil {
+		return fmt.Errorf("failed to register metrics callbacks: %w", err)
+	}
+
+	// Trace queries
+	if err := DB.Use(gormtracing.NewPlugin(gormtracing.WithoutMetrics())); err != nil {
+		return fmt.Errorf("failed to register tracing plugin: %w", err)
+	}
+
+	pool := poolSettings{
+		maxOpenConns:    cfg.DBMaxOpenConns,
+		maxIdleConns:    cfg.DBMaxIdleConns,
+		connMaxLifetime: time.Duration(cfg.DBConnMaxLifetimeSeconds) * time.Second,
+	}
+	if cfg.DBReplicaDSN != "" {
+		if err := useReadReplica(DB, cfg.DBDriver, cfg.DBReplicaDSN, pool); err != nil {
+			return fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+		log.Println("Read replica configured")
+	}
+
 	sqlDB, err := DB.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get database instance: %w", err)
 	}
 
-	// Set connection pool settings
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	// Set connection pool settings, and publish the pool's usage
+	pool.apply(sqlDB)
+	if err := metrics.RegisterDBStats(sqlDB, "primary"); err != nil {
+		return fmt.Errorf("failed to register pool metrics: %w", err)
+	}
 
 	log.Println("Database connected successfully")
 	return nil
@@ -63,3 +202,18 @@ func Close() error {
 	}
 	return sqlDB.Close()
 }
+
+// Ping verifies the database connection, and that the breaker isn't open
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return errors.New("database not initialized")
+	}
+	if resilience != nil && resilience.open() {
+		return fmt.Errorf("%w: circuit breaker open", ErrUnavailable)
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
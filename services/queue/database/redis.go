@@ -2,11 +2,13 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"gin-quickstart/config"
+	"gin-quickstart/metrics"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -25,6 +27,9 @@ func InitRedis(cfg *config.Config) error {
 		PoolSize:     10,
 	})
 
+	// Count failed commands for Prometheus
+	RedisClient.AddHook(metrics.RedisHook{})
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -48,3 +53,11 @@ func CloseRedis() error {
 	}
 	return nil
 }
+
+// PingRedis verifies the Redis connection
+func PingRedis(ctx context.Context) error {
+	if RedisClient == nil {
+		return errors.New("redis not initialized")
+	}
+	return RedisClient.Ping(ctx).Err()
+}
@@ -0,0 +1,219 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"syscall"
+	"time"
+
+	"gin-quickstart/metrics"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sony/gobreaker/v2"
+	"gorm.io/gorm"
+)
+
+// ErrUnavailable is returned when the database breaker is open, or a transient
+// error outlasted the retries. The last database error stays wrapped.
+var ErrUnavailable = errors.New("database temporarily unavailable")
+
+// MySQL error numbers worth retrying
+const (
+	mysqlErrLockWaitTimeout = 1205
+	mysqlErrDeadlock        = 1213
+)
+
+// PostgreSQL error codes worth retrying
+const (
+	pgErrSerializationFailure = "40001"
+	pgErrDeadlock             = "40P01"
+	pgErrLockNotAvailable     = "55P03"
+)
+
+// dbBreakerInterval is the window the breaker's failure ratio is measured over
+const dbBreakerInterval = time.Minute
+
+// ResilienceSettings configures retries and the circuit breaker around the
+// database connection pool
+type ResilienceSettings struct {
+	MaxRetries          int
+	RetryBackoff        time.Duration
+	BreakerMinRequests  int
+	BreakerFailureRatio float64
+	BreakerOpenFor      time.Duration
+}
+
+// resilientPool retries statements that failed on a lock conflict or a lost
+// connection, with exponential backoff, and stops sending statements for a
+// while once transient errors make up too much of the traffic. Statements
+// inside a transaction run on the *sql.Tx and aren't retried: a deadlock
+// rolls the whole transaction back.
+type resilientPool struct {
+	db       *sql.DB
+	settings ResilienceSettings
+	breaker  *gobreaker.CircuitBreaker[any]
+}
+
+func newResilientPool(db *sql.DB, settings ResilienceSettings) *resilientPool {
+	breaker := gobreaker.NewCircuitBreaker[any](gobreaker.Settings{
+		Name:     "database",
+		Interval: dbBreakerInterval,
+		Timeout:  settings.BreakerOpenFor,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.Requests >= uint32(settings.BreakerMinRequests) &&
+				float64(counts.TotalFailures)/float64(counts.Requests) >= settings.BreakerFailureRatio
+		},
+		// Constraint violations and missing rows are the caller's business
+		IsSuccessful: func(err error) bool {
+			return err == nil || !IsTransientError(err)
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			log.Printf("Circuit breaker %s: %s -> %s", name, from, to)
+			metrics.DBBreakerState.Set(float64(to))
+		},
+	})
+	return &resilientPool{db: db, settings: settings, breaker: breaker}
+}
+
+// do runs fn under the breaker, retrying errors retryable accepts
+func (p *resilientPool) do(ctx context.Context, retryable func(error) bool, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		_, err := p.breaker.Execute(func() (any, error) {
+			return nil, fn()
+		})
+		if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+			return ErrUnavailable
+		}
+		if err == nil || !retryable(err) {
+			return err
+		}
+		if attempt > p.settings.MaxRetries {
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+
+		delay := p.backoff(attempt)
+		log.Printf("Transient database error (attempt=%d), retrying in %s: %v", attempt, delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// backoff returns the delay before the given retry (1-based), doubling each time
+func (p *resilientPool) backoff(retry int) time.Duration {
+	return p.settings.RetryBackoff << (retry - 1)
+}
+
+func (p *resilientPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.db.PrepareContext(ctx, query)
+}
+
+// ExecContext retries only errors that guarantee the statement didn't apply;
+// a connection lost mid-statement may have committed it
+func (p *resilientPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := p.do(ctx, isUnappliedError, func() (err error) {
+		result, err = p.db.ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+func (p *resilientPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := p.do(ctx, IsTransientError, func() (err error) {
+		rows, err = p.db.QueryContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// QueryRowContext retries like QueryContext, but can't refuse to run while
+// the breaker is open: a *sql.Row only carries errors from the driver
+func (p *resilientPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	row := p.db.QueryRowContext(ctx, query, args...)
+	for retry := 1; retry <= p.settings.MaxRetries && row.Err() != nil && IsTransientError(row.Err()); retry++ {
+		select {
+		case <-time.After(p.backoff(retry)):
+		case <-ctx.Done():
+			return row
+		}
+		row = p.db.QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
+
+func (p *resilientPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	var tx *sql.Tx
+	err := p.do(ctx, IsTransientError, func() (err error) {
+		tx, err = p.db.BeginTx(ctx, opts)
+		return err
+	})
+	return tx, err
+}
+
+// GetDBConn lets gorm's DB() reach the pool for its settings and Close
+func (p *resilientPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+// open reports whether the breaker is refusing statements
+func (p *resilientPool) open() bool {
+	return p.breaker.State() == gobreaker.StateOpen
+}
+
+// useResilientPool puts retries and the breaker in front of db's connection pool
+func useResilientPool(db *gorm.DB, settings ResilienceSettings) (*resilientPool, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	pool := newResilientPool(sqlDB, settings)
+	db.ConnPool = pool
+	db.Statement.ConnPool = pool
+	return pool, nil
+}
+
+// IsTransientError reports whether a database error is likely to go away on
+// retry: a lock conflict or a lost connection. A cancelled or timed out
+// request context isn't; the caller gave up, the database didn't fail.
+func IsTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if isUnappliedError(err) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// isUnappliedError reports whether a statement failed without taking effect,
+// so it is safe to run again
+func isUnappliedError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrLockWaitTimeout || mysqlErr.Number == mysqlErrDeadlock
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == pgErrSerializationFailure || pgErr.Code == pgErrDeadlock || pgErr.Code == pgErrLockNotAvailable
+	}
+	return false
+}
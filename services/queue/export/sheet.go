@@ -0,0 +1,82 @@
+// Package export writes tabular exports as CSV or XLSX.
+//
+// Rows are written as they come, so an export never has to be held in
+// memory whole. An export may hold several sheets: XLSX files get a
+// worksheet for each, CSV files get the tables one after another,
+// separated by an empty line.
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ErrUnknownFormat is returned for an export format other than csv or xlsx
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// SheetWriter writes rows of one or more sheets. AddSheet must come before
+// the first row, and Close must be called to complete the file.
+type SheetWriter interface {
+	AddSheet(name string) error
+	WriteRow(cells []string) error
+	Close() error
+}
+
+// NewSheetWriter creates a writer of the format to w
+func NewSheetWriter(format string, w io.Writer) (SheetWriter, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// CSVWriter writes sheets as consecutive CSV tables
+type CSVWriter struct {
+	w      *csv.Writer
+	sheets int
+}
+
+// NewCSVWriter creates a CSV sheet writer to w
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// AddSheet separates the next table from the previous one
+func (c *CSVWriter) AddSheet(name string) error {
+	c.sheets++
+	if c.sheets == 1 {
+		return nil
+	}
+	return c.w.Write(nil)
+}
+
+// WriteRow writes a record
+func (c *CSVWriter) WriteRow(cells []string) error {
+	return c.w.Write(cells)
+}
+
+// Close flushes the buffered records
+func (c *CSVWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,170 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`%s</Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxSheetHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+const xlsxSheetFooter = `</sheetData></worksheet>`
+
+// XLSXWriter writes sheets as the worksheets of an XLSX workbook. Each
+// worksheet is streamed into the archive; the workbook parts listing them
+// are written on Close.
+type XLSXWriter struct {
+	zip    *zip.Writer
+	sheets []string
+	sheet  *bufio.Writer
+	rows   int
+}
+
+// NewXLSXWriter creates an XLSX sheet writer to w
+func NewXLSXWriter(w io.Writer) *XLSXWriter {
+	return &XLSXWriter{zip: zip.NewWriter(w)}
+}
+
+// AddSheet finishes the current worksheet and starts a new one
+func (x *XLSXWriter) AddSheet(name string) error {
+	if err := x.finishSheet(); err != nil {
+		return err
+	}
+
+	x.sheets = append(x.sheets, name)
+	part, err := x.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(x.sheets)))
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(part)
+	x.rows = 0
+	_, err = x.sheet.WriteString(xlsxSheetHeader)
+	return err
+}
+
+// WriteRow writes a row of the current worksheet. Cells that parse as
+// numbers are written as numbers, the rest as text.
+func (x *XLSXWriter) WriteRow(cells []string) error {
+	if x.sheet == nil {
+		return errors.New("no sheet added")
+	}
+
+	x.rows++
+	fmt.Fprintf(x.sheet, `<row r="%d">`, x.rows)
+	for i, cell := range cells {
+		if cell == "" {
+			continue
+		}
+		ref := columnName(i) + strconv.Itoa(x.rows)
+		if isNumber(cell) {
+			fmt.Fprintf(x.sheet, `<c r="%s"><v>%s</v></c>`, ref, cell)
+			continue
+		}
+		fmt.Fprintf(x.sheet, `<c r="%s" t="inlineStr"><is><t>`, ref)
+		if err := xml.EscapeText(x.sheet, []byte(cell)); err != nil {
+			return err
+		}
+		x.sheet.WriteString(`</t></is></c>`)
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	return err
+}
+
+// Close finishes the last worksheet and writes the workbook
+func (x *XLSXWriter) Close() error {
+	if err := x.finishSheet(); err != nil {
+		return err
+	}
+	if len(x.sheets) == 0 {
+		if err := x.AddSheet("Sheet1"); err != nil {
+			return err
+		}
+		if err := x.finishSheet(); err != nil {
+			return err
+		}
+	}
+
+	var overrides, sheets, rels strings.Builder
+	for i, name := range x.sheets {
+		n := i + 1
+		fmt.Fprintf(&overrides, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&sheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeAttr(name), n, n)
+		fmt.Fprintf(&rels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, overrides.String())},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` +
+			sheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + rels.String() + `</Relationships>`},
+	}
+	for _, part := range parts {
+		w, err := x.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, part.content); err != nil {
+			return err
+		}
+	}
+	return x.zip.Close()
+}
+
+func (x *XLSXWriter) finishSheet() error {
+	if x.sheet == nil {
+		return nil
+	}
+	if _, err := x.sheet.WriteString(xlsxSheetFooter); err != nil {
+		return err
+	}
+	err := x.sheet.Flush()
+	x.sheet = nil
+	return err
+}
+
+// columnName returns the letters of a zero-based column index: A, B, ... AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// isNumber reports whether a cell is a plain decimal number; NaN, Inf and
+// hex floats stay text
+func isNumber(cell string) bool {
+	if strings.Trim(cell, "0123456789.-+eE") != "" {
+		return false
+	}
+	_, err := strconv.ParseFloat(cell, 64)
+	return err == nil
+}
+
+func escapeAttr(value string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(value))
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"gin-quickstart/config"
+	queuepb "gin-quickstart/proto/queue"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Gateway is the running REST gateway in front of the gRPC Queue API
+type Gateway struct {
+	server *http.Server
+	conn   *grpc.ClientConn
+}
+
+// ServeGateway starts the REST mapping of the Queue API (the google.api.http
+// rules in queue.proto) on GRPC_GATEWAY_PORT. It calls the gRPC server on
+// GRPC_PORT, so both surfaces run the same RPC code. Like the gRPC API it is
+// meant for the internal network and carries no end-user authentication.
+func ServeGateway(cfg *config.Config) (*Gateway, error) {
+	conn, err := grpc.NewClient("localhost:"+cfg.GRPCPort,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect the gateway to the gRPC server: %w", err)
+	}
+
+	// Field names as in the proto (snake_case), like the Gin API's JSON
+	mux := runtime.NewServeMux(runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+		MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+		UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+	}))
+	if err := queuepb.RegisterQueueServiceHandlerClient(context.Background(), mux, queuepb.NewQueueServiceClient(conn)); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to register the gateway handlers: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", ":"+cfg.GRPCGatewayPort)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to listen on gateway port %s: %w", cfg.GRPCGatewayPort, err)
+	}
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("gRPC gateway stopped: %v", err)
+		}
+	}()
+
+	log.Printf("Queue REST gateway listening on port %s", cfg.GRPCGatewayPort)
+	return &Gateway{server: server, conn: conn}, nil
+}
+
+// Shutdown drains in-flight requests, then closes the connection to the gRPC server
+func (g *Gateway) Shutdown(ctx context.Context) {
+	if err := g.server.Shutdown(ctx); err != nil {
+		log.Printf("gRPC gateway shutdown: %v", err)
+	}
+	g.conn.Close()
+}
@@ -0,0 +1,53 @@
+package grpc
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// retryableCodes are failures where the call most likely never reached the
+// handler, or the handler is idempotent (all menu RPCs are reads)
+var retryableCodes = map[codes.Code]bool{
+	codes.Unavailable:       true,
+	codes.ResourceExhausted: true,
+	codes.DeadlineExceeded:  true,
+}
+
+// timeoutInterceptor gives each attempt its own deadline unless the caller
+// already set a tighter one
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > timeout {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// retryInterceptor retries retryable failures up to maxRetries times with
+// exponential backoff and full jitter, stopping when the caller's context ends
+func retryInterceptor(maxRetries int, backoff time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var err error
+		for attempt := 0; ; attempt++ {
+			err = invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= maxRetries || !retryableCodes[status.Code(err)] {
+				return err
+			}
+
+			delay := time.Duration(rand.Int63n(int64(backoff<<attempt) + 1))
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gin-quickstart/metrics"
+
+	"github.com/sony/gobreaker/v2"
+)
+
+// breakerMenuClient stops calling the Menu Service for a while after
+// repeated failures, so a slow or down menu service fails fast instead of
+// stalling queue entry creation. Per-call deadlines and retries come from
+// the connection's interceptors.
+type breakerMenuClient struct {
+	next    MenuServiceClient
+	breaker *gobreaker.CircuitBreaker[any]
+}
+
+func newBreakerMenuClient(next MenuServiceClient, failureThreshold int, openFor time.Duration) *breakerMenuClient {
+	breaker := gobreaker.NewCircuitBreaker[any](gobreaker.Settings{
+		Name:    "menu-service",
+		Timeout: openFor,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= uint32(failureThreshold)
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			log.Printf("Circuit breaker %s: %s -> %s", name, from, to)
+			metrics.MenuBreakerState.Set(float64(to))
+		},
+	})
+	return &breakerMenuClient{next: next, breaker: breaker}
+}
+
+// call runs fn under the breaker
+func (b *breakerMenuClient) call(ctx context.Context, fn func(ctx context.Context) (any, error)) (any, error) {
+	return b.breaker.Execute(func() (any, error) {
+		return fn(ctx)
+	})
+}
+
+func (b *breakerMenuClient) GetMenuItem(ctx context.Context, itemID string) (*MenuItem, error) {
+	result, err := b.call(ctx, func(ctx context.Context) (any, error) {
+		return b.next.GetMenuItem(ctx, itemID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*MenuItem), nil
+}
+
+func (b *breakerMenuClient) GetMenuItems(ctx context.Context, itemIDs []string) ([]*MenuItem, error) {
+	result, err := b.call(ctx, func(ctx context.Context) (any, error) {
+		return b.next.GetMenuItems(ctx, itemIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*MenuItem), nil
+}
+
+func (b *breakerMenuClient) ListMenuItems(ctx context.Context) ([]*MenuItem, error) {
+	result, err := b.call(ctx, func(ctx context.Context) (any, error) {
+		return b.next.ListMenuItems(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]*MenuItem), nil
+}
+
+func (b *breakerMenuClient) GetAveragePreparationTime(ctx context.Context, itemIDs []string) (int, error) {
+	result, err := b.call(ctx, func(ctx context.Context) (any, error) {
+		return b.next.GetAveragePreparationTime(ctx, itemIDs)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.(int), nil
+}
@@ -2,20 +2,36 @@ package grpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"gin-quickstart/config"
+	"gin-quickstart/database"
+	"gin-quickstart/mtls"
+	menupb "gin-quickstart/proto/menu"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	_ "google.golang.org/grpc/health" // enables client-side health checking
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// menuServiceName is the service checked with the gRPC health protocol
+var menuServiceName = menupb.MenuService_ServiceDesc.ServiceName
+
 // MenuClient wraps the gRPC connection to Menu Service
 type MenuClient struct {
-	conn   *grpc.ClientConn
-	client MenuServiceClient
+	conn      *grpc.ClientConn
+	client    MenuServiceClient
+	health    healthpb.HealthClient
+	prepTimes *PrepTimeCache
+	// defaultPrepTime (minutes) stands in for items the Menu Service can't answer for
+	defaultPrepTime int
 }
 
 // MenuItem represents a menu item from Menu Service
@@ -32,43 +48,85 @@ type MenuItem struct {
 type MenuServiceClient interface {
 	GetMenuItem(ctx context.Context, itemID string) (*MenuItem, error)
 	GetMenuItems(ctx context.Context, itemIDs []string) ([]*MenuItem, error)
+	ListMenuItems(ctx context.Context) ([]*MenuItem, error)
 	GetAveragePreparationTime(ctx context.Context, itemIDs []string) (int, error)
 }
 
-type menuServiceClient struct {
-	// This will be replaced with actual gRPC client when proto is available
-}
-
+// NewMenuClient connects to the Menu Service. The connection is established
+// in the background; when the first health probe fails the client falls back
+// to the mock if MENU_MOCK_FALLBACK is set, and otherwise keeps reconnecting.
+// MENU_CLIENT_MOCK forces the mock client.
 func NewMenuClient(cfg *config.Config) (*MenuClient, error) {
+	if cfg.MenuClientMock {
+		log.Println("Using mock Menu Service client")
+		return newMockMenuClient(cfg), nil
+	}
+
 	address := fmt.Sprintf("%s:%s", cfg.MenuServiceHost, cfg.MenuServicePort)
-	
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
 
-	// Create gRPC connection
-	conn, err := grpc.DialContext(ctx, address,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
+	creds := insecure.NewCredentials()
+	if cfg.MenuTLSEnabled {
+		tlsConfig, err := mtls.ClientConfig(cfg.MenuTLSCAFile, cfg.MenuTLSCertFile, cfg.MenuTLSKeyFile, cfg.MenuTLSServerName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure menu service TLS: %w", err)
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	// Client-side health checking keeps traffic off backends that report NOT_SERVING.
+	// The keepalive time must not undercut the server's permitted ping interval.
+	conn, err := grpc.NewClient(address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithDefaultServiceConfig(fmt.Sprintf(`{"healthCheckConfig":{"serviceName":%q}}`, menuServiceName)),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:    time.Duration(cfg.MenuKeepaliveTimeSeconds) * time.Second,
+			Timeout: time.Duration(cfg.MenuKeepaliveTimeoutSeconds) * time.Second,
+		}),
+		grpc.WithChainUnaryInterceptor(
+			retryInterceptor(cfg.MenuCallMaxRetries, time.Duration(cfg.MenuRetryBackoffMs)*time.Millisecond),
+			timeoutInterceptor(time.Duration(cfg.MenuCallTimeoutMs)*time.Millisecond),
+		),
 	)
 	if err != nil {
-		log.Printf("Warning: Failed to connect to Menu Service: %v", err)
-		// Return mock client for development
-		return &MenuClient{
-			conn:   nil,
-			client: &mockMenuClient{},
-		}, nil
+		return nil, fmt.Errorf("failed to create menu service client for %s: %w", address, err)
 	}
 
-	log.Printf("Connected to Menu Service at %s", address)
+	mc := &MenuClient{
+		conn: conn,
+		client: newBreakerMenuClient(
+			&grpcMenuClient{client: menupb.NewMenuServiceClient(conn)},
+			cfg.MenuBreakerFailureThreshold,
+			time.Duration(cfg.MenuBreakerOpenSeconds)*time.Second,
+		),
+		health:          healthpb.NewHealthClient(conn),
+		prepTimes:       NewPrepTimeCache(database.GetRedis(), time.Duration(cfg.MenuPrepTimeCacheTTLSeconds)*time.Second),
+		defaultPrepTime: cfg.MenuDefaultPrepTimeMinutes,
+	}
 
-	// Initialize actual gRPC client
-	// TODO: Replace with generated proto client when available
-	// client := pb.NewMenuServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.MenuConnectTimeoutSeconds)*time.Second)
+	defer cancel()
+	if err := mc.Ping(ctx); err != nil {
+		if cfg.MenuMockFallback {
+			log.Printf("Warning: Menu Service at %s not reachable: %v; using mock client", address, err)
+			conn.Close()
+			return newMockMenuClient(cfg), nil
+		}
+		log.Printf("Warning: Menu Service at %s not reachable yet: %v", address, err)
+		return mc, nil
+	}
 
+	log.Printf("Connected to Menu Service at %s", address)
+	return mc, nil
+}
+
+func newMockMenuClient(cfg *config.Config) *MenuClient {
 	return &MenuClient{
-		conn:   conn,
-		client: &mockMenuClient{},
-	}, nil
+		conn:            nil,
+		client:          &mockMenuClient{},
+		prepTimes:       NewPrepTimeCache(database.GetRedis(), time.Duration(cfg.MenuPrepTimeCacheTTLSeconds)*time.Second),
+		defaultPrepTime: cfg.MenuDefaultPrepTimeMinutes,
+	}
 }
 
 func (mc *MenuClient) Close() error {
@@ -78,6 +136,22 @@ func (mc *MenuClient) Close() error {
 	return nil
 }
 
+// Ping asks the Menu Service over the gRPC health protocol whether it is serving
+func (mc *MenuClient) Ping(ctx context.Context) error {
+	if mc.conn == nil {
+		return errors.New("not connected to menu service, using mock client")
+	}
+
+	resp, err := mc.health.Check(ctx, &healthpb.HealthCheckRequest{Service: menuServiceName})
+	if err != nil {
+		return fmt.Errorf("menu service health check failed: %w", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("menu service is %s", resp.GetStatus())
+	}
+	return nil
+}
+
 func (mc *MenuClient) GetMenuItem(ctx context.Context, itemID string) (*MenuItem, error) {
 	return mc.client.GetMenuItem(ctx, itemID)
 }
@@ -86,8 +160,128 @@ func (mc *MenuClient) GetMenuItems(ctx context.Context, itemIDs []string) ([]*Me
 	return mc.client.GetMenuItems(ctx, itemIDs)
 }
 
+// GetAveragePreparationTime averages per-item preparation times (itemIDs may
+// repeat an item to weight it), serving cached items and fetching only the
+// misses from the Menu Service. When the Menu Service fails (or its breaker
+// is open) the misses count at the default preparation time instead.
 func (mc *MenuClient) GetAveragePreparationTime(ctx context.Context, itemIDs []string) (int, error) {
-	return mc.client.GetAveragePreparationTime(ctx, itemIDs)
+	if len(itemIDs) == 0 {
+		return mc.defaultPrepTime, nil
+	}
+
+	unique := make([]string, 0, len(itemIDs))
+	seen := make(map[string]bool, len(itemIDs))
+	for _, itemID := range itemIDs {
+		if !seen[itemID] {
+			seen[itemID] = true
+			unique = append(unique, itemID)
+		}
+	}
+
+	times, missing := mc.prepTimes.GetMany(ctx, unique)
+	if len(missing) > 0 {
+		items, err := mc.client.GetMenuItems(ctx, missing)
+		if err != nil {
+			log.Printf("Menu Service unavailable, using default prep time for %d items: %v", len(missing), err)
+		}
+		fetched := make(map[string]int, len(items))
+		for _, item := range items {
+			fetched[item.ID] = item.PreparationTime
+			times[item.ID] = item.PreparationTime
+		}
+		mc.prepTimes.SetMany(ctx, fetched)
+	}
+
+	total := 0
+	for _, itemID := range itemIDs {
+		if minutes, ok := times[itemID]; ok {
+			total += minutes
+		} else {
+			total += mc.defaultPrepTime
+		}
+	}
+	return total / len(itemIDs), nil
+}
+
+// PrefetchPreparationTimes loads the whole menu into the prep-time cache
+func (mc *MenuClient) PrefetchPreparationTimes(ctx context.Context) (int, error) {
+	items, err := mc.client.ListMenuItems(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	times := make(map[string]int, len(items))
+	for _, item := range items {
+		times[item.ID] = item.PreparationTime
+	}
+	mc.prepTimes.SetMany(ctx, times)
+	return len(times), nil
+}
+
+// UpdatePreparationTime refreshes the cached preparation time of an item
+func (mc *MenuClient) UpdatePreparationTime(ctx context.Context, itemID string, minutes int) {
+	mc.prepTimes.Set(ctx, itemID, minutes)
+}
+
+// InvalidatePreparationTime drops an item from the preparation time cache
+func (mc *MenuClient) InvalidatePreparationTime(ctx context.Context, itemID string) {
+	mc.prepTimes.Invalidate(ctx, itemID)
+}
+
+// grpcMenuClient calls the Menu Service through the generated client
+type grpcMenuClient struct {
+	client menupb.MenuServiceClient
+}
+
+func (g *grpcMenuClient) GetMenuItem(ctx context.Context, itemID string) (*MenuItem, error) {
+	item, err := g.client.GetMenuItem(ctx, &menupb.GetMenuItemRequest{ItemId: itemID})
+	if err != nil {
+		return nil, err
+	}
+	return menuItemFromProto(item), nil
+}
+
+func (g *grpcMenuClient) GetMenuItems(ctx context.Context, itemIDs []string) ([]*MenuItem, error) {
+	resp, err := g.client.GetMenuItems(ctx, &menupb.GetMenuItemsRequest{ItemIds: itemIDs})
+	if err != nil {
+		return nil, err
+	}
+	return menuItemsFromProto(resp), nil
+}
+
+func (g *grpcMenuClient) ListMenuItems(ctx context.Context) ([]*MenuItem, error) {
+	resp, err := g.client.ListMenuItems(ctx, &menupb.ListMenuItemsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return menuItemsFromProto(resp), nil
+}
+
+func (g *grpcMenuClient) GetAveragePreparationTime(ctx context.Context, itemIDs []string) (int, error) {
+	resp, err := g.client.GetAveragePreparationTime(ctx, &menupb.GetAveragePreparationTimeRequest{ItemIds: itemIDs})
+	if err != nil {
+		return 0, err
+	}
+	return int(resp.GetAverageMinutes()), nil
+}
+
+func menuItemsFromProto(resp *menupb.GetMenuItemsResponse) []*MenuItem {
+	items := make([]*MenuItem, len(resp.GetItems()))
+	for i, item := range resp.GetItems() {
+		items[i] = menuItemFromProto(item)
+	}
+	return items
+}
+
+func menuItemFromProto(item *menupb.MenuItem) *MenuItem {
+	return &MenuItem{
+		ID:              item.GetId(),
+		Name:            item.GetName(),
+		Category:        item.GetCategory(),
+		PreparationTime: int(item.GetPreparationTime()),
+		Price:           item.GetPrice(),
+		IsAvailable:     item.GetIsAvailable(),
+	}
 }
 
 // Mock implementation for development
@@ -118,6 +312,11 @@ func (m *mockMenuClient) GetMenuItems(ctx context.Context, itemIDs []string) ([]
 	return items, nil
 }
 
+func (m *mockMenuClient) ListMenuItems(ctx context.Context) ([]*MenuItem, error) {
+	// Mock: no menu to prefetch
+	return nil, nil
+}
+
 func (m *mockMenuClient) GetAveragePreparationTime(ctx context.Context, itemIDs []string) (int, error) {
 	// Mock: return 10 minutes average
 	return 10, nil
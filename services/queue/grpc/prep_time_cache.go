@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"gin-quickstart/metrics"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const prepTimeKeyPrefix = "menu:prep_time:"
+
+// PrepTimeCache holds per-item preparation times (minutes) so ETA
+// calculations don't call the Menu Service for every entry. Entries live in
+// Redis with a TTL, shared by all replicas; without Redis they are kept in
+// process. It is kept fresh by menu.updated events.
+type PrepTimeCache struct {
+	redis *redis.Client
+	ttl   time.Duration
+
+	mu    sync.RWMutex
+	local map[string]localPrepTime
+}
+
+type localPrepTime struct {
+	minutes   int
+	expiresAt time.Time
+}
+
+// NewPrepTimeCache creates a cache; rdb may be nil
+func NewPrepTimeCache(rdb *redis.Client, ttl time.Duration) *PrepTimeCache {
+	return &PrepTimeCache{
+		redis: rdb,
+		ttl:   ttl,
+		local: make(map[string]localPrepTime),
+	}
+}
+
+// GetMany returns the cached preparation times of the items, and the items
+// that are not cached
+func (c *PrepTimeCache) GetMany(ctx context.Context, itemIDs []string) (map[string]int, []string) {
+	times := make(map[string]int, len(itemIDs))
+	var missing []string
+
+	if c.redis != nil {
+		keys := make([]string, len(itemIDs))
+		for i, itemID := range itemIDs {
+			keys[i] = prepTimeKeyPrefix + itemID
+		}
+		values, err := c.redis.MGet(ctx, keys...).Result()
+		if err != nil {
+			log.Printf("Failed to read prep-time cache: %v", err)
+			values = make([]interface{}, len(itemIDs))
+		}
+		for i, itemID := range itemIDs {
+			value, _ := values[i].(string)
+			if minutes, err := strconv.Atoi(value); err == nil {
+				times[itemID] = minutes
+			} else {
+				missing = append(missing, itemID)
+			}
+		}
+	} else {
+		now := time.Now()
+		c.mu.RLock()
+		for _, itemID := range itemIDs {
+			if cached, ok := c.local[itemID]; ok && now.Before(cached.expiresAt) {
+				times[itemID] = cached.minutes
+			} else {
+				missing = append(missing, itemID)
+			}
+		}
+		c.mu.RUnlock()
+	}
+
+	metrics.MenuPrepTimeCacheLookupsTotal.WithLabelValues("hit").Add(float64(len(times)))
+	metrics.MenuPrepTimeCacheLookupsTotal.WithLabelValues("miss").Add(float64(len(missing)))
+	return times, missing
+}
+
+// SetMany stores preparation times of several items
+func (c *PrepTimeCache) SetMany(ctx context.Context, times map[string]int) {
+	if len(times) == 0 {
+		return
+	}
+
+	if c.redis != nil {
+		pipe := c.redis.Pipeline()
+		for itemID, minutes := range times {
+			pipe.Set(ctx, prepTimeKeyPrefix+itemID, minutes, c.ttl)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.Printf("Failed to write prep-time cache: %v", err)
+		}
+		return
+	}
+
+	expiresAt := time.Now().Add(c.ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for itemID, minutes := range times {
+		c.local[itemID] = localPrepTime{minutes: minutes, expiresAt: expiresAt}
+	}
+}
+
+// Set stores the preparation time of an item
+func (c *PrepTimeCache) Set(ctx context.Context, itemID string, minutes int) {
+	c.SetMany(ctx, map[string]int{itemID: minutes})
+}
+
+// Invalidate drops an item so the next lookup asks the Menu Service
+func (c *PrepTimeCache) Invalidate(ctx context.Context, itemID string) {
+	if c.redis != nil {
+		if err := c.redis.Del(ctx, prepTimeKeyPrefix+itemID).Err(); err != nil {
+			log.Printf("Failed to invalidate prep-time cache: %v", err)
+		}
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.local, itemID)
+}
@@ -0,0 +1,275 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"gin-quickstart/config"
+	"gin-quickstart/models"
+	queuepb "gin-quickstart/proto/queue"
+	"gin-quickstart/services"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// QueueServer serves the Queue API to other services over gRPC
+type QueueServer struct {
+	queuepb.UnimplementedQueueServiceServer
+	service *services.QueueService
+}
+
+func NewQueueServer(service *services.QueueService) *QueueServer {
+	return &QueueServer{service: service}
+}
+
+// Server is the running gRPC server with its health service
+type Server struct {
+	server *grpc.Server
+	health *health.Server
+}
+
+// Serve starts a gRPC server with the Queue API, the standard health service
+// and (when enabled) reflection on GRPC_PORT. The returned server is already
+// serving; stop it with Shutdown.
+func Serve(cfg *config.Config, service *services.QueueService) (*Server, error) {
+	listener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on gRPC port %s: %w", cfg.GRPCPort, err)
+	}
+
+	server := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	queuepb.RegisterQueueServiceServer(server, NewQueueServer(service))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus(queuepb.QueueService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	if cfg.GRPCReflectionEnabled {
+		reflection.Register(server)
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Printf("gRPC server stopped: %v", err)
+		}
+	}()
+
+	log.Printf("Queue gRPC server listening on port %s", cfg.GRPCPort)
+	return &Server{server: server, health: healthServer}, nil
+}
+
+// Shutdown reports NOT_SERVING to health checks, then drains in-flight RPCs
+func (s *Server) Shutdown() {
+	s.health.Shutdown()
+	s.server.GracefulStop()
+}
+
+// CreateQueueEntry queues an order, or returns its existing entry
+func (qs *QueueServer) CreateQueueEntry(ctx context.Context, req *queuepb.CreateQueueEntryRequest) (*queuepb.CreateQueueEntryResponse, error) {
+	if req.GetOrderId() == "" || req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id and user_id are required")
+	}
+	if err := checkEnum("token_type", req.GetTokenType(), models.TokenTypes); err != nil {
+		return nil, err
+	}
+	if err := checkEnum("priority", req.GetPriority(), models.QueuePriorities); err != nil {
+		return nil, err
+	}
+
+	createReq := &models.CreateQueueEntryRequest{
+		OrderID:         req.GetOrderId(),
+		UserID:          req.GetUserId(),
+		LocationID:      req.GetLocationId(),
+		UserName:        req.GetUserName(),
+		UserPhone:       req.GetUserPhone(),
+		TokenType:       req.GetTokenType(),
+		Priority:        req.GetPriority(),
+		IsExpressQueue:  req.GetIsExpressQueue(),
+		SpecialHandling: req.GetSpecialHandling(),
+		ItemCount:       int(req.GetItemCount()),
+		AwaitingPayment: req.GetAwaitingPayment(),
+	}
+	for _, item := range req.GetItems() {
+		createReq.Items = append(createReq.Items, models.CreateQueueItemRequest{
+			MenuItemID: item.GetMenuItemId(),
+			ItemName:   item.GetItemName(),
+			Quantity:   int(item.GetQuantity()),
+		})
+	}
+
+	entry, created, err := qs.service.EnqueueOrder(ctx, createReq)
+	if err != nil {
+		return nil, toStatus(err, "failed to create queue entry")
+	}
+
+	return &queuepb.CreateQueueEntryResponse{Entry: entryToProto(entry), Created: created}, nil
+}
+
+// GetPosition returns the position of a token
+func (qs *QueueServer) GetPosition(ctx context.Context, req *queuepb.GetPositionRequest) (*queuepb.GetPositionResponse, error) {
+	if req.GetTokenNumber() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token_number is required")
+	}
+
+	position, err := qs.service.GetQueuePosition(ctx, req.GetTokenNumber())
+	if err != nil {
+		return nil, toStatus(err, "failed to get queue position")
+	}
+
+	return &queuepb.GetPositionResponse{
+		Entry:              entryToProto(position.QueueEntry),
+		Position:           int32(position.Position),
+		EstimatedWaitTime:  int32(position.EstimatedWaitTime),
+		EstimatedReadyTime: timestampOrNil(position.EstimatedReadyTime),
+		PeopleAhead:        int32(position.PeopleAhead),
+	}, nil
+}
+
+// UpdateStatus moves an entry to a new status
+func (qs *QueueServer) UpdateStatus(ctx context.Context, req *queuepb.UpdateStatusRequest) (*queuepb.UpdateStatusResponse, error) {
+	if req.GetEntryId() == "" || req.GetStatus() == "" {
+		return nil, status.Error(codes.InvalidArgument, "entry_id and status are required")
+	}
+	if err := checkEnum("status", req.GetStatus(), models.QueueStatuses); err != nil {
+		return nil, err
+	}
+
+	actorID, actorName := req.GetActorId(), req.GetActorName()
+	if actorID == "" {
+		actorID, actorName = "system", "System"
+	}
+
+	updateReq := &models.UpdateQueueStatusRequest{
+		Status:          req.GetStatus(),
+		AssignedCounter: req.AssignedCounter,
+		AssignedStaff:   req.AssignedStaff,
+		Notes:           req.Notes,
+		Reason:          req.Reason,
+	}
+	if err := qs.service.UpdateQueueStatus(ctx, req.GetEntryId(), updateReq, actorID, actorName); err != nil {
+		return nil, toStatus(err, "failed to update queue status")
+	}
+
+	entry, err := qs.service.GetQueueEntryByID(ctx, req.GetEntryId())
+	if err != nil {
+		return nil, toStatus(err, "failed to get queue entry")
+	}
+
+	return &queuepb.UpdateStatusResponse{Entry: entryToProto(entry)}, nil
+}
+
+// GetCurrentQueue returns the active entries of the requested location (the
+// default one when unset) grouped by status
+func (qs *QueueServer) GetCurrentQueue(ctx context.Context, req *queuepb.GetCurrentQueueRequest) (*queuepb.GetCurrentQueueResponse, error) {
+	if locationID := req.GetLocationId(); locationID != "" {
+		if !services.ValidLocationID(locationID) {
+			return nil, status.Error(codes.InvalidArgument, "invalid location_id")
+		}
+		ctx = services.WithLocation(ctx, locationID)
+	}
+
+	queue, err := qs.service.GetCurrentQueue(ctx)
+	if err != nil {
+		return nil, toStatus(err, "failed to get current queue")
+	}
+
+	// Both lanes, told apart by is_express_queue
+	return &queuepb.GetCurrentQueueResponse{
+		Waiting:     entriesToProto(slices.Concat(queue.Waiting, queue.ExpressWaiting)),
+		InProgress:  entriesToProto(queue.InProgress),
+		Ready:       entriesToProto(queue.Ready),
+		TotalActive: int32(queue.TotalActive),
+	}, nil
+}
+
+// checkEnum rejects a set value outside its allowed values, as the HTTP
+// API's enum validators do
+func checkEnum(field, value string, allowed []string) error {
+	if value != "" && !slices.Contains(allowed, value) {
+		return status.Errorf(codes.InvalidArgument, "%s must be one of %s", field, strings.Join(allowed, ", "))
+	}
+	return nil
+}
+
+// toStatus maps a service error to a gRPC status
+func toStatus(err error, message string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Error(codes.NotFound, "queue entry not found")
+	}
+	if domainErr, ok := services.AsError(err); ok {
+		switch {
+		case errors.Is(err, services.ErrDuplicateOrder):
+			return status.Error(codes.AlreadyExists, err.Error())
+		case domainErr.Kind == services.KindInvalid:
+			return status.Error(codes.InvalidArgument, err.Error())
+		case domainErr.Kind == services.KindNotFound:
+			return status.Error(codes.NotFound, err.Error())
+		case domainErr.Kind == services.KindConflict:
+			return status.Error(codes.FailedPrecondition, err.Error())
+		case domainErr.Kind == services.KindUnavailable:
+			return status.Error(codes.Unavailable, err.Error())
+		case domainErr.Kind == services.KindRateLimited:
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		return status.Error(codes.Canceled, err.Error())
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Errorf(codes.Internal, "%s: %v", message, err)
+}
+
+func entryToProto(entry *models.QueueEntry) *queuepb.QueueEntry {
+	if entry == nil {
+		return nil
+	}
+	return &queuepb.QueueEntry{
+		Id:                 entry.ID,
+		OrderId:            entry.OrderID,
+		LocationId:         entry.LocationID,
+		UserId:             entry.UserID,
+		TokenNumber:        entry.TokenNumber,
+		TokenType:          entry.TokenType,
+		Status:             entry.Status,
+		Priority:           entry.Priority,
+		Position:           int32(entry.Position),
+		EstimatedWaitTime:  int32(entry.EstimatedWaitTime),
+		EstimatedReadyTime: timestampOrNil(entry.EstimatedReadyTime),
+		IsExpressQueue:     entry.IsExpressQueue,
+		AssignedCounter:    entry.AssignedCounter,
+		CreatedAt:          timestamppb.New(entry.CreatedAt),
+		UpdatedAt:          timestamppb.New(entry.UpdatedAt),
+	}
+}
+
+func entriesToProto(entries []models.QueueEntry) []*queuepb.QueueEntry {
+	result := make([]*queuepb.QueueEntry, len(entries))
+	for i := range entries {
+		result[i] = entryToProto(&entries[i])
+	}
+	return result
+}
+
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListAuditLog lists audited staff and admin requests, newest first, filtered
+// by ?actor=, ?method=, ?route= (e.g. /api/v1/queue/:id/status), ?failed=true|false
+// and ?from=/?to= business days (Admin only)
+// GET /api/queue/admin/audit-log
+func (h *QueueHandler) ListAuditLog(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	filter := models.AuditLogFilter{
+		ActorID: c.Query("actor"),
+		Method:  strings.ToUpper(c.Query("method")),
+		Route:   c.Query("route"),
+	}
+	if value := c.Query("failed"); value != "" {
+		failed, err := strconv.ParseBool(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: translate(c, "Invalid failed parameter")})
+			return
+		}
+		filter.Failed = &failed
+	}
+	for param, date := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   translate(c, "Invalid date format"),
+					Message: fmt.Sprintf(translate(c, "Use YYYY-MM-DD format for %s"), param),
+				})
+				return
+			}
+			*date = &parsed
+		}
+	}
+	// Dates are business days in the restaurant's time zone; to is inclusive
+	if filter.From != nil {
+		begin := clock.DayStart(*filter.From)
+		filter.From = &begin
+	}
+	if filter.To != nil {
+		end := clock.DayStart(filter.To.AddDate(0, 0, 1))
+		filter.To = &end
+	}
+
+	result, err := h.service.ListAuditLog(c.Request.Context(), &filter, limit, offset)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to list audit log")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBatchSuggestions lists identical items across adjacent orders for the KDS (Staff only)
+// GET /api/queue/kds/batches?location_id=default
+func (h *QueueHandler) GetBatchSuggestions(c *gin.Context) {
+	suggestions, err := h.service.GetBatchSuggestions(c.Request.Context(), c.Query("location_id"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get batch suggestions")
+		return
+	}
+
+	c.JSON(http.StatusOK, suggestions)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetClock reports the service time and whether it is simulated (Admin only)
+// GET /api/queue/admin/clock
+func (h *QueueHandler) GetClock(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.GetClockStatus(c.Request.Context()))
+}
+
+// AdvanceClock moves simulated time forward (Admin only, CLOCK_MODE=simulated)
+// POST /api/queue/admin/clock/advance
+func (h *QueueHandler) AdvanceClock(c *gin.Context) {
+	var req models.AdvanceClockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	d, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   translate(c, "Invalid duration"),
+			Message: translate(c, "Use a Go duration such as 90m or 2h"),
+		})
+		return
+	}
+
+	status, err := h.service.AdvanceClock(c.Request.Context(), d)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to advance clock")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
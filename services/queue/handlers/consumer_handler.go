@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetConsumerStatus reports whether order event consumption is paused (Admin only)
+// GET /api/queue/admin/consumer
+func (h *QueueHandler) GetConsumerStatus(c *gin.Context) {
+	status, err := h.service.GetConsumerStatus(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get consumer status")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// PauseConsumer stops pulling order events, e.g. during a DB migration (Admin only)
+// POST /api/queue/admin/consumer/pause
+func (h *QueueHandler) PauseConsumer(c *gin.Context) {
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	status, err := h.service.PauseConsumer(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to pause consumer")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Kafka consumption paused"),
+		Data:    status,
+	})
+}
+
+// ResumeConsumer resumes pulling order events (Admin only)
+// POST /api/queue/admin/consumer/resume
+func (h *QueueHandler) ResumeConsumer(c *gin.Context) {
+	status, err := h.service.ResumeConsumer(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to resume consumer")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Kafka consumption resumed"),
+		Data:    status,
+	})
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCounters lists counters with their current load (Staff only)
+// GET /api/queue/counters
+func (h *QueueHandler) GetCounters(c *gin.Context) {
+	counters, err := h.service.GetCounters(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get counters")
+		return
+	}
+
+	c.JSON(http.StatusOK, counters)
+}
+
+// OpenCounter reopens a counter (Staff only)
+// POST /api/queue/counters/:counterId/open
+func (h *QueueHandler) OpenCounter(c *gin.Context) {
+	counter, err := h.service.OpenCounter(c.Request.Context(), c.Param("counterId"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to open counter")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Counter opened successfully"),
+		Data:    counter,
+	})
+}
+
+// CloseCounter closes a counter and rebalances its entries (Staff only)
+// POST /api/queue/counters/:counterId/close
+func (h *QueueHandler) CloseCounter(c *gin.Context) {
+	userID, userName, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	result, err := h.service.CloseCounter(c.Request.Context(), c.Param("counterId"), userID, userName)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to close counter")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Counter closed and queue rebalanced"),
+		Data:    result,
+	})
+}
+
+// CreateCounter adds a counter (Admin only)
+// POST /api/queue/counters
+func (h *QueueHandler) CreateCounter(c *gin.Context) {
+	var req models.CounterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	counter, err := h.service.CreateCounter(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to create counter")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: translate(c, "Counter created successfully"),
+		Data:    counter,
+	})
+}
+
+// RenameCounter renames a counter (Admin only)
+// PUT /api/queue/counters/:counterId
+func (h *QueueHandler) RenameCounter(c *gin.Context) {
+	var req models.CounterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	counter, err := h.service.RenameCounter(c.Request.Context(), c.Param("counterId"), &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to rename counter")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Counter renamed successfully"),
+		Data:    counter,
+	})
+}
+
+// DeleteCounter removes a counter with no active entries (Admin only)
+// DELETE /api/queue/counters/:counterId
+func (h *QueueHandler) DeleteCounter(c *gin.Context) {
+	if err := h.service.DeleteCounter(c.Request.Context(), c.Param("counterId")); err != nil {
+		c.Error(err).SetMeta("Failed to delete counter")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Counter deleted successfully"),
+	})
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDashboard returns everything the staff home screen needs in one call (Staff only)
+// GET /api/queue/dashboard
+func (h *QueueHandler) GetDashboard(c *gin.Context) {
+	dashboard, err := h.service.GetDashboard(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to load dashboard")
+		return
+	}
+
+	c.JSON(http.StatusOK, dashboard)
+}
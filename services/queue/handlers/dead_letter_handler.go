@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListDeadLetters lists messages parked on the dead letter topic (Admin only)
+// GET /api/queue/admin/dlq?status=PENDING&topic=order.created&limit=50&offset=0
+func (h *QueueHandler) ListDeadLetters(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	result, err := h.service.ListDeadLetters(c.Request.Context(), c.Query("status"), c.Query("topic"), limit, offset)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to list dead letters")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RedriveDeadLetter republishes a dead letter to its original topic (Admin only)
+// POST /api/queue/admin/dlq/:messageId/redrive
+func (h *QueueHandler) RedriveDeadLetter(c *gin.Context) {
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	message, err := h.service.RedriveDeadLetter(c.Request.Context(), c.Param("messageId"), userID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to re-drive dead letter")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Dead letter re-driven successfully"),
+		Data:    message,
+	})
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListDepthLimits lists the waiting depth limits per lane and priority (Staff only)
+// GET /api/queue/depth-limits
+func (h *QueueHandler) ListDepthLimits(c *gin.Context) {
+	limits, err := h.service.ListDepthLimits(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to list depth limits")
+		return
+	}
+
+	c.JSON(http.StatusOK, limits)
+}
+
+// SetDepthLimit creates or replaces a depth limit (Admin only)
+// PUT /api/queue/depth-limits
+func (h *QueueHandler) SetDepthLimit(c *gin.Context) {
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.SetDepthLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	limit, err := h.service.SetDepthLimit(c.Request.Context(), &req, userID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to set depth limit")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Depth limit saved successfully"),
+		Data:    limit,
+	})
+}
+
+// DeleteDepthLimit removes a depth limit (Admin only)
+// DELETE /api/queue/depth-limits/:limitId
+func (h *QueueHandler) DeleteDepthLimit(c *gin.Context) {
+	if err := h.service.DeleteDepthLimit(c.Request.Context(), c.Param("limitId")); err != nil {
+		c.Error(err).SetMeta("Failed to delete depth limit")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Depth limit deleted successfully"),
+	})
+}
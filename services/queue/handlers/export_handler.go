@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/export"
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// formatJSON exports entries as a JSON array
+const formatJSON = "json"
+
+const (
+	// defaultExportDays is the range exported when from isn't given
+	defaultExportDays = 30
+	// maxExportDays bounds the range of one statistics export
+	maxExportDays = 366
+)
+
+var dailyStatsColumns = []string{
+	"date", "completed", "cancelled", "no_show", "expired", "walk_ins",
+	"avg_wait_time", "wait_time_p50", "wait_time_p90", "wait_time_p99",
+	"longest_wait_time", "shortest_wait_time",
+	"avg_preparation_time", "prep_time_p50", "prep_time_p90", "prep_time_p99",
+	"on_time_completion_rate", "no_show_rate",
+	"peak_load", "peak_load_time", "peak_queue_length", "peak_queue_length_time",
+}
+
+var hourlyStatsColumns = []string{
+	"date", "hour", "orders", "completed", "cancelled",
+	"avg_wait_time", "wait_time_p50", "wait_time_p90", "wait_time_p99",
+	"avg_preparation_time", "prep_time_p50", "prep_time_p90", "prep_time_p99",
+	"peak_queue_length",
+}
+
+// ExportStatistics downloads the daily and hourly statistics of a date range
+// as CSV or an XLSX workbook (Staff only)
+// GET /api/queue/stats/export?from=YYYY-MM-DD&to=YYYY-MM-DD&format=csv|xlsx
+func (h *QueueHandler) ExportStatistics(c *gin.Context) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != export.FormatXLSX {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   translate(c, "Invalid format"),
+			Message: translate(c, "Use csv or xlsx"),
+		})
+		return
+	}
+
+	to := clock.Date(h.service.GetClockStatus(c.Request.Context()).Now)
+	from := to.AddDate(0, 0, -(defaultExportDays - 1))
+	for param, date := range map[string]*time.Time{"from": &from, "to": &to} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   translate(c, "Invalid date format"),
+					Message: fmt.Sprintf(translate(c, "Use YYYY-MM-DD format for %s"), param),
+				})
+				return
+			}
+			*date = parsed
+		}
+	}
+	if to.Before(from) || to.Sub(from) >= maxExportDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   translate(c, "Invalid date range"),
+			Message: fmt.Sprintf("from must not be after to, and the range is at most %d days", maxExportDays),
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("queue-stats_%s_%s.%s", from.Format("2006-01-02"), to.Format("2006-01-02"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	sheets, _ := export.NewSheetWriter(format, c.Writer)
+	if err := h.writeStatsExport(c.Request.Context(), sheets, from, to); err != nil {
+		// The response has started, so the download is left truncated
+		log.Printf("Failed to export statistics: %v", err)
+	}
+}
+
+// writeStatsExport writes the Daily and Hourly sheets of a date range, each
+// a header row followed by the statistics rows
+func (h *QueueHandler) writeStatsExport(ctx context.Context, sheets export.SheetWriter, from, to time.Time) error {
+	if err := sheets.AddSheet("Daily"); err != nil {
+		return err
+	}
+	if err := sheets.WriteRow(dailyStatsColumns); err != nil {
+		return err
+	}
+	if err := h.service.StreamDailyStatistics(ctx, from, to, func(batch []models.QueueStatistics) error {
+		for i := range batch {
+			if err := sheets.WriteRow(dailyStatsRow(&batch[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := sheets.AddSheet("Hourly"); err != nil {
+		return err
+	}
+	if err := sheets.WriteRow(hourlyStatsColumns); err != nil {
+		return err
+	}
+	if err := h.service.StreamHourlyStatistics(ctx, from, to, func(batch []models.QueueHourlyStatistics) error {
+		for i := range batch {
+			if err := sheets.WriteRow(hourlyStatsRow(&batch[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return sheets.Close()
+}
+
+// ExportQueueEntries downloads the entries created in a date range, filtered
+// by status, assigned staff and counter, as CSV or a JSON array (Admin only)
+// GET /api/queue/admin/entries/export?from=YYYY-MM-DD&to=YYYY-MM-DD&status=A,B&staff=&counter=&format=csv|json
+func (h *QueueHandler) ExportQueueEntries(c *gin.Context) {
+	format := c.DefaultQuery("format", export.FormatCSV)
+	if format != export.FormatCSV && format != formatJSON {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   translate(c, "Invalid format"),
+			Message: translate(c, "Use csv or json"),
+		})
+		return
+	}
+
+	filter := models.QueueEntryExportFilter{
+		StaffID:   c.Query("staff"),
+		CounterID: c.Query("counter"),
+	}
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+	for param, date := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		if value := c.Query(param); value != "" {
+			parsed, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   translate(c, "Invalid date format"),
+					Message: fmt.Sprintf(translate(c, "Use YYYY-MM-DD format for %s"), param),
+				})
+				return
+			}
+			*date = &parsed
+		}
+	}
+	// Dates are business days in the restaurant's time zone; to is inclusive
+	if filter.From != nil {
+		begin := clock.DayStart(*filter.From)
+		filter.From = &begin
+	}
+	if filter.To != nil {
+		end := clock.DayStart(filter.To.AddDate(0, 0, 1))
+		filter.To = &end
+	}
+
+	// The response only starts with the first batch, so a rejected filter
+	// can still be answered with an error
+	var entries entryExportWriter
+	start := func() {
+		c.Header("Content-Type", export.ContentType(export.FormatCSV))
+		if format == formatJSON {
+			c.Header("Content-Type", "application/json; charset=utf-8")
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="queue-entries.%s"`, format))
+		c.Status(http.StatusOK)
+		entries = newEntryExportWriter(format, c.Writer)
+	}
+
+	err := h.service.StreamQueueEntries(c.Request.Context(), &filter, func(batch []models.QueueEntry) error {
+		if entries == nil {
+			start()
+		}
+		for i := range batch {
+			if err := entries.Write(&batch[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if entries == nil {
+		if err != nil {
+			c.Error(err).SetMeta("Failed to export entries")
+			return
+		}
+		start()
+	}
+	if err == nil {
+		err = entries.Close()
+	}
+	if err != nil {
+		// The response has started, so the download is left truncated
+		log.Printf("Failed to export entries: %v", err)
+	}
+}
+
+func dailyStatsRow(stats *models.QueueStatistics) []string {
+	return []string{
+		stats.Date.Format("2006-01-02"),
+		strconv.Itoa(stats.CompletedToday),
+		strconv.Itoa(stats.CancelledToday),
+		strconv.Itoa(stats.NoShowToday),
+		strconv.Itoa(stats.ExpiredToday),
+		strconv.Itoa(stats.WalkInsToday),
+		strconv.Itoa(stats.AvgWaitTime),
+		strconv.Itoa(stats.WaitTimeP50),
+		strconv.Itoa(stats.WaitTimeP90),
+		strconv.Itoa(stats.WaitTimeP99),
+		strconv.Itoa(stats.LongestWaitTime),
+		strconv.Itoa(stats.ShortestWaitTime),
+		strconv.Itoa(stats.AvgPreparationTime),
+		strconv.Itoa(stats.PrepTimeP50),
+		strconv.Itoa(stats.PrepTimeP90),
+		strconv.Itoa(stats.PrepTimeP99),
+		strconv.FormatFloat(stats.OnTimeCompletionRate, 'f', 2, 64),
+		strconv.FormatFloat(stats.NoShowRate, 'f', 2, 64),
+		strconv.FormatFloat(stats.PeakLoad, 'f', 2, 64),
+		stringOrEmpty(stats.PeakLoadTime),
+		strconv.Itoa(stats.PeakQueueLength),
+		stringOrEmpty(stats.PeakQueueLengthTime),
+	}
+}
+
+func hourlyStatsRow(stats *models.QueueHourlyStatistics) []string {
+	return []string{
+		stats.Date.Format("2006-01-02"),
+		strconv.Itoa(stats.Hour),
+		strconv.Itoa(stats.OrderCount),
+		strconv.Itoa(stats.CompletedCount),
+		strconv.Itoa(stats.CancelledCount),
+		strconv.Itoa(stats.AvgWaitTime),
+		strconv.Itoa(stats.WaitTimeP50),
+		strconv.Itoa(stats.WaitTimeP90),
+		strconv.Itoa(stats.WaitTimeP99),
+		strconv.Itoa(stats.AvgPreparationTime),
+		strconv.Itoa(stats.PrepTimeP50),
+		strconv.Itoa(stats.PrepTimeP90),
+		strconv.Itoa(stats.PrepTimeP99),
+		strconv.Itoa(stats.PeakPosition),
+	}
+}
+
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+var entryExportColumns = []string{
+	"id", "order_id", "location_id", "token_number", "token_type", "status", "priority",
+	"is_express_queue", "position", "item_count", "user_id", "assigned_staff", "assigned_staff_name",
+	"counter_id", "estimated_wait_time", "created_at", "actual_start_time", "actual_ready_time",
+	"actual_completion_time", "sla_breached_at",
+}
+
+// entryExportWriter writes exported entries one at a time
+type entryExportWriter interface {
+	Write(entry *models.QueueEntry) error
+	Close() error
+}
+
+func newEntryExportWriter(format string, w io.Writer) entryExportWriter {
+	if format == formatJSON {
+		return &jsonEntryWriter{w: w}
+	}
+	return &csvEntryWriter{w: export.NewCSVWriter(w)}
+}
+
+// jsonEntryWriter writes entries as the elements of a JSON array
+type jsonEntryWriter struct {
+	w       io.Writer
+	written int
+}
+
+func (j *jsonEntryWriter) Write(entry *models.QueueEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	separator := ","
+	if j.written == 0 {
+		separator = "["
+	}
+	j.written++
+	_, err = io.WriteString(j.w, separator+string(data))
+	return err
+}
+
+func (j *jsonEntryWriter) Close() error {
+	if j.written == 0 {
+		_, err := io.WriteString(j.w, "[]")
+		return err
+	}
+	_, err := io.WriteString(j.w, "]")
+	return err
+}
+
+// csvEntryWriter writes entries as CSV rows under a header row
+type csvEntryWriter struct {
+	w      *export.CSVWriter
+	header bool
+}
+
+func (c *csvEntryWriter) Write(entry *models.QueueEntry) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.w.WriteRow([]string{
+		entry.ID,
+		entry.OrderID,
+		entry.LocationID,
+		entry.TokenNumber,
+		entry.TokenType,
+		entry.Status,
+		entry.Priority,
+		strconv.FormatBool(entry.IsExpressQueue),
+		strconv.Itoa(entry.Position),
+		strconv.Itoa(entry.ItemCount),
+		entry.UserID,
+		stringOrEmpty(entry.AssignedStaff),
+		stringOrEmpty(entry.AssignedStaffName),
+		stringOrEmpty(entry.CounterID),
+		strconv.Itoa(entry.EstimatedWaitTime),
+		clock.Local(entry.CreatedAt).Format(time.RFC3339),
+		timeOrEmpty(entry.ActualStartTime),
+		timeOrEmpty(entry.ActualReadyTime),
+		timeOrEmpty(entry.ActualCompletionTime),
+		timeOrEmpty(entry.SLABreachedAt),
+	})
+}
+
+func (c *csvEntryWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+	c.header = true
+	return c.w.WriteRow(entryExportColumns)
+}
+
+func (c *csvEntryWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	return c.w.Close()
+}
+
+func timeOrEmpty(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return clock.Local(*value).Format(time.RFC3339)
+}
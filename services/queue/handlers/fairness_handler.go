@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExplainQueuePosition explains why a token is at its position
+// GET /api/queue/:token/why
+func (h *QueueHandler) ExplainQueuePosition(c *gin.Context) {
+	// Registered as /:id/why to share the wildcard with the other /:id routes
+	token, err := h.service.ResolveLookupCode(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to explain position")
+		return
+	}
+
+	explanation, err := h.service.ExplainPosition(c.Request.Context(), token)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to explain position")
+		return
+	}
+
+	c.JSON(http.StatusOK, explanation)
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadinessCheck reports per-dependency health
+// GET /health/ready
+func ReadinessCheck(c *gin.Context) {
+	report := health.Check(c.Request.Context(), "queue-service")
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetIntegrityReport returns the latest data integrity report (Admin only)
+// GET /api/queue/admin/integrity
+func (h *QueueHandler) GetIntegrityReport(c *gin.Context) {
+	report, err := h.service.GetIntegrityReport(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get integrity report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// RunIntegrityCheck checks data integrity, optionally repairing what it finds (Admin only)
+// POST /api/queue/admin/integrity/check?repair=true
+func (h *QueueHandler) RunIntegrityCheck(c *gin.Context) {
+	repair, err := strconv.ParseBool(c.DefaultQuery("repair", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   translate(c, "Invalid repair flag"),
+			Message: err.Error(),
+		})
+		return
+	}
+
+	report, err := h.service.CheckIntegrity(c.Request.Context(), repair)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to check integrity")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"gin-quickstart/models"
+	"gin-quickstart/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListKPIDefinitions lists custom KPI definitions and the counters they may use (Staff only)
+// GET /api/queue/kpis
+func (h *QueueHandler) ListKPIDefinitions(c *gin.Context) {
+	definitions, err := h.service.ListKPIDefinitions(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to list KPI definitions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"definitions": definitions,
+		"counters":    services.KPICounterNames,
+	})
+}
+
+// CreateKPIDefinition defines a custom KPI (Admin only)
+// POST /api/queue/kpis
+func (h *QueueHandler) CreateKPIDefinition(c *gin.Context) {
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.CreateKPIDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	definition, err := h.service.CreateKPIDefinition(c.Request.Context(), &req, userID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to create KPI definition")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: translate(c, "KPI definition created successfully"),
+		Data:    definition,
+	})
+}
+
+// UpdateKPIDefinition changes a custom KPI (Admin only)
+// PUT /api/queue/kpis/:kpiId
+func (h *QueueHandler) UpdateKPIDefinition(c *gin.Context) {
+	var req models.UpdateKPIDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	definition, err := h.service.UpdateKPIDefinition(c.Request.Context(), c.Param("kpiId"), &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to update KPI definition")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "KPI definition updated successfully"),
+		Data:    definition,
+	})
+}
+
+// DeleteKPIDefinition removes a custom KPI (Admin only)
+// DELETE /api/queue/kpis/:kpiId
+func (h *QueueHandler) DeleteKPIDefinition(c *gin.Context) {
+	if err := h.service.DeleteKPIDefinition(c.Request.Context(), c.Param("kpiId")); err != nil {
+		c.Error(err).SetMeta("Failed to delete KPI definition")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "KPI definition deleted successfully"),
+	})
+}
+
+// GetKPIReport gets the daily counters and custom KPIs (Staff only)
+// GET /api/queue/stats/kpis?date=YYYY-MM-DD
+func (h *QueueHandler) GetKPIReport(c *gin.Context) {
+	var date *time.Time
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   translate(c, "Invalid date format"),
+				Message: translate(c, "Use YYYY-MM-DD format"),
+			})
+			return
+		}
+		date = &parsedDate
+	}
+
+	report, err := h.service.GetKPIReport(c.Request.Context(), date)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get KPI report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
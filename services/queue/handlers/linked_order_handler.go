@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LinkOrders adds orders to an entry's party token (Staff only)
+// POST /api/queue/:id/orders
+func (h *QueueHandler) LinkOrders(c *gin.Context) {
+	entryID := c.Param("id")
+	userID, userName, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.LinkOrdersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	orders, err := h.service.LinkOrders(c.Request.Context(), entryID, &req, userID, userName)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to link orders")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Orders linked successfully"),
+		Data:    orders,
+	})
+}
+
+// GetLinkedOrders gets the progress of each order of a party token (Staff only)
+// GET /api/queue/:id/orders
+func (h *QueueHandler) GetLinkedOrders(c *gin.Context) {
+	entryID := c.Param("id")
+
+	orders, err := h.service.GetLinkedOrders(c.Request.Context(), entryID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get linked orders")
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// GetLinkedOrdersByToken gets the progress of each order of a party token by token (public)
+// GET /api/queue/token/:token/orders
+func (h *QueueHandler) GetLinkedOrdersByToken(c *gin.Context) {
+	token, err := h.service.ResolveLookupCode(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get linked orders")
+		return
+	}
+
+	orders, err := h.service.GetLinkedOrdersByToken(c.Request.Context(), token)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get linked orders")
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// UpdateLinkedOrderStatus updates the status of one order of a party token (Staff only)
+// PATCH /api/queue/:id/orders/:orderId/status
+func (h *QueueHandler) UpdateLinkedOrderStatus(c *gin.Context) {
+	entryID := c.Param("id")
+	orderID := c.Param("orderId")
+	userID, userName, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.UpdateLinkedOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	order, err := h.service.UpdateLinkedOrderStatus(c.Request.Context(), entryID, orderID, &req, userID, userName)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to update order status")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Order status updated successfully"),
+		Data:    order,
+	})
+}
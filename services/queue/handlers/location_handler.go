@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListLocations lists the locations served by this deployment
+// GET /api/queue/locations
+func (h *QueueHandler) ListLocations(c *gin.Context) {
+	locations, err := h.service.ListLocations(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to list locations")
+		return
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+// CreateLocation adds a location (Admin only)
+// POST /api/queue/locations
+func (h *QueueHandler) CreateLocation(c *gin.Context) {
+	var req models.CreateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	location, err := h.service.CreateLocation(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to create location")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: translate(c, "Location created successfully"),
+		Data:    location,
+	})
+}
+
+// UpdateLocation renames, re-prefixes or (de)activates a location (Admin only)
+// PUT /api/queue/locations/:locationId
+func (h *QueueHandler) UpdateLocation(c *gin.Context) {
+	var req models.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	location, err := h.service.UpdateLocation(c.Request.Context(), c.Param("locationId"), &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to update location")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Location updated successfully"),
+		Data:    location,
+	})
+}
+
+// DeactivateLocation stops a location from taking new entries (Admin only)
+// DELETE /api/queue/locations/:locationId
+func (h *QueueHandler) DeactivateLocation(c *gin.Context) {
+	if err := h.service.DeactivateLocation(c.Request.Context(), c.Param("locationId")); err != nil {
+		c.Error(err).SetMeta("Failed to deactivate location")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Location deactivated successfully"),
+	})
+}
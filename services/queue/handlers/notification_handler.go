@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/middleware"
+	"gin-quickstart/models"
+	"gin-quickstart/notify"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SMSStatusCallback records SMS delivery status reported by the provider
+// (public; requests must carry a valid X-Twilio-Signature)
+// POST /api/queue/notifications/sms/status
+func (h *QueueHandler) SMSStatusCallback(c *gin.Context) {
+	if err := c.Request.ParseForm(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: translate(c, "Invalid form body")})
+		return
+	}
+
+	if !notify.VerifySMSStatusCallback(c.Request.PostForm, c.GetHeader("X-Twilio-Signature")) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: translate(c, "Invalid signature")})
+		return
+	}
+
+	messageID := c.Request.PostForm.Get("MessageSid")
+	status := c.Request.PostForm.Get("MessageStatus")
+	if messageID == "" || status == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: translate(c, "MessageSid and MessageStatus are required")})
+		return
+	}
+
+	var deliveryError *string
+	if code := c.Request.PostForm.Get("ErrorCode"); code != "" {
+		deliveryError = &code
+	}
+
+	if _, err := h.service.RecordDeliveryStatus(c.Request.Context(), messageID, status, deliveryError); err != nil {
+		c.Error(err).SetMeta("Failed to record delivery status")
+		return
+	}
+
+	// Unknown message IDs are acknowledged too, so the provider doesn't retry them
+	c.Status(http.StatusNoContent)
+}
+
+// GetNotificationPreferences returns the caller's notification channels
+// GET /api/queue/notifications/preferences
+func (h *QueueHandler) GetNotificationPreferences(c *gin.Context) {
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	pref, err := h.service.GetNotificationPreference(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, pref)
+}
+
+// UpdateNotificationPreferences changes the caller's notification channels
+// PUT /api/queue/notifications/preferences
+func (h *QueueHandler) UpdateNotificationPreferences(c *gin.Context) {
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.UpdateNotificationPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	// The account email is used when email is turned on without an address
+	accountEmail, _ := c.Get("user_email")
+	fallbackEmail, _ := accountEmail.(string)
+
+	// Notifications follow the language of the app until the customer picks one
+	pref, err := h.service.UpdateNotificationPreference(c.Request.Context(), userID, &req, fallbackEmail, middleware.Language(c))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to update notification preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: translate(c, "Notification preferences updated"), Data: pref})
+}
+
+// RegisterDevice registers the caller's device for push notifications
+// POST /api/queue/notifications/devices
+func (h *QueueHandler) RegisterDevice(c *gin.Context) {
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.RegisterDeviceTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	device, err := h.service.RegisterDeviceToken(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to register device")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{Message: translate(c, "Device registered"), Data: device})
+}
+
+// UnregisterDevice stops push notifications to one of the caller's devices
+// DELETE /api/queue/notifications/devices/:token
+func (h *QueueHandler) UnregisterDevice(c *gin.Context) {
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	removed, err := h.service.RemoveDeviceToken(c.Request.Context(), userID, c.Param("token"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to unregister device")
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: translate(c, "Device not found")})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: translate(c, "Device unregistered")})
+}
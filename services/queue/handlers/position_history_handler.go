@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPositionHistory gets how an entry's position, status and ETA changed (Staff only)
+// GET /api/queue/:id/history
+func (h *QueueHandler) GetPositionHistory(c *gin.Context) {
+	entryID := c.Param("id")
+
+	history, err := h.service.GetPositionHistory(c.Request.Context(), entryID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get position history")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetPositionHistoryByToken gets how a token's position and ETA changed (public)
+// GET /api/queue/token/:token/history
+func (h *QueueHandler) GetPositionHistoryByToken(c *gin.Context) {
+	token, err := h.service.ResolveLookupCode(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get position history")
+		return
+	}
+
+	history, err := h.service.GetPositionHistoryByToken(c.Request.Context(), token)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get position history")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 
+	"gin-quickstart/i18n"
+	"gin-quickstart/middleware"
 	"gin-quickstart/models"
 	"gin-quickstart/services"
 
@@ -33,29 +38,59 @@ func GetUserFromContext(c *gin.Context) (string, string, string, bool) {
 	return userID.(string), userName.(string), userRole.(string), true
 }
 
+// translate returns a response message in the request's language
+func translate(c *gin.Context, message string) string {
+	return i18n.Translate(middleware.Language(c), message)
+}
+
 // CreateQueueEntry creates a new queue entry
 // POST /api/queue
 func (h *QueueHandler) CreateQueueEntry(c *gin.Context) {
 	var req models.CreateQueueEntryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request",
-			Message: err.Error(),
-		})
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
 		return
 	}
 
+	// Customers are limited per user; staff queue on others' behalf
+	if role := c.GetString("user_role"); role != "staff" && role != "admin" {
+		if err := h.service.CheckUserEntryLimits(c.Request.Context(), req.UserID); err != nil {
+			c.Error(err).SetMeta("Failed to create queue entry")
+			return
+		}
+	}
+
 	entry, err := h.service.CreateQueueEntry(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to create queue entry",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to create queue entry")
+		return
+	}
+	entry.LookupCode = services.LookupCode(entry)
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: translate(c, "Queue entry created successfully"),
+		Data:    entry,
+	})
+}
+
+// CreateWalkInEntry queues a walk-in customer without an order (Staff only)
+// POST /api/queue/walk-in
+func (h *QueueHandler) CreateWalkInEntry(c *gin.Context) {
+	var req models.CreateWalkInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
 		return
 	}
 
+	entry, err := h.service.CreateWalkInEntry(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to create queue entry")
+		return
+	}
+	entry.LookupCode = services.LookupCode(entry)
+
 	c.JSON(http.StatusCreated, models.SuccessResponse{
-		Message: "Queue entry created successfully",
+		Message: translate(c, "Walk-in queued successfully"),
 		Data:    entry,
 	})
 }
@@ -63,14 +98,15 @@ func (h *QueueHandler) CreateQueueEntry(c *gin.Context) {
 // GetQueuePosition gets position for a token
 // GET /api/queue/position/:token
 func (h *QueueHandler) GetQueuePosition(c *gin.Context) {
-	token := c.Param("token")
+	token, err := h.service.ResolveLookupCode(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get queue position")
+		return
+	}
 
 	position, err := h.service.GetQueuePosition(c.Request.Context(), token)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Queue entry not found",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to get queue position")
 		return
 	}
 
@@ -80,14 +116,15 @@ func (h *QueueHandler) GetQueuePosition(c *gin.Context) {
 // GetQueueEntryByToken gets queue entry by token
 // GET /api/queue/token/:token
 func (h *QueueHandler) GetQueueEntryByToken(c *gin.Context) {
-	token := c.Param("token")
+	token, err := h.service.ResolveLookupCode(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get queue entry")
+		return
+	}
 
 	entry, err := h.service.GetQueueEntryByToken(c.Request.Context(), token)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Queue entry not found",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to get queue entry")
 		return
 	}
 
@@ -101,12 +138,10 @@ func (h *QueueHandler) GetQueueEntryByOrderID(c *gin.Context) {
 
 	entry, err := h.service.GetQueueEntryByOrderID(c.Request.Context(), orderID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error:   "Queue entry not found",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to get queue entry")
 		return
 	}
+	entry.LookupCode = services.LookupCode(entry)
 
 	c.JSON(http.StatusOK, entry)
 }
@@ -116,10 +151,7 @@ func (h *QueueHandler) GetQueueEntryByOrderID(c *gin.Context) {
 func (h *QueueHandler) GetCurrentQueue(c *gin.Context) {
 	queue, err := h.service.GetCurrentQueue(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to get current queue",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to get current queue")
 		return
 	}
 
@@ -132,29 +164,23 @@ func (h *QueueHandler) UpdateQueueStatus(c *gin.Context) {
 	entryID := c.Param("id")
 	userID, userName, _, ok := GetUserFromContext(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
 		return
 	}
 
 	var req models.UpdateQueueStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request",
-			Message: err.Error(),
-		})
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
 		return
 	}
 
 	if err := h.service.UpdateQueueStatus(c.Request.Context(), entryID, &req, userID, userName); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to update queue status",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to update queue status")
 		return
 	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
-		Message: "Queue status updated successfully",
+		Message: translate(c, "Queue status updated successfully"),
 	})
 }
 
@@ -164,29 +190,144 @@ func (h *QueueHandler) UpdateQueuePriority(c *gin.Context) {
 	entryID := c.Param("id")
 	userID, userName, _, ok := GetUserFromContext(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
 		return
 	}
 
 	var req models.UpdateQueuePriorityRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request",
-			Message: err.Error(),
-		})
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
 		return
 	}
 
 	if err := h.service.UpdateQueuePriority(c.Request.Context(), entryID, &req, userID, userName); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to update queue priority",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to update queue priority")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Queue priority updated successfully"),
+	})
+}
+
+// RequeueEntry reinstates a no-show or expired entry with its token (Staff only)
+// POST /api/queue/:id/requeue
+func (h *QueueHandler) RequeueEntry(c *gin.Context) {
+	entryID := c.Param("id")
+	userID, userName, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.RequeueRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+			return
+		}
+	}
+
+	entry, err := h.service.RequeueEntry(c.Request.Context(), entryID, &req, userID, userName)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to requeue entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Queue entry requeued successfully"),
+		Data:    entry,
+	})
+}
+
+// SkipEntry bumps a waiting entry down a few places for a while (Staff only)
+// POST /api/queue/:id/skip
+func (h *QueueHandler) SkipEntry(c *gin.Context) {
+	entryID := c.Param("id")
+	userID, userName, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.SkipEntryRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+			return
+		}
+	}
+
+	entry, err := h.service.SkipEntry(c.Request.Context(), entryID, &req, userID, userName)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to skip entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Queue entry skipped successfully"),
+		Data:    entry,
+	})
+}
+
+// RecallEntry calls a ready token again (Staff only)
+// POST /api/queue/:id/recall
+func (h *QueueHandler) RecallEntry(c *gin.Context) {
+	entryID := c.Param("id")
+	userID, userName, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	entry, err := h.service.RecallEntry(c.Request.Context(), entryID, userID, userName)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to recall entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Queue entry recalled successfully"),
+		Data:    entry,
+	})
+}
+
+// GetScheduledEntries lists entries waiting for a scheduled pickup (Staff only)
+// GET /api/queue/scheduled
+func (h *QueueHandler) GetScheduledEntries(c *gin.Context) {
+	entries, err := h.service.GetScheduledEntries(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get scheduled entries")
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// ReorderQueue puts a lane's waiting entries in a new order (Staff only)
+// PUT /api/queue/reorder
+func (h *QueueHandler) ReorderQueue(c *gin.Context) {
+	userID, userName, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.ReorderQueueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	entries, err := h.service.ReorderQueue(c.Request.Context(), &req, userID, userName)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to reorder queue")
 		return
 	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
-		Message: "Queue priority updated successfully",
+		Message: translate(c, "Queue reordered successfully"),
+		Data:    entries,
 	})
 }
 
@@ -196,29 +337,23 @@ func (h *QueueHandler) AssignStaff(c *gin.Context) {
 	entryID := c.Param("id")
 	userID, userName, _, ok := GetUserFromContext(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
 		return
 	}
 
 	var req models.AssignStaffRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request",
-			Message: err.Error(),
-		})
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
 		return
 	}
 
 	if err := h.service.AssignStaff(c.Request.Context(), entryID, &req, userID, userName); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to assign staff",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to assign staff")
 		return
 	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
-		Message: "Staff assigned successfully",
+		Message: translate(c, "Staff assigned successfully"),
 	})
 }
 
@@ -227,20 +362,17 @@ func (h *QueueHandler) AssignStaff(c *gin.Context) {
 func (h *QueueHandler) AdvanceQueue(c *gin.Context) {
 	userID, userName, _, ok := GetUserFromContext(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
 		return
 	}
 
 	if err := h.service.AdvanceQueue(c.Request.Context(), userID, userName); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to advance queue",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to advance queue")
 		return
 	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
-		Message: "Queue advanced successfully",
+		Message: translate(c, "Queue advanced successfully"),
 	})
 }
 
@@ -252,8 +384,8 @@ func (h *QueueHandler) GetQueueStatistics(c *gin.Context) {
 		parsedDate, err := time.Parse("2006-01-02", dateStr)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Error:   "Invalid date format",
-				Message: "Use YYYY-MM-DD format",
+				Error:   translate(c, "Invalid date format"),
+				Message: translate(c, "Use YYYY-MM-DD format"),
 			})
 			return
 		}
@@ -262,33 +394,133 @@ func (h *QueueHandler) GetQueueStatistics(c *gin.Context) {
 
 	stats, err := h.service.GetQueueStatistics(c.Request.Context(), date)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to get statistics",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to get statistics")
 		return
 	}
 
 	c.JSON(http.StatusOK, stats)
 }
 
+// GetHourlyStatistics gets the statistics of each hour of a day, with wait and
+// preparation time percentiles (Staff only)
+// GET /api/queue/stats/hourly?date=YYYY-MM-DD
+func (h *QueueHandler) GetHourlyStatistics(c *gin.Context) {
+	var date *time.Time
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   translate(c, "Invalid date format"),
+				Message: translate(c, "Use YYYY-MM-DD format"),
+			})
+			return
+		}
+		date = &parsedDate
+	}
+
+	stats, err := h.service.GetHourlyStatistics(c.Request.Context(), date)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get hourly statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetLoadCurve gets the queue length and kitchen load sampled through a day,
+// with the day's peaks, for capacity planning (Staff only)
+// GET /api/queue/stats/load?date=YYYY-MM-DD
+func (h *QueueHandler) GetLoadCurve(c *gin.Context) {
+	var date *time.Time
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   translate(c, "Invalid date format"),
+				Message: translate(c, "Use YYYY-MM-DD format"),
+			})
+			return
+		}
+		date = &parsedDate
+	}
+
+	curve, err := h.service.GetLoadCurve(c.Request.Context(), date)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get load curve")
+		return
+	}
+
+	c.JSON(http.StatusOK, curve)
+}
+
+// GetKitchenLoad gets in-progress entries against the kitchen's capacity
+// GET /api/queue/load
+func (h *QueueHandler) GetKitchenLoad(c *gin.Context) {
+	load, err := h.service.GetKitchenLoad(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get kitchen load")
+		return
+	}
+
+	c.JSON(http.StatusOK, load)
+}
+
+// CompareLocationStatistics compares KPIs across locations (Staff only)
+// GET /api/queue/stats/compare?locations=a,b&date=YYYY-MM-DD
+func (h *QueueHandler) CompareLocationStatistics(c *gin.Context) {
+	var locationIDs []string
+	for _, id := range strings.Split(c.Query("locations"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			locationIDs = append(locationIDs, id)
+		}
+	}
+	if len(locationIDs) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   translate(c, "Invalid request"),
+			Message: translate(c, "locations query parameter is required"),
+		})
+		return
+	}
+
+	var date *time.Time
+	if dateStr := c.Query("date"); dateStr != "" {
+		parsedDate, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   translate(c, "Invalid date format"),
+				Message: translate(c, "Use YYYY-MM-DD format"),
+			})
+			return
+		}
+		date = &parsedDate
+	}
+
+	comparison, err := h.service.CompareLocations(c.Request.Context(), locationIDs, date)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to compare locations")
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
 // GetUserQueueEntries gets all queue entries for the authenticated user
 // GET /api/queue/user/me
 func (h *QueueHandler) GetUserQueueEntries(c *gin.Context) {
 	userID, _, _, ok := GetUserFromContext(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
 		return
 	}
 
 	entries, err := h.service.GetUserQueueEntries(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to get user queue entries",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to get user queue entries")
 		return
 	}
+	for i := range entries {
+		entries[i].LookupCode = services.LookupCode(&entries[i])
+	}
 
 	c.JSON(http.StatusOK, entries)
 }
@@ -298,10 +530,7 @@ func (h *QueueHandler) GetUserQueueEntries(c *gin.Context) {
 func (h *QueueHandler) GetActiveQueueEntries(c *gin.Context) {
 	entries, err := h.service.GetActiveQueueEntries(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to get active queue entries",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to get active queue entries")
 		return
 	}
 
@@ -326,10 +555,7 @@ func (h *QueueHandler) GetStaffActionLogs(c *gin.Context) {
 
 	logs, err := h.service.GetStaffActionLogs(c.Request.Context(), entryID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to get action logs",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to get action logs")
 		return
 	}
 
@@ -341,10 +567,7 @@ func (h *QueueHandler) GetStaffActionLogs(c *gin.Context) {
 func (h *QueueHandler) GetConfiguration(c *gin.Context) {
 	config, err := h.service.GetConfiguration(c.Request.Context())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to get configuration",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to get configuration")
 		return
 	}
 
@@ -356,29 +579,23 @@ func (h *QueueHandler) GetConfiguration(c *gin.Context) {
 func (h *QueueHandler) UpdateConfiguration(c *gin.Context) {
 	userID, _, _, ok := GetUserFromContext(c)
 	if !ok {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
 		return
 	}
 
 	var config models.QueueConfiguration
 	if err := c.ShouldBindJSON(&config); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request",
-			Message: err.Error(),
-		})
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
 		return
 	}
 
 	if err := h.service.UpdateConfiguration(c.Request.Context(), &config, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to update configuration",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to update configuration")
 		return
 	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
-		Message: "Configuration updated successfully",
+		Message: translate(c, "Configuration updated successfully"),
 		Data:    config,
 	})
 }
@@ -387,14 +604,33 @@ func (h *QueueHandler) UpdateConfiguration(c *gin.Context) {
 // POST /api/queue/recalculate
 func (h *QueueHandler) RecalculatePositions(c *gin.Context) {
 	if err := h.service.RecalculatePositions(c.Request.Context()); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to recalculate positions",
-			Message: err.Error(),
-		})
+		c.Error(err).SetMeta("Failed to recalculate positions")
 		return
 	}
 
 	c.JSON(http.StatusOK, models.SuccessResponse{
-		Message: "Positions recalculated successfully",
+		Message: translate(c, "Positions recalculated successfully"),
+	})
+}
+
+// RenotifyEntries re-sends notifications in bulk (Admin only)
+// POST /api/queue/admin/renotify
+func (h *QueueHandler) RenotifyEntries(c *gin.Context) {
+	// An empty body re-sends READY notifications with default batching
+	var req models.RenotifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	result, err := h.service.RenotifyEntries(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to renotify entries")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.SuccessResponse{
+		Message: translate(c, "Re-notification scheduled"),
+		Data:    result,
 	})
 }
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"gin-quickstart/middleware"
+	"gin-quickstart/models"
+	"gin-quickstart/realtime"
+	"gin-quickstart/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// wsPingInterval keeps idle connections alive through proxies
+	wsPingInterval = 30 * time.Second
+	wsWriteTimeout = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || middleware.AllowedOrigin(origin)
+	},
+}
+
+// realtimeFilter builds the client filter from ?token= (the token or its
+// lookup code; or else the request's location) and the caller's role, if
+// any. Token numbers are unique across locations, so following a token
+// needs no location.
+func (h *QueueHandler) realtimeFilter(c *gin.Context) (realtime.Filter, error) {
+	var filter realtime.Filter
+	if token := c.Query("token"); token != "" {
+		resolved, err := h.service.ResolveLookupCode(c.Request.Context(), token)
+		if err != nil {
+			return filter, err
+		}
+		filter.Token = resolved
+	} else {
+		filter.Location = services.LocationFromContext(c.Request.Context())
+	}
+	if role, ok := c.Get("user_role"); ok {
+		filter.Role, _ = role.(string)
+	}
+	return filter, nil
+}
+
+// StreamQueueUpdatesWS pushes queue updates and stats over a WebSocket (public;
+// staff and admins also receive personal details)
+// GET /api/queue/ws
+func (h *QueueHandler) StreamQueueUpdatesWS(c *gin.Context) {
+	hub := realtime.GetHub()
+	if hub == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: translate(c, "Real-time updates unavailable")})
+		return
+	}
+
+	filter, err := h.realtimeFilter(c)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get queue position")
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already responded
+		return
+	}
+	defer conn.Close()
+
+	client := hub.Register(filter)
+	defer hub.Unregister(client)
+
+	// Clients only send control frames; reading handles them and detects disconnects
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case msg := <-client.Messages():
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-client.Done():
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow"),
+				time.Now().Add(wsWriteTimeout))
+			return
+		case <-closed:
+			return
+		}
+	}
+}
+
+// StreamQueueUpdatesSSE pushes queue updates and stats as server-sent events
+// (public; staff and admins also receive personal details)
+// GET /api/queue/events
+func (h *QueueHandler) StreamQueueUpdatesSSE(c *gin.Context) {
+	hub := realtime.GetHub()
+	if hub == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: translate(c, "Real-time updates unavailable")})
+		return
+	}
+
+	filter, err := h.realtimeFilter(c)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get queue position")
+		return
+	}
+
+	client := hub.Register(filter)
+	defer hub.Unregister(client)
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case msg := <-client.Messages():
+			c.SSEvent(msg.Type, string(msg.Data))
+			return true
+		case <-ping.C:
+			c.SSEvent("ping", "")
+			return true
+		case <-client.Done():
+			return false
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReplayEvents replays a Kafka offset range through the queue handlers (Admin only)
+// POST /api/queue/admin/replay
+func (h *QueueHandler) ReplayEvents(c *gin.Context) {
+	var req models.ReplayEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	report, err := h.service.ReplayEvents(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to replay events")
+		return
+	}
+
+	message := "Dry run complete; no changes were made"
+	if req.Apply {
+		message = "Events replayed"
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, message),
+		Data:    report,
+	})
+}
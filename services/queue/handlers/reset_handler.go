@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"gin-quickstart/models"
+	"gin-quickstart/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResetQueue completes or expires the location's remaining entries (Admin only).
+// Without a matching confirmation_token it answers 428 with a preview of the
+// reset, whose confirmation_token confirms it on the next call.
+// POST /api/queue/admin/reset
+func (h *QueueHandler) ResetQueue(c *gin.Context) {
+	userID, userName, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.ResetQueueRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+			return
+		}
+	}
+
+	result, preview, err := h.service.ResetQueue(c.Request.Context(), &req, userID, userName)
+	// An unconfirmed reset answers with its preview, not an error
+	if errors.Is(err, services.ErrResetNotConfirmed) {
+		message := "Confirm the reset by sending it again with this confirmation_token"
+		if req.ConfirmationToken != "" {
+			message = "The queue changed since the preview; review it and confirm with the new confirmation_token"
+		}
+		c.JSON(http.StatusPreconditionRequired, models.SuccessResponse{
+			Message: translate(c, message),
+			Data:    preview,
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err).SetMeta("Failed to reset queue")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Queue reset successfully"),
+		Data:    result,
+	})
+}
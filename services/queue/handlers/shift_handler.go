@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClockIn starts a shift for the calling staff member at the request's location
+// POST /api/queue/shifts/clock-in
+func (h *QueueHandler) ClockIn(c *gin.Context) {
+	userID, userName, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.ClockInRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+			return
+		}
+	}
+
+	shift, err := h.service.ClockIn(c.Request.Context(), userID, userName, &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to clock in")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: translate(c, "Clocked in successfully"),
+		Data:    shift,
+	})
+}
+
+// ClockOut ends the calling staff member's shift
+// POST /api/queue/shifts/clock-out
+func (h *QueueHandler) ClockOut(c *gin.Context) {
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	shift, err := h.service.ClockOut(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to clock out")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Clocked out successfully"),
+		Data:    shift,
+	})
+}
+
+// GetActiveShifts lists the staff on duty at the request's location
+// GET /api/queue/shifts/active
+func (h *QueueHandler) GetActiveShifts(c *gin.Context) {
+	shifts, err := h.service.GetActiveShifts(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get active shifts")
+		return
+	}
+
+	c.JSON(http.StatusOK, shifts)
+}
+
+// GetShiftActionLogs lists the staff actions taken during a shift
+// GET /api/queue/shifts/:shiftId/logs
+func (h *QueueHandler) GetShiftActionLogs(c *gin.Context) {
+	logs, err := h.service.GetShiftActionLogs(c.Request.Context(), c.Param("shiftId"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get shift logs")
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
@@ -0,0 +1,19 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSLABreaches lists active entries that have waited past their SLA (Staff only)
+// GET /api/queue/sla/breached
+func (h *QueueHandler) GetSLABreaches(c *gin.Context) {
+	breaches, err := h.service.GetSLABreaches(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get SLA breaches")
+		return
+	}
+
+	c.JSON(http.StatusOK, breaches)
+}
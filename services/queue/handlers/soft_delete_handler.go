@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteConfiguration deletes the location's own configuration, falling back
+// to the default's (Admin only)
+// DELETE /api/queue/config
+func (h *QueueHandler) DeleteConfiguration(c *gin.Context) {
+	if err := h.service.DeleteConfiguration(c.Request.Context()); err != nil {
+		c.Error(err).SetMeta("Failed to delete configuration")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Configuration deleted successfully"),
+	})
+}
+
+// DeleteAnnouncement deletes a display announcement (Admin only)
+// DELETE /api/queue/admin/announcements/:announcementId
+func (h *QueueHandler) DeleteAnnouncement(c *gin.Context) {
+	if err := h.service.DeleteAnnouncement(c.Request.Context(), c.Param("announcementId")); err != nil {
+		c.Error(err).SetMeta("Failed to delete announcement")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Announcement deleted successfully"),
+	})
+}
+
+// GetDeletedRecords lists deleted entries, announcements or configurations
+// (Admin only)
+// GET /api/queue/admin/deleted/:kind
+func (h *QueueHandler) GetDeletedRecords(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var records interface{}
+	var err error
+	switch c.Param("kind") {
+	case "entries":
+		records, err = h.service.GetDeletedQueueEntries(ctx)
+	case "announcements":
+		records, err = h.service.GetDeletedAnnouncements(ctx)
+	case "configurations":
+		records, err = h.service.GetDeletedConfigurations(ctx)
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   translate(c, "Invalid kind"),
+			Message: translate(c, "Use entries, announcements or configurations"),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get deleted records")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Deleted records retrieved successfully"),
+		Data:    records,
+	})
+}
+
+// RestoreDeletedRecord brings back a deleted entry, announcement or
+// configuration (Admin only)
+// POST /api/queue/admin/deleted/:kind/:recordId/restore
+func (h *QueueHandler) RestoreDeletedRecord(c *gin.Context) {
+	ctx := c.Request.Context()
+	recordID := c.Param("recordId")
+
+	var record interface{}
+	var err error
+	switch c.Param("kind") {
+	case "entries":
+		record, err = h.service.RestoreQueueEntry(ctx, recordID)
+	case "announcements":
+		record, err = h.service.RestoreAnnouncement(ctx, recordID)
+	case "configurations":
+		record, err = h.service.RestoreConfiguration(ctx, recordID)
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   translate(c, "Invalid kind"),
+			Message: translate(c, "Use entries, announcements or configurations"),
+		})
+		return
+	}
+	if err != nil {
+		c.Error(err).SetMeta("Failed to restore record")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Record restored successfully"),
+		Data:    record,
+	})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefineStages splits a queue entry into staged pickups (Staff only)
+// POST /api/queue/:id/stages
+func (h *QueueHandler) DefineStages(c *gin.Context) {
+	entryID := c.Param("id")
+
+	var req models.DefineQueueStagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	stages, err := h.service.DefineStages(c.Request.Context(), entryID, req.Stages)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to define stages")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: translate(c, "Stages defined successfully"),
+		Data:    stages,
+	})
+}
+
+// GetEntryStages gets the stages of a queue entry (Staff only)
+// GET /api/queue/:id/stages
+func (h *QueueHandler) GetEntryStages(c *gin.Context) {
+	entryID := c.Param("id")
+
+	stages, err := h.service.GetEntryStages(c.Request.Context(), entryID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get stages")
+		return
+	}
+
+	c.JSON(http.StatusOK, stages)
+}
+
+// GetStagesByToken gets the stages of a queue entry by token (public)
+// GET /api/queue/token/:token/stages
+func (h *QueueHandler) GetStagesByToken(c *gin.Context) {
+	token, err := h.service.ResolveLookupCode(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get stages")
+		return
+	}
+
+	stages, err := h.service.GetStagesByToken(c.Request.Context(), token)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get stages")
+		return
+	}
+
+	c.JSON(http.StatusOK, stages)
+}
+
+// UpdateStageStatus updates the status of one stage (Staff only)
+// PATCH /api/queue/:id/stages/:stageId/status
+func (h *QueueHandler) UpdateStageStatus(c *gin.Context) {
+	entryID := c.Param("id")
+	stageID := c.Param("stageId")
+	userID, userName, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.UpdateQueueStageStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	stage, err := h.service.UpdateStageStatus(c.Request.Context(), entryID, stageID, &req, userID, userName)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to update stage status")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Stage status updated successfully"),
+		Data:    stage,
+	})
+}
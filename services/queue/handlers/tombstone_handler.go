@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteQueueEntry soft-deletes a queue entry (Admin only)
+// DELETE /api/queue/:id
+func (h *QueueHandler) DeleteQueueEntry(c *gin.Context) {
+	h.removeQueueEntry(c, h.service.DeleteQueueEntry, "Queue entry deleted successfully")
+}
+
+// AnonymizeQueueEntry strips personal data from a queue entry (Admin only)
+// POST /api/queue/:id/anonymize
+func (h *QueueHandler) AnonymizeQueueEntry(c *gin.Context) {
+	h.removeQueueEntry(c, h.service.AnonymizeQueueEntry, "Queue entry anonymized successfully")
+}
+
+func (h *QueueHandler) removeQueueEntry(
+	c *gin.Context,
+	remove func(ctx context.Context, entryID string, reason *string, performedBy string) (*models.QueueEntryTombstone, error),
+	message string,
+) {
+	entryID := c.Param("id")
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.RemoveQueueEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	tombstone, err := remove(c.Request.Context(), entryID, req.Reason, userID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to remove queue entry")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, message),
+		Data:    tombstone,
+	})
+}
+
+// GetTombstones lists tombstones for sync consumers (Admin only)
+// GET /api/queue/admin/tombstones?since=RFC3339
+func (h *QueueHandler) GetTombstones(c *gin.Context) {
+	var since *time.Time
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   translate(c, "Invalid since format"),
+				Message: translate(c, "Use RFC3339 format"),
+			})
+			return
+		}
+		since = &parsed
+	}
+
+	tombstones, err := h.service.GetTombstones(c.Request.Context(), since)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to get tombstones")
+		return
+	}
+
+	c.JSON(http.StatusOK, tombstones)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RegisterValidators adds the enum binding tags of models.EnumValidators
+// (queue_status, queue_priority, ...) to Gin's validator, and names fields
+// in validation errors by their JSON names
+func RegisterValidators() error {
+	validate, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return fmt.Errorf("unexpected binding validator %T", binding.Validator.Engine())
+	}
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+
+	for tag, values := range models.EnumValidators {
+		if err := validate.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			return slices.Contains(values, fl.Field().String())
+		}); err != nil {
+			return fmt.Errorf("failed to register validator %s: %w", tag, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"gin-quickstart/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListWebhooks lists outbound webhook subscriptions (Admin only)
+// GET /api/queue/admin/webhooks
+func (h *QueueHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.service.ListWebhooks(c.Request.Context())
+	if err != nil {
+		c.Error(err).SetMeta("Failed to list webhooks")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// CreateWebhook subscribes a URL to queue events (Admin only). The response
+// carries the signing secret, which is not shown again.
+// POST /api/queue/admin/webhooks
+func (h *QueueHandler) CreateWebhook(c *gin.Context) {
+	userID, _, _, ok := GetUserFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: translate(c, "Unauthorized")})
+		return
+	}
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	webhook, err := h.service.CreateWebhook(c.Request.Context(), &req, userID)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.SuccessResponse{
+		Message: translate(c, "Webhook created successfully"),
+		Data:    webhook,
+	})
+}
+
+// UpdateWebhook changes a webhook subscription (Admin only)
+// PUT /api/queue/admin/webhooks/:webhookId
+func (h *QueueHandler) UpdateWebhook(c *gin.Context) {
+	var req models.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		return
+	}
+
+	webhook, err := h.service.UpdateWebhook(c.Request.Context(), c.Param("webhookId"), &req)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to update webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Webhook updated successfully"),
+		Data:    webhook,
+	})
+}
+
+// DeleteWebhook removes a webhook subscription and its delivery log (Admin only)
+// DELETE /api/queue/admin/webhooks/:webhookId
+func (h *QueueHandler) DeleteWebhook(c *gin.Context) {
+	if err := h.service.DeleteWebhook(c.Request.Context(), c.Param("webhookId")); err != nil {
+		c.Error(err).SetMeta("Failed to delete webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{
+		Message: translate(c, "Webhook deleted successfully"),
+	})
+}
+
+// ListWebhookDeliveries lists the delivery log of a webhook subscription (Admin only)
+// GET /api/queue/admin/webhooks/:webhookId/deliveries?status=FAILED&limit=50&offset=0
+func (h *QueueHandler) ListWebhookDeliveries(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	result, err := h.service.ListWebhookDeliveries(c.Request.Context(), c.Param("webhookId"), c.Query("status"), limit, offset)
+	if err != nil {
+		c.Error(err).SetMeta("Failed to list webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RetryWebhookDelivery sends a webhook delivery again (Admin only)
+// POST /api/queue/admin/webhooks/deliveries/:deliveryId/retry
+func (h *QueueHandler) RetryWebhookDelivery(c *gin.Context) {
+	delivery, err := h.service.RetryWebhookDelivery(c.Request.Context(), c.Param("deliveryId"))
+	if err != nil {
+		c.Error(err).SetMeta("Failed to retry webhook delivery")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, models.SuccessResponse{
+		Message: translate(c, "Webhook delivery queued"),
+		Data:    delivery,
+	})
+}
@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Status values reported by checks
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+	StatusFailing  = "failing"
+)
+
+const defaultCheckTimeout = 2 * time.Second
+
+// CheckFunc verifies a single dependency
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+}
+
+// DependencyStatus is the result of one dependency check
+type DependencyStatus struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the aggregated readiness result
+type Report struct {
+	Status       string                      `json:"status"`
+	Service      string                      `json:"service"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+}
+
+// Ready reports whether the instance should receive traffic
+func (r *Report) Ready() bool {
+	return r.Status != StatusFailing
+}
+
+var (
+	mu     sync.RWMutex
+	checks []check
+)
+
+// Register adds a dependency check. A failing critical check marks the
+// instance as not ready; a failing non-critical check only degrades it.
+func Register(name string, critical bool, fn CheckFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	checks = append(checks, check{name: name, critical: critical, fn: fn})
+}
+
+// Check runs all registered checks concurrently
+func Check(ctx context.Context, service string) *Report {
+	mu.RLock()
+	registered := make([]check, len(checks))
+	copy(registered, checks)
+	mu.RUnlock()
+
+	report := &Report{
+		Status:       StatusOK,
+		Service:      service,
+		Dependencies: make(map[string]DependencyStatus, len(registered)),
+	}
+
+	var (
+		wg      sync.WaitGroup
+		resultM sync.Mutex
+	)
+	for _, c := range registered {
+		wg.Add(1)
+		go func(c check) {
+			defer wg.Done()
+			result := run(ctx, c)
+
+			resultM.Lock()
+			defer resultM.Unlock()
+			report.Dependencies[c.name] = result
+			if result.Status == StatusDown {
+				if c.critical {
+					report.Status = StatusFailing
+				} else if report.Status == StatusOK {
+					report.Status = StatusDegraded
+				}
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func run(ctx context.Context, c check) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, defaultCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- c.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := DependencyStatus{
+		Status:    StatusUp,
+		Critical:  c.critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+// Package i18n translates response messages and error codes into the
+// customer's language. Messages are written in English in the code and
+// looked up by that text (or, for error codes, by the code) in the catalogs
+// under locales/; a message missing from a catalog stays in English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SourceLanguage is the language messages are written in
+const SourceLanguage = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs maps a language to its translations, keyed by English message or
+// error code
+var catalogs = map[string]map[string]string{SourceLanguage: {}}
+
+var defaultLanguage = SourceLanguage
+
+func init() {
+	files, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	for _, file := range files {
+		data, err := localeFiles.ReadFile("locales/" + file.Name())
+		if err != nil {
+			panic(err)
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("invalid message catalog %s: %v", file.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(file.Name(), path.Ext(file.Name()))] = messages
+	}
+}
+
+// Languages lists the supported languages
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Match returns the supported language of a language tag ("es-MX" is "es")
+func Match(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if _, ok := catalogs[tag]; ok {
+		return tag, true
+	}
+	base, _, _ := strings.Cut(tag, "-")
+	if _, ok := catalogs[base]; ok {
+		return base, true
+	}
+	return "", false
+}
+
+// SetDefaultLanguage sets the language of requests that don't ask for a
+// supported one; unsupported languages are ignored
+func SetDefaultLanguage(language string) {
+	if matched, ok := Match(language); ok {
+		defaultLanguage = matched
+	}
+}
+
+// DefaultLanguage returns the language of requests that don't ask for a supported one
+func DefaultLanguage() string {
+	return defaultLanguage
+}
+
+// Negotiate picks the supported language the Accept-Language header prefers
+// most, or the default language
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := defaultLanguage, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		language, ok := Match(tag)
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = language, q
+		}
+	}
+	return best
+}
+
+// Lookup returns the translation of message (or error code) into language
+func Lookup(language, message string) (string, bool) {
+	translated, ok := catalogs[language][message]
+	return translated, ok
+}
+
+// Translate returns message in language, or as written when it has no translation
+func Translate(language, message string) string {
+	if translated, ok := Lookup(language, message); ok {
+		return translated
+	}
+	return message
+}
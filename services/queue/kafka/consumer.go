@@ -3,41 +3,71 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"gin-quickstart/auth"
 	"gin-quickstart/config"
+	"gin-quickstart/metrics"
 	"gin-quickstart/models"
+	eventspb "gin-quickstart/proto/events"
 	"gin-quickstart/services"
+	"gin-quickstart/tracing"
 
 	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
 )
 
 type KafkaConsumer struct {
 	consumer      sarama.ConsumerGroup
+	dlqProducer   sarama.SyncProducer
+	publisher     EntryEventPublisher
+	prepTimes     PrepTimeStore
+	tiers         CustomerTierSource
+	encoding      string
 	queueService  *services.QueueService
-	topics        []string
+	brokers       []string
+	retry         retryPolicy
+	pause         pauseState
+	topics        Topics
 	ready         chan bool
 	ctx           context.Context
 	cancel        context.CancelFunc
 }
 
+// EntryEventPublisher publishes events for entries the consumer creates.
+// It is satisfied by the shared KafkaProducer.
+type EntryEventPublisher interface {
+	PublishQueueEntryCreated(ctx context.Context, entry *models.QueueEntry) error
+}
+
 // OrderCreatedEvent represents order creation event from Order Service
 type OrderCreatedEvent struct {
 	OrderID     string    `json:"order_id"`
 	UserID      string    `json:"user_id"`
+	LocationID  string    `json:"location_id,omitempty"`
 	UserName    string    `json:"user_name"`
 	UserPhone   string    `json:"user_phone"`
 	Items       []OrderItem `json:"items"`
 	TotalAmount float64   `json:"total_amount"`
 	Priority    string    `json:"priority,omitempty"`
 	IsExpress   bool      `json:"is_express,omitempty"`
+	// PaymentStatus is PENDING for unpaid orders; older producers omit it
+	PaymentStatus string    `json:"payment_status,omitempty"`
+	// RequestedPickupTime is set for orders to be picked up later (JSON only)
+	RequestedPickupTime *time.Time `json:"requested_pickup_time,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
 type OrderItem struct {
 	MenuItemID string  `json:"menu_item_id"`
+	Name       string  `json:"name,omitempty"`
 	Quantity   int     `json:"quantity"`
 	Price      float64 `json:"price"`
 }
@@ -49,7 +79,61 @@ type OrderStatusEvent struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-func NewKafkaConsumer(cfg *config.Config, queueService *services.QueueService) (*KafkaConsumer, error) {
+// PaymentCompletedEvent represents payment completed event from Payment Service
+type PaymentCompletedEvent struct {
+	OrderID     string    `json:"order_id"`
+	PaymentID   string    `json:"payment_id"`
+	Amount      float64   `json:"amount"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// MenuUpdatedEvent represents menu item update event from Menu Service
+type MenuUpdatedEvent struct {
+	MenuItemID      string    `json:"menu_item_id"`
+	Name            string    `json:"name,omitempty"`
+	PreparationTime *int      `json:"preparation_time,omitempty"` // minutes
+	IsAvailable     bool      `json:"is_available"`
+	Deleted         bool      `json:"deleted,omitempty"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TokenRevokedEvent represents a token revocation from the Auth Service. A
+// jti revokes one token; a user ID alone revokes every token of that user
+// issued before RevokedAt. The auth service publishes it as JSON only.
+type TokenRevokedEvent struct {
+	JTI       string    `json:"jti,omitempty"`
+	UserID    string    `json:"user_id,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Reason    string    `json:"reason,omitempty"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// PrepTimeStore is the local per-item preparation time cache refreshed by
+// menu.updated events
+type PrepTimeStore interface {
+	UpdatePreparationTime(ctx context.Context, itemID string, minutes int)
+	InvalidatePreparationTime(ctx context.Context, itemID string)
+}
+
+// CustomerTierSource looks customer tiers up in the user service, for
+// queueing customers of a VIP tier as VIP
+type CustomerTierSource interface {
+	GetCustomerTier(ctx context.Context, userID string) (string, error)
+	IsVIPTier(tier string) bool
+}
+
+// OrderCancelledEvent represents order cancelled event from Order Service
+type OrderCancelledEvent struct {
+	OrderID     string    `json:"order_id"`
+	Reason      string    `json:"reason"`
+	CancelledAt time.Time `json:"cancelled_at"`
+}
+
+// NewKafkaConsumer creates the order event consumer. publisher may be nil, in
+// which case queue entries are still created but not announced; prepTimes may
+// be nil, in which case menu updates are ignored; tiers may be nil, in which
+// case orders keep the priority they ask for.
+func NewKafkaConsumer(cfg *config.Config, queueService *services.QueueService, publisher EntryEventPublisher, prepTimes PrepTimeStore, tiers CustomerTierSource) (*KafkaConsumer, error) {
 	config := sarama.NewConfig()
 	config.Version = sarama.V3_0_0_0
 	config.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
@@ -64,10 +148,32 @@ func NewKafkaConsumer(cfg *config.Config, queueService *services.QueueService) (
 		return nil, fmt.Errorf("failed to create consumer group: %w", err)
 	}
 
+	dlqConfig := sarama.NewConfig()
+	dlqConfig.Producer.RequiredAcks = sarama.WaitForAll
+	dlqConfig.Producer.Return.Successes = true
+
+	dlqProducer, err := sarama.NewSyncProducer(cfg.KafkaBrokers, dlqConfig)
+	if err != nil {
+		cancel()
+		consumer.Close()
+		return nil, fmt.Errorf("failed to create dead letter producer: %w", err)
+	}
+
 	return &KafkaConsumer{
 		consumer:     consumer,
+		dlqProducer:  dlqProducer,
+		publisher:    publisher,
+		prepTimes:    prepTimes,
+		tiers:        tiers,
+		encoding:     strings.ToLower(cfg.KafkaConsumerEncoding),
 		queueService: queueService,
-		topics:       []string{"order.created", "order.status.changed"},
+		brokers:      cfg.KafkaBrokers,
+		retry: retryPolicy{
+			maxRetries:     cfg.KafkaConsumerMaxRetries,
+			initialBackoff: time.Duration(cfg.KafkaConsumerRetryBackoffMs) * time.Millisecond,
+			maxBackoff:     time.Duration(cfg.KafkaConsumerMaxBackoffMs) * time.Millisecond,
+		},
+		topics:       TopicsFromConfig(cfg),
 		ready:        make(chan bool),
 		ctx:          ctx,
 		cancel:       cancel,
@@ -81,7 +187,7 @@ func (kc *KafkaConsumer) Start() error {
 			case <-kc.ctx.Done():
 				return
 			default:
-				if err := kc.consumer.Consume(kc.ctx, kc.topics, kc); err != nil {
+				if err := kc.consumer.Consume(kc.ctx, kc.topics.Consumed(), kc); err != nil {
 					log.Printf("Error from consumer: %v", err)
 					time.Sleep(5 * time.Second) // Backoff before retry
 				}
@@ -98,6 +204,9 @@ func (kc *KafkaConsumer) Start() error {
 
 func (kc *KafkaConsumer) Stop() error {
 	kc.cancel()
+	if err := kc.dlqProducer.Close(); err != nil {
+		log.Printf("Error closing dead letter producer: %v", err)
+	}
 	return kc.consumer.Close()
 }
 
@@ -114,6 +223,8 @@ func (kc *KafkaConsumer) Cleanup(sarama.ConsumerGroupSession) error {
 
 // ConsumeClaim must start a consumer loop of ConsumerGroupClaim's Messages()
 func (kc *KafkaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	kc.pauseClaimIfPaused(claim)
+
 	for {
 		select {
 		case message := <-claim.Messages():
@@ -121,12 +232,24 @@ func (kc *KafkaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim
 				return nil
 			}
 
+			// Hold buffered messages while an admin has consumption paused
+			if !kc.waitWhilePaused(session.Context()) {
+				return nil
+			}
+
 			log.Printf("Message received: topic=%s, partition=%d, offset=%d", 
 				message.Topic, message.Partition, message.Offset)
 
-			if err := kc.handleMessage(message); err != nil {
-				log.Printf("Error handling message: %v", err)
-				// Continue processing other messages even if one fails
+			attempts, err := kc.handleWithRetry(session.Context(), message)
+			if errors.Is(err, errSessionEnded) {
+				log.Printf("Leaving message unmarked after %d attempt(s): %v", attempts, err)
+				return nil
+			}
+			metrics.KafkaMessagesConsumed.WithLabelValues(message.Topic, metrics.ResultLabel(err)).Inc()
+			if err != nil {
+				log.Printf("Error handling message after %d attempt(s): %v", attempts, err)
+				// Park the message on the DLQ and continue with the rest of the partition
+				kc.sendToDeadLetter(message, err, attempts)
 			}
 
 			session.MarkMessage(message, "")
@@ -138,34 +261,70 @@ func (kc *KafkaConsumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim
 }
 
 func (kc *KafkaConsumer) handleMessage(message *sarama.ConsumerMessage) error {
-	ctx := context.Background()
-
-	switch message.Topic {
-	case "order.created":
-		return kc.handleOrderCreated(ctx, message.Value)
-	case "order.status.changed":
-		return kc.handleOrderStatusChanged(ctx, message.Value)
-	default:
-		log.Printf("Unknown topic: %s", message.Topic)
+	// Continue the producer's trace if the message carries one
+	ctx, span := tracing.StartConsumerSpan(context.Background(), message)
+	defer span.End()
+
+	// Carry the upstream request ID into anything this message produces
+	headers := headersFromMessage(message)
+	ctx = tracing.WithRequestID(ctx, headers.RequestID)
+	span.SetAttributes(
+		attribute.String("messaging.event.type", headers.EventType),
+		attribute.String("messaging.event.version", headers.EventVersion),
+		attribute.String("request.id", headers.RequestID),
+	)
+	log.Printf("Handling message: topic=%s, event_type=%s, event_version=%s, source=%s, request_id=%s, trace_id=%s",
+		message.Topic, headers.EventType, headers.EventVersion, headers.Source, headers.RequestID, headers.TraceID)
+
+	// Skip messages that were already handled (re-delivery after a rebalance or retry)
+	key := eventKey(message)
+	claimed, err := kc.queueService.ClaimEvent(ctx, key, message.Topic, message.Partition, message.Offset)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("failed to claim event %s: %w", key, err)
+	}
+	if !claimed {
+		metrics.DuplicateSuppressionsTotal.WithLabelValues(message.Topic, metrics.DuplicateProcessedEvent).Inc()
+		log.Printf("Skipping already processed message: key=%s", key)
 		return nil
 	}
+
+	err = kc.dispatch(ctx, message)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		// Let a retry or re-drive process the message again
+		if releaseErr := kc.queueService.ReleaseEvent(ctx, key); releaseErr != nil {
+			log.Printf("Failed to release event %s: %v", key, releaseErr)
+		}
+	}
+	return err
+}
+
+// dispatch routes a message to the handler for its event type and version
+func (kc *KafkaConsumer) dispatch(ctx context.Context, message *sarama.ConsumerMessage) error {
+	event, err := kc.unwrap(message)
+	if err != nil {
+		return err
+	}
+
+	handler, ok := inboundHandlers[inboundKey{event.Type, event.Version}]
+	if !ok {
+		return fmt.Errorf("%w: %s v%d", errUnsupportedEvent, event.Type, event.Version)
+	}
+	return handler(kc, ctx, event.Payload)
 }
 
 func (kc *KafkaConsumer) handleOrderCreated(ctx context.Context, data []byte) error {
-	var event OrderCreatedEvent
-	if err := json.Unmarshal(data, &event); err != nil {
+	event, err := kc.decodeOrderCreated(data)
+	if err != nil {
 		return fmt.Errorf("failed to unmarshal order created event: %w", err)
 	}
 
 	log.Printf("Processing order created event: order_id=%s, user_id=%s", event.OrderID, event.UserID)
 
-	// Check if queue entry already exists
-	existing, _ := kc.queueService.GetQueueEntryByOrderID(ctx, event.OrderID)
-	if existing != nil {
-		log.Printf("Queue entry already exists for order %s", event.OrderID)
-		return nil
-	}
-
 	// Determine priority based on order
 	priority := event.Priority
 	if priority == "" {
@@ -175,8 +334,14 @@ func (kc *KafkaConsumer) handleOrderCreated(ctx context.Context, data []byte) er
 	// Determine if express queue
 	isExpress := event.IsExpress
 	itemCount := 0
+	items := make([]models.CreateQueueItemRequest, 0, len(event.Items))
 	for _, item := range event.Items {
 		itemCount += item.Quantity
+		items = append(items, models.CreateQueueItemRequest{
+			MenuItemID: item.MenuItemID,
+			ItemName:   item.Name,
+			Quantity:   item.Quantity,
+		})
 	}
 
 	// Auto-qualify for express if <= 3 items
@@ -185,40 +350,107 @@ func (kc *KafkaConsumer) handleOrderCreated(ctx context.Context, data []byte) er
 		priority = "HIGH"
 	}
 
+	// Customers of a VIP tier are served as VIP whatever the order asks for
+	elevatedFrom, tier := "", ""
+	if priority != "VIP" {
+		if tier = kc.vipTier(ctx, event.UserID); tier != "" {
+			elevatedFrom, priority = priority, "VIP"
+		}
+	}
+
 	// Create queue entry
 	req := &models.CreateQueueEntryRequest{
-		OrderID:        event.OrderID,
-		UserID:         event.UserID,
-		UserName:       event.UserName,
-		UserPhone:      event.UserPhone,
-		TokenType:      determineTokenType(itemCount, isExpress),
-		Priority:       priority,
-		IsExpressQueue: isExpress,
-		ItemCount:      itemCount,
+		OrderID:         event.OrderID,
+		UserID:          event.UserID,
+		LocationID:      event.LocationID,
+		UserName:        event.UserName,
+		UserPhone:       event.UserPhone,
+		TokenType:       determineTokenType(itemCount, isExpress),
+		Priority:        priority,
+		IsExpressQueue:  isExpress,
+		ItemCount:       itemCount,
+		Items:           items,
+		// Unpaid orders are held out of the active queue until payment.completed
+		AwaitingPayment: strings.EqualFold(event.PaymentStatus, "PENDING"),
+		RequestedPickupTime: event.RequestedPickupTime,
 	}
 
-	entry, err := kc.queueService.CreateQueueEntry(ctx, req)
+	// Duplicate bursts (two partitions, fast retries) resolve to the existing entry
+	entry, created, err := kc.queueService.EnqueueOrder(ctx, req)
 	if err != nil {
 		return fmt.Errorf("failed to create queue entry: %w", err)
 	}
+	if !created {
+		metrics.DuplicateSuppressionsTotal.WithLabelValues(kc.topics.OrderCreated, metrics.DuplicateAlreadyQueued).Inc()
+		log.Printf("Order %s already queued as token=%s", event.OrderID, entry.TokenNumber)
+		return nil
+	}
 
 	log.Printf("Queue entry created: token=%s, position=%d, estimated_wait=%d mins",
 		entry.TokenNumber, entry.Position, entry.EstimatedWaitTime)
 
+	// Audit why the entry jumped ahead of its requested priority
+	if elevatedFrom != "" {
+		reason := fmt.Sprintf("Customer tier %s (user service)", tier)
+		if err := kc.queueService.LogStaffAction(ctx, entry.ID, "system", "System", "ADJUST_PRIORITY", nil, nil, &elevatedFrom, &entry.Priority, &reason); err != nil {
+			log.Printf("Failed to audit VIP priority of %s: %v", entry.TokenNumber, err)
+		}
+	}
+
 	// Publish queue entry created event
-	go kc.publishQueueEntryCreated(entry)
+	go kc.publishQueueEntryCreated(ctx, entry)
 
 	return nil
 }
 
+// vipTier returns the customer's tier when it is served as VIP. Lookup
+// failures leave the order's priority alone rather than hold it up.
+func (kc *KafkaConsumer) vipTier(ctx context.Context, userID string) string {
+	if kc.tiers == nil || userID == "" {
+		return ""
+	}
+
+	tier, err := kc.tiers.GetCustomerTier(ctx, userID)
+	if err != nil {
+		log.Printf("Failed to look up customer tier of user %s: %v", userID, err)
+		return ""
+	}
+	if !kc.tiers.IsVIPTier(tier) {
+		return ""
+	}
+	return tier
+}
+
 func (kc *KafkaConsumer) handleOrderStatusChanged(ctx context.Context, data []byte) error {
-	var event OrderStatusEvent
-	if err := json.Unmarshal(data, &event); err != nil {
+	event, err := kc.decodeOrderStatus(data)
+	if err != nil {
 		return fmt.Errorf("failed to unmarshal order status event: %w", err)
 	}
 
 	log.Printf("Processing order status changed: order_id=%s, status=%s", event.OrderID, event.Status)
 
+	// Map order status to queue status
+	queueStatus := mapOrderStatusToQueueStatus(event.Status)
+	if queueStatus == "" {
+		log.Printf("No queue status mapping for order status: %s", event.Status)
+		return nil
+	}
+
+	// Orders sharing a party token roll up to it
+	if link, err := kc.queueService.GetOrderLink(ctx, event.OrderID); err == nil {
+		linkReq := &models.UpdateLinkedOrderStatusRequest{Status: queueStatus}
+		_, err := kc.queueService.UpdateLinkedOrderStatus(ctx, link.QueueEntryID, link.OrderID, linkReq, "system", "System")
+		if errors.Is(err, services.ErrInvalidOrderStatus) {
+			log.Printf("Ignoring order status for party token=%s: %v", link.TokenNumber, err)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update linked order status: %w", err)
+		}
+		log.Printf("Linked order status updated: token=%s, order_id=%s, status=%s", link.TokenNumber, link.OrderID, queueStatus)
+		return nil
+	}
+
 	// Get queue entry for order
 	entry, err := kc.queueService.GetQueueEntryByOrderID(ctx, event.OrderID)
 	if err != nil {
@@ -226,19 +458,24 @@ func (kc *KafkaConsumer) handleOrderStatusChanged(ctx context.Context, data []by
 		return nil
 	}
 
-	// Map order status to queue status
-	queueStatus := mapOrderStatusToQueueStatus(event.Status)
-	if queueStatus == "" {
-		log.Printf("No queue status mapping for order status: %s", event.Status)
+	// Scheduled entries join the queue when their preparation is due
+	if entry.Status == "SCHEDULED" && queueStatus == "WAITING" {
+		log.Printf("Queue entry %s is scheduled; it joins the queue at %v", entry.TokenNumber, entry.ScheduledActivateAt)
 		return nil
 	}
 
-	// Update queue status
+	// Update queue status; a kitchen at capacity starts the entry once there is room
 	req := &models.UpdateQueueStatusRequest{
-		Status: queueStatus,
+		Status:      queueStatus,
+		DeferIfFull: true,
 	}
 
-	if err := kc.queueService.UpdateQueueStatus(ctx, entry.ID, req, "system", "System"); err != nil {
+	err = kc.queueService.UpdateQueueStatus(ctx, entry.ID, req, "system", "System")
+	if errors.Is(err, services.ErrInvalidTransition) {
+		log.Printf("Ignoring order status for token=%s: %v", entry.TokenNumber, err)
+		return nil
+	}
+	if err != nil {
 		return fmt.Errorf("failed to update queue status: %w", err)
 	}
 
@@ -247,40 +484,202 @@ func (kc *KafkaConsumer) handleOrderStatusChanged(ctx context.Context, data []by
 	return nil
 }
 
-func (kc *KafkaConsumer) publishQueueEntryCreated(entry *models.QueueEntry) {
-	// Publish to notification service via Kafka
-	event := map[string]interface{}{
-		"event_type":          "queue.entry.created",
-		"queue_entry_id":      entry.ID,
-		"order_id":            entry.OrderID,
-		"user_id":             entry.UserID,
-		"token_number":        entry.TokenNumber,
-		"position":            entry.Position,
-		"estimated_wait_time": entry.EstimatedWaitTime,
-		"estimated_ready_time": entry.EstimatedReadyTime,
-		"created_at":          entry.CreatedAt,
+func (kc *KafkaConsumer) handleOrderCancelled(ctx context.Context, data []byte) error {
+	event, err := kc.decodeOrderCancelled(data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal order cancelled event: %w", err)
 	}
 
-	data, _ := json.Marshal(event)
-	
-	// Send to Kafka topic for notifications
-	producer, err := sarama.NewSyncProducer([]string{"kafka:9092"}, nil)
+	log.Printf("Processing order cancelled: order_id=%s, reason=%s", event.OrderID, event.Reason)
+
+	var reason *string
+	if event.Reason != "" {
+		reason = &event.Reason
+	}
+
+	entry, cancelled, err := kc.queueService.CancelOrderEntry(ctx, event.OrderID, reason)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Queue entry not found for order %s", event.OrderID)
+		return nil
+	}
 	if err != nil {
-		log.Printf("Failed to create producer: %v", err)
-		return
+		return fmt.Errorf("failed to cancel queue entry: %w", err)
 	}
-	defer producer.Close()
+	if !cancelled {
+		log.Printf("Queue entry %s already %s; ignoring cancellation", entry.TokenNumber, entry.Status)
+		return nil
+	}
+
+	log.Printf("Queue entry cancelled: token=%s", entry.TokenNumber)
+
+	return nil
+}
 
-	msg := &sarama.ProducerMessage{
-		Topic: "queue.events",
-		Value: sarama.ByteEncoder(data),
+func (kc *KafkaConsumer) handlePaymentCompleted(ctx context.Context, data []byte) error {
+	event, err := kc.decodePaymentCompleted(data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal payment completed event: %w", err)
 	}
 
-	if _, _, err := producer.SendMessage(msg); err != nil {
+	log.Printf("Processing payment completed: order_id=%s, payment_id=%s", event.OrderID, event.PaymentID)
+
+	entry, activated, err := kc.queueService.ActivatePaidEntry(ctx, event.OrderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Payment overtook order.created; retry until the entry exists
+		return fmt.Errorf("%w: order %s", errOrderNotQueued, event.OrderID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to activate queue entry: %w", err)
+	}
+	if !activated {
+		log.Printf("Queue entry %s is %s; ignoring payment", entry.TokenNumber, entry.Status)
+		return nil
+	}
+
+	log.Printf("Queue entry activated: token=%s, position=%d", entry.TokenNumber, entry.Position)
+
+	return nil
+}
+
+func (kc *KafkaConsumer) handleMenuUpdated(ctx context.Context, data []byte) error {
+	event, err := kc.decodeMenuUpdated(data)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal menu updated event: %w", err)
+	}
+
+	if kc.prepTimes == nil {
+		log.Printf("No prep-time cache configured; skipping menu update for item %s", event.MenuItemID)
+		return nil
+	}
+
+	// Without a new time, drop the item so the next lookup fetches it
+	if event.Deleted || event.PreparationTime == nil {
+		kc.prepTimes.InvalidatePreparationTime(ctx, event.MenuItemID)
+		log.Printf("Prep-time cache invalidated: menu_item_id=%s", event.MenuItemID)
+		return nil
+	}
+
+	kc.prepTimes.UpdatePreparationTime(ctx, event.MenuItemID, *event.PreparationTime)
+	log.Printf("Prep-time cache updated: menu_item_id=%s, preparation_time=%d", event.MenuItemID, *event.PreparationTime)
+
+	return nil
+}
+
+func (kc *KafkaConsumer) handleTokenRevoked(ctx context.Context, data []byte) error {
+	var event TokenRevokedEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal token revoked event: %w", err)
+	}
+
+	switch {
+	case event.JTI != "":
+		if err := auth.RevokeToken(ctx, event.JTI, event.ExpiresAt); err != nil {
+			return fmt.Errorf("failed to revoke token: %w", err)
+		}
+		log.Printf("Token revoked: jti=%s, user_id=%s, reason=%s", event.JTI, event.UserID, event.Reason)
+	case event.UserID != "":
+		revokedAt := event.RevokedAt
+		if revokedAt.IsZero() {
+			revokedAt = time.Now()
+		}
+		if err := auth.RevokeUserTokens(ctx, event.UserID, revokedAt); err != nil {
+			return fmt.Errorf("failed to revoke user tokens: %w", err)
+		}
+		log.Printf("All tokens revoked: user_id=%s, reason=%s", event.UserID, event.Reason)
+	default:
+		log.Printf("Token revoked event without jti or user_id; ignoring")
+	}
+
+	return nil
+}
+
+func (kc *KafkaConsumer) publishQueueEntryCreated(ctx context.Context, entry *models.QueueEntry) {
+	if kc.publisher == nil {
+		log.Printf("No event publisher configured; skipping queue entry created event: token=%s", entry.TokenNumber)
+		return
+	}
+
+	// Publish to notification service via the shared producer
+	if err := kc.publisher.PublishQueueEntryCreated(ctx, entry); err != nil {
 		log.Printf("Failed to publish queue entry created event: %v", err)
-	} else {
-		log.Printf("Published queue entry created event: token=%s", entry.TokenNumber)
+		return
+	}
+	log.Printf("Published queue entry created event: token=%s", entry.TokenNumber)
+}
+
+// decodeOrderCreated decodes order.created in the configured inbound encoding
+func (kc *KafkaConsumer) decodeOrderCreated(data []byte) (OrderCreatedEvent, error) {
+	if kc.encoding == EncodingProtobuf {
+		var msg eventspb.OrderCreated
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return OrderCreatedEvent{}, err
+		}
+		return orderCreatedFromProto(&msg), nil
+	}
+
+	var event OrderCreatedEvent
+	err := json.Unmarshal(data, &event)
+	return event, err
+}
+
+// decodeOrderStatus decodes order.status.changed in the configured inbound encoding
+func (kc *KafkaConsumer) decodeOrderStatus(data []byte) (OrderStatusEvent, error) {
+	if kc.encoding == EncodingProtobuf {
+		var msg eventspb.OrderStatusChanged
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return OrderStatusEvent{}, err
+		}
+		return orderStatusFromProto(&msg), nil
 	}
+
+	var event OrderStatusEvent
+	err := json.Unmarshal(data, &event)
+	return event, err
+}
+
+// decodePaymentCompleted decodes payment.completed in the configured inbound encoding
+func (kc *KafkaConsumer) decodePaymentCompleted(data []byte) (PaymentCompletedEvent, error) {
+	if kc.encoding == EncodingProtobuf {
+		var msg eventspb.PaymentCompleted
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return PaymentCompletedEvent{}, err
+		}
+		return paymentCompletedFromProto(&msg), nil
+	}
+
+	var event PaymentCompletedEvent
+	err := json.Unmarshal(data, &event)
+	return event, err
+}
+
+// decodeMenuUpdated decodes menu.updated in the configured inbound encoding
+func (kc *KafkaConsumer) decodeMenuUpdated(data []byte) (MenuUpdatedEvent, error) {
+	if kc.encoding == EncodingProtobuf {
+		var msg eventspb.MenuUpdated
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return MenuUpdatedEvent{}, err
+		}
+		return menuUpdatedFromProto(&msg), nil
+	}
+
+	var event MenuUpdatedEvent
+	err := json.Unmarshal(data, &event)
+	return event, err
+}
+
+// decodeOrderCancelled decodes order.cancelled in the configured inbound encoding
+func (kc *KafkaConsumer) decodeOrderCancelled(data []byte) (OrderCancelledEvent, error) {
+	if kc.encoding == EncodingProtobuf {
+		var msg eventspb.OrderCancelled
+		if err := proto.Unmarshal(data, &msg); err != nil {
+			return OrderCancelledEvent{}, err
+		}
+		return orderCancelledFromProto(&msg), nil
+	}
+
+	var event OrderCancelledEvent
+	err := json.Unmarshal(data, &event)
+	return event, err
 }
 
 func determineTokenType(itemCount int, isExpress bool) string {
@@ -0,0 +1,87 @@
+package kafka
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"gin-quickstart/metrics"
+	"gin-quickstart/models"
+	"gin-quickstart/tracing"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Headers attached to dead letter messages
+const (
+	headerDLQOriginalTopic     = "dlq.original.topic"
+	headerDLQOriginalPartition = "dlq.original.partition"
+	headerDLQOriginalOffset    = "dlq.original.offset"
+	headerDLQError             = "dlq.error"
+	headerDLQAttempts          = "dlq.attempts"
+	headerDLQFailedAt          = "dlq.failed.at"
+)
+
+// sendToDeadLetter writes a failed message to the configured DLQ topic and records it for re-driving
+func (kc *KafkaConsumer) sendToDeadLetter(message *sarama.ConsumerMessage, handleErr error, attempts int) {
+	failedAt := time.Now().UTC()
+
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+6)
+	for _, h := range message.Headers {
+		if h != nil {
+			headers = append(headers, *h)
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(headerDLQOriginalTopic), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte(headerDLQOriginalPartition), Value: []byte(strconv.Itoa(int(message.Partition)))},
+		sarama.RecordHeader{Key: []byte(headerDLQOriginalOffset), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		sarama.RecordHeader{Key: []byte(headerDLQError), Value: []byte(handleErr.Error())},
+		sarama.RecordHeader{Key: []byte(headerDLQAttempts), Value: []byte(strconv.Itoa(attempts))},
+		sarama.RecordHeader{Key: []byte(headerDLQFailedAt), Value: []byte(failedAt.Format(time.RFC3339))},
+	)
+
+	msg := &sarama.ProducerMessage{
+		Topic:   kc.topics.DeadLetter,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+	if message.Key != nil {
+		msg.Key = sarama.ByteEncoder(message.Key)
+	}
+
+	ctx, span := tracing.StartProducerSpan(context.Background(), msg)
+	defer span.End()
+
+	_, _, err := kc.dlqProducer.SendMessage(msg)
+	metrics.KafkaMessagesProduced.WithLabelValues(kc.topics.DeadLetter, metrics.ResultLabel(err)).Inc()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		log.Printf("Failed to write message to DLQ: topic=%s, offset=%d, error=%v", message.Topic, message.Offset, err)
+	}
+
+	record := &models.DeadLetterMessage{
+		OriginalTopic: message.Topic,
+		Partition:     message.Partition,
+		Offset:        message.Offset,
+		Payload:       string(message.Value),
+		Error:         handleErr.Error(),
+		Attempts:      attempts,
+		FailedAt:      failedAt,
+	}
+	if message.Key != nil {
+		key := string(message.Key)
+		record.MessageKey = &key
+	}
+
+	if err := kc.queueService.RecordDeadLetter(ctx, record); err != nil {
+		log.Printf("Failed to record dead letter: topic=%s, offset=%d, error=%v", message.Topic, message.Offset, err)
+		return
+	}
+
+	log.Printf("Message dead-lettered: topic=%s, partition=%d, offset=%d, id=%s",
+		message.Topic, message.Partition, message.Offset, record.ID)
+}
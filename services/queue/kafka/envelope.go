@@ -0,0 +1,169 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gin-quickstart/utils"
+
+	"github.com/IBM/sarama"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Schema evolution rules for enveloped events:
+//
+//   - Adding an optional field, or a field consumers can default, keeps the
+//     version. Consumers must ignore fields they do not know.
+//   - Removing, renaming or changing the meaning of a field is a breaking
+//     change: bump the event's version and register a handler for the new
+//     version next to the old one, so old producers keep working.
+//   - A handler for an old version stays registered until no producer emits it.
+
+// Envelope wraps every JSON-encoded event. The protobuf topic envelopes carry
+// the same metadata; Avro events carry it in headers.
+type Envelope struct {
+	EventID    string          `json:"event_id"`
+	Type       string          `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// EventMeta identifies one published event
+type EventMeta struct {
+	ID         string
+	Type       string
+	Version    int
+	OccurredAt time.Time
+}
+
+// versionedEvent is implemented by events whose payload has a breaking change
+// from version 1
+type versionedEvent interface {
+	version() int
+}
+
+func newEventMeta(event Event) EventMeta {
+	version := 1
+	if v, ok := event.(versionedEvent); ok {
+		version = v.version()
+	}
+	return EventMeta{
+		ID:         utils.GenerateUUID(),
+		Type:       event.eventType(),
+		Version:    version,
+		OccurredAt: time.Now().UTC(),
+	}
+}
+
+// marshalEnvelope encodes event as the payload of a JSON envelope
+func marshalEnvelope(meta EventMeta, event Event) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(Envelope{
+		EventID:    meta.ID,
+		Type:       meta.Type,
+		Version:    meta.Version,
+		OccurredAt: meta.OccurredAt,
+		Payload:    payload,
+	})
+}
+
+// stampProtoEnvelope sets the envelope metadata fields of a protobuf topic envelope
+func stampProtoEnvelope(msg proto.Message, meta EventMeta) {
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	if fd := fields.ByName("event_id"); fd != nil {
+		m.Set(fd, protoreflect.ValueOfString(meta.ID))
+	}
+	if fd := fields.ByName("version"); fd != nil {
+		m.Set(fd, protoreflect.ValueOfInt32(int32(meta.Version)))
+	}
+	if fd := fields.ByName("occurred_at"); fd != nil {
+		m.Set(fd, protoreflect.ValueOfMessage(timestamppb.New(meta.OccurredAt).ProtoReflect()))
+	}
+}
+
+// Inbound event types handled by the consumer
+const (
+	eventTypeOrderCreated       = "order.created"
+	eventTypeOrderStatusChanged = "order.status.changed"
+	eventTypeOrderCancelled     = "order.cancelled"
+	eventTypePaymentCompleted   = "payment.completed"
+	eventTypeMenuUpdated        = "menu.updated"
+	eventTypeTokenRevoked       = "auth.token.revoked"
+)
+
+// errUnsupportedEvent is returned for an event type and version with no handler
+var errUnsupportedEvent = errors.New("unsupported event")
+
+// inboundEvent is a consumed event with its envelope removed
+type inboundEvent struct {
+	Type    string
+	Version int
+	Payload []byte
+}
+
+type inboundKey struct {
+	eventType string
+	version   int
+}
+
+// inboundHandlers dispatches consumed events by type and version. Register a
+// new version here, next to the old one, when an upstream payload changes.
+var inboundHandlers = map[inboundKey]func(kc *KafkaConsumer, ctx context.Context, payload []byte) error{
+	{eventTypeOrderCreated, 1}:       (*KafkaConsumer).handleOrderCreated,
+	{eventTypeOrderStatusChanged, 1}: (*KafkaConsumer).handleOrderStatusChanged,
+	{eventTypeOrderCancelled, 1}:     (*KafkaConsumer).handleOrderCancelled,
+	{eventTypePaymentCompleted, 1}:   (*KafkaConsumer).handlePaymentCompleted,
+	{eventTypeMenuUpdated, 1}:        (*KafkaConsumer).handleMenuUpdated,
+	{eventTypeTokenRevoked, 1}:       (*KafkaConsumer).handleTokenRevoked,
+}
+
+// unwrap removes the envelope from a consumed message. Bare payloads from
+// producers that predate the envelope are treated as version 1 of the
+// topic's event type, or of the version in the event-version header.
+func (kc *KafkaConsumer) unwrap(message *sarama.ConsumerMessage) (inboundEvent, error) {
+	if kc.encoding != EncodingProtobuf {
+		var envelope Envelope
+		if err := json.Unmarshal(message.Value, &envelope); err == nil && envelope.Type != "" && len(envelope.Payload) > 0 {
+			if envelope.Version == 0 {
+				envelope.Version = 1
+			}
+			return inboundEvent{Type: envelope.Type, Version: envelope.Version, Payload: envelope.Payload}, nil
+		}
+	}
+
+	var eventType string
+	switch message.Topic {
+	case kc.topics.OrderCreated:
+		eventType = eventTypeOrderCreated
+	case kc.topics.OrderStatusChanged:
+		eventType = eventTypeOrderStatusChanged
+	case kc.topics.OrderCancelled:
+		eventType = eventTypeOrderCancelled
+	case kc.topics.PaymentCompleted:
+		eventType = eventTypePaymentCompleted
+	case kc.topics.MenuUpdated:
+		eventType = eventTypeMenuUpdated
+	case kc.topics.TokenRevoked:
+		eventType = eventTypeTokenRevoked
+	default:
+		return inboundEvent{}, fmt.Errorf("%w: no event type for topic %s", errUnsupportedEvent, message.Topic)
+	}
+
+	version := 1
+	if header := headersFromMessage(message).EventVersion; header != "" {
+		if _, err := fmt.Sscanf(header, "%d", &version); err != nil {
+			return inboundEvent{}, fmt.Errorf("invalid event-version header %q", header)
+		}
+	}
+	return inboundEvent{Type: eventType, Version: version, Payload: message.Value}, nil
+}
@@ -0,0 +1,253 @@
+package kafka
+
+import (
+	"time"
+
+	"gin-quickstart/models"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Event is a typed event published by this service. Each event has an Avro
+// schema of the same record name under kafka/schemas and a protobuf message
+// in proto/events.
+type Event interface {
+	// eventType is the event_type discriminator, e.g. "queue.ready"
+	eventType() string
+	// partitionKey keeps all events of one entry on the same partition
+	partitionKey() string
+	// schemaName is the Avro record name of the event
+	schemaName() string
+	// protoMessage wraps the event in its topic's protobuf envelope
+	protoMessage() proto.Message
+}
+
+// QueuePositionUpdatedEvent is published when an entry moves in the queue
+type QueuePositionUpdatedEvent struct {
+	EventType          string     `json:"event_type" avro:"event_type"`
+	QueueEntryID       string     `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID            string     `json:"order_id" avro:"order_id"`
+	UserID             string     `json:"user_id" avro:"user_id"`
+	TokenNumber        string     `json:"token_number" avro:"token_number"`
+	Position           int        `json:"position" avro:"position"`
+	EstimatedWaitTime  int        `json:"estimated_wait_time" avro:"estimated_wait_time"`
+	EstimatedReadyTime *time.Time `json:"estimated_ready_time" avro:"estimated_ready_time"`
+	Status             string     `json:"status" avro:"status"`
+	Timestamp          time.Time  `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueuePositionUpdatedEvent) eventType() string    { return e.EventType }
+func (e *QueuePositionUpdatedEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueuePositionUpdatedEvent) schemaName() string   { return "QueuePositionUpdated" }
+
+// QueueStatusChangedEvent is published when an entry changes status
+type QueueStatusChangedEvent struct {
+	EventType         string    `json:"event_type" avro:"event_type"`
+	QueueEntryID      string    `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID           string    `json:"order_id" avro:"order_id"`
+	UserID            string    `json:"user_id" avro:"user_id"`
+	TokenNumber       string    `json:"token_number" avro:"token_number"`
+	OldStatus         string    `json:"old_status" avro:"old_status"`
+	NewStatus         string    `json:"new_status" avro:"new_status"`
+	Position          int       `json:"position" avro:"position"`
+	EstimatedWaitTime int       `json:"estimated_wait_time" avro:"estimated_wait_time"`
+	Timestamp         time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueueStatusChangedEvent) eventType() string    { return e.EventType }
+func (e *QueueStatusChangedEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueueStatusChangedEvent) schemaName() string   { return "QueueStatusChanged" }
+
+// QueueAlmostReadyEvent asks the notification service to warn the customer
+type QueueAlmostReadyEvent struct {
+	EventType         string    `json:"event_type" avro:"event_type"`
+	QueueEntryID      string    `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID           string    `json:"order_id" avro:"order_id"`
+	UserID            string    `json:"user_id" avro:"user_id"`
+	TokenNumber       string    `json:"token_number" avro:"token_number"`
+	Position          int       `json:"position" avro:"position"`
+	EstimatedWaitTime int       `json:"estimated_wait_time" avro:"estimated_wait_time"`
+	NotificationType  string    `json:"notification_type" avro:"notification_type"`
+	Timestamp         time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueueAlmostReadyEvent) eventType() string    { return e.EventType }
+func (e *QueueAlmostReadyEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueueAlmostReadyEvent) schemaName() string   { return "QueueAlmostReady" }
+
+// QueueReadyEvent asks the notification service to call the customer
+type QueueReadyEvent struct {
+	EventType        string    `json:"event_type" avro:"event_type"`
+	QueueEntryID     string    `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID          string    `json:"order_id" avro:"order_id"`
+	UserID           string    `json:"user_id" avro:"user_id"`
+	TokenNumber      string    `json:"token_number" avro:"token_number"`
+	NotificationType string    `json:"notification_type" avro:"notification_type"`
+	Timestamp        time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueueReadyEvent) eventType() string    { return e.EventType }
+func (e *QueueReadyEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueueReadyEvent) schemaName() string   { return "QueueReady" }
+
+// QueueStageReadyEvent is published when one stage of a staged entry is ready
+type QueueStageReadyEvent struct {
+	EventType        string    `json:"event_type" avro:"event_type"`
+	QueueEntryID     string    `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID          string    `json:"order_id" avro:"order_id"`
+	UserID           string    `json:"user_id" avro:"user_id"`
+	TokenNumber      string    `json:"token_number" avro:"token_number"`
+	StageID          string    `json:"stage_id" avro:"stage_id"`
+	StageSequence    int       `json:"stage_sequence" avro:"stage_sequence"`
+	StageName        string    `json:"stage_name" avro:"stage_name"`
+	RemainingStages  int       `json:"remaining_stages" avro:"remaining_stages"`
+	NotificationType string    `json:"notification_type" avro:"notification_type"`
+	Timestamp        time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueueStageReadyEvent) eventType() string    { return e.EventType }
+func (e *QueueStageReadyEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueueStageReadyEvent) schemaName() string   { return "QueueStageReady" }
+
+// QueueCancelledEvent tells the customer their order left the queue
+type QueueCancelledEvent struct {
+	EventType        string    `json:"event_type" avro:"event_type"`
+	QueueEntryID     string    `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID          string    `json:"order_id" avro:"order_id"`
+	UserID           string    `json:"user_id" avro:"user_id"`
+	TokenNumber      string    `json:"token_number" avro:"token_number"`
+	Reason           *string   `json:"reason" avro:"reason"`
+	NotificationType string    `json:"notification_type" avro:"notification_type"`
+	Timestamp        time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueueCancelledEvent) eventType() string    { return e.EventType }
+func (e *QueueCancelledEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueueCancelledEvent) schemaName() string   { return "QueueCancelled" }
+
+// BatchSuggestedEvent tells the kitchen that adjacent orders share an item
+type BatchSuggestedEvent struct {
+	EventType     string    `json:"event_type" avro:"event_type"`
+	LocationID    string    `json:"location_id" avro:"location_id"`
+	MenuItemID    string    `json:"menu_item_id" avro:"menu_item_id"`
+	ItemName      *string   `json:"item_name" avro:"item_name"`
+	TotalQuantity int       `json:"total_quantity" avro:"total_quantity"`
+	EntryIDs      []string  `json:"entry_ids" avro:"entry_ids"`
+	TokenNumbers  []string  `json:"token_numbers" avro:"token_numbers"`
+	StartPosition int       `json:"start_position" avro:"start_position"`
+	EndPosition   int       `json:"end_position" avro:"end_position"`
+	Timestamp     time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *BatchSuggestedEvent) eventType() string    { return e.EventType }
+func (e *BatchSuggestedEvent) partitionKey() string { return e.LocationID }
+func (e *BatchSuggestedEvent) schemaName() string   { return "BatchSuggested" }
+
+// QueueEntryTombstoneEvent tells consumers to purge their copy of an entry
+type QueueEntryTombstoneEvent struct {
+	EventType    string    `json:"event_type" avro:"event_type"`
+	QueueEntryID string    `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID      string    `json:"order_id" avro:"order_id"`
+	TokenNumber  string    `json:"token_number" avro:"token_number"`
+	Action       string    `json:"action" avro:"action"`
+	Timestamp    time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueueEntryTombstoneEvent) eventType() string    { return e.EventType }
+func (e *QueueEntryTombstoneEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueueEntryTombstoneEvent) schemaName() string   { return "QueueEntryTombstone" }
+
+// QueueCompletedEvent is published when an entry is picked up
+type QueueCompletedEvent struct {
+	EventType    string    `json:"event_type" avro:"event_type"`
+	QueueEntryID string    `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID      string    `json:"order_id" avro:"order_id"`
+	UserID       string    `json:"user_id" avro:"user_id"`
+	TokenNumber  string    `json:"token_number" avro:"token_number"`
+	Timestamp    time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueueCompletedEvent) eventType() string    { return e.EventType }
+func (e *QueueCompletedEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueueCompletedEvent) schemaName() string   { return "QueueCompleted" }
+
+// QueueAdvancedEvent is published when staff advance the queue
+type QueueAdvancedEvent struct {
+	EventType    string    `json:"event_type" avro:"event_type"`
+	QueueEntryID string    `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID      string    `json:"order_id" avro:"order_id"`
+	TokenNumber  string    `json:"token_number" avro:"token_number"`
+	NewStatus    string    `json:"new_status" avro:"new_status"`
+	Timestamp    time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueueAdvancedEvent) eventType() string    { return e.EventType }
+func (e *QueueAdvancedEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueueAdvancedEvent) schemaName() string   { return "QueueAdvanced" }
+
+// QueueEntryCreatedEvent is published when an order enters the queue
+type QueueEntryCreatedEvent struct {
+	EventType          string     `json:"event_type" avro:"event_type"`
+	QueueEntryID       string     `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID            string     `json:"order_id" avro:"order_id"`
+	UserID             string     `json:"user_id" avro:"user_id"`
+	TokenNumber        string     `json:"token_number" avro:"token_number"`
+	Position           int        `json:"position" avro:"position"`
+	EstimatedWaitTime  int        `json:"estimated_wait_time" avro:"estimated_wait_time"`
+	EstimatedReadyTime *time.Time `json:"estimated_ready_time" avro:"estimated_ready_time"`
+	CreatedAt          time.Time  `json:"created_at" avro:"created_at"`
+}
+
+func (e *QueueEntryCreatedEvent) eventType() string    { return e.EventType }
+func (e *QueueEntryCreatedEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueueEntryCreatedEvent) schemaName() string   { return "QueueEntryCreated" }
+
+// QueueSLABreachedEvent is published when an active entry has waited longer
+// than the SLA of its priority
+type QueueSLABreachedEvent struct {
+	EventType     string    `json:"event_type" avro:"event_type"`
+	QueueEntryID  string    `json:"queue_entry_id" avro:"queue_entry_id"`
+	OrderID       string    `json:"order_id" avro:"order_id"`
+	UserID        string    `json:"user_id" avro:"user_id"`
+	TokenNumber   string    `json:"token_number" avro:"token_number"`
+	LocationID    string    `json:"location_id" avro:"location_id"`
+	Status        string    `json:"status" avro:"status"`
+	Priority      string    `json:"priority" avro:"priority"`
+	WaitedMinutes int       `json:"waited_minutes" avro:"waited_minutes"`
+	SLAMinutes    int       `json:"sla_minutes" avro:"sla_minutes"`
+	Timestamp     time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueueSLABreachedEvent) eventType() string    { return e.EventType }
+func (e *QueueSLABreachedEvent) partitionKey() string { return e.QueueEntryID }
+func (e *QueueSLABreachedEvent) schemaName() string   { return "QueueSlaBreached" }
+
+// QueueDayClosedEvent is published when a location's business day is rolled
+// over after closing time
+type QueueDayClosedEvent struct {
+	EventType       string    `json:"event_type" avro:"event_type"`
+	LocationID      string    `json:"location_id" avro:"location_id"`
+	BusinessDate    string    `json:"business_date" avro:"business_date"`
+	ExpiredEntries  int       `json:"expired_entries" avro:"expired_entries"`
+	TokensIssued    int       `json:"tokens_issued" avro:"tokens_issued"`
+	NextTokenPrefix string    `json:"next_token_prefix" avro:"next_token_prefix"`
+	Timestamp       time.Time `json:"timestamp" avro:"timestamp"`
+}
+
+func (e *QueueDayClosedEvent) eventType() string    { return e.EventType }
+func (e *QueueDayClosedEvent) partitionKey() string { return e.LocationID }
+func (e *QueueDayClosedEvent) schemaName() string   { return "QueueDayClosed" }
+
+func newQueueEntryCreatedEvent(entry *models.QueueEntry) *QueueEntryCreatedEvent {
+	return &QueueEntryCreatedEvent{
+		EventType:          "queue.entry.created",
+		QueueEntryID:       entry.ID,
+		OrderID:            entry.OrderID,
+		UserID:             entry.UserID,
+		TokenNumber:        entry.TokenNumber,
+		Position:           entry.Position,
+		EstimatedWaitTime:  entry.EstimatedWaitTime,
+		EstimatedReadyTime: entry.EstimatedReadyTime,
+		CreatedAt:          entry.CreatedAt,
+	}
+}
@@ -0,0 +1,264 @@
+package kafka
+
+import (
+	"time"
+
+	eventspb "gin-quickstart/proto/events"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Protobuf envelopes: one oneof message per topic, so consumers can decode
+// any message on a topic without out-of-band type information.
+
+func (e *QueuePositionUpdatedEvent) protoMessage() proto.Message {
+	return &eventspb.QueueEvent{Event: &eventspb.QueueEvent_PositionUpdated{PositionUpdated: &eventspb.QueuePositionUpdated{
+		EventType:          e.EventType,
+		QueueEntryId:       e.QueueEntryID,
+		OrderId:            e.OrderID,
+		UserId:             e.UserID,
+		TokenNumber:        e.TokenNumber,
+		Position:           int32(e.Position),
+		EstimatedWaitTime:  int32(e.EstimatedWaitTime),
+		EstimatedReadyTime: timestampOrNil(e.EstimatedReadyTime),
+		Status:             e.Status,
+		Timestamp:          timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *QueueStatusChangedEvent) protoMessage() proto.Message {
+	return &eventspb.QueueEvent{Event: &eventspb.QueueEvent_StatusChanged{StatusChanged: &eventspb.QueueStatusChanged{
+		EventType:         e.EventType,
+		QueueEntryId:      e.QueueEntryID,
+		OrderId:           e.OrderID,
+		UserId:            e.UserID,
+		TokenNumber:       e.TokenNumber,
+		OldStatus:         e.OldStatus,
+		NewStatus:         e.NewStatus,
+		Position:          int32(e.Position),
+		EstimatedWaitTime: int32(e.EstimatedWaitTime),
+		Timestamp:         timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *QueueAlmostReadyEvent) protoMessage() proto.Message {
+	return &eventspb.NotificationEvent{Event: &eventspb.NotificationEvent_AlmostReady{AlmostReady: &eventspb.QueueAlmostReady{
+		EventType:         e.EventType,
+		QueueEntryId:      e.QueueEntryID,
+		OrderId:           e.OrderID,
+		UserId:            e.UserID,
+		TokenNumber:       e.TokenNumber,
+		Position:          int32(e.Position),
+		EstimatedWaitTime: int32(e.EstimatedWaitTime),
+		NotificationType:  e.NotificationType,
+		Timestamp:         timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *QueueReadyEvent) protoMessage() proto.Message {
+	return &eventspb.NotificationEvent{Event: &eventspb.NotificationEvent_Ready{Ready: &eventspb.QueueReady{
+		EventType:        e.EventType,
+		QueueEntryId:     e.QueueEntryID,
+		OrderId:          e.OrderID,
+		UserId:           e.UserID,
+		TokenNumber:      e.TokenNumber,
+		NotificationType: e.NotificationType,
+		Timestamp:        timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *QueueStageReadyEvent) protoMessage() proto.Message {
+	return &eventspb.NotificationEvent{Event: &eventspb.NotificationEvent_StageReady{StageReady: &eventspb.QueueStageReady{
+		EventType:        e.EventType,
+		QueueEntryId:     e.QueueEntryID,
+		OrderId:          e.OrderID,
+		UserId:           e.UserID,
+		TokenNumber:      e.TokenNumber,
+		StageId:          e.StageID,
+		StageSequence:    int32(e.StageSequence),
+		StageName:        e.StageName,
+		RemainingStages:  int32(e.RemainingStages),
+		NotificationType: e.NotificationType,
+		Timestamp:        timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *QueueCancelledEvent) protoMessage() proto.Message {
+	return &eventspb.NotificationEvent{Event: &eventspb.NotificationEvent_Cancelled{Cancelled: &eventspb.QueueCancelled{
+		EventType:        e.EventType,
+		QueueEntryId:     e.QueueEntryID,
+		OrderId:          e.OrderID,
+		UserId:           e.UserID,
+		TokenNumber:      e.TokenNumber,
+		Reason:           e.Reason,
+		NotificationType: e.NotificationType,
+		Timestamp:        timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *BatchSuggestedEvent) protoMessage() proto.Message {
+	return &eventspb.KitchenEvent{Event: &eventspb.KitchenEvent_BatchSuggested{BatchSuggested: &eventspb.BatchSuggested{
+		EventType:     e.EventType,
+		LocationId:    e.LocationID,
+		MenuItemId:    e.MenuItemID,
+		ItemName:      e.ItemName,
+		TotalQuantity: int32(e.TotalQuantity),
+		EntryIds:      e.EntryIDs,
+		TokenNumbers:  e.TokenNumbers,
+		StartPosition: int32(e.StartPosition),
+		EndPosition:   int32(e.EndPosition),
+		Timestamp:     timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *QueueEntryTombstoneEvent) protoMessage() proto.Message {
+	return &eventspb.QueueEvent{Event: &eventspb.QueueEvent_EntryTombstone{EntryTombstone: &eventspb.QueueEntryTombstone{
+		EventType:    e.EventType,
+		QueueEntryId: e.QueueEntryID,
+		OrderId:      e.OrderID,
+		TokenNumber:  e.TokenNumber,
+		Action:       e.Action,
+		Timestamp:    timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *QueueCompletedEvent) protoMessage() proto.Message {
+	return &eventspb.QueueEvent{Event: &eventspb.QueueEvent_Completed{Completed: &eventspb.QueueCompleted{
+		EventType:    e.EventType,
+		QueueEntryId: e.QueueEntryID,
+		OrderId:      e.OrderID,
+		UserId:       e.UserID,
+		TokenNumber:  e.TokenNumber,
+		Timestamp:    timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *QueueAdvancedEvent) protoMessage() proto.Message {
+	return &eventspb.QueueEvent{Event: &eventspb.QueueEvent_Advanced{Advanced: &eventspb.QueueAdvanced{
+		EventType:    e.EventType,
+		QueueEntryId: e.QueueEntryID,
+		OrderId:      e.OrderID,
+		TokenNumber:  e.TokenNumber,
+		NewStatus:    e.NewStatus,
+		Timestamp:    timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *QueueEntryCreatedEvent) protoMessage() proto.Message {
+	return &eventspb.QueueEvent{Event: &eventspb.QueueEvent_EntryCreated{EntryCreated: &eventspb.QueueEntryCreated{
+		EventType:          e.EventType,
+		QueueEntryId:       e.QueueEntryID,
+		OrderId:            e.OrderID,
+		UserId:             e.UserID,
+		TokenNumber:        e.TokenNumber,
+		Position:           int32(e.Position),
+		EstimatedWaitTime:  int32(e.EstimatedWaitTime),
+		EstimatedReadyTime: timestampOrNil(e.EstimatedReadyTime),
+		CreatedAt:          timestamppb.New(e.CreatedAt),
+	}}}
+}
+
+func (e *QueueSLABreachedEvent) protoMessage() proto.Message {
+	return &eventspb.QueueEvent{Event: &eventspb.QueueEvent_SlaBreached{SlaBreached: &eventspb.QueueSlaBreached{
+		EventType:     e.EventType,
+		QueueEntryId:  e.QueueEntryID,
+		OrderId:       e.OrderID,
+		UserId:        e.UserID,
+		TokenNumber:   e.TokenNumber,
+		LocationId:    e.LocationID,
+		Status:        e.Status,
+		Priority:      e.Priority,
+		WaitedMinutes: int32(e.WaitedMinutes),
+		SlaMinutes:    int32(e.SLAMinutes),
+		Timestamp:     timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func (e *QueueDayClosedEvent) protoMessage() proto.Message {
+	return &eventspb.QueueEvent{Event: &eventspb.QueueEvent_DayClosed{DayClosed: &eventspb.QueueDayClosed{
+		EventType:       e.EventType,
+		LocationId:      e.LocationID,
+		BusinessDate:    e.BusinessDate,
+		ExpiredEntries:  int32(e.ExpiredEntries),
+		TokensIssued:    int32(e.TokensIssued),
+		NextTokenPrefix: e.NextTokenPrefix,
+		Timestamp:       timestamppb.New(e.Timestamp),
+	}}}
+}
+
+func timestampOrNil(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// orderCreatedFromProto converts an inbound protobuf order.created message
+func orderCreatedFromProto(msg *eventspb.OrderCreated) OrderCreatedEvent {
+	event := OrderCreatedEvent{
+		OrderID:       msg.GetOrderId(),
+		UserID:        msg.GetUserId(),
+		LocationID:    msg.GetLocationId(),
+		UserName:      msg.GetUserName(),
+		UserPhone:     msg.GetUserPhone(),
+		TotalAmount:   msg.GetTotalAmount(),
+		Priority:      msg.GetPriority(),
+		IsExpress:     msg.GetIsExpress(),
+		PaymentStatus: msg.GetPaymentStatus(),
+		CreatedAt:     msg.GetCreatedAt().AsTime(),
+	}
+	for _, item := range msg.GetItems() {
+		event.Items = append(event.Items, OrderItem{
+			MenuItemID: item.GetMenuItemId(),
+			Name:       item.GetName(),
+			Quantity:   int(item.GetQuantity()),
+			Price:      item.GetPrice(),
+		})
+	}
+	return event
+}
+
+// orderStatusFromProto converts an inbound protobuf order.status.changed message
+func orderStatusFromProto(msg *eventspb.OrderStatusChanged) OrderStatusEvent {
+	return OrderStatusEvent{
+		OrderID:   msg.GetOrderId(),
+		Status:    msg.GetStatus(),
+		UpdatedAt: msg.GetUpdatedAt().AsTime(),
+	}
+}
+
+// orderCancelledFromProto converts an inbound protobuf order.cancelled message
+func orderCancelledFromProto(msg *eventspb.OrderCancelled) OrderCancelledEvent {
+	return OrderCancelledEvent{
+		OrderID:     msg.GetOrderId(),
+		Reason:      msg.GetReason(),
+		CancelledAt: msg.GetCancelledAt().AsTime(),
+	}
+}
+
+// paymentCompletedFromProto converts an inbound protobuf payment.completed message
+func paymentCompletedFromProto(msg *eventspb.PaymentCompleted) PaymentCompletedEvent {
+	return PaymentCompletedEvent{
+		OrderID:     msg.GetOrderId(),
+		PaymentID:   msg.GetPaymentId(),
+		Amount:      msg.GetAmount(),
+		CompletedAt: msg.GetCompletedAt().AsTime(),
+	}
+}
+
+// menuUpdatedFromProto converts an inbound protobuf menu.updated message
+func menuUpdatedFromProto(msg *eventspb.MenuUpdated) MenuUpdatedEvent {
+	event := MenuUpdatedEvent{
+		MenuItemID:  msg.GetMenuItemId(),
+		Name:        msg.GetName(),
+		IsAvailable: msg.GetIsAvailable(),
+		Deleted:     msg.GetDeleted(),
+		UpdatedAt:   msg.GetUpdatedAt().AsTime(),
+	}
+	if msg.PreparationTime != nil {
+		minutes := int(msg.GetPreparationTime())
+		event.PreparationTime = &minutes
+	}
+	return event
+}
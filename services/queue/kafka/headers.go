@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+
+	"gin-quickstart/tracing"
+
+	"github.com/IBM/sarama"
+)
+
+// Headers attached to every produced event so consumers can route and
+// correlate messages without decoding the payload
+const (
+	HeaderEventID      = "event-id"
+	HeaderEventType    = "event-type"
+	HeaderEventVersion = "event-version"
+	HeaderContentType  = "content-type"
+	HeaderRequestID    = "request-id"
+	HeaderTraceID      = "trace-id"
+	HeaderSource       = "source"
+)
+
+// MessageHeaders are the correlation headers extracted from a consumed message
+type MessageHeaders struct {
+	EventID      string
+	EventType    string
+	EventVersion string
+	ContentType  string
+	RequestID    string
+	TraceID      string
+	Source       string
+}
+
+// setEventHeaders adds event metadata and correlation context to msg; meta
+// is nil for raw payloads. Call it with the producer span context so trace-id
+// matches traceparent.
+func setEventHeaders(ctx context.Context, msg *sarama.ProducerMessage, source string, meta *EventMeta, contentType string) {
+	setHeader(msg, HeaderSource, source)
+	setHeader(msg, HeaderRequestID, tracing.RequestIDFromContext(ctx))
+	setHeader(msg, HeaderTraceID, tracing.TraceIDFromContext(ctx))
+	if meta != nil {
+		setHeader(msg, HeaderEventID, meta.ID)
+		setHeader(msg, HeaderEventType, meta.Type)
+		setHeader(msg, HeaderEventVersion, strconv.Itoa(meta.Version))
+	}
+	setHeader(msg, HeaderContentType, contentType)
+}
+
+// setHeader replaces or appends a header, skipping empty values
+func setHeader(msg *sarama.ProducerMessage, key, value string) {
+	if value == "" {
+		return
+	}
+	for i, h := range msg.Headers {
+		if string(h.Key) == key {
+			msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	msg.Headers = append(msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+// headersFromMessage extracts the correlation headers of a consumed message
+func headersFromMessage(msg *sarama.ConsumerMessage) MessageHeaders {
+	var headers MessageHeaders
+	for _, h := range msg.Headers {
+		if h == nil {
+			continue
+		}
+		value := string(h.Value)
+		switch string(h.Key) {
+		case HeaderEventID:
+			headers.EventID = value
+		case HeaderEventType:
+			headers.EventType = value
+		case HeaderEventVersion:
+			headers.EventVersion = value
+		case HeaderContentType:
+			headers.ContentType = value
+		case HeaderRequestID:
+			headers.RequestID = value
+		case HeaderTraceID:
+			headers.TraceID = value
+		case HeaderSource:
+			headers.Source = value
+		}
+	}
+	return headers
+}
@@ -0,0 +1,21 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/IBM/sarama"
+)
+
+// eventKey identifies a message for deduplication. Producers that stamp an
+// event_id get dedup across topics and re-publishes; otherwise the Kafka
+// coordinates are used, which covers re-delivery after a rebalance.
+func eventKey(message *sarama.ConsumerMessage) string {
+	var envelope struct {
+		EventID string `json:"event_id"`
+	}
+	if err := json.Unmarshal(message.Value, &envelope); err == nil && envelope.EventID != "" {
+		return "event:" + envelope.EventID
+	}
+	return fmt.Sprintf("%s/%d/%d", message.Topic, message.Partition, message.Offset)
+}
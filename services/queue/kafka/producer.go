@@ -1,155 +1,332 @@
 package kafka
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sync/atomic"
 	"time"
 
 	"gin-quickstart/config"
+	"gin-quickstart/metrics"
 	"gin-quickstart/models"
+	"gin-quickstart/tracing"
 
 	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/codes"
 )
 
 type KafkaProducer struct {
-	producer sarama.SyncProducer
+	client     sarama.Client
+	producer   sarama.SyncProducer
+	serializer Serializer
+	source     string
+	topics     Topics
+	connected  atomic.Bool
 }
 
 func NewKafkaProducer(cfg *config.Config) (*KafkaProducer, error) {
+	serializer, err := NewSerializer(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	config := sarama.NewConfig()
 	config.Producer.Return.Successes = true
 	config.Producer.Retry.Max = 3
 	config.Producer.RequiredAcks = sarama.WaitForAll
 
-	producer, err := sarama.NewSyncProducer(cfg.KafkaBrokers, config)
+	client, err := sarama.NewClient(cfg.KafkaBrokers, config)
 	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to create producer: %w", err)
 	}
 
 	log.Println("Kafka producer created successfully")
-	return &KafkaProducer{producer: producer}, nil
+	kp := &KafkaProducer{client: client, producer: producer, serializer: serializer, source: cfg.ServiceName, topics: TopicsFromConfig(cfg)}
+	kp.setConnected(true, nil)
+	return kp, nil
 }
 
 func (kp *KafkaProducer) Close() error {
-	return kp.producer.Close()
+	if err := kp.producer.Close(); err != nil {
+		return err
+	}
+	if !kp.client.Closed() {
+		return kp.client.Close()
+	}
+	return nil
+}
+
+// Ping verifies that the brokers are reachable by refreshing cluster metadata
+func (kp *KafkaProducer) Ping(ctx context.Context) error {
+	if kp.client.Closed() {
+		return errors.New("kafka client closed")
+	}
+	return kp.client.RefreshMetadata()
 }
 
 // PublishQueuePositionUpdate publishes position update event
-func (kp *KafkaProducer) PublishQueuePositionUpdate(entry *models.QueueEntry) error {
-	event := map[string]interface{}{
-		"event_type":          "queue.position.updated",
-		"queue_entry_id":      entry.ID,
-		"order_id":            entry.OrderID,
-		"user_id":             entry.UserID,
-		"token_number":        entry.TokenNumber,
-		"position":            entry.Position,
-		"estimated_wait_time": entry.EstimatedWaitTime,
-		"estimated_ready_time": entry.EstimatedReadyTime,
-		"status":              entry.Status,
-		"timestamp":           time.Now().UTC(),
+func (kp *KafkaProducer) PublishQueuePositionUpdate(ctx context.Context, entry *models.QueueEntry) error {
+	event := &QueuePositionUpdatedEvent{
+		EventType:          "queue.position.updated",
+		QueueEntryID:       entry.ID,
+		OrderID:            entry.OrderID,
+		UserID:             entry.UserID,
+		TokenNumber:        entry.TokenNumber,
+		Position:           entry.Position,
+		EstimatedWaitTime:  entry.EstimatedWaitTime,
+		EstimatedReadyTime: entry.EstimatedReadyTime,
+		Status:             entry.Status,
+		Timestamp:          time.Now().UTC(),
 	}
 
-	return kp.publishEvent("queue.events", event)
+	return kp.publishEvent(ctx, kp.topics.QueueEvents, event)
 }
 
 // PublishQueueStatusChanged publishes status change event
-func (kp *KafkaProducer) PublishQueueStatusChanged(entry *models.QueueEntry, oldStatus, newStatus string) error {
-	event := map[string]interface{}{
-		"event_type":          "queue.status.changed",
-		"queue_entry_id":      entry.ID,
-		"order_id":            entry.OrderID,
-		"user_id":             entry.UserID,
-		"token_number":        entry.TokenNumber,
-		"old_status":          oldStatus,
-		"new_status":          newStatus,
-		"position":            entry.Position,
-		"estimated_wait_time": entry.EstimatedWaitTime,
-		"timestamp":           time.Now().UTC(),
+func (kp *KafkaProducer) PublishQueueStatusChanged(ctx context.Context, entry *models.QueueEntry, oldStatus, newStatus string) error {
+	event := &QueueStatusChangedEvent{
+		EventType:         "queue.status.changed",
+		QueueEntryID:      entry.ID,
+		OrderID:           entry.OrderID,
+		UserID:            entry.UserID,
+		TokenNumber:       entry.TokenNumber,
+		OldStatus:         oldStatus,
+		NewStatus:         newStatus,
+		Position:          entry.Position,
+		EstimatedWaitTime: entry.EstimatedWaitTime,
+		Timestamp:         time.Now().UTC(),
 	}
 
-	return kp.publishEvent("queue.events", event)
+	return kp.publishEvent(ctx, kp.topics.QueueEvents, event)
 }
 
 // PublishQueueAlmostReady publishes almost ready notification
-func (kp *KafkaProducer) PublishQueueAlmostReady(entry *models.QueueEntry) error {
-	event := map[string]interface{}{
-		"event_type":          "queue.almost.ready",
-		"queue_entry_id":      entry.ID,
-		"order_id":            entry.OrderID,
-		"user_id":             entry.UserID,
-		"token_number":        entry.TokenNumber,
-		"position":            entry.Position,
-		"estimated_wait_time": entry.EstimatedWaitTime,
-		"timestamp":           time.Now().UTC(),
-		"notification_type":   "ALMOST_READY",
+func (kp *KafkaProducer) PublishQueueAlmostReady(ctx context.Context, entry *models.QueueEntry) error {
+	event := &QueueAlmostReadyEvent{
+		EventType:         "queue.almost.ready",
+		QueueEntryID:      entry.ID,
+		OrderID:           entry.OrderID,
+		UserID:            entry.UserID,
+		TokenNumber:       entry.TokenNumber,
+		Position:          entry.Position,
+		EstimatedWaitTime: entry.EstimatedWaitTime,
+		NotificationType:  "ALMOST_READY",
+		Timestamp:         time.Now().UTC(),
 	}
 
-	return kp.publishEvent("notification.events", event)
+	return kp.publishEvent(ctx, kp.topics.NotificationEvents, event)
 }
 
 // PublishQueueReady publishes ready notification
-func (kp *KafkaProducer) PublishQueueReady(entry *models.QueueEntry) error {
-	event := map[string]interface{}{
-		"event_type":     "queue.ready",
-		"queue_entry_id": entry.ID,
-		"order_id":       entry.OrderID,
-		"user_id":        entry.UserID,
-		"token_number":   entry.TokenNumber,
-		"timestamp":      time.Now().UTC(),
-		"notification_type": "READY",
+func (kp *KafkaProducer) PublishQueueReady(ctx context.Context, entry *models.QueueEntry) error {
+	event := &QueueReadyEvent{
+		EventType:        "queue.ready",
+		QueueEntryID:     entry.ID,
+		OrderID:          entry.OrderID,
+		UserID:           entry.UserID,
+		TokenNumber:      entry.TokenNumber,
+		NotificationType: "READY",
+		Timestamp:        time.Now().UTC(),
+	}
+
+	return kp.publishEvent(ctx, kp.topics.NotificationEvents, event)
+}
+
+// PublishQueueStageReady publishes a per-stage ready notification
+func (kp *KafkaProducer) PublishQueueStageReady(ctx context.Context, entry *models.QueueEntry, stage *models.QueueEntryStage, remainingStages int) error {
+	event := &QueueStageReadyEvent{
+		EventType:        "queue.stage.ready",
+		QueueEntryID:     entry.ID,
+		OrderID:          entry.OrderID,
+		UserID:           entry.UserID,
+		TokenNumber:      entry.TokenNumber,
+		StageID:          stage.ID,
+		StageSequence:    stage.Sequence,
+		StageName:        stage.Name,
+		RemainingStages:  remainingStages,
+		NotificationType: "READY",
+		Timestamp:        time.Now().UTC(),
+	}
+
+	return kp.publishEvent(ctx, kp.topics.NotificationEvents, event)
+}
+
+// PublishQueueCancelled publishes a cancellation notification
+func (kp *KafkaProducer) PublishQueueCancelled(ctx context.Context, entry *models.QueueEntry, reason *string) error {
+	event := &QueueCancelledEvent{
+		EventType:        "queue.cancelled",
+		QueueEntryID:     entry.ID,
+		OrderID:          entry.OrderID,
+		UserID:           entry.UserID,
+		TokenNumber:      entry.TokenNumber,
+		Reason:           reason,
+		NotificationType: "CANCELLED",
+		Timestamp:        time.Now().UTC(),
 	}
 
-	return kp.publishEvent("notification.events", event)
+	return kp.publishEvent(ctx, kp.topics.NotificationEvents, event)
+}
+
+// PublishQueueEntryTombstone publishes a tombstone so consumers purge their copies
+func (kp *KafkaProducer) PublishQueueEntryTombstone(ctx context.Context, tombstone *models.QueueEntryTombstone) error {
+	event := &QueueEntryTombstoneEvent{
+		EventType:    "queue.entry.tombstone",
+		QueueEntryID: tombstone.QueueEntryID,
+		OrderID:      tombstone.OrderID,
+		TokenNumber:  tombstone.TokenNumber,
+		Action:       tombstone.Action,
+		Timestamp:    tombstone.CreatedAt,
+	}
+
+	return kp.publishEvent(ctx, kp.topics.QueueEvents, event)
+}
+
+// PublishBatchSuggestion tells the kitchen that adjacent orders share an item
+func (kp *KafkaProducer) PublishBatchSuggestion(ctx context.Context, locationID string, suggestion *models.BatchSuggestion) error {
+	event := &BatchSuggestedEvent{
+		EventType:     "kitchen.batch.suggested",
+		LocationID:    locationID,
+		MenuItemID:    suggestion.MenuItemID,
+		ItemName:      suggestion.ItemName,
+		TotalQuantity: suggestion.TotalQuantity,
+		EntryIDs:      suggestion.EntryIDs,
+		TokenNumbers:  suggestion.TokenNumbers,
+		StartPosition: suggestion.StartPosition,
+		EndPosition:   suggestion.EndPosition,
+		Timestamp:     time.Now().UTC(),
+	}
+
+	return kp.publishEvent(ctx, kp.topics.KitchenEvents, event)
 }
 
 // PublishQueueCompleted publishes completion event
-func (kp *KafkaProducer) PublishQueueCompleted(entry *models.QueueEntry) error {
-	event := map[string]interface{}{
-		"event_type":     "queue.completed",
-		"queue_entry_id": entry.ID,
-		"order_id":       entry.OrderID,
-		"user_id":        entry.UserID,
-		"token_number":   entry.TokenNumber,
-		"timestamp":      time.Now().UTC(),
+func (kp *KafkaProducer) PublishQueueCompleted(ctx context.Context, entry *models.QueueEntry) error {
+	event := &QueueCompletedEvent{
+		EventType:    "queue.completed",
+		QueueEntryID: entry.ID,
+		OrderID:      entry.OrderID,
+		UserID:       entry.UserID,
+		TokenNumber:  entry.TokenNumber,
+		Timestamp:    time.Now().UTC(),
 	}
 
-	return kp.publishEvent("queue.events", event)
+	return kp.publishEvent(ctx, kp.topics.QueueEvents, event)
+}
+
+// PublishQueueEntryCreated publishes the event for a newly queued order
+func (kp *KafkaProducer) PublishQueueEntryCreated(ctx context.Context, entry *models.QueueEntry) error {
+	return kp.publishEvent(ctx, kp.topics.QueueEvents, newQueueEntryCreatedEvent(entry))
+}
+
+// PublishSLABreached publishes that an entry has waited past its SLA
+func (kp *KafkaProducer) PublishSLABreached(ctx context.Context, entry *models.QueueEntry, waitedMinutes, slaMinutes int) error {
+	event := &QueueSLABreachedEvent{
+		EventType:     "queue.sla.breached",
+		QueueEntryID:  entry.ID,
+		OrderID:       entry.OrderID,
+		UserID:        entry.UserID,
+		TokenNumber:   entry.TokenNumber,
+		LocationID:    entry.LocationID,
+		Status:        entry.Status,
+		Priority:      entry.Priority,
+		WaitedMinutes: waitedMinutes,
+		SLAMinutes:    slaMinutes,
+		Timestamp:     time.Now().UTC(),
+	}
+
+	return kp.publishEvent(ctx, kp.topics.QueueEvents, event)
+}
+
+// PublishDayClosed publishes that a location's business day was rolled over
+func (kp *KafkaProducer) PublishDayClosed(ctx context.Context, rollover *models.QueueDayRollover) error {
+	event := &QueueDayClosedEvent{
+		EventType:       "queue.day.closed",
+		LocationID:      rollover.LocationID,
+		BusinessDate:    rollover.BusinessDate.Format("2006-01-02"),
+		ExpiredEntries:  rollover.ExpiredEntries,
+		TokensIssued:    rollover.TokensIssued,
+		NextTokenPrefix: rollover.NextTokenPrefix,
+		Timestamp:       time.Now().UTC(),
+	}
+
+	return kp.publishEvent(ctx, kp.topics.QueueEvents, event)
 }
 
 // PublishQueueAdvanced publishes queue advance event
-func (kp *KafkaProducer) PublishQueueAdvanced(entry *models.QueueEntry) error {
-	event := map[string]interface{}{
-		"event_type":     "queue.advanced",
-		"queue_entry_id": entry.ID,
-		"order_id":       entry.OrderID,
-		"token_number":   entry.TokenNumber,
-		"new_status":     entry.Status,
-		"timestamp":      time.Now().UTC(),
+func (kp *KafkaProducer) PublishQueueAdvanced(ctx context.Context, entry *models.QueueEntry) error {
+	event := &QueueAdvancedEvent{
+		EventType:    "queue.advanced",
+		QueueEntryID: entry.ID,
+		OrderID:      entry.OrderID,
+		TokenNumber:  entry.TokenNumber,
+		NewStatus:    entry.Status,
+		Timestamp:    time.Now().UTC(),
 	}
 
-	return kp.publishEvent("queue.events", event)
+	return kp.publishEvent(ctx, kp.topics.QueueEvents, event)
 }
 
-func (kp *KafkaProducer) publishEvent(topic string, event map[string]interface{}) error {
-	data, err := json.Marshal(event)
+// PublishRaw publishes an already-encoded payload, e.g. when re-driving dead letters
+func (kp *KafkaProducer) PublishRaw(ctx context.Context, topic string, key *string, payload []byte) error {
+	msg := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(payload),
+	}
+	if key != nil {
+		msg.Key = sarama.StringEncoder(*key)
+	}
+
+	ctx, span := tracing.StartProducerSpan(ctx, msg)
+	defer span.End()
+	setEventHeaders(ctx, msg, kp.source, nil, "")
+
+	_, _, err := kp.producer.SendMessage(msg)
+	metrics.KafkaMessagesProduced.WithLabelValues(topic, metrics.ResultLabel(err)).Inc()
+	kp.observeSend(err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+func (kp *KafkaProducer) publishEvent(ctx context.Context, topic string, event Event) error {
+	meta := newEventMeta(event)
+	data, err := kp.serializer.Serialize(ctx, topic, meta, event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
+		return fmt.Errorf("failed to serialize event: %w", err)
 	}
 
 	msg := &sarama.ProducerMessage{
 		Topic: topic,
 		Value: sarama.ByteEncoder(data),
-		Key:   sarama.StringEncoder(fmt.Sprintf("%v", event["queue_entry_id"])),
+		Key:   sarama.StringEncoder(event.partitionKey()),
 	}
 
+	ctx, span := tracing.StartProducerSpan(ctx, msg)
+	defer span.End()
+	setEventHeaders(ctx, msg, kp.source, &meta, kp.serializer.ContentType())
+
 	partition, offset, err := kp.producer.SendMessage(msg)
+	metrics.KafkaMessagesProduced.WithLabelValues(topic, metrics.ResultLabel(err)).Inc()
+	kp.observeSend(err)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("failed to send message: %w", err)
 	}
 
-	log.Printf("Published event to %s: partition=%d, offset=%d, event_type=%s",
-		topic, partition, offset, event["event_type"])
+	log.Printf("Published event to %s: partition=%d, offset=%d, event_type=%s, request_id=%s",
+		topic, partition, offset, event.eventType(), tracing.RequestIDFromContext(ctx))
 
 	return nil
 }
@@ -0,0 +1,69 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"gin-quickstart/metrics"
+
+	"github.com/IBM/sarama"
+)
+
+// Healthy reports whether the last health probe or send reached the brokers
+func (kp *KafkaProducer) Healthy() bool {
+	return kp.connected.Load()
+}
+
+// StartHealthMonitor probes the brokers every interval until ctx is cancelled,
+// so a lost connection shows up in logs and metrics before sends start failing
+func (kp *KafkaProducer) StartHealthMonitor(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := kp.Ping(ctx)
+			kp.setConnected(err == nil, err)
+		}
+	}
+}
+
+// observeSend updates the connection state from a send result; only
+// connectivity errors mark the producer down
+func (kp *KafkaProducer) observeSend(err error) {
+	switch {
+	case err == nil:
+		kp.setConnected(true, nil)
+	case errors.Is(err, sarama.ErrOutOfBrokers),
+		errors.Is(err, sarama.ErrNotConnected),
+		errors.Is(err, sarama.ErrClosedClient):
+		kp.setConnected(false, err)
+	}
+}
+
+// setConnected records the connection state and logs transitions
+func (kp *KafkaProducer) setConnected(connected bool, err error) {
+	if connected {
+		metrics.KafkaProducerConnected.Set(1)
+	} else {
+		metrics.KafkaProducerConnected.Set(0)
+	}
+
+	if kp.connected.Swap(connected) == connected {
+		return
+	}
+	if connected {
+		log.Println("Kafka producer connection restored")
+	} else {
+		log.Printf("Kafka producer lost connection to brokers: %v", err)
+	}
+}
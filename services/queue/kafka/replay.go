@@ -0,0 +1,230 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"gin-quickstart/models"
+	"gin-quickstart/services"
+
+	"github.com/IBM/sarama"
+)
+
+// Replay bounds
+const (
+	defaultReplayLimit = 1000
+	maxReplayLimit     = 10000
+	replayFetchTimeout = 10 * time.Second
+)
+
+// Replay reads an offset range of a consumed topic and runs each message
+// through the queue handlers. In dry-run mode nothing is written and each
+// message reports what applying it would do. Replays bypass the processed
+// event check, so pause the consumer before applying to avoid racing it.
+func (kc *KafkaConsumer) Replay(ctx context.Context, req *models.ReplayEventsRequest) (*models.ReplayReport, error) {
+	if req.Topic == "" || !slices.Contains(kc.topics.Consumed(), req.Topic) {
+		return nil, fmt.Errorf("%w: topic %q is not consumed by this service", services.ErrInvalidReplay, req.Topic)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultReplayLimit
+	}
+	if limit > maxReplayLimit {
+		limit = maxReplayLimit
+	}
+
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V3_0_0_0
+	saramaConfig.Consumer.Return.Errors = true
+
+	client, err := sarama.NewClient(kc.brokers, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+	defer client.Close()
+
+	oldest, err := client.GetOffset(req.Topic, req.Partition, sarama.OffsetOldest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get oldest offset: %w", err)
+	}
+	newest, err := client.GetOffset(req.Topic, req.Partition, sarama.OffsetNewest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newest offset: %w", err)
+	}
+	if req.StartOffset < oldest {
+		return nil, fmt.Errorf("%w: offset %d is no longer retained; oldest is %d", services.ErrInvalidReplay, req.StartOffset, oldest)
+	}
+
+	end := newest - 1
+	if req.EndOffset != nil && *req.EndOffset < end {
+		end = *req.EndOffset
+	}
+	if end-req.StartOffset+1 > int64(limit) {
+		end = req.StartOffset + int64(limit) - 1
+	}
+
+	report := &models.ReplayReport{
+		Topic:       req.Topic,
+		Partition:   req.Partition,
+		StartOffset: req.StartOffset,
+		EndOffset:   end,
+		Apply:       req.Apply,
+		Messages:    []models.ReplayedMessage{},
+	}
+	if end < req.StartOffset {
+		return report, nil
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consumer: %w", err)
+	}
+	defer consumer.Close()
+
+	pc, err := consumer.ConsumePartition(req.Topic, req.Partition, req.StartOffset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume partition: %w", err)
+	}
+	defer pc.Close()
+
+	// Handlers outlive the request (e.g. publishing created events)
+	handlerCtx := context.WithoutCancel(ctx)
+
+	for {
+		select {
+		case message := <-pc.Messages():
+			if message == nil {
+				return report, nil
+			}
+
+			result := kc.replayMessage(handlerCtx, message, req.Apply)
+			report.Processed++
+			if result.Error != "" {
+				report.Failed++
+			}
+			report.Messages = append(report.Messages, result)
+
+			if message.Offset >= end {
+				log.Printf("Replayed %s[%d] offsets %d-%d: apply=%t, processed=%d, failed=%d",
+					req.Topic, req.Partition, req.StartOffset, end, req.Apply, report.Processed, report.Failed)
+				return report, nil
+			}
+
+		case consumerErr := <-pc.Errors():
+			return nil, fmt.Errorf("failed to read partition: %w", consumerErr)
+
+		case <-time.After(replayFetchTimeout):
+			return nil, fmt.Errorf("timed out waiting for messages after offset %d", req.StartOffset+int64(report.Processed)-1)
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// replayMessage describes and, when apply is set, handles one message
+func (kc *KafkaConsumer) replayMessage(ctx context.Context, message *sarama.ConsumerMessage, apply bool) models.ReplayedMessage {
+	result := models.ReplayedMessage{Offset: message.Offset}
+	if message.Key != nil {
+		key := string(message.Key)
+		result.Key = &key
+	}
+
+	action, err := kc.planMessage(ctx, message)
+	if err != nil {
+		result.Action = "skip"
+		result.Error = err.Error()
+		return result
+	}
+	result.Action = action
+
+	if apply {
+		if err := kc.dispatch(ctx, message); err != nil {
+			result.Error = err.Error()
+		}
+	}
+	return result
+}
+
+// planMessage decodes a message and describes what handling it would change
+func (kc *KafkaConsumer) planMessage(ctx context.Context, message *sarama.ConsumerMessage) (string, error) {
+	inbound, err := kc.unwrap(message)
+	if err != nil {
+		return "", err
+	}
+
+	switch inbound.Type {
+	case eventTypeOrderCreated:
+		event, err := kc.decodeOrderCreated(inbound.Payload)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode order created event: %w", err)
+		}
+		if entry, err := kc.queueService.GetQueueEntryByOrderID(ctx, event.OrderID); err == nil {
+			return fmt.Sprintf("no change: order %s already queued as %s", event.OrderID, entry.TokenNumber), nil
+		}
+		return fmt.Sprintf("enqueue order %s", event.OrderID), nil
+
+	case eventTypeOrderStatusChanged:
+		event, err := kc.decodeOrderStatus(inbound.Payload)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode order status event: %w", err)
+		}
+		queueStatus := mapOrderStatusToQueueStatus(event.Status)
+		if queueStatus == "" {
+			return fmt.Sprintf("no change: order status %s has no queue status", event.Status), nil
+		}
+		entry, err := kc.queueService.GetQueueEntryByOrderID(ctx, event.OrderID)
+		if err != nil {
+			return fmt.Sprintf("no change: order %s is not queued", event.OrderID), nil
+		}
+		if entry.Status == queueStatus {
+			return fmt.Sprintf("no change: %s already %s", entry.TokenNumber, queueStatus), nil
+		}
+		return fmt.Sprintf("set %s status %s -> %s", entry.TokenNumber, entry.Status, queueStatus), nil
+
+	case eventTypeOrderCancelled:
+		event, err := kc.decodeOrderCancelled(inbound.Payload)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode order cancelled event: %w", err)
+		}
+		entry, err := kc.queueService.GetQueueEntryByOrderID(ctx, event.OrderID)
+		if err != nil {
+			return fmt.Sprintf("no change: order %s is not queued", event.OrderID), nil
+		}
+		if services.IsFinalStatus(entry.Status) {
+			return fmt.Sprintf("no change: %s already %s", entry.TokenNumber, entry.Status), nil
+		}
+		return fmt.Sprintf("cancel %s (%s)", entry.TokenNumber, entry.Status), nil
+
+	case eventTypePaymentCompleted:
+		event, err := kc.decodePaymentCompleted(inbound.Payload)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode payment completed event: %w", err)
+		}
+		entry, err := kc.queueService.GetQueueEntryByOrderID(ctx, event.OrderID)
+		if err != nil {
+			return fmt.Sprintf("no change: order %s is not queued", event.OrderID), nil
+		}
+		if entry.Status != "PENDING_PAYMENT" {
+			return fmt.Sprintf("no change: %s already %s", entry.TokenNumber, entry.Status), nil
+		}
+		return fmt.Sprintf("activate %s", entry.TokenNumber), nil
+
+	case eventTypeMenuUpdated:
+		event, err := kc.decodeMenuUpdated(inbound.Payload)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode menu updated event: %w", err)
+		}
+		if event.Deleted || event.PreparationTime == nil {
+			return fmt.Sprintf("invalidate prep time of %s", event.MenuItemID), nil
+		}
+		return fmt.Sprintf("set prep time of %s to %d min", event.MenuItemID, *event.PreparationTime), nil
+
+	default:
+		return "", fmt.Errorf("%w: %s v%d", errUnsupportedEvent, inbound.Type, inbound.Version)
+	}
+}
@@ -0,0 +1,69 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gin-quickstart/database"
+
+	"github.com/IBM/sarama"
+)
+
+// retryPolicy bounds in-process retries of a single message
+type retryPolicy struct {
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// backoff returns the delay before the given retry (1-based), doubling each time
+func (p retryPolicy) backoff(retry int) time.Duration {
+	delay := p.initialBackoff
+	for i := 1; i < retry; i++ {
+		delay *= 2
+		if p.maxBackoff > 0 && delay >= p.maxBackoff {
+			return p.maxBackoff
+		}
+	}
+	return delay
+}
+
+// errSessionEnded is returned when the session ends while a message waits for a retry;
+// the message is left unmarked so the partition's next owner redelivers it
+var errSessionEnded = errors.New("consumer session ended before retry")
+
+// handleWithRetry handles a message, retrying transient failures with exponential backoff.
+// It returns the number of attempts made and the last error, if any.
+func (kc *KafkaConsumer) handleWithRetry(ctx context.Context, message *sarama.ConsumerMessage) (int, error) {
+	attempts := 0
+	for {
+		attempts++
+		err := kc.handleMessage(message)
+		if err == nil || !isTransientError(err) || attempts > kc.retry.maxRetries {
+			return attempts, err
+		}
+
+		delay := kc.retry.backoff(attempts)
+		log.Printf("Transient error handling message (topic=%s, offset=%d, attempt=%d), retrying in %s: %v",
+			message.Topic, message.Offset, attempts, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return attempts, fmt.Errorf("%w: %v", errSessionEnded, err)
+		}
+	}
+}
+
+// errOrderNotQueued is returned when an event for an order arrives before its
+// order.created; retrying gives the other topic time to catch up
+var errOrderNotQueued = errors.New("order not queued yet")
+
+// isTransientError reports whether an error is likely to succeed on retry
+func isTransientError(err error) bool {
+	return errors.Is(err, errOrderNotQueued) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, database.ErrUnavailable) || database.IsTransientError(err)
+}
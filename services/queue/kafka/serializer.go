@@ -0,0 +1,158 @@
+package kafka
+
+import (
+	"context"
+	"embed"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gin-quickstart/config"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/registry"
+	"google.golang.org/protobuf/proto"
+)
+
+// Supported event encodings
+const (
+	EncodingJSON     = "json"
+	EncodingAvro     = "avro"
+	EncodingProtobuf = "protobuf"
+)
+
+//go:embed schemas/*.avsc
+var schemaFiles embed.FS
+
+// Content types advertised in the content-type header
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeAvro     = "application/vnd.confluent.avro"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// Serializer encodes typed events for a topic
+type Serializer interface {
+	Serialize(ctx context.Context, topic string, meta EventMeta, event Event) ([]byte, error)
+	ContentType() string
+}
+
+// NewSerializer builds the serializer selected by KAFKA_EVENT_ENCODING
+func NewSerializer(cfg *config.Config) (Serializer, error) {
+	switch strings.ToLower(cfg.KafkaEventEncoding) {
+	case "", EncodingJSON:
+		return jsonSerializer{}, nil
+	case EncodingAvro:
+		return newAvroSerializer(cfg)
+	case EncodingProtobuf:
+		return protobufSerializer{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported event encoding %q", cfg.KafkaEventEncoding)
+	}
+}
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Serialize(_ context.Context, _ string, meta EventMeta, event Event) ([]byte, error) {
+	return marshalEnvelope(meta, event)
+}
+
+func (jsonSerializer) ContentType() string { return ContentTypeJSON }
+
+type protobufSerializer struct{}
+
+func (protobufSerializer) Serialize(_ context.Context, _ string, meta EventMeta, event Event) ([]byte, error) {
+	msg := event.protoMessage()
+	stampProtoEnvelope(msg, meta)
+	return proto.Marshal(msg)
+}
+
+func (protobufSerializer) ContentType() string { return ContentTypeProtobuf }
+
+// avroSerializer writes the Confluent wire format: magic byte, schema ID, Avro body.
+// Schemas are registered on first use per subject using the TopicRecordNameStrategy,
+// since several event types share a topic.
+type avroSerializer struct {
+	client *registry.Client
+
+	mu         sync.RWMutex
+	registered map[string]registeredSchema
+}
+
+type registeredSchema struct {
+	id     int
+	schema avro.Schema
+}
+
+func newAvroSerializer(cfg *config.Config) (*avroSerializer, error) {
+	if cfg.SchemaRegistryURL == "" {
+		return nil, fmt.Errorf("SCHEMA_REGISTRY_URL is required for avro encoding")
+	}
+
+	var opts []registry.ClientFunc
+	if cfg.SchemaRegistryUsername != "" {
+		opts = append(opts, registry.WithBasicAuth(cfg.SchemaRegistryUsername, cfg.SchemaRegistryPassword))
+	}
+
+	client, err := registry.NewClient(cfg.SchemaRegistryURL, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create schema registry client: %w", err)
+	}
+
+	return &avroSerializer{
+		client:     client,
+		registered: make(map[string]registeredSchema),
+	}, nil
+}
+
+// Serialize encodes the event record itself; the envelope metadata travels in headers
+func (s *avroSerializer) Serialize(ctx context.Context, topic string, _ EventMeta, event Event) ([]byte, error) {
+	registered, err := s.schemaFor(ctx, topic, event.schemaName())
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := avro.Marshal(registered.schema, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s as avro: %w", event.schemaName(), err)
+	}
+
+	data := make([]byte, 5, 5+len(body))
+	binary.BigEndian.PutUint32(data[1:5], uint32(registered.id))
+	return append(data, body...), nil
+}
+
+func (s *avroSerializer) ContentType() string { return ContentTypeAvro }
+
+func (s *avroSerializer) schemaFor(ctx context.Context, topic, name string) (registeredSchema, error) {
+	s.mu.RLock()
+	registered, ok := s.registered[topic+"/"+name]
+	s.mu.RUnlock()
+	if ok {
+		return registered, nil
+	}
+
+	definition, err := schemaFiles.ReadFile("schemas/" + name + ".avsc")
+	if err != nil {
+		return registeredSchema{}, fmt.Errorf("no avro schema for %s: %w", name, err)
+	}
+
+	schema, err := avro.Parse(string(definition))
+	if err != nil {
+		return registeredSchema{}, fmt.Errorf("invalid avro schema for %s: %w", name, err)
+	}
+
+	subject := topic + "-" + schema.(avro.NamedSchema).FullName()
+	id, _, err := s.client.CreateSchema(ctx, subject, string(definition))
+	if err != nil {
+		return registeredSchema{}, fmt.Errorf("failed to register schema %s: %w", subject, err)
+	}
+
+	registered = registeredSchema{id: id, schema: schema}
+	s.mu.Lock()
+	s.registered[topic+"/"+name] = registered
+	s.mu.Unlock()
+
+	return registered, nil
+}
@@ -0,0 +1,100 @@
+package kafka
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"gin-quickstart/config"
+
+	"github.com/IBM/sarama"
+)
+
+// Topics holds the configured topic names
+type Topics struct {
+	OrderCreated       string
+	OrderStatusChanged string
+	OrderCancelled     string
+	PaymentCompleted   string
+	MenuUpdated        string
+	TokenRevoked       string
+	QueueEvents        string
+	NotificationEvents string
+	KitchenEvents      string
+	DeadLetter         string
+}
+
+// TopicsFromConfig reads the topic names from cfg
+func TopicsFromConfig(cfg *config.Config) Topics {
+	return Topics{
+		OrderCreated:       cfg.KafkaTopicOrderCreated,
+		OrderStatusChanged: cfg.KafkaTopicOrderStatusChanged,
+		OrderCancelled:     cfg.KafkaTopicOrderCancelled,
+		PaymentCompleted:   cfg.KafkaTopicPaymentCompleted,
+		MenuUpdated:        cfg.KafkaTopicMenuUpdated,
+		TokenRevoked:       cfg.KafkaTopicTokenRevoked,
+		QueueEvents:        cfg.KafkaTopicQueueEvents,
+		NotificationEvents: cfg.KafkaTopicNotificationEvents,
+		KitchenEvents:      cfg.KafkaTopicKitchenEvents,
+		DeadLetter:         cfg.KafkaTopicDeadLetter,
+	}
+}
+
+// Consumed returns the topics the consumer subscribes to
+func (t Topics) Consumed() []string {
+	return []string{t.OrderCreated, t.OrderStatusChanged, t.OrderCancelled, t.PaymentCompleted, t.MenuUpdated, t.TokenRevoked}
+}
+
+// All returns every topic the service reads or writes
+func (t Topics) All() []string {
+	return []string{t.OrderCreated, t.OrderStatusChanged, t.OrderCancelled, t.PaymentCompleted, t.MenuUpdated, t.TokenRevoked, t.QueueEvents, t.NotificationEvents, t.KitchenEvents, t.DeadLetter}
+}
+
+// EnsureTopics creates any configured topic missing from the cluster, using
+// the configured partition count, replication factor and retention
+func EnsureTopics(cfg *config.Config) error {
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Version = sarama.V3_0_0_0
+
+	admin, err := sarama.NewClusterAdmin(cfg.KafkaBrokers, saramaConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create cluster admin: %w", err)
+	}
+	defer admin.Close()
+
+	existing, err := admin.ListTopics()
+	if err != nil {
+		return fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	retentionMs := strconv.FormatInt(int64(cfg.KafkaTopicRetentionHours)*3600*1000, 10)
+	detail := &sarama.TopicDetail{
+		NumPartitions:     int32(cfg.KafkaTopicPartitions),
+		ReplicationFactor: int16(cfg.KafkaTopicReplicationFactor),
+		ConfigEntries: map[string]*string{
+			"retention.ms": &retentionMs,
+		},
+	}
+
+	seen := make(map[string]bool)
+	for _, topic := range TopicsFromConfig(cfg).All() {
+		if topic == "" || seen[topic] {
+			continue
+		}
+		seen[topic] = true
+
+		if _, ok := existing[topic]; ok {
+			continue
+		}
+
+		// Another instance may create the topic between listing and creating it
+		if err := admin.CreateTopic(topic, detail, false); err != nil && !errors.Is(err, sarama.ErrTopicAlreadyExists) {
+			return fmt.Errorf("failed to create topic %s: %w", topic, err)
+		}
+		log.Printf("Created Kafka topic %s (partitions=%d, replication=%d, retention=%dh)",
+			topic, cfg.KafkaTopicPartitions, cfg.KafkaTopicReplicationFactor, cfg.KafkaTopicRetentionHours)
+	}
+
+	return nil
+}
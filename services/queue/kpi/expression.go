@@ -0,0 +1,287 @@
+// Package kpi evaluates operator-defined KPI expressions.
+//
+// Expressions are plain arithmetic over named counters, for example
+// "express_completed / completed * 100". Supported syntax: numbers,
+// identifiers, + - * /, unary minus and parentheses.
+package kpi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"unicode"
+)
+
+// ErrDivisionByZero is returned when an expression divides by zero
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Expression is a parsed KPI expression
+type Expression struct {
+	source string
+	root   node
+}
+
+// Parse parses an expression, returning a descriptive error on bad syntax
+func Parse(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("expression is empty")
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.tokens[p.pos].text, p.tokens[p.pos].offset)
+	}
+
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the original expression text
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Variables returns the distinct counter names used by the expression, sorted
+func (e *Expression) Variables() []string {
+	seen := make(map[string]bool)
+	e.root.collect(seen)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Eval evaluates the expression against the given counter values
+func (e *Expression) Eval(vars map[string]float64) (float64, error) {
+	return e.root.eval(vars)
+}
+
+type node interface {
+	eval(vars map[string]float64) (float64, error)
+	collect(seen map[string]bool)
+}
+
+type numberNode float64
+
+func (n numberNode) eval(map[string]float64) (float64, error) { return float64(n), nil }
+func (n numberNode) collect(map[string]bool)                  {}
+
+type variableNode string
+
+func (n variableNode) eval(vars map[string]float64) (float64, error) {
+	value, ok := vars[string(n)]
+	if !ok {
+		return 0, fmt.Errorf("unknown counter %q", string(n))
+	}
+	return value, nil
+}
+
+func (n variableNode) collect(seen map[string]bool) { seen[string(n)] = true }
+
+type negateNode struct{ operand node }
+
+func (n negateNode) eval(vars map[string]float64) (float64, error) {
+	value, err := n.operand.eval(vars)
+	return -value, err
+}
+
+func (n negateNode) collect(seen map[string]bool) { n.operand.collect(seen) }
+
+type binaryNode struct {
+	op          byte
+	left, right node
+}
+
+func (n binaryNode) eval(vars map[string]float64) (float64, error) {
+	left, err := n.left.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+
+	switch n.op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	case '/':
+		if right == 0 {
+			return 0, ErrDivisionByZero
+		}
+		return left / right, nil
+	}
+	return 0, fmt.Errorf("unknown operator %q", n.op)
+}
+
+func (n binaryNode) collect(seen map[string]bool) {
+	n.left.collect(seen)
+	n.right.collect(seen)
+}
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+func tokenize(source string) ([]token, error) {
+	runes := []rune(source)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), offset: start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), offset: start})
+		case r == '+' || r == '-' || r == '*' || r == '/':
+			tokens = append(tokens, token{kind: tokenOperator, text: string(r), offset: i})
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", offset: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", offset: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+		}
+	}
+
+	return tokens, nil
+}
+
+// parser is a recursive-descent parser:
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = "-" unary | primary
+//	primary = number | identifier | "(" sum ")"
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() *token {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok == nil || tok.kind != tokenOperator || (tok.text != "+" && tok.text != "-") {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: tok.text[0], left: left, right: right}
+	}
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok == nil || tok.kind != tokenOperator || (tok.text != "*" && tok.text != "/") {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: tok.text[0], left: left, right: right}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if tok := p.peek(); tok != nil && tok.kind == tokenOperator && tok.text == "-" {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negateNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.peek()
+	if tok == nil {
+		return nil, errors.New("unexpected end of expression")
+	}
+	p.pos++
+
+	switch tok.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.offset)
+		}
+		return numberNode(value), nil
+	case tokenIdent:
+		return variableNode(tok.text), nil
+	case tokenLParen:
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != tokenRParen {
+			return nil, fmt.Errorf("missing closing parenthesis for '(' at position %d", tok.offset)
+		}
+		p.pos++
+		return inner, nil
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.offset)
+}
@@ -1,17 +1,33 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"gin-quickstart/auth"
+	"gin-quickstart/clock"
 	"gin-quickstart/config"
 	"gin-quickstart/database"
 	"gin-quickstart/grpc"
+	"gin-quickstart/health"
+	"gin-quickstart/i18n"
 	"gin-quickstart/kafka"
+	"gin-quickstart/middleware"
+	"gin-quickstart/mtls"
+	"gin-quickstart/notify"
+	"gin-quickstart/realtime"
 	"gin-quickstart/routes"
 	"gin-quickstart/services"
+	"gin-quickstart/tracing"
+	"gin-quickstart/users"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -25,53 +41,196 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(cfg)
+	if err != nil {
+		log.Printf("Warning: Failed to initialize tracing: %v", err)
+		shutdownTracing = func(context.Context) error { return nil }
+	}
+
+	// Select the service clock (simulated time for standalone/test deployments)
+	serviceClock, err := newClock(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize clock: %v", err)
+	}
+	services.SetClock(serviceClock)
+
+	// Business days follow the restaurant's time zone (validated above)
+	zone, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		log.Fatalf("Failed to load time zone: %v", err)
+	}
+	clock.SetZone(zone)
 
 	// Initialize database
 	if err := database.InitDB(cfg); err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer database.Close()
+	health.Register("mysql", true, database.Ping)
 
 	// Initialize Redis
 	if err := database.InitRedis(cfg); err != nil {
 		log.Fatalf("Failed to initialize Redis: %v", err)
 	}
 	defer database.CloseRedis()
+	health.Register("redis", true, database.PingRedis)
+	if err := realtime.Configure(cfg); err != nil {
+		log.Fatalf("Failed to configure real-time updates: %v", err)
+	}
 
 	// Initialize gRPC Menu Service client
 	menuClient, err := grpc.NewMenuClient(cfg)
 	if err != nil {
 		log.Printf("Warning: Failed to initialize Menu Service client: %v", err)
+		health.Register("menu_grpc", false, failedCheck(err))
 	} else {
 		defer menuClient.Close()
+		health.Register("menu_grpc", false, menuClient.Ping)
+		services.SetPrepTimeProvider(menuClient)
 		log.Println("Menu Service gRPC client initialized")
+
+		// Warm the prep-time cache without holding up startup
+		if cfg.MenuPrefetchOnStartup {
+			go func() {
+				count, err := menuClient.PrefetchPreparationTimes(context.Background())
+				if err != nil {
+					log.Printf("Warning: Failed to prefetch menu prep times: %v", err)
+					return
+				}
+				log.Printf("Prefetched prep times for %d menu items", count)
+			}()
+		}
+	}
+
+	// Create missing Kafka topics
+	if cfg.KafkaAutoCreateTopics {
+		if err := kafka.EnsureTopics(cfg); err != nil {
+			log.Printf("Warning: Failed to provision Kafka topics: %v", err)
+		}
 	}
 
 	// Initialize Kafka Producer
 	kafkaProducer, err := kafka.NewKafkaProducer(cfg)
 	if err != nil {
 		log.Printf("Warning: Failed to initialize Kafka producer: %v", err)
+		health.Register("kafka", false, failedCheck(err))
 	} else {
 		defer kafkaProducer.Close()
+		health.Register("kafka", false, kafkaProducer.Ping)
+		services.SetEventPublisher(kafkaProducer)
 		log.Println("Kafka producer initialized")
 	}
 
+	// Responses and notifications fall back to DEFAULT_LANGUAGE
+	i18n.SetDefaultLanguage(cfg.DefaultLanguage)
+
+	// Initialize customer notification channels (SMS, email, push)
+	dispatcher, err := notify.NewDispatcher(cfg)
+	if err != nil {
+		log.Fatalf("Failed to initialize notifications: %v", err)
+	}
+	if dispatcher != nil {
+		services.SetNotifier(dispatcher)
+		log.Println("Customer notifications initialized")
+	}
+
+	// Predict waits from completed entries, falling back to the linear formula
+	if cfg.WaitPredictor == "regression" {
+		services.SetWaitPredictor(services.NewRegressionWaitPredictor(cfg.WaitModelMinSamples))
+	}
+
 	// Initialize Queue Service
+	services.SetUserEntryLimits(cfg.MaxActiveEntriesPerUser, cfg.MaxEntriesPerUserPerMinute)
+	services.SetLookupCodeSecret(cfg.LookupCodeSecret, cfg.LookupCodeRequired)
 	queueService := services.NewQueueService()
 
+	// Start background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go queueService.StartTombstonePurger(workerCtx, time.Duration(cfg.TombstoneRetentionHours)*time.Hour, time.Hour)
+	go queueService.StartProcessedEventPurger(workerCtx, time.Duration(cfg.ProcessedEventRetentionHours)*time.Hour, time.Hour)
+	if cfg.EntryArchiveAfterDays > 0 {
+		go queueService.StartEntryArchiver(workerCtx, time.Duration(cfg.EntryArchiveAfterDays)*24*time.Hour, time.Hour)
+	}
+	go queueService.StartStatsFlusher(workerCtx, time.Duration(cfg.StatsFlushIntervalSeconds)*time.Second)
+	go queueService.StartStatsReconciler(workerCtx, cfg.StatsReconcileHour, cfg.StatsReconcileDays)
+	go queueService.StartLoadSampler(workerCtx, time.Duration(cfg.LoadSampleIntervalSeconds)*time.Second, time.Duration(cfg.LoadSampleRetentionDays)*24*time.Hour)
+	go queueService.StartTokenCounterPersister(workerCtx, time.Duration(cfg.TokenCounterPersistIntervalSeconds)*time.Second)
+	if cfg.DayRolloverEnabled {
+		go queueService.StartDayRollover(workerCtx, time.Duration(cfg.DayRolloverCheckIntervalSeconds)*time.Second, time.Duration(cfg.DayRolloverGraceMinutes)*time.Minute, cfg.DayRolloverRotateTokenPrefix)
+	}
+	go queueService.StartConfigReloader(workerCtx, time.Duration(cfg.ConfigLocalCacheSeconds)*time.Second)
+	go queueService.StartActiveSnapshotRefresher(workerCtx)
+	go queueService.StartSkipRestorer(workerCtx, time.Duration(cfg.SkipRestoreIntervalSeconds)*time.Second)
+	go queueService.StartScheduledActivator(workerCtx, time.Duration(cfg.ScheduledActivationIntervalSeconds)*time.Second)
+	go queueService.StartSLAMonitor(workerCtx, time.Duration(cfg.SLACheckIntervalSeconds)*time.Second)
+	go queueService.StartWaitModelTrainer(workerCtx, time.Duration(cfg.WaitModelTrainIntervalSeconds)*time.Second, time.Duration(cfg.WaitModelLookbackDays)*24*time.Hour)
+	go queueService.StartWebhookDispatcher(workerCtx, time.Duration(cfg.WebhookDispatchIntervalSeconds)*time.Second, time.Duration(cfg.WebhookTimeoutSeconds)*time.Second, cfg.WebhookMaxAttempts)
+
+	// Fan queue updates from Redis out to this instance's WebSocket/SSE clients
+	hub := realtime.NewHub()
+	realtime.SetHub(hub)
+	go hub.Run(workerCtx)
+	if cfg.IntegrityCheckOnStartup {
+		go queueService.RunStartupIntegrityCheck(workerCtx, cfg.IntegrityRepairOnStartup)
+	}
+
+	// Share the producer with the consumer instead of dialing per event
+	var entryPublisher kafka.EntryEventPublisher
+	if kafkaProducer != nil {
+		entryPublisher = kafkaProducer
+		go kafkaProducer.StartHealthMonitor(workerCtx, time.Duration(cfg.KafkaProducerHealthIntervalSeconds)*time.Second)
+	}
+
+	// Keep the menu client's prep-time cache fresh from menu.updated
+	var prepTimes kafka.PrepTimeStore
+	if menuClient != nil {
+		prepTimes = menuClient
+	}
+
+	// Queue customers of a VIP tier as VIP
+	var customerTiers kafka.CustomerTierSource
+	if cfg.VIPDetectionEnabled {
+		customerTiers = users.NewClient(cfg)
+		log.Printf("VIP detection enabled (tiers from %s)", cfg.UserServiceURL)
+	}
+
 	// Initialize and start Kafka Consumer
-	kafkaConsumer, err := kafka.NewKafkaConsumer(cfg, queueService)
+	kafkaConsumer, err := kafka.NewKafkaConsumer(cfg, queueService, entryPublisher, prepTimes, customerTiers)
 	if err != nil {
 		log.Printf("Warning: Failed to initialize Kafka consumer: %v", err)
 	} else {
+		services.SetEventReplayer(kafkaConsumer)
 		if err := kafkaConsumer.Start(); err != nil {
 			log.Printf("Warning: Failed to start Kafka consumer: %v", err)
 		} else {
 			defer kafkaConsumer.Stop()
+			services.SetConsumerController(kafkaConsumer)
 			log.Println("Kafka consumer started successfully")
 		}
 	}
 
+	// Serve the Queue API to other services over gRPC
+	var grpcServer *grpc.Server
+	if cfg.GRPCPort != "" {
+		grpcServer, err = grpc.Serve(cfg, queueService)
+		if err != nil {
+			log.Printf("Warning: Failed to start gRPC server: %v", err)
+		}
+	}
+	var grpcGateway *grpc.Gateway
+	if grpcServer != nil && cfg.GRPCGatewayPort != "" {
+		grpcGateway, err = grpc.ServeGateway(cfg)
+		if err != nil {
+			log.Printf("Warning: Failed to start gRPC gateway: %v", err)
+		}
+	}
+
 	// Set Gin mode
 	if os.Getenv("GIN_MODE") == "" {
 		gin.SetMode(gin.ReleaseMode)
@@ -79,10 +238,36 @@ func main() {
 
 	// Create router
 	router := gin.Default()
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
+	middleware.SetForwardedForTrusted(len(cfg.TrustedProxies) > 0)
 
 	// Setup routes
+	middleware.SetIdempotencyTTL(time.Duration(cfg.IdempotencyKeyTTLSeconds) * time.Second)
+	middleware.SetLookupRateLimit(cfg.LookupRateLimitPerMinute)
+	if cfg.AuditLogEnabled {
+		middleware.SetAuditRecorder(queueService)
+	}
+	if err := middleware.SetAdminNetworks(cfg.AdminAllowedNetworks); err != nil {
+		log.Fatalf("Invalid admin networks: %v", err)
+	}
+	middleware.SetCompression(cfg.CompressionEnabled, cfg.CompressionMinBytes)
+	middleware.SetRequestTimeouts(time.Duration(cfg.RequestTimeoutSeconds)*time.Second, time.Duration(cfg.LongRequestTimeoutSeconds)*time.Second)
+	if strings.EqualFold(cfg.AuthMode, auth.ModeIntrospect) {
+		middleware.SetTokenIntrospector(auth.NewIntrospector(cfg))
+		log.Printf("Validating tokens with the auth service at %s", cfg.AuthServiceURL)
+	}
 	routes.SetupRoutes(router)
 
+	server := &http.Server{Addr: ":" + cfg.Port, Handler: router}
+	if cfg.TLSCertFile != "" {
+		server.TLSConfig, err = mtls.ServerConfig(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile)
+		if err != nil {
+			log.Fatalf("Failed to configure TLS: %v", err)
+		}
+	}
+
 	// Graceful shutdown
 	sigint := make(chan os.Signal, 1)
 	signal.Notify(sigint, os.Interrupt, syscall.SIGTERM)
@@ -96,10 +281,32 @@ func main() {
 		log.Println("  ✓ Redis real-time cache")
 		log.Println("  ✓ Kafka event streaming")
 		log.Println("  ✓ gRPC Menu Service client")
+		if grpcServer != nil {
+			log.Println("  ✓ gRPC Queue API")
+		}
+		if grpcGateway != nil {
+			log.Println("  ✓ REST gateway for the gRPC Queue API")
+		}
 		log.Println("  ✓ Token-based queue system")
 		log.Println("  ✓ Real-time position tracking")
+		log.Println("  ✓ Prometheus metrics (/metrics)")
+		if cfg.TracingEnabled {
+			log.Println("  ✓ OpenTelemetry tracing")
+		}
+		if server.TLSConfig != nil && server.TLSConfig.ClientCAs != nil {
+			log.Println("  ✓ Mutual TLS (client certificates required)")
+		} else if server.TLSConfig != nil {
+			log.Println("  ✓ TLS")
+		}
 		
-		if err := router.Run(":" + port); err != nil {
+		var err error
+		if server.TLSConfig != nil {
+			// The certificate is already in TLSConfig
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("Failed to start server: %v", err)
 		}
 	}()
@@ -109,6 +316,15 @@ func main() {
 	log.Println("🛑 Shutting down server...")
 
 	// Cleanup
+	stopWorkers()
+	if grpcGateway != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		grpcGateway.Shutdown(shutdownCtx)
+		cancel()
+	}
+	if grpcServer != nil {
+		grpcServer.Shutdown()
+	}
 	if kafkaConsumer != nil {
 		kafkaConsumer.Stop()
 	}
@@ -120,7 +336,35 @@ func main() {
 	}
 	database.CloseRedis()
 	database.Close()
+	shutdownTracing(context.Background())
 
 	log.Println("✅ Server stopped gracefully")
 	os.Exit(0)
-}
\ No newline at end of file
+}
+// failedCheck reports a dependency that could not be initialized at startup
+func failedCheck(initErr error) health.CheckFunc {
+	return func(context.Context) error {
+		return initErr
+	}
+}
+
+// newClock returns the real clock, or a simulated one when CLOCK_MODE=simulated
+func newClock(cfg *config.Config) (clock.Clock, error) {
+	switch cfg.ClockMode {
+	case "", "real":
+		return clock.Real(), nil
+	case "simulated":
+		start := time.Now().UTC()
+		if cfg.ClockSimulatedStart != "" {
+			parsed, err := time.Parse(time.RFC3339, cfg.ClockSimulatedStart)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CLOCK_SIMULATED_START: %w", err)
+			}
+			start = parsed
+		}
+		log.Printf("Using simulated clock starting at %s", start.Format(time.RFC3339))
+		return clock.NewSimulated(start), nil
+	default:
+		return nil, fmt.Errorf("unsupported CLOCK_MODE %q", cfg.ClockMode)
+	}
+}
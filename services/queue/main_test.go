@@ -2,19 +2,57 @@ package main
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"gin-quickstart/auth"
+	"gin-quickstart/clock"
 	"gin-quickstart/config"
 	"gin-quickstart/database"
+	queuegrpc "gin-quickstart/grpc"
+	"gin-quickstart/handlers"
+	"gin-quickstart/kafka"
+	"gin-quickstart/middleware"
+	"gin-quickstart/models"
+	"gin-quickstart/mtls"
+	"gin-quickstart/notify"
+	eventspb "gin-quickstart/proto/events"
+	queuepb "gin-quickstart/proto/queue"
 	"gin-quickstart/routes"
+	"gin-quickstart/services"
 
+	"github.com/IBM/sarama"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 var router *gin.Engine
@@ -31,6 +69,159 @@ func setupTestDB() {
 	database.InitRedis(cfg)
 }
 
+// setupTestRedis points the service at an in-memory Redis for one test
+func setupTestRedis(t *testing.T) *miniredis.Miniredis {
+	server := miniredis.RunT(t)
+	previous := database.RedisClient
+	database.RedisClient = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		database.RedisClient.Close()
+		database.RedisClient = previous
+	})
+	return server
+}
+
+// testToken returns a token for a user with role, as AuthMiddleware decodes
+// it when tokens aren't introspected
+func testToken(userID, role string) string {
+	claims, _ := json.Marshal(map[string]interface{}{"id": userID, "name": "Test User", "role": role})
+	return "e30." + base64.RawURLEncoding.EncodeToString(claims) + ".signature"
+}
+
+// serveJSON sends a request with body as JSON (none when nil) to the test
+// router, as a user with role unless role is empty
+func serveJSON(method, path string, body interface{}, role string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		jsonData, _ := json.Marshal(body)
+		reader = bytes.NewReader(jsonData)
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if role != "" {
+		req.Header.Set("Authorization", "Bearer "+testToken(role+"-1", role))
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// rawPublisher records the messages published with PublishRaw; other
+// events aren't expected
+type rawPublisher struct {
+	services.EventPublisher
+	published []string
+}
+
+func (p *rawPublisher) PublishRaw(ctx context.Context, topic string, key *string, payload []byte) error {
+	p.published = append(p.published, topic+" "+string(payload))
+	return nil
+}
+
+// pausableConsumer stands in for the Kafka consumer behind the admin endpoints
+type pausableConsumer struct {
+	status models.ConsumerStatus
+}
+
+func (c *pausableConsumer) Pause(pausedBy string) models.ConsumerStatus {
+	if !c.status.Paused {
+		pausedAt := time.Now().UTC()
+		c.status = models.ConsumerStatus{Paused: true, PausedAt: &pausedAt, PausedBy: &pausedBy, Topics: c.status.Topics}
+	}
+	return c.status
+}
+
+func (c *pausableConsumer) Resume() models.ConsumerStatus {
+	c.status = models.ConsumerStatus{Topics: c.status.Topics}
+	return c.status
+}
+
+func (c *pausableConsumer) Status() models.ConsumerStatus {
+	return c.status
+}
+
+// claimSession stands in for a consumer group session and the one partition
+// it claims, recording the offsets ConsumeClaim marks
+type claimSession struct {
+	sarama.ConsumerGroupSession
+	sarama.ConsumerGroupClaim
+	ctx      context.Context
+	messages chan *sarama.ConsumerMessage
+	marked   []int64
+}
+
+func newClaimSession(ctx context.Context, messages ...*sarama.ConsumerMessage) *claimSession {
+	s := &claimSession{ctx: ctx, messages: make(chan *sarama.ConsumerMessage, len(messages))}
+	for _, message := range messages {
+		s.messages <- message
+	}
+	close(s.messages)
+	return s
+}
+
+func (s *claimSession) Context() context.Context {
+	return s.ctx
+}
+
+func (s *claimSession) Messages() <-chan *sarama.ConsumerMessage {
+	return s.messages
+}
+
+func (s *claimSession) Topic() string {
+	return ""
+}
+
+func (s *claimSession) Partition() int32 {
+	return 0
+}
+
+func (s *claimSession) MarkMessage(message *sarama.ConsumerMessage, metadata string) {
+	s.marked = append(s.marked, message.Offset)
+}
+
+// setupTestSQLite points the service at a fresh in-memory database holding
+// every table and the default configuration, and its clock at now, for one
+// test. The single connection
+// makes a query that bypasses an open transaction hang instead of passing.
+func setupTestSQLite(t *testing.T, now time.Time) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open("file:"+strings.ReplaceAll(t.Name(), "/", "_")+"?mode=memory&cache=shared"), &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	if err := db.AutoMigrate(
+		&models.QueueEntry{}, &models.QueueNotificationSent{}, &models.NotificationPreference{},
+		&models.DeviceToken{}, &models.WebhookSubscription{}, &models.WebhookDelivery{},
+		&models.QueuePositionHistory{}, &models.QueueLocation{}, &models.QueueConfiguration{},
+		&models.QueueWorkingHours{}, &models.QueuePriorityMultiplier{}, &models.QueueDisplayAnnouncement{},
+		&models.StaffQueueActionLog{}, &models.StaffShift{}, &models.QueueStatistics{},
+		&models.QueueHourlyStatistics{}, &models.QueueLoadSample{}, &models.QueueTokenCounter{},
+		&models.QueueDayRollover{}, &models.QueueEntryStage{}, &models.QueueEntryOrder{},
+		&models.QueueEntryTombstone{}, &models.QueueCounter{}, &models.QueueEntryItem{},
+		&models.KPIDefinition{}, &models.ProcessedEvent{}, &models.DeadLetterMessage{},
+		&models.QueueDepthLimit{}, &models.AuditLogEntry{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.QueueConfiguration{ID: "config-default", LocationID: models.DefaultLocationID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	previous := database.DB
+	database.DB = db
+	services.SetClock(clock.NewSimulated(now))
+	t.Cleanup(func() {
+		database.DB = previous
+		services.SetClock(clock.Real())
+		sqlDB.Close()
+	})
+	return db
+}
+
 func TestHealthCheck(t *testing.T) {
 	setupTestRouter()
 
@@ -46,6 +237,54 @@ func TestHealthCheck(t *testing.T) {
 	assert.Equal(t, "queue-service", response["service"])
 }
 
+func TestReadinessCheck(t *testing.T) {
+	setupTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/health/ready", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Equal(t, "ok", response["status"])
+	assert.Contains(t, response, "dependencies")
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	setupTestRouter()
+
+	// Generate at least one request metric
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/health", nil)
+	router.ServeHTTP(w, req)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/metrics", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "queue_service_http_requests_total")
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	setupTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/health", nil)
+	router.ServeHTTP(w, req)
+
+	assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/health", nil)
+	req.Header.Set("X-Request-ID", "test-request-id")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "test-request-id", w.Header().Get("X-Request-ID"))
+}
+
 func TestGetCurrentQueue(t *testing.T) {
 	setupTestRouter()
 
@@ -79,6 +318,16 @@ func TestGetQueueStatsWithDate(t *testing.T) {
 	assert.Equal(t, 200, w.Code)
 }
 
+func TestQueueEventsWithoutHub(t *testing.T) {
+	setupTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/queue/events?token=A001", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 503, w.Code)
+}
+
 func TestCreateQueueEntry(t *testing.T) {
 	setupTestRouter()
 
@@ -159,6 +408,12 @@ func TestCORS(t *testing.T) {
 	assert.Equal(t, 204, w.Code)
 	assert.NotEmpty(t, w.Header().Get("Access-Control-Allow-Origin"))
 	assert.NotEmpty(t, w.Header().Get("Access-Control-Allow-Methods"))
+
+	// Browsers only let scripts read the headers listed here
+	exposed := w.Header().Get("Access-Control-Expose-Headers")
+	for _, header := range []string{"Retry-After", "X-RateLimit-Remaining", "Idempotent-Replayed", "ETag"} {
+		assert.Contains(t, exposed, header)
+	}
 }
 
 func TestInvalidDateFormat(t *testing.T) {
@@ -170,3 +425,2416 @@ func TestInvalidDateFormat(t *testing.T) {
 
 	assert.Equal(t, 400, w.Code)
 }
+
+func TestConfigValidate(t *testing.T) {
+	assert.NoError(t, config.Load().Validate())
+
+	t.Setenv("BUFFER_TIME", "-1")
+	t.Setenv("MAX_CONCURRENT_ORDERS", "ten")
+	t.Setenv("KAFKA_BROKERS", "kafka:9092,kafka")
+
+	err := config.Load().Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "BUFFER_TIME")
+	assert.Contains(t, err.Error(), "MAX_CONCURRENT_ORDERS")
+	assert.Contains(t, err.Error(), `KAFKA_BROKERS "kafka"`)
+}
+
+func TestVersionedRoutes(t *testing.T) {
+	setupTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/v1/queue/user/me", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, "1", w.Header().Get("API-Version"))
+	assert.Empty(t, w.Header().Get("Deprecation"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/queue/user/me", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, 401, w.Code)
+	assert.Equal(t, "true", w.Header().Get("Deprecation"))
+	assert.Equal(t, `</api/v1/queue/user/me>; rel="successor-version"`, w.Header().Get("Link"))
+}
+
+func TestETagNotModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/current", middleware.ETagMiddleware(), func(c *gin.Context) {
+		c.JSON(200, gin.H{"total_active": 3})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/current", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/current", nil)
+	req.Header.Set("If-None-Match", etag)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 304, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.CompressionMiddleware())
+	r.GET("/large", func(c *gin.Context) {
+		c.JSON(200, gin.H{"data": strings.Repeat("A123", 1000)})
+	})
+	r.GET("/small", func(c *gin.Context) {
+		c.JSON(200, gin.H{"data": "A123"})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/large", nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	reader, err := gzip.NewReader(w.Body)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(reader)
+	assert.Contains(t, string(body), "A123A123")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/small", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	r.ServeHTTP(w, req)
+
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, `{"data":"A123"}`, w.Body.String())
+}
+
+func TestRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	middleware.SetRequestTimeouts(50*time.Millisecond, time.Second)
+	defer middleware.SetRequestTimeouts(15*time.Second, 2*time.Minute)
+
+	r := gin.New()
+	r.Use(middleware.TimeoutMiddleware())
+	slow := func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			c.JSON(500, gin.H{"error": c.Request.Context().Err().Error()})
+		case <-time.After(200 * time.Millisecond):
+			c.JSON(200, gin.H{"status": "done"})
+		}
+	}
+	r.GET("/slow", slow)
+	r.GET("/export", middleware.LongTimeoutMiddleware(), slow)
+	// A handler cut short after it started answering
+	r.GET("/partial", func(c *gin.Context) {
+		c.Status(200)
+		c.Writer.WriteString(`{"entries":[`)
+		<-c.Request.Context().Done()
+	})
+	var written bool
+	r.GET("/answered", func(c *gin.Context) {
+		c.Next()
+		written = c.Writer.Written()
+	}, func(c *gin.Context) {
+		c.JSON(400, gin.H{"error": "invalid"})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/slow", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 504, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/export", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/partial", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 504, w.Code)
+	assert.NotContains(t, w.Body.String(), "entries")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/answered", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+	assert.True(t, written)
+}
+
+func TestStreamTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	middleware.SetRequestTimeouts(time.Second, 200*time.Millisecond)
+	defer middleware.SetRequestTimeouts(15*time.Second, 2*time.Minute)
+
+	r := gin.New()
+	r.Use(middleware.TimeoutMiddleware())
+	sent := make(chan struct{})
+	r.GET("/export", middleware.StreamTimeoutMiddleware(), func(c *gin.Context) {
+		c.Status(200)
+		c.Writer.WriteString("id,token\n")
+		c.Writer.Flush()
+		close(sent)
+		<-c.Request.Context().Done()
+		c.Writer.WriteString("1,A1")
+	})
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/export")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	assert.Equal(t, 200, resp.StatusCode)
+
+	// The first rows arrive while the export is still running
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("export didn't start")
+	}
+	first := make([]byte, len("id,token\n"))
+	_, err = io.ReadFull(resp.Body, first)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,token\n", string(first))
+
+	// Cut short by the timeout, it doesn't end like a complete download
+	_, err = io.ReadAll(resp.Body)
+	assert.Error(t, err)
+}
+
+func TestErrorMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware(), middleware.TimeoutMiddleware())
+	r.GET("/duplicate", func(c *gin.Context) {
+		c.Error(fmt.Errorf("order 42: %w", services.ErrDuplicateOrder)).SetMeta("Failed to create queue entry")
+	})
+	r.GET("/missing", func(c *gin.Context) {
+		c.Error(gorm.ErrRecordNotFound)
+	})
+	r.POST("/bind", func(c *gin.Context) {
+		var req models.UpdateQueueStatusRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+		}
+	})
+	r.GET("/broken", func(c *gin.Context) {
+		c.Error(fmt.Errorf("connection refused"))
+	})
+	r.GET("/limited", func(c *gin.Context) {
+		c.Error(&services.RetryError{Err: services.ErrEntryRateLimited, RetryAfter: 1500 * time.Millisecond})
+	})
+	r.GET("/stage", func(c *gin.Context) {
+		c.Error(fmt.Errorf("%w: COMPLETED to WAITING", services.ErrInvalidStageTransition)).SetMeta("Failed to update stage status")
+	})
+
+	for path, expected := range map[string]struct {
+		status int
+		code   string
+	}{
+		"/duplicate": {409, "DUPLICATE_ORDER"},
+		"/missing":   {404, "NOT_FOUND"},
+		"/bind":      {400, "INVALID_REQUEST"},
+		"/broken":    {500, "INTERNAL_ERROR"},
+		"/limited":   {429, "ENTRY_RATE_LIMITED"},
+		"/stage":     {400, "INVALID_STAGE_TRANSITION"},
+	} {
+		method := "GET"
+		if path == "/bind" {
+			method = "POST"
+		}
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader("{}"))
+		r.ServeHTTP(w, req)
+
+		var response models.ErrorResponse
+		assert.Equal(t, expected.status, w.Code, path)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), path)
+		assert.Equal(t, expected.code, response.Code, path)
+		if path == "/limited" {
+			assert.Equal(t, "2", w.Header().Get("Retry-After"))
+		}
+	}
+}
+
+func TestIdempotencyMiddleware(t *testing.T) {
+	setupTestRedis(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware(), middleware.TimeoutMiddleware())
+	var created, duplicates int
+	r.POST("/orders", middleware.IdempotencyMiddleware(), func(c *gin.Context) {
+		created++
+		if created == 1 {
+			c.Error(fmt.Errorf("connection refused")).SetMeta("Failed to create queue entry")
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": "entry-1"})
+	})
+	r.POST("/walk-ins", middleware.IdempotencyMiddleware(), func(c *gin.Context) {
+		duplicates++
+		c.Error(services.ErrDuplicateOrder).SetMeta("Failed to create queue entry")
+	})
+
+	post := func(path, key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", path, strings.NewReader(`{"order_id":"42"}`))
+		req.Header.Set(middleware.IdempotencyKeyHeader, key)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// A server error releases the key, so the retry runs the handler again
+	w := post("/orders", "key-1")
+	assert.Equal(t, 500, w.Code)
+	assert.Contains(t, w.Body.String(), "INTERNAL_ERROR")
+	w = post("/orders", "key-1")
+	assert.Equal(t, 201, w.Code)
+	assert.Empty(t, w.Header().Get(middleware.IdempotentReplayedHeader))
+	w = post("/orders", "key-1")
+	assert.Equal(t, 201, w.Code)
+	assert.Equal(t, "true", w.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.JSONEq(t, `{"id":"entry-1"}`, w.Body.String())
+	assert.Equal(t, 2, created)
+
+	// A client error is stored with the body ErrorMiddleware gave it
+	first := post("/walk-ins", "key-2")
+	assert.Equal(t, 409, first.Code)
+	retry := post("/walk-ins", "key-2")
+	assert.Equal(t, 409, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(middleware.IdempotentReplayedHeader))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+	assert.Contains(t, retry.Body.String(), "DUPLICATE_ORDER")
+	assert.Equal(t, 1, duplicates)
+}
+
+func TestRollOverDay(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	// The day is rolled over after midnight, once the next one has begun
+	db := setupTestSQLite(t, day.Add(25*time.Hour))
+	service := services.NewQueueService()
+	ctx := context.Background()
+
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: "main", Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+	entry := func(token, locationID, status string, createdAt time.Time) models.QueueEntry {
+		return models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: locationID, UserID: "user-1",
+			TokenNumber: token, Status: status, Priority: "NORMAL", Position: 1,
+			CreatedAt: createdAt, UpdatedAt: createdAt,
+		}
+	}
+	entries := []models.QueueEntry{
+		entry("A001", "main", "WAITING", day.Add(12*time.Hour)),
+		entry("A002", "main", "READY", day.Add(20*time.Hour)),
+		entry("A003", "main", "COMPLETED", day.Add(13*time.Hour)),
+		entry("A004", "main", "WAITING", day.Add(24*time.Hour+30*time.Minute)),
+		entry("B001", "other", "WAITING", day.Add(12*time.Hour)),
+	}
+	entries[3].Position = 2
+	assert.NoError(t, db.Create(&entries).Error)
+
+	closesAt := day.Add(22 * time.Hour)
+	rollover, err := service.RollOverDay(ctx, "main", day, closesAt, false)
+	if !assert.NoError(t, err) || !assert.NotNil(t, rollover) {
+		return
+	}
+	assert.Equal(t, 2, rollover.ExpiredEntries)
+	assert.Equal(t, 3, rollover.TokensIssued)
+
+	// The next day's entry and other locations' stay in line
+	var stored []models.QueueEntry
+	assert.NoError(t, db.Find(&stored).Error)
+	statuses := make(map[string]string, len(stored))
+	for _, entry := range stored {
+		statuses[entry.TokenNumber] = entry.Status
+	}
+	assert.Equal(t, map[string]string{
+		"A001": "EXPIRED", "A002": "EXPIRED", "A003": "COMPLETED", "A004": "WAITING", "B001": "WAITING",
+	}, statuses)
+	position, err := service.GetQueuePosition(services.WithLocation(ctx, "main"), "A004")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, position.Position)
+	}
+
+	// A day is rolled over once
+	again, err := service.RollOverDay(ctx, "main", day, closesAt, false)
+	assert.NoError(t, err)
+	assert.Nil(t, again)
+
+	// A failure after claiming a day leaves it to be rolled over again
+	next := day.AddDate(0, 0, 1)
+	assert.NoError(t, db.Migrator().DropTable(&models.QueueEntry{}))
+	_, err = service.RollOverDay(ctx, "main", next, closesAt.AddDate(0, 0, 1), false)
+	assert.Error(t, err)
+	var claims int64
+	assert.NoError(t, db.Model(&models.QueueDayRollover{}).Where("location_id = ?", "main").Count(&claims).Error)
+	assert.Equal(t, int64(1), claims)
+}
+
+func TestEntryLookupsStayInLocation(t *testing.T) {
+	db := setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	service := services.NewQueueService()
+	now := time.Date(2026, 3, 10, 11, 0, 0, 0, time.UTC)
+	assert.NoError(t, db.Create(&models.QueueEntry{
+		ID: "entry-B001", OrderID: "order-B001", LocationID: "other", UserID: "user-1",
+		TokenNumber: "B001", Status: "WAITING", Priority: "NORMAL", Position: 1, CreatedAt: now, UpdatedAt: now,
+	}).Error)
+
+	// Staff scoped to another location can't reach the entry by ID...
+	scoped := services.WithLocation(context.Background(), "main")
+	_, err := service.GetQueueEntryByID(scoped, "entry-B001")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = service.GetPositionHistory(scoped, "entry-B001")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = service.DeleteQueueEntry(scoped, "entry-B001", nil, "staff-1")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	// ...while its own location and internal callers can
+	entry, err := service.GetQueueEntryByID(services.WithLocation(context.Background(), "other"), "entry-B001")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "B001", entry.TokenNumber)
+	}
+	_, err = service.GetQueueEntryByID(context.Background(), "entry-B001")
+	assert.NoError(t, err)
+}
+
+func TestCreateQueueEntryWithStages(t *testing.T) {
+	db := setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	service := services.NewQueueService()
+	ctx := context.Background()
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: models.DefaultLocationID, Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+
+	req := &models.CreateQueueEntryRequest{
+		OrderID:   "order-1",
+		UserID:    "user-1",
+		ItemCount: 5,
+		Stages: []models.CreateQueueStageRequest{
+			{Name: "Appetizers", ItemCount: 2},
+			{Name: "Mains", ItemCount: 3, ReadyAfterMinutes: 15},
+		},
+	}
+	entry, err := service.CreateQueueEntry(ctx, req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	stages, err := service.GetEntryStages(ctx, entry.ID)
+	if !assert.NoError(t, err) || !assert.Len(t, stages.Stages, 2) {
+		return
+	}
+
+	// A finished stage stays finished
+	completed := stages.Stages[0]
+	assert.NoError(t, db.Model(&completed).Update("status", "COMPLETED").Error)
+	_, err = service.UpdateStageStatus(ctx, entry.ID, completed.ID, &models.UpdateQueueStageStatusRequest{Status: "CANCELLED"}, "staff-1", "Sam")
+	assert.ErrorIs(t, err, services.ErrStageFinished)
+	assert.NoError(t, db.First(&completed, "id = ?", completed.ID).Error)
+	assert.Equal(t, "COMPLETED", completed.Status)
+
+	// An entry whose stages can't be stored isn't created either
+	assert.NoError(t, db.Migrator().DropTable(&models.QueueEntryStage{}))
+	req.OrderID = "order-2"
+	_, err = service.CreateQueueEntry(ctx, req)
+	assert.Error(t, err)
+	var count int64
+	assert.NoError(t, db.Model(&models.QueueEntry{}).Where("order_id = ?", "order-2").Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestCreateQueueEntryWithItems(t *testing.T) {
+	db := setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	service := services.NewQueueService()
+	ctx := context.Background()
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: models.DefaultLocationID, Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+
+	req := &models.CreateQueueEntryRequest{
+		OrderID:   "order-1",
+		UserID:    "user-1",
+		ItemCount: 3,
+		Stages:    []models.CreateQueueStageRequest{{Name: "Mains", ItemCount: 3}},
+		Items:     []models.CreateQueueItemRequest{{MenuItemID: "burger", ItemName: "Burger", Quantity: 3}},
+	}
+	entry, err := service.CreateQueueEntry(ctx, req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var items int64
+	assert.NoError(t, db.Model(&models.QueueEntryItem{}).Where("queue_entry_id = ?", entry.ID).Count(&items).Error)
+	assert.Equal(t, int64(1), items)
+
+	// An entry whose items can't be stored is created with neither its
+	// items nor its stages
+	assert.NoError(t, db.Migrator().DropTable(&models.QueueEntryItem{}))
+	req.OrderID = "order-2"
+	_, err = service.CreateQueueEntry(ctx, req)
+	assert.Error(t, err)
+	var entries, stages int64
+	assert.NoError(t, db.Model(&models.QueueEntry{}).Where("order_id = ?", "order-2").Count(&entries).Error)
+	assert.NoError(t, db.Model(&models.QueueEntryStage{}).Count(&stages).Error)
+	assert.Zero(t, entries)
+	assert.Equal(t, int64(1), stages)
+}
+
+func TestCustomKPIs(t *testing.T) {
+	db := setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	redisServer := setupTestRedis(t)
+	setupTestRouter()
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: models.DefaultLocationID, Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+
+	define := func(expression string) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(map[string]interface{}{"name": "completion_rate", "expression": expression})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/queue/kpis", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testToken("admin-1", "admin"))
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Expressions longer than the column are refused up front
+	w := define("total" + strings.Repeat(" + total", 63))
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_REQUEST")
+
+	w = define("completed / total")
+	assert.Equal(t, 201, w.Code)
+
+	entry := func(token, status string) models.QueueEntry {
+		createdAt := time.Date(2026, 3, 10, 11, 0, 0, 0, time.UTC)
+		return models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: status, Priority: "NORMAL", Position: 1,
+			CreatedAt: createdAt, UpdatedAt: createdAt,
+		}
+	}
+	assert.NoError(t, db.Create([]models.QueueEntry{entry("A001", "COMPLETED"), entry("A002", "WAITING")}).Error)
+
+	service := services.NewQueueService()
+	ctx := services.WithLocation(context.Background(), models.DefaultLocationID)
+	completionRate := func() float64 {
+		stats, err := service.GetQueueStatistics(ctx, nil)
+		if !assert.NoError(t, err) || !assert.Len(t, stats.CustomKPIs, 1) || !assert.NotNil(t, stats.CustomKPIs[0].Value) {
+			return -1
+		}
+		return *stats.CustomKPIs[0].Value
+	}
+	assert.Equal(t, 0.5, completionRate())
+
+	// The stats response reuses the day's counters for a while
+	assert.NoError(t, db.Create(&[]models.QueueEntry{entry("A003", "COMPLETED"), entry("A004", "COMPLETED")}).Error)
+	assert.Equal(t, 0.5, completionRate())
+
+	redisServer.FastForward(time.Minute)
+	assert.Equal(t, 0.75, completionRate())
+}
+
+func TestCloseCounterRebalances(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	service := services.NewQueueService()
+	ctx := services.WithLocation(context.Background(), models.DefaultLocationID)
+
+	assert.NoError(t, db.Create([]models.QueueCounter{
+		{ID: "counter-1", LocationID: models.DefaultLocationID, Name: "Counter 1", IsOpen: true},
+		{ID: "counter-2", LocationID: models.DefaultLocationID, Name: "Counter 2", IsOpen: true},
+		{ID: "counter-3", LocationID: models.DefaultLocationID, Name: "Counter 3", IsOpen: true},
+	}).Error)
+	counterID := "counter-3"
+	var entries []models.QueueEntry
+	for i, token := range []string{"A001", "A002", "A003"} {
+		entries = append(entries, models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: "WAITING", Priority: "NORMAL", Position: i + 1, CounterID: &counterID,
+			CreatedAt: now, UpdatedAt: now,
+		})
+	}
+	assert.NoError(t, db.Create(&entries).Error)
+
+	result, err := service.CloseCounter(ctx, "counter-3", "staff-1", "Sam")
+	if !assert.NoError(t, err) || !assert.Len(t, result.Reassignments, 3) {
+		return
+	}
+	// Even loads go to the counter that comes first by name
+	var targets []string
+	for _, reassignment := range result.Reassignments {
+		targets = append(targets, *reassignment.ToCounter)
+	}
+	assert.Equal(t, []string{"Counter 1", "Counter 2", "Counter 1"}, targets)
+}
+
+func TestIntegrityCheck(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	setupTestRouter()
+
+	entry := func(token, status string, position int) models.QueueEntry {
+		return models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: status, Priority: "NORMAL", Position: position,
+			CreatedAt: now, UpdatedAt: now,
+		}
+	}
+	assert.NoError(t, db.Create([]models.QueueEntry{
+		entry("A001", "WAITING", 1), entry("A002", "WAITING", 1), entry("A003", "READY", 0),
+	}).Error)
+	assert.NoError(t, db.Create(&models.QueueEntryStage{ID: "stage-1", QueueEntryID: "entry-gone", TokenNumber: "A000", Sequence: 1, Name: "Mains"}).Error)
+
+	check := func(repair string) models.IntegrityReport {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/queue/admin/integrity/check?repair="+repair, nil)
+		req.Header.Set("Authorization", "Bearer "+testToken("admin-1", "admin"))
+		router.ServeHTTP(w, req)
+		assert.Equal(t, 200, w.Code)
+
+		var report models.IntegrityReport
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		return report
+	}
+
+	report := check("false")
+	assert.False(t, report.Consistent)
+	assert.True(t, report.CheckedAt.Equal(now))
+	counts := make(map[string]int, len(report.Issues))
+	for _, issue := range report.Issues {
+		counts[issue.Check] = issue.Count
+		assert.Zero(t, issue.Repaired, issue.Check)
+	}
+	assert.Equal(t, map[string]int{
+		"duplicate_active_positions":  2,
+		"ready_without_timestamp":     1,
+		"orphaned_queue_entry_stages": 1,
+	}, counts)
+
+	report = check("true")
+	assert.True(t, report.Consistent)
+	report = check("false")
+	assert.True(t, report.Consistent)
+	assert.Empty(t, report.Issues)
+}
+
+func TestStatsSummary(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	redisServer := setupTestRedis(t)
+	service := services.NewQueueService()
+	ctx := services.WithLocation(context.Background(), models.DefaultLocationID)
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	key := "queue:stats:" + models.DefaultLocationID + ":2026-03-10"
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: models.DefaultLocationID, Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+
+	entry := func(token, status, tokenType string) models.QueueEntry {
+		return models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, TokenType: tokenType, Status: status, Priority: "NORMAL", Position: 1,
+			CreatedAt: now, UpdatedAt: now,
+		}
+	}
+	assert.NoError(t, db.Create([]models.QueueEntry{
+		entry("A001", "WAITING", models.TokenTypeWalkIn),
+		entry("A002", "WAITING", "REGULAR"),
+		entry("A003", "COMPLETED", "REGULAR"),
+	}).Error)
+
+	summary, err := service.RebuildStatsSummary(ctx, models.DefaultLocationID, day)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", summary["waiting_count"])
+	assert.Equal(t, "1", summary["completed_today"])
+	assert.Equal(t, "1", summary["walk_ins_today"])
+
+	// A new entry is counted onto the existing summary
+	_, err = service.CreateQueueEntry(ctx, &models.CreateQueueEntryRequest{OrderID: "order-A004", UserID: "user-1", ItemCount: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, "3", redisServer.HGet(key, "waiting_count"))
+
+	// A missing summary is rebuilt rather than started from the delta
+	redisServer.Del(key)
+	_, err = service.CreateQueueEntry(ctx, &models.CreateQueueEntryRequest{OrderID: "order-A005", UserID: "user-1", ItemCount: 1})
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return redisServer.HGet(key, "waiting_count") == "4" && redisServer.HGet(key, "completed_today") == "1"
+	}, time.Second, 10*time.Millisecond)
+
+	// An entry completed while a rebuild is counting isn't lost when the
+	// rebuild stores its counts
+	var completed bool
+	assert.NoError(t, db.Callback().Query().After("gorm:query").Register("test:complete_entry", func(tx *gorm.DB) {
+		if completed {
+			return
+		}
+		completed = true
+		db.Create(&[]models.QueueEntry{entry("A006", "COMPLETED", "REGULAR")})
+		redisServer.HIncrBy(key, "completed_today", 1)
+	}))
+	summary, err = service.RebuildStatsSummary(ctx, models.DefaultLocationID, day)
+	assert.NoError(t, db.Callback().Query().Remove("test:complete_entry"))
+	assert.NoError(t, err)
+	assert.Equal(t, "2", summary["completed_today"])
+	assert.Equal(t, "2", redisServer.HGet(key, "completed_today"))
+}
+
+func TestDepthLimits(t *testing.T) {
+	db := setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	setupTestRouter()
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: models.DefaultLocationID, Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+
+	setLimit := func(payload map[string]interface{}) *httptest.ResponseRecorder {
+		jsonData, _ := json.Marshal(payload)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("PUT", "/api/queue/depth-limits", bytes.NewBuffer(jsonData))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testToken("admin-1", "admin"))
+		router.ServeHTTP(w, req)
+		return w
+	}
+	assert.Equal(t, 200, setLimit(map[string]interface{}{"priority": "VIP", "max_waiting": 1}).Code)
+	assert.Equal(t, 200, setLimit(map[string]interface{}{"lane": "EXPRESS", "priority": "ANY", "max_waiting": 0}).Code)
+	// Only the express lane can be capped across priorities
+	w := setLimit(map[string]interface{}{"lane": "REGULAR", "priority": "ANY", "max_waiting": 3})
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_DEPTH_LIMIT")
+
+	service := services.NewQueueService()
+	ctx := services.WithLocation(context.Background(), models.DefaultLocationID)
+	create := func(orderID, priority string, express bool) *models.QueueEntry {
+		entry, err := service.CreateQueueEntry(ctx, &models.CreateQueueEntryRequest{
+			OrderID: orderID, UserID: "user-1", ItemCount: 1, Priority: priority, IsExpressQueue: express,
+		})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return entry
+	}
+
+	first := create("order-1", "VIP", false)
+	assert.Equal(t, "VIP", first.Priority)
+	assert.Nil(t, first.Notes)
+
+	// A full priority falls back to the next one down, saying why
+	second := create("order-2", "VIP", false)
+	assert.Equal(t, "URGENT", second.Priority)
+	if assert.NotNil(t, second.Notes) {
+		assert.Equal(t, "VIP priority full (1 waiting); queued as URGENT", *second.Notes)
+	}
+
+	// A full express lane overflows into the regular lane
+	express := create("order-3", "NORMAL", true)
+	assert.False(t, express.IsExpressQueue)
+	assert.Equal(t, "NORMAL", express.Priority)
+}
+
+func TestDefineStages(t *testing.T) {
+	db := setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	setupTestRouter()
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: models.DefaultLocationID, Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+
+	ctx := services.WithLocation(context.Background(), models.DefaultLocationID)
+	entry, err := services.NewQueueService().CreateQueueEntry(ctx, &models.CreateQueueEntryRequest{OrderID: "order-1", UserID: "user-1", ItemCount: 5})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	stages := map[string]interface{}{
+		"stages": []map[string]interface{}{
+			{"name": "Appetizers", "item_count": 2},
+			{"name": "Mains", "item_count": 3, "ready_after_minutes": 15},
+		},
+	}
+	w := serveJSON("POST", "/api/queue/"+entry.ID+"/stages", stages, "staff")
+	assert.Equal(t, 201, w.Code)
+
+	// The customer follows the stages by token
+	w = serveJSON("GET", "/api/queue/token/"+entry.TokenNumber+"/stages", nil, "")
+	assert.Equal(t, 200, w.Code)
+	var progress models.QueueStagesResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &progress))
+	if assert.Len(t, progress.Stages, 2) {
+		assert.Equal(t, "Appetizers", progress.Stages[0].Name)
+		assert.Equal(t, "Mains", progress.Stages[1].Name)
+	}
+	assert.Equal(t, 2, progress.RemainingStages)
+
+	// Stages can't be redefined once one has started
+	assert.NoError(t, db.Model(&models.QueueEntryStage{}).Where("queue_entry_id = ? AND sequence = ?", entry.ID, 1).Update("status", "IN_PROGRESS").Error)
+	w = serveJSON("POST", "/api/queue/"+entry.ID+"/stages", stages, "staff")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "STAGES_STARTED")
+
+	// Nor defined for a finished entry
+	assert.NoError(t, db.Model(&models.QueueEntry{}).Where("id = ?", entry.ID).Update("status", "COMPLETED").Error)
+	w = serveJSON("POST", "/api/queue/"+entry.ID+"/stages", stages, "staff")
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "STAGES_NOT_ALLOWED")
+}
+
+func TestPprof(t *testing.T) {
+	setupTestRouter()
+
+	w := serveJSON("GET", "/debug/pprof/heap?debug=1", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "heap profile")
+
+	assert.Equal(t, 401, serveJSON("GET", "/debug/pprof/heap", nil, "").Code)
+	assert.Equal(t, 403, serveJSON("GET", "/debug/pprof/heap", nil, "staff").Code)
+
+	// Admins outside the admin networks are refused too
+	assert.NoError(t, middleware.SetAdminNetworks([]string{"10.8.0.0/16"}))
+	defer middleware.SetAdminNetworks(nil)
+	assert.Equal(t, 403, serveJSON("GET", "/debug/pprof/heap", nil, "admin").Code)
+}
+
+func TestRedriveDeadLetter(t *testing.T) {
+	setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	ctx := context.Background()
+	key := "42"
+	pending := &models.DeadLetterMessage{OriginalTopic: "order.created", MessageKey: &key, Payload: `{"order_id":"42"}`, Error: "queue unavailable"}
+	discarded := &models.DeadLetterMessage{OriginalTopic: "order.created", Payload: `{}`, Error: "malformed", Status: "DISCARDED"}
+	assert.NoError(t, services.NewQueueService().RecordDeadLetter(ctx, pending))
+	assert.NoError(t, services.NewQueueService().RecordDeadLetter(ctx, discarded))
+
+	// Without Kafka there is nothing to re-drive with
+	setupTestRouter()
+	w := serveJSON("POST", "/api/queue/admin/dlq/"+pending.ID+"/redrive", nil, "admin")
+	assert.Equal(t, 503, w.Code)
+	assert.Contains(t, w.Body.String(), "REDRIVE_UNAVAILABLE")
+
+	publisher := &rawPublisher{}
+	services.SetEventPublisher(publisher)
+	defer services.SetEventPublisher(nil)
+	setupTestRouter()
+
+	w = serveJSON("POST", "/api/queue/admin/dlq/"+pending.ID+"/redrive", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, []string{`order.created {"order_id":"42"}`}, publisher.published)
+
+	w = serveJSON("GET", "/api/queue/admin/dlq?status=REDRIVEN", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	var list models.DeadLetterListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, int64(1), list.Total)
+	if assert.Len(t, list.Messages, 1) {
+		assert.Equal(t, 1, list.Messages[0].RedriveCount)
+		assert.Equal(t, "admin-1", *list.Messages[0].LastRedrivenBy)
+	}
+
+	// Discarded messages stay discarded
+	w = serveJSON("POST", "/api/queue/admin/dlq/"+discarded.ID+"/redrive", nil, "admin")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "DEAD_LETTER_DISCARDED")
+	assert.Len(t, publisher.published, 1)
+
+	assert.Equal(t, 404, serveJSON("POST", "/api/queue/admin/dlq/missing/redrive", nil, "admin").Code)
+}
+
+func TestDeadLetterProtobufMessage(t *testing.T) {
+	db := setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+
+	cfg := config.Load()
+	cfg.KafkaConsumerEncoding = "protobuf"
+	cfg.KafkaConsumerMaxRetries = 0
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(cfg.KafkaTopicDeadLetter, 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+	cfg.KafkaBrokers = []string{broker.Addr()}
+
+	consumer, err := kafka.NewKafkaConsumer(cfg, services.NewQueueService(), nil, nil, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer consumer.Stop()
+
+	// A payment for an order that was never queued; the amount's fixed64
+	// encoding holds NUL bytes no text column takes
+	value, err := proto.Marshal(&eventspb.PaymentCompleted{OrderId: "42", PaymentId: "payment-1", Amount: 12.5})
+	assert.NoError(t, err)
+	assert.Contains(t, string(value), "\x00")
+	message := &sarama.ConsumerMessage{Topic: cfg.KafkaTopicPaymentCompleted, Offset: 7, Key: []byte("42"), Value: value}
+	session := newClaimSession(context.Background(), message)
+	assert.NoError(t, consumer.ConsumeClaim(session, session))
+	assert.Equal(t, []int64{7}, session.marked)
+
+	var dead models.DeadLetterMessage
+	if !assert.NoError(t, db.First(&dead, "original_topic = ?", cfg.KafkaTopicPaymentCompleted).Error) {
+		return
+	}
+	assert.Equal(t, "BASE64", dead.PayloadEncoding)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(value), dead.Payload)
+
+	// Re-driving publishes the original bytes
+	publisher := &rawPublisher{}
+	services.SetEventPublisher(publisher)
+	defer services.SetEventPublisher(nil)
+	setupTestRouter()
+	w := serveJSON("POST", "/api/queue/admin/dlq/"+dead.ID+"/redrive", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, []string{cfg.KafkaTopicPaymentCompleted + " " + string(value)}, publisher.published)
+}
+
+func TestConsumerSessionEndsDuringRetry(t *testing.T) {
+	db := setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+
+	cfg := config.Load()
+	cfg.KafkaConsumerMaxRetries = 5
+	cfg.KafkaConsumerRetryBackoffMs = 60000
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(cfg.KafkaTopicDeadLetter, 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+	cfg.KafkaBrokers = []string{broker.Addr()}
+
+	consumer, err := kafka.NewKafkaConsumer(cfg, services.NewQueueService(), nil, nil, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer consumer.Stop()
+
+	// A payment overtaking its order is retried until the rebalance ends the session
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	message := &sarama.ConsumerMessage{
+		Topic: cfg.KafkaTopicPaymentCompleted, Offset: 3, Key: []byte("42"),
+		Value: []byte(`{"order_id":"42","payment_id":"payment-1","amount":12.5}`),
+	}
+	session := newClaimSession(ctx, message)
+	assert.NoError(t, consumer.ConsumeClaim(session, session))
+
+	// Neither committed nor dead-lettered, so the next owner redelivers it
+	assert.Empty(t, session.marked)
+	var dead int64
+	assert.NoError(t, db.Model(&models.DeadLetterMessage{}).Count(&dead).Error)
+	assert.Zero(t, dead)
+}
+
+func TestPauseConsumer(t *testing.T) {
+	setupTestRouter()
+
+	// Without a running consumer there is nothing to pause
+	w := serveJSON("POST", "/api/queue/admin/consumer/pause", nil, "admin")
+	assert.Equal(t, 503, w.Code)
+	assert.Contains(t, w.Body.String(), "CONSUMER_UNAVAILABLE")
+
+	services.SetConsumerController(&pausableConsumer{status: models.ConsumerStatus{Topics: []string{"order.created"}}})
+	defer services.SetConsumerController(nil)
+
+	status := func() models.ConsumerStatus {
+		w := serveJSON("GET", "/api/queue/admin/consumer", nil, "admin")
+		assert.Equal(t, 200, w.Code)
+		var status models.ConsumerStatus
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+		return status
+	}
+
+	assert.Equal(t, 200, serveJSON("POST", "/api/queue/admin/consumer/pause", nil, "admin").Code)
+	paused := status()
+	assert.True(t, paused.Paused)
+	if assert.NotNil(t, paused.PausedBy) {
+		assert.Equal(t, "admin-1", *paused.PausedBy)
+	}
+	assert.Equal(t, []string{"order.created"}, paused.Topics)
+
+	assert.Equal(t, 200, serveJSON("POST", "/api/queue/admin/consumer/resume", nil, "admin").Code)
+	resumed := status()
+	assert.False(t, resumed.Paused)
+	assert.Nil(t, resumed.PausedBy)
+
+	assert.Equal(t, 403, serveJSON("POST", "/api/queue/admin/consumer/pause", nil, "staff").Code)
+}
+
+func TestReplayEvents(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	assert.NoError(t, db.Create(&models.QueueEntry{
+		ID: "entry-41", OrderID: "41", LocationID: models.DefaultLocationID, UserID: "user-1",
+		TokenNumber: "A041", Status: "WAITING", Priority: "NORMAL", Position: 1, CreatedAt: now, UpdatedAt: now,
+	}).Error)
+	assert.NoError(t, db.Create(&models.QueueEntry{
+		ID: "entry-43", OrderID: "43", LocationID: models.DefaultLocationID, UserID: "user-3",
+		TokenNumber: "A043", Status: "PENDING_PAYMENT", Priority: "NORMAL", CreatedAt: now, UpdatedAt: now,
+	}).Error)
+
+	cfg := config.Load()
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"ApiVersionsRequest": sarama.NewMockApiVersionsResponse(t),
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader(cfg.KafkaTopicOrderCreated, 0, broker.BrokerID()).
+			SetLeader(cfg.KafkaTopicPaymentCompleted, 0, broker.BrokerID()),
+		"OffsetRequest": sarama.NewMockOffsetResponse(t).
+			SetOffset(cfg.KafkaTopicOrderCreated, 0, sarama.OffsetOldest, 0).
+			SetOffset(cfg.KafkaTopicOrderCreated, 0, sarama.OffsetNewest, 2).
+			SetOffset(cfg.KafkaTopicPaymentCompleted, 0, sarama.OffsetOldest, 0).
+			SetOffset(cfg.KafkaTopicPaymentCompleted, 0, sarama.OffsetNewest, 1),
+		"FetchRequest": sarama.NewMockFetchResponse(t, 1).
+			SetMessage(cfg.KafkaTopicOrderCreated, 0, 0, sarama.StringEncoder(`{"order_id":"41","user_id":"user-1"}`)).
+			SetMessage(cfg.KafkaTopicOrderCreated, 0, 1, sarama.StringEncoder(`{"order_id":"42","user_id":"user-2"}`)).
+			SetHighWaterMark(cfg.KafkaTopicOrderCreated, 0, 2).
+			SetMessage(cfg.KafkaTopicPaymentCompleted, 0, 0, sarama.StringEncoder(`{"order_id":"43","payment_id":"payment-1"}`)).
+			SetHighWaterMark(cfg.KafkaTopicPaymentCompleted, 0, 1),
+	})
+	cfg.KafkaBrokers = []string{broker.Addr()}
+
+	consumer, err := kafka.NewKafkaConsumer(cfg, services.NewQueueService(), nil, nil, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer consumer.Stop()
+	services.SetEventReplayer(consumer)
+	defer services.SetEventReplayer(nil)
+	setupTestRouter()
+
+	// A dry run describes each message without applying it
+	w := serveJSON("POST", "/api/queue/admin/replay", map[string]interface{}{
+		"topic": cfg.KafkaTopicOrderCreated, "partition": 0, "start_offset": 0,
+	}, "admin")
+	assert.Equal(t, 200, w.Code)
+	var response struct {
+		Data models.ReplayReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	report := response.Data
+	assert.Equal(t, 2, report.Processed)
+	var actions []string
+	for _, message := range report.Messages {
+		actions = append(actions, message.Action)
+	}
+	assert.Equal(t, []string{"no change: order 41 already queued as A041", "enqueue order 42"}, actions)
+	var queued int64
+	assert.NoError(t, db.Model(&models.QueueEntry{}).Where("order_id = ?", "42").Count(&queued).Error)
+	assert.Zero(t, queued)
+
+	// Every consumed topic can be replayed, not just the order ones
+	w = serveJSON("POST", "/api/queue/admin/replay", map[string]interface{}{
+		"topic": cfg.KafkaTopicPaymentCompleted, "partition": 0, "start_offset": 0, "apply": true,
+	}, "admin")
+	assert.Equal(t, 200, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.Len(t, response.Data.Messages, 1) {
+		assert.Equal(t, "activate A043", response.Data.Messages[0].Action)
+		assert.Empty(t, response.Data.Messages[0].Error)
+	}
+	var paid models.QueueEntry
+	assert.NoError(t, db.First(&paid, "order_id = ?", "43").Error)
+	assert.NotEqual(t, "PENDING_PAYMENT", paid.Status)
+
+	// Only consumed topics can be replayed
+	w = serveJSON("POST", "/api/queue/admin/replay", map[string]interface{}{"topic": cfg.KafkaTopicQueueEvents}, "admin")
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_REPLAY")
+
+	// Without a consumer there is nothing to replay from
+	services.SetEventReplayer(nil)
+	setupTestRouter()
+	w = serveJSON("POST", "/api/queue/admin/replay", map[string]interface{}{"topic": cfg.KafkaTopicOrderCreated}, "admin")
+	assert.Equal(t, 503, w.Code)
+	assert.Contains(t, w.Body.String(), "REPLAY_UNAVAILABLE")
+}
+
+func TestSMSStatusCallback(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	messageID := "SM123"
+	assert.NoError(t, db.Create(&models.QueueNotificationSent{
+		ID: "notification-1", QueueEntryID: "entry-1", NotificationType: "READY", Channel: "SMS",
+		SentAt: now, ProviderMessageID: &messageID,
+	}).Error)
+
+	callbackURL := "https://queue.example.com/api/queue/notifications/sms/status"
+	_, err := notify.NewDispatcher(&config.Config{
+		SMSProvider: "twilio", TwilioAccountSID: "AC123", TwilioAuthToken: "secret",
+		TwilioFromNumber: "+15550100", TwilioStatusCallbackURL: callbackURL,
+	})
+	assert.NoError(t, err)
+	setupTestRouter()
+
+	callback := func(form url.Values, signature string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/queue/notifications/sms/status", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Twilio-Signature", signature)
+		router.ServeHTTP(w, req)
+		return w
+	}
+	form := url.Values{"MessageSid": {messageID}, "MessageStatus": {"undelivered"}, "ErrorCode": {"30003"}}
+
+	// Twilio signs the callback URL followed by the sorted parameters
+	mac := hmac.New(sha1.New, []byte("secret"))
+	mac.Write([]byte(callbackURL + "ErrorCode30003MessageSidSM123MessageStatusundelivered"))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(t, 403, callback(form, "forged").Code)
+	var notification models.QueueNotificationSent
+	assert.NoError(t, db.First(&notification, "id = ?", "notification-1").Error)
+	assert.Nil(t, notification.DeliveryStatus)
+
+	assert.Equal(t, 204, callback(form, signature).Code)
+	assert.NoError(t, db.First(&notification, "id = ?", "notification-1").Error)
+	if assert.NotNil(t, notification.DeliveryStatus) && assert.NotNil(t, notification.DeliveryError) {
+		assert.Equal(t, "undelivered", *notification.DeliveryStatus)
+		assert.Equal(t, "30003", *notification.DeliveryError)
+	}
+}
+
+func TestUpdateNotificationPreferences(t *testing.T) {
+	setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	setupTestRouter()
+
+	// Email can't be turned on without an address, and the token carries none
+	w := serveJSON("PUT", "/api/queue/notifications/preferences", map[string]interface{}{"email_enabled": true}, "customer")
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "EMAIL_REQUIRED")
+
+	w = serveJSON("PUT", "/api/queue/notifications/preferences", map[string]interface{}{"language": "xx"}, "customer")
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "UNSUPPORTED_LANGUAGE")
+
+	w = serveJSON("PUT", "/api/queue/notifications/preferences", map[string]interface{}{
+		"email": "customer@example.com", "email_enabled": true, "sms_enabled": false, "language": "es",
+	}, "customer")
+	assert.Equal(t, 200, w.Code)
+
+	// Omitted fields keep their value
+	w = serveJSON("PUT", "/api/queue/notifications/preferences", map[string]interface{}{"sms_enabled": true}, "customer")
+	assert.Equal(t, 200, w.Code)
+
+	w = serveJSON("GET", "/api/queue/notifications/preferences", nil, "customer")
+	assert.Equal(t, 200, w.Code)
+	var pref models.NotificationPreference
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &pref))
+	assert.Equal(t, "customer-1", pref.UserID)
+	assert.True(t, pref.SMSEnabled)
+	assert.True(t, pref.EmailEnabled)
+	if assert.NotNil(t, pref.Email) && assert.NotNil(t, pref.Language) {
+		assert.Equal(t, "customer@example.com", *pref.Email)
+		assert.Equal(t, "es", *pref.Language)
+	}
+}
+
+func TestRegisterDevice(t *testing.T) {
+	db := setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	setupTestRouter()
+
+	w := serveJSON("POST", "/api/queue/notifications/devices", map[string]interface{}{"token": "fcm-token", "platform": "symbian"}, "customer")
+	assert.Equal(t, 400, w.Code)
+
+	w = serveJSON("POST", "/api/queue/notifications/devices", map[string]interface{}{"token": "fcm-token", "platform": "android"}, "customer")
+	assert.Equal(t, 201, w.Code)
+
+	// A device signed in to another account moves to it
+	w = serveJSON("POST", "/api/queue/notifications/devices", map[string]interface{}{"token": "fcm-token", "platform": "android"}, "staff")
+	assert.Equal(t, 201, w.Code)
+	var devices []models.DeviceToken
+	assert.NoError(t, db.Find(&devices).Error)
+	if assert.Len(t, devices, 1) {
+		assert.Equal(t, "staff-1", devices[0].UserID)
+	}
+
+	w = serveJSON("DELETE", "/api/queue/notifications/devices/fcm-token", nil, "customer")
+	assert.Equal(t, 404, w.Code)
+	w = serveJSON("DELETE", "/api/queue/notifications/devices/fcm-token", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	var remaining int64
+	assert.NoError(t, db.Model(&models.DeviceToken{}).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+}
+
+func TestCreateWebhook(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	setupTestRouter()
+
+	received := make(chan *http.Request, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		received <- r
+	}))
+	defer receiver.Close()
+
+	w := serveJSON("POST", "/api/queue/admin/webhooks", map[string]interface{}{
+		"url": receiver.URL, "event_types": []string{"queue.entry.deleted"},
+	}, "admin")
+	assert.Equal(t, 400, w.Code)
+
+	w = serveJSON("POST", "/api/queue/admin/webhooks", map[string]interface{}{
+		"url": receiver.URL, "event_types": []string{"queue.status.changed"},
+	}, "admin")
+	assert.Equal(t, 201, w.Code)
+	var created struct {
+		Data models.WebhookCreatedResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	webhook := created.Data
+	assert.True(t, strings.HasPrefix(webhook.Secret, "whsec_"))
+	assert.Equal(t, "admin-1", *webhook.CreatedBy)
+
+	// The secret is only shown on creation
+	w = serveJSON("GET", "/api/queue/admin/webhooks", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	assert.NotContains(t, w.Body.String(), webhook.Secret)
+
+	// Deliveries are signed with the secret
+	payload := `{"type":"queue.status.changed"}`
+	assert.NoError(t, db.Create(&models.WebhookDelivery{
+		ID: "delivery-1", SubscriptionID: webhook.ID, EventID: "event-1", EventType: "queue.status.changed",
+		Payload: payload, Status: "FAILED", Attempts: 5, CreatedAt: now,
+	}).Error)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go services.NewQueueService().StartWebhookDispatcher(ctx, time.Minute, time.Second, 5)
+
+	w = serveJSON("POST", "/api/queue/admin/webhooks/deliveries/delivery-1/retry", nil, "admin")
+	assert.Equal(t, 202, w.Code)
+	select {
+	case r := <-received:
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, payload, string(body))
+		mac := hmac.New(sha256.New, []byte(webhook.Secret))
+		mac.Write([]byte(r.Header.Get("X-Webhook-Timestamp") + "." + payload))
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-Webhook-Signature"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook delivery was not sent")
+	}
+}
+
+func TestCreateCounter(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	setupTestRouter()
+
+	w := serveJSON("POST", "/api/queue/counters", map[string]interface{}{"name": "Counter 1"}, "staff")
+	assert.Equal(t, 403, w.Code)
+
+	w = serveJSON("POST", "/api/queue/counters", map[string]interface{}{"name": "Counter 1"}, "admin")
+	assert.Equal(t, 201, w.Code)
+	var created struct {
+		Data models.QueueCounter `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	counter := created.Data
+	assert.Equal(t, models.DefaultLocationID, counter.LocationID)
+	assert.True(t, counter.IsOpen)
+
+	w = serveJSON("POST", "/api/queue/counters", map[string]interface{}{"name": "Counter 1"}, "admin")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "COUNTER_EXISTS")
+
+	w = serveJSON("GET", "/api/queue/counters", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), counter.ID)
+
+	// Counters with orders waiting on them can't be removed
+	assert.NoError(t, db.Create(&models.QueueEntry{
+		ID: "entry-1", OrderID: "order-1", LocationID: models.DefaultLocationID, UserID: "user-1", TokenNumber: "A001",
+		Status: "WAITING", Priority: "NORMAL", Position: 1, CounterID: &counter.ID, CreatedAt: now, UpdatedAt: now,
+	}).Error)
+	w = serveJSON("DELETE", "/api/queue/counters/"+counter.ID, nil, "admin")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "COUNTER_IN_USE")
+
+	assert.NoError(t, db.Model(&models.QueueEntry{}).Where("id = ?", "entry-1").Update("status", "COMPLETED").Error)
+	w = serveJSON("DELETE", "/api/queue/counters/"+counter.ID, nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	var entry models.QueueEntry
+	assert.NoError(t, db.First(&entry, "id = ?", "entry-1").Error)
+	assert.Nil(t, entry.CounterID)
+}
+
+func TestCreateLocation(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: models.DefaultLocationID, Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+	setupTestRouter()
+
+	w := serveJSON("POST", "/api/queue/locations", map[string]interface{}{"id": "down town", "name": "Downtown", "token_prefix": "B"}, "admin")
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_LOCATION_ID")
+
+	w = serveJSON("POST", "/api/queue/locations", map[string]interface{}{"id": "downtown", "name": "Downtown", "token_prefix": "b"}, "admin")
+	assert.Equal(t, 201, w.Code)
+
+	// Token prefixes tell locations' tokens apart
+	w = serveJSON("POST", "/api/queue/locations", map[string]interface{}{"id": "uptown", "name": "Uptown", "token_prefix": "B"}, "admin")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "LOCATION_EXISTS")
+
+	w = serveJSON("GET", "/api/queue/locations", nil, "")
+	assert.Equal(t, 200, w.Code)
+	var locations []models.QueueLocation
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &locations))
+	if assert.Len(t, locations, 2) {
+		assert.Equal(t, "downtown", locations[0].ID)
+		assert.Equal(t, "B", locations[0].TokenPrefix)
+		assert.True(t, locations[0].IsActive)
+	}
+
+	w = serveJSON("DELETE", "/api/queue/locations/"+models.DefaultLocationID, nil, "admin")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "DEFAULT_LOCATION")
+
+	assert.NoError(t, db.Create(&models.QueueEntry{
+		ID: "entry-1", OrderID: "order-1", LocationID: "downtown", UserID: "user-1", TokenNumber: "B001",
+		Status: "WAITING", Priority: "NORMAL", Position: 1, CreatedAt: now, UpdatedAt: now,
+	}).Error)
+	w = serveJSON("DELETE", "/api/queue/locations/downtown", nil, "admin")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "LOCATION_IN_USE")
+}
+
+func TestClockIn(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	assert.NoError(t, db.Create(&models.QueueCounter{ID: "counter-1", LocationID: models.DefaultLocationID, Name: "Counter 1", IsOpen: true}).Error)
+	// The migrations allow one open shift per staff member
+	assert.NoError(t, db.Exec("CREATE UNIQUE INDEX idx_queue_staff_shifts_open_staff_id ON queue_staff_shifts (staff_id) WHERE clock_out_at IS NULL").Error)
+	setupTestRouter()
+
+	w := serveJSON("POST", "/api/queue/shifts/clock-in", map[string]interface{}{"counter_id": "counter-9"}, "staff")
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "UNKNOWN_COUNTER")
+
+	w = serveJSON("POST", "/api/queue/shifts/clock-in", map[string]interface{}{"counter_id": "counter-1"}, "staff")
+	assert.Equal(t, 201, w.Code)
+	w = serveJSON("POST", "/api/queue/shifts/clock-in", nil, "staff")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "ALREADY_CLOCKED_IN")
+
+	w = serveJSON("GET", "/api/queue/shifts/active", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	var shifts []models.StaffShift
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &shifts))
+	if assert.Len(t, shifts, 1) {
+		assert.Equal(t, "staff-1", shifts[0].StaffID)
+		assert.Equal(t, "counter-1", *shifts[0].CounterID)
+		assert.True(t, shifts[0].ClockInAt.Equal(now))
+	}
+
+	w = serveJSON("POST", "/api/queue/shifts/clock-out", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	w = serveJSON("POST", "/api/queue/shifts/clock-out", nil, "staff")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "NOT_CLOCKED_IN")
+	w = serveJSON("GET", "/api/queue/shifts/active", nil, "staff")
+	assert.Equal(t, "[]", w.Body.String())
+}
+
+func TestGRPCGetCurrentQueueLocation(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	for i, locationID := range []string{models.DefaultLocationID, "north"} {
+		token := fmt.Sprintf("A%03d", i+1)
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: locationID, UserID: "user-1",
+			TokenNumber: token, Status: "WAITING", Priority: "NORMAL", Position: 1, CreatedAt: now, UpdatedAt: now,
+		}).Error)
+	}
+	server := queuegrpc.NewQueueServer(services.NewQueueService())
+	ctx := context.Background()
+
+	tokens := func(req *queuepb.GetCurrentQueueRequest) []string {
+		response, err := server.GetCurrentQueue(ctx, req)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		var tokens []string
+		for _, entry := range response.GetWaiting() {
+			tokens = append(tokens, entry.GetTokenNumber())
+		}
+		return tokens
+	}
+	assert.Equal(t, []string{"A001"}, tokens(&queuepb.GetCurrentQueueRequest{}))
+	assert.Equal(t, []string{"A002"}, tokens(&queuepb.GetCurrentQueueRequest{LocationId: "north"}))
+
+	_, err := server.GetCurrentQueue(ctx, &queuepb.GetCurrentQueueRequest{LocationId: "no such place"})
+	assert.Error(t, err)
+}
+
+func TestGetStaffActionLogs(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	assert.NoError(t, db.Create(&models.QueueEntry{
+		ID: "entry-1", OrderID: "order-1", LocationID: models.DefaultLocationID, UserID: "user-1",
+		TokenNumber: "A001", Status: "WAITING", Priority: "NORMAL", Position: 1, CreatedAt: now, UpdatedAt: now,
+	}).Error)
+	services.NewQueueService().LogStaffAction(context.Background(), "entry-1", "staff-1", "Test User", "ADD_NOTE", nil, nil, nil, nil, nil)
+	setupTestRouter()
+
+	w := serveJSON("GET", "/api/queue/entry-1/logs", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	var logs []models.StaffQueueActionLog
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &logs))
+	assert.Len(t, logs, 1)
+
+	// Staff working another location don't see the entry's logs
+	w = serveJSON("GET", "/api/queue/entry-1/logs?location_id=north", nil, "staff")
+	assert.Equal(t, 404, w.Code)
+	assert.NotContains(t, w.Body.String(), "ADD_NOTE")
+}
+
+func TestAdvanceEmptyQueue(t *testing.T) {
+	setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	setupTestRouter()
+
+	w := serveJSON("POST", "/api/queue/shifts/clock-in", nil, "staff")
+	assert.Equal(t, 201, w.Code)
+
+	// Nobody waiting is a conflict the client can act on, not a server error
+	w = serveJSON("POST", "/api/queue/advance", nil, "staff")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "QUEUE_EMPTY")
+}
+
+func TestReorderQueue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	for i, priority := range []string{"HIGH", "NORMAL", "NORMAL"} {
+		token := fmt.Sprintf("A%03d", i+1)
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: "WAITING", Priority: priority, Position: i + 1, CreatedAt: now, UpdatedAt: now,
+		}).Error)
+	}
+	setupTestRouter()
+
+	reorder := func(ids ...string) *httptest.ResponseRecorder {
+		return serveJSON("PUT", "/api/queue/reorder", map[string]interface{}{"entry_ids": ids}, "staff")
+	}
+
+	// Every waiting entry of the lane must be listed
+	w := reorder("entry-A003", "entry-A002")
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_REORDER")
+
+	// Priorities can't be overtaken
+	w = reorder("entry-A003", "entry-A001", "entry-A002")
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_REORDER")
+
+	w = reorder("entry-A001", "entry-A003", "entry-A002")
+	assert.Equal(t, 200, w.Code)
+	var reordered struct {
+		Data []models.QueueEntry `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &reordered))
+	var tokens []string
+	for _, entry := range reordered.Data {
+		tokens = append(tokens, fmt.Sprintf("%s@%d", entry.TokenNumber, entry.Position))
+	}
+	assert.Equal(t, []string{"A001@1", "A003@2", "A002@3"}, tokens)
+
+	var moved []string
+	assert.NoError(t, db.Model(&models.QueuePositionHistory{}).
+		Where("reason = ?", "Reordered by staff").Order("queue_entry_id").Pluck("queue_entry_id", &moved).Error)
+	assert.Equal(t, []string{"entry-A002", "entry-A003"}, moved)
+}
+
+func TestSkipEntry(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	for i := range 3 {
+		token := fmt.Sprintf("A%03d", i+1)
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: "WAITING", Priority: "NORMAL", Position: i + 1, CreatedAt: now, UpdatedAt: now,
+		}).Error)
+	}
+	setupTestRouter()
+
+	w := serveJSON("POST", "/api/queue/entry-A001/skip", map[string]interface{}{"positions": 1}, "staff")
+	assert.Equal(t, 200, w.Code)
+
+	positions := make(map[string]int)
+	var entries []models.QueueEntry
+	assert.NoError(t, db.Find(&entries).Error)
+	for _, entry := range entries {
+		positions[entry.TokenNumber] = entry.Position
+	}
+	assert.Equal(t, map[string]int{"A001": 2, "A002": 1, "A003": 3}, positions)
+
+	// One history row per entry, the moves keeping their old position and reason
+	var history []models.QueuePositionHistory
+	assert.NoError(t, db.Order("queue_entry_id").Find(&history).Error)
+	if assert.Len(t, history, 3) {
+		for i, moved := range []string{"1->2", "2->1"} {
+			assert.Equal(t, moved, fmt.Sprintf("%d->%d", history[i].OldPosition, history[i].NewPosition))
+			if assert.NotNil(t, history[i].Reason) {
+				assert.Equal(t, "Customer not responding at the counter", *history[i].Reason)
+			}
+		}
+		assert.Nil(t, history[2].Reason)
+	}
+}
+
+func TestRequeueEntry(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	for i, status := range []string{"WAITING", "WAITING", "NO_SHOW", "EXPIRED"} {
+		token := fmt.Sprintf("A%03d", i+1)
+		position := i + 1
+		if status != "WAITING" {
+			position = 0
+		}
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: status, Priority: "NORMAL", Position: position, CreatedAt: now, UpdatedAt: now,
+		}).Error)
+	}
+	setupTestRouter()
+
+	w := serveJSON("POST", "/api/queue/entry-A001/requeue", nil, "staff")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "NOT_REQUEUEABLE")
+
+	// No-shows go to the back of the lane by default
+	w = serveJSON("POST", "/api/queue/entry-A003/requeue", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+
+	// or to the front of their priority when asked
+	w = serveJSON("POST", "/api/queue/entry-A004/requeue", map[string]interface{}{"placement": "PRIORITY"}, "staff")
+	assert.Equal(t, 200, w.Code)
+
+	var lane []string
+	assert.NoError(t, db.Model(&models.QueueEntry{}).Where("status = ?", "WAITING").Order("position").Pluck("token_number", &lane).Error)
+	assert.Equal(t, []string{"A004", "A001", "A002", "A003"}, lane)
+
+	var logged int64
+	assert.NoError(t, db.Model(&models.StaffQueueActionLog{}).Where("action = ?", "REQUEUE").Count(&logged).Error)
+	assert.Equal(t, int64(2), logged)
+}
+
+func TestRecallEntry(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	for i, status := range []string{"READY", "WAITING"} {
+		token := fmt.Sprintf("A%03d", i+1)
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: status, Priority: "NORMAL", Position: i + 1, CreatedAt: now, UpdatedAt: now,
+		}).Error)
+	}
+	setupTestRouter()
+
+	w := serveJSON("POST", "/api/queue/entry-A002/recall", nil, "staff")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "NOT_RECALLABLE")
+
+	for i := 0; i < 2; i++ {
+		w = serveJSON("POST", "/api/queue/entry-A001/recall", nil, "staff")
+		assert.Equal(t, 200, w.Code)
+	}
+	var recalled struct {
+		Data models.QueueEntry `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &recalled))
+	assert.Equal(t, 2, recalled.Data.RecallCount)
+	if assert.NotNil(t, recalled.Data.LastRecalledAt) {
+		assert.True(t, recalled.Data.LastRecalledAt.Equal(now))
+	}
+
+	var reasons []string
+	assert.NoError(t, db.Model(&models.StaffQueueActionLog{}).
+		Where("action = ?", "RECALL").Order("reason").Pluck("reason", &reasons).Error)
+	assert.Equal(t, []string{"Recall #1", "Recall #2"}, reasons)
+}
+
+func TestLinkOrders(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	for i, status := range []string{"WAITING", "WAITING", "READY"} {
+		token := fmt.Sprintf("A%03d", i+1)
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: fmt.Sprintf("order-%d", i+1), LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: status, Priority: "NORMAL", Position: i + 1, CreatedAt: now, UpdatedAt: now,
+		}).Error)
+	}
+	setupTestRouter()
+
+	link := func(entryID string, orderIDs ...string) *httptest.ResponseRecorder {
+		return serveJSON("POST", "/api/queue/"+entryID+"/orders", map[string]interface{}{"order_ids": orderIDs}, "staff")
+	}
+
+	// Orders with a token of their own, and ready tokens, can't be linked
+	w := link("entry-A001", "order-2")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "CANNOT_LINK")
+	w = link("entry-A003", "order-4")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "CANNOT_LINK")
+
+	w = link("entry-A001", "order-4", "order-5")
+	assert.Equal(t, 200, w.Code)
+	var linked struct {
+		Data models.LinkedOrdersResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &linked))
+	var orderIDs []string
+	for _, order := range linked.Data.Orders {
+		assert.Equal(t, "A001", order.TokenNumber)
+		orderIDs = append(orderIDs, order.OrderID)
+	}
+	assert.ElementsMatch(t, []string{"order-1", "order-4", "order-5"}, orderIDs)
+	assert.Equal(t, 3, linked.Data.RemainingOrders)
+
+	// An order belongs to one party token
+	w = link("entry-A002", "order-4")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "CANNOT_LINK")
+
+	w = serveJSON("GET", "/api/queue/token/A001/orders", nil, "")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "order-5")
+}
+
+func TestCreateWalkIn(t *testing.T) {
+	db := setupTestSQLite(t, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	setupTestRedis(t)
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: models.DefaultLocationID, Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+	setupTestRouter()
+
+	w := serveJSON("POST", "/api/queue/walk-in", map[string]interface{}{"user_name": "Walk-in Customer", "item_count": 2}, "staff")
+	assert.Equal(t, 400, w.Code)
+
+	// Retrying with the same Idempotency-Key doesn't queue the customer twice
+	walkIn := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/queue/walk-in", strings.NewReader(`{"user_name":"Walk-in Customer","user_phone":"+15550100","item_count":2}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testToken("staff-1", "staff"))
+		req.Header.Set(middleware.IdempotencyKeyHeader, "walk-in-1")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	first, retry := walkIn(), walkIn()
+	assert.Equal(t, 201, first.Code)
+	assert.Equal(t, 201, retry.Code)
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+
+	var created struct {
+		Data models.QueueEntry `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(first.Body.Bytes(), &created))
+	entry := created.Data
+	assert.Equal(t, models.TokenTypeWalkIn, entry.TokenType)
+	assert.Equal(t, "WAITING", entry.Status)
+	assert.True(t, strings.HasPrefix(entry.TokenNumber, "A"))
+	assert.NotEmpty(t, entry.OrderID)
+
+	var queued int64
+	assert.NoError(t, db.Model(&models.QueueEntry{}).Count(&queued).Error)
+	assert.Equal(t, int64(1), queued)
+}
+
+func TestGetScheduledEntries(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	for i, entry := range []struct {
+		location, status string
+		activateIn       time.Duration
+	}{
+		{models.DefaultLocationID, "SCHEDULED", time.Hour},
+		{models.DefaultLocationID, "SCHEDULED", 30 * time.Minute},
+		{models.DefaultLocationID, "WAITING", 0},
+		{"downtown", "SCHEDULED", 10 * time.Minute},
+	} {
+		token := fmt.Sprintf("A%03d", i+1)
+		activateAt := now.Add(entry.activateIn)
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: entry.location, UserID: "user-1",
+			TokenNumber: token, Status: entry.status, Priority: "NORMAL", ScheduledActivateAt: &activateAt,
+			CreatedAt: now, UpdatedAt: now,
+		}).Error)
+	}
+	setupTestRouter()
+
+	scheduled := func(path string) []string {
+		w := serveJSON("GET", path, nil, "staff")
+		assert.Equal(t, 200, w.Code)
+		var entries []models.QueueEntry
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+		tokens := []string{}
+		for _, entry := range entries {
+			tokens = append(tokens, entry.TokenNumber)
+		}
+		return tokens
+	}
+
+	// The location's scheduled entries, next to join the queue first
+	assert.Equal(t, []string{"A002", "A001"}, scheduled("/api/queue/scheduled"))
+	assert.Equal(t, []string{"A004"}, scheduled("/api/queue/scheduled?location_id=downtown"))
+	assert.Equal(t, []string{}, scheduled("/api/queue/scheduled?location_id=uptown"))
+}
+
+func TestGetHourlyStatistics(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	setupTestRedis(t)
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: models.DefaultLocationID, Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+	at := func(hour, minute int) *time.Time {
+		moment := time.Date(2026, 3, 10, hour, minute, 0, 0, time.UTC)
+		return &moment
+	}
+	for i, entry := range []struct {
+		status              string
+		created, start, end *time.Time
+	}{
+		{"COMPLETED", at(10, 0), at(10, 5), at(10, 15)},
+		{"COMPLETED", at(10, 30), at(10, 50), at(11, 0)},
+		{"CANCELLED", at(11, 15), nil, nil},
+	} {
+		token := fmt.Sprintf("A%03d", i+1)
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: entry.status, Priority: "NORMAL", ActualStartTime: entry.start,
+			ActualReadyTime: entry.end, ActualCompletionTime: entry.end, CreatedAt: *entry.created, UpdatedAt: now,
+		}).Error)
+	}
+	setupTestRouter()
+
+	w := serveJSON("GET", "/api/queue/stats/hourly?date=10-03-2026", nil, "staff")
+	assert.Equal(t, 400, w.Code)
+
+	// Nothing is persisted until the statistics job runs
+	w = serveJSON("GET", "/api/queue/stats/hourly?date=2026-03-10", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "[]", w.Body.String())
+
+	assert.NoError(t, services.NewQueueService().UpdateStatistics(context.Background()))
+	w = serveJSON("GET", "/api/queue/stats/hourly?date=2026-03-10", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	var hours []models.QueueHourlyStatistics
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &hours))
+	if !assert.Len(t, hours, 2) {
+		return
+	}
+	assert.Equal(t, 10, hours[0].Hour)
+	assert.Equal(t, 2, hours[0].OrderCount)
+	assert.Equal(t, 2, hours[0].CompletedCount)
+	assert.Equal(t, 5, hours[0].WaitTimeP50)
+	assert.Equal(t, 20, hours[0].WaitTimeP90)
+	assert.Equal(t, 10, hours[0].PrepTimeP90)
+	assert.Equal(t, 11, hours[1].Hour)
+	assert.Equal(t, 1, hours[1].OrderCount)
+	assert.Equal(t, 1, hours[1].CancelledCount)
+	assert.Zero(t, hours[1].WaitTimeP50)
+}
+
+func TestGetSLABreaches(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	simulated := clock.NewSimulated(now)
+	services.SetClock(simulated)
+	assert.NoError(t, db.Model(&models.QueueConfiguration{}).Where("id = ?", "config-default").
+		Updates(map[string]interface{}{"max_wait_time_alert": 30, "sla_minutes_high": 10}).Error)
+	for i, entry := range []struct {
+		status, priority string
+		waited           time.Duration
+	}{
+		{"WAITING", "NORMAL", time.Hour},
+		{"WAITING", "HIGH", 5 * time.Minute},
+		{"IN_PROGRESS", "NORMAL", 10 * time.Minute},
+		{"COMPLETED", "NORMAL", 2 * time.Hour},
+	} {
+		token := fmt.Sprintf("A%03d", i+1)
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: entry.status, Priority: entry.priority, Position: i + 1,
+			CreatedAt: now.Add(-entry.waited), UpdatedAt: now,
+		}).Error)
+	}
+	service := services.NewQueueService()
+	setupTestRouter()
+
+	// Past the wait alert for normal priority, and past its own SLA for high
+	breached, err := service.CheckSLABreaches(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, breached)
+	simulated.Advance(10 * time.Minute)
+	breached, err = service.CheckSLABreaches(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, breached)
+
+	w := serveJSON("GET", "/api/queue/sla/breached", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	var breaches []models.SLABreach
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &breaches))
+	if assert.Len(t, breaches, 2) {
+		assert.Equal(t, "A001", breaches[0].TokenNumber)
+		assert.Equal(t, 70, breaches[0].WaitedMinutes)
+		assert.Equal(t, 30, breaches[0].SLAMinutes)
+		assert.True(t, breaches[0].BreachedAt.Equal(now))
+		assert.Equal(t, "A002", breaches[1].TokenNumber)
+		assert.Equal(t, 15, breaches[1].WaitedMinutes)
+		assert.Equal(t, 10, breaches[1].SLAMinutes)
+	}
+}
+
+func TestGetLoadCurve(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	for i, sample := range []struct {
+		location  string
+		sampledAt time.Time
+		waiting   int
+	}{
+		{models.DefaultLocationID, now.Add(-time.Hour), 8},
+		{models.DefaultLocationID, now.Add(-2 * time.Hour), 3},
+		{models.DefaultLocationID, now.Add(-24 * time.Hour), 12},
+		{"downtown", now.Add(-time.Hour), 20},
+	} {
+		assert.NoError(t, db.Create(&models.QueueLoadSample{
+			ID: fmt.Sprintf("sample-%d", i), LocationID: sample.location, SampledAt: sample.sampledAt,
+			WaitingCount: sample.waiting, InProgressCount: 2, MaxConcurrentOrders: 4, KitchenLoad: 50,
+		}).Error)
+	}
+	peakTime := "11:00"
+	assert.NoError(t, db.Create(&models.QueueStatistics{
+		ID: "stats-1", LocationID: models.DefaultLocationID, Date: clock.Date(now),
+		PeakLoad: 50, PeakLoadTime: &peakTime, PeakQueueLength: 8, PeakQueueLengthTime: &peakTime,
+	}).Error)
+	setupTestRouter()
+
+	w := serveJSON("GET", "/api/queue/stats/load?date=2026-03-10", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	var curve models.LoadCurveResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &curve))
+	assert.Equal(t, models.DefaultLocationID, curve.LocationID)
+	assert.Equal(t, 8, curve.PeakQueueLength)
+	assert.Equal(t, &peakTime, curve.PeakQueueLengthTime)
+	// The location's samples of the day, in time order
+	var waiting []int
+	for _, sample := range curve.Samples {
+		waiting = append(waiting, sample.WaitingCount)
+	}
+	assert.Equal(t, []int{3, 8}, waiting)
+
+	// Days without statistics have no peaks
+	w = serveJSON("GET", "/api/queue/stats/load?date=2026-03-09", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	curve = models.LoadCurveResponse{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &curve))
+	assert.Len(t, curve.Samples, 1)
+	assert.Zero(t, curve.PeakQueueLength)
+	assert.Nil(t, curve.PeakQueueLengthTime)
+}
+
+func TestExportStatistics(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	for i, day := range []int{1, 9, 10} {
+		date := time.Date(2026, 3, day, 0, 0, 0, 0, time.UTC)
+		assert.NoError(t, db.Create(&models.QueueStatistics{
+			ID: fmt.Sprintf("stats-%d", i), LocationID: models.DefaultLocationID, Date: date, CompletedToday: day,
+		}).Error)
+		assert.NoError(t, db.Create(&models.QueueHourlyStatistics{
+			ID: fmt.Sprintf("hourly-%d", i), LocationID: models.DefaultLocationID, Date: date, Hour: 11, OrderCount: day,
+		}).Error)
+	}
+	setupTestRouter()
+
+	w := serveJSON("GET", "/api/queue/stats/export?format=pdf", nil, "staff")
+	assert.Equal(t, 400, w.Code)
+	w = serveJSON("GET", "/api/queue/stats/export?from=2026-03-10&to=2026-03-09", nil, "staff")
+	assert.Equal(t, 400, w.Code)
+
+	// A Daily table then an Hourly one, separated by an empty line
+	w = serveJSON("GET", "/api/queue/stats/export?from=2026-03-09&to=2026-03-10&format=csv", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "queue-stats_2026-03-09_2026-03-10.csv")
+	var rows []string
+	for _, line := range strings.Split(strings.TrimSpace(w.Body.String()), "\n") {
+		cells := strings.Split(line, ",")
+		rows = append(rows, strings.Join(cells[:min(len(cells), 3)], ","))
+	}
+	assert.Equal(t, []string{
+		"date,completed,cancelled", "2026-03-09,9,0", "2026-03-10,10,0",
+		"",
+		"date,hour,orders", "2026-03-09,11,9", "2026-03-10,11,10",
+	}, rows)
+
+	w = serveJSON("GET", "/api/queue/stats/export?from=2026-03-09&to=2026-03-10&format=xlsx", nil, "staff")
+	assert.Equal(t, 200, w.Code)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "PK"))
+}
+
+func TestExportQueueEntries(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	for i, entry := range []struct {
+		status, staff string
+		day           int
+	}{
+		{"COMPLETED", "staff-1", 9},
+		{"CANCELLED", "staff-1", 10},
+		{"COMPLETED", "staff-2", 10},
+	} {
+		token := fmt.Sprintf("A%03d", i+1)
+		createdAt := time.Date(2026, 3, entry.day, 11, i, 0, 0, time.UTC)
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: entry.status, Priority: "NORMAL", AssignedStaff: &entry.staff,
+			CreatedAt: createdAt, UpdatedAt: createdAt,
+		}).Error)
+	}
+	setupTestRouter()
+
+	w := serveJSON("GET", "/api/queue/admin/entries/export?status=LOST", nil, "admin")
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "INVALID_EXPORT_FILTER")
+
+	w = serveJSON("GET", "/api/queue/admin/entries/export?format=json&status=completed&from=2026-03-10&to=2026-03-10", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	var entries []models.QueueEntry
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entries))
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "A003", entries[0].TokenNumber)
+	}
+
+	w = serveJSON("GET", "/api/queue/admin/entries/export?staff=staff-1", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "queue-entries.csv")
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.True(t, strings.HasPrefix(lines[0], "id,order_id,"))
+		assert.True(t, strings.HasPrefix(lines[1], "entry-A001,"))
+		assert.True(t, strings.HasPrefix(lines[2], "entry-A002,"))
+	}
+
+	// Nothing matching still makes a valid document
+	w = serveJSON("GET", "/api/queue/admin/entries/export?format=json&from=2026-03-11", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	assert.Equal(t, "[]", w.Body.String())
+}
+
+func TestRestoreDeletedRecord(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	entry := models.QueueEntry{
+		ID: "entry-1", OrderID: "order-1", LocationID: models.DefaultLocationID, UserID: "user-1",
+		TokenNumber: "A001", Status: "WAITING", Priority: "NORMAL", CreatedAt: now, UpdatedAt: now,
+		DeletedAt: gorm.DeletedAt{Time: now, Valid: true},
+	}
+	assert.NoError(t, db.Create(&entry).Error)
+	assert.NoError(t, db.Create(&models.QueueEntryTombstone{
+		ID: "tombstone-1", QueueEntryID: entry.ID, OrderID: entry.OrderID, TokenNumber: entry.TokenNumber,
+		Action: "DELETED", PerformedBy: "admin-1", CreatedAt: now,
+	}).Error)
+	assert.NoError(t, db.Create(&models.QueueDisplayAnnouncement{
+		ID: "announcement-1", Message: "Kitchen closes at 22:00", Type: "INFO", IsActive: true,
+		CreatedAt: now, UpdatedAt: now,
+	}).Error)
+	setupTestRouter()
+
+	w := serveJSON("POST", "/api/queue/admin/deleted/orders/entry-1/restore", nil, "admin")
+	assert.Equal(t, 400, w.Code)
+
+	// Only deleted records can be restored
+	w = serveJSON("POST", "/api/queue/admin/deleted/announcements/announcement-1/restore", nil, "admin")
+	assert.Equal(t, 404, w.Code)
+
+	w = serveJSON("DELETE", "/api/queue/admin/announcements/announcement-1", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	w = serveJSON("GET", "/api/queue/admin/deleted/announcements", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	assert.Contains(t, w.Body.String(), "announcement-1")
+
+	w = serveJSON("POST", "/api/queue/admin/deleted/announcements/announcement-1/restore", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	var announcements int64
+	db.Model(&models.QueueDisplayAnnouncement{}).Where("id = ?", "announcement-1").Count(&announcements)
+	assert.Equal(t, int64(1), announcements)
+
+	w = serveJSON("POST", "/api/queue/admin/deleted/entries/entry-1/restore", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	var restored struct {
+		Data models.QueueEntry `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &restored))
+	assert.Equal(t, "A001", restored.Data.TokenNumber)
+	assert.False(t, restored.Data.DeletedAt.Valid)
+
+	// Restoring drops the tombstone so syncing consumers keep the entry
+	var tombstones int64
+	db.Model(&models.QueueEntryTombstone{}).Where("queue_entry_id = ?", "entry-1").Count(&tombstones)
+	assert.Equal(t, int64(0), tombstones)
+
+	w = serveJSON("GET", "/api/queue/admin/deleted/entries", nil, "admin")
+	assert.Equal(t, 200, w.Code)
+	assert.NotContains(t, w.Body.String(), "entry-1")
+}
+
+func TestResetQueue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	assert.NoError(t, db.Create(&models.QueueLocation{ID: models.DefaultLocationID, Name: "Main", TokenPrefix: "A", IsActive: true}).Error)
+	seed := func(i int, status string) {
+		token := fmt.Sprintf("A%03d", i)
+		assert.NoError(t, db.Create(&models.QueueEntry{
+			ID: "entry-" + token, OrderID: "order-" + token, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: status, Priority: "NORMAL", Position: i,
+			CreatedAt: now.Add(time.Duration(i) * time.Minute), UpdatedAt: now,
+		}).Error)
+	}
+	seed(1, "WAITING")
+	seed(2, "IN_PROGRESS")
+	seed(3, "COMPLETED")
+	setupTestRouter()
+
+	type preview struct {
+		Data models.QueueResetPreview `json:"data"`
+	}
+	reset := func(body map[string]interface{}) (int, preview) {
+		w := serveJSON("POST", "/api/queue/admin/reset", body, "admin")
+		var p preview
+		json.Unmarshal(w.Body.Bytes(), &p)
+		return w.Code, p
+	}
+
+	// Without a confirmation token nothing changes
+	code, first := reset(map[string]interface{}{"outcome": "EXPIRED"})
+	assert.Equal(t, 428, code)
+	assert.Equal(t, 2, first.Data.ActiveEntries)
+	assert.Equal(t, map[string]int{"WAITING": 1, "IN_PROGRESS": 1}, first.Data.ByStatus)
+	assert.NotEmpty(t, first.Data.ConfirmationToken)
+
+	// A token from before the queue changed no longer confirms
+	seed(4, "WAITING")
+	code, second := reset(map[string]interface{}{"outcome": "EXPIRED", "confirmation_token": first.Data.ConfirmationToken})
+	assert.Equal(t, 428, code)
+	assert.Equal(t, 3, second.Data.ActiveEntries)
+	assert.NotEqual(t, first.Data.ConfirmationToken, second.Data.ConfirmationToken)
+
+	// Today's A numbers are taken, so restarting them needs a new prefix
+	_, third := reset(map[string]interface{}{"reset_token_counter": true})
+	w := serveJSON("POST", "/api/queue/admin/reset", map[string]interface{}{
+		"reset_token_counter": true, "confirmation_token": third.Data.ConfirmationToken,
+	}, "admin")
+	assert.Equal(t, 409, w.Code)
+	assert.Contains(t, w.Body.String(), "TOKEN_NUMBERS_TAKEN")
+	var waiting int64
+	db.Model(&models.QueueEntry{}).Where("status = ?", "WAITING").Count(&waiting)
+	assert.Equal(t, int64(2), waiting)
+
+	_, fourth := reset(map[string]interface{}{"reset_token_counter": true, "token_prefix": "b"})
+	assert.Equal(t, "B", fourth.Data.TokenPrefix)
+	w = serveJSON("POST", "/api/queue/admin/reset", map[string]interface{}{
+		"reset_token_counter": true, "token_prefix": "b", "confirmation_token": fourth.Data.ConfirmationToken,
+	}, "admin")
+	assert.Equal(t, 200, w.Code)
+	var result struct {
+		Data models.QueueResetResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 3, result.Data.EntriesReset)
+	assert.Equal(t, "EXPIRED", result.Data.Outcome)
+	assert.Equal(t, "B", result.Data.TokenPrefix)
+
+	statuses := map[string]string{}
+	var entries []models.QueueEntry
+	db.Order("token_number").Find(&entries)
+	for _, entry := range entries {
+		statuses[entry.TokenNumber] = entry.Status
+		if entry.Status == "EXPIRED" {
+			assert.Equal(t, 0, entry.Position, entry.TokenNumber)
+		}
+	}
+	assert.Equal(t, map[string]string{"A001": "EXPIRED", "A002": "EXPIRED", "A003": "COMPLETED", "A004": "EXPIRED"}, statuses)
+	var location models.QueueLocation
+	db.First(&location, "id = ?", models.DefaultLocationID)
+	assert.Equal(t, "B", location.TokenPrefix)
+	var logs int64
+	db.Model(&models.StaffQueueActionLog{}).Where("action = ? AND reason = ?", "RESET", "Queue reset by admin").Count(&logs)
+	assert.Equal(t, int64(3), logs)
+}
+
+func TestEnumValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	assert.NoError(t, handlers.RegisterValidators())
+
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware())
+	r.PUT("/status", func(c *gin.Context) {
+		var req models.UpdateQueueStatusRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(err).SetType(gin.ErrorTypeBind).SetMeta("Invalid request")
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/status", strings.NewReader(`{"status":"DONE"}`))
+	r.ServeHTTP(w, req)
+
+	var response models.ErrorResponse
+	assert.Equal(t, 400, w.Code)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "INVALID_REQUEST", response.Code)
+	assert.Contains(t, response.Message, "status must be one of PENDING_PAYMENT, SCHEDULED, WAITING")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PUT", "/status", strings.NewReader(`{"status":"READY"}`))
+	r.ServeHTTP(w, req)
+	assert.Equal(t, 204, w.Code)
+}
+
+func TestLanguageNegotiation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.LanguageMiddleware(), middleware.ErrorMiddleware())
+	r.GET("/duplicate", func(c *gin.Context) {
+		c.Error(services.ErrDuplicateOrder).SetMeta("Failed to create queue entry")
+	})
+
+	for acceptLanguage, expected := range map[string]string{
+		"":                   "en",
+		"es-MX,es;q=0.9":     "es",
+		"fr-FR, hi;q=0.8":    "hi",
+		"es;q=0.5, en;q=0.9": "en",
+		"es;q=0, fr":         "en",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/duplicate", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		r.ServeHTTP(w, req)
+
+		var response models.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response), acceptLanguage)
+		assert.Equal(t, expected, w.Header().Get("Content-Language"), acceptLanguage)
+		assert.Equal(t, "DUPLICATE_ORDER", response.Code, acceptLanguage)
+		if expected == "es" {
+			assert.Equal(t, "No se pudo añadir el pedido a la cola", response.Error)
+			assert.Equal(t, "El pedido ya está en la cola", response.Message)
+		}
+	}
+}
+
+func TestBusinessDay(t *testing.T) {
+	zone, err := time.LoadLocation("America/New_York")
+	if !assert.NoError(t, err) {
+		return
+	}
+	clock.SetZone(zone)
+	defer clock.SetZone(time.UTC)
+
+	// 03:00 UTC is still the evening before in New York
+	assert.Equal(t, time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC), clock.Date(time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC)))
+
+	// The day clocks spring forward is 23 hours long
+	start, end := clock.DayBounds(time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 3, 8, 5, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 3, 9, 4, 0, 0, 0, time.UTC), end)
+}
+
+func TestTokenIntrospection(t *testing.T) {
+	calls := 0
+	authService := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/api/auth/introspect", r.URL.Path)
+		if r.FormValue("token") == "staff-token" {
+			w.Write([]byte(`{"active":true,"sub":"staff-1","name":"Sam","role":"staff"}`))
+			return
+		}
+		w.Write([]byte(`{"active":false}`))
+	}))
+	defer authService.Close()
+
+	middleware.SetTokenIntrospector(auth.NewIntrospector(&config.Config{
+		AuthServiceURL:                authService.URL,
+		AuthIntrospectionTimeoutMs:    1000,
+		AuthIntrospectionCacheSeconds: 30,
+	}))
+	defer middleware.SetTokenIntrospector(nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/me", middleware.AuthMiddleware(), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("user_id"))
+	})
+
+	for _, token := range []string{"staff-token", "staff-token", "revoked-token"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		r.ServeHTTP(w, req)
+
+		if token == "staff-token" {
+			assert.Equal(t, 200, w.Code)
+			assert.Equal(t, "staff-1", w.Body.String())
+		} else {
+			assert.Equal(t, 401, w.Code)
+		}
+	}
+	// The second request was answered from the cache
+	assert.Equal(t, 2, calls)
+}
+
+// writeCert issues a certificate for name, signed by parent (self-signed when
+// nil), and writes it and its key as PEM files in dir
+func writeCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, name+".pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	os.WriteFile(filepath.Join(dir, name+"-key.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := writeCert(t, dir, "ca", nil, nil)
+	writeCert(t, dir, "localhost", ca, caKey)
+	writeCert(t, dir, "order-service", ca, caKey)
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	serverTLS, err := mtls.ServerConfig(path("localhost.pem"), path("localhost-key.pem"), path("ca.pem"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = serverTLS
+	server.StartTLS()
+	defer server.Close()
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	// Without a client certificate the handshake is refused
+	anonymousTLS, err := mtls.ClientConfig(path("ca.pem"), "", "", "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = (&http.Client{Transport: &http.Transport{TLSClientConfig: anonymousTLS}}).Get(url)
+	assert.Error(t, err)
+
+	clientTLS, err := mtls.ClientConfig(path("ca.pem"), path("order-service.pem"), path("order-service-key.pem"), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	resp, err := (&http.Client{Transport: &http.Transport{TLSClientConfig: clientTLS}}).Get(url)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "order-service", string(body))
+}
+
+func TestLookupCode(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	db := setupTestSQLite(t, now)
+	entry := func(id, token string) *models.QueueEntry {
+		entry := &models.QueueEntry{
+			ID: id, OrderID: "order-" + id, LocationID: models.DefaultLocationID, UserID: "user-1",
+			TokenNumber: token, Status: "WAITING", Priority: "NORMAL", Position: 1, CreatedAt: now, UpdatedAt: now,
+		}
+		assert.NoError(t, db.Create(entry).Error)
+		return entry
+	}
+	first := entry("entry-1", "A001")
+	entry("entry-2", "A002")
+	services.SetLookupCodeSecret("test-secret", true)
+	defer services.SetLookupCodeSecret("", false)
+	service := services.NewQueueService()
+	ctx := context.Background()
+
+	code := services.LookupCode(first)
+	assert.True(t, strings.HasPrefix(code, "A001-"))
+
+	token, err := service.ResolveLookupCode(ctx, code)
+	assert.NoError(t, err)
+	assert.Equal(t, "A001", token)
+
+	// Neither the bare token nor another token's signature gets through
+	_, err = service.ResolveLookupCode(ctx, "A001")
+	assert.ErrorIs(t, err, services.ErrLookupCodeRequired)
+	_, err = service.ResolveLookupCode(ctx, "A002"+strings.TrimPrefix(code, "A001"))
+	assert.ErrorIs(t, err, services.ErrLookupCodeRequired)
+
+	// Once A001 is reissued on a later day, the old code doesn't reach the new holder
+	assert.NoError(t, db.Unscoped().Delete(first).Error)
+	reissued := entry("entry-9", "A001")
+	_, err = service.ResolveLookupCode(ctx, code)
+	assert.ErrorIs(t, err, services.ErrLookupCodeRequired)
+	token, err = service.ResolveLookupCode(ctx, services.LookupCode(reissued))
+	assert.NoError(t, err)
+	assert.Equal(t, "A001", token)
+
+	// Optional codes still accept bare tokens
+	services.SetLookupCodeSecret("test-secret", false)
+	token, err = service.ResolveLookupCode(ctx, "A002")
+	assert.NoError(t, err)
+	assert.Equal(t, "A002", token)
+}
+
+func TestExplainPositionLookup(t *testing.T) {
+	setupTestRedis(t)
+	services.SetLookupCodeSecret("test-secret", true)
+	defer services.SetLookupCodeSecret("", false)
+	middleware.SetLookupRateLimit(1)
+	defer middleware.SetLookupRateLimit(60)
+	setupTestRouter()
+
+	// The explanation takes a lookup code like the other token lookups...
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/queue/A001/why", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, 400, w.Code)
+	assert.Contains(t, w.Body.String(), "LOOKUP_CODE_REQUIRED")
+
+	// ...and counts against the client's lookup limit
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/queue/A002/why", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, 429, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Equal(t, w.Header().Get("Retry-After"), w.Header().Get("X-RateLimit-Reset"))
+}
+
+func TestAdminNetworks(t *testing.T) {
+	assert.NoError(t, middleware.SetAdminNetworks([]string{"10.8.0.0/16", "192.168.1.10"}))
+	defer middleware.SetAdminNetworks(nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/admin", middleware.AdminNetworkMiddleware(), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	for remoteAddr, expected := range map[string]int{
+		"10.8.3.4:51000":     204,
+		"192.168.1.10:51000": 204,
+		"192.168.1.11:51000": 403,
+		"203.0.113.7:51000":  403,
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin", nil)
+		req.RemoteAddr = remoteAddr
+		r.ServeHTTP(w, req)
+		assert.Equal(t, expected, w.Code, remoteAddr)
+	}
+
+	forwarded := func(remoteAddr, forwardedFor string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/admin", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Without trusted proxies a client can't claim an admin network
+	assert.Equal(t, 403, forwarded("203.0.113.7:51000", "10.8.3.4"))
+
+	// Behind a trusted proxy, the client it forwards for is checked
+	assert.NoError(t, r.SetTrustedProxies([]string{"192.168.1.10"}))
+	middleware.SetForwardedForTrusted(true)
+	defer middleware.SetForwardedForTrusted(false)
+	assert.Equal(t, 204, forwarded("192.168.1.10:51000", "10.8.3.4"))
+	assert.Equal(t, 403, forwarded("192.168.1.10:51000", "203.0.113.7"))
+	assert.Equal(t, 403, forwarded("203.0.113.7:51000", "10.8.3.4"))
+}
+
+type auditRecorderFunc func(entry *models.AuditLogEntry)
+
+func (f auditRecorderFunc) RecordAuditLog(_ context.Context, entry *models.AuditLogEntry) error {
+	f(entry)
+	return nil
+}
+
+func TestAuditMiddleware(t *testing.T) {
+	var recorded []*models.AuditLogEntry
+	middleware.SetAuditRecorder(auditRecorderFunc(func(entry *models.AuditLogEntry) {
+		recorded = append(recorded, entry)
+	}))
+	defer middleware.SetAuditRecorder(nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.ErrorMiddleware(), func(c *gin.Context) {
+		c.Set("user_id", "staff-1")
+		c.Set("user_role", "staff")
+	}, middleware.AuditMiddleware())
+	r.GET("/entries/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.POST("/entries/:id/walk-in", func(c *gin.Context) {
+		// The handler still reads the whole body
+		var req models.CreateWalkInRequest
+		assert.NoError(t, c.ShouldBindJSON(&req))
+		assert.Equal(t, "+15550100", req.UserPhone)
+		c.Error(services.ErrDuplicateOrder).SetMeta("Failed to create queue entry")
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/entries/42", nil),
+		httptest.NewRequest("POST", "/entries/42/walk-in", strings.NewReader(`{"user_name":"Ana","user_phone":"+15550100","item_count":2}`)),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Reads aren't audited
+	if !assert.Len(t, recorded, 1) {
+		return
+	}
+	entry := recorded[0]
+	assert.Equal(t, "staff-1", entry.ActorID)
+	assert.Equal(t, "/entries/:id/walk-in", entry.Route)
+	assert.Equal(t, 409, entry.StatusCode)
+	if assert.NotNil(t, entry.ErrorCode) {
+		assert.Equal(t, "DUPLICATE_ORDER", *entry.ErrorCode)
+	}
+	if assert.NotNil(t, entry.Payload) {
+		assert.JSONEq(t, `{"user_name":"Ana","user_phone":"[REDACTED]","item_count":2}`, *entry.Payload)
+	}
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// RegisterGormCallbacks counts failed GORM operations in DBErrorsTotal
+func RegisterGormCallbacks(db *gorm.DB) error {
+	record := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+				DBErrorsTotal.WithLabelValues(operation).Inc()
+			}
+		}
+	}
+
+	callback := db.Callback()
+	if err := callback.Create().After("gorm:create").Register("metrics:create", record("create")); err != nil {
+		return err
+	}
+	if err := callback.Query().After("gorm:query").Register("metrics:query", record("query")); err != nil {
+		return err
+	}
+	if err := callback.Update().After("gorm:update").Register("metrics:update", record("update")); err != nil {
+		return err
+	}
+	if err := callback.Delete().After("gorm:delete").Register("metrics:delete", record("delete")); err != nil {
+		return err
+	}
+	if err := callback.Row().After("gorm:row").Register("metrics:row", record("row")); err != nil {
+		return err
+	}
+	return callback.Raw().After("gorm:raw").Register("metrics:raw", record("raw"))
+}
+
+// RegisterDBStats publishes a connection pool's usage (open, in-use and idle
+// connections, waits for a free one) as go_sql_* metrics labelled db_name
+func RegisterDBStats(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// RedisHook counts failed Redis commands in RedisErrorsTotal
+type RedisHook struct{}
+
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			RedisErrorsTotal.WithLabelValues("dial").Inc()
+		}
+		return conn, err
+	}
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			RedisErrorsTotal.WithLabelValues(cmd.Name()).Inc()
+		}
+		return err
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		if err != nil && !errors.Is(err, redis.Nil) {
+			RedisErrorsTotal.WithLabelValues("pipeline").Inc()
+		}
+		return err
+	}
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "queue_service"
+
+var (
+	// HTTPRequestsTotal counts HTTP requests by method, route and status code
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests processed.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration tracks HTTP request latency by method and route
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// KafkaMessagesConsumed counts consumed Kafka messages by topic and result
+	KafkaMessagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_messages_consumed_total",
+		Help:      "Total number of Kafka messages consumed.",
+	}, []string{"topic", "result"})
+
+	// KafkaMessagesProduced counts produced Kafka messages by topic and result
+	KafkaMessagesProduced = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_messages_produced_total",
+		Help:      "Total number of Kafka messages produced.",
+	}, []string{"topic", "result"})
+
+	// KafkaProducerConnected is 1 while the shared producer can reach the brokers
+	KafkaProducerConnected = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "kafka_producer_connected",
+		Help:      "Whether the Kafka producer can reach the brokers (1) or not (0).",
+	})
+
+	// KafkaConsumerPaused is 1 while order event consumption is paused by an admin
+	KafkaConsumerPaused = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "kafka_consumer_paused",
+		Help:      "Whether Kafka consumption is paused (1) or running (0).",
+	})
+
+	// DBBreakerState is the database circuit breaker state
+	DBBreakerState = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "db_breaker_state",
+		Help:      "Database circuit breaker state: closed (0), half-open (1) or open (2).",
+	})
+
+	// MenuBreakerState is the menu client circuit breaker state
+	MenuBreakerState = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "menu_breaker_state",
+		Help:      "Menu Service circuit breaker state: closed (0), half-open (1) or open (2).",
+	})
+
+	// MenuPrepTimeCacheLookupsTotal counts prep-time cache lookups per item, by result
+	MenuPrepTimeCacheLookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "menu_prep_time_cache_lookups_total",
+		Help:      "Total number of per-item prep-time cache lookups by result (hit or miss).",
+	}, []string{"result"})
+
+	// DuplicateSuppressionsTotal counts duplicate order events that were dropped, by reason
+	DuplicateSuppressionsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "duplicate_suppressions_total",
+		Help:      "Total number of duplicate events suppressed instead of being processed.",
+	}, []string{"topic", "reason"})
+
+	// DepthLimitFallbacksTotal counts new entries moved down because a lane or priority was full
+	DepthLimitFallbacksTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "depth_limit_fallbacks_total",
+		Help:      "Total number of new entries placed in a lower lane or priority because of a depth limit.",
+	}, []string{"kind", "from"})
+
+	// DBErrorsTotal counts failed database operations by operation type
+	DBErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_errors_total",
+		Help:      "Total number of failed database operations.",
+	}, []string{"operation"})
+
+	// RedisErrorsTotal counts failed Redis commands by command name
+	RedisErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_errors_total",
+		Help:      "Total number of failed Redis commands.",
+	}, []string{"command"})
+)
+
+// Result labels
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+// Duplicate suppression reasons
+const (
+	DuplicateProcessedEvent = "processed_event"
+	DuplicateAlreadyQueued  = "already_queued"
+)
+
+// ResultLabel converts an error into a result label
+func ResultLabel(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
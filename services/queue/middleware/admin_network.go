@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminNetworks are the networks admin routes may be called from; empty
+// allows any
+var adminNetworks []*net.IPNet
+
+// forwardedForTrusted is whether trusted proxies are configured, so the
+// client IP may come from X-Forwarded-For
+var forwardedForTrusted bool
+
+// SetForwardedForTrusted lets the admin network check take the client IP
+// from X-Forwarded-For (as far as the router trusts it) rather than from the
+// connection. Enable it only with TRUSTED_PROXIES configured.
+func SetForwardedForTrusted(trusted bool) {
+	forwardedForTrusted = trusted
+}
+
+// SetAdminNetworks restricts admin routes to clients in the given CIDRs or
+// IPs (internal networks, VPN); none lifts the restriction
+func SetAdminNetworks(cidrs []string) error {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return fmt.Errorf("invalid admin network %q", cidr)
+			}
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid admin network %q: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	adminNetworks = networks
+	return nil
+}
+
+// AdminNetworkMiddleware refuses admin requests from outside the admin
+// networks with 403, on top of the role checks. The client IP is the
+// connection's, or with SetForwardedForTrusted the one trusted proxies
+// forward.
+func AdminNetworkMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(adminNetworks) == 0 {
+			c.Next()
+			return
+		}
+
+		clientIP := c.RemoteIP()
+		if forwardedForTrusted {
+			clientIP = c.ClientIP()
+		}
+		if ip := net.ParseIP(clientIP); ip != nil {
+			for _, network := range adminNetworks {
+				if network.Contains(ip) {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access is not allowed from this network"})
+		c.Abort()
+	}
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"gin-quickstart/models"
+	"gin-quickstart/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditRecorder stores audit log entries. It is satisfied by the QueueService.
+type AuditRecorder interface {
+	RecordAuditLog(ctx context.Context, entry *models.AuditLogEntry) error
+}
+
+// auditRecorder, when set, receives an entry for every audited request
+var auditRecorder AuditRecorder
+
+// SetAuditRecorder enables the audit log; nil disables it
+func SetAuditRecorder(recorder AuditRecorder) {
+	auditRecorder = recorder
+}
+
+// maxAuditPayloadBytes caps the request body kept in the audit log; larger
+// bodies are recorded without it
+const maxAuditPayloadBytes = 64 << 10
+
+// redactedAuditFields are request fields kept out of the audit log: any
+// field whose name contains one of them, at any depth
+var redactedAuditFields = []string{"password", "secret", "phone", "email", "confirmation_token"}
+
+// AuditMiddleware records every mutating request (POST, PUT, PATCH, DELETE)
+// in the audit log once it has been handled: the actor, the route, the JSON
+// request body with personal and secret fields redacted, and the status and
+// error code it ended with. Mount it after authentication. A failure to
+// record is logged and doesn't fail the request.
+func AuditMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auditRecorder == nil || !auditedMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		payload := auditPayload(c)
+
+		c.Next()
+
+		// Errors recorded with c.Error are answered later by ErrorMiddleware
+		status := c.Writer.Status()
+		var errorCode *string
+		if len(c.Errors) > 0 && !c.Writer.Written() {
+			var code string
+			status, code = errorStatus(c.Errors.Last())
+			errorCode = &code
+		}
+
+		entry := &models.AuditLogEntry{
+			ActorID:    c.GetString("user_id"),
+			ActorRole:  c.GetString("user_role"),
+			Method:     c.Request.Method,
+			Route:      c.FullPath(),
+			Path:       c.Request.URL.Path,
+			LocationID: services.LocationFromContext(c.Request.Context()),
+			Payload:    payload,
+			StatusCode: status,
+			ErrorCode:  errorCode,
+			ClientIP:   c.ClientIP(),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if name := c.GetString("user_name"); name != "" {
+			entry.ActorName = &name
+		}
+		if requestID := c.GetString("request_id"); requestID != "" {
+			entry.RequestID = &requestID
+		}
+
+		// The request may have timed out; the entry is recorded regardless
+		if err := auditRecorder.RecordAuditLog(context.WithoutCancel(c.Request.Context()), entry); err != nil {
+			log.Printf("Failed to record audit log for %s %s: %v", entry.Method, entry.Path, err)
+		}
+	}
+}
+
+func auditedMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// auditPayload returns the redacted JSON request body, leaving the body for
+// the handler to read. Empty, oversized and non-JSON bodies give nil.
+func auditPayload(c *gin.Context) *string {
+	if c.Request.Body == nil {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxAuditPayloadBytes+1))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	if err != nil || len(body) == 0 || len(body) > maxAuditPayloadBytes {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil
+	}
+	redacted, err := json.Marshal(redactAuditValue(value))
+	if err != nil {
+		return nil
+	}
+	payload := string(redacted)
+	return &payload
+}
+
+func redactAuditValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if redactedAuditField(key) {
+				v[key] = "[REDACTED]"
+			} else {
+				v[key] = redactAuditValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactAuditValue(item)
+		}
+	}
+	return value
+}
+
+func redactedAuditField(key string) bool {
+	key = strings.ToLower(key)
+	for _, field := range redactedAuditFields {
+		if strings.Contains(key, field) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,62 @@
 package middleware
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"time"
 
+	"gin-quickstart/auth"
+
 	"github.com/gin-gonic/gin"
 )
 
+// TokenIntrospector validates tokens with the auth service
+type TokenIntrospector interface {
+	Introspect(ctx context.Context, token string) (map[string]interface{}, error)
+}
+
+// introspector, when set, validates tokens instead of decoding them locally
+var introspector TokenIntrospector
+
+// SetTokenIntrospector makes the auth middlewares validate tokens with the
+// auth service (AUTH_MODE=introspect)
+func SetTokenIntrospector(i TokenIntrospector) {
+	introspector = i
+}
+
+// errRevokedToken is returned for tokens on the revocation blacklist
+var errRevokedToken = errors.New("token has been revoked")
+
+// verifyToken returns the claims of a valid token, from the auth service when
+// introspection is enabled. Revoked tokens are refused; when the blacklist
+// can't be read (Redis down) tokens are let through rather than locking
+// everyone out.
+func verifyToken(ctx context.Context, token string) (map[string]interface{}, error) {
+	var claims map[string]interface{}
+	var err error
+	if introspector != nil {
+		claims, err = introspector.Introspect(ctx, token)
+	} else {
+		claims, err = decodeJWT(token)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := auth.IsRevoked(ctx, claims)
+	if err != nil {
+		log.Printf("Failed to check token revocation: %v", err)
+	}
+	if revoked {
+		return nil, errRevokedToken
+	}
+	return claims, nil
+}
+
 // AuthMiddleware extracts user info from JWT and adds to context
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -30,7 +78,18 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// Verify and decode token
-		payload, err := decodeJWT(token)
+		payload, err := verifyToken(c.Request.Context(), token)
+		if errors.Is(err, errRevokedToken) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			c.Abort()
+			return
+		}
+		if err != nil && introspector != nil && !errors.Is(err, auth.ErrInactiveToken) {
+			log.Printf("Failed to introspect token: %v", err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Authentication service unavailable"})
+			c.Abort()
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
@@ -38,37 +97,63 @@ func AuthMiddleware() gin.HandlerFunc {
 		}
 
 		// Set user info in context
-		c.Set("user_id", payload["id"])
-		c.Set("user_name", payload["name"])
-		c.Set("user_email", payload["email"])
-		
-		// Handle role - could be a string or array
-		if role, ok := payload["role"].(string); ok {
-			c.Set("user_role", role)
-		} else if roles, ok := payload["roles"].([]interface{}); ok && len(roles) > 0 {
-			// If roles is an array, check for staff or admin
-			roleStr := "user"
-			for _, r := range roles {
-				if rStr, ok := r.(string); ok {
-					if rStr == "admin" {
-						roleStr = "admin"
-						break
-					} else if rStr == "staff" {
-						roleStr = "staff"
-					}
-				}
+		setUserContext(c, payload)
+
+		c.Next()
+	}
+}
+
+// OptionalAuthMiddleware sets user info when a valid token is presented, and
+// otherwise lets the request through anonymously. Browsers can't set headers
+// on WebSocket and EventSource requests, so the token may also be passed as
+// the access_token query parameter.
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("access_token")
+		if authHeader := c.GetHeader("Authorization"); len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+			token = authHeader[7:]
+		}
+
+		if token != "" {
+			if payload, err := verifyToken(c.Request.Context(), token); err == nil {
+				setUserContext(c, payload)
 			}
-			c.Set("user_role", roleStr)
-		} else {
-			c.Set("user_role", "user")
 		}
-		
-		c.Set("user_payload", payload)
 
 		c.Next()
 	}
 }
 
+// setUserContext stores the user info from a token payload in the context
+func setUserContext(c *gin.Context, payload map[string]interface{}) {
+	c.Set("user_id", payload["id"])
+	c.Set("user_name", payload["name"])
+	c.Set("user_email", payload["email"])
+	
+	// Handle role - could be a string or array
+	if role, ok := payload["role"].(string); ok {
+		c.Set("user_role", role)
+	} else if roles, ok := payload["roles"].([]interface{}); ok && len(roles) > 0 {
+		// If roles is an array, check for staff or admin
+		roleStr := "user"
+		for _, r := range roles {
+			if rStr, ok := r.(string); ok {
+				if rStr == "admin" {
+					roleStr = "admin"
+					break
+				} else if rStr == "staff" {
+					roleStr = "staff"
+				}
+			}
+		}
+		c.Set("user_role", roleStr)
+	} else {
+		c.Set("user_role", "user")
+	}
+	
+	c.Set("user_payload", payload)
+}
+
 // decodeJWT decodes a JWT token without verification
 func decodeJWT(tokenString string) (map[string]interface{}, error) {
 	parts := make([]string, 0, 3)
@@ -159,20 +244,25 @@ func AdminOnlyMiddleware() gin.HandlerFunc {
 	}
 }
 
+// allowedOrigins are the browser origins allowed to call the API (development)
+var allowedOrigins = map[string]bool{
+	"http://localhost:3000": true,
+	"http://localhost:8080": true,
+	"http://127.0.0.1:3000": true,
+	"http://127.0.0.1:8080": true,
+}
+
+// AllowedOrigin reports whether browsers on origin may call the API
+func AllowedOrigin(origin string) bool {
+	return allowedOrigins[origin]
+}
+
 // CORSMiddleware adds CORS headers
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
 		
-		// Allow specific origins for development
-		allowedOrigins := map[string]bool{
-			"http://localhost:3000":    true,
-			"http://localhost:8080":    true,
-			"http://127.0.0.1:3000":    true,
-			"http://127.0.0.1:8080":    true,
-		}
-		
-		if origin != "" && allowedOrigins[origin] {
+		if origin != "" && AllowedOrigin(origin) {
 			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
 		} else if origin == "" {
 			// Allow requests with no origin (curl, Postman, etc.)
@@ -180,9 +270,9 @@ func CORSMiddleware() gin.HandlerFunc {
 		}
 		
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, X-Location-ID, Idempotency-Key")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Content-Length, Content-Type, X-Request-ID, Retry-After, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, "+IdempotentReplayedHeader+", ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"bytes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseBuffer holds a response back, so a middleware can look at it (or
+// replace it) before anything reaches the client. Once streaming, writes go
+// straight through.
+type responseBuffer struct {
+	gin.ResponseWriter
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+	streaming   bool
+}
+
+func (w *responseBuffer) WriteHeader(code int) {
+	if w.streaming {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.status = code
+	w.wroteHeader = true
+}
+
+func (w *responseBuffer) WriteHeaderNow() {
+	if w.streaming {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *responseBuffer) Write(data []byte) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *responseBuffer) WriteString(s string) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+func (w *responseBuffer) Status() int {
+	if w.streaming {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *responseBuffer) Size() int {
+	if w.streaming {
+		return w.ResponseWriter.Size()
+	}
+	return w.body.Len()
+}
+
+// Written reports whether the handler has answered, even though nothing
+// reached the client yet
+func (w *responseBuffer) Written() bool {
+	if w.streaming {
+		return w.ResponseWriter.Written()
+	}
+	return w.wroteHeader || w.body.Len() > 0
+}
+
+// Flush is left to the underlying writer once streaming; a held response
+// has nothing to send yet
+func (w *responseBuffer) Flush() {
+	if w.streaming {
+		w.ResponseWriter.Flush()
+	}
+}
+
+// flush sends the held response on to the underlying writer. A handler that
+// wrote nothing (it left an error to ErrorMiddleware) leaves it untouched.
+func (w *responseBuffer) flush() {
+	if w.streaming || !w.wroteHeader && w.body.Len() == 0 {
+		return
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	w.ResponseWriter.Write(w.body.Bytes())
+}
+
+// stream sends what is held and lets later writes through, for responses
+// too large to hold
+func (w *responseBuffer) stream() {
+	w.flush()
+	w.streaming = true
+}
@@ -0,0 +1,224 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// compressionEnabled and compressionMinSize are set from the configuration at
+// startup; responses smaller than compressionMinSize aren't worth the CPU
+var (
+	compressionEnabled = true
+	compressionMinSize = 1024
+)
+
+// SetCompression turns response compression on or off and sets the smallest
+// body it compresses
+func SetCompression(enabled bool, minSize int) {
+	compressionEnabled = enabled
+	if minSize >= 0 {
+		compressionMinSize = minSize
+	}
+}
+
+// compressibleTypes are the content types compressed. SSE streams and
+// exports in compressed formats (XLSX) are left alone.
+var compressibleTypes = map[string]bool{
+	"application/json":         true,
+	"application/problem+json": true,
+	"application/xml":          true,
+	"text/csv":                 true,
+	"text/html":                true,
+	"text/plain":               true,
+}
+
+var (
+	gzipWriters  = sync.Pool{New: func() interface{} { return gzip.NewWriter(io.Discard) }}
+	flateWriters = sync.Pool{New: func() interface{} {
+		w, _ := flate.NewWriter(io.Discard, flate.DefaultCompression)
+		return w
+	}}
+)
+
+// encoder is what gzip.Writer and flate.Writer have in common
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// compressWriter holds the start of a response back until it knows whether
+// the body is compressible and large enough, then writes it either
+// compressed or as is
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	buffer   bytes.Buffer
+	decided  bool
+	encoder  encoder
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buffer.Write(data)
+		if w.buffer.Len() < compressionMinSize {
+			return len(data), nil
+		}
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow sends the headers as they are, so the body goes out uncompressed
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decided = true
+		w.ResponseWriter.WriteHeaderNow()
+		w.ResponseWriter.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush sends what is buffered; a streamed response (SSE) is sent uncompressed
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.WriteHeaderNow()
+	}
+	if w.encoder != nil {
+		w.encoder.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide starts the body, compressing it when its type and size allow
+func (w *compressWriter) decide() error {
+	w.decided = true
+	header := w.Header()
+	status := w.Status()
+
+	compress := w.buffer.Len() >= compressionMinSize &&
+		header.Get("Content-Encoding") == "" &&
+		status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified &&
+		isCompressible(header.Get("Content-Type"))
+	if compress {
+		header.Set("Content-Encoding", w.encoding)
+		header.Add("Vary", "Accept-Encoding")
+		header.Del("Content-Length")
+		if w.encoding == "gzip" {
+			w.encoder = gzipWriters.Get().(*gzip.Writer)
+		} else {
+			w.encoder = flateWriters.Get().(*flate.Writer)
+		}
+		w.encoder.Reset(w.ResponseWriter)
+		_, err := w.encoder.Write(w.buffer.Bytes())
+		w.buffer.Reset()
+		return err
+	}
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buffer.Bytes())
+	w.buffer.Reset()
+	return err
+}
+
+// finish writes what is still buffered and ends the compressed stream
+func (w *compressWriter) finish() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.encoder == nil {
+		return
+	}
+
+	w.encoder.Close()
+	w.encoder.Reset(io.Discard)
+	if w.encoding == "gzip" {
+		gzipWriters.Put(w.encoder)
+	} else {
+		flateWriters.Put(w.encoder)
+	}
+	w.encoder = nil
+}
+
+// CompressionMiddleware compresses responses with gzip or deflate, as the
+// client's Accept-Encoding prefers, when they are of a compressible type and
+// at least the configured size. WebSocket upgrades and event streams pass
+// through untouched.
+func CompressionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !compressionEnabled || c.Request.Method == http.MethodHead ||
+			strings.EqualFold(c.GetHeader("Upgrade"), "websocket") ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		writer := c.Writer
+		compressor := &compressWriter{ResponseWriter: writer, encoding: encoding}
+		c.Writer = compressor
+		// A panicking handler leaves the response to the recovery middleware
+		defer func() { c.Writer = writer }()
+		c.Next()
+		compressor.finish()
+	}
+}
+
+func isCompressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && compressibleTypes[mediaType]
+}
+
+// negotiateEncoding picks gzip or deflate from Accept-Encoding, preferring
+// gzip on equal weights, or returns "" when the client accepts neither
+func negotiateEncoding(acceptEncoding string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "deflate" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ || (q == bestQ && name == "gzip") {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gin-quickstart/i18n"
+	"gin-quickstart/models"
+	"gin-quickstart/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// Error codes of failures that aren't domain errors
+const (
+	CodeTimeout  = "TIMEOUT"
+	CodeInternal = "INTERNAL_ERROR"
+)
+
+// ErrorMiddleware answers requests whose handler recorded an error with
+// c.Error instead of writing a response. A domain error (services.Error)
+// gets its kind's HTTP status and its code, a missing record 404 NOT_FOUND,
+// a rate-limited request 429 with Retry-After,
+// a binding error 400 INVALID_REQUEST (naming the allowed values of enum
+// fields), anything else 500 INTERNAL_ERROR.
+// The error's meta, when a string, titles the response ("Failed to ...").
+// Title and message are translated into the request's language where the
+// catalog has them; a message is looked up by its error code (binding
+// errors keep their field-by-field English detail).
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+		writeError(c)
+	}
+}
+
+// writeError answers the request with the last error its handler recorded
+func writeError(c *gin.Context) {
+	last := c.Errors.Last()
+	status, code := errorStatus(last)
+	title, _ := last.Meta.(string)
+	if title == "" {
+		title = http.StatusText(status)
+	}
+	if retryAfter, ok := services.RetryAfter(last.Err); ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	}
+	language := Language(c)
+	message := last.Error()
+	if last.IsType(gin.ErrorTypeBind) {
+		message = validationMessage(last.Err)
+	} else if translated, ok := i18n.Lookup(language, code); ok {
+		message = translated
+	}
+	c.JSON(status, models.ErrorResponse{
+		Error:   i18n.Translate(language, title),
+		Code:    code,
+		Message: message,
+	})
+}
+
+// errorStatus maps a recorded error to its HTTP status and error code
+func errorStatus(err *gin.Error) (int, string) {
+	if domainErr, ok := services.AsError(err.Err); ok {
+		switch domainErr.Kind {
+		case services.KindInvalid:
+			return http.StatusBadRequest, domainErr.Code
+		case services.KindNotFound:
+			return http.StatusNotFound, domainErr.Code
+		case services.KindConflict:
+			return http.StatusConflict, domainErr.Code
+		case services.KindUnavailable:
+			return http.StatusServiceUnavailable, domainErr.Code
+		case services.KindRateLimited:
+			return http.StatusTooManyRequests, domainErr.Code
+		}
+	}
+
+	switch {
+	case errors.Is(err.Err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound, services.ErrNotFound.Code
+	case err.IsType(gin.ErrorTypeBind):
+		return http.StatusBadRequest, services.ErrInvalidRequest.Code
+	case errors.Is(err.Err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, CodeTimeout
+	}
+	return http.StatusInternalServerError, CodeInternal
+}
+
+// validationMessage spells out the allowed values of enum fields that failed
+// validation; other binding errors keep their own message
+func validationMessage(err error) string {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err.Error()
+	}
+
+	messages := make([]string, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		if values, ok := models.EnumValidators[fieldErr.Tag()]; ok {
+			messages[i] = fmt.Sprintf("%s must be one of %s", fieldErr.Field(), strings.Join(values, ", "))
+		} else {
+			messages[i] = fieldErr.Error()
+		}
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETagMiddleware tags successful responses with a weak ETag of their body and
+// answers 304 Not Modified when If-None-Match already holds it, so displays
+// polling an unchanged queue skip the download. Clients must revalidate
+// every time, as the queue can change at any moment.
+func ETagMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := c.Writer
+		recorder := &responseBuffer{ResponseWriter: writer, status: http.StatusOK}
+		c.Writer = recorder
+		// A panicking handler leaves the response to the recovery middleware
+		defer func() { c.Writer = writer }()
+		c.Next()
+		c.Writer = writer
+
+		if recorder.status != http.StatusOK || len(c.Errors) > 0 {
+			recorder.flush()
+			return
+		}
+
+		sum := sha256.Sum256(recorder.body.Bytes())
+		etag := `W/"` + hex.EncodeToString(sum[:8]) + `"`
+		writer.Header().Set("ETag", etag)
+		writer.Header().Set("Cache-Control", "no-cache")
+		writer.Header().Add("Vary", LocationHeader)
+
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			writer.Header().Del("Content-Type")
+			writer.WriteHeader(http.StatusNotModified)
+			writer.WriteHeaderNow()
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+		writer.Write(recorder.body.Bytes())
+	}
+}
+
+// etagMatches compares If-None-Match weakly, as RFC 9110 asks for GET
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"gin-quickstart/database"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// IdempotencyKeyHeader lets clients retry a mutating request safely
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marks a response replayed from an earlier request
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const maxIdempotencyKeyLength = 255
+
+// idempotencyTTL is how long a stored response is replayed
+var idempotencyTTL = 24 * time.Hour
+
+// SetIdempotencyTTL sets how long responses to Idempotency-Key requests are kept
+func SetIdempotencyTTL(ttl time.Duration) {
+	if ttl > 0 {
+		idempotencyTTL = ttl
+	}
+}
+
+// idempotentResponse is the stored outcome of the first request with a key.
+// Fingerprint is empty while that request is still being handled.
+type idempotentResponse struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// idempotencyRecorder keeps a copy of the response while writing it
+type idempotencyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *idempotencyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware honors the Idempotency-Key header: the first request
+// with a key runs and its response is stored in Redis; retries with the same
+// key get that response back instead of running again. Keys are scoped to the
+// caller and route. Reusing a key for a different request is rejected, as is
+// a retry while the first request is still running. Server errors and
+// rate-limited requests aren't stored, so the request can be retried.
+// Without Redis, or without the header, requests run as usual.
+func IdempotencyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		rdb := database.GetRedis()
+		if key == "" || rdb == nil {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		redisKey := idempotencyKey(c.GetString("user_id"), c.Request.Method, c.FullPath(), key)
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		// Claim the key; a claimed key holds a pending record until the response is stored
+		pending, _ := json.Marshal(idempotentResponse{})
+		claimed, err := rdb.SetNX(ctx, redisKey, pending, idempotencyTTL).Result()
+		if err != nil {
+			log.Printf("Failed to claim idempotency key: %v", err)
+			c.Next()
+			return
+		}
+		if !claimed {
+			replayIdempotentResponse(c, rdb, redisKey, fingerprint)
+			return
+		}
+
+		recorder := &idempotencyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		// An error recorded with c.Error is answered here rather than by
+		// ErrorMiddleware, so the response stored is the one the client gets
+		if len(c.Errors) > 0 && !recorder.Written() {
+			writeError(c)
+		}
+
+		// Let server errors and rate-limited requests be retried
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			if err := rdb.Del(ctx, redisKey).Err(); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+
+		stored, _ := json.Marshal(idempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := rdb.Set(ctx, redisKey, stored, idempotencyTTL).Err(); err != nil {
+			log.Printf("Failed to store idempotent response: %v", err)
+		}
+	}
+}
+
+// replayIdempotentResponse answers a retry from the stored response
+func replayIdempotentResponse(c *gin.Context, rdb *redis.Client, redisKey, fingerprint string) {
+	raw, err := rdb.Get(c.Request.Context(), redisKey).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// Released after a server error in the meantime
+			c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key failed; retry it"})
+		} else {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to look up Idempotency-Key"})
+		}
+		c.Abort()
+		return
+	}
+
+	var stored idempotentResponse
+	if err := json.Unmarshal(raw, &stored); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to look up Idempotency-Key"})
+		c.Abort()
+		return
+	}
+
+	switch {
+	case stored.Fingerprint == "":
+		c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still in progress"})
+	case stored.Fingerprint != fingerprint:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was used for a different request"})
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(stored.Status, stored.ContentType, stored.Body)
+	}
+	c.Abort()
+}
+
+// idempotencyKey scopes a client's key to the caller and route
+func idempotencyKey(userID, method, route, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "idempotency:" + userID + ":" + method + ":" + route + ":" + hex.EncodeToString(sum[:])
+}
+
+// requestFingerprint identifies the request a key was first used for
+func requestFingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
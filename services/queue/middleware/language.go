@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"gin-quickstart/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LanguageMiddleware picks the response language from the Accept-Language
+// header (falling back to DEFAULT_LANGUAGE) and announces it in
+// Content-Language
+func LanguageMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		language := i18n.Negotiate(c.GetHeader("Accept-Language"))
+
+		c.Set("language", language)
+		c.Header("Content-Language", language)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
+
+// Language returns the response language of the request
+func Language(c *gin.Context) string {
+	if language := c.GetString("language"); language != "" {
+		return language
+	}
+	return i18n.DefaultLanguage()
+}
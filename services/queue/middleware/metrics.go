@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"gin-quickstart/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records request counts and latencies for Prometheus
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		// Use the route template to keep label cardinality bounded
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		metrics.HTTPRequestsTotal.WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
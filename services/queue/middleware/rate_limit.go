@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gin-quickstart/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// lookupRateLimit is how many public token lookups a client IP may make per
+// minute (0 disables)
+var lookupRateLimit = 60
+
+// SetLookupRateLimit sets how many public token lookups a client IP may make per minute
+func SetLookupRateLimit(perMinute int) {
+	lookupRateLimit = perMinute
+}
+
+// LookupRateLimitMiddleware limits public token lookups per client IP, so
+// the sequential tokens (A001, A002, ...) can't be walked to read other
+// customers' entries. Lookups are counted in Redis per calendar minute and
+// each counted lookup reports X-RateLimit-Limit, -Remaining and -Reset;
+// over the limit the request gets 429 with Retry-After. Without Redis, or
+// when Redis fails, lookups aren't limited.
+func LookupRateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rdb := database.GetRedis()
+		if lookupRateLimit <= 0 || rdb == nil {
+			c.Next()
+			return
+		}
+
+		now := time.Now().Unix()
+		key := fmt.Sprintf("ratelimit:lookup:%s:%d", c.ClientIP(), now/60)
+		ctx := c.Request.Context()
+		pipe := rdb.TxPipeline()
+		count := pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, time.Minute)
+		if _, err := pipe.Exec(ctx); err != nil {
+			log.Printf("Failed to count lookup for %s: %v", c.ClientIP(), err)
+			c.Next()
+			return
+		}
+
+		reset := strconv.FormatInt(60-now%60, 10)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(lookupRateLimit))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(max(int64(lookupRateLimit)-count.Val(), 0), 10))
+		c.Header("X-RateLimit-Reset", reset)
+
+		if count.Val() > int64(lookupRateLimit) {
+			c.Header("Retry-After", reset)
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many lookups; try again later"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
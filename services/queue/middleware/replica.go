@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"gin-quickstart/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReplicaReadsMiddleware sends the request's database reads to the read
+// replica, for public endpoints that tolerate replication lag
+func ReplicaReadsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(database.WithReplicaReads(c.Request.Context()))
+		c.Next()
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"gin-quickstart/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID between services
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware reuses the caller's request ID or assigns one, and
+// stores it on the request context so it reaches produced Kafka messages
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.NewString()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(tracing.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
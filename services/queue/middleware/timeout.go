@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestTimeout bounds ordinary requests, longRequestTimeout exports,
+// replays and other bulk work; zero disables the bound
+var (
+	requestTimeout     = 15 * time.Second
+	longRequestTimeout = 2 * time.Minute
+)
+
+// SetRequestTimeouts sets the ordinary and the long request timeouts
+func SetRequestTimeouts(standard, long time.Duration) {
+	requestTimeout = standard
+	longRequestTimeout = long
+}
+
+const (
+	requestTimerKey   = "request_timer"
+	responseBufferKey = "response_buffer"
+)
+
+// TimeoutMiddleware cancels the request's context once the request timeout
+// has passed, so database and Redis calls made with it give up. The response
+// is held back until the handler ends; one that hasn't ended in time gets
+// 504 Gateway Timeout instead, as whatever it wrote may be cut short. Not
+// for WebSocket or SSE.
+func TimeoutMiddleware() gin.HandlerFunc {
+	return timeoutMiddleware(func() time.Duration { return requestTimeout }, false)
+}
+
+// LongTimeoutMiddleware replaces the group's timeout with the long one, for
+// bulk routes
+func LongTimeoutMiddleware() gin.HandlerFunc {
+	return timeoutMiddleware(func() time.Duration { return longRequestTimeout }, false)
+}
+
+// StreamTimeoutMiddleware replaces the group's timeout with the long one and
+// sends the response as it is written, for exports. A stream the timeout
+// cuts short has its connection dropped, so the client doesn't take it for
+// complete.
+func StreamTimeoutMiddleware() gin.HandlerFunc {
+	return timeoutMiddleware(func() time.Duration { return longRequestTimeout }, true)
+}
+
+func timeoutMiddleware(timeout func() time.Duration, stream bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// A route's own timeout replaces its group's, keeping the context
+		// and the values other middleware stored on it
+		if timer, ok := c.Get(requestTimerKey); ok {
+			if limit := timeout(); limit > 0 {
+				timer.(*time.Timer).Reset(limit)
+			} else {
+				timer.(*time.Timer).Stop()
+			}
+			if buffer, ok := c.Get(responseBufferKey); ok && stream {
+				buffer.(*responseBuffer).stream()
+			}
+			c.Next()
+			return
+		}
+
+		limit := timeout()
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithCancelCause(c.Request.Context())
+		defer cancel(nil)
+		timer := time.AfterFunc(limit, func() { cancel(context.DeadlineExceeded) })
+		defer timer.Stop()
+		c.Set(requestTimerKey, timer)
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := c.Writer
+		buffer := &responseBuffer{ResponseWriter: writer, status: http.StatusOK, streaming: stream}
+		c.Writer = buffer
+		c.Set(responseBufferKey, buffer)
+		// A panicking handler leaves the response to the recovery middleware
+		defer func() { c.Writer = writer }()
+		c.Next()
+		c.Writer = writer
+
+		if !errors.Is(context.Cause(ctx), context.DeadlineExceeded) {
+			buffer.flush()
+			return
+		}
+		if buffer.streaming && writer.Written() {
+			abortResponse(c.Request, writer)
+			return
+		}
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Request timed out after " + limit.String()})
+		c.Abort()
+	}
+}
+
+// abortResponse drops the connection of a response that has already started.
+// HTTP/2 streams can't be dropped from here and are left to end as they are.
+func abortResponse(r *http.Request, w gin.ResponseWriter) {
+	if r.ProtoMajor != 1 {
+		log.Printf("Request %s %s timed out mid-response", r.Method, r.URL.Path)
+		return
+	}
+	conn, _, err := w.Hijack()
+	if err != nil {
+		log.Printf("Failed to drop timed out response: %v", err)
+		return
+	}
+	conn.Close()
+}
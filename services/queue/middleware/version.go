@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionHeader reports the API version that served a response
+const APIVersionHeader = "API-Version"
+
+// APIVersionMiddleware records the API version a route group serves.
+//
+// Within a version, response DTOs only gain fields. A change that renames,
+// removes or reshapes a field ships under the next /api/vN group instead,
+// with the handler rendering the shape of APIVersion(c), so clients of the
+// earlier version keep the responses they were written against.
+func APIVersionMiddleware(version int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("api_version", version)
+		c.Header(APIVersionHeader, strconv.Itoa(version))
+		c.Next()
+	}
+}
+
+// APIVersion returns the API version of the request's route, 1 outside a
+// versioned group
+func APIVersion(c *gin.Context) int {
+	if version, ok := c.Get("api_version"); ok {
+		return version.(int)
+	}
+	return 1
+}
+
+// DeprecatedPathMiddleware marks responses under prefix as deprecated and
+// links the same route under successor
+func DeprecatedPathMiddleware(prefix, successor string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successor, strings.TrimPrefix(c.Request.URL.Path, prefix)))
+		c.Next()
+	}
+}
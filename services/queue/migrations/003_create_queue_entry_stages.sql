@@ -0,0 +1,27 @@
+-- ============================================
+-- Queue Entry Stages Table (Staggered courses)
+-- ============================================
+-- Sub-entries that share the parent entry's token and become ready independently
+CREATE TABLE IF NOT EXISTS queue_entry_stages (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL,
+    token_number VARCHAR(20) NOT NULL,
+    sequence INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    item_count INT DEFAULT 0,
+    status ENUM('WAITING', 'IN_PROGRESS', 'READY', 'COMPLETED', 'CANCELLED') DEFAULT 'WAITING',
+    ready_after_minutes INT DEFAULT 0, -- offset from the previous stage
+    estimated_ready_time TIMESTAMP NULL,
+    actual_start_time TIMESTAMP NULL,
+    actual_ready_time TIMESTAMP NULL,
+    actual_completion_time TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_entry_sequence (queue_entry_id, sequence),
+    INDEX idx_queue_entry_id (queue_entry_id),
+    INDEX idx_token_number (token_number),
+    INDEX idx_status (status),
+
+    FOREIGN KEY (queue_entry_id) REFERENCES queue_entries(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,8 @@
+-- ============================================
+-- Location dimension for queue entries
+-- ============================================
+-- Lets multi-location deployments compare KPIs per store
+ALTER TABLE queue_entries
+    ADD COLUMN location_id VARCHAR(36) NOT NULL DEFAULT 'default' AFTER order_id,
+    ADD INDEX idx_location_id (location_id),
+    ADD INDEX idx_location_created_at (location_id, created_at);
@@ -0,0 +1,17 @@
+-- ============================================
+-- Queue Entry Tombstones Table
+-- ============================================
+-- Lightweight markers for deleted/anonymized entries, purged after a grace period
+CREATE TABLE IF NOT EXISTS queue_entry_tombstones (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL,
+    order_id VARCHAR(36) NOT NULL,
+    token_number VARCHAR(20) NOT NULL,
+    action ENUM('DELETED', 'ANONYMIZED') NOT NULL,
+    reason TEXT,
+    performed_by VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_queue_entry_id (queue_entry_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
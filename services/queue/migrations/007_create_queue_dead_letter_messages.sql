@@ -0,0 +1,23 @@
+-- ============================================
+-- Dead Letter Messages Table
+-- ============================================
+-- Mirrors messages written to queue.events.dlq so they can be listed and re-driven
+CREATE TABLE IF NOT EXISTS queue_dead_letter_messages (
+    id VARCHAR(36) PRIMARY KEY,
+    original_topic VARCHAR(255) NOT NULL,
+    partition_id INT NOT NULL,
+    offset_id BIGINT NOT NULL,
+    message_key VARCHAR(255),
+    payload MEDIUMTEXT NOT NULL,
+    error TEXT NOT NULL,
+    attempts INT DEFAULT 1,
+    status ENUM('PENDING', 'REDRIVEN', 'DISCARDED') DEFAULT 'PENDING',
+    redrive_count INT DEFAULT 0,
+    last_redriven_at TIMESTAMP NULL,
+    last_redriven_by VARCHAR(36),
+    failed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_original_topic (original_topic),
+    INDEX idx_status (status),
+    INDEX idx_failed_at (failed_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,17 @@
+-- ============================================
+-- Queue Entry Items Table
+-- ============================================
+-- Line items per entry, used to spot identical items across adjacent orders
+CREATE TABLE IF NOT EXISTS queue_entry_items (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL,
+    menu_item_id VARCHAR(36) NOT NULL,
+    item_name VARCHAR(255),
+    quantity INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_queue_entry_id (queue_entry_id),
+    INDEX idx_menu_item_id (menu_item_id),
+
+    FOREIGN KEY (queue_entry_id) REFERENCES queue_entries(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
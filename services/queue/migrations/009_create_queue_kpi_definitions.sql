@@ -0,0 +1,16 @@
+-- ============================================
+-- Custom KPI Definitions Table
+-- ============================================
+-- Operator-defined arithmetic over daily queue counters, e.g. express_completed / completed
+CREATE TABLE IF NOT EXISTS queue_kpi_definitions (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    expression VARCHAR(500) NOT NULL,
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_is_active (is_active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,13 @@
+-- ============================================
+-- Processed Events Table (Idempotent consumer)
+-- ============================================
+-- One row per consumed message; the primary key makes re-deliveries a no-op
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_key VARCHAR(255) PRIMARY KEY, -- event:<event_id> or <topic>/<partition>/<offset>
+    topic VARCHAR(255) NOT NULL,
+    partition_id INT NOT NULL,
+    offset_id BIGINT NOT NULL,
+    processed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_processed_at (processed_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,16 @@
+-- ============================================
+-- Queue Depth Limits Table
+-- ============================================
+-- Caps on waiting entries per lane and priority. New entries over a cap fall
+-- back to the next lower priority (or from the express to the regular lane).
+CREATE TABLE IF NOT EXISTS queue_depth_limits (
+    id VARCHAR(36) PRIMARY KEY,
+    lane ENUM('ANY','REGULAR','EXPRESS') NOT NULL DEFAULT 'ANY',
+    priority ENUM('ANY','NORMAL','HIGH','URGENT','VIP') NOT NULL DEFAULT 'ANY',
+    max_waiting INT NOT NULL,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_lane_priority (lane, priority)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,22 @@
+-- ============================================
+-- Payment gating for queue entries
+-- ============================================
+-- Entries created from unpaid orders wait in PENDING_PAYMENT, outside the
+-- active positions (position 0), until payment.completed activates them.
+ALTER TABLE queue_entries
+    MODIFY status ENUM(
+        'PENDING_PAYMENT', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    ) DEFAULT 'WAITING',
+    DROP CHECK queue_entries_chk_1,
+    ADD CONSTRAINT chk_queue_entries_position CHECK (position >= 0);
+
+ALTER TABLE staff_queue_actions_log
+    MODIFY old_status ENUM(
+        'PENDING_PAYMENT', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    ),
+    MODIFY new_status ENUM(
+        'PENDING_PAYMENT', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    );
@@ -0,0 +1,12 @@
+-- ============================================
+-- Delivery tracking for direct notification channels
+-- ============================================
+-- SMS (and later email/push) notifications are sent by the queue service
+-- itself; provider status callbacks update the delivery status by message ID.
+ALTER TABLE queue_notifications_sent
+    MODIFY channel ENUM('PUSH', 'IN_APP', 'SMS', 'EMAIL') NOT NULL,
+    ADD COLUMN provider_message_id VARCHAR(64) NULL AFTER sent_at,
+    ADD COLUMN delivery_status VARCHAR(32) NULL AFTER provider_message_id,
+    ADD COLUMN delivery_error VARCHAR(255) NULL AFTER delivery_status,
+    ADD COLUMN status_updated_at TIMESTAMP NULL AFTER delivery_error,
+    ADD INDEX idx_provider_message_id (provider_message_id);
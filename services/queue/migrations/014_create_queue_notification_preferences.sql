@@ -0,0 +1,12 @@
+-- ============================================
+-- Notification Preferences Table
+-- ============================================
+-- Per-customer choice of direct notification channels. Customers without a row
+-- get SMS (when they have a phone number) and no email.
+CREATE TABLE IF NOT EXISTS queue_notification_preferences (
+    user_id VARCHAR(36) PRIMARY KEY,
+    email VARCHAR(255),
+    sms_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    email_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
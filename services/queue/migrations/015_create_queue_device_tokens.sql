@@ -0,0 +1,15 @@
+-- ============================================
+-- Device Tokens Table
+-- ============================================
+-- FCM registration tokens of the mobile app installations of each customer.
+-- Tokens FCM reports as no longer valid are deleted when a push fails.
+CREATE TABLE IF NOT EXISTS queue_device_tokens (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    token VARCHAR(512) NOT NULL,
+    platform ENUM('android', 'ios', 'web') NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_token (token(255)),
+    INDEX idx_user_id (user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
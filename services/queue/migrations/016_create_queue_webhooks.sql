@@ -0,0 +1,40 @@
+-- ============================================
+-- Webhook Subscriptions Table
+-- ============================================
+-- Third-party endpoints (POS, displays) that receive queue events as signed
+-- JSON POSTs
+CREATE TABLE IF NOT EXISTS queue_webhook_subscriptions (
+    id VARCHAR(36) PRIMARY KEY,
+    url VARCHAR(512) NOT NULL,
+    event_types JSON NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    description VARCHAR(255),
+    is_active BOOLEAN DEFAULT TRUE,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_is_active (is_active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- ============================================
+-- Webhook Deliveries Table
+-- ============================================
+-- One row per event and subscription; doubles as the retry queue and the
+-- delivery log
+CREATE TABLE IF NOT EXISTS queue_webhook_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    subscription_id VARCHAR(36) NOT NULL,
+    event_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload MEDIUMTEXT NOT NULL,
+    status ENUM('PENDING', 'SUCCEEDED', 'FAILED') DEFAULT 'PENDING',
+    attempts INT DEFAULT 0,
+    response_status INT,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NULL,
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_subscription_created (subscription_id, created_at),
+    INDEX idx_status_next_attempt (status, next_attempt_at),
+    FOREIGN KEY (subscription_id) REFERENCES queue_webhook_subscriptions(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,16 @@
+-- ============================================
+-- Counter references
+-- ============================================
+-- Entries reference their counter by ID; assigned_counter keeps the counter
+-- name for display and history. Counters track the entry they are serving.
+ALTER TABLE queue_entries
+    ADD COLUMN counter_id VARCHAR(36) NULL AFTER assigned_counter,
+    ADD INDEX idx_counter_id (counter_id);
+
+UPDATE queue_entries e
+    JOIN queue_counters c ON c.name = e.assigned_counter
+    SET e.counter_id = c.id;
+
+ALTER TABLE queue_counters
+    ADD COLUMN serving_entry_id VARCHAR(36) NULL AFTER is_open,
+    ADD COLUMN serving_since TIMESTAMP NULL AFTER serving_entry_id;
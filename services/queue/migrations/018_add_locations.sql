@@ -0,0 +1,47 @@
+-- ============================================
+-- Locations (tenants)
+-- ============================================
+-- One deployment runs the queues of several outlets. Each location has its own
+-- configuration, statistics and token sequence; the token prefix keeps token
+-- numbers unique across locations (A001 at one outlet, B001 at another).
+CREATE TABLE IF NOT EXISTS queue_locations (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    token_prefix VARCHAR(3) UNIQUE NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_is_active (is_active)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Existing entries already default to this location (see 004)
+INSERT INTO queue_locations (id, name, token_prefix)
+VALUES ('default', 'Default', 'A');
+
+-- The existing configuration becomes the default location's; other locations
+-- fall back to it until they are given their own
+ALTER TABLE queue_configuration
+    ADD COLUMN location_id VARCHAR(36) NOT NULL DEFAULT 'default' AFTER id;
+
+UPDATE queue_configuration SET location_id = 'default';
+
+ALTER TABLE queue_configuration
+    ADD UNIQUE INDEX idx_location_id (location_id);
+
+-- Statistics and token counters are kept per location and day
+ALTER TABLE queue_statistics
+    ADD COLUMN location_id VARCHAR(36) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX date,
+    ADD UNIQUE INDEX idx_location_date (location_id, date);
+
+ALTER TABLE queue_token_counter
+    ADD COLUMN location_id VARCHAR(36) NOT NULL DEFAULT 'default' AFTER id,
+    MODIFY COLUMN prefix VARCHAR(3) DEFAULT 'A',
+    DROP INDEX date,
+    ADD UNIQUE INDEX idx_location_date (location_id, date);
+
+-- Counter names only need to be unique within a location
+ALTER TABLE queue_counters
+    ADD COLUMN location_id VARCHAR(36) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX name,
+    ADD UNIQUE INDEX idx_location_name (location_id, name);
@@ -0,0 +1,28 @@
+-- ============================================
+-- Staff Shifts Table
+-- ============================================
+-- Staff clock in (optionally at a counter) and out. Open shifts tell which
+-- staff are on duty; action logs reference the shift they were taken in.
+CREATE TABLE IF NOT EXISTS queue_staff_shifts (
+    id VARCHAR(36) PRIMARY KEY,
+    staff_id VARCHAR(36) NOT NULL,
+    staff_name VARCHAR(100),
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    counter_id VARCHAR(36) NULL,
+    clock_in_at TIMESTAMP NOT NULL,
+    clock_out_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    -- Only set while the shift is open, so a staff member has one open shift at most
+    open_staff_id VARCHAR(36) AS (IF(clock_out_at IS NULL, staff_id, NULL)) STORED,
+    UNIQUE INDEX idx_open_staff_id (open_staff_id),
+
+    INDEX idx_staff_clock_in (staff_id, clock_in_at),
+    INDEX idx_location_open (location_id, clock_out_at),
+    INDEX idx_counter_id (counter_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE staff_queue_actions_log
+    ADD COLUMN shift_id VARCHAR(36) NULL AFTER staff_name,
+    ADD INDEX idx_shift_id (shift_id);
@@ -0,0 +1,9 @@
+-- ============================================
+-- Deferred Starts
+-- ============================================
+-- Entries the kitchen started while max_concurrent_orders were already in
+-- progress stay WAITING with start_deferred_at set, and start in that order as
+-- in-progress entries finish.
+ALTER TABLE queue_entries
+    ADD COLUMN start_deferred_at TIMESTAMP NULL AFTER actual_completion_time,
+    ADD INDEX idx_location_start_deferred (location_id, start_deferred_at);
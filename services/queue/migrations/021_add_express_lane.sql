@@ -0,0 +1,25 @@
+-- ============================================
+-- Express Lane
+-- ============================================
+-- Express entries get their own position sequence. Staff serve the lanes in
+-- turn: regular_serve_ratio regular entries, then express_serve_ratio express
+-- (values below 1 count as 1).
+ALTER TABLE queue_configuration
+    ADD COLUMN regular_serve_ratio INT DEFAULT 2 AFTER express_queue_max_items,
+    ADD COLUMN express_serve_ratio INT DEFAULT 1 AFTER regular_serve_ratio;
+
+ALTER TABLE queue_entries
+    ADD INDEX idx_location_lane_position (location_id, is_express_queue, position);
+
+-- Renumber active entries within their lane; the Redis position index follows
+-- on the next recalculation
+UPDATE queue_entries e
+    JOIN (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY location_id, is_express_queue
+            ORDER BY position ASC, created_at ASC
+        ) AS lane_position
+        FROM queue_entries
+        WHERE status IN ('WAITING', 'IN_PROGRESS')
+    ) ranked ON ranked.id = e.id
+    SET e.position = ranked.lane_position;
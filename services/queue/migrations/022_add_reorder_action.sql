@@ -0,0 +1,14 @@
+-- ============================================
+-- Staff action log actions
+-- ============================================
+-- Manual reorders are logged per moved entry. The ENUM also gains the actions
+-- already written for every status change (MARK_<status>) and for starts
+-- deferred while the kitchen is at capacity, which it rejected until now.
+ALTER TABLE staff_queue_actions_log
+    MODIFY action ENUM(
+        'START_PREPARATION', 'MARK_READY', 'MARK_COMPLETED',
+        'CANCEL', 'REASSIGN', 'ADJUST_PRIORITY', 'ADD_NOTE',
+        'MARK_PENDING_PAYMENT', 'MARK_WAITING', 'MARK_IN_PROGRESS',
+        'MARK_CANCELLED', 'MARK_NO_SHOW', 'MARK_EXPIRED',
+        'DEFER_START', 'REORDER'
+    ) NOT NULL;
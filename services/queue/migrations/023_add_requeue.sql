@@ -0,0 +1,17 @@
+-- ============================================
+-- Requeue
+-- ============================================
+-- Staff can reinstate NO_SHOW or EXPIRED entries with their token. They go
+-- to the back of their lane (BACK) or to the front of their priority
+-- (PRIORITY); an empty value counts as BACK.
+ALTER TABLE queue_configuration
+    ADD COLUMN requeue_placement VARCHAR(16) DEFAULT 'BACK' AFTER express_serve_ratio;
+
+ALTER TABLE staff_queue_actions_log
+    MODIFY action ENUM(
+        'START_PREPARATION', 'MARK_READY', 'MARK_COMPLETED',
+        'CANCEL', 'REASSIGN', 'ADJUST_PRIORITY', 'ADD_NOTE',
+        'MARK_PENDING_PAYMENT', 'MARK_WAITING', 'MARK_IN_PROGRESS',
+        'MARK_CANCELLED', 'MARK_NO_SHOW', 'MARK_EXPIRED',
+        'DEFER_START', 'REORDER', 'REQUEUE'
+    ) NOT NULL;
@@ -0,0 +1,25 @@
+-- ============================================
+-- Skipped entries
+-- ============================================
+-- Staff can bump a waiting entry whose customer isn't responding down a few
+-- places. It moves back up the same number of places at skip_restore_at.
+ALTER TABLE queue_entries
+    ADD COLUMN skipped_at TIMESTAMP NULL AFTER start_deferred_at,
+    ADD COLUMN skipped_positions INT NOT NULL DEFAULT 0 AFTER skipped_at,
+    ADD COLUMN skip_restore_at TIMESTAMP NULL AFTER skipped_positions,
+    ADD INDEX idx_skip_restore_at (skip_restore_at);
+
+-- Defaults for skips that don't say how far or for how long (values below 1
+-- use the built-in defaults)
+ALTER TABLE queue_configuration
+    ADD COLUMN skip_positions INT DEFAULT 3 AFTER requeue_placement,
+    ADD COLUMN skip_timeout_minutes INT DEFAULT 5 AFTER skip_positions;
+
+ALTER TABLE staff_queue_actions_log
+    MODIFY action ENUM(
+        'START_PREPARATION', 'MARK_READY', 'MARK_COMPLETED',
+        'CANCEL', 'REASSIGN', 'ADJUST_PRIORITY', 'ADD_NOTE',
+        'MARK_PENDING_PAYMENT', 'MARK_WAITING', 'MARK_IN_PROGRESS',
+        'MARK_CANCELLED', 'MARK_NO_SHOW', 'MARK_EXPIRED',
+        'DEFER_START', 'REORDER', 'REQUEUE', 'SKIP', 'RESTORE_SKIP'
+    ) NOT NULL;
@@ -0,0 +1,18 @@
+-- ============================================
+-- Recalled entries
+-- ============================================
+-- Staff can call a READY token again when its customer hasn't collected it.
+-- recall_count tells them how many times the customer has been called.
+ALTER TABLE queue_entries
+    ADD COLUMN recall_count INT NOT NULL DEFAULT 0 AFTER skip_restore_at,
+    ADD COLUMN last_recalled_at TIMESTAMP NULL AFTER recall_count;
+
+ALTER TABLE staff_queue_actions_log
+    MODIFY action ENUM(
+        'START_PREPARATION', 'MARK_READY', 'MARK_COMPLETED',
+        'CANCEL', 'REASSIGN', 'ADJUST_PRIORITY', 'ADD_NOTE',
+        'MARK_PENDING_PAYMENT', 'MARK_WAITING', 'MARK_IN_PROGRESS',
+        'MARK_CANCELLED', 'MARK_NO_SHOW', 'MARK_EXPIRED',
+        'DEFER_START', 'REORDER', 'REQUEUE', 'SKIP', 'RESTORE_SKIP',
+        'RECALL'
+    ) NOT NULL;
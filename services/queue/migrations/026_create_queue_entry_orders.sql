@@ -0,0 +1,35 @@
+-- ============================================
+-- Queue Entry Orders Table (Party tokens)
+-- ============================================
+-- Orders sharing one pickup token, e.g. a family ordering separately. The
+-- entry becomes ready once all its orders are. The entry's own order gets a
+-- row too once other orders are linked to it.
+CREATE TABLE IF NOT EXISTS queue_entry_orders (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL,
+    order_id VARCHAR(36) NOT NULL,
+    token_number VARCHAR(20) NOT NULL,
+    status ENUM('WAITING', 'IN_PROGRESS', 'READY', 'COMPLETED', 'CANCELLED') DEFAULT 'WAITING',
+    actual_start_time TIMESTAMP NULL,
+    actual_ready_time TIMESTAMP NULL,
+    actual_completion_time TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uk_order_id (order_id),
+    INDEX idx_queue_entry_id (queue_entry_id),
+    INDEX idx_token_number (token_number),
+    INDEX idx_status (status),
+
+    FOREIGN KEY (queue_entry_id) REFERENCES queue_entries(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+ALTER TABLE staff_queue_actions_log
+    MODIFY action ENUM(
+        'START_PREPARATION', 'MARK_READY', 'MARK_COMPLETED',
+        'CANCEL', 'REASSIGN', 'ADJUST_PRIORITY', 'ADD_NOTE',
+        'MARK_PENDING_PAYMENT', 'MARK_WAITING', 'MARK_IN_PROGRESS',
+        'MARK_CANCELLED', 'MARK_NO_SHOW', 'MARK_EXPIRED',
+        'DEFER_START', 'REORDER', 'REQUEUE', 'SKIP', 'RESTORE_SKIP',
+        'RECALL', 'LINK_ORDERS'
+    ) NOT NULL;
@@ -0,0 +1,10 @@
+-- ============================================
+-- Walk-in entries
+-- ============================================
+-- Staff can queue walk-in customers who have no upstream order. They get the
+-- WALK_IN token type and a generated order_id, since the column stays unique.
+ALTER TABLE queue_entries
+    MODIFY token_type ENUM('REGULAR', 'EXPRESS', 'BULK', 'SPECIAL', 'STAFF', 'WALK_IN') DEFAULT 'REGULAR';
+
+ALTER TABLE queue_statistics
+    ADD COLUMN walk_ins_today INT DEFAULT 0 AFTER expired_today;
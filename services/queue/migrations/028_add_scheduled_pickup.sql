@@ -0,0 +1,36 @@
+-- ============================================
+-- Scheduled pickup times
+-- ============================================
+-- Entries with a requested pickup time stay SCHEDULED, outside the active
+-- positions (position 0), until scheduled_activate_at: the pickup time less
+-- the entry's estimated preparation. They then join the active queue.
+ALTER TABLE queue_entries
+    MODIFY status ENUM(
+        'PENDING_PAYMENT', 'SCHEDULED', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    ) DEFAULT 'WAITING',
+    ADD COLUMN requested_pickup_time TIMESTAMP NULL AFTER estimated_ready_time,
+    ADD COLUMN scheduled_activate_at TIMESTAMP NULL AFTER requested_pickup_time,
+    ADD INDEX idx_status_scheduled_activate_at (status, scheduled_activate_at);
+
+ALTER TABLE staff_queue_actions_log
+    MODIFY old_status ENUM(
+        'PENDING_PAYMENT', 'SCHEDULED', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    ),
+    MODIFY new_status ENUM(
+        'PENDING_PAYMENT', 'SCHEDULED', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    );
+
+-- Activations are recorded in the position history, which never took the
+-- PENDING_PAYMENT status either
+ALTER TABLE queue_position_history
+    MODIFY old_status ENUM(
+        'PENDING_PAYMENT', 'SCHEDULED', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    ) NOT NULL,
+    MODIFY new_status ENUM(
+        'PENDING_PAYMENT', 'SCHEDULED', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    ) NOT NULL;
@@ -0,0 +1,16 @@
+-- ============================================
+-- Entry item counts
+-- ============================================
+-- The wait time predictor uses an entry's item count, both for live entries
+-- and when it is trained on completed ones. Existing entries take the count
+-- of their recorded line items.
+ALTER TABLE queue_entries
+    ADD COLUMN item_count INT NOT NULL DEFAULT 0 AFTER position;
+
+UPDATE queue_entries e
+    JOIN (
+        SELECT queue_entry_id, SUM(quantity) AS item_count
+        FROM queue_entry_items
+        GROUP BY queue_entry_id
+    ) i ON i.queue_entry_id = e.id
+SET e.item_count = i.item_count;
@@ -0,0 +1,13 @@
+-- ============================================
+-- Adaptive preparation time
+-- ============================================
+-- With adaptive_prep_time_enabled, each entry marked ready moves
+-- avg_preparation_time_per_item towards its actual preparation time per item
+-- by an exponentially weighted moving average. learned_prep_time_per_item
+-- keeps the unrounded average; prep_time_smoothing is its weight for each
+-- new entry (0-1, NULL for the default).
+ALTER TABLE queue_configuration
+    ADD COLUMN adaptive_prep_time_enabled BOOLEAN DEFAULT FALSE AFTER avg_preparation_time_per_item,
+    ADD COLUMN prep_time_smoothing DECIMAL(4, 3) NULL AFTER adaptive_prep_time_enabled,
+    ADD COLUMN learned_prep_time_per_item DOUBLE NULL AFTER prep_time_smoothing,
+    ADD COLUMN prep_time_learned_at TIMESTAMP NULL AFTER learned_prep_time_per_item;
@@ -0,0 +1,24 @@
+-- ============================================
+-- Wait and preparation time percentiles
+-- ============================================
+-- Daily and hourly statistics keep the p50/p90/p99 of wait (joined to
+-- started) and preparation (started to ready) times, in minutes, next to
+-- the averages. Hourly statistics become per location like the daily ones.
+ALTER TABLE queue_statistics
+    ADD COLUMN wait_time_p50 INT DEFAULT 0 AFTER avg_preparation_time,
+    ADD COLUMN wait_time_p90 INT DEFAULT 0 AFTER wait_time_p50,
+    ADD COLUMN wait_time_p99 INT DEFAULT 0 AFTER wait_time_p90,
+    ADD COLUMN prep_time_p50 INT DEFAULT 0 AFTER wait_time_p99,
+    ADD COLUMN prep_time_p90 INT DEFAULT 0 AFTER prep_time_p50,
+    ADD COLUMN prep_time_p99 INT DEFAULT 0 AFTER prep_time_p90;
+
+ALTER TABLE queue_hourly_statistics
+    ADD COLUMN location_id VARCHAR(36) NOT NULL DEFAULT 'default' AFTER id,
+    DROP INDEX unique_date_hour,
+    ADD UNIQUE INDEX idx_location_date_hour (location_id, date, hour),
+    ADD COLUMN wait_time_p50 INT DEFAULT 0 AFTER avg_preparation_time,
+    ADD COLUMN wait_time_p90 INT DEFAULT 0 AFTER wait_time_p50,
+    ADD COLUMN wait_time_p99 INT DEFAULT 0 AFTER wait_time_p90,
+    ADD COLUMN prep_time_p50 INT DEFAULT 0 AFTER wait_time_p99,
+    ADD COLUMN prep_time_p90 INT DEFAULT 0 AFTER prep_time_p50,
+    ADD COLUMN prep_time_p99 INT DEFAULT 0 AFTER prep_time_p90;
@@ -0,0 +1,18 @@
+-- ============================================
+-- Wait SLA breaches
+-- ============================================
+-- An active entry that has waited longer than the SLA of its priority is
+-- flagged at sla_breached_at and a queue.sla.breached event is published,
+-- once per entry.
+ALTER TABLE queue_entries
+    ADD COLUMN sla_breached_at TIMESTAMP NULL AFTER last_recalled_at,
+    ADD INDEX idx_sla_breached_at (sla_breached_at);
+
+-- SLA in minutes per priority. NULL uses max_wait_time_alert; values below 1
+-- turn the SLA off for the priority.
+ALTER TABLE queue_configuration
+    ADD COLUMN sla_minutes_vip INT NULL AFTER max_wait_time_alert,
+    ADD COLUMN sla_minutes_urgent INT NULL AFTER sla_minutes_vip,
+    ADD COLUMN sla_minutes_high INT NULL AFTER sla_minutes_urgent,
+    ADD COLUMN sla_minutes_normal INT NULL AFTER sla_minutes_high,
+    ADD COLUMN sla_minutes_low INT NULL AFTER sla_minutes_normal;
@@ -0,0 +1,25 @@
+-- ============================================
+-- Queue Load Samples Table
+-- ============================================
+-- Every few minutes each active location's queue length (waiting entries)
+-- and kitchen load (in-progress entries as a percentage of
+-- max_concurrent_orders) are sampled for the load curve. Samples are
+-- bucketed to the sampling interval, so instances sampling at once share one.
+CREATE TABLE IF NOT EXISTS queue_load_samples (
+    id VARCHAR(36) PRIMARY KEY,
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    sampled_at TIMESTAMP NOT NULL,
+    waiting_count INT NOT NULL DEFAULT 0,
+    in_progress_count INT NOT NULL DEFAULT 0,
+    max_concurrent_orders INT NOT NULL DEFAULT 0,
+    kitchen_load DECIMAL(5, 2) NOT NULL DEFAULT 0.00, -- percentage
+
+    UNIQUE INDEX idx_location_sampled_at (location_id, sampled_at),
+    INDEX idx_sampled_at (sampled_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- The day's longest queue, next to its peak kitchen load. Hourly statistics
+-- keep the hour's longest queue in peak_position.
+ALTER TABLE queue_statistics
+    ADD COLUMN peak_queue_length INT DEFAULT 0 AFTER peak_load_time,
+    ADD COLUMN peak_queue_length_time VARCHAR(5) AFTER peak_queue_length; -- HH:MM
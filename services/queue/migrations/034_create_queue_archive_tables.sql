@@ -0,0 +1,21 @@
+-- ============================================
+-- Queue Archive Tables
+-- ============================================
+-- Finished entries (COMPLETED, CANCELLED, NO_SHOW) older than
+-- ARCHIVE_AFTER_DAYS move here with their position history and staff action
+-- logs, keeping queue_entries small. Their stages, linked orders, items and
+-- sent notifications are dropped with them.
+--
+-- The archive tables mirror their hot tables column for column, so rows are
+-- copied with INSERT ... SELECT *. Later migrations changing the columns of
+-- a hot table must change its archive table the same way.
+CREATE TABLE IF NOT EXISTS queue_entries_archive LIKE queue_entries;
+
+-- Order and token numbers may come round again once archived
+ALTER TABLE queue_entries_archive
+    DROP INDEX order_id,
+    DROP INDEX token_number;
+
+CREATE TABLE IF NOT EXISTS queue_position_history_archive LIKE queue_position_history;
+
+CREATE TABLE IF NOT EXISTS staff_queue_actions_log_archive LIKE staff_queue_actions_log;
@@ -0,0 +1,22 @@
+-- ============================================
+-- Soft deletes
+-- ============================================
+-- Deleting an entry, announcement or location configuration sets deleted_at
+-- instead of removing the row, so admins can list and restore it. Deleted
+-- rows keep their unique order_id, token_number and location_id.
+ALTER TABLE queue_entries
+    ADD COLUMN deleted_at TIMESTAMP NULL AFTER updated_at,
+    ADD INDEX idx_deleted_at (deleted_at);
+
+-- Deleted entries are archived with the finished ones
+ALTER TABLE queue_entries_archive
+    ADD COLUMN deleted_at TIMESTAMP NULL AFTER updated_at,
+    ADD INDEX idx_deleted_at (deleted_at);
+
+ALTER TABLE queue_display_announcements
+    ADD COLUMN deleted_at TIMESTAMP NULL AFTER updated_at,
+    ADD INDEX idx_deleted_at (deleted_at);
+
+ALTER TABLE queue_configuration
+    ADD COLUMN deleted_at TIMESTAMP NULL AFTER updated_by,
+    ADD INDEX idx_deleted_at (deleted_at);
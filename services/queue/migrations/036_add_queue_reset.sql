@@ -0,0 +1,25 @@
+-- ============================================
+-- Queue reset
+-- ============================================
+-- Admins can close out a location's queue (at close of business, or after
+-- test data got into it). Every entry the reset completes or expires logs a
+-- RESET action.
+ALTER TABLE staff_queue_actions_log
+    MODIFY action ENUM(
+        'START_PREPARATION', 'MARK_READY', 'MARK_COMPLETED',
+        'CANCEL', 'REASSIGN', 'ADJUST_PRIORITY', 'ADD_NOTE',
+        'MARK_PENDING_PAYMENT', 'MARK_WAITING', 'MARK_IN_PROGRESS',
+        'MARK_CANCELLED', 'MARK_NO_SHOW', 'MARK_EXPIRED',
+        'DEFER_START', 'REORDER', 'REQUEUE', 'SKIP', 'RESTORE_SKIP',
+        'RECALL', 'LINK_ORDERS', 'RESET'
+    ) NOT NULL;
+
+ALTER TABLE staff_queue_actions_log_archive
+    MODIFY action ENUM(
+        'START_PREPARATION', 'MARK_READY', 'MARK_COMPLETED',
+        'CANCEL', 'REASSIGN', 'ADJUST_PRIORITY', 'ADD_NOTE',
+        'MARK_PENDING_PAYMENT', 'MARK_WAITING', 'MARK_IN_PROGRESS',
+        'MARK_CANCELLED', 'MARK_NO_SHOW', 'MARK_EXPIRED',
+        'DEFER_START', 'REORDER', 'REQUEUE', 'SKIP', 'RESTORE_SKIP',
+        'RECALL', 'LINK_ORDERS', 'RESET'
+    ) NOT NULL;
@@ -0,0 +1,19 @@
+-- ============================================
+-- Queue Day Rollovers Table
+-- ============================================
+-- Once a location's closing time (from its working hours) has passed, the
+-- end-of-day rollover expires leftover entries, finalizes the day's
+-- statistics and can rotate the token prefix. Each location's business day
+-- is rolled over once; the unique key lets one instance claim it.
+CREATE TABLE IF NOT EXISTS queue_day_rollovers (
+    id VARCHAR(36) PRIMARY KEY,
+    location_id VARCHAR(36) NOT NULL,
+    business_date DATE NOT NULL,
+    closed_at TIMESTAMP NOT NULL,
+    expired_entries INT NOT NULL DEFAULT 0,
+    tokens_issued INT NOT NULL DEFAULT 0,
+    token_prefix VARCHAR(3) NOT NULL,
+    next_token_prefix VARCHAR(3) NOT NULL,
+
+    UNIQUE INDEX idx_location_business_date (location_id, business_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
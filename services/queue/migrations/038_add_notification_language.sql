@@ -0,0 +1,7 @@
+-- ============================================
+-- Notification language
+-- ============================================
+-- Customers may pick the language of their SMS, email and push notifications
+-- (en, es, hi). NULL sends them in DEFAULT_LANGUAGE.
+ALTER TABLE queue_notification_preferences
+    ADD COLUMN language VARCHAR(8) NULL AFTER email_enabled;
@@ -0,0 +1,29 @@
+-- ============================================
+-- Queue Audit Log Table
+-- ============================================
+-- Every mutating request to the staff and admin routes: the actor, the route,
+-- the request body (personal and secret fields redacted) and the outcome.
+-- Unlike staff_queue_actions_log it doesn't depend on the service path
+-- recording it.
+CREATE TABLE IF NOT EXISTS queue_audit_log (
+    id VARCHAR(36) PRIMARY KEY,
+    actor_id VARCHAR(36) NOT NULL,
+    actor_name VARCHAR(255) NULL,
+    actor_role VARCHAR(16) NOT NULL,
+    method VARCHAR(8) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path VARCHAR(512) NOT NULL,
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    payload TEXT NULL,
+    status_code INT NOT NULL,
+    error_code VARCHAR(64) NULL,
+    request_id VARCHAR(64) NULL,
+    client_ip VARCHAR(45) NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_actor_id (actor_id),
+    INDEX idx_route (route),
+    INDEX idx_location_id (location_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,8 @@
+-- ============================================
+-- Dead letter payload encoding
+-- ============================================
+-- Protobuf and Avro message values aren't valid utf8mb4 text, so dead letters
+-- holding them store the payload base64-encoded and mark it BASE64. Re-driving
+-- decodes it back to the original bytes.
+ALTER TABLE queue_dead_letter_messages
+    ADD COLUMN payload_encoding ENUM('TEXT', 'BASE64') NOT NULL DEFAULT 'TEXT' AFTER payload;
@@ -0,0 +1,639 @@
+-- ============================================
+-- Queue Service Database Schema (PostgreSQL)
+-- ============================================
+-- The PostgreSQL schema for DB_DRIVER=postgres, equivalent to the MySQL
+-- migrations 001 through 035 applied in order. Later MySQL migrations get a
+-- PostgreSQL counterpart in this directory.
+--
+-- Enumerated columns are VARCHAR with a CHECK constraint rather than ENUM.
+-- There is no ON UPDATE CURRENT_TIMESTAMP: the service sets updated_at on
+-- every update itself. Timestamps are stored in UTC.
+
+-- ============================================
+-- Locations
+-- ============================================
+CREATE TABLE IF NOT EXISTS queue_locations (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    token_prefix VARCHAR(3) UNIQUE NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_locations_is_active ON queue_locations (is_active);
+
+INSERT INTO queue_locations (id, name, token_prefix)
+VALUES ('default', 'Default', 'A')
+ON CONFLICT DO NOTHING;
+
+-- ============================================
+-- Queue Configuration
+-- ============================================
+-- One row per location with its own configuration; the others use the
+-- default location's
+CREATE TABLE IF NOT EXISTS queue_configuration (
+    id VARCHAR(36) PRIMARY KEY,
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default' UNIQUE,
+
+    -- Capacity settings
+    max_concurrent_orders INT DEFAULT 10 CHECK (max_concurrent_orders > 0),
+    avg_preparation_time_per_item INT DEFAULT 5 CHECK (avg_preparation_time_per_item > 0),
+    adaptive_prep_time_enabled BOOLEAN DEFAULT FALSE,
+    prep_time_smoothing DECIMAL(4, 3) NULL,
+    learned_prep_time_per_item DOUBLE PRECISION NULL,
+    prep_time_learned_at TIMESTAMP NULL,
+    buffer_time INT DEFAULT 2 CHECK (buffer_time >= 0),
+
+    -- Express queue settings
+    express_queue_enabled BOOLEAN DEFAULT FALSE,
+    express_queue_max_items INT DEFAULT 3 CHECK (express_queue_max_items > 0),
+    regular_serve_ratio INT DEFAULT 2,
+    express_serve_ratio INT DEFAULT 1,
+    requeue_placement VARCHAR(16) DEFAULT 'BACK',
+    skip_positions INT DEFAULT 3,
+    skip_timeout_minutes INT DEFAULT 5,
+
+    -- Alert settings
+    max_wait_time_alert INT DEFAULT 30,
+    sla_minutes_vip INT NULL,
+    sla_minutes_urgent INT NULL,
+    sla_minutes_high INT NULL,
+    sla_minutes_normal INT NULL,
+    sla_minutes_low INT NULL,
+    token_expiry_time INT DEFAULT 60,
+
+    -- Notification settings
+    auto_notification_enabled BOOLEAN DEFAULT TRUE,
+    notification_position_threshold INT DEFAULT 5,
+    notification_almost_ready_threshold INT DEFAULT 2,
+
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_by VARCHAR(36),
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_configuration_deleted_at ON queue_configuration (deleted_at);
+
+INSERT INTO queue_configuration (id, location_id)
+VALUES ('00000000-0000-0000-0000-000000000001', 'default')
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS queue_working_hours (
+    id VARCHAR(36) PRIMARY KEY,
+    configuration_id VARCHAR(36) NOT NULL REFERENCES queue_configuration(id) ON DELETE CASCADE,
+    day VARCHAR(16) NOT NULL CHECK (day IN ('MONDAY', 'TUESDAY', 'WEDNESDAY', 'THURSDAY', 'FRIDAY', 'SATURDAY', 'SUNDAY')),
+    open_time VARCHAR(5) NOT NULL, -- HH:MM
+    close_time VARCHAR(5) NOT NULL, -- HH:MM
+    is_open BOOLEAN DEFAULT TRUE,
+
+    CONSTRAINT unique_config_day UNIQUE (configuration_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_working_hours_day ON queue_working_hours (day);
+
+INSERT INTO queue_working_hours (id, configuration_id, day, open_time, close_time, is_open) VALUES
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'MONDAY', '08:00', '22:00', TRUE),
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'TUESDAY', '08:00', '22:00', TRUE),
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'WEDNESDAY', '08:00', '22:00', TRUE),
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'THURSDAY', '08:00', '22:00', TRUE),
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'FRIDAY', '08:00', '22:00', TRUE),
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'SATURDAY', '08:00', '22:00', TRUE),
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'SUNDAY', '08:00', '22:00', TRUE)
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS queue_priority_multipliers (
+    id VARCHAR(36) PRIMARY KEY,
+    configuration_id VARCHAR(36) NOT NULL REFERENCES queue_configuration(id) ON DELETE CASCADE,
+    priority VARCHAR(16) NOT NULL CHECK (priority IN ('LOW', 'NORMAL', 'HIGH', 'URGENT', 'VIP')),
+    multiplier DECIMAL(3, 2) DEFAULT 1.00 CHECK (multiplier BETWEEN 0.1 AND 10.0),
+
+    CONSTRAINT unique_config_priority UNIQUE (configuration_id, priority)
+);
+
+INSERT INTO queue_priority_multipliers (id, configuration_id, priority, multiplier) VALUES
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'LOW', 1.50),
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'NORMAL', 1.00),
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'HIGH', 0.70),
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'URGENT', 0.50),
+    (gen_random_uuid()::text, '00000000-0000-0000-0000-000000000001', 'VIP', 0.30)
+ON CONFLICT DO NOTHING;
+
+-- ============================================
+-- Queue Entries
+-- ============================================
+CREATE TABLE IF NOT EXISTS queue_entries (
+    id VARCHAR(36) PRIMARY KEY,
+    order_id VARCHAR(36) UNIQUE NOT NULL, -- Reference to order service (no FK)
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    user_id VARCHAR(36) NOT NULL, -- Reference to auth service (no FK)
+
+    -- User info cached from auth service
+    user_name VARCHAR(200),
+    user_phone VARCHAR(20),
+
+    -- Token information
+    token_number VARCHAR(20) UNIQUE NOT NULL,
+    token_type VARCHAR(16) DEFAULT 'REGULAR' CHECK (token_type IN ('REGULAR', 'EXPRESS', 'BULK', 'SPECIAL', 'STAFF', 'WALK_IN')),
+
+    -- Queue status
+    status VARCHAR(16) DEFAULT 'WAITING' CHECK (status IN (
+        'PENDING_PAYMENT', 'SCHEDULED', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    )),
+    priority VARCHAR(16) DEFAULT 'NORMAL' CHECK (priority IN ('LOW', 'NORMAL', 'HIGH', 'URGENT', 'VIP')),
+
+    -- Position and timing
+    position INT NOT NULL CONSTRAINT chk_queue_entries_position CHECK (position >= 0),
+    item_count INT NOT NULL DEFAULT 0,
+    estimated_wait_time INT DEFAULT 0, -- minutes
+    estimated_ready_time TIMESTAMP NULL,
+    requested_pickup_time TIMESTAMP NULL,
+    scheduled_activate_at TIMESTAMP NULL,
+
+    -- Actual timing
+    actual_start_time TIMESTAMP NULL,
+    actual_ready_time TIMESTAMP NULL,
+    actual_completion_time TIMESTAMP NULL,
+    start_deferred_at TIMESTAMP NULL,
+    skipped_at TIMESTAMP NULL,
+    skipped_positions INT NOT NULL DEFAULT 0,
+    skip_restore_at TIMESTAMP NULL,
+    recall_count INT NOT NULL DEFAULT 0,
+    last_recalled_at TIMESTAMP NULL,
+    sla_breached_at TIMESTAMP NULL,
+
+    -- Assignment
+    assigned_counter VARCHAR(50),
+    counter_id VARCHAR(36) NULL,
+    assigned_staff VARCHAR(36),
+    assigned_staff_name VARCHAR(100),
+
+    -- Preparation
+    average_item_preparation_time INT, -- minutes
+
+    -- Special handling
+    is_express_queue BOOLEAN DEFAULT FALSE,
+    special_handling TEXT,
+    notes TEXT,
+
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_entries_user_id ON queue_entries (user_id);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_status ON queue_entries (status);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_priority ON queue_entries (priority);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_position ON queue_entries (position);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_assigned_staff ON queue_entries (assigned_staff);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_assigned_counter ON queue_entries (assigned_counter);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_counter_id ON queue_entries (counter_id);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_created_at ON queue_entries (created_at);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_estimated_ready_time ON queue_entries (estimated_ready_time);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_location_id ON queue_entries (location_id);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_location_created_at ON queue_entries (location_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_location_start_deferred ON queue_entries (location_id, start_deferred_at);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_location_lane_position ON queue_entries (location_id, is_express_queue, position);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_skip_restore_at ON queue_entries (skip_restore_at);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_status_scheduled_activate_at ON queue_entries (status, scheduled_activate_at);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_sla_breached_at ON queue_entries (sla_breached_at);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_deleted_at ON queue_entries (deleted_at);
+
+CREATE TABLE IF NOT EXISTS queue_notifications_sent (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL REFERENCES queue_entries(id) ON DELETE CASCADE,
+    notification_type VARCHAR(16) NOT NULL CHECK (notification_type IN ('ORDER_CONFIRMED', 'POSITION_UPDATE', 'ALMOST_READY', 'READY', 'REMINDER')),
+    channel VARCHAR(16) NOT NULL CHECK (channel IN ('PUSH', 'IN_APP', 'SMS', 'EMAIL')),
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    provider_message_id VARCHAR(64) NULL,
+    delivery_status VARCHAR(32) NULL,
+    delivery_error VARCHAR(255) NULL,
+    status_updated_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_notifications_sent_queue_entry_id ON queue_notifications_sent (queue_entry_id);
+CREATE INDEX IF NOT EXISTS idx_queue_notifications_sent_notification_type ON queue_notifications_sent (notification_type);
+CREATE INDEX IF NOT EXISTS idx_queue_notifications_sent_sent_at ON queue_notifications_sent (sent_at);
+CREATE INDEX IF NOT EXISTS idx_queue_notifications_sent_provider_message_id ON queue_notifications_sent (provider_message_id);
+
+CREATE TABLE IF NOT EXISTS queue_position_history (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL REFERENCES queue_entries(id) ON DELETE CASCADE,
+    old_position INT NOT NULL,
+    new_position INT NOT NULL,
+    old_status VARCHAR(16) NOT NULL CHECK (old_status IN (
+        'PENDING_PAYMENT', 'SCHEDULED', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    )),
+    new_status VARCHAR(16) NOT NULL CHECK (new_status IN (
+        'PENDING_PAYMENT', 'SCHEDULED', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    )),
+    estimated_wait_time INT,
+    estimated_ready_time TIMESTAMP,
+    reason TEXT,
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_position_history_queue_entry_id ON queue_position_history (queue_entry_id);
+CREATE INDEX IF NOT EXISTS idx_queue_position_history_timestamp ON queue_position_history (timestamp);
+
+CREATE TABLE IF NOT EXISTS staff_queue_actions_log (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL REFERENCES queue_entries(id) ON DELETE CASCADE,
+    staff_id VARCHAR(36) NOT NULL,
+    staff_name VARCHAR(100),
+    shift_id VARCHAR(36) NULL,
+    action VARCHAR(32) NOT NULL CHECK (action IN (
+        'START_PREPARATION', 'MARK_READY', 'MARK_COMPLETED',
+        'CANCEL', 'REASSIGN', 'ADJUST_PRIORITY', 'ADD_NOTE',
+        'MARK_PENDING_PAYMENT', 'MARK_WAITING', 'MARK_IN_PROGRESS',
+        'MARK_CANCELLED', 'MARK_NO_SHOW', 'MARK_EXPIRED',
+        'DEFER_START', 'REORDER', 'REQUEUE', 'SKIP', 'RESTORE_SKIP',
+        'RECALL', 'LINK_ORDERS'
+    )),
+    old_status VARCHAR(16) CHECK (old_status IN (
+        'PENDING_PAYMENT', 'SCHEDULED', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    )),
+    new_status VARCHAR(16) CHECK (new_status IN (
+        'PENDING_PAYMENT', 'SCHEDULED', 'WAITING', 'IN_PROGRESS', 'READY',
+        'COMPLETED', 'CANCELLED', 'NO_SHOW', 'EXPIRED'
+    )),
+    old_priority VARCHAR(16) CHECK (old_priority IN ('LOW', 'NORMAL', 'HIGH', 'URGENT', 'VIP')),
+    new_priority VARCHAR(16) CHECK (new_priority IN ('LOW', 'NORMAL', 'HIGH', 'URGENT', 'VIP')),
+    assigned_counter VARCHAR(50),
+    assigned_staff VARCHAR(36),
+    note TEXT,
+    reason TEXT,
+    timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_staff_queue_actions_log_queue_entry_id ON staff_queue_actions_log (queue_entry_id);
+CREATE INDEX IF NOT EXISTS idx_staff_queue_actions_log_staff_id ON staff_queue_actions_log (staff_id);
+CREATE INDEX IF NOT EXISTS idx_staff_queue_actions_log_shift_id ON staff_queue_actions_log (shift_id);
+CREATE INDEX IF NOT EXISTS idx_staff_queue_actions_log_action ON staff_queue_actions_log (action);
+CREATE INDEX IF NOT EXISTS idx_staff_queue_actions_log_timestamp ON staff_queue_actions_log (timestamp);
+
+-- Sub-entries that share the parent entry's token and become ready independently
+CREATE TABLE IF NOT EXISTS queue_entry_stages (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL REFERENCES queue_entries(id) ON DELETE CASCADE,
+    token_number VARCHAR(20) NOT NULL,
+    sequence INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    item_count INT DEFAULT 0,
+    status VARCHAR(16) DEFAULT 'WAITING' CHECK (status IN ('WAITING', 'IN_PROGRESS', 'READY', 'COMPLETED', 'CANCELLED')),
+    ready_after_minutes INT DEFAULT 0, -- offset from the previous stage
+    estimated_ready_time TIMESTAMP NULL,
+    actual_start_time TIMESTAMP NULL,
+    actual_ready_time TIMESTAMP NULL,
+    actual_completion_time TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uk_entry_sequence UNIQUE (queue_entry_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_entry_stages_token_number ON queue_entry_stages (token_number);
+CREATE INDEX IF NOT EXISTS idx_queue_entry_stages_status ON queue_entry_stages (status);
+
+-- Orders sharing one pickup token
+CREATE TABLE IF NOT EXISTS queue_entry_orders (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL REFERENCES queue_entries(id) ON DELETE CASCADE,
+    order_id VARCHAR(36) NOT NULL,
+    token_number VARCHAR(20) NOT NULL,
+    status VARCHAR(16) DEFAULT 'WAITING' CHECK (status IN ('WAITING', 'IN_PROGRESS', 'READY', 'COMPLETED', 'CANCELLED')),
+    actual_start_time TIMESTAMP NULL,
+    actual_ready_time TIMESTAMP NULL,
+    actual_completion_time TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uk_order_id UNIQUE (order_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_entry_orders_queue_entry_id ON queue_entry_orders (queue_entry_id);
+CREATE INDEX IF NOT EXISTS idx_queue_entry_orders_token_number ON queue_entry_orders (token_number);
+CREATE INDEX IF NOT EXISTS idx_queue_entry_orders_status ON queue_entry_orders (status);
+
+-- Line items per entry
+CREATE TABLE IF NOT EXISTS queue_entry_items (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL REFERENCES queue_entries(id) ON DELETE CASCADE,
+    menu_item_id VARCHAR(36) NOT NULL,
+    item_name VARCHAR(255),
+    quantity INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_entry_items_queue_entry_id ON queue_entry_items (queue_entry_id);
+CREATE INDEX IF NOT EXISTS idx_queue_entry_items_menu_item_id ON queue_entry_items (menu_item_id);
+
+-- Markers for deleted/anonymized entries, purged after a grace period
+CREATE TABLE IF NOT EXISTS queue_entry_tombstones (
+    id VARCHAR(36) PRIMARY KEY,
+    queue_entry_id VARCHAR(36) NOT NULL,
+    order_id VARCHAR(36) NOT NULL,
+    token_number VARCHAR(20) NOT NULL,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('DELETED', 'ANONYMIZED')),
+    reason TEXT,
+    performed_by VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_entry_tombstones_queue_entry_id ON queue_entry_tombstones (queue_entry_id);
+CREATE INDEX IF NOT EXISTS idx_queue_entry_tombstones_created_at ON queue_entry_tombstones (created_at);
+
+-- ============================================
+-- Archive Tables
+-- ============================================
+-- Finished and deleted entries move here with their position history and
+-- staff action logs. Rows are copied with INSERT ... SELECT *, so the
+-- archive tables must keep the columns of their hot tables in order.
+CREATE TABLE IF NOT EXISTS queue_entries_archive (LIKE queue_entries INCLUDING DEFAULTS);
+ALTER TABLE queue_entries_archive ADD PRIMARY KEY (id);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_archive_order_id ON queue_entries_archive (order_id);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_archive_location_created_at ON queue_entries_archive (location_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_queue_entries_archive_deleted_at ON queue_entries_archive (deleted_at);
+
+CREATE TABLE IF NOT EXISTS queue_position_history_archive (LIKE queue_position_history INCLUDING DEFAULTS);
+ALTER TABLE queue_position_history_archive ADD PRIMARY KEY (id);
+CREATE INDEX IF NOT EXISTS idx_queue_position_history_archive_queue_entry_id ON queue_position_history_archive (queue_entry_id);
+
+CREATE TABLE IF NOT EXISTS staff_queue_actions_log_archive (LIKE staff_queue_actions_log INCLUDING DEFAULTS);
+ALTER TABLE staff_queue_actions_log_archive ADD PRIMARY KEY (id);
+CREATE INDEX IF NOT EXISTS idx_staff_queue_actions_log_archive_queue_entry_id ON staff_queue_actions_log_archive (queue_entry_id);
+
+-- ============================================
+-- Announcements, Counters and Shifts
+-- ============================================
+CREATE TABLE IF NOT EXISTS queue_display_announcements (
+    id VARCHAR(36) PRIMARY KEY,
+    message TEXT NOT NULL,
+    type VARCHAR(16) DEFAULT 'INFO' CHECK (type IN ('INFO', 'WARNING', 'URGENT')),
+    priority INT DEFAULT 0,
+    is_active BOOLEAN DEFAULT TRUE,
+    display_until TIMESTAMP,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_display_announcements_is_active ON queue_display_announcements (is_active);
+CREATE INDEX IF NOT EXISTS idx_queue_display_announcements_display_until ON queue_display_announcements (display_until);
+CREATE INDEX IF NOT EXISTS idx_queue_display_announcements_priority ON queue_display_announcements (priority);
+CREATE INDEX IF NOT EXISTS idx_queue_display_announcements_deleted_at ON queue_display_announcements (deleted_at);
+
+CREATE TABLE IF NOT EXISTS queue_counters (
+    id VARCHAR(36) PRIMARY KEY,
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    name VARCHAR(50) NOT NULL,
+    is_open BOOLEAN DEFAULT TRUE,
+    serving_entry_id VARCHAR(36) NULL,
+    serving_since TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT idx_location_name UNIQUE (location_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_counters_is_open ON queue_counters (is_open);
+
+CREATE TABLE IF NOT EXISTS queue_staff_shifts (
+    id VARCHAR(36) PRIMARY KEY,
+    staff_id VARCHAR(36) NOT NULL,
+    staff_name VARCHAR(100),
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    counter_id VARCHAR(36) NULL,
+    clock_in_at TIMESTAMP NOT NULL,
+    clock_out_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A staff member has one open shift at most
+CREATE UNIQUE INDEX IF NOT EXISTS idx_queue_staff_shifts_open_staff_id ON queue_staff_shifts (staff_id) WHERE clock_out_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_queue_staff_shifts_staff_clock_in ON queue_staff_shifts (staff_id, clock_in_at);
+CREATE INDEX IF NOT EXISTS idx_queue_staff_shifts_location_open ON queue_staff_shifts (location_id, clock_out_at);
+CREATE INDEX IF NOT EXISTS idx_queue_staff_shifts_counter_id ON queue_staff_shifts (counter_id);
+
+-- Caps on waiting entries per lane and priority
+CREATE TABLE IF NOT EXISTS queue_depth_limits (
+    id VARCHAR(36) PRIMARY KEY,
+    lane VARCHAR(16) NOT NULL DEFAULT 'ANY' CHECK (lane IN ('ANY', 'REGULAR', 'EXPRESS')),
+    priority VARCHAR(16) NOT NULL DEFAULT 'ANY' CHECK (priority IN ('ANY', 'NORMAL', 'HIGH', 'URGENT', 'VIP')),
+    max_waiting INT NOT NULL,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uk_lane_priority UNIQUE (lane, priority)
+);
+
+-- ============================================
+-- Statistics
+-- ============================================
+CREATE TABLE IF NOT EXISTS queue_statistics (
+    id VARCHAR(36) PRIMARY KEY,
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    date DATE NOT NULL,
+
+    -- Queue counts
+    total_in_queue INT DEFAULT 0,
+    waiting_count INT DEFAULT 0,
+    in_progress_count INT DEFAULT 0,
+    ready_count INT DEFAULT 0,
+    completed_today INT DEFAULT 0,
+    cancelled_today INT DEFAULT 0,
+    no_show_today INT DEFAULT 0,
+    expired_today INT DEFAULT 0,
+    walk_ins_today INT DEFAULT 0,
+
+    -- Performance metrics, in minutes
+    avg_wait_time INT DEFAULT 0,
+    avg_preparation_time INT DEFAULT 0,
+    wait_time_p50 INT DEFAULT 0,
+    wait_time_p90 INT DEFAULT 0,
+    wait_time_p99 INT DEFAULT 0,
+    prep_time_p50 INT DEFAULT 0,
+    prep_time_p90 INT DEFAULT 0,
+    prep_time_p99 INT DEFAULT 0,
+    longest_wait_time INT DEFAULT 0,
+    shortest_wait_time INT DEFAULT 0,
+
+    -- Capacity metrics
+    current_load DECIMAL(5, 2) DEFAULT 0.00, -- percentage
+    peak_load DECIMAL(5, 2) DEFAULT 0.00, -- percentage
+    peak_load_time VARCHAR(5), -- HH:MM
+    peak_queue_length INT DEFAULT 0,
+    peak_queue_length_time VARCHAR(5), -- HH:MM
+
+    -- Customer satisfaction
+    on_time_completion_rate DECIMAL(5, 2) DEFAULT 0.00, -- percentage
+    no_show_rate DECIMAL(5, 2) DEFAULT 0.00, -- percentage
+
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT idx_location_date UNIQUE (location_id, date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_statistics_date ON queue_statistics (date);
+
+CREATE TABLE IF NOT EXISTS queue_hourly_statistics (
+    id VARCHAR(36) PRIMARY KEY,
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    date DATE NOT NULL,
+    hour INT NOT NULL CHECK (hour BETWEEN 0 AND 23),
+
+    -- Hourly metrics, times in minutes
+    order_count INT DEFAULT 0,
+    avg_wait_time INT DEFAULT 0,
+    avg_preparation_time INT DEFAULT 0,
+    wait_time_p50 INT DEFAULT 0,
+    wait_time_p90 INT DEFAULT 0,
+    wait_time_p99 INT DEFAULT 0,
+    prep_time_p50 INT DEFAULT 0,
+    prep_time_p90 INT DEFAULT 0,
+    prep_time_p99 INT DEFAULT 0,
+    completed_count INT DEFAULT 0,
+    cancelled_count INT DEFAULT 0,
+    peak_position INT DEFAULT 0,
+
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT idx_location_date_hour UNIQUE (location_id, date, hour)
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_hourly_statistics_date ON queue_hourly_statistics (date);
+CREATE INDEX IF NOT EXISTS idx_queue_hourly_statistics_hour ON queue_hourly_statistics (hour);
+
+CREATE TABLE IF NOT EXISTS queue_load_samples (
+    id VARCHAR(36) PRIMARY KEY,
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    sampled_at TIMESTAMP NOT NULL,
+    waiting_count INT NOT NULL DEFAULT 0,
+    in_progress_count INT NOT NULL DEFAULT 0,
+    max_concurrent_orders INT NOT NULL DEFAULT 0,
+    kitchen_load DECIMAL(5, 2) NOT NULL DEFAULT 0.00, -- percentage
+
+    CONSTRAINT idx_location_sampled_at UNIQUE (location_id, sampled_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_load_samples_sampled_at ON queue_load_samples (sampled_at);
+
+CREATE TABLE IF NOT EXISTS queue_token_counter (
+    id VARCHAR(36) PRIMARY KEY,
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    date DATE NOT NULL,
+    current_number INT DEFAULT 0,
+    prefix VARCHAR(3) DEFAULT 'A',
+    last_reset_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uk_token_counter_location_date UNIQUE (location_id, date)
+);
+
+CREATE TABLE IF NOT EXISTS queue_kpi_definitions (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    expression VARCHAR(500) NOT NULL,
+    description TEXT,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_kpi_definitions_is_active ON queue_kpi_definitions (is_active);
+
+-- ============================================
+-- Event Consumption
+-- ============================================
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_key VARCHAR(255) PRIMARY KEY, -- event:<event_id> or <topic>/<partition>/<offset>
+    topic VARCHAR(255) NOT NULL,
+    partition_id INT NOT NULL,
+    offset_id BIGINT NOT NULL,
+    processed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_processed_events_processed_at ON processed_events (processed_at);
+
+CREATE TABLE IF NOT EXISTS queue_dead_letter_messages (
+    id VARCHAR(36) PRIMARY KEY,
+    original_topic VARCHAR(255) NOT NULL,
+    partition_id INT NOT NULL,
+    offset_id BIGINT NOT NULL,
+    message_key VARCHAR(255),
+    payload TEXT NOT NULL,
+    error TEXT NOT NULL,
+    attempts INT DEFAULT 1,
+    status VARCHAR(16) DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'REDRIVEN', 'DISCARDED')),
+    redrive_count INT DEFAULT 0,
+    last_redriven_at TIMESTAMP NULL,
+    last_redriven_by VARCHAR(36),
+    failed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_dead_letter_messages_original_topic ON queue_dead_letter_messages (original_topic);
+CREATE INDEX IF NOT EXISTS idx_queue_dead_letter_messages_status ON queue_dead_letter_messages (status);
+CREATE INDEX IF NOT EXISTS idx_queue_dead_letter_messages_failed_at ON queue_dead_letter_messages (failed_at);
+
+-- ============================================
+-- Notifications and Webhooks
+-- ============================================
+CREATE TABLE IF NOT EXISTS queue_notification_preferences (
+    user_id VARCHAR(36) PRIMARY KEY,
+    email VARCHAR(255),
+    sms_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    email_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS queue_device_tokens (
+    id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    token VARCHAR(512) NOT NULL,
+    platform VARCHAR(16) NULL CHECK (platform IN ('android', 'ios', 'web')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT uk_token UNIQUE (token)
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_device_tokens_user_id ON queue_device_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS queue_webhook_subscriptions (
+    id VARCHAR(36) PRIMARY KEY,
+    url VARCHAR(512) NOT NULL,
+    event_types JSON NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    description VARCHAR(255),
+    is_active BOOLEAN DEFAULT TRUE,
+    created_by VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_webhook_subscriptions_is_active ON queue_webhook_subscriptions (is_active);
+
+CREATE TABLE IF NOT EXISTS queue_webhook_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    subscription_id VARCHAR(36) NOT NULL REFERENCES queue_webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(16) DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SUCCEEDED', 'FAILED')),
+    attempts INT DEFAULT 0,
+    response_status INT,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP NULL,
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_webhook_deliveries_subscription_created ON queue_webhook_deliveries (subscription_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_queue_webhook_deliveries_status_next_attempt ON queue_webhook_deliveries (status, next_attempt_at);
@@ -0,0 +1,17 @@
+-- ============================================
+-- Queue reset (PostgreSQL counterpart of 036_add_queue_reset.sql)
+-- ============================================
+-- Entries closed out by an admin queue reset log a RESET action. The archive
+-- table was created without the CHECK constraint, so only the hot table changes.
+ALTER TABLE staff_queue_actions_log
+    DROP CONSTRAINT IF EXISTS staff_queue_actions_log_action_check;
+
+ALTER TABLE staff_queue_actions_log
+    ADD CONSTRAINT staff_queue_actions_log_action_check CHECK (action IN (
+        'START_PREPARATION', 'MARK_READY', 'MARK_COMPLETED',
+        'CANCEL', 'REASSIGN', 'ADJUST_PRIORITY', 'ADD_NOTE',
+        'MARK_PENDING_PAYMENT', 'MARK_WAITING', 'MARK_IN_PROGRESS',
+        'MARK_CANCELLED', 'MARK_NO_SHOW', 'MARK_EXPIRED',
+        'DEFER_START', 'REORDER', 'REQUEUE', 'SKIP', 'RESTORE_SKIP',
+        'RECALL', 'LINK_ORDERS', 'RESET'
+    ));
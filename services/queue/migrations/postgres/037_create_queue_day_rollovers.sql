@@ -0,0 +1,16 @@
+-- ============================================
+-- Queue Day Rollovers (PostgreSQL counterpart of 037_create_queue_day_rollovers.sql)
+-- ============================================
+-- One row per location and business day closed by the end-of-day rollover.
+CREATE TABLE IF NOT EXISTS queue_day_rollovers (
+    id VARCHAR(36) PRIMARY KEY,
+    location_id VARCHAR(36) NOT NULL,
+    business_date DATE NOT NULL,
+    closed_at TIMESTAMP NOT NULL,
+    expired_entries INT NOT NULL DEFAULT 0,
+    tokens_issued INT NOT NULL DEFAULT 0,
+    token_prefix VARCHAR(3) NOT NULL,
+    next_token_prefix VARCHAR(3) NOT NULL,
+
+    CONSTRAINT idx_location_business_date UNIQUE (location_id, business_date)
+);
@@ -0,0 +1,6 @@
+-- ============================================
+-- Notification language (PostgreSQL counterpart of 038_add_notification_language.sql)
+-- ============================================
+-- NULL sends notifications in DEFAULT_LANGUAGE.
+ALTER TABLE queue_notification_preferences
+    ADD COLUMN IF NOT EXISTS language VARCHAR(8);
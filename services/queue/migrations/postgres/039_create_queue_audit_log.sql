@@ -0,0 +1,26 @@
+-- ============================================
+-- Queue Audit Log (PostgreSQL counterpart of 039_create_queue_audit_log.sql)
+-- ============================================
+-- Every mutating request to the staff and admin routes and its outcome.
+CREATE TABLE IF NOT EXISTS queue_audit_log (
+    id VARCHAR(36) PRIMARY KEY,
+    actor_id VARCHAR(36) NOT NULL,
+    actor_name VARCHAR(255),
+    actor_role VARCHAR(16) NOT NULL,
+    method VARCHAR(8) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path VARCHAR(512) NOT NULL,
+    location_id VARCHAR(36) NOT NULL DEFAULT 'default',
+    payload TEXT,
+    status_code INT NOT NULL,
+    error_code VARCHAR(64),
+    request_id VARCHAR(64),
+    client_ip VARCHAR(45) NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_audit_log_actor_id ON queue_audit_log (actor_id);
+CREATE INDEX IF NOT EXISTS idx_queue_audit_log_route ON queue_audit_log (route);
+CREATE INDEX IF NOT EXISTS idx_queue_audit_log_location_id ON queue_audit_log (location_id);
+CREATE INDEX IF NOT EXISTS idx_queue_audit_log_created_at ON queue_audit_log (created_at);
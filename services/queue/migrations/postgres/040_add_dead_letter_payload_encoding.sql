@@ -0,0 +1,7 @@
+-- ============================================
+-- Dead letter payload encoding (PostgreSQL counterpart of 040_add_dead_letter_payload_encoding.sql)
+-- ============================================
+-- Binary payloads (protobuf, Avro) are stored base64-encoded and marked BASE64.
+ALTER TABLE queue_dead_letter_messages
+    ADD COLUMN IF NOT EXISTS payload_encoding VARCHAR(8) NOT NULL DEFAULT 'TEXT'
+        CHECK (payload_encoding IN ('TEXT', 'BASE64'));
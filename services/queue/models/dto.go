@@ -6,35 +6,352 @@ import "time"
 type CreateQueueEntryRequest struct {
 	OrderID         string `json:"order_id" binding:"required"`
 	UserID          string `json:"user_id" binding:"required"`
+	LocationID      string `json:"location_id"`
 	UserName        string `json:"user_name"`
 	UserPhone       string `json:"user_phone"`
-	TokenType       string `json:"token_type"`
-	Priority        string `json:"priority"`
+	TokenType       string `json:"token_type" binding:"omitempty,token_type"`
+	Priority        string `json:"priority" binding:"omitempty,queue_priority"`
 	IsExpressQueue  bool   `json:"is_express_queue"`
 	SpecialHandling string `json:"special_handling"`
 	ItemCount       int    `json:"item_count"`
+
+	// Optional staged readiness (e.g. appetizers now, mains later)
+	Stages []CreateQueueStageRequest `json:"stages"`
+
+	// Optional line items, used for kitchen batch suggestions
+	Items []CreateQueueItemRequest `json:"items"`
+
+	// Unpaid orders wait in PENDING_PAYMENT until payment completes
+	AwaitingPayment bool `json:"awaiting_payment"`
+
+	// Orders for later wait in SCHEDULED until their preparation is due
+	RequestedPickupTime *time.Time `json:"requested_pickup_time"`
+}
+
+// CreateWalkInRequest represents request to queue a walk-in customer who
+// has no upstream order
+type CreateWalkInRequest struct {
+	UserName        string `json:"user_name" binding:"required"`
+	UserPhone       string `json:"user_phone" binding:"required"`
+	ItemCount       int    `json:"item_count" binding:"required,min=1"`
+	LocationID      string `json:"location_id"`
+	SpecialHandling string `json:"special_handling"`
+}
+
+// CreateQueueItemRequest describes one line item of the order
+type CreateQueueItemRequest struct {
+	MenuItemID string `json:"menu_item_id" binding:"required"`
+	ItemName   string `json:"item_name"`
+	Quantity   int    `json:"quantity"`
+}
+
+// CreateQueueStageRequest describes one stage of a staged entry
+type CreateQueueStageRequest struct {
+	Name              string `json:"name" binding:"required"`
+	ItemCount         int    `json:"item_count"`
+	ReadyAfterMinutes int    `json:"ready_after_minutes"`
+}
+
+// DefineQueueStagesRequest represents request to split an entry into stages
+type DefineQueueStagesRequest struct {
+	Stages []CreateQueueStageRequest `json:"stages" binding:"required,min=1,dive"`
+}
+
+// UpdateQueueStageStatusRequest represents request to update a stage status
+type UpdateQueueStageStatusRequest struct {
+	Status string  `json:"status" binding:"required,stage_status"`
+	Reason *string `json:"reason"`
+}
+
+// LinkOrdersRequest represents request to add orders to an entry's party token
+type LinkOrdersRequest struct {
+	OrderIDs []string `json:"order_ids" binding:"required,min=1,dive,required"`
+}
+
+// UpdateLinkedOrderStatusRequest represents request to update one order of a party token
+type UpdateLinkedOrderStatusRequest struct {
+	Status string  `json:"status" binding:"required,stage_status"`
+	Reason *string `json:"reason"`
 }
 
 // UpdateQueueStatusRequest represents request to update queue status
 type UpdateQueueStatusRequest struct {
-	Status          string  `json:"status" binding:"required"`
+	Status string `json:"status" binding:"required,queue_status"`
+	// CounterID assigns the entry to a counter; AssignedCounter does the
+	// same by counter name
+	CounterID       *string `json:"counter_id"`
 	AssignedCounter *string `json:"assigned_counter"`
 	AssignedStaff   *string `json:"assigned_staff"`
 	Notes           *string `json:"notes"`
 	Reason          *string `json:"reason"`
+	// DeferIfFull keeps an entry WAITING, to start once there is room, when
+	// the kitchen is at capacity (automatic transitions; staff get an error)
+	DeferIfFull bool `json:"-"`
 }
 
 // UpdateQueuePriorityRequest represents request to update priority
 type UpdateQueuePriorityRequest struct {
-	Priority string  `json:"priority" binding:"required"`
+	Priority string  `json:"priority" binding:"required,queue_priority"`
 	Reason   *string `json:"reason"`
 }
 
+// ReorderQueueRequest represents request to put a lane's waiting entries in a
+// new order, front of the lane first
+type ReorderQueueRequest struct {
+	EntryIDs []string `json:"entry_ids" binding:"required,min=1,dive,required"`
+	Reason   *string  `json:"reason"`
+}
+
+// RequeueRequest represents request to reinstate a NO_SHOW or EXPIRED entry.
+// Placement overrides the configured requeue placement.
+type RequeueRequest struct {
+	Placement string  `json:"placement" binding:"omitempty,oneof=BACK PRIORITY"`
+	Reason    *string `json:"reason"`
+}
+
+// SkipEntryRequest represents request to bump a waiting entry down a few
+// places for a while. Unset fields use the location's configuration.
+type SkipEntryRequest struct {
+	Positions      *int    `json:"positions" binding:"omitempty,min=1"`
+	TimeoutMinutes *int    `json:"timeout_minutes" binding:"omitempty,min=1"`
+	Reason         *string `json:"reason"`
+}
+
 // AssignStaffRequest represents request to assign staff
 type AssignStaffRequest struct {
 	StaffID   string  `json:"staff_id" binding:"required"`
 	StaffName string  `json:"staff_name"`
-	Counter   *string `json:"counter"`
+	CounterID *string `json:"counter_id"`
+	// Counter is the counter name, for callers that don't know its ID
+	Counter *string `json:"counter"`
+}
+
+// SetDepthLimitRequest represents request to cap waiting entries in a lane and priority
+type SetDepthLimitRequest struct {
+	Lane       string `json:"lane" binding:"omitempty,oneof=ANY REGULAR EXPRESS"`
+	Priority   string `json:"priority" binding:"omitempty,oneof=ANY NORMAL HIGH URGENT VIP"`
+	MaxWaiting *int   `json:"max_waiting" binding:"required,min=0"`
+}
+
+// RenotifyRequest represents request to re-send notifications in bulk
+type RenotifyRequest struct {
+	Statuses        []string `json:"statuses" binding:"omitempty,dive,queue_status"`
+	BatchSize       int      `json:"batch_size"`
+	BatchIntervalMs int      `json:"batch_interval_ms"`
+}
+
+// QueueEntryExportFilter selects the entries of an export. Empty fields
+// don't filter.
+type QueueEntryExportFilter struct {
+	// Entries created from From up to, not including, To
+	From      *time.Time
+	To        *time.Time
+	Statuses  []string
+	StaffID   string
+	CounterID string
+}
+
+// RenotifyResponse summarizes a scheduled bulk re-notification
+type RenotifyResponse struct {
+	Matched         int      `json:"matched"`
+	Batches         int      `json:"batches"`
+	BatchSize       int      `json:"batch_size"`
+	BatchIntervalMs int      `json:"batch_interval_ms"`
+	Statuses        []string `json:"statuses"`
+}
+
+// ResetQueueRequest represents request to close out a location's queue.
+// Without a ConfirmationToken matching the current queue nothing changes;
+// the response previews the reset and carries the token.
+type ResetQueueRequest struct {
+	ConfirmationToken string `json:"confirmation_token"`
+	// COMPLETED or EXPIRED (the default)
+	Outcome           string `json:"outcome" binding:"omitempty,oneof=COMPLETED EXPIRED"`
+	ResetTokenCounter bool   `json:"reset_token_counter"`
+	// New token prefix, needed when today's numbers under the current one are taken
+	TokenPrefix *string `json:"token_prefix" binding:"omitempty,min=1,max=3,alpha"`
+	Reason      *string `json:"reason"`
+}
+
+// QueueResetPreview lists what a reset would change
+type QueueResetPreview struct {
+	LocationID        string         `json:"location_id"`
+	Outcome           string         `json:"outcome"`
+	ActiveEntries     int            `json:"active_entries"`
+	ByStatus          map[string]int `json:"by_status"`
+	ResetTokenCounter bool           `json:"reset_token_counter"`
+	TokenPrefix       string         `json:"token_prefix"`
+	ConfirmationToken string         `json:"confirmation_token"`
+}
+
+// QueueResetResult summarizes a reset
+type QueueResetResult struct {
+	LocationID        string         `json:"location_id"`
+	Outcome           string         `json:"outcome"`
+	EntriesReset      int            `json:"entries_reset"`
+	ByStatus          map[string]int `json:"by_status"`
+	TokenCounterReset bool           `json:"token_counter_reset"`
+	TokenPrefix       string         `json:"token_prefix"`
+}
+
+// RemoveQueueEntryRequest represents request to delete or anonymize an entry
+type RemoveQueueEntryRequest struct {
+	Reason *string `json:"reason"`
+}
+
+// CounterReassignment describes one entry moved off a closed counter
+type CounterReassignment struct {
+	QueueEntryID     string  `json:"queue_entry_id"`
+	TokenNumber      string  `json:"token_number"`
+	FromCounter      string  `json:"from_counter"`
+	ToCounter        *string `json:"to_counter"`
+	OldWaitTime      int     `json:"old_wait_time"`
+	NewWaitTime      int     `json:"new_wait_time"`
+	CustomerNotified bool    `json:"customer_notified"`
+}
+
+// CounterStatusResponse represents a counter and its current load
+type CounterStatusResponse struct {
+	QueueCounter
+	ActiveEntries int `json:"active_entries"`
+	// NowServing is the token number of the serving entry
+	NowServing *string `json:"now_serving,omitempty"`
+}
+
+// CounterRequest represents request to create or rename a counter
+type CounterRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+// ClockInRequest represents request to start a shift, optionally at a counter
+type ClockInRequest struct {
+	CounterID *string `json:"counter_id"`
+}
+
+// CreateLocationRequest represents request to add a location
+type CreateLocationRequest struct {
+	ID          string `json:"id" binding:"required,max=36"`
+	Name        string `json:"name" binding:"required,max=100"`
+	TokenPrefix string `json:"token_prefix" binding:"required,min=1,max=3,alpha"`
+}
+
+// UpdateLocationRequest represents request to change a location
+type UpdateLocationRequest struct {
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	TokenPrefix *string `json:"token_prefix" binding:"omitempty,min=1,max=3,alpha"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// NowServing is the token being served at a counter, for the display
+type NowServing struct {
+	CounterID    string    `json:"counter_id"`
+	Counter      string    `json:"counter"`
+	QueueEntryID string    `json:"queue_entry_id"`
+	TokenNumber  string    `json:"token_number"`
+	Status       string    `json:"status"`
+	Since        time.Time `json:"since"`
+}
+
+// CloseCounterResponse summarizes a counter closure and the resulting rebalance
+type CloseCounterResponse struct {
+	Counter       *QueueCounter         `json:"counter"`
+	Reassignments []CounterReassignment `json:"reassignments"`
+}
+
+// BatchSuggestion describes a run of adjacent entries sharing the same item
+type BatchSuggestion struct {
+	MenuItemID    string   `json:"menu_item_id"`
+	ItemName      *string  `json:"item_name,omitempty"`
+	TotalQuantity int      `json:"total_quantity"`
+	EntryIDs      []string `json:"entry_ids"`
+	TokenNumbers  []string `json:"token_numbers"`
+	StartPosition int      `json:"start_position"`
+	EndPosition   int      `json:"end_position"`
+}
+
+// BatchSuggestionsResponse represents the batch suggestions shown on the KDS
+type BatchSuggestionsResponse struct {
+	LocationID  string            `json:"location_id"`
+	Suggestions []BatchSuggestion `json:"suggestions"`
+	GeneratedAt time.Time         `json:"generated_at"`
+}
+
+// DeadLetterListResponse represents a page of dead letter messages
+type DeadLetterListResponse struct {
+	Messages []DeadLetterMessage `json:"messages"`
+	Total    int64               `json:"total"`
+	Limit    int                 `json:"limit"`
+	Offset   int                 `json:"offset"`
+}
+
+// IntegrityIssue is one class of inconsistent rows found by the integrity check
+type IntegrityIssue struct {
+	Check       string   `json:"check"`
+	Description string   `json:"description"`
+	Count       int      `json:"count"`
+	SampleIDs   []string `json:"sample_ids"`
+	Repaired    int      `json:"repaired"`
+}
+
+// IntegrityReport summarizes an integrity check run
+type IntegrityReport struct {
+	CheckedAt  time.Time        `json:"checked_at"`
+	Repair     bool             `json:"repair"`
+	Consistent bool             `json:"consistent"`
+	DurationMs int64            `json:"duration_ms"`
+	Issues     []IntegrityIssue `json:"issues"`
+}
+
+// ConsumerStatus describes whether order event consumption is paused
+type ConsumerStatus struct {
+	Paused   bool       `json:"paused"`
+	PausedAt *time.Time `json:"paused_at,omitempty"`
+	PausedBy *string    `json:"paused_by,omitempty"`
+	Topics   []string   `json:"topics"`
+}
+
+// AdvanceClockRequest represents request to move simulated time forward
+type AdvanceClockRequest struct {
+	Duration string `json:"duration" binding:"required"`
+}
+
+// ClockStatus reports the service time and whether it is simulated
+type ClockStatus struct {
+	Now       time.Time `json:"now"`
+	Simulated bool      `json:"simulated"`
+	// Timezone is the restaurant's time zone, which business days follow
+	Timezone string `json:"timezone"`
+}
+
+// ReplayEventsRequest represents request to replay a Kafka offset range
+type ReplayEventsRequest struct {
+	Topic       string `json:"topic" binding:"required"`
+	Partition   int32  `json:"partition" binding:"min=0"`
+	StartOffset int64  `json:"start_offset" binding:"min=0"`
+	EndOffset   *int64 `json:"end_offset"`
+	Limit       int    `json:"limit"`
+	Apply       bool   `json:"apply"`
+}
+
+// ReplayedMessage is the outcome of replaying one message
+type ReplayedMessage struct {
+	Offset int64   `json:"offset"`
+	Key    *string `json:"key,omitempty"`
+	Action string  `json:"action"`
+	Error  string  `json:"error,omitempty"`
+}
+
+// ReplayReport summarizes an event replay; in dry-run mode Action describes
+// what applying would do
+type ReplayReport struct {
+	Topic       string            `json:"topic"`
+	Partition   int32             `json:"partition"`
+	StartOffset int64             `json:"start_offset"`
+	EndOffset   int64             `json:"end_offset"`
+	Apply       bool              `json:"apply"`
+	Processed   int               `json:"processed"`
+	Failed      int               `json:"failed"`
+	Messages    []ReplayedMessage `json:"messages"`
 }
 
 // QueuePositionResponse represents queue position info
@@ -46,16 +363,137 @@ type QueuePositionResponse struct {
 	PeopleAhead       int         `json:"people_ahead"`
 }
 
+// QueueStagesResponse represents the stages of a staged entry
+type QueueStagesResponse struct {
+	QueueEntryID    string            `json:"queue_entry_id"`
+	TokenNumber     string            `json:"token_number"`
+	Stages          []QueueEntryStage `json:"stages"`
+	ReadyStages     int               `json:"ready_stages"`
+	RemainingStages int               `json:"remaining_stages"`
+}
+
+// LinkedOrdersResponse represents the progress of each order of a party token
+type LinkedOrdersResponse struct {
+	QueueEntryID    string            `json:"queue_entry_id"`
+	TokenNumber     string            `json:"token_number"`
+	Status          string            `json:"status"`
+	Orders          []QueueEntryOrder `json:"orders"`
+	ReadyOrders     int               `json:"ready_orders"`
+	RemainingOrders int               `json:"remaining_orders"`
+}
+
+// QueueFairnessExplanation explains to a customer why they are at their position
+type QueueFairnessExplanation struct {
+	TokenNumber      string                `json:"token_number"`
+	Status           string                `json:"status"`
+	Priority         string                `json:"priority"`
+	IsExpressQueue   bool                  `json:"is_express_queue"`
+	Position         int                   `json:"position"`
+	PeopleAhead      int                   `json:"people_ahead"`
+	AheadByPriority  map[string]int        `json:"ahead_by_priority"`
+	ExpressAhead     int                   `json:"express_ahead"`
+	JoinedLaterAhead int                   `json:"joined_later_ahead"`
+	Summary          string                `json:"summary"`
+	Reasons          []string              `json:"reasons"`
+	Rules            []string              `json:"rules"`
+	History          []PositionChangeEntry `json:"history"`
+}
+
+// PositionChangeEntry is one customer-visible change of queue position
+type PositionChangeEntry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	OldPosition int       `json:"old_position"`
+	NewPosition int       `json:"new_position"`
+	Explanation string    `json:"explanation"`
+}
+
+// PositionHistoryResponse is the full position history of an entry, oldest first
+type PositionHistoryResponse struct {
+	QueueEntryID string                 `json:"queue_entry_id"`
+	TokenNumber  string                 `json:"token_number"`
+	History      []QueuePositionHistory `json:"history"`
+}
+
+// CustomerPositionHistoryResponse is how a token's place in line and ETA
+// changed over time, without staff notes
+type CustomerPositionHistoryResponse struct {
+	TokenNumber        string                   `json:"token_number"`
+	Status             string                   `json:"status"`
+	Position           int                      `json:"position"`
+	EstimatedWaitTime  int                      `json:"estimated_wait_time"`
+	EstimatedReadyTime *time.Time               `json:"estimated_ready_time,omitempty"`
+	History            []CustomerPositionChange `json:"history"`
+}
+
+// CustomerPositionChange is one customer-visible point of a token's history
+type CustomerPositionChange struct {
+	Timestamp          time.Time  `json:"timestamp"`
+	Position           int        `json:"position"`
+	Status             string     `json:"status"`
+	EstimatedWaitTime  *int       `json:"estimated_wait_time,omitempty"`
+	EstimatedReadyTime *time.Time `json:"estimated_ready_time,omitempty"`
+	Description        string     `json:"description"`
+}
+
 // CurrentQueueResponse represents current queue state
 type CurrentQueueResponse struct {
-	Waiting     []QueueEntry `json:"waiting"`
-	InProgress  []QueueEntry `json:"in_progress"`
-	Ready       []QueueEntry `json:"ready"`
-	TotalActive int          `json:"total_active"`
+	// Waiting is the regular lane; the express lane has its own positions
+	Waiting        []QueueEntry `json:"waiting"`
+	ExpressWaiting []QueueEntry `json:"express_waiting"`
+	InProgress     []QueueEntry `json:"in_progress"`
+	Ready          []QueueEntry `json:"ready"`
+	TotalActive    int          `json:"total_active"`
+	NowServing     []NowServing `json:"now_serving"`
+}
+
+// QueueSummary counts active entries by status
+type QueueSummary struct {
+	WaitingCount    int `json:"waiting_count"`
+	InProgressCount int `json:"in_progress_count"`
+	ReadyCount      int `json:"ready_count"`
+	TotalActive     int `json:"total_active"`
+}
+
+// AtRiskEntry is an active entry that is overdue or projected to exceed the wait alert
+type AtRiskEntry struct {
+	QueueEntryID       string     `json:"queue_entry_id"`
+	TokenNumber        string     `json:"token_number"`
+	Status             string     `json:"status"`
+	Priority           string     `json:"priority"`
+	IsExpressQueue     bool       `json:"is_express_queue"`
+	Position           int        `json:"position"`
+	WaitedMinutes      int        `json:"waited_minutes"`
+	ProjectedMinutes   int        `json:"projected_minutes"`
+	EstimatedReadyTime *time.Time `json:"estimated_ready_time,omitempty"`
+	Reason             string     `json:"reason"`
+}
+
+// SLABreach is an active entry that has waited longer than the SLA of its priority
+type SLABreach struct {
+	QueueEntryID   string    `json:"queue_entry_id"`
+	TokenNumber    string    `json:"token_number"`
+	Status         string    `json:"status"`
+	Priority       string    `json:"priority"`
+	IsExpressQueue bool      `json:"is_express_queue"`
+	Position       int       `json:"position"`
+	WaitedMinutes  int       `json:"waited_minutes"`
+	SLAMinutes     int       `json:"sla_minutes"`
+	BreachedAt     time.Time `json:"breached_at"`
+}
+
+// DashboardResponse is everything the staff home screen shows, in one call
+type DashboardResponse struct {
+	Queue         QueueSummary               `json:"queue"`
+	AtRisk        []AtRiskEntry              `json:"at_risk"`
+	Counters      []CounterStatusResponse    `json:"counters"`
+	Stats         *QueueStatsResponse        `json:"stats"`
+	Announcements []QueueDisplayAnnouncement `json:"announcements"`
+	GeneratedAt   time.Time                  `json:"generated_at"`
 }
 
 // QueueStatsResponse represents queue statistics
 type QueueStatsResponse struct {
+	LocationID           string  `json:"location_id"`
 	Date                 string  `json:"date"`
 	TotalInQueue         int     `json:"total_in_queue"`
 	WaitingCount         int     `json:"waiting_count"`
@@ -63,15 +501,176 @@ type QueueStatsResponse struct {
 	ReadyCount           int     `json:"ready_count"`
 	CompletedToday       int     `json:"completed_today"`
 	CancelledToday       int     `json:"cancelled_today"`
+	WalkInsToday         int     `json:"walk_ins_today"`
 	AvgWaitTime          int     `json:"avg_wait_time"`
 	AvgPreparationTime   int     `json:"avg_preparation_time"`
+	TimePercentiles
+	LongestWaitTime      int     `json:"longest_wait_time"`
+	ShortestWaitTime     int     `json:"shortest_wait_time"`
 	CurrentLoad          float64 `json:"current_load"`
 	OnTimeCompletionRate float64 `json:"on_time_completion_rate"`
+	NoShowRate           float64 `json:"no_show_rate"`
+
+	CustomKPIs []CustomKPIValue `json:"custom_kpis,omitempty"`
+}
+
+// KitchenLoadResponse represents how much of a location's kitchen capacity is in use
+type KitchenLoadResponse struct {
+	LocationID          string  `json:"location_id"`
+	InProgress          int     `json:"in_progress"`
+	MaxConcurrentOrders int     `json:"max_concurrent_orders"`
+	Available           int     `json:"available"`
+	Deferred            int     `json:"deferred"`
+	CurrentLoad         float64 `json:"current_load"`
+	AtCapacity          bool    `json:"at_capacity"`
+}
+
+// LoadCurveResponse is a location's queue length and kitchen load through a
+// day, with the day's peaks
+type LoadCurveResponse struct {
+	LocationID          string            `json:"location_id"`
+	Date                string            `json:"date"`
+	PeakLoad            float64           `json:"peak_load"`
+	PeakLoadTime        *string           `json:"peak_load_time,omitempty"`
+	PeakQueueLength     int               `json:"peak_queue_length"`
+	PeakQueueLengthTime *string           `json:"peak_queue_length_time,omitempty"`
+	Samples             []QueueLoadSample `json:"samples"`
+}
+
+// CreateKPIDefinitionRequest represents request to define a custom KPI
+type CreateKPIDefinitionRequest struct {
+	Name        string  `json:"name" binding:"required,max=100"`
+	Expression  string  `json:"expression" binding:"required,max=500"`
+	Description *string `json:"description"`
+}
+
+// UpdateKPIDefinitionRequest represents request to change a custom KPI
+type UpdateKPIDefinitionRequest struct {
+	Expression  *string `json:"expression" binding:"omitempty,max=500"`
+	Description *string `json:"description"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// CustomKPIValue is one evaluated custom KPI; Value is null when undefined (e.g. divide by zero)
+type CustomKPIValue struct {
+	Name       string   `json:"name"`
+	Expression string   `json:"expression"`
+	Value      *float64 `json:"value"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// KPIReportResponse represents the daily counters and custom KPIs
+type KPIReportResponse struct {
+	Date     string             `json:"date"`
+	Counters map[string]float64 `json:"counters"`
+	KPIs     []CustomKPIValue   `json:"kpis"`
+}
+
+// LocationKPIs represents key performance indicators for one location
+type LocationKPIs struct {
+	LocationID         string  `json:"location_id"`
+	TotalEntries       int     `json:"total_entries"`
+	CompletedCount     int     `json:"completed_count"`
+	CancelledCount     int     `json:"cancelled_count"`
+	NoShowCount        int     `json:"no_show_count"`
+	AvgWaitTime        int     `json:"avg_wait_time"`
+	AvgPreparationTime int     `json:"avg_preparation_time"`
+	ThroughputPerHour  float64 `json:"throughput_per_hour"`
+	SLAComplianceRate  float64 `json:"sla_compliance_rate"`
+	NoShowRate         float64 `json:"no_show_rate"`
+}
+
+// LocationComparisonResponse represents side-by-side KPIs for several locations
+type LocationComparisonResponse struct {
+	Date      string         `json:"date"`
+	Locations []LocationKPIs `json:"locations"`
+}
+
+// UpdateNotificationPreferenceRequest represents request to change notification channels.
+// Omitted fields keep their current value.
+type UpdateNotificationPreferenceRequest struct {
+	Email        *string `json:"email" binding:"omitempty,email"`
+	SMSEnabled   *bool   `json:"sms_enabled"`
+	EmailEnabled *bool   `json:"email_enabled"`
+	// Language of notifications (en, es, hi); empty clears it
+	Language *string `json:"language"`
+}
+
+// RegisterDeviceTokenRequest represents request to register a device for push notifications
+type RegisterDeviceTokenRequest struct {
+	Token    string `json:"token" binding:"required,max=512"`
+	Platform string `json:"platform" binding:"omitempty,oneof=android ios web"`
+}
+
+// CreateWebhookRequest represents request to subscribe a URL to queue events
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=512"`
+	EventTypes  []string `json:"event_types" binding:"required,min=1,dive,oneof=queue.entry.created queue.status.changed"`
+	Description *string  `json:"description"`
+}
+
+// UpdateWebhookRequest represents request to change a webhook subscription
+type UpdateWebhookRequest struct {
+	URL         *string  `json:"url" binding:"omitempty,url,max=512"`
+	EventTypes  []string `json:"event_types" binding:"omitempty,min=1,dive,oneof=queue.entry.created queue.status.changed"`
+	Description *string  `json:"description"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// WebhookCreatedResponse includes the signing secret, which is only shown once
+type WebhookCreatedResponse struct {
+	WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveryListResponse represents a page of webhook deliveries
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+	Total      int64             `json:"total"`
+	Limit      int               `json:"limit"`
+	Offset     int               `json:"offset"`
+}
+
+// AuditLogFilter selects audit log entries. Empty fields don't filter.
+type AuditLogFilter struct {
+	ActorID string
+	Method  string
+	Route   string
+	// Only failed (status 400 and up) or only successful requests
+	Failed *bool
+	// Entries recorded from From up to, not including, To
+	From *time.Time
+	To   *time.Time
+}
+
+// AuditLogListResponse represents a page of audit log entries, newest first
+type AuditLogListResponse struct {
+	Entries []AuditLogEntry `json:"entries"`
+	Total   int64           `json:"total"`
+	Limit   int             `json:"limit"`
+	Offset  int             `json:"offset"`
+}
+
+// WebhookEvent is the JSON body POSTed to webhook subscribers
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookStatusChange is the data of a queue.status.changed event
+type WebhookStatusChange struct {
+	PreviousStatus string      `json:"previous_status"`
+	Status         string      `json:"status"`
+	Entry          *QueueEntry `json:"entry"`
 }
 
-// ErrorResponse represents an error response
+// ErrorResponse represents an error response. Code is stable across
+// releases and wording changes, for clients to branch on.
 type ErrorResponse struct {
 	Error   string `json:"error"`
+	Code    string `json:"code,omitempty"`
 	Message string `json:"message,omitempty"`
 }
 
@@ -0,0 +1,19 @@
+package models
+
+// Allowed values of the enum fields, matching the check constraints of their
+// columns
+var (
+	QueueStatuses   = []string{"PENDING_PAYMENT", "SCHEDULED", "WAITING", "IN_PROGRESS", "READY", "COMPLETED", "CANCELLED", "NO_SHOW", "EXPIRED"}
+	QueuePriorities = []string{"LOW", "NORMAL", "HIGH", "URGENT", "VIP"}
+	TokenTypes      = []string{"REGULAR", "EXPRESS", "BULK", "SPECIAL", "STAFF", TokenTypeWalkIn}
+	StageStatuses   = []string{"WAITING", "IN_PROGRESS", "READY", "COMPLETED", "CANCELLED"}
+)
+
+// EnumValidators maps the binding tags of enum request fields to their
+// allowed values, e.g. `binding:"required,queue_status"`
+var EnumValidators = map[string][]string{
+	"queue_status":   QueueStatuses,
+	"queue_priority": QueuePriorities,
+	"token_type":     TokenTypes,
+	"stage_status":   StageStatuses,
+}
@@ -2,26 +2,43 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
+// DefaultLocationID is used for entries that don't specify a location
+const DefaultLocationID = "default"
+
 // QueueEntry represents a queue entry in the system
 type QueueEntry struct {
 	ID                        string     `gorm:"column:id;primaryKey" json:"id"`
 	OrderID                   string     `gorm:"column:order_id;uniqueIndex;not null" json:"order_id"`
+	LocationID                string     `gorm:"column:location_id;index;default:'default'" json:"location_id"`
 	UserID                    string     `gorm:"column:user_id;index;not null" json:"user_id"`
 	UserName                  *string    `gorm:"column:user_name" json:"user_name,omitempty"`
 	UserPhone                 *string    `gorm:"column:user_phone" json:"user_phone,omitempty"`
 	TokenNumber               string     `gorm:"column:token_number;uniqueIndex;not null" json:"token_number"`
-	TokenType                 string     `gorm:"column:token_type;type:ENUM('REGULAR','EXPRESS','BULK','SPECIAL','STAFF');default:'REGULAR'" json:"token_type"`
-	Status                    string     `gorm:"column:status;type:ENUM('WAITING','IN_PROGRESS','READY','COMPLETED','CANCELLED','NO_SHOW','EXPIRED');default:'WAITING';index" json:"status"`
-	Priority                  string     `gorm:"column:priority;type:ENUM('LOW','NORMAL','HIGH','URGENT','VIP');default:'NORMAL';index" json:"priority"`
+	TokenType                 string     `gorm:"column:token_type;type:varchar(16);check:token_type IN ('REGULAR','EXPRESS','BULK','SPECIAL','STAFF','WALK_IN');default:'REGULAR'" json:"token_type"`
+	Status                    string     `gorm:"column:status;type:varchar(16);check:status IN ('PENDING_PAYMENT','SCHEDULED','WAITING','IN_PROGRESS','READY','COMPLETED','CANCELLED','NO_SHOW','EXPIRED');default:'WAITING';index" json:"status"`
+	Priority                  string     `gorm:"column:priority;type:varchar(16);check:priority IN ('LOW','NORMAL','HIGH','URGENT','VIP');default:'NORMAL';index" json:"priority"`
 	Position                  int        `gorm:"column:position;not null;index" json:"position"`
+	ItemCount                 int        `gorm:"column:item_count;default:0" json:"item_count"`
 	EstimatedWaitTime         int        `gorm:"column:estimated_wait_time;default:0" json:"estimated_wait_time"`
 	EstimatedReadyTime        *time.Time `gorm:"column:estimated_ready_time;index" json:"estimated_ready_time,omitempty"`
+	RequestedPickupTime       *time.Time `gorm:"column:requested_pickup_time" json:"requested_pickup_time,omitempty"`
+	ScheduledActivateAt       *time.Time `gorm:"column:scheduled_activate_at" json:"scheduled_activate_at,omitempty"`
 	ActualStartTime           *time.Time `gorm:"column:actual_start_time" json:"actual_start_time,omitempty"`
 	ActualReadyTime           *time.Time `gorm:"column:actual_ready_time" json:"actual_ready_time,omitempty"`
 	ActualCompletionTime      *time.Time `gorm:"column:actual_completion_time" json:"actual_completion_time,omitempty"`
+	StartDeferredAt           *time.Time `gorm:"column:start_deferred_at" json:"start_deferred_at,omitempty"`
+	SkippedAt                 *time.Time `gorm:"column:skipped_at" json:"skipped_at,omitempty"`
+	SkippedPositions          int        `gorm:"column:skipped_positions;default:0" json:"skipped_positions,omitempty"`
+	SkipRestoreAt             *time.Time `gorm:"column:skip_restore_at;index" json:"skip_restore_at,omitempty"`
+	RecallCount               int        `gorm:"column:recall_count;default:0" json:"recall_count"`
+	LastRecalledAt            *time.Time `gorm:"column:last_recalled_at" json:"last_recalled_at,omitempty"`
+	SLABreachedAt             *time.Time `gorm:"column:sla_breached_at;index" json:"sla_breached_at,omitempty"`
 	AssignedCounter           *string    `gorm:"column:assigned_counter;index" json:"assigned_counter,omitempty"`
+	CounterID                 *string    `gorm:"column:counter_id;index" json:"counter_id,omitempty"`
 	AssignedStaff             *string    `gorm:"column:assigned_staff;index" json:"assigned_staff,omitempty"`
 	AssignedStaffName         *string    `gorm:"column:assigned_staff_name" json:"assigned_staff_name,omitempty"`
 	AverageItemPreparationTime *int      `gorm:"column:average_item_preparation_time" json:"average_item_preparation_time,omitempty"`
@@ -30,6 +47,11 @@ type QueueEntry struct {
 	Notes                     *string    `gorm:"column:notes" json:"notes,omitempty"`
 	CreatedAt                 time.Time  `gorm:"column:created_at;index" json:"created_at"`
 	UpdatedAt                 time.Time  `gorm:"column:updated_at" json:"updated_at"`
+	DeletedAt                 gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at,omitempty"`
+
+	// LookupCode is the signed code the customer looks the entry up with;
+	// only set in responses to the customer (see services.LookupCode)
+	LookupCode string `gorm:"-" json:"lookup_code,omitempty"`
 }
 
 func (QueueEntry) TableName() string {
@@ -40,15 +62,86 @@ func (QueueEntry) TableName() string {
 type QueueNotificationSent struct {
 	ID               string    `gorm:"column:id;primaryKey" json:"id"`
 	QueueEntryID     string    `gorm:"column:queue_entry_id;index;not null" json:"queue_entry_id"`
-	NotificationType string    `gorm:"column:notification_type;type:ENUM('ORDER_CONFIRMED','POSITION_UPDATE','ALMOST_READY','READY','REMINDER');not null;index" json:"notification_type"`
-	Channel          string    `gorm:"column:channel;type:ENUM('PUSH','IN_APP','SMS','EMAIL');not null" json:"channel"`
+	NotificationType string    `gorm:"column:notification_type;type:varchar(16);check:notification_type IN ('ORDER_CONFIRMED','POSITION_UPDATE','ALMOST_READY','READY','REMINDER');not null;index" json:"notification_type"`
+	Channel          string    `gorm:"column:channel;type:varchar(16);check:channel IN ('PUSH','IN_APP','SMS','EMAIL');not null" json:"channel"`
 	SentAt           time.Time `gorm:"column:sent_at;index" json:"sent_at"`
+
+	// Provider delivery tracking for direct channels (SMS, email, push)
+	ProviderMessageID *string    `gorm:"column:provider_message_id;index" json:"provider_message_id,omitempty"`
+	DeliveryStatus    *string    `gorm:"column:delivery_status" json:"delivery_status,omitempty"`
+	DeliveryError     *string    `gorm:"column:delivery_error" json:"delivery_error,omitempty"`
+	StatusUpdatedAt   *time.Time `gorm:"column:status_updated_at" json:"status_updated_at,omitempty"`
 }
 
 func (QueueNotificationSent) TableName() string {
 	return "queue_notifications_sent"
 }
 
+// NotificationPreference holds a customer's choice of notification channels
+type NotificationPreference struct {
+	UserID       string    `gorm:"column:user_id;primaryKey" json:"user_id"`
+	Email        *string   `gorm:"column:email" json:"email,omitempty"`
+	SMSEnabled   bool      `gorm:"column:sms_enabled;default:true" json:"sms_enabled"`
+	EmailEnabled bool      `gorm:"column:email_enabled;default:false" json:"email_enabled"`
+	Language     *string   `gorm:"column:language" json:"language,omitempty"`
+	UpdatedAt    time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (NotificationPreference) TableName() string {
+	return "queue_notification_preferences"
+}
+
+// DeviceToken is a mobile app installation registered for push notifications
+type DeviceToken struct {
+	ID        string    `gorm:"column:id;primaryKey" json:"id"`
+	UserID    string    `gorm:"column:user_id;index;not null" json:"user_id"`
+	Token     string    `gorm:"column:token;uniqueIndex;not null" json:"token"`
+	Platform  *string   `gorm:"column:platform" json:"platform,omitempty"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (DeviceToken) TableName() string {
+	return "queue_device_tokens"
+}
+
+// WebhookSubscription is a third-party endpoint that receives queue events
+type WebhookSubscription struct {
+	ID          string    `gorm:"column:id;primaryKey" json:"id"`
+	URL         string    `gorm:"column:url;not null" json:"url"`
+	EventTypes  []string  `gorm:"column:event_types;serializer:json;type:JSON;not null" json:"event_types"`
+	Secret      string    `gorm:"column:secret;not null" json:"-"`
+	Description *string   `gorm:"column:description" json:"description,omitempty"`
+	IsActive    bool      `gorm:"column:is_active;default:true;index" json:"is_active"`
+	CreatedBy   *string   `gorm:"column:created_by" json:"created_by,omitempty"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "queue_webhook_subscriptions"
+}
+
+// WebhookDelivery is one event sent (or to be sent) to one subscription
+type WebhookDelivery struct {
+	ID             string     `gorm:"column:id;primaryKey" json:"id"`
+	SubscriptionID string     `gorm:"column:subscription_id;index;not null" json:"subscription_id"`
+	EventID        string     `gorm:"column:event_id;not null" json:"event_id"`
+	EventType      string     `gorm:"column:event_type;not null" json:"event_type"`
+	Payload        string     `gorm:"column:payload;size:16777215;not null" json:"payload"`
+	Status         string     `gorm:"column:status;type:varchar(16);check:status IN ('PENDING','SUCCEEDED','FAILED');default:'PENDING';index" json:"status"`
+	Attempts       int        `gorm:"column:attempts;default:0" json:"attempts"`
+	ResponseStatus *int       `gorm:"column:response_status" json:"response_status,omitempty"`
+	LastError      *string    `gorm:"column:last_error;type:TEXT" json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `gorm:"column:next_attempt_at;index" json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at" json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"column:created_at;index" json:"created_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "queue_webhook_deliveries"
+}
+
 // QueuePositionHistory tracks position changes
 type QueuePositionHistory struct {
 	ID                  string     `gorm:"column:id;primaryKey" json:"id"`
@@ -67,32 +160,72 @@ func (QueuePositionHistory) TableName() string {
 	return "queue_position_history"
 }
 
+// QueueLocation is an outlet whose queue runs in this deployment
+type QueueLocation struct {
+	ID          string    `gorm:"column:id;primaryKey" json:"id"`
+	Name        string    `gorm:"column:name;not null" json:"name"`
+	TokenPrefix string    `gorm:"column:token_prefix;uniqueIndex;not null" json:"token_prefix"`
+	IsActive    bool      `gorm:"column:is_active;default:true;index" json:"is_active"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (QueueLocation) TableName() string {
+	return "queue_locations"
+}
+
 // QueueConfiguration holds queue settings
 type QueueConfiguration struct {
 	ID                              string    `gorm:"column:id;primaryKey" json:"id"`
+	LocationID                      string    `gorm:"column:location_id;uniqueIndex;default:'default'" json:"location_id"`
 	MaxConcurrentOrders             int       `gorm:"column:max_concurrent_orders;default:10" json:"max_concurrent_orders"`
 	AvgPreparationTimePerItem       int       `gorm:"column:avg_preparation_time_per_item;default:5" json:"avg_preparation_time_per_item"`
+	AdaptivePrepTimeEnabled         bool       `gorm:"column:adaptive_prep_time_enabled;default:false" json:"adaptive_prep_time_enabled"`
+	PrepTimeSmoothing               *float64   `gorm:"column:prep_time_smoothing" json:"prep_time_smoothing,omitempty"`
+	LearnedPrepTimePerItem          *float64   `gorm:"column:learned_prep_time_per_item" json:"learned_prep_time_per_item,omitempty"`
+	PrepTimeLearnedAt               *time.Time `gorm:"column:prep_time_learned_at" json:"prep_time_learned_at,omitempty"`
 	BufferTime                      int       `gorm:"column:buffer_time;default:2" json:"buffer_time"`
 	ExpressQueueEnabled             bool      `gorm:"column:express_queue_enabled;default:false" json:"express_queue_enabled"`
 	ExpressQueueMaxItems            int       `gorm:"column:express_queue_max_items;default:3" json:"express_queue_max_items"`
+	RegularServeRatio               int       `gorm:"column:regular_serve_ratio;default:2" json:"regular_serve_ratio"`
+	ExpressServeRatio               int       `gorm:"column:express_serve_ratio;default:1" json:"express_serve_ratio"`
+	RequeuePlacement                string    `gorm:"column:requeue_placement;default:'BACK'" json:"requeue_placement"`
+	SkipPositions                   int       `gorm:"column:skip_positions;default:3" json:"skip_positions"`
+	SkipTimeoutMinutes              int       `gorm:"column:skip_timeout_minutes;default:5" json:"skip_timeout_minutes"`
 	MaxWaitTimeAlert                int       `gorm:"column:max_wait_time_alert;default:30" json:"max_wait_time_alert"`
+	SLAMinutesVIP                   *int      `gorm:"column:sla_minutes_vip" json:"sla_minutes_vip,omitempty"`
+	SLAMinutesUrgent                *int      `gorm:"column:sla_minutes_urgent" json:"sla_minutes_urgent,omitempty"`
+	SLAMinutesHigh                  *int      `gorm:"column:sla_minutes_high" json:"sla_minutes_high,omitempty"`
+	SLAMinutesNormal                *int      `gorm:"column:sla_minutes_normal" json:"sla_minutes_normal,omitempty"`
+	SLAMinutesLow                   *int      `gorm:"column:sla_minutes_low" json:"sla_minutes_low,omitempty"`
 	TokenExpiryTime                 int       `gorm:"column:token_expiry_time;default:60" json:"token_expiry_time"`
 	AutoNotificationEnabled         bool      `gorm:"column:auto_notification_enabled;default:true" json:"auto_notification_enabled"`
 	NotificationPositionThreshold   int       `gorm:"column:notification_position_threshold;default:5" json:"notification_position_threshold"`
 	NotificationAlmostReadyThreshold int      `gorm:"column:notification_almost_ready_threshold;default:2" json:"notification_almost_ready_threshold"`
 	UpdatedAt                       time.Time `gorm:"column:updated_at" json:"updated_at"`
 	UpdatedBy                       *string   `gorm:"column:updated_by" json:"updated_by,omitempty"`
+	DeletedAt                       gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at,omitempty"`
 }
 
 func (QueueConfiguration) TableName() string {
 	return "queue_configuration"
 }
 
+// TokenTypeWalkIn marks entries staff created for walk-in customers, which
+// have no upstream order
+const TokenTypeWalkIn = "WALK_IN"
+
+// Where requeued entries go in their lane
+const (
+	RequeueBack     = "BACK"
+	RequeuePriority = "PRIORITY"
+)
+
 // QueueWorkingHours defines operating hours
 type QueueWorkingHours struct {
 	ID              string `gorm:"column:id;primaryKey" json:"id"`
 	ConfigurationID string `gorm:"column:configuration_id;index;not null" json:"configuration_id"`
-	Day             string `gorm:"column:day;type:ENUM('MONDAY','TUESDAY','WEDNESDAY','THURSDAY','FRIDAY','SATURDAY','SUNDAY');not null" json:"day"`
+	Day             string `gorm:"column:day;type:varchar(16);check:day IN ('MONDAY','TUESDAY','WEDNESDAY','THURSDAY','FRIDAY','SATURDAY','SUNDAY');not null" json:"day"`
 	OpenTime        string `gorm:"column:open_time;not null" json:"open_time"`
 	CloseTime       string `gorm:"column:close_time;not null" json:"close_time"`
 	IsOpen          bool   `gorm:"column:is_open;default:true" json:"is_open"`
@@ -106,7 +239,7 @@ func (QueueWorkingHours) TableName() string {
 type QueuePriorityMultiplier struct {
 	ID              string  `gorm:"column:id;primaryKey" json:"id"`
 	ConfigurationID string  `gorm:"column:configuration_id;index;not null" json:"configuration_id"`
-	Priority        string  `gorm:"column:priority;type:ENUM('LOW','NORMAL','HIGH','URGENT','VIP');not null" json:"priority"`
+	Priority        string  `gorm:"column:priority;type:varchar(16);check:priority IN ('LOW','NORMAL','HIGH','URGENT','VIP');not null" json:"priority"`
 	Multiplier      float64 `gorm:"column:multiplier;default:1.00" json:"multiplier"`
 }
 
@@ -118,13 +251,14 @@ func (QueuePriorityMultiplier) TableName() string {
 type QueueDisplayAnnouncement struct {
 	ID           string     `gorm:"column:id;primaryKey" json:"id"`
 	Message      string     `gorm:"column:message;not null" json:"message"`
-	Type         string     `gorm:"column:type;type:ENUM('INFO','WARNING','URGENT');default:'INFO'" json:"type"`
+	Type         string     `gorm:"column:type;type:varchar(16);check:type IN ('INFO','WARNING','URGENT');default:'INFO'" json:"type"`
 	Priority     int        `gorm:"column:priority;default:0;index" json:"priority"`
 	IsActive     bool       `gorm:"column:is_active;default:true;index" json:"is_active"`
 	DisplayUntil *time.Time `gorm:"column:display_until;index" json:"display_until,omitempty"`
 	CreatedBy    *string    `gorm:"column:created_by" json:"created_by,omitempty"`
 	CreatedAt    time.Time  `gorm:"column:created_at" json:"created_at"`
 	UpdatedAt    time.Time  `gorm:"column:updated_at" json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at,omitempty"`
 }
 
 func (QueueDisplayAnnouncement) TableName() string {
@@ -137,7 +271,8 @@ type StaffQueueActionLog struct {
 	QueueEntryID    string     `gorm:"column:queue_entry_id;index;not null" json:"queue_entry_id"`
 	StaffID         string     `gorm:"column:staff_id;index;not null" json:"staff_id"`
 	StaffName       *string    `gorm:"column:staff_name" json:"staff_name,omitempty"`
-	Action          string     `gorm:"column:action;type:ENUM('START_PREPARATION','MARK_READY','MARK_COMPLETED','CANCEL','REASSIGN','ADJUST_PRIORITY','ADD_NOTE');not null;index" json:"action"`
+	ShiftID         *string    `gorm:"column:shift_id;index" json:"shift_id,omitempty"`
+	Action          string     `gorm:"column:action;type:varchar(32);check:action IN ('START_PREPARATION','MARK_READY','MARK_COMPLETED','CANCEL','REASSIGN','ADJUST_PRIORITY','ADD_NOTE','MARK_PENDING_PAYMENT','MARK_WAITING','MARK_IN_PROGRESS','MARK_CANCELLED','MARK_NO_SHOW','MARK_EXPIRED','DEFER_START','REORDER','REQUEUE','SKIP','RESTORE_SKIP','RECALL','LINK_ORDERS','RESET');not null;index" json:"action"`
 	OldStatus       *string    `gorm:"column:old_status" json:"old_status,omitempty"`
 	NewStatus       *string    `gorm:"column:new_status" json:"new_status,omitempty"`
 	OldPriority     *string    `gorm:"column:old_priority" json:"old_priority,omitempty"`
@@ -153,10 +288,28 @@ func (StaffQueueActionLog) TableName() string {
 	return "staff_queue_actions_log"
 }
 
+// StaffShift is a period a staff member is on duty at a location
+type StaffShift struct {
+	ID         string     `gorm:"column:id;primaryKey" json:"id"`
+	StaffID    string     `gorm:"column:staff_id;index;not null" json:"staff_id"`
+	StaffName  *string    `gorm:"column:staff_name" json:"staff_name,omitempty"`
+	LocationID string     `gorm:"column:location_id;index;default:'default'" json:"location_id"`
+	CounterID  *string    `gorm:"column:counter_id;index" json:"counter_id,omitempty"`
+	ClockInAt  time.Time  `gorm:"column:clock_in_at;not null" json:"clock_in_at"`
+	ClockOutAt *time.Time `gorm:"column:clock_out_at" json:"clock_out_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (StaffShift) TableName() string {
+	return "queue_staff_shifts"
+}
+
 // QueueStatistics holds daily statistics
 type QueueStatistics struct {
 	ID                    string    `gorm:"column:id;primaryKey" json:"id"`
-	Date                  time.Time `gorm:"column:date;uniqueIndex;not null" json:"date"`
+	LocationID            string    `gorm:"column:location_id;uniqueIndex:idx_location_date;default:'default'" json:"location_id"`
+	Date                  time.Time `gorm:"column:date;uniqueIndex:idx_location_date;not null" json:"date"`
 	TotalInQueue          int       `gorm:"column:total_in_queue;default:0" json:"total_in_queue"`
 	WaitingCount          int       `gorm:"column:waiting_count;default:0" json:"waiting_count"`
 	InProgressCount       int       `gorm:"column:in_progress_count;default:0" json:"in_progress_count"`
@@ -165,13 +318,17 @@ type QueueStatistics struct {
 	CancelledToday        int       `gorm:"column:cancelled_today;default:0" json:"cancelled_today"`
 	NoShowToday           int       `gorm:"column:no_show_today;default:0" json:"no_show_today"`
 	ExpiredToday          int       `gorm:"column:expired_today;default:0" json:"expired_today"`
+	WalkInsToday          int       `gorm:"column:walk_ins_today;default:0" json:"walk_ins_today"`
 	AvgWaitTime           int       `gorm:"column:avg_wait_time;default:0" json:"avg_wait_time"`
 	AvgPreparationTime    int       `gorm:"column:avg_preparation_time;default:0" json:"avg_preparation_time"`
+	TimePercentiles
 	LongestWaitTime       int       `gorm:"column:longest_wait_time;default:0" json:"longest_wait_time"`
 	ShortestWaitTime      int       `gorm:"column:shortest_wait_time;default:0" json:"shortest_wait_time"`
 	CurrentLoad           float64   `gorm:"column:current_load;default:0.00" json:"current_load"`
 	PeakLoad              float64   `gorm:"column:peak_load;default:0.00" json:"peak_load"`
 	PeakLoadTime          *string   `gorm:"column:peak_load_time" json:"peak_load_time,omitempty"`
+	PeakQueueLength       int       `gorm:"column:peak_queue_length;default:0" json:"peak_queue_length"`
+	PeakQueueLengthTime   *string   `gorm:"column:peak_queue_length_time" json:"peak_queue_length_time,omitempty"`
 	OnTimeCompletionRate  float64   `gorm:"column:on_time_completion_rate;default:0.00" json:"on_time_completion_rate"`
 	NoShowRate            float64   `gorm:"column:no_show_rate;default:0.00" json:"no_show_rate"`
 	UpdatedAt             time.Time `gorm:"column:updated_at" json:"updated_at"`
@@ -181,14 +338,28 @@ func (QueueStatistics) TableName() string {
 	return "queue_statistics"
 }
 
-// QueueHourlyStatistics holds hourly statistics
+// TimePercentiles are the p50/p90/p99 wait (joined to started) and
+// preparation (started to ready) times of entries, in minutes
+type TimePercentiles struct {
+	WaitTimeP50 int `gorm:"column:wait_time_p50;default:0" json:"wait_time_p50"`
+	WaitTimeP90 int `gorm:"column:wait_time_p90;default:0" json:"wait_time_p90"`
+	WaitTimeP99 int `gorm:"column:wait_time_p99;default:0" json:"wait_time_p99"`
+	PrepTimeP50 int `gorm:"column:prep_time_p50;default:0" json:"prep_time_p50"`
+	PrepTimeP90 int `gorm:"column:prep_time_p90;default:0" json:"prep_time_p90"`
+	PrepTimeP99 int `gorm:"column:prep_time_p99;default:0" json:"prep_time_p99"`
+}
+
+// QueueHourlyStatistics holds hourly statistics of the entries that joined
+// a location's queue in the hour
 type QueueHourlyStatistics struct {
 	ID                  string    `gorm:"column:id;primaryKey" json:"id"`
-	Date                time.Time `gorm:"column:date;not null" json:"date"`
-	Hour                int       `gorm:"column:hour;not null" json:"hour"`
+	LocationID          string    `gorm:"column:location_id;uniqueIndex:idx_location_date_hour;default:'default'" json:"location_id"`
+	Date                time.Time `gorm:"column:date;uniqueIndex:idx_location_date_hour;not null" json:"date"`
+	Hour                int       `gorm:"column:hour;uniqueIndex:idx_location_date_hour;not null" json:"hour"`
 	OrderCount          int       `gorm:"column:order_count;default:0" json:"order_count"`
 	AvgWaitTime         int       `gorm:"column:avg_wait_time;default:0" json:"avg_wait_time"`
 	AvgPreparationTime  int       `gorm:"column:avg_preparation_time;default:0" json:"avg_preparation_time"`
+	TimePercentiles
 	CompletedCount      int       `gorm:"column:completed_count;default:0" json:"completed_count"`
 	CancelledCount      int       `gorm:"column:cancelled_count;default:0" json:"cancelled_count"`
 	PeakPosition        int       `gorm:"column:peak_position;default:0" json:"peak_position"`
@@ -199,10 +370,26 @@ func (QueueHourlyStatistics) TableName() string {
 	return "queue_hourly_statistics"
 }
 
+// QueueLoadSample is a location's queue length and kitchen load at a moment
+type QueueLoadSample struct {
+	ID                  string    `gorm:"column:id;primaryKey" json:"-"`
+	LocationID          string    `gorm:"column:location_id;uniqueIndex:idx_location_sampled_at;default:'default'" json:"-"`
+	SampledAt           time.Time `gorm:"column:sampled_at;uniqueIndex:idx_location_sampled_at;not null" json:"sampled_at"`
+	WaitingCount        int       `gorm:"column:waiting_count;default:0" json:"waiting_count"`
+	InProgressCount     int       `gorm:"column:in_progress_count;default:0" json:"in_progress_count"`
+	MaxConcurrentOrders int       `gorm:"column:max_concurrent_orders;default:0" json:"max_concurrent_orders"`
+	KitchenLoad         float64   `gorm:"column:kitchen_load;default:0.00" json:"kitchen_load"`
+}
+
+func (QueueLoadSample) TableName() string {
+	return "queue_load_samples"
+}
+
 // QueueTokenCounter tracks token generation
 type QueueTokenCounter struct {
 	ID            string    `gorm:"column:id;primaryKey" json:"id"`
-	Date          time.Time `gorm:"column:date;uniqueIndex;not null" json:"date"`
+	LocationID    string    `gorm:"column:location_id;uniqueIndex:uk_token_counter_location_date;default:'default'" json:"location_id"`
+	Date          time.Time `gorm:"column:date;uniqueIndex:uk_token_counter_location_date;not null" json:"date"`
 	CurrentNumber int       `gorm:"column:current_number;default:0" json:"current_number"`
 	Prefix        string    `gorm:"column:prefix;default:'A'" json:"prefix"`
 	LastResetAt   time.Time `gorm:"column:last_reset_at" json:"last_reset_at"`
@@ -211,3 +398,208 @@ type QueueTokenCounter struct {
 func (QueueTokenCounter) TableName() string {
 	return "queue_token_counter"
 }
+
+// QueueDayRollover records a location's business day closed by the
+// end-of-day rollover. The unique key lets one instance claim each day.
+type QueueDayRollover struct {
+	ID              string    `gorm:"column:id;primaryKey" json:"id"`
+	LocationID      string    `gorm:"column:location_id;uniqueIndex:idx_location_business_date;not null" json:"location_id"`
+	BusinessDate    time.Time `gorm:"column:business_date;uniqueIndex:idx_location_business_date;not null" json:"business_date"`
+	ClosedAt        time.Time `gorm:"column:closed_at;not null" json:"closed_at"`
+	ExpiredEntries  int       `gorm:"column:expired_entries;default:0" json:"expired_entries"`
+	TokensIssued    int       `gorm:"column:tokens_issued;default:0" json:"tokens_issued"`
+	TokenPrefix     string    `gorm:"column:token_prefix;not null" json:"token_prefix"`
+	NextTokenPrefix string    `gorm:"column:next_token_prefix;not null" json:"next_token_prefix"`
+}
+
+func (QueueDayRollover) TableName() string {
+	return "queue_day_rollovers"
+}
+
+// QueueEntryStage is a staged portion of a queue entry (e.g. appetizers now, mains later)
+// that shares the parent entry's token
+type QueueEntryStage struct {
+	ID                   string     `gorm:"column:id;primaryKey" json:"id"`
+	QueueEntryID         string     `gorm:"column:queue_entry_id;index;not null" json:"queue_entry_id"`
+	TokenNumber          string     `gorm:"column:token_number;index;not null" json:"token_number"`
+	Sequence             int        `gorm:"column:sequence;not null" json:"sequence"`
+	Name                 string     `gorm:"column:name;not null" json:"name"`
+	ItemCount            int        `gorm:"column:item_count;default:0" json:"item_count"`
+	Status               string     `gorm:"column:status;type:varchar(16);check:status IN ('WAITING','IN_PROGRESS','READY','COMPLETED','CANCELLED');default:'WAITING';index" json:"status"`
+	ReadyAfterMinutes    int        `gorm:"column:ready_after_minutes;default:0" json:"ready_after_minutes"`
+	EstimatedReadyTime   *time.Time `gorm:"column:estimated_ready_time" json:"estimated_ready_time,omitempty"`
+	ActualStartTime      *time.Time `gorm:"column:actual_start_time" json:"actual_start_time,omitempty"`
+	ActualReadyTime      *time.Time `gorm:"column:actual_ready_time" json:"actual_ready_time,omitempty"`
+	ActualCompletionTime *time.Time `gorm:"column:actual_completion_time" json:"actual_completion_time,omitempty"`
+	CreatedAt            time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt            time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (QueueEntryStage) TableName() string {
+	return "queue_entry_stages"
+}
+
+// QueueEntryOrder is one of the orders sharing a party entry's token; the
+// entry becomes ready once all its orders are
+type QueueEntryOrder struct {
+	ID                   string     `gorm:"column:id;primaryKey" json:"id"`
+	QueueEntryID         string     `gorm:"column:queue_entry_id;index;not null" json:"queue_entry_id"`
+	OrderID              string     `gorm:"column:order_id;uniqueIndex;not null" json:"order_id"`
+	TokenNumber          string     `gorm:"column:token_number;index;not null" json:"token_number"`
+	Status               string     `gorm:"column:status;type:varchar(16);check:status IN ('WAITING','IN_PROGRESS','READY','COMPLETED','CANCELLED');default:'WAITING';index" json:"status"`
+	ActualStartTime      *time.Time `gorm:"column:actual_start_time" json:"actual_start_time,omitempty"`
+	ActualReadyTime      *time.Time `gorm:"column:actual_ready_time" json:"actual_ready_time,omitempty"`
+	ActualCompletionTime *time.Time `gorm:"column:actual_completion_time" json:"actual_completion_time,omitempty"`
+	CreatedAt            time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt            time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (QueueEntryOrder) TableName() string {
+	return "queue_entry_orders"
+}
+
+// QueueEntryTombstone records a deleted or anonymized entry for a grace period
+// so downstream caches and analytics consumers can purge their copies
+type QueueEntryTombstone struct {
+	ID           string    `gorm:"column:id;primaryKey" json:"id"`
+	QueueEntryID string    `gorm:"column:queue_entry_id;index;not null" json:"queue_entry_id"`
+	OrderID      string    `gorm:"column:order_id;not null" json:"order_id"`
+	TokenNumber  string    `gorm:"column:token_number;not null" json:"token_number"`
+	Action       string    `gorm:"column:action;type:varchar(16);check:action IN ('DELETED','ANONYMIZED');not null" json:"action"`
+	Reason       *string   `gorm:"column:reason" json:"reason,omitempty"`
+	PerformedBy  string    `gorm:"column:performed_by;not null" json:"performed_by"`
+	CreatedAt    time.Time `gorm:"column:created_at;index" json:"created_at"`
+}
+
+func (QueueEntryTombstone) TableName() string {
+	return "queue_entry_tombstones"
+}
+
+// QueueCounter is a pickup/service counter that entries can be assigned to.
+// ServingEntryID is the entry being prepared or handed over at the counter.
+type QueueCounter struct {
+	ID             string     `gorm:"column:id;primaryKey" json:"id"`
+	LocationID     string     `gorm:"column:location_id;uniqueIndex:idx_location_name;default:'default'" json:"location_id"`
+	Name           string     `gorm:"column:name;uniqueIndex:idx_location_name;not null" json:"name"`
+	IsOpen         bool       `gorm:"column:is_open;default:true;index" json:"is_open"`
+	ServingEntryID *string    `gorm:"column:serving_entry_id" json:"serving_entry_id,omitempty"`
+	ServingSince   *time.Time `gorm:"column:serving_since" json:"serving_since,omitempty"`
+	CreatedAt      time.Time  `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (QueueCounter) TableName() string {
+	return "queue_counters"
+}
+
+// QueueEntryItem is one line item of the order behind a queue entry
+type QueueEntryItem struct {
+	ID           string    `gorm:"column:id;primaryKey" json:"id"`
+	QueueEntryID string    `gorm:"column:queue_entry_id;index;not null" json:"queue_entry_id"`
+	MenuItemID   string    `gorm:"column:menu_item_id;index;not null" json:"menu_item_id"`
+	ItemName     *string   `gorm:"column:item_name" json:"item_name,omitempty"`
+	Quantity     int       `gorm:"column:quantity;not null;default:1" json:"quantity"`
+	CreatedAt    time.Time `gorm:"column:created_at" json:"created_at"`
+}
+
+func (QueueEntryItem) TableName() string {
+	return "queue_entry_items"
+}
+
+// KPIDefinition is an operator-defined KPI evaluated over daily counters
+type KPIDefinition struct {
+	ID          string    `gorm:"column:id;primaryKey" json:"id"`
+	Name        string    `gorm:"column:name;uniqueIndex;not null" json:"name"`
+	Expression  string    `gorm:"column:expression;not null" json:"expression"`
+	Description *string   `gorm:"column:description" json:"description,omitempty"`
+	IsActive    bool      `gorm:"column:is_active;default:true;index" json:"is_active"`
+	CreatedBy   *string   `gorm:"column:created_by" json:"created_by,omitempty"`
+	CreatedAt   time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (KPIDefinition) TableName() string {
+	return "queue_kpi_definitions"
+}
+
+// ProcessedEvent records a consumed Kafka message so re-deliveries are skipped
+type ProcessedEvent struct {
+	EventKey    string    `gorm:"column:event_key;primaryKey" json:"event_key"`
+	Topic       string    `gorm:"column:topic;not null" json:"topic"`
+	Partition   int32     `gorm:"column:partition_id;not null" json:"partition"`
+	Offset      int64     `gorm:"column:offset_id;not null" json:"offset"`
+	ProcessedAt time.Time `gorm:"column:processed_at;index" json:"processed_at"`
+}
+
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
+
+// DeadLetterMessage is a consumed Kafka message that failed processing
+type DeadLetterMessage struct {
+	ID              string     `gorm:"column:id;primaryKey" json:"id"`
+	OriginalTopic   string     `gorm:"column:original_topic;index;not null" json:"original_topic"`
+	Partition       int32      `gorm:"column:partition_id;not null" json:"partition"`
+	Offset          int64      `gorm:"column:offset_id;not null" json:"offset"`
+	MessageKey      *string    `gorm:"column:message_key" json:"message_key,omitempty"`
+	Payload         string     `gorm:"column:payload;size:16777215;not null" json:"payload"`
+	PayloadEncoding string     `gorm:"column:payload_encoding;type:varchar(8);check:payload_encoding IN ('TEXT','BASE64');default:'TEXT'" json:"payload_encoding"`
+	Error           string     `gorm:"column:error;type:TEXT;not null" json:"error"`
+	Attempts        int        `gorm:"column:attempts;default:1" json:"attempts"`
+	Status          string     `gorm:"column:status;type:varchar(16);check:status IN ('PENDING','REDRIVEN','DISCARDED');default:'PENDING';index" json:"status"`
+	RedriveCount    int        `gorm:"column:redrive_count;default:0" json:"redrive_count"`
+	LastRedrivenAt  *time.Time `gorm:"column:last_redriven_at" json:"last_redriven_at,omitempty"`
+	LastRedrivenBy  *string    `gorm:"column:last_redriven_by" json:"last_redriven_by,omitempty"`
+	FailedAt        time.Time  `gorm:"column:failed_at;index" json:"failed_at"`
+}
+
+func (DeadLetterMessage) TableName() string {
+	return "queue_dead_letter_messages"
+}
+
+// Lanes a depth limit can apply to
+const (
+	LaneAny     = "ANY"
+	LaneRegular = "REGULAR"
+	LaneExpress = "EXPRESS"
+)
+
+// QueueDepthLimit caps how many entries may wait in a lane and priority at once
+type QueueDepthLimit struct {
+	ID         string    `gorm:"column:id;primaryKey" json:"id"`
+	Lane       string    `gorm:"column:lane;type:varchar(16);uniqueIndex:uk_lane_priority;check:lane IN ('ANY','REGULAR','EXPRESS');default:'ANY';not null" json:"lane"`
+	Priority   string    `gorm:"column:priority;type:varchar(16);uniqueIndex:uk_lane_priority;check:priority IN ('ANY','NORMAL','HIGH','URGENT','VIP');default:'ANY';not null" json:"priority"`
+	MaxWaiting int       `gorm:"column:max_waiting;not null" json:"max_waiting"`
+	CreatedBy  *string   `gorm:"column:created_by" json:"created_by,omitempty"`
+	CreatedAt  time.Time `gorm:"column:created_at" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at" json:"updated_at"`
+}
+
+func (QueueDepthLimit) TableName() string {
+	return "queue_depth_limits"
+}
+
+// AuditLogEntry records one mutating staff or admin request: who made it,
+// what they asked for and how it ended
+type AuditLogEntry struct {
+	ID         string  `gorm:"column:id;primaryKey" json:"id"`
+	ActorID    string  `gorm:"column:actor_id;index;not null" json:"actor_id"`
+	ActorName  *string `gorm:"column:actor_name" json:"actor_name,omitempty"`
+	ActorRole  string  `gorm:"column:actor_role;not null" json:"actor_role"`
+	Method     string  `gorm:"column:method;type:varchar(8);not null" json:"method"`
+	Route      string  `gorm:"column:route;index;not null" json:"route"`
+	Path       string  `gorm:"column:path;not null" json:"path"`
+	LocationID string  `gorm:"column:location_id;index;default:'default'" json:"location_id"`
+	// Payload is the JSON request body with personal and secret fields redacted
+	Payload    *string   `gorm:"column:payload;type:TEXT" json:"payload,omitempty"`
+	StatusCode int       `gorm:"column:status_code;not null" json:"status_code"`
+	ErrorCode  *string   `gorm:"column:error_code" json:"error_code,omitempty"`
+	RequestID  *string   `gorm:"column:request_id" json:"request_id,omitempty"`
+	ClientIP   string    `gorm:"column:client_ip" json:"client_ip"`
+	DurationMs int64     `gorm:"column:duration_ms" json:"duration_ms"`
+	CreatedAt  time.Time `gorm:"column:created_at;index" json:"created_at"`
+}
+
+func (AuditLogEntry) TableName() string {
+	return "queue_audit_log"
+}
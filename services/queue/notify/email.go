@@ -0,0 +1,185 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"gin-quickstart/config"
+	"gin-quickstart/i18n"
+	"gin-quickstart/models"
+)
+
+// Email providers selectable with EMAIL_PROVIDER
+const (
+	EmailProviderSMTP = "smtp"
+	// EmailProviderSES sends through the Amazon SES SMTP interface
+	EmailProviderSES = "ses"
+	// EmailProviderLog only logs messages, for development
+	EmailProviderLog = "log"
+)
+
+// English templates sit in templates/, translations in templates/<language>/
+//
+//go:embed templates/*.html templates/*/*.html
+var emailTemplateFiles embed.FS
+
+// emailTypes are the notifications that have an email template
+var emailTypes = []string{TypeConfirmed, TypeReady}
+
+// EmailProvider sends an HTML email
+type EmailProvider interface {
+	SendEmail(ctx context.Context, to, subject, htmlBody string) error
+}
+
+type emailChannel struct {
+	provider EmailProvider
+	// templates maps language, then notification type, to its message
+	templates map[string]map[string]*template.Template
+}
+
+// newEmailChannel returns nil when EMAIL_PROVIDER is empty
+func newEmailChannel(cfg *config.Config) (*emailChannel, error) {
+	var provider EmailProvider
+	switch strings.ToLower(cfg.EmailProvider) {
+	case "":
+		return nil, nil
+	case EmailProviderSMTP:
+		smtpProvider, err := newSMTPProvider(cfg.SMTPHost, cfg.SMTPPort, cfg)
+		if err != nil {
+			return nil, err
+		}
+		provider = smtpProvider
+	case EmailProviderSES:
+		if cfg.SESRegion == "" {
+			return nil, fmt.Errorf("SES_REGION is required for the ses email provider")
+		}
+		// SES SMTP credentials go in SMTP_USERNAME/SMTP_PASSWORD
+		sesProvider, err := newSMTPProvider(fmt.Sprintf("email-smtp.%s.amazonaws.com", cfg.SESRegion), 587, cfg)
+		if err != nil {
+			return nil, err
+		}
+		provider = sesProvider
+	case EmailProviderLog:
+		provider = logEmailProvider{}
+	default:
+		return nil, fmt.Errorf("unsupported EMAIL_PROVIDER %q", cfg.EmailProvider)
+	}
+
+	templates := make(map[string]map[string]*template.Template)
+	for _, language := range i18n.Languages() {
+		dir := "templates/"
+		if language != i18n.SourceLanguage {
+			dir += language + "/"
+		}
+		templates[language] = make(map[string]*template.Template, len(emailTypes))
+		for _, notificationType := range emailTypes {
+			name := dir + notificationType + ".html"
+			if _, err := fs.Stat(emailTemplateFiles, name); errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			tmpl, err := template.ParseFS(emailTemplateFiles, name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid email template for %s (%s): %w", notificationType, language, err)
+			}
+			templates[language][notificationType] = tmpl
+		}
+	}
+
+	return &emailChannel{provider: provider, templates: templates}, nil
+}
+
+func (c *emailChannel) Name() string { return "EMAIL" }
+
+func (c *emailChannel) Send(ctx context.Context, notificationType string, entry *models.QueueEntry, to Recipient) (Delivery, bool) {
+	tmpl, ok := localized(c.templates, to.Language, notificationType)
+	if !ok || to.Email == "" {
+		return Delivery{}, false
+	}
+
+	delivery := Delivery{Channel: c.Name()}
+	data := newMessageData(entry, to)
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		delivery.Err = fmt.Errorf("failed to render email subject: %w", err)
+		return delivery, true
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		delivery.Err = fmt.Errorf("failed to render email body: %w", err)
+		return delivery, true
+	}
+
+	delivery.Err = c.provider.SendEmail(ctx, to.Email, strings.TrimSpace(subject.String()), body.String())
+	if delivery.Err == nil {
+		delivery.Status = "sent"
+	}
+	return delivery, true
+}
+
+// smtpProvider sends through an SMTP relay, upgrading to TLS when offered
+type smtpProvider struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+}
+
+func newSMTPProvider(host string, port int, cfg *config.Config) (*smtpProvider, error) {
+	if host == "" || cfg.EmailFrom == "" {
+		return nil, fmt.Errorf("SMTP_HOST and EMAIL_FROM are required for the %s email provider", cfg.EmailProvider)
+	}
+
+	provider := &smtpProvider{
+		addr: net.JoinHostPort(host, fmt.Sprint(port)),
+		host: host,
+		from: cfg.EmailFrom,
+	}
+	if cfg.SMTPUsername != "" {
+		provider.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, host)
+	}
+	return provider, nil
+}
+
+func (p *smtpProvider) SendEmail(ctx context.Context, to, subject, htmlBody string) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", p.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+	msg.WriteString(htmlBody)
+
+	// net/smtp has no context support; bound the send instead
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(p.addr, p.auth, p.from, []string{to}, msg.Bytes())
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(30 * time.Second):
+		return fmt.Errorf("timed out sending email via %s", p.host)
+	}
+}
+
+type logEmailProvider struct{}
+
+func (logEmailProvider) SendEmail(_ context.Context, to, subject, _ string) error {
+	log.Printf("Email to %s: %s", to, subject)
+	return nil
+}
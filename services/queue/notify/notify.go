@@ -0,0 +1,126 @@
+package notify
+
+import (
+	"context"
+	"log"
+
+	"gin-quickstart/config"
+	"gin-quickstart/i18n"
+	"gin-quickstart/models"
+)
+
+// Notification types delivered directly to customers
+const (
+	TypeConfirmed   = "ORDER_CONFIRMED"
+	TypeAlmostReady = "ALMOST_READY"
+	TypeReady       = "READY"
+	// TypeReminder calls a customer whose ready order is still waiting for pickup
+	TypeReminder = "REMINDER"
+)
+
+// Recipient is where a customer can be reached
+type Recipient struct {
+	Name  string
+	Phone string
+	Email string
+	// DeviceTokens are the FCM registration tokens of the customer's devices
+	DeviceTokens []string
+	// Language the customer reads notifications in; empty is DEFAULT_LANGUAGE
+	Language string
+}
+
+// Delivery is the outcome of sending a notification over one channel
+type Delivery struct {
+	// Channel matches queue_notifications_sent.channel (SMS, EMAIL, PUSH)
+	Channel string
+	// ProviderMessageID identifies the message in delivery status callbacks
+	ProviderMessageID string
+	// Status is the provider's initial status, e.g. "queued"
+	Status string
+	Err    error
+	// InvalidDeviceTokens are push tokens the provider no longer accepts; they
+	// should be forgotten
+	InvalidDeviceTokens []string
+}
+
+// Channel delivers notifications over one medium
+type Channel interface {
+	Name() string
+	// Send delivers the notification. ok is false when the customer can't be
+	// reached on this channel (e.g. no phone number) or the channel has no
+	// message for the notification type, in which case nothing is sent.
+	Send(ctx context.Context, notificationType string, entry *models.QueueEntry, to Recipient) (delivery Delivery, ok bool)
+}
+
+// Dispatcher sends customer notifications over the configured channels
+type Dispatcher struct {
+	channels []Channel
+}
+
+// NewDispatcher builds the channels enabled in cfg; it returns nil when none are
+func NewDispatcher(cfg *config.Config) (*Dispatcher, error) {
+	var channels []Channel
+
+	sms, err := newSMSChannel(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if sms != nil {
+		channels = append(channels, sms)
+	}
+
+	email, err := newEmailChannel(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if email != nil {
+		channels = append(channels, email)
+	}
+
+	push, err := newPushChannel(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if push != nil {
+		channels = append(channels, push)
+	}
+
+	if len(channels) == 0 {
+		return nil, nil
+	}
+	return &Dispatcher{channels: channels}, nil
+}
+
+// Notify sends the notification to the recipient over every channel not in
+// skip (already used, or turned off in the customer's preferences)
+func (d *Dispatcher) Notify(ctx context.Context, notificationType string, entry *models.QueueEntry, to Recipient, skip map[string]bool) []Delivery {
+	var deliveries []Delivery
+	for _, channel := range d.channels {
+		if skip[channel.Name()] {
+			continue
+		}
+
+		delivery, ok := channel.Send(ctx, notificationType, entry, to)
+		if !ok {
+			continue
+		}
+		if delivery.Err != nil {
+			log.Printf("Failed to send %s %s notification for token=%s: %v",
+				channel.Name(), notificationType, entry.TokenNumber, delivery.Err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries
+}
+
+// localized returns the template of a notification type in language, falling
+// back to the default language and then English when it has none
+func localized[T any](templates map[string]map[string]T, language, notificationType string) (T, bool) {
+	for _, candidate := range []string{language, i18n.DefaultLanguage(), i18n.SourceLanguage} {
+		if tmpl, ok := templates[candidate][notificationType]; ok {
+			return tmpl, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,310 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"text/template"
+	"time"
+
+	"gin-quickstart/config"
+	"gin-quickstart/models"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	fcmSendURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	fcmScope   = "https://www.googleapis.com/auth/firebase.messaging"
+)
+
+// pushMessages are the title and body templates of each push notification,
+// by language
+var pushMessages = map[string]map[string][2]string{
+	"en": {
+		TypeConfirmed:   {"Order {{.TokenNumber}} is in the queue", "You're number {{.Position}} in line, about {{.EstimatedWaitTime}} min to go."},
+		TypeAlmostReady: {"Almost ready", "Order {{.TokenNumber}} is being prepared{{if .ReadyTime}} and should be ready around {{.ReadyTime}}{{end}}."},
+		TypeReady:       {"Order {{.TokenNumber}} is ready", "Pick it up{{if .Counter}} at counter {{.Counter}}{{else}} at the counter{{end}}."},
+		TypeReminder:    {"Order {{.TokenNumber}} is waiting for you", "Please pick it up{{if .Counter}} at counter {{.Counter}}{{else}} at the counter{{end}}."},
+	},
+	"es": {
+		TypeConfirmed:   {"El pedido {{.TokenNumber}} está en la cola", "Es el número {{.Position}} en la fila, faltan unos {{.EstimatedWaitTime}} min."},
+		TypeAlmostReady: {"Casi listo", "El pedido {{.TokenNumber}} se está preparando{{if .ReadyTime}} y estará listo hacia las {{.ReadyTime}}{{end}}."},
+		TypeReady:       {"El pedido {{.TokenNumber}} está listo", "Recójalo{{if .Counter}} en el mostrador {{.Counter}}{{else}} en el mostrador{{end}}."},
+		TypeReminder:    {"El pedido {{.TokenNumber}} le está esperando", "Por favor, recójalo{{if .Counter}} en el mostrador {{.Counter}}{{else}} en el mostrador{{end}}."},
+	},
+	"hi": {
+		TypeConfirmed:   {"ऑर्डर {{.TokenNumber}} कतार में है", "आप कतार में {{.Position}} नंबर पर हैं, लगभग {{.EstimatedWaitTime}} मिनट बाकी हैं।"},
+		TypeAlmostReady: {"लगभग तैयार", "ऑर्डर {{.TokenNumber}} तैयार हो रहा है{{if .ReadyTime}} और लगभग {{.ReadyTime}} तक तैयार हो जाएगा{{end}}।"},
+		TypeReady:       {"ऑर्डर {{.TokenNumber}} तैयार है", "इसे{{if .Counter}} काउंटर {{.Counter}}{{else}} काउंटर{{end}} से ले जाएँ।"},
+		TypeReminder:    {"ऑर्डर {{.TokenNumber}} आपका इंतज़ार कर रहा है", "कृपया इसे{{if .Counter}} काउंटर {{.Counter}}{{else}} काउंटर{{end}} से ले जाएँ।"},
+	},
+}
+
+type pushTemplate struct {
+	title *template.Template
+	body  *template.Template
+}
+
+// pushChannel sends through the FCM HTTP v1 API
+type pushChannel struct {
+	client     *http.Client
+	sendURL    string
+	maxRetries int
+	// templates maps language, then notification type, to its message
+	templates map[string]map[string]pushTemplate
+}
+
+// newPushChannel returns nil when FCM_PROJECT_ID is empty
+func newPushChannel(cfg *config.Config) (*pushChannel, error) {
+	if cfg.FCMProjectID == "" {
+		return nil, nil
+	}
+
+	ctx := context.Background()
+	var creds *google.Credentials
+	if cfg.FCMCredentialsFile != "" {
+		data, err := os.ReadFile(cfg.FCMCredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+		}
+		creds, err = google.CredentialsFromJSON(ctx, data, fcmScope)
+		if err != nil {
+			return nil, fmt.Errorf("invalid FCM credentials: %w", err)
+		}
+	} else {
+		var err error
+		creds, err = google.FindDefaultCredentials(ctx, fcmScope)
+		if err != nil {
+			return nil, fmt.Errorf("no FCM credentials (set FCM_CREDENTIALS_FILE): %w", err)
+		}
+	}
+
+	client := oauth2.NewClient(ctx, creds.TokenSource)
+	client.Timeout = 10 * time.Second
+
+	templates := make(map[string]map[string]pushTemplate, len(pushMessages))
+	for language, messages := range pushMessages {
+		templates[language] = make(map[string]pushTemplate, len(messages))
+		for notificationType, text := range messages {
+			title, err := template.New(notificationType + " title").Parse(text[0])
+			if err != nil {
+				return nil, fmt.Errorf("invalid push title for %s (%s): %w", notificationType, language, err)
+			}
+			body, err := template.New(notificationType + " body").Parse(text[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid push body for %s (%s): %w", notificationType, language, err)
+			}
+			templates[language][notificationType] = pushTemplate{title: title, body: body}
+		}
+	}
+
+	return &pushChannel{
+		client:     client,
+		sendURL:    fmt.Sprintf(fcmSendURL, cfg.FCMProjectID),
+		maxRetries: cfg.FCMMaxRetries,
+		templates:  templates,
+	}, nil
+}
+
+func (c *pushChannel) Name() string { return "PUSH" }
+
+// Send pushes to every device of the recipient. The delivery succeeds when at
+// least one device accepted the message.
+func (c *pushChannel) Send(ctx context.Context, notificationType string, entry *models.QueueEntry, to Recipient) (Delivery, bool) {
+	tmpl, ok := localized(c.templates, to.Language, notificationType)
+	if !ok || len(to.DeviceTokens) == 0 {
+		return Delivery{}, false
+	}
+
+	delivery := Delivery{Channel: c.Name()}
+	data := newMessageData(entry, to)
+
+	var title, body bytes.Buffer
+	if err := tmpl.title.Execute(&title, data); err != nil {
+		delivery.Err = fmt.Errorf("failed to render push title: %w", err)
+		return delivery, true
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		delivery.Err = fmt.Errorf("failed to render push body: %w", err)
+		return delivery, true
+	}
+
+	var lastErr error
+	for _, token := range to.DeviceTokens {
+		message := fcmMessage{
+			Token:        token,
+			Notification: fcmNotification{Title: title.String(), Body: body.String()},
+			Data: map[string]string{
+				"type":           notificationType,
+				"queue_entry_id": entry.ID,
+				"token_number":   entry.TokenNumber,
+				"position":       strconv.Itoa(entry.Position),
+			},
+		}
+		message.Android.Priority = "high"
+
+		name, err := c.send(ctx, message)
+		if err != nil {
+			var fcmErr *fcmError
+			if errors.As(err, &fcmErr) && fcmErr.invalidToken() {
+				delivery.InvalidDeviceTokens = append(delivery.InvalidDeviceTokens, token)
+			}
+			lastErr = err
+			continue
+		}
+		if delivery.ProviderMessageID == "" {
+			delivery.ProviderMessageID = name
+			delivery.Status = "sent"
+		}
+	}
+
+	if delivery.Status == "" {
+		delivery.Err = fmt.Errorf("push failed for all %d devices: %w", len(to.DeviceTokens), lastErr)
+	}
+	return delivery, true
+}
+
+// send delivers one message, retrying throttled and unavailable responses with
+// exponential backoff (or the Retry-After FCM asks for)
+func (c *pushChannel) send(ctx context.Context, message fcmMessage) (string, error) {
+	payload, err := json.Marshal(map[string]fcmMessage{"message": message})
+	if err != nil {
+		return "", err
+	}
+
+	backoff := time.Second
+	for attempt := 0; ; attempt++ {
+		name, err := c.post(ctx, payload)
+		if err == nil {
+			return name, nil
+		}
+
+		wait := backoff
+		var fcmErr *fcmError
+		if errors.As(err, &fcmErr) {
+			if !fcmErr.retryable() {
+				return "", err
+			}
+			if fcmErr.retryAfter > 0 {
+				wait = fcmErr.retryAfter
+			}
+		}
+		if attempt >= c.maxRetries {
+			return "", err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+func (c *pushChannel) post(ctx context.Context, payload []byte) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.sendURL, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fcm request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return "", newFCMError(resp)
+	}
+
+	var result struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid fcm response: %w", err)
+	}
+	return result.Name, nil
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      struct {
+		Priority string `json:"priority,omitempty"`
+	} `json:"android"`
+}
+
+// fcmError is an error response of the FCM v1 API
+type fcmError struct {
+	statusCode int
+	// errorCode is the FCM error code (UNREGISTERED, QUOTA_EXCEEDED, ...) or
+	// the generic status when FCM didn't give one
+	errorCode  string
+	message    string
+	retryAfter time.Duration
+}
+
+func newFCMError(resp *http.Response) *fcmError {
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+			Status  string `json:"status"`
+			Details []struct {
+				Type      string `json:"@type"`
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	// A body that isn't JSON still leaves the HTTP status to go on
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	fcmErr := &fcmError{
+		statusCode: resp.StatusCode,
+		errorCode:  body.Error.Status,
+		message:    body.Error.Message,
+	}
+	for _, detail := range body.Error.Details {
+		if detail.Type == "type.googleapis.com/google.firebase.fcm.v1.FcmError" && detail.ErrorCode != "" {
+			fcmErr.errorCode = detail.ErrorCode
+		}
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		fcmErr.retryAfter = time.Duration(seconds) * time.Second
+	}
+	return fcmErr
+}
+
+func (e *fcmError) Error() string {
+	return fmt.Sprintf("fcm rejected message (HTTP %d, %s): %s", e.statusCode, e.errorCode, e.message)
+}
+
+// retryable reports throttling and transient server errors
+func (e *fcmError) retryable() bool {
+	return e.statusCode == http.StatusTooManyRequests || e.statusCode >= 500
+}
+
+// invalidToken reports errors meaning the registration token will never work
+// again: the app was uninstalled, the token is malformed, or it belongs to
+// another Firebase project
+func (e *fcmError) invalidToken() bool {
+	switch e.errorCode {
+	case "UNREGISTERED", "INVALID_ARGUMENT", "SENDER_ID_MISMATCH":
+		return true
+	}
+	return false
+}
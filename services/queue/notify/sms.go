@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/config"
+	"gin-quickstart/i18n"
+	"gin-quickstart/models"
+)
+
+// SMS providers selectable with SMS_PROVIDER
+const (
+	SMSProviderTwilio = "twilio"
+	// SMSProviderLog only logs messages, for development
+	SMSProviderLog = "log"
+)
+
+// defaultSMSTemplates holds the messages of each language; SMS_TEMPLATE_<TYPE>
+// overrides those of the default language
+var defaultSMSTemplates = map[string]map[string]string{
+	"en": {
+		TypeAlmostReady: "Your order {{.TokenNumber}} is being prepared and should be ready around {{.ReadyTime}}.",
+		TypeReady:       "Your order {{.TokenNumber}} is ready for pickup{{if .Counter}} at counter {{.Counter}}{{end}}.",
+		TypeReminder:    "Your order {{.TokenNumber}} is still waiting for you{{if .Counter}} at counter {{.Counter}}{{end}}. Please pick it up.",
+	},
+	"es": {
+		TypeAlmostReady: "Su pedido {{.TokenNumber}} se está preparando y estará listo hacia las {{.ReadyTime}}.",
+		TypeReady:       "Su pedido {{.TokenNumber}} está listo para recoger{{if .Counter}} en el mostrador {{.Counter}}{{end}}.",
+		TypeReminder:    "Su pedido {{.TokenNumber}} le sigue esperando{{if .Counter}} en el mostrador {{.Counter}}{{end}}. Por favor, recójalo.",
+	},
+	"hi": {
+		TypeAlmostReady: "आपका ऑर्डर {{.TokenNumber}} तैयार हो रहा है और लगभग {{.ReadyTime}} तक तैयार हो जाएगा।",
+		TypeReady:       "आपका ऑर्डर {{.TokenNumber}} पिकअप के लिए तैयार है{{if .Counter}}, काउंटर {{.Counter}} पर{{end}}।",
+		TypeReminder:    "आपका ऑर्डर {{.TokenNumber}} अभी भी आपका इंतज़ार कर रहा है{{if .Counter}}, काउंटर {{.Counter}} पर{{end}}। कृपया इसे ले जाएँ।",
+	},
+}
+
+// SMSProvider sends a text message
+type SMSProvider interface {
+	SendSMS(ctx context.Context, to, body string) (messageID, status string, err error)
+}
+
+// MessageData is what notification templates can refer to
+type MessageData struct {
+	Name              string
+	TokenNumber       string
+	Position          int
+	EstimatedWaitTime int
+	ReadyTime         string
+	Counter           string
+}
+
+func newMessageData(entry *models.QueueEntry, to Recipient) MessageData {
+	data := MessageData{
+		Name:              to.Name,
+		TokenNumber:       entry.TokenNumber,
+		Position:          entry.Position,
+		EstimatedWaitTime: entry.EstimatedWaitTime,
+	}
+	if entry.EstimatedReadyTime != nil {
+		data.ReadyTime = clock.Local(*entry.EstimatedReadyTime).Format(time.Kitchen)
+	}
+	if entry.AssignedCounter != nil {
+		data.Counter = *entry.AssignedCounter
+	}
+	return data
+}
+
+type smsChannel struct {
+	provider SMSProvider
+	// templates maps language, then notification type, to its message
+	templates map[string]map[string]*template.Template
+}
+
+// newSMSChannel returns nil when SMS_PROVIDER is empty
+func newSMSChannel(cfg *config.Config) (*smsChannel, error) {
+	var provider SMSProvider
+	switch strings.ToLower(cfg.SMSProvider) {
+	case "":
+		return nil, nil
+	case SMSProviderTwilio:
+		twilio, err := newTwilioProvider(cfg)
+		if err != nil {
+			return nil, err
+		}
+		provider = twilio
+	case SMSProviderLog:
+		provider = logSMSProvider{}
+	default:
+		return nil, fmt.Errorf("unsupported SMS_PROVIDER %q", cfg.SMSProvider)
+	}
+
+	overrides := map[string]string{
+		TypeAlmostReady: cfg.SMSTemplateAlmostReady,
+		TypeReady:       cfg.SMSTemplateReady,
+		TypeReminder:    cfg.SMSTemplateReminder,
+	}
+	templates := make(map[string]map[string]*template.Template, len(defaultSMSTemplates))
+	for language, texts := range defaultSMSTemplates {
+		templates[language] = make(map[string]*template.Template, len(texts))
+		for notificationType, text := range texts {
+			if override := overrides[notificationType]; override != "" && language == i18n.DefaultLanguage() {
+				text = override
+			}
+			tmpl, err := template.New(notificationType).Parse(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid SMS template for %s (%s): %w", notificationType, language, err)
+			}
+			templates[language][notificationType] = tmpl
+		}
+	}
+
+	return &smsChannel{provider: provider, templates: templates}, nil
+}
+
+func (c *smsChannel) Name() string { return "SMS" }
+
+func (c *smsChannel) Send(ctx context.Context, notificationType string, entry *models.QueueEntry, to Recipient) (Delivery, bool) {
+	tmpl, ok := localized(c.templates, to.Language, notificationType)
+	if !ok || to.Phone == "" {
+		return Delivery{}, false
+	}
+
+	delivery := Delivery{Channel: c.Name()}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, newMessageData(entry, to)); err != nil {
+		delivery.Err = fmt.Errorf("failed to render SMS: %w", err)
+		return delivery, true
+	}
+
+	delivery.ProviderMessageID, delivery.Status, delivery.Err = c.provider.SendSMS(ctx, to.Phone, body.String())
+	return delivery, true
+}
+
+type logSMSProvider struct{}
+
+func (logSMSProvider) SendSMS(_ context.Context, to, body string) (string, string, error) {
+	log.Printf("SMS to %s: %s", to, body)
+	return "", "sent", nil
+}
@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"gin-quickstart/config"
+)
+
+const twilioAPIBaseURL = "https://api.twilio.com"
+
+// twilioProvider sends SMS through the Twilio Messages API
+type twilioProvider struct {
+	baseURL             string
+	accountSID          string
+	authToken           string
+	from                string
+	messagingServiceSID string
+	statusCallbackURL   string
+	client              *http.Client
+}
+
+// smsCallbackVerifier checks delivery status callbacks; nil until Twilio is configured
+var smsCallbackVerifier func(params url.Values, signature string) bool
+
+func newTwilioProvider(cfg *config.Config) (*twilioProvider, error) {
+	if cfg.TwilioAccountSID == "" || cfg.TwilioAuthToken == "" {
+		return nil, errors.New("TWILIO_ACCOUNT_SID and TWILIO_AUTH_TOKEN are required for the twilio SMS provider")
+	}
+	if cfg.TwilioFromNumber == "" && cfg.TwilioMessagingServiceSID == "" {
+		return nil, errors.New("TWILIO_FROM_NUMBER or TWILIO_MESSAGING_SERVICE_SID is required for the twilio SMS provider")
+	}
+
+	provider := &twilioProvider{
+		baseURL:             twilioAPIBaseURL,
+		accountSID:          cfg.TwilioAccountSID,
+		authToken:           cfg.TwilioAuthToken,
+		from:                cfg.TwilioFromNumber,
+		messagingServiceSID: cfg.TwilioMessagingServiceSID,
+		statusCallbackURL:   cfg.TwilioStatusCallbackURL,
+		client:              &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if provider.statusCallbackURL != "" {
+		smsCallbackVerifier = func(params url.Values, signature string) bool {
+			return validTwilioSignature(provider.authToken, provider.statusCallbackURL, params, signature)
+		}
+	}
+	return provider, nil
+}
+
+func (t *twilioProvider) SendSMS(ctx context.Context, to, body string) (string, string, error) {
+	form := url.Values{
+		"To":   {to},
+		"Body": {body},
+	}
+	if t.messagingServiceSID != "" {
+		form.Set("MessagingServiceSid", t.messagingServiceSID)
+	} else {
+		form.Set("From", t.from)
+	}
+	if t.statusCallbackURL != "" {
+		form.Set("StatusCallback", t.statusCallbackURL)
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", t.baseURL, t.accountSID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", "", err
+	}
+	req.SetBasicAuth(t.accountSID, t.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("twilio request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		SID     string `json:"sid"`
+		Status  string `json:"status"`
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", "", fmt.Errorf("invalid twilio response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode >= 300 {
+		return "", "", fmt.Errorf("twilio rejected message (HTTP %d, code %d): %s", resp.StatusCode, result.Code, result.Message)
+	}
+	return result.SID, result.Status, nil
+}
+
+// VerifySMSStatusCallback checks the X-Twilio-Signature of a delivery status
+// callback. It is false when no status callback URL is configured.
+func VerifySMSStatusCallback(params url.Values, signature string) bool {
+	if smsCallbackVerifier == nil {
+		return false
+	}
+	return smsCallbackVerifier(params, signature)
+}
+
+// validTwilioSignature implements Twilio's request signing: base64 HMAC-SHA1,
+// keyed with the auth token, of the callback URL followed by the sorted POST
+// parameters with their values
+func validTwilioSignature(authToken, callbackURL string, params url.Values, signature string) bool {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var data strings.Builder
+	data.WriteString(callbackURL)
+	for _, key := range keys {
+		for _, value := range params[key] {
+			data.WriteString(key)
+			data.WriteString(value)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(data.String()))
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,2643 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: events/events.proto
+
+package eventspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// QueueEvent is the envelope for every message on queue.events
+type QueueEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*QueueEvent_PositionUpdated
+	//	*QueueEvent_StatusChanged
+	//	*QueueEvent_EntryTombstone
+	//	*QueueEvent_Completed
+	//	*QueueEvent_Advanced
+	//	*QueueEvent_EntryCreated
+	//	*QueueEvent_SlaBreached
+	//	*QueueEvent_DayClosed
+	Event isQueueEvent_Event `protobuf_oneof:"event"`
+	// Envelope metadata, shared with the JSON envelope
+	EventId       string                 `protobuf:"bytes,100,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Version       int32                  `protobuf:"varint,101,opt,name=version,proto3" json:"version,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,102,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueEvent) Reset() {
+	*x = QueueEvent{}
+	mi := &file_events_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueEvent) ProtoMessage() {}
+
+func (x *QueueEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueEvent.ProtoReflect.Descriptor instead.
+func (*QueueEvent) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QueueEvent) GetEvent() isQueueEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *QueueEvent) GetPositionUpdated() *QueuePositionUpdated {
+	if x != nil {
+		if x, ok := x.Event.(*QueueEvent_PositionUpdated); ok {
+			return x.PositionUpdated
+		}
+	}
+	return nil
+}
+
+func (x *QueueEvent) GetStatusChanged() *QueueStatusChanged {
+	if x != nil {
+		if x, ok := x.Event.(*QueueEvent_StatusChanged); ok {
+			return x.StatusChanged
+		}
+	}
+	return nil
+}
+
+func (x *QueueEvent) GetEntryTombstone() *QueueEntryTombstone {
+	if x != nil {
+		if x, ok := x.Event.(*QueueEvent_EntryTombstone); ok {
+			return x.EntryTombstone
+		}
+	}
+	return nil
+}
+
+func (x *QueueEvent) GetCompleted() *QueueCompleted {
+	if x != nil {
+		if x, ok := x.Event.(*QueueEvent_Completed); ok {
+			return x.Completed
+		}
+	}
+	return nil
+}
+
+func (x *QueueEvent) GetAdvanced() *QueueAdvanced {
+	if x != nil {
+		if x, ok := x.Event.(*QueueEvent_Advanced); ok {
+			return x.Advanced
+		}
+	}
+	return nil
+}
+
+func (x *QueueEvent) GetEntryCreated() *QueueEntryCreated {
+	if x != nil {
+		if x, ok := x.Event.(*QueueEvent_EntryCreated); ok {
+			return x.EntryCreated
+		}
+	}
+	return nil
+}
+
+func (x *QueueEvent) GetSlaBreached() *QueueSlaBreached {
+	if x != nil {
+		if x, ok := x.Event.(*QueueEvent_SlaBreached); ok {
+			return x.SlaBreached
+		}
+	}
+	return nil
+}
+
+func (x *QueueEvent) GetDayClosed() *QueueDayClosed {
+	if x != nil {
+		if x, ok := x.Event.(*QueueEvent_DayClosed); ok {
+			return x.DayClosed
+		}
+	}
+	return nil
+}
+
+func (x *QueueEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *QueueEvent) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *QueueEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type isQueueEvent_Event interface {
+	isQueueEvent_Event()
+}
+
+type QueueEvent_PositionUpdated struct {
+	PositionUpdated *QueuePositionUpdated `protobuf:"bytes,1,opt,name=position_updated,json=positionUpdated,proto3,oneof"`
+}
+
+type QueueEvent_StatusChanged struct {
+	StatusChanged *QueueStatusChanged `protobuf:"bytes,2,opt,name=status_changed,json=statusChanged,proto3,oneof"`
+}
+
+type QueueEvent_EntryTombstone struct {
+	EntryTombstone *QueueEntryTombstone `protobuf:"bytes,3,opt,name=entry_tombstone,json=entryTombstone,proto3,oneof"`
+}
+
+type QueueEvent_Completed struct {
+	Completed *QueueCompleted `protobuf:"bytes,4,opt,name=completed,proto3,oneof"`
+}
+
+type QueueEvent_Advanced struct {
+	Advanced *QueueAdvanced `protobuf:"bytes,5,opt,name=advanced,proto3,oneof"`
+}
+
+type QueueEvent_EntryCreated struct {
+	EntryCreated *QueueEntryCreated `protobuf:"bytes,6,opt,name=entry_created,json=entryCreated,proto3,oneof"`
+}
+
+type QueueEvent_SlaBreached struct {
+	SlaBreached *QueueSlaBreached `protobuf:"bytes,7,opt,name=sla_breached,json=slaBreached,proto3,oneof"`
+}
+
+type QueueEvent_DayClosed struct {
+	DayClosed *QueueDayClosed `protobuf:"bytes,8,opt,name=day_closed,json=dayClosed,proto3,oneof"`
+}
+
+func (*QueueEvent_PositionUpdated) isQueueEvent_Event() {}
+
+func (*QueueEvent_StatusChanged) isQueueEvent_Event() {}
+
+func (*QueueEvent_EntryTombstone) isQueueEvent_Event() {}
+
+func (*QueueEvent_Completed) isQueueEvent_Event() {}
+
+func (*QueueEvent_Advanced) isQueueEvent_Event() {}
+
+func (*QueueEvent_EntryCreated) isQueueEvent_Event() {}
+
+func (*QueueEvent_SlaBreached) isQueueEvent_Event() {}
+
+func (*QueueEvent_DayClosed) isQueueEvent_Event() {}
+
+type QueuePositionUpdated struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	EventType          string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId       string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId            string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId             string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenNumber        string                 `protobuf:"bytes,5,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	Position           int32                  `protobuf:"varint,6,opt,name=position,proto3" json:"position,omitempty"`
+	EstimatedWaitTime  int32                  `protobuf:"varint,7,opt,name=estimated_wait_time,json=estimatedWaitTime,proto3" json:"estimated_wait_time,omitempty"`
+	EstimatedReadyTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=estimated_ready_time,json=estimatedReadyTime,proto3" json:"estimated_ready_time,omitempty"`
+	Status             string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	Timestamp          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *QueuePositionUpdated) Reset() {
+	*x = QueuePositionUpdated{}
+	mi := &file_events_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueuePositionUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueuePositionUpdated) ProtoMessage() {}
+
+func (x *QueuePositionUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueuePositionUpdated.ProtoReflect.Descriptor instead.
+func (*QueuePositionUpdated) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *QueuePositionUpdated) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueuePositionUpdated) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueuePositionUpdated) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueuePositionUpdated) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueuePositionUpdated) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueuePositionUpdated) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *QueuePositionUpdated) GetEstimatedWaitTime() int32 {
+	if x != nil {
+		return x.EstimatedWaitTime
+	}
+	return 0
+}
+
+func (x *QueuePositionUpdated) GetEstimatedReadyTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedReadyTime
+	}
+	return nil
+}
+
+func (x *QueuePositionUpdated) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *QueuePositionUpdated) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type QueueStatusChanged struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	EventType         string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId      string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId           string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId            string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenNumber       string                 `protobuf:"bytes,5,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	OldStatus         string                 `protobuf:"bytes,6,opt,name=old_status,json=oldStatus,proto3" json:"old_status,omitempty"`
+	NewStatus         string                 `protobuf:"bytes,7,opt,name=new_status,json=newStatus,proto3" json:"new_status,omitempty"`
+	Position          int32                  `protobuf:"varint,8,opt,name=position,proto3" json:"position,omitempty"`
+	EstimatedWaitTime int32                  `protobuf:"varint,9,opt,name=estimated_wait_time,json=estimatedWaitTime,proto3" json:"estimated_wait_time,omitempty"`
+	Timestamp         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *QueueStatusChanged) Reset() {
+	*x = QueueStatusChanged{}
+	mi := &file_events_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueStatusChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueStatusChanged) ProtoMessage() {}
+
+func (x *QueueStatusChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueStatusChanged.ProtoReflect.Descriptor instead.
+func (*QueueStatusChanged) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *QueueStatusChanged) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueStatusChanged) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueueStatusChanged) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueStatusChanged) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueueStatusChanged) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueStatusChanged) GetOldStatus() string {
+	if x != nil {
+		return x.OldStatus
+	}
+	return ""
+}
+
+func (x *QueueStatusChanged) GetNewStatus() string {
+	if x != nil {
+		return x.NewStatus
+	}
+	return ""
+}
+
+func (x *QueueStatusChanged) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *QueueStatusChanged) GetEstimatedWaitTime() int32 {
+	if x != nil {
+		return x.EstimatedWaitTime
+	}
+	return 0
+}
+
+func (x *QueueStatusChanged) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type QueueEntryTombstone struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventType     string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId  string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	TokenNumber   string                 `protobuf:"bytes,4,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	Action        string                 `protobuf:"bytes,5,opt,name=action,proto3" json:"action,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueEntryTombstone) Reset() {
+	*x = QueueEntryTombstone{}
+	mi := &file_events_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueEntryTombstone) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueEntryTombstone) ProtoMessage() {}
+
+func (x *QueueEntryTombstone) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueEntryTombstone.ProtoReflect.Descriptor instead.
+func (*QueueEntryTombstone) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *QueueEntryTombstone) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueEntryTombstone) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueueEntryTombstone) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueEntryTombstone) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueEntryTombstone) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *QueueEntryTombstone) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type QueueCompleted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventType     string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId  string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenNumber   string                 `protobuf:"bytes,5,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueCompleted) Reset() {
+	*x = QueueCompleted{}
+	mi := &file_events_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueCompleted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueCompleted) ProtoMessage() {}
+
+func (x *QueueCompleted) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueCompleted.ProtoReflect.Descriptor instead.
+func (*QueueCompleted) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QueueCompleted) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueCompleted) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueueCompleted) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueCompleted) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueueCompleted) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueCompleted) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type QueueAdvanced struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventType     string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId  string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	TokenNumber   string                 `protobuf:"bytes,4,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	NewStatus     string                 `protobuf:"bytes,5,opt,name=new_status,json=newStatus,proto3" json:"new_status,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueAdvanced) Reset() {
+	*x = QueueAdvanced{}
+	mi := &file_events_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueAdvanced) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueAdvanced) ProtoMessage() {}
+
+func (x *QueueAdvanced) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueAdvanced.ProtoReflect.Descriptor instead.
+func (*QueueAdvanced) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *QueueAdvanced) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueAdvanced) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueueAdvanced) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueAdvanced) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueAdvanced) GetNewStatus() string {
+	if x != nil {
+		return x.NewStatus
+	}
+	return ""
+}
+
+func (x *QueueAdvanced) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type QueueEntryCreated struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	EventType          string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId       string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId            string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId             string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenNumber        string                 `protobuf:"bytes,5,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	Position           int32                  `protobuf:"varint,6,opt,name=position,proto3" json:"position,omitempty"`
+	EstimatedWaitTime  int32                  `protobuf:"varint,7,opt,name=estimated_wait_time,json=estimatedWaitTime,proto3" json:"estimated_wait_time,omitempty"`
+	EstimatedReadyTime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=estimated_ready_time,json=estimatedReadyTime,proto3" json:"estimated_ready_time,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *QueueEntryCreated) Reset() {
+	*x = QueueEntryCreated{}
+	mi := &file_events_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueEntryCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueEntryCreated) ProtoMessage() {}
+
+func (x *QueueEntryCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueEntryCreated.ProtoReflect.Descriptor instead.
+func (*QueueEntryCreated) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *QueueEntryCreated) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueEntryCreated) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueueEntryCreated) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueEntryCreated) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueueEntryCreated) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueEntryCreated) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *QueueEntryCreated) GetEstimatedWaitTime() int32 {
+	if x != nil {
+		return x.EstimatedWaitTime
+	}
+	return 0
+}
+
+func (x *QueueEntryCreated) GetEstimatedReadyTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedReadyTime
+	}
+	return nil
+}
+
+func (x *QueueEntryCreated) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type QueueSlaBreached struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventType     string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId  string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenNumber   string                 `protobuf:"bytes,5,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	LocationId    string                 `protobuf:"bytes,6,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Priority      string                 `protobuf:"bytes,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	WaitedMinutes int32                  `protobuf:"varint,9,opt,name=waited_minutes,json=waitedMinutes,proto3" json:"waited_minutes,omitempty"`
+	SlaMinutes    int32                  `protobuf:"varint,10,opt,name=sla_minutes,json=slaMinutes,proto3" json:"sla_minutes,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueSlaBreached) Reset() {
+	*x = QueueSlaBreached{}
+	mi := &file_events_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueSlaBreached) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueSlaBreached) ProtoMessage() {}
+
+func (x *QueueSlaBreached) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueSlaBreached.ProtoReflect.Descriptor instead.
+func (*QueueSlaBreached) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *QueueSlaBreached) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueSlaBreached) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueueSlaBreached) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueSlaBreached) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueueSlaBreached) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueSlaBreached) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
+func (x *QueueSlaBreached) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *QueueSlaBreached) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *QueueSlaBreached) GetWaitedMinutes() int32 {
+	if x != nil {
+		return x.WaitedMinutes
+	}
+	return 0
+}
+
+func (x *QueueSlaBreached) GetSlaMinutes() int32 {
+	if x != nil {
+		return x.SlaMinutes
+	}
+	return 0
+}
+
+func (x *QueueSlaBreached) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type QueueDayClosed struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EventType       string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	LocationId      string                 `protobuf:"bytes,2,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	BusinessDate    string                 `protobuf:"bytes,3,opt,name=business_date,json=businessDate,proto3" json:"business_date,omitempty"`
+	ExpiredEntries  int32                  `protobuf:"varint,4,opt,name=expired_entries,json=expiredEntries,proto3" json:"expired_entries,omitempty"`
+	TokensIssued    int32                  `protobuf:"varint,5,opt,name=tokens_issued,json=tokensIssued,proto3" json:"tokens_issued,omitempty"`
+	NextTokenPrefix string                 `protobuf:"bytes,6,opt,name=next_token_prefix,json=nextTokenPrefix,proto3" json:"next_token_prefix,omitempty"`
+	Timestamp       *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *QueueDayClosed) Reset() {
+	*x = QueueDayClosed{}
+	mi := &file_events_events_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueDayClosed) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueDayClosed) ProtoMessage() {}
+
+func (x *QueueDayClosed) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueDayClosed.ProtoReflect.Descriptor instead.
+func (*QueueDayClosed) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *QueueDayClosed) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueDayClosed) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
+func (x *QueueDayClosed) GetBusinessDate() string {
+	if x != nil {
+		return x.BusinessDate
+	}
+	return ""
+}
+
+func (x *QueueDayClosed) GetExpiredEntries() int32 {
+	if x != nil {
+		return x.ExpiredEntries
+	}
+	return 0
+}
+
+func (x *QueueDayClosed) GetTokensIssued() int32 {
+	if x != nil {
+		return x.TokensIssued
+	}
+	return 0
+}
+
+func (x *QueueDayClosed) GetNextTokenPrefix() string {
+	if x != nil {
+		return x.NextTokenPrefix
+	}
+	return ""
+}
+
+func (x *QueueDayClosed) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// NotificationEvent is the envelope for every message on notification.events
+type NotificationEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*NotificationEvent_AlmostReady
+	//	*NotificationEvent_Ready
+	//	*NotificationEvent_StageReady
+	//	*NotificationEvent_Cancelled
+	Event isNotificationEvent_Event `protobuf_oneof:"event"`
+	// Envelope metadata, shared with the JSON envelope
+	EventId       string                 `protobuf:"bytes,100,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Version       int32                  `protobuf:"varint,101,opt,name=version,proto3" json:"version,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,102,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NotificationEvent) Reset() {
+	*x = NotificationEvent{}
+	mi := &file_events_events_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationEvent) ProtoMessage() {}
+
+func (x *NotificationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationEvent.ProtoReflect.Descriptor instead.
+func (*NotificationEvent) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *NotificationEvent) GetEvent() isNotificationEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *NotificationEvent) GetAlmostReady() *QueueAlmostReady {
+	if x != nil {
+		if x, ok := x.Event.(*NotificationEvent_AlmostReady); ok {
+			return x.AlmostReady
+		}
+	}
+	return nil
+}
+
+func (x *NotificationEvent) GetReady() *QueueReady {
+	if x != nil {
+		if x, ok := x.Event.(*NotificationEvent_Ready); ok {
+			return x.Ready
+		}
+	}
+	return nil
+}
+
+func (x *NotificationEvent) GetStageReady() *QueueStageReady {
+	if x != nil {
+		if x, ok := x.Event.(*NotificationEvent_StageReady); ok {
+			return x.StageReady
+		}
+	}
+	return nil
+}
+
+func (x *NotificationEvent) GetCancelled() *QueueCancelled {
+	if x != nil {
+		if x, ok := x.Event.(*NotificationEvent_Cancelled); ok {
+			return x.Cancelled
+		}
+	}
+	return nil
+}
+
+func (x *NotificationEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *NotificationEvent) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *NotificationEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type isNotificationEvent_Event interface {
+	isNotificationEvent_Event()
+}
+
+type NotificationEvent_AlmostReady struct {
+	AlmostReady *QueueAlmostReady `protobuf:"bytes,1,opt,name=almost_ready,json=almostReady,proto3,oneof"`
+}
+
+type NotificationEvent_Ready struct {
+	Ready *QueueReady `protobuf:"bytes,2,opt,name=ready,proto3,oneof"`
+}
+
+type NotificationEvent_StageReady struct {
+	StageReady *QueueStageReady `protobuf:"bytes,3,opt,name=stage_ready,json=stageReady,proto3,oneof"`
+}
+
+type NotificationEvent_Cancelled struct {
+	Cancelled *QueueCancelled `protobuf:"bytes,4,opt,name=cancelled,proto3,oneof"`
+}
+
+func (*NotificationEvent_AlmostReady) isNotificationEvent_Event() {}
+
+func (*NotificationEvent_Ready) isNotificationEvent_Event() {}
+
+func (*NotificationEvent_StageReady) isNotificationEvent_Event() {}
+
+func (*NotificationEvent_Cancelled) isNotificationEvent_Event() {}
+
+type QueueAlmostReady struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	EventType         string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId      string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId           string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId            string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenNumber       string                 `protobuf:"bytes,5,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	Position          int32                  `protobuf:"varint,6,opt,name=position,proto3" json:"position,omitempty"`
+	EstimatedWaitTime int32                  `protobuf:"varint,7,opt,name=estimated_wait_time,json=estimatedWaitTime,proto3" json:"estimated_wait_time,omitempty"`
+	NotificationType  string                 `protobuf:"bytes,8,opt,name=notification_type,json=notificationType,proto3" json:"notification_type,omitempty"`
+	Timestamp         *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *QueueAlmostReady) Reset() {
+	*x = QueueAlmostReady{}
+	mi := &file_events_events_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueAlmostReady) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueAlmostReady) ProtoMessage() {}
+
+func (x *QueueAlmostReady) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueAlmostReady.ProtoReflect.Descriptor instead.
+func (*QueueAlmostReady) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *QueueAlmostReady) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueAlmostReady) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueueAlmostReady) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueAlmostReady) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueueAlmostReady) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueAlmostReady) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *QueueAlmostReady) GetEstimatedWaitTime() int32 {
+	if x != nil {
+		return x.EstimatedWaitTime
+	}
+	return 0
+}
+
+func (x *QueueAlmostReady) GetNotificationType() string {
+	if x != nil {
+		return x.NotificationType
+	}
+	return ""
+}
+
+func (x *QueueAlmostReady) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type QueueReady struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	EventType        string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId     string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId          string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId           string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenNumber      string                 `protobuf:"bytes,5,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	NotificationType string                 `protobuf:"bytes,6,opt,name=notification_type,json=notificationType,proto3" json:"notification_type,omitempty"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *QueueReady) Reset() {
+	*x = QueueReady{}
+	mi := &file_events_events_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueReady) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueReady) ProtoMessage() {}
+
+func (x *QueueReady) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueReady.ProtoReflect.Descriptor instead.
+func (*QueueReady) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *QueueReady) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueReady) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueueReady) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueReady) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueueReady) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueReady) GetNotificationType() string {
+	if x != nil {
+		return x.NotificationType
+	}
+	return ""
+}
+
+func (x *QueueReady) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type QueueStageReady struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	EventType        string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId     string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId          string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId           string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenNumber      string                 `protobuf:"bytes,5,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	StageId          string                 `protobuf:"bytes,6,opt,name=stage_id,json=stageId,proto3" json:"stage_id,omitempty"`
+	StageSequence    int32                  `protobuf:"varint,7,opt,name=stage_sequence,json=stageSequence,proto3" json:"stage_sequence,omitempty"`
+	StageName        string                 `protobuf:"bytes,8,opt,name=stage_name,json=stageName,proto3" json:"stage_name,omitempty"`
+	RemainingStages  int32                  `protobuf:"varint,9,opt,name=remaining_stages,json=remainingStages,proto3" json:"remaining_stages,omitempty"`
+	NotificationType string                 `protobuf:"bytes,10,opt,name=notification_type,json=notificationType,proto3" json:"notification_type,omitempty"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *QueueStageReady) Reset() {
+	*x = QueueStageReady{}
+	mi := &file_events_events_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueStageReady) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueStageReady) ProtoMessage() {}
+
+func (x *QueueStageReady) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueStageReady.ProtoReflect.Descriptor instead.
+func (*QueueStageReady) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *QueueStageReady) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueStageReady) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueueStageReady) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueStageReady) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueueStageReady) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueStageReady) GetStageId() string {
+	if x != nil {
+		return x.StageId
+	}
+	return ""
+}
+
+func (x *QueueStageReady) GetStageSequence() int32 {
+	if x != nil {
+		return x.StageSequence
+	}
+	return 0
+}
+
+func (x *QueueStageReady) GetStageName() string {
+	if x != nil {
+		return x.StageName
+	}
+	return ""
+}
+
+func (x *QueueStageReady) GetRemainingStages() int32 {
+	if x != nil {
+		return x.RemainingStages
+	}
+	return 0
+}
+
+func (x *QueueStageReady) GetNotificationType() string {
+	if x != nil {
+		return x.NotificationType
+	}
+	return ""
+}
+
+func (x *QueueStageReady) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type QueueCancelled struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	EventType        string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	QueueEntryId     string                 `protobuf:"bytes,2,opt,name=queue_entry_id,json=queueEntryId,proto3" json:"queue_entry_id,omitempty"`
+	OrderId          string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId           string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenNumber      string                 `protobuf:"bytes,5,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	Reason           *string                `protobuf:"bytes,6,opt,name=reason,proto3,oneof" json:"reason,omitempty"`
+	NotificationType string                 `protobuf:"bytes,7,opt,name=notification_type,json=notificationType,proto3" json:"notification_type,omitempty"`
+	Timestamp        *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *QueueCancelled) Reset() {
+	*x = QueueCancelled{}
+	mi := &file_events_events_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueCancelled) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueCancelled) ProtoMessage() {}
+
+func (x *QueueCancelled) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueCancelled.ProtoReflect.Descriptor instead.
+func (*QueueCancelled) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *QueueCancelled) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *QueueCancelled) GetQueueEntryId() string {
+	if x != nil {
+		return x.QueueEntryId
+	}
+	return ""
+}
+
+func (x *QueueCancelled) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueCancelled) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueueCancelled) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueCancelled) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
+}
+
+func (x *QueueCancelled) GetNotificationType() string {
+	if x != nil {
+		return x.NotificationType
+	}
+	return ""
+}
+
+func (x *QueueCancelled) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// KitchenEvent is the envelope for every message on kitchen.events
+type KitchenEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*KitchenEvent_BatchSuggested
+	Event isKitchenEvent_Event `protobuf_oneof:"event"`
+	// Envelope metadata, shared with the JSON envelope
+	EventId       string                 `protobuf:"bytes,100,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Version       int32                  `protobuf:"varint,101,opt,name=version,proto3" json:"version,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,102,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KitchenEvent) Reset() {
+	*x = KitchenEvent{}
+	mi := &file_events_events_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KitchenEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KitchenEvent) ProtoMessage() {}
+
+func (x *KitchenEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KitchenEvent.ProtoReflect.Descriptor instead.
+func (*KitchenEvent) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *KitchenEvent) GetEvent() isKitchenEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *KitchenEvent) GetBatchSuggested() *BatchSuggested {
+	if x != nil {
+		if x, ok := x.Event.(*KitchenEvent_BatchSuggested); ok {
+			return x.BatchSuggested
+		}
+	}
+	return nil
+}
+
+func (x *KitchenEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *KitchenEvent) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *KitchenEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type isKitchenEvent_Event interface {
+	isKitchenEvent_Event()
+}
+
+type KitchenEvent_BatchSuggested struct {
+	BatchSuggested *BatchSuggested `protobuf:"bytes,1,opt,name=batch_suggested,json=batchSuggested,proto3,oneof"`
+}
+
+func (*KitchenEvent_BatchSuggested) isKitchenEvent_Event() {}
+
+type BatchSuggested struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventType     string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	LocationId    string                 `protobuf:"bytes,2,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	MenuItemId    string                 `protobuf:"bytes,3,opt,name=menu_item_id,json=menuItemId,proto3" json:"menu_item_id,omitempty"`
+	ItemName      *string                `protobuf:"bytes,4,opt,name=item_name,json=itemName,proto3,oneof" json:"item_name,omitempty"`
+	TotalQuantity int32                  `protobuf:"varint,5,opt,name=total_quantity,json=totalQuantity,proto3" json:"total_quantity,omitempty"`
+	EntryIds      []string               `protobuf:"bytes,6,rep,name=entry_ids,json=entryIds,proto3" json:"entry_ids,omitempty"`
+	TokenNumbers  []string               `protobuf:"bytes,7,rep,name=token_numbers,json=tokenNumbers,proto3" json:"token_numbers,omitempty"`
+	StartPosition int32                  `protobuf:"varint,8,opt,name=start_position,json=startPosition,proto3" json:"start_position,omitempty"`
+	EndPosition   int32                  `protobuf:"varint,9,opt,name=end_position,json=endPosition,proto3" json:"end_position,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchSuggested) Reset() {
+	*x = BatchSuggested{}
+	mi := &file_events_events_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchSuggested) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSuggested) ProtoMessage() {}
+
+func (x *BatchSuggested) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSuggested.ProtoReflect.Descriptor instead.
+func (*BatchSuggested) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchSuggested) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *BatchSuggested) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
+func (x *BatchSuggested) GetMenuItemId() string {
+	if x != nil {
+		return x.MenuItemId
+	}
+	return ""
+}
+
+func (x *BatchSuggested) GetItemName() string {
+	if x != nil && x.ItemName != nil {
+		return *x.ItemName
+	}
+	return ""
+}
+
+func (x *BatchSuggested) GetTotalQuantity() int32 {
+	if x != nil {
+		return x.TotalQuantity
+	}
+	return 0
+}
+
+func (x *BatchSuggested) GetEntryIds() []string {
+	if x != nil {
+		return x.EntryIds
+	}
+	return nil
+}
+
+func (x *BatchSuggested) GetTokenNumbers() []string {
+	if x != nil {
+		return x.TokenNumbers
+	}
+	return nil
+}
+
+func (x *BatchSuggested) GetStartPosition() int32 {
+	if x != nil {
+		return x.StartPosition
+	}
+	return 0
+}
+
+func (x *BatchSuggested) GetEndPosition() int32 {
+	if x != nil {
+		return x.EndPosition
+	}
+	return 0
+}
+
+func (x *BatchSuggested) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type OrderCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	LocationId    string                 `protobuf:"bytes,3,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	UserName      string                 `protobuf:"bytes,4,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	UserPhone     string                 `protobuf:"bytes,5,opt,name=user_phone,json=userPhone,proto3" json:"user_phone,omitempty"`
+	Items         []*OrderItem           `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	TotalAmount   float64                `protobuf:"fixed64,7,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	Priority      string                 `protobuf:"bytes,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	IsExpress     bool                   `protobuf:"varint,9,opt,name=is_express,json=isExpress,proto3" json:"is_express,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	PaymentStatus string                 `protobuf:"bytes,11,opt,name=payment_status,json=paymentStatus,proto3" json:"payment_status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCreated) Reset() {
+	*x = OrderCreated{}
+	mi := &file_events_events_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCreated) ProtoMessage() {}
+
+func (x *OrderCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCreated.ProtoReflect.Descriptor instead.
+func (*OrderCreated) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *OrderCreated) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderCreated) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *OrderCreated) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
+func (x *OrderCreated) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *OrderCreated) GetUserPhone() string {
+	if x != nil {
+		return x.UserPhone
+	}
+	return ""
+}
+
+func (x *OrderCreated) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderCreated) GetTotalAmount() float64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *OrderCreated) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *OrderCreated) GetIsExpress() bool {
+	if x != nil {
+		return x.IsExpress
+	}
+	return false
+}
+
+func (x *OrderCreated) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OrderCreated) GetPaymentStatus() string {
+	if x != nil {
+		return x.PaymentStatus
+	}
+	return ""
+}
+
+type OrderItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MenuItemId    string                 `protobuf:"bytes,1,opt,name=menu_item_id,json=menuItemId,proto3" json:"menu_item_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_events_events_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *OrderItem) GetMenuItemId() string {
+	if x != nil {
+		return x.MenuItemId
+	}
+	return ""
+}
+
+func (x *OrderItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *OrderItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderItem) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+type OrderStatusChanged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusChanged) Reset() {
+	*x = OrderStatusChanged{}
+	mi := &file_events_events_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusChanged) ProtoMessage() {}
+
+func (x *OrderStatusChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusChanged.ProtoReflect.Descriptor instead.
+func (*OrderStatusChanged) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *OrderStatusChanged) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type OrderCancelled struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	CancelledAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderCancelled) Reset() {
+	*x = OrderCancelled{}
+	mi := &file_events_events_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderCancelled) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderCancelled) ProtoMessage() {}
+
+func (x *OrderCancelled) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderCancelled.ProtoReflect.Descriptor instead.
+func (*OrderCancelled) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *OrderCancelled) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderCancelled) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderCancelled) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+type PaymentCompleted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PaymentId     string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	CompletedAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentCompleted) Reset() {
+	*x = PaymentCompleted{}
+	mi := &file_events_events_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentCompleted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentCompleted) ProtoMessage() {}
+
+func (x *PaymentCompleted) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentCompleted.ProtoReflect.Descriptor instead.
+func (*PaymentCompleted) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *PaymentCompleted) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PaymentCompleted) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *PaymentCompleted) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentCompleted) GetCompletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return nil
+}
+
+type MenuUpdated struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	MenuItemId      string                 `protobuf:"bytes,1,opt,name=menu_item_id,json=menuItemId,proto3" json:"menu_item_id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	PreparationTime *int32                 `protobuf:"varint,3,opt,name=preparation_time,json=preparationTime,proto3,oneof" json:"preparation_time,omitempty"`
+	IsAvailable     bool                   `protobuf:"varint,4,opt,name=is_available,json=isAvailable,proto3" json:"is_available,omitempty"`
+	Deleted         bool                   `protobuf:"varint,5,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MenuUpdated) Reset() {
+	*x = MenuUpdated{}
+	mi := &file_events_events_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MenuUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MenuUpdated) ProtoMessage() {}
+
+func (x *MenuUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_events_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MenuUpdated.ProtoReflect.Descriptor instead.
+func (*MenuUpdated) Descriptor() ([]byte, []int) {
+	return file_events_events_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *MenuUpdated) GetMenuItemId() string {
+	if x != nil {
+		return x.MenuItemId
+	}
+	return ""
+}
+
+func (x *MenuUpdated) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MenuUpdated) GetPreparationTime() int32 {
+	if x != nil && x.PreparationTime != nil {
+		return *x.PreparationTime
+	}
+	return 0
+}
+
+func (x *MenuUpdated) GetIsAvailable() bool {
+	if x != nil {
+		return x.IsAvailable
+	}
+	return false
+}
+
+func (x *MenuUpdated) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *MenuUpdated) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+var File_events_events_proto protoreflect.FileDescriptor
+
+const file_events_events_proto_rawDesc = "" +
+	"\n" +
+	"\x13events/events.proto\x12\x0fqueue.events.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xce\x05\n" +
+	"\n" +
+	"QueueEvent\x12R\n" +
+	"\x10position_updated\x18\x01 \x01(\v2%.queue.events.v1.QueuePositionUpdatedH\x00R\x0fpositionUpdated\x12L\n" +
+	"\x0estatus_changed\x18\x02 \x01(\v2#.queue.events.v1.QueueStatusChangedH\x00R\rstatusChanged\x12O\n" +
+	"\x0fentry_tombstone\x18\x03 \x01(\v2$.queue.events.v1.QueueEntryTombstoneH\x00R\x0eentryTombstone\x12?\n" +
+	"\tcompleted\x18\x04 \x01(\v2\x1f.queue.events.v1.QueueCompletedH\x00R\tcompleted\x12<\n" +
+	"\badvanced\x18\x05 \x01(\v2\x1e.queue.events.v1.QueueAdvancedH\x00R\badvanced\x12I\n" +
+	"\rentry_created\x18\x06 \x01(\v2\".queue.events.v1.QueueEntryCreatedH\x00R\fentryCreated\x12F\n" +
+	"\fsla_breached\x18\a \x01(\v2!.queue.events.v1.QueueSlaBreachedH\x00R\vslaBreached\x12@\n" +
+	"\n" +
+	"day_closed\x18\b \x01(\v2\x1f.queue.events.v1.QueueDayClosedH\x00R\tdayClosed\x12\x19\n" +
+	"\bevent_id\x18d \x01(\tR\aeventId\x12\x18\n" +
+	"\aversion\x18e \x01(\x05R\aversion\x12;\n" +
+	"\voccurred_at\x18f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAtB\a\n" +
+	"\x05event\"\x9e\x03\n" +
+	"\x14QueuePositionUpdated\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\ftoken_number\x18\x05 \x01(\tR\vtokenNumber\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\x05R\bposition\x12.\n" +
+	"\x13estimated_wait_time\x18\a \x01(\x05R\x11estimatedWaitTime\x12L\n" +
+	"\x14estimated_ready_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x12estimatedReadyTime\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x128\n" +
+	"\ttimestamp\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xf4\x02\n" +
+	"\x12QueueStatusChanged\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\ftoken_number\x18\x05 \x01(\tR\vtokenNumber\x12\x1d\n" +
+	"\n" +
+	"old_status\x18\x06 \x01(\tR\toldStatus\x12\x1d\n" +
+	"\n" +
+	"new_status\x18\a \x01(\tR\tnewStatus\x12\x1a\n" +
+	"\bposition\x18\b \x01(\x05R\bposition\x12.\n" +
+	"\x13estimated_wait_time\x18\t \x01(\x05R\x11estimatedWaitTime\x128\n" +
+	"\ttimestamp\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xea\x01\n" +
+	"\x13QueueEntryTombstone\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12!\n" +
+	"\ftoken_number\x18\x04 \x01(\tR\vtokenNumber\x12\x16\n" +
+	"\x06action\x18\x05 \x01(\tR\x06action\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xe6\x01\n" +
+	"\x0eQueueCompleted\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\ftoken_number\x18\x05 \x01(\tR\vtokenNumber\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xeb\x01\n" +
+	"\rQueueAdvanced\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12!\n" +
+	"\ftoken_number\x18\x04 \x01(\tR\vtokenNumber\x12\x1d\n" +
+	"\n" +
+	"new_status\x18\x05 \x01(\tR\tnewStatus\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x84\x03\n" +
+	"\x11QueueEntryCreated\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\ftoken_number\x18\x05 \x01(\tR\vtokenNumber\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\x05R\bposition\x12.\n" +
+	"\x13estimated_wait_time\x18\a \x01(\x05R\x11estimatedWaitTime\x12L\n" +
+	"\x14estimated_ready_time\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x12estimatedReadyTime\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x85\x03\n" +
+	"\x10QueueSlaBreached\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\ftoken_number\x18\x05 \x01(\tR\vtokenNumber\x12\x1f\n" +
+	"\vlocation_id\x18\x06 \x01(\tR\n" +
+	"locationId\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\tR\bpriority\x12%\n" +
+	"\x0ewaited_minutes\x18\t \x01(\x05R\rwaitedMinutes\x12\x1f\n" +
+	"\vsla_minutes\x18\n" +
+	" \x01(\x05R\n" +
+	"slaMinutes\x128\n" +
+	"\ttimestamp\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xa9\x02\n" +
+	"\x0eQueueDayClosed\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12\x1f\n" +
+	"\vlocation_id\x18\x02 \x01(\tR\n" +
+	"locationId\x12#\n" +
+	"\rbusiness_date\x18\x03 \x01(\tR\fbusinessDate\x12'\n" +
+	"\x0fexpired_entries\x18\x04 \x01(\x05R\x0eexpiredEntries\x12#\n" +
+	"\rtokens_issued\x18\x05 \x01(\x05R\ftokensIssued\x12*\n" +
+	"\x11next_token_prefix\x18\x06 \x01(\tR\x0fnextTokenPrefix\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x91\x03\n" +
+	"\x11NotificationEvent\x12F\n" +
+	"\falmost_ready\x18\x01 \x01(\v2!.queue.events.v1.QueueAlmostReadyH\x00R\valmostReady\x123\n" +
+	"\x05ready\x18\x02 \x01(\v2\x1b.queue.events.v1.QueueReadyH\x00R\x05ready\x12C\n" +
+	"\vstage_ready\x18\x03 \x01(\v2 .queue.events.v1.QueueStageReadyH\x00R\n" +
+	"stageReady\x12?\n" +
+	"\tcancelled\x18\x04 \x01(\v2\x1f.queue.events.v1.QueueCancelledH\x00R\tcancelled\x12\x19\n" +
+	"\bevent_id\x18d \x01(\tR\aeventId\x12\x18\n" +
+	"\aversion\x18e \x01(\x05R\aversion\x12;\n" +
+	"\voccurred_at\x18f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAtB\a\n" +
+	"\x05event\"\xe1\x02\n" +
+	"\x10QueueAlmostReady\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\ftoken_number\x18\x05 \x01(\tR\vtokenNumber\x12\x1a\n" +
+	"\bposition\x18\x06 \x01(\x05R\bposition\x12.\n" +
+	"\x13estimated_wait_time\x18\a \x01(\x05R\x11estimatedWaitTime\x12+\n" +
+	"\x11notification_type\x18\b \x01(\tR\x10notificationType\x128\n" +
+	"\ttimestamp\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x8f\x02\n" +
+	"\n" +
+	"QueueReady\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\ftoken_number\x18\x05 \x01(\tR\vtokenNumber\x12+\n" +
+	"\x11notification_type\x18\x06 \x01(\tR\x10notificationType\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xa0\x03\n" +
+	"\x0fQueueStageReady\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\ftoken_number\x18\x05 \x01(\tR\vtokenNumber\x12\x19\n" +
+	"\bstage_id\x18\x06 \x01(\tR\astageId\x12%\n" +
+	"\x0estage_sequence\x18\a \x01(\x05R\rstageSequence\x12\x1d\n" +
+	"\n" +
+	"stage_name\x18\b \x01(\tR\tstageName\x12)\n" +
+	"\x10remaining_stages\x18\t \x01(\x05R\x0fremainingStages\x12+\n" +
+	"\x11notification_type\x18\n" +
+	" \x01(\tR\x10notificationType\x128\n" +
+	"\ttimestamp\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xbb\x02\n" +
+	"\x0eQueueCancelled\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12$\n" +
+	"\x0equeue_entry_id\x18\x02 \x01(\tR\fqueueEntryId\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\ftoken_number\x18\x05 \x01(\tR\vtokenNumber\x12\x1b\n" +
+	"\x06reason\x18\x06 \x01(\tH\x00R\x06reason\x88\x01\x01\x12+\n" +
+	"\x11notification_type\x18\a \x01(\tR\x10notificationType\x128\n" +
+	"\ttimestamp\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\ttimestampB\t\n" +
+	"\a_reason\"\xd5\x01\n" +
+	"\fKitchenEvent\x12J\n" +
+	"\x0fbatch_suggested\x18\x01 \x01(\v2\x1f.queue.events.v1.BatchSuggestedH\x00R\x0ebatchSuggested\x12\x19\n" +
+	"\bevent_id\x18d \x01(\tR\aeventId\x12\x18\n" +
+	"\aversion\x18e \x01(\x05R\aversion\x12;\n" +
+	"\voccurred_at\x18f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAtB\a\n" +
+	"\x05event\"\x8f\x03\n" +
+	"\x0eBatchSuggested\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12\x1f\n" +
+	"\vlocation_id\x18\x02 \x01(\tR\n" +
+	"locationId\x12 \n" +
+	"\fmenu_item_id\x18\x03 \x01(\tR\n" +
+	"menuItemId\x12 \n" +
+	"\titem_name\x18\x04 \x01(\tH\x00R\bitemName\x88\x01\x01\x12%\n" +
+	"\x0etotal_quantity\x18\x05 \x01(\x05R\rtotalQuantity\x12\x1b\n" +
+	"\tentry_ids\x18\x06 \x03(\tR\bentryIds\x12#\n" +
+	"\rtoken_numbers\x18\a \x03(\tR\ftokenNumbers\x12%\n" +
+	"\x0estart_position\x18\b \x01(\x05R\rstartPosition\x12!\n" +
+	"\fend_position\x18\t \x01(\x05R\vendPosition\x128\n" +
+	"\ttimestamp\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\ttimestampB\f\n" +
+	"\n" +
+	"_item_name\"\x91\x03\n" +
+	"\fOrderCreated\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vlocation_id\x18\x03 \x01(\tR\n" +
+	"locationId\x12\x1b\n" +
+	"\tuser_name\x18\x04 \x01(\tR\buserName\x12\x1d\n" +
+	"\n" +
+	"user_phone\x18\x05 \x01(\tR\tuserPhone\x120\n" +
+	"\x05items\x18\x06 \x03(\v2\x1a.queue.events.v1.OrderItemR\x05items\x12!\n" +
+	"\ftotal_amount\x18\a \x01(\x01R\vtotalAmount\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\tR\bpriority\x12\x1d\n" +
+	"\n" +
+	"is_express\x18\t \x01(\bR\tisExpress\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12%\n" +
+	"\x0epayment_status\x18\v \x01(\tR\rpaymentStatus\"s\n" +
+	"\tOrderItem\x12 \n" +
+	"\fmenu_item_id\x18\x01 \x01(\tR\n" +
+	"menuItemId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\"\x82\x01\n" +
+	"\x12OrderStatusChanged\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x82\x01\n" +
+	"\x0eOrderCancelled\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12=\n" +
+	"\fcancelled_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\"\xa3\x01\n" +
+	"\x10PaymentCompleted\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12=\n" +
+	"\fcompleted_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vcompletedAt\"\x80\x02\n" +
+	"\vMenuUpdated\x12 \n" +
+	"\fmenu_item_id\x18\x01 \x01(\tR\n" +
+	"menuItemId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12.\n" +
+	"\x10preparation_time\x18\x03 \x01(\x05H\x00R\x0fpreparationTime\x88\x01\x01\x12!\n" +
+	"\fis_available\x18\x04 \x01(\bR\visAvailable\x12\x18\n" +
+	"\adeleted\x18\x05 \x01(\bR\adeleted\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x13\n" +
+	"\x11_preparation_timeB&Z$gin-quickstart/proto/events;eventspbb\x06proto3"
+
+var (
+	file_events_events_proto_rawDescOnce sync.Once
+	file_events_events_proto_rawDescData []byte
+)
+
+func file_events_events_proto_rawDescGZIP() []byte {
+	file_events_events_proto_rawDescOnce.Do(func() {
+		file_events_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_events_events_proto_rawDesc), len(file_events_events_proto_rawDesc)))
+	})
+	return file_events_events_proto_rawDescData
+}
+
+var file_events_events_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
+var file_events_events_proto_goTypes = []any{
+	(*QueueEvent)(nil),            // 0: queue.events.v1.QueueEvent
+	(*QueuePositionUpdated)(nil),  // 1: queue.events.v1.QueuePositionUpdated
+	(*QueueStatusChanged)(nil),    // 2: queue.events.v1.QueueStatusChanged
+	(*QueueEntryTombstone)(nil),   // 3: queue.events.v1.QueueEntryTombstone
+	(*QueueCompleted)(nil),        // 4: queue.events.v1.QueueCompleted
+	(*QueueAdvanced)(nil),         // 5: queue.events.v1.QueueAdvanced
+	(*QueueEntryCreated)(nil),     // 6: queue.events.v1.QueueEntryCreated
+	(*QueueSlaBreached)(nil),      // 7: queue.events.v1.QueueSlaBreached
+	(*QueueDayClosed)(nil),        // 8: queue.events.v1.QueueDayClosed
+	(*NotificationEvent)(nil),     // 9: queue.events.v1.NotificationEvent
+	(*QueueAlmostReady)(nil),      // 10: queue.events.v1.QueueAlmostReady
+	(*QueueReady)(nil),            // 11: queue.events.v1.QueueReady
+	(*QueueStageReady)(nil),       // 12: queue.events.v1.QueueStageReady
+	(*QueueCancelled)(nil),        // 13: queue.events.v1.QueueCancelled
+	(*KitchenEvent)(nil),          // 14: queue.events.v1.KitchenEvent
+	(*BatchSuggested)(nil),        // 15: queue.events.v1.BatchSuggested
+	(*OrderCreated)(nil),          // 16: queue.events.v1.OrderCreated
+	(*OrderItem)(nil),             // 17: queue.events.v1.OrderItem
+	(*OrderStatusChanged)(nil),    // 18: queue.events.v1.OrderStatusChanged
+	(*OrderCancelled)(nil),        // 19: queue.events.v1.OrderCancelled
+	(*PaymentCompleted)(nil),      // 20: queue.events.v1.PaymentCompleted
+	(*MenuUpdated)(nil),           // 21: queue.events.v1.MenuUpdated
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
+}
+var file_events_events_proto_depIdxs = []int32{
+	1,  // 0: queue.events.v1.QueueEvent.position_updated:type_name -> queue.events.v1.QueuePositionUpdated
+	2,  // 1: queue.events.v1.QueueEvent.status_changed:type_name -> queue.events.v1.QueueStatusChanged
+	3,  // 2: queue.events.v1.QueueEvent.entry_tombstone:type_name -> queue.events.v1.QueueEntryTombstone
+	4,  // 3: queue.events.v1.QueueEvent.completed:type_name -> queue.events.v1.QueueCompleted
+	5,  // 4: queue.events.v1.QueueEvent.advanced:type_name -> queue.events.v1.QueueAdvanced
+	6,  // 5: queue.events.v1.QueueEvent.entry_created:type_name -> queue.events.v1.QueueEntryCreated
+	7,  // 6: queue.events.v1.QueueEvent.sla_breached:type_name -> queue.events.v1.QueueSlaBreached
+	8,  // 7: queue.events.v1.QueueEvent.day_closed:type_name -> queue.events.v1.QueueDayClosed
+	22, // 8: queue.events.v1.QueueEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	22, // 9: queue.events.v1.QueuePositionUpdated.estimated_ready_time:type_name -> google.protobuf.Timestamp
+	22, // 10: queue.events.v1.QueuePositionUpdated.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 11: queue.events.v1.QueueStatusChanged.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 12: queue.events.v1.QueueEntryTombstone.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 13: queue.events.v1.QueueCompleted.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 14: queue.events.v1.QueueAdvanced.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 15: queue.events.v1.QueueEntryCreated.estimated_ready_time:type_name -> google.protobuf.Timestamp
+	22, // 16: queue.events.v1.QueueEntryCreated.created_at:type_name -> google.protobuf.Timestamp
+	22, // 17: queue.events.v1.QueueSlaBreached.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 18: queue.events.v1.QueueDayClosed.timestamp:type_name -> google.protobuf.Timestamp
+	10, // 19: queue.events.v1.NotificationEvent.almost_ready:type_name -> queue.events.v1.QueueAlmostReady
+	11, // 20: queue.events.v1.NotificationEvent.ready:type_name -> queue.events.v1.QueueReady
+	12, // 21: queue.events.v1.NotificationEvent.stage_ready:type_name -> queue.events.v1.QueueStageReady
+	13, // 22: queue.events.v1.NotificationEvent.cancelled:type_name -> queue.events.v1.QueueCancelled
+	22, // 23: queue.events.v1.NotificationEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	22, // 24: queue.events.v1.QueueAlmostReady.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 25: queue.events.v1.QueueReady.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 26: queue.events.v1.QueueStageReady.timestamp:type_name -> google.protobuf.Timestamp
+	22, // 27: queue.events.v1.QueueCancelled.timestamp:type_name -> google.protobuf.Timestamp
+	15, // 28: queue.events.v1.KitchenEvent.batch_suggested:type_name -> queue.events.v1.BatchSuggested
+	22, // 29: queue.events.v1.KitchenEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	22, // 30: queue.events.v1.BatchSuggested.timestamp:type_name -> google.protobuf.Timestamp
+	17, // 31: queue.events.v1.OrderCreated.items:type_name -> queue.events.v1.OrderItem
+	22, // 32: queue.events.v1.OrderCreated.created_at:type_name -> google.protobuf.Timestamp
+	22, // 33: queue.events.v1.OrderStatusChanged.updated_at:type_name -> google.protobuf.Timestamp
+	22, // 34: queue.events.v1.OrderCancelled.cancelled_at:type_name -> google.protobuf.Timestamp
+	22, // 35: queue.events.v1.PaymentCompleted.completed_at:type_name -> google.protobuf.Timestamp
+	22, // 36: queue.events.v1.MenuUpdated.updated_at:type_name -> google.protobuf.Timestamp
+	37, // [37:37] is the sub-list for method output_type
+	37, // [37:37] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
+}
+
+func init() { file_events_events_proto_init() }
+func file_events_events_proto_init() {
+	if File_events_events_proto != nil {
+		return
+	}
+	file_events_events_proto_msgTypes[0].OneofWrappers = []any{
+		(*QueueEvent_PositionUpdated)(nil),
+		(*QueueEvent_StatusChanged)(nil),
+		(*QueueEvent_EntryTombstone)(nil),
+		(*QueueEvent_Completed)(nil),
+		(*QueueEvent_Advanced)(nil),
+		(*QueueEvent_EntryCreated)(nil),
+		(*QueueEvent_SlaBreached)(nil),
+		(*QueueEvent_DayClosed)(nil),
+	}
+	file_events_events_proto_msgTypes[9].OneofWrappers = []any{
+		(*NotificationEvent_AlmostReady)(nil),
+		(*NotificationEvent_Ready)(nil),
+		(*NotificationEvent_StageReady)(nil),
+		(*NotificationEvent_Cancelled)(nil),
+	}
+	file_events_events_proto_msgTypes[13].OneofWrappers = []any{}
+	file_events_events_proto_msgTypes[14].OneofWrappers = []any{
+		(*KitchenEvent_BatchSuggested)(nil),
+	}
+	file_events_events_proto_msgTypes[15].OneofWrappers = []any{}
+	file_events_events_proto_msgTypes[21].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_events_proto_rawDesc), len(file_events_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   22,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_events_events_proto_goTypes,
+		DependencyIndexes: file_events_events_proto_depIdxs,
+		MessageInfos:      file_events_events_proto_msgTypes,
+	}.Build()
+	File_events_events_proto = out.File
+	file_events_events_proto_goTypes = nil
+	file_events_events_proto_depIdxs = nil
+}
@@ -0,0 +1,6 @@
+// Package proto holds the protobuf definitions shared with other services.
+package proto
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative events/events.proto
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative --grpc-gateway_out=. --grpc-gateway_opt=paths=source_relative queue/queue.proto
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative menu/menu.proto
@@ -0,0 +1,464 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: menu/menu.proto
+
+package menupb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type MenuItem struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Category string                 `protobuf:"bytes,3,opt,name=category,proto3" json:"category,omitempty"`
+	// Preparation time in minutes
+	PreparationTime int32   `protobuf:"varint,4,opt,name=preparation_time,json=preparationTime,proto3" json:"preparation_time,omitempty"`
+	Price           float64 `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	IsAvailable     bool    `protobuf:"varint,6,opt,name=is_available,json=isAvailable,proto3" json:"is_available,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *MenuItem) Reset() {
+	*x = MenuItem{}
+	mi := &file_menu_menu_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MenuItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MenuItem) ProtoMessage() {}
+
+func (x *MenuItem) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_menu_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MenuItem.ProtoReflect.Descriptor instead.
+func (*MenuItem) Descriptor() ([]byte, []int) {
+	return file_menu_menu_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MenuItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *MenuItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MenuItem) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *MenuItem) GetPreparationTime() int32 {
+	if x != nil {
+		return x.PreparationTime
+	}
+	return 0
+}
+
+func (x *MenuItem) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *MenuItem) GetIsAvailable() bool {
+	if x != nil {
+		return x.IsAvailable
+	}
+	return false
+}
+
+type GetMenuItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemId        string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMenuItemRequest) Reset() {
+	*x = GetMenuItemRequest{}
+	mi := &file_menu_menu_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMenuItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMenuItemRequest) ProtoMessage() {}
+
+func (x *GetMenuItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_menu_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMenuItemRequest.ProtoReflect.Descriptor instead.
+func (*GetMenuItemRequest) Descriptor() ([]byte, []int) {
+	return file_menu_menu_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetMenuItemRequest) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+type GetMenuItemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemIds       []string               `protobuf:"bytes,1,rep,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMenuItemsRequest) Reset() {
+	*x = GetMenuItemsRequest{}
+	mi := &file_menu_menu_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMenuItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMenuItemsRequest) ProtoMessage() {}
+
+func (x *GetMenuItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_menu_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMenuItemsRequest.ProtoReflect.Descriptor instead.
+func (*GetMenuItemsRequest) Descriptor() ([]byte, []int) {
+	return file_menu_menu_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetMenuItemsRequest) GetItemIds() []string {
+	if x != nil {
+		return x.ItemIds
+	}
+	return nil
+}
+
+type GetMenuItemsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*MenuItem            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMenuItemsResponse) Reset() {
+	*x = GetMenuItemsResponse{}
+	mi := &file_menu_menu_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMenuItemsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMenuItemsResponse) ProtoMessage() {}
+
+func (x *GetMenuItemsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_menu_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMenuItemsResponse.ProtoReflect.Descriptor instead.
+func (*GetMenuItemsResponse) Descriptor() ([]byte, []int) {
+	return file_menu_menu_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetMenuItemsResponse) GetItems() []*MenuItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type ListMenuItemsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AvailableOnly bool                   `protobuf:"varint,1,opt,name=available_only,json=availableOnly,proto3" json:"available_only,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListMenuItemsRequest) Reset() {
+	*x = ListMenuItemsRequest{}
+	mi := &file_menu_menu_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMenuItemsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMenuItemsRequest) ProtoMessage() {}
+
+func (x *ListMenuItemsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_menu_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMenuItemsRequest.ProtoReflect.Descriptor instead.
+func (*ListMenuItemsRequest) Descriptor() ([]byte, []int) {
+	return file_menu_menu_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListMenuItemsRequest) GetAvailableOnly() bool {
+	if x != nil {
+		return x.AvailableOnly
+	}
+	return false
+}
+
+type GetAveragePreparationTimeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemIds       []string               `protobuf:"bytes,1,rep,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAveragePreparationTimeRequest) Reset() {
+	*x = GetAveragePreparationTimeRequest{}
+	mi := &file_menu_menu_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAveragePreparationTimeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAveragePreparationTimeRequest) ProtoMessage() {}
+
+func (x *GetAveragePreparationTimeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_menu_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAveragePreparationTimeRequest.ProtoReflect.Descriptor instead.
+func (*GetAveragePreparationTimeRequest) Descriptor() ([]byte, []int) {
+	return file_menu_menu_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetAveragePreparationTimeRequest) GetItemIds() []string {
+	if x != nil {
+		return x.ItemIds
+	}
+	return nil
+}
+
+type GetAveragePreparationTimeResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AverageMinutes int32                  `protobuf:"varint,1,opt,name=average_minutes,json=averageMinutes,proto3" json:"average_minutes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetAveragePreparationTimeResponse) Reset() {
+	*x = GetAveragePreparationTimeResponse{}
+	mi := &file_menu_menu_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAveragePreparationTimeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAveragePreparationTimeResponse) ProtoMessage() {}
+
+func (x *GetAveragePreparationTimeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_menu_menu_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAveragePreparationTimeResponse.ProtoReflect.Descriptor instead.
+func (*GetAveragePreparationTimeResponse) Descriptor() ([]byte, []int) {
+	return file_menu_menu_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetAveragePreparationTimeResponse) GetAverageMinutes() int32 {
+	if x != nil {
+		return x.AverageMinutes
+	}
+	return 0
+}
+
+var File_menu_menu_proto protoreflect.FileDescriptor
+
+const file_menu_menu_proto_rawDesc = "" +
+	"\n" +
+	"\x0fmenu/menu.proto\x12\amenu.v1\"\xae\x01\n" +
+	"\bMenuItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bcategory\x18\x03 \x01(\tR\bcategory\x12)\n" +
+	"\x10preparation_time\x18\x04 \x01(\x05R\x0fpreparationTime\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12!\n" +
+	"\fis_available\x18\x06 \x01(\bR\visAvailable\"-\n" +
+	"\x12GetMenuItemRequest\x12\x17\n" +
+	"\aitem_id\x18\x01 \x01(\tR\x06itemId\"0\n" +
+	"\x13GetMenuItemsRequest\x12\x19\n" +
+	"\bitem_ids\x18\x01 \x03(\tR\aitemIds\"?\n" +
+	"\x14GetMenuItemsResponse\x12'\n" +
+	"\x05items\x18\x01 \x03(\v2\x11.menu.v1.MenuItemR\x05items\"=\n" +
+	"\x14ListMenuItemsRequest\x12%\n" +
+	"\x0eavailable_only\x18\x01 \x01(\bR\ravailableOnly\"=\n" +
+	" GetAveragePreparationTimeRequest\x12\x19\n" +
+	"\bitem_ids\x18\x01 \x03(\tR\aitemIds\"L\n" +
+	"!GetAveragePreparationTimeResponse\x12'\n" +
+	"\x0faverage_minutes\x18\x01 \x01(\x05R\x0eaverageMinutes2\xdc\x02\n" +
+	"\vMenuService\x12=\n" +
+	"\vGetMenuItem\x12\x1b.menu.v1.GetMenuItemRequest\x1a\x11.menu.v1.MenuItem\x12K\n" +
+	"\fGetMenuItems\x12\x1c.menu.v1.GetMenuItemsRequest\x1a\x1d.menu.v1.GetMenuItemsResponse\x12M\n" +
+	"\rListMenuItems\x12\x1d.menu.v1.ListMenuItemsRequest\x1a\x1d.menu.v1.GetMenuItemsResponse\x12r\n" +
+	"\x19GetAveragePreparationTime\x12).menu.v1.GetAveragePreparationTimeRequest\x1a*.menu.v1.GetAveragePreparationTimeResponseB\"Z gin-quickstart/proto/menu;menupbb\x06proto3"
+
+var (
+	file_menu_menu_proto_rawDescOnce sync.Once
+	file_menu_menu_proto_rawDescData []byte
+)
+
+func file_menu_menu_proto_rawDescGZIP() []byte {
+	file_menu_menu_proto_rawDescOnce.Do(func() {
+		file_menu_menu_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_menu_menu_proto_rawDesc), len(file_menu_menu_proto_rawDesc)))
+	})
+	return file_menu_menu_proto_rawDescData
+}
+
+var file_menu_menu_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_menu_menu_proto_goTypes = []any{
+	(*MenuItem)(nil),                          // 0: menu.v1.MenuItem
+	(*GetMenuItemRequest)(nil),                // 1: menu.v1.GetMenuItemRequest
+	(*GetMenuItemsRequest)(nil),               // 2: menu.v1.GetMenuItemsRequest
+	(*GetMenuItemsResponse)(nil),              // 3: menu.v1.GetMenuItemsResponse
+	(*ListMenuItemsRequest)(nil),              // 4: menu.v1.ListMenuItemsRequest
+	(*GetAveragePreparationTimeRequest)(nil),  // 5: menu.v1.GetAveragePreparationTimeRequest
+	(*GetAveragePreparationTimeResponse)(nil), // 6: menu.v1.GetAveragePreparationTimeResponse
+}
+var file_menu_menu_proto_depIdxs = []int32{
+	0, // 0: menu.v1.GetMenuItemsResponse.items:type_name -> menu.v1.MenuItem
+	1, // 1: menu.v1.MenuService.GetMenuItem:input_type -> menu.v1.GetMenuItemRequest
+	2, // 2: menu.v1.MenuService.GetMenuItems:input_type -> menu.v1.GetMenuItemsRequest
+	4, // 3: menu.v1.MenuService.ListMenuItems:input_type -> menu.v1.ListMenuItemsRequest
+	5, // 4: menu.v1.MenuService.GetAveragePreparationTime:input_type -> menu.v1.GetAveragePreparationTimeRequest
+	0, // 5: menu.v1.MenuService.GetMenuItem:output_type -> menu.v1.MenuItem
+	3, // 6: menu.v1.MenuService.GetMenuItems:output_type -> menu.v1.GetMenuItemsResponse
+	3, // 7: menu.v1.MenuService.ListMenuItems:output_type -> menu.v1.GetMenuItemsResponse
+	6, // 8: menu.v1.MenuService.GetAveragePreparationTime:output_type -> menu.v1.GetAveragePreparationTimeResponse
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_menu_menu_proto_init() }
+func file_menu_menu_proto_init() {
+	if File_menu_menu_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_menu_menu_proto_rawDesc), len(file_menu_menu_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_menu_menu_proto_goTypes,
+		DependencyIndexes: file_menu_menu_proto_depIdxs,
+		MessageInfos:      file_menu_menu_proto_msgTypes,
+	}.Build()
+	File_menu_menu_proto = out.File
+	file_menu_menu_proto_goTypes = nil
+	file_menu_menu_proto_depIdxs = nil
+}
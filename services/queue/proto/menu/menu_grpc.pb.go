@@ -0,0 +1,243 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: menu/menu.proto
+
+package menupb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MenuService_GetMenuItem_FullMethodName               = "/menu.v1.MenuService/GetMenuItem"
+	MenuService_GetMenuItems_FullMethodName              = "/menu.v1.MenuService/GetMenuItems"
+	MenuService_ListMenuItems_FullMethodName             = "/menu.v1.MenuService/ListMenuItems"
+	MenuService_GetAveragePreparationTime_FullMethodName = "/menu.v1.MenuService/GetAveragePreparationTime"
+)
+
+// MenuServiceClient is the client API for MenuService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// MenuService is served by the Menu Service; the queue service is a client
+type MenuServiceClient interface {
+	GetMenuItem(ctx context.Context, in *GetMenuItemRequest, opts ...grpc.CallOption) (*MenuItem, error)
+	GetMenuItems(ctx context.Context, in *GetMenuItemsRequest, opts ...grpc.CallOption) (*GetMenuItemsResponse, error)
+	// ListMenuItems returns the whole menu, e.g. to warm caches
+	ListMenuItems(ctx context.Context, in *ListMenuItemsRequest, opts ...grpc.CallOption) (*GetMenuItemsResponse, error)
+	// GetAveragePreparationTime averages the preparation time of the items, in minutes
+	GetAveragePreparationTime(ctx context.Context, in *GetAveragePreparationTimeRequest, opts ...grpc.CallOption) (*GetAveragePreparationTimeResponse, error)
+}
+
+type menuServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMenuServiceClient(cc grpc.ClientConnInterface) MenuServiceClient {
+	return &menuServiceClient{cc}
+}
+
+func (c *menuServiceClient) GetMenuItem(ctx context.Context, in *GetMenuItemRequest, opts ...grpc.CallOption) (*MenuItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MenuItem)
+	err := c.cc.Invoke(ctx, MenuService_GetMenuItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *menuServiceClient) GetMenuItems(ctx context.Context, in *GetMenuItemsRequest, opts ...grpc.CallOption) (*GetMenuItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMenuItemsResponse)
+	err := c.cc.Invoke(ctx, MenuService_GetMenuItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *menuServiceClient) ListMenuItems(ctx context.Context, in *ListMenuItemsRequest, opts ...grpc.CallOption) (*GetMenuItemsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMenuItemsResponse)
+	err := c.cc.Invoke(ctx, MenuService_ListMenuItems_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *menuServiceClient) GetAveragePreparationTime(ctx context.Context, in *GetAveragePreparationTimeRequest, opts ...grpc.CallOption) (*GetAveragePreparationTimeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAveragePreparationTimeResponse)
+	err := c.cc.Invoke(ctx, MenuService_GetAveragePreparationTime_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MenuServiceServer is the server API for MenuService service.
+// All implementations must embed UnimplementedMenuServiceServer
+// for forward compatibility.
+//
+// MenuService is served by the Menu Service; the queue service is a client
+type MenuServiceServer interface {
+	GetMenuItem(context.Context, *GetMenuItemRequest) (*MenuItem, error)
+	GetMenuItems(context.Context, *GetMenuItemsRequest) (*GetMenuItemsResponse, error)
+	// ListMenuItems returns the whole menu, e.g. to warm caches
+	ListMenuItems(context.Context, *ListMenuItemsRequest) (*GetMenuItemsResponse, error)
+	// GetAveragePreparationTime averages the preparation time of the items, in minutes
+	GetAveragePreparationTime(context.Context, *GetAveragePreparationTimeRequest) (*GetAveragePreparationTimeResponse, error)
+	mustEmbedUnimplementedMenuServiceServer()
+}
+
+// UnimplementedMenuServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMenuServiceServer struct{}
+
+func (UnimplementedMenuServiceServer) GetMenuItem(context.Context, *GetMenuItemRequest) (*MenuItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMenuItem not implemented")
+}
+func (UnimplementedMenuServiceServer) GetMenuItems(context.Context, *GetMenuItemsRequest) (*GetMenuItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMenuItems not implemented")
+}
+func (UnimplementedMenuServiceServer) ListMenuItems(context.Context, *ListMenuItemsRequest) (*GetMenuItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMenuItems not implemented")
+}
+func (UnimplementedMenuServiceServer) GetAveragePreparationTime(context.Context, *GetAveragePreparationTimeRequest) (*GetAveragePreparationTimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAveragePreparationTime not implemented")
+}
+func (UnimplementedMenuServiceServer) mustEmbedUnimplementedMenuServiceServer() {}
+func (UnimplementedMenuServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeMenuServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MenuServiceServer will
+// result in compilation errors.
+type UnsafeMenuServiceServer interface {
+	mustEmbedUnimplementedMenuServiceServer()
+}
+
+func RegisterMenuServiceServer(s grpc.ServiceRegistrar, srv MenuServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMenuServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MenuService_ServiceDesc, srv)
+}
+
+func _MenuService_GetMenuItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMenuItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).GetMenuItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_GetMenuItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).GetMenuItem(ctx, req.(*GetMenuItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MenuService_GetMenuItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMenuItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).GetMenuItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_GetMenuItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).GetMenuItems(ctx, req.(*GetMenuItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MenuService_ListMenuItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMenuItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).ListMenuItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_ListMenuItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).ListMenuItems(ctx, req.(*ListMenuItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MenuService_GetAveragePreparationTime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAveragePreparationTimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MenuServiceServer).GetAveragePreparationTime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MenuService_GetAveragePreparationTime_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MenuServiceServer).GetAveragePreparationTime(ctx, req.(*GetAveragePreparationTimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MenuService_ServiceDesc is the grpc.ServiceDesc for MenuService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MenuService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "menu.v1.MenuService",
+	HandlerType: (*MenuServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMenuItem",
+			Handler:    _MenuService_GetMenuItem_Handler,
+		},
+		{
+			MethodName: "GetMenuItems",
+			Handler:    _MenuService_GetMenuItems_Handler,
+		},
+		{
+			MethodName: "ListMenuItems",
+			Handler:    _MenuService_ListMenuItems_Handler,
+		},
+		{
+			MethodName: "GetAveragePreparationTime",
+			Handler:    _MenuService_GetAveragePreparationTime_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "menu/menu.proto",
+}
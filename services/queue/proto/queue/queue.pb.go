@@ -0,0 +1,975 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: queue/queue.proto
+
+package queuepb
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type QueueEntry struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId            string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	LocationId         string                 `protobuf:"bytes,3,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	UserId             string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	TokenNumber        string                 `protobuf:"bytes,5,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	TokenType          string                 `protobuf:"bytes,6,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	Status             string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Priority           string                 `protobuf:"bytes,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	Position           int32                  `protobuf:"varint,9,opt,name=position,proto3" json:"position,omitempty"`
+	EstimatedWaitTime  int32                  `protobuf:"varint,10,opt,name=estimated_wait_time,json=estimatedWaitTime,proto3" json:"estimated_wait_time,omitempty"`
+	EstimatedReadyTime *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=estimated_ready_time,json=estimatedReadyTime,proto3" json:"estimated_ready_time,omitempty"`
+	IsExpressQueue     bool                   `protobuf:"varint,12,opt,name=is_express_queue,json=isExpressQueue,proto3" json:"is_express_queue,omitempty"`
+	AssignedCounter    *string                `protobuf:"bytes,13,opt,name=assigned_counter,json=assignedCounter,proto3,oneof" json:"assigned_counter,omitempty"`
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt          *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *QueueEntry) Reset() {
+	*x = QueueEntry{}
+	mi := &file_queue_queue_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueEntry) ProtoMessage() {}
+
+func (x *QueueEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_queue_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueEntry.ProtoReflect.Descriptor instead.
+func (*QueueEntry) Descriptor() ([]byte, []int) {
+	return file_queue_queue_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QueueEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *QueueEntry) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *QueueEntry) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
+func (x *QueueEntry) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *QueueEntry) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+func (x *QueueEntry) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *QueueEntry) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *QueueEntry) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *QueueEntry) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *QueueEntry) GetEstimatedWaitTime() int32 {
+	if x != nil {
+		return x.EstimatedWaitTime
+	}
+	return 0
+}
+
+func (x *QueueEntry) GetEstimatedReadyTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedReadyTime
+	}
+	return nil
+}
+
+func (x *QueueEntry) GetIsExpressQueue() bool {
+	if x != nil {
+		return x.IsExpressQueue
+	}
+	return false
+}
+
+func (x *QueueEntry) GetAssignedCounter() string {
+	if x != nil && x.AssignedCounter != nil {
+		return *x.AssignedCounter
+	}
+	return ""
+}
+
+func (x *QueueEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *QueueEntry) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type QueueItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MenuItemId    string                 `protobuf:"bytes,1,opt,name=menu_item_id,json=menuItemId,proto3" json:"menu_item_id,omitempty"`
+	ItemName      string                 `protobuf:"bytes,2,opt,name=item_name,json=itemName,proto3" json:"item_name,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueueItem) Reset() {
+	*x = QueueItem{}
+	mi := &file_queue_queue_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueueItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueueItem) ProtoMessage() {}
+
+func (x *QueueItem) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_queue_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueueItem.ProtoReflect.Descriptor instead.
+func (*QueueItem) Descriptor() ([]byte, []int) {
+	return file_queue_queue_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *QueueItem) GetMenuItemId() string {
+	if x != nil {
+		return x.MenuItemId
+	}
+	return ""
+}
+
+func (x *QueueItem) GetItemName() string {
+	if x != nil {
+		return x.ItemName
+	}
+	return ""
+}
+
+func (x *QueueItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type CreateQueueEntryRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	OrderId         string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	LocationId      string                 `protobuf:"bytes,3,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	UserName        string                 `protobuf:"bytes,4,opt,name=user_name,json=userName,proto3" json:"user_name,omitempty"`
+	UserPhone       string                 `protobuf:"bytes,5,opt,name=user_phone,json=userPhone,proto3" json:"user_phone,omitempty"`
+	TokenType       string                 `protobuf:"bytes,6,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	Priority        string                 `protobuf:"bytes,7,opt,name=priority,proto3" json:"priority,omitempty"`
+	IsExpressQueue  bool                   `protobuf:"varint,8,opt,name=is_express_queue,json=isExpressQueue,proto3" json:"is_express_queue,omitempty"`
+	SpecialHandling string                 `protobuf:"bytes,9,opt,name=special_handling,json=specialHandling,proto3" json:"special_handling,omitempty"`
+	ItemCount       int32                  `protobuf:"varint,10,opt,name=item_count,json=itemCount,proto3" json:"item_count,omitempty"`
+	Items           []*QueueItem           `protobuf:"bytes,11,rep,name=items,proto3" json:"items,omitempty"`
+	AwaitingPayment bool                   `protobuf:"varint,12,opt,name=awaiting_payment,json=awaitingPayment,proto3" json:"awaiting_payment,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateQueueEntryRequest) Reset() {
+	*x = CreateQueueEntryRequest{}
+	mi := &file_queue_queue_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateQueueEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateQueueEntryRequest) ProtoMessage() {}
+
+func (x *CreateQueueEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_queue_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateQueueEntryRequest.ProtoReflect.Descriptor instead.
+func (*CreateQueueEntryRequest) Descriptor() ([]byte, []int) {
+	return file_queue_queue_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateQueueEntryRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CreateQueueEntryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateQueueEntryRequest) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
+func (x *CreateQueueEntryRequest) GetUserName() string {
+	if x != nil {
+		return x.UserName
+	}
+	return ""
+}
+
+func (x *CreateQueueEntryRequest) GetUserPhone() string {
+	if x != nil {
+		return x.UserPhone
+	}
+	return ""
+}
+
+func (x *CreateQueueEntryRequest) GetTokenType() string {
+	if x != nil {
+		return x.TokenType
+	}
+	return ""
+}
+
+func (x *CreateQueueEntryRequest) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *CreateQueueEntryRequest) GetIsExpressQueue() bool {
+	if x != nil {
+		return x.IsExpressQueue
+	}
+	return false
+}
+
+func (x *CreateQueueEntryRequest) GetSpecialHandling() string {
+	if x != nil {
+		return x.SpecialHandling
+	}
+	return ""
+}
+
+func (x *CreateQueueEntryRequest) GetItemCount() int32 {
+	if x != nil {
+		return x.ItemCount
+	}
+	return 0
+}
+
+func (x *CreateQueueEntryRequest) GetItems() []*QueueItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CreateQueueEntryRequest) GetAwaitingPayment() bool {
+	if x != nil {
+		return x.AwaitingPayment
+	}
+	return false
+}
+
+type CreateQueueEntryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Entry *QueueEntry            `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	// created is false when the order was already queued
+	Created       bool `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateQueueEntryResponse) Reset() {
+	*x = CreateQueueEntryResponse{}
+	mi := &file_queue_queue_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateQueueEntryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateQueueEntryResponse) ProtoMessage() {}
+
+func (x *CreateQueueEntryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_queue_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateQueueEntryResponse.ProtoReflect.Descriptor instead.
+func (*CreateQueueEntryResponse) Descriptor() ([]byte, []int) {
+	return file_queue_queue_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateQueueEntryResponse) GetEntry() *QueueEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *CreateQueueEntryResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+type GetPositionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TokenNumber   string                 `protobuf:"bytes,1,opt,name=token_number,json=tokenNumber,proto3" json:"token_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPositionRequest) Reset() {
+	*x = GetPositionRequest{}
+	mi := &file_queue_queue_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPositionRequest) ProtoMessage() {}
+
+func (x *GetPositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_queue_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPositionRequest.ProtoReflect.Descriptor instead.
+func (*GetPositionRequest) Descriptor() ([]byte, []int) {
+	return file_queue_queue_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPositionRequest) GetTokenNumber() string {
+	if x != nil {
+		return x.TokenNumber
+	}
+	return ""
+}
+
+type GetPositionResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Entry              *QueueEntry            `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	Position           int32                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	EstimatedWaitTime  int32                  `protobuf:"varint,3,opt,name=estimated_wait_time,json=estimatedWaitTime,proto3" json:"estimated_wait_time,omitempty"`
+	EstimatedReadyTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=estimated_ready_time,json=estimatedReadyTime,proto3" json:"estimated_ready_time,omitempty"`
+	PeopleAhead        int32                  `protobuf:"varint,5,opt,name=people_ahead,json=peopleAhead,proto3" json:"people_ahead,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *GetPositionResponse) Reset() {
+	*x = GetPositionResponse{}
+	mi := &file_queue_queue_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPositionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPositionResponse) ProtoMessage() {}
+
+func (x *GetPositionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_queue_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPositionResponse.ProtoReflect.Descriptor instead.
+func (*GetPositionResponse) Descriptor() ([]byte, []int) {
+	return file_queue_queue_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetPositionResponse) GetEntry() *QueueEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *GetPositionResponse) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *GetPositionResponse) GetEstimatedWaitTime() int32 {
+	if x != nil {
+		return x.EstimatedWaitTime
+	}
+	return 0
+}
+
+func (x *GetPositionResponse) GetEstimatedReadyTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EstimatedReadyTime
+	}
+	return nil
+}
+
+func (x *GetPositionResponse) GetPeopleAhead() int32 {
+	if x != nil {
+		return x.PeopleAhead
+	}
+	return 0
+}
+
+type UpdateStatusRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	EntryId         string                 `protobuf:"bytes,1,opt,name=entry_id,json=entryId,proto3" json:"entry_id,omitempty"`
+	Status          string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	AssignedCounter *string                `protobuf:"bytes,3,opt,name=assigned_counter,json=assignedCounter,proto3,oneof" json:"assigned_counter,omitempty"`
+	AssignedStaff   *string                `protobuf:"bytes,4,opt,name=assigned_staff,json=assignedStaff,proto3,oneof" json:"assigned_staff,omitempty"`
+	Notes           *string                `protobuf:"bytes,5,opt,name=notes,proto3,oneof" json:"notes,omitempty"`
+	Reason          *string                `protobuf:"bytes,6,opt,name=reason,proto3,oneof" json:"reason,omitempty"`
+	// Recorded in the staff action log; defaults to the calling service
+	ActorId       string `protobuf:"bytes,7,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorName     string `protobuf:"bytes,8,opt,name=actor_name,json=actorName,proto3" json:"actor_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStatusRequest) Reset() {
+	*x = UpdateStatusRequest{}
+	mi := &file_queue_queue_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStatusRequest) ProtoMessage() {}
+
+func (x *UpdateStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_queue_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateStatusRequest) Descriptor() ([]byte, []int) {
+	return file_queue_queue_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateStatusRequest) GetEntryId() string {
+	if x != nil {
+		return x.EntryId
+	}
+	return ""
+}
+
+func (x *UpdateStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateStatusRequest) GetAssignedCounter() string {
+	if x != nil && x.AssignedCounter != nil {
+		return *x.AssignedCounter
+	}
+	return ""
+}
+
+func (x *UpdateStatusRequest) GetAssignedStaff() string {
+	if x != nil && x.AssignedStaff != nil {
+		return *x.AssignedStaff
+	}
+	return ""
+}
+
+func (x *UpdateStatusRequest) GetNotes() string {
+	if x != nil && x.Notes != nil {
+		return *x.Notes
+	}
+	return ""
+}
+
+func (x *UpdateStatusRequest) GetReason() string {
+	if x != nil && x.Reason != nil {
+		return *x.Reason
+	}
+	return ""
+}
+
+func (x *UpdateStatusRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *UpdateStatusRequest) GetActorName() string {
+	if x != nil {
+		return x.ActorName
+	}
+	return ""
+}
+
+type UpdateStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *QueueEntry            `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateStatusResponse) Reset() {
+	*x = UpdateStatusResponse{}
+	mi := &file_queue_queue_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateStatusResponse) ProtoMessage() {}
+
+func (x *UpdateStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_queue_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateStatusResponse) Descriptor() ([]byte, []int) {
+	return file_queue_queue_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateStatusResponse) GetEntry() *QueueEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+type GetCurrentQueueRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Location to read; empty reads the default location
+	LocationId    string `protobuf:"bytes,1,opt,name=location_id,json=locationId,proto3" json:"location_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentQueueRequest) Reset() {
+	*x = GetCurrentQueueRequest{}
+	mi := &file_queue_queue_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentQueueRequest) ProtoMessage() {}
+
+func (x *GetCurrentQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_queue_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentQueueRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrentQueueRequest) Descriptor() ([]byte, []int) {
+	return file_queue_queue_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetCurrentQueueRequest) GetLocationId() string {
+	if x != nil {
+		return x.LocationId
+	}
+	return ""
+}
+
+type GetCurrentQueueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Waiting       []*QueueEntry          `protobuf:"bytes,1,rep,name=waiting,proto3" json:"waiting,omitempty"`
+	InProgress    []*QueueEntry          `protobuf:"bytes,2,rep,name=in_progress,json=inProgress,proto3" json:"in_progress,omitempty"`
+	Ready         []*QueueEntry          `protobuf:"bytes,3,rep,name=ready,proto3" json:"ready,omitempty"`
+	TotalActive   int32                  `protobuf:"varint,4,opt,name=total_active,json=totalActive,proto3" json:"total_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentQueueResponse) Reset() {
+	*x = GetCurrentQueueResponse{}
+	mi := &file_queue_queue_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentQueueResponse) ProtoMessage() {}
+
+func (x *GetCurrentQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_queue_queue_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentQueueResponse.ProtoReflect.Descriptor instead.
+func (*GetCurrentQueueResponse) Descriptor() ([]byte, []int) {
+	return file_queue_queue_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetCurrentQueueResponse) GetWaiting() []*QueueEntry {
+	if x != nil {
+		return x.Waiting
+	}
+	return nil
+}
+
+func (x *GetCurrentQueueResponse) GetInProgress() []*QueueEntry {
+	if x != nil {
+		return x.InProgress
+	}
+	return nil
+}
+
+func (x *GetCurrentQueueResponse) GetReady() []*QueueEntry {
+	if x != nil {
+		return x.Ready
+	}
+	return nil
+}
+
+func (x *GetCurrentQueueResponse) GetTotalActive() int32 {
+	if x != nil {
+		return x.TotalActive
+	}
+	return 0
+}
+
+var File_queue_queue_proto protoreflect.FileDescriptor
+
+const file_queue_queue_proto_rawDesc = "" +
+	"\n" +
+	"\x11queue/queue.proto\x12\bqueue.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe6\x04\n" +
+	"\n" +
+	"QueueEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1f\n" +
+	"\vlocation_id\x18\x03 \x01(\tR\n" +
+	"locationId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\ftoken_number\x18\x05 \x01(\tR\vtokenNumber\x12\x1d\n" +
+	"\n" +
+	"token_type\x18\x06 \x01(\tR\ttokenType\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\tR\bpriority\x12\x1a\n" +
+	"\bposition\x18\t \x01(\x05R\bposition\x12.\n" +
+	"\x13estimated_wait_time\x18\n" +
+	" \x01(\x05R\x11estimatedWaitTime\x12L\n" +
+	"\x14estimated_ready_time\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x12estimatedReadyTime\x12(\n" +
+	"\x10is_express_queue\x18\f \x01(\bR\x0eisExpressQueue\x12.\n" +
+	"\x10assigned_counter\x18\r \x01(\tH\x00R\x0fassignedCounter\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x13\n" +
+	"\x11_assigned_counter\"f\n" +
+	"\tQueueItem\x12 \n" +
+	"\fmenu_item_id\x18\x01 \x01(\tR\n" +
+	"menuItemId\x12\x1b\n" +
+	"\titem_name\x18\x02 \x01(\tR\bitemName\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"\xaf\x03\n" +
+	"\x17CreateQueueEntryRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vlocation_id\x18\x03 \x01(\tR\n" +
+	"locationId\x12\x1b\n" +
+	"\tuser_name\x18\x04 \x01(\tR\buserName\x12\x1d\n" +
+	"\n" +
+	"user_phone\x18\x05 \x01(\tR\tuserPhone\x12\x1d\n" +
+	"\n" +
+	"token_type\x18\x06 \x01(\tR\ttokenType\x12\x1a\n" +
+	"\bpriority\x18\a \x01(\tR\bpriority\x12(\n" +
+	"\x10is_express_queue\x18\b \x01(\bR\x0eisExpressQueue\x12)\n" +
+	"\x10special_handling\x18\t \x01(\tR\x0fspecialHandling\x12\x1d\n" +
+	"\n" +
+	"item_count\x18\n" +
+	" \x01(\x05R\titemCount\x12)\n" +
+	"\x05items\x18\v \x03(\v2\x13.queue.v1.QueueItemR\x05items\x12)\n" +
+	"\x10awaiting_payment\x18\f \x01(\bR\x0fawaitingPayment\"`\n" +
+	"\x18CreateQueueEntryResponse\x12*\n" +
+	"\x05entry\x18\x01 \x01(\v2\x14.queue.v1.QueueEntryR\x05entry\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\"7\n" +
+	"\x12GetPositionRequest\x12!\n" +
+	"\ftoken_number\x18\x01 \x01(\tR\vtokenNumber\"\xfe\x01\n" +
+	"\x13GetPositionResponse\x12*\n" +
+	"\x05entry\x18\x01 \x01(\v2\x14.queue.v1.QueueEntryR\x05entry\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\x05R\bposition\x12.\n" +
+	"\x13estimated_wait_time\x18\x03 \x01(\x05R\x11estimatedWaitTime\x12L\n" +
+	"\x14estimated_ready_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x12estimatedReadyTime\x12!\n" +
+	"\fpeople_ahead\x18\x05 \x01(\x05R\vpeopleAhead\"\xd3\x02\n" +
+	"\x13UpdateStatusRequest\x12\x19\n" +
+	"\bentry_id\x18\x01 \x01(\tR\aentryId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12.\n" +
+	"\x10assigned_counter\x18\x03 \x01(\tH\x00R\x0fassignedCounter\x88\x01\x01\x12*\n" +
+	"\x0eassigned_staff\x18\x04 \x01(\tH\x01R\rassignedStaff\x88\x01\x01\x12\x19\n" +
+	"\x05notes\x18\x05 \x01(\tH\x02R\x05notes\x88\x01\x01\x12\x1b\n" +
+	"\x06reason\x18\x06 \x01(\tH\x03R\x06reason\x88\x01\x01\x12\x19\n" +
+	"\bactor_id\x18\a \x01(\tR\aactorId\x12\x1d\n" +
+	"\n" +
+	"actor_name\x18\b \x01(\tR\tactorNameB\x13\n" +
+	"\x11_assigned_counterB\x11\n" +
+	"\x0f_assigned_staffB\b\n" +
+	"\x06_notesB\t\n" +
+	"\a_reason\"B\n" +
+	"\x14UpdateStatusResponse\x12*\n" +
+	"\x05entry\x18\x01 \x01(\v2\x14.queue.v1.QueueEntryR\x05entry\"9\n" +
+	"\x16GetCurrentQueueRequest\x12\x1f\n" +
+	"\vlocation_id\x18\x01 \x01(\tR\n" +
+	"locationId\"\xcf\x01\n" +
+	"\x17GetCurrentQueueResponse\x12.\n" +
+	"\awaiting\x18\x01 \x03(\v2\x14.queue.v1.QueueEntryR\awaiting\x125\n" +
+	"\vin_progress\x18\x02 \x03(\v2\x14.queue.v1.QueueEntryR\n" +
+	"inProgress\x12*\n" +
+	"\x05ready\x18\x03 \x03(\v2\x14.queue.v1.QueueEntryR\x05ready\x12!\n" +
+	"\ftotal_active\x18\x04 \x01(\x05R\vtotalActive2\xf0\x03\n" +
+	"\fQueueService\x12w\n" +
+	"\x10CreateQueueEntry\x12!.queue.v1.CreateQueueEntryRequest\x1a\".queue.v1.CreateQueueEntryResponse\"\x1c\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/queue/entries\x12u\n" +
+	"\vGetPosition\x12\x1c.queue.v1.GetPositionRequest\x1a\x1d.queue.v1.GetPositionResponse\")\x82\xd3\xe4\x93\x02#\x12!/v1/queue/position/{token_number}\x12}\n" +
+	"\fUpdateStatus\x12\x1d.queue.v1.UpdateStatusRequest\x1a\x1e.queue.v1.UpdateStatusResponse\".\x82\xd3\xe4\x93\x02(:\x01*2#/v1/queue/entries/{entry_id}/status\x12q\n" +
+	"\x0fGetCurrentQueue\x12 .queue.v1.GetCurrentQueueRequest\x1a!.queue.v1.GetCurrentQueueResponse\"\x19\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/queue/currentB$Z\"gin-quickstart/proto/queue;queuepbb\x06proto3"
+
+var (
+	file_queue_queue_proto_rawDescOnce sync.Once
+	file_queue_queue_proto_rawDescData []byte
+)
+
+func file_queue_queue_proto_rawDescGZIP() []byte {
+	file_queue_queue_proto_rawDescOnce.Do(func() {
+		file_queue_queue_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_queue_queue_proto_rawDesc), len(file_queue_queue_proto_rawDesc)))
+	})
+	return file_queue_queue_proto_rawDescData
+}
+
+var file_queue_queue_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_queue_queue_proto_goTypes = []any{
+	(*QueueEntry)(nil),               // 0: queue.v1.QueueEntry
+	(*QueueItem)(nil),                // 1: queue.v1.QueueItem
+	(*CreateQueueEntryRequest)(nil),  // 2: queue.v1.CreateQueueEntryRequest
+	(*CreateQueueEntryResponse)(nil), // 3: queue.v1.CreateQueueEntryResponse
+	(*GetPositionRequest)(nil),       // 4: queue.v1.GetPositionRequest
+	(*GetPositionResponse)(nil),      // 5: queue.v1.GetPositionResponse
+	(*UpdateStatusRequest)(nil),      // 6: queue.v1.UpdateStatusRequest
+	(*UpdateStatusResponse)(nil),     // 7: queue.v1.UpdateStatusResponse
+	(*GetCurrentQueueRequest)(nil),   // 8: queue.v1.GetCurrentQueueRequest
+	(*GetCurrentQueueResponse)(nil),  // 9: queue.v1.GetCurrentQueueResponse
+	(*timestamppb.Timestamp)(nil),    // 10: google.protobuf.Timestamp
+}
+var file_queue_queue_proto_depIdxs = []int32{
+	10, // 0: queue.v1.QueueEntry.estimated_ready_time:type_name -> google.protobuf.Timestamp
+	10, // 1: queue.v1.QueueEntry.created_at:type_name -> google.protobuf.Timestamp
+	10, // 2: queue.v1.QueueEntry.updated_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: queue.v1.CreateQueueEntryRequest.items:type_name -> queue.v1.QueueItem
+	0,  // 4: queue.v1.CreateQueueEntryResponse.entry:type_name -> queue.v1.QueueEntry
+	0,  // 5: queue.v1.GetPositionResponse.entry:type_name -> queue.v1.QueueEntry
+	10, // 6: queue.v1.GetPositionResponse.estimated_ready_time:type_name -> google.protobuf.Timestamp
+	0,  // 7: queue.v1.UpdateStatusResponse.entry:type_name -> queue.v1.QueueEntry
+	0,  // 8: queue.v1.GetCurrentQueueResponse.waiting:type_name -> queue.v1.QueueEntry
+	0,  // 9: queue.v1.GetCurrentQueueResponse.in_progress:type_name -> queue.v1.QueueEntry
+	0,  // 10: queue.v1.GetCurrentQueueResponse.ready:type_name -> queue.v1.QueueEntry
+	2,  // 11: queue.v1.QueueService.CreateQueueEntry:input_type -> queue.v1.CreateQueueEntryRequest
+	4,  // 12: queue.v1.QueueService.GetPosition:input_type -> queue.v1.GetPositionRequest
+	6,  // 13: queue.v1.QueueService.UpdateStatus:input_type -> queue.v1.UpdateStatusRequest
+	8,  // 14: queue.v1.QueueService.GetCurrentQueue:input_type -> queue.v1.GetCurrentQueueRequest
+	3,  // 15: queue.v1.QueueService.CreateQueueEntry:output_type -> queue.v1.CreateQueueEntryResponse
+	5,  // 16: queue.v1.QueueService.GetPosition:output_type -> queue.v1.GetPositionResponse
+	7,  // 17: queue.v1.QueueService.UpdateStatus:output_type -> queue.v1.UpdateStatusResponse
+	9,  // 18: queue.v1.QueueService.GetCurrentQueue:output_type -> queue.v1.GetCurrentQueueResponse
+	15, // [15:19] is the sub-list for method output_type
+	11, // [11:15] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
+}
+
+func init() { file_queue_queue_proto_init() }
+func file_queue_queue_proto_init() {
+	if File_queue_queue_proto != nil {
+		return
+	}
+	file_queue_queue_proto_msgTypes[0].OneofWrappers = []any{}
+	file_queue_queue_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_queue_queue_proto_rawDesc), len(file_queue_queue_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_queue_queue_proto_goTypes,
+		DependencyIndexes: file_queue_queue_proto_depIdxs,
+		MessageInfos:      file_queue_queue_proto_msgTypes,
+	}.Build()
+	File_queue_queue_proto = out.File
+	file_queue_queue_proto_goTypes = nil
+	file_queue_queue_proto_depIdxs = nil
+}
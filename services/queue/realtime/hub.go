@@ -0,0 +1,244 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"gin-quickstart/models"
+)
+
+// Message types delivered to connected clients
+const (
+	MessageQueueUpdate = "queue.update"
+	MessageQueueStats  = "queue.stats"
+)
+
+// clientBuffer is how many messages a client may fall behind before it is dropped
+const clientBuffer = 64
+
+// Message is one update for a connected client
+type Message struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Filter selects which messages a client receives
+type Filter struct {
+	// Token limits queue updates to one token number; clients following a
+	// token receive no stats
+	Token string
+	// Role is the authenticated role; only staff and admins see personal details
+	Role string
+	// Location limits updates and stats to one location's queue
+	Location string
+}
+
+func (f Filter) privileged() bool {
+	return f.Role == "staff" || f.Role == "admin"
+}
+
+// Client is a connected WebSocket or SSE client
+type Client struct {
+	filter Filter
+	send   chan Message
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Messages delivers the client's updates; it is closed when the hub drops the client
+func (c *Client) Messages() <-chan Message {
+	return c.send
+}
+
+// Done is closed when the hub drops the client (it fell too far behind)
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Client) drop() {
+	c.once.Do(func() { close(c.done) })
+}
+
+// Hub subscribes once per instance to queue updates and stats and fans them out
+// to the clients connected to this instance. Every instance receives every
+// update, so clients can connect to any replica.
+type Hub struct {
+	rs *RealtimeService
+
+	mu      sync.RWMutex
+	clients map[*Client]struct{}
+}
+
+var defaultHub *Hub
+
+// SetHub sets the hub that serves WebSocket and SSE clients
+func SetHub(hub *Hub) {
+	defaultHub = hub
+}
+
+// GetHub returns the hub, or nil when real-time updates are not running
+func GetHub() *Hub {
+	return defaultHub
+}
+
+// NewHub creates a hub; Run starts its subscriptions
+func NewHub() *Hub {
+	return &Hub{
+		rs:      NewRealtimeService(),
+		clients: make(map[*Client]struct{}),
+	}
+}
+
+// Run subscribes to updates and stats until ctx is cancelled, resubscribing
+// after Redis errors
+func (h *Hub) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		h.keepSubscribed(ctx, "queue updates", func() error {
+			if transport == TransportStreams {
+				// A group per instance, so every instance sees every update
+				consumer := streamConsumerName()
+				return h.rs.SubscribeQueueUpdatesFrom(ctx, streamGroup+":"+consumer, consumer, h.broadcastEntry)
+			}
+			return h.rs.SubscribeQueueUpdates(ctx, h.broadcastEntry)
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		h.keepSubscribed(ctx, "queue stats", func() error {
+			return h.rs.SubscribeQueueStats(ctx, h.broadcastStats)
+		})
+	}()
+	wg.Wait()
+}
+
+func (h *Hub) keepSubscribed(ctx context.Context, name string, subscribe func() error) {
+	for {
+		err := subscribe()
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Subscription to %s ended, retrying: %v", name, err)
+
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Register connects a client
+func (h *Hub) Register(filter Filter) *Client {
+	client := &Client{
+		filter: filter,
+		send:   make(chan Message, clientBuffer),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	h.clients[client] = struct{}{}
+	h.mu.Unlock()
+	return client
+}
+
+// Unregister disconnects a client
+func (h *Hub) Unregister(client *Client) {
+	h.mu.Lock()
+	delete(h.clients, client)
+	h.mu.Unlock()
+	client.drop()
+}
+
+// ClientCount returns the number of clients connected to this instance
+func (h *Hub) ClientCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+func (h *Hub) broadcastEntry(entry *models.QueueEntry) {
+	full, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Failed to encode queue update: %v", err)
+		return
+	}
+	public, err := json.Marshal(redactEntry(entry))
+	if err != nil {
+		log.Printf("Failed to encode queue update: %v", err)
+		return
+	}
+
+	h.fanOut(func(filter Filter) (Message, bool) {
+		if filter.Token != "" && filter.Token != entry.TokenNumber {
+			return Message{}, false
+		}
+		if filter.Location != "" && filter.Location != entry.LocationID {
+			return Message{}, false
+		}
+		if filter.privileged() {
+			return Message{Type: MessageQueueUpdate, Data: full}, true
+		}
+		return Message{Type: MessageQueueUpdate, Data: public}, true
+	})
+}
+
+func (h *Hub) broadcastStats(stats json.RawMessage) {
+	var scope struct {
+		LocationID string `json:"location_id"`
+	}
+	if err := json.Unmarshal(stats, &scope); err != nil {
+		log.Printf("Failed to decode queue stats: %v", err)
+		return
+	}
+
+	h.fanOut(func(filter Filter) (Message, bool) {
+		if filter.Token != "" {
+			return Message{}, false
+		}
+		if filter.Location != "" && filter.Location != scope.LocationID {
+			return Message{}, false
+		}
+		return Message{Type: MessageQueueStats, Data: stats}, true
+	})
+}
+
+// fanOut offers a message to every client; clients whose buffer is full are
+// dropped instead of holding up the others
+func (h *Hub) fanOut(messageFor func(Filter) (Message, bool)) {
+	var slow []*Client
+
+	h.mu.RLock()
+	for client := range h.clients {
+		msg, ok := messageFor(client.filter)
+		if !ok {
+			continue
+		}
+		select {
+		case client.send <- msg:
+		default:
+			slow = append(slow, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, client := range slow {
+		h.Unregister(client)
+	}
+}
+
+// redactEntry strips personal details from an entry for public clients
+func redactEntry(entry *models.QueueEntry) *models.QueueEntry {
+	redacted := *entry
+	redacted.UserID = ""
+	redacted.UserName = nil
+	redacted.UserPhone = nil
+	redacted.Notes = nil
+	redacted.SpecialHandling = nil
+	return &redacted
+}
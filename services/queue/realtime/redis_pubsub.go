@@ -16,6 +16,8 @@ import (
 const (
 	QueueUpdatesChannel = "queue:updates"
 	QueueStatsChannel   = "queue:stats"
+
+	ActiveQueueSnapshotKey = "queue:active:snapshot"
 )
 
 type RealtimeService struct {
@@ -28,14 +30,20 @@ func NewRealtimeService() *RealtimeService {
 	}
 }
 
-// PublishQueueUpdate publishes queue update to Redis pub/sub
+// PublishQueueUpdate publishes queue update to Redis pub/sub, or appends it to
+// the updates stream when using the streams transport
 func (rs *RealtimeService) PublishQueueUpdate(ctx context.Context, entry *models.QueueEntry) error {
 	data, err := json.Marshal(entry)
 	if err != nil {
 		return fmt.Errorf("failed to marshal queue entry: %w", err)
 	}
 
-	if err := rs.redis.Publish(ctx, QueueUpdatesChannel, data).Err(); err != nil {
+	if transport == TransportStreams {
+		err = rs.appendQueueUpdate(ctx, data)
+	} else {
+		err = rs.redis.Publish(ctx, QueueUpdatesChannel, data).Err()
+	}
+	if err != nil {
 		return fmt.Errorf("failed to publish queue update: %w", err)
 	}
 
@@ -60,8 +68,13 @@ func (rs *RealtimeService) PublishQueueStats(ctx context.Context, stats interfac
 	return nil
 }
 
-// SubscribeQueueUpdates subscribes to queue updates
+// SubscribeQueueUpdates subscribes to queue updates. With the streams transport
+// this replica reads as its own consumer of the configured group.
 func (rs *RealtimeService) SubscribeQueueUpdates(ctx context.Context, callback func(*models.QueueEntry)) error {
+	if transport == TransportStreams {
+		return rs.SubscribeQueueUpdatesFrom(ctx, streamGroup, streamConsumerName(), callback)
+	}
+
 	pubsub := rs.redis.Subscribe(ctx, QueueUpdatesChannel)
 	defer pubsub.Close()
 
@@ -85,6 +98,30 @@ func (rs *RealtimeService) SubscribeQueueUpdates(ctx context.Context, callback f
 	}
 }
 
+// SubscribeQueueStats subscribes to queue statistics, passing each payload as published
+func (rs *RealtimeService) SubscribeQueueStats(ctx context.Context, callback func(json.RawMessage)) error {
+	pubsub := rs.redis.Subscribe(ctx, QueueStatsChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+
+	log.Println("Subscribed to queue stats channel")
+
+	for {
+		select {
+		case msg := <-ch:
+			if !json.Valid([]byte(msg.Payload)) {
+				log.Println("Ignoring malformed queue stats payload")
+				continue
+			}
+			callback(json.RawMessage(msg.Payload))
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // UpdateQueueCache updates queue entry in Redis cache
 func (rs *RealtimeService) UpdateQueueCache(ctx context.Context, entry *models.QueueEntry) error {
 	key := fmt.Sprintf("queue:entry:%s", entry.ID)
@@ -125,14 +162,12 @@ func (rs *RealtimeService) SetActiveQueueSnapshot(ctx context.Context, entries [
 		return err
 	}
 
-	key := "queue:active:snapshot"
-	return rs.redis.Set(ctx, key, data, 5*time.Minute).Err()
+	return rs.redis.Set(ctx, ActiveQueueSnapshotKey, data, 5*time.Minute).Err()
 }
 
 // GetActiveQueueSnapshot retrieves active queue snapshot
 func (rs *RealtimeService) GetActiveQueueSnapshot(ctx context.Context) ([]models.QueueEntry, error) {
-	key := "queue:active:snapshot"
-	data, err := rs.redis.Get(ctx, key).Result()
+	data, err := rs.redis.Get(ctx, ActiveQueueSnapshotKey).Result()
 	if err != nil {
 		return nil, err
 	}
@@ -145,9 +180,9 @@ func (rs *RealtimeService) GetActiveQueueSnapshot(ctx context.Context) ([]models
 	return entries, nil
 }
 
-// IncrementTokenCounter increments daily token counter atomically
-func (rs *RealtimeService) IncrementTokenCounter(ctx context.Context, date string) (int64, error) {
-	key := fmt.Sprintf("queue:token:counter:%s", date)
+// IncrementTokenCounter increments a location's daily token counter atomically
+func (rs *RealtimeService) IncrementTokenCounter(ctx context.Context, locationID, date string) (int64, error) {
+	key := fmt.Sprintf("queue:token:counter:%s:%s", locationID, date)
 	val, err := rs.redis.Incr(ctx, key).Result()
 	if err != nil {
 		return 0, err
@@ -0,0 +1,135 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"gin-quickstart/config"
+	"gin-quickstart/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Transports for queue updates
+const (
+	// TransportPubSub only reaches subscribers connected at publish time
+	TransportPubSub = "pubsub"
+	// TransportStreams keeps recent updates so late joiners can catch up
+	TransportStreams = "streams"
+)
+
+// QueueUpdatesStream holds recent queue updates when using the streams transport
+const QueueUpdatesStream = "queue:updates:stream"
+
+var (
+	transport          = TransportPubSub
+	streamMaxLen int64 = 1000
+	streamGroup        = "queue-service"
+)
+
+// Configure selects the transport used for queue updates
+func Configure(cfg *config.Config) error {
+	switch strings.ToLower(cfg.RealtimeTransport) {
+	case "", TransportPubSub:
+		transport = TransportPubSub
+	case TransportStreams:
+		transport = TransportStreams
+	default:
+		return fmt.Errorf("unsupported REALTIME_TRANSPORT %q", cfg.RealtimeTransport)
+	}
+
+	if cfg.RealtimeStreamMaxLen > 0 {
+		streamMaxLen = int64(cfg.RealtimeStreamMaxLen)
+	}
+	if cfg.RealtimeStreamGroup != "" {
+		streamGroup = cfg.RealtimeStreamGroup
+	}
+	return nil
+}
+
+// appendQueueUpdate adds an update to the stream, trimming it to roughly streamMaxLen
+func (rs *RealtimeService) appendQueueUpdate(ctx context.Context, data []byte) error {
+	return rs.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: QueueUpdatesStream,
+		MaxLen: streamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"entry": data},
+	}).Err()
+}
+
+// SubscribeQueueUpdatesFrom reads queue updates from the stream as consumer of
+// group. A group that doesn't exist yet starts at the oldest retained update,
+// so a display joining late first receives what it missed. Updates delivered
+// to the consumer but never acknowledged (e.g. it crashed) are redelivered first.
+func (rs *RealtimeService) SubscribeQueueUpdatesFrom(ctx context.Context, group, consumer string, callback func(*models.QueueEntry)) error {
+	err := rs.redis.XGroupCreateMkStream(ctx, QueueUpdatesStream, group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s: %w", group, err)
+	}
+
+	log.Printf("Reading queue updates stream as %s/%s", group, consumer)
+
+	// "0" reads this consumer's pending updates; ">" reads new ones
+	lastID := "0"
+	for {
+		streams, err := rs.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: consumer,
+			Streams:  []string{QueueUpdatesStream, lastID},
+			Count:    100,
+			Block:    5 * time.Second,
+		}).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return fmt.Errorf("failed to read queue updates stream: %w", err)
+		}
+
+		for _, stream := range streams {
+			if lastID == "0" && len(stream.Messages) == 0 {
+				lastID = ">"
+			}
+			for _, msg := range stream.Messages {
+				if entry, err := decodeStreamEntry(msg); err != nil {
+					log.Printf("Error decoding queue update %s: %v", msg.ID, err)
+				} else {
+					callback(entry)
+				}
+				if err := rs.redis.XAck(ctx, QueueUpdatesStream, group, msg.ID).Err(); err != nil {
+					log.Printf("Failed to acknowledge queue update %s: %v", msg.ID, err)
+				}
+			}
+		}
+	}
+}
+
+func decodeStreamEntry(msg redis.XMessage) (*models.QueueEntry, error) {
+	data, ok := msg.Values["entry"].(string)
+	if !ok {
+		return nil, errors.New("missing entry field")
+	}
+
+	var entry models.QueueEntry
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// streamConsumerName identifies this replica within the configured group
+func streamConsumerName() string {
+	if hostname, err := os.Hostname(); err == nil {
+		return hostname
+	}
+	return "queue-service"
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"net/http/pprof"
+
+	"gin-quickstart/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupPprofRoutes mounts the net/http/pprof handlers behind admin auth and
+// the admin networks
+func setupPprofRoutes(router *gin.Engine) {
+	debug := router.Group("/debug/pprof")
+	debug.Use(middleware.AuthMiddleware(), middleware.AdminOnlyMiddleware(), middleware.AdminNetworkMiddleware())
+	{
+		debug.GET("/", gin.WrapF(pprof.Index))
+		debug.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		debug.GET("/profile", gin.WrapF(pprof.Profile))
+		debug.GET("/symbol", gin.WrapF(pprof.Symbol))
+		debug.POST("/symbol", gin.WrapF(pprof.Symbol))
+		debug.GET("/trace", gin.WrapF(pprof.Trace))
+
+		for _, profile := range []string{"allocs", "block", "goroutine", "heap", "mutex", "threadcreate"} {
+			debug.GET("/"+profile, gin.WrapH(pprof.Handler(profile)))
+		}
+	}
+}
@@ -1,90 +1,331 @@
 package routes
 
 import (
+	"log"
+
 	"gin-quickstart/handlers"
 	"gin-quickstart/middleware"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func SetupRoutes(router *gin.Engine) {
+	// Enum request fields reject values outside their allowed set
+	if err := handlers.RegisterValidators(); err != nil {
+		log.Fatalf("Failed to register request validators: %v", err)
+	}
+
 	queueHandler := handlers.NewQueueHandler()
 
 	// Apply CORS
 	router.Use(middleware.CORSMiddleware())
 
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
+	// Assign or propagate request IDs
+	router.Use(middleware.RequestIDMiddleware())
+
+	// Record request metrics
+	router.Use(middleware.MetricsMiddleware())
+
+	// Trace requests
+	router.Use(otelgin.Middleware("queue-service"))
+
+	// Compress large JSON and CSV responses
+	router.Use(middleware.CompressionMiddleware())
+
+	// Negotiate the response language (Accept-Language)
+	router.Use(middleware.LanguageMiddleware())
+
+	// Answer errors handlers recorded with c.Error
+	router.Use(middleware.ErrorMiddleware())
+
+	// Health check (liveness)
+	liveness := func(c *gin.Context) {
 		c.JSON(200, gin.H{
 			"status":  "ok",
 			"service": "queue-service",
 		})
-	})
+	}
+	router.GET("/health", liveness)
+	router.GET("/health/live", liveness)
+
+	// Readiness check (pings MySQL, Redis, Kafka and Menu gRPC)
+	router.GET("/health/ready", handlers.ReadinessCheck)
+
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	// Versioned queue API. A response DTO change that would break clients
+	// ships under the next version (see middleware.APIVersionMiddleware).
+	setupQueueRoutes(router.Group("/api/v1/queue", middleware.APIVersionMiddleware(1)), queueHandler)
 
+	// Legacy paths, kept as aliases of v1 for the existing frontend
+	setupQueueRoutes(router.Group("/api/queue",
+		middleware.APIVersionMiddleware(1),
+		middleware.DeprecatedPathMiddleware("/api/queue", "/api/v1/queue"),
+	), queueHandler)
+
+	// Profiling endpoints (require admin role, from the admin networks)
+	setupPprofRoutes(router)
+}
+
+// setupQueueRoutes mounts the queue API on api
+func setupQueueRoutes(api *gin.RouterGroup, queueHandler *handlers.QueueHandler) {
 	// Public routes
-	public := router.Group("/api/queue")
+	// Every group scopes requests to the location in X-Location-ID (or
+	// ?location_id=), after authentication so location-bound staff stay in theirs
+	public := api.Group("")
+	public.Use(middleware.TimeoutMiddleware(), middleware.LocationMiddleware())
+	// Token lookups are limited per client IP and take the token or its
+	// signed lookup code, against walking the sequential tokens
+	lookupLimit := middleware.LookupRateLimitMiddleware()
 	{
-		// Get all active queue entries (public - for display)
-		public.GET("", queueHandler.GetActiveQueueEntries)
+		// Get all active queue entries (public - for display; ETag for polling displays)
+		public.GET("", middleware.ETagMiddleware(), queueHandler.GetActiveQueueEntries)
 		
-		// Get queue position by token (public)
-		public.GET("/position/:token", queueHandler.GetQueuePosition)
+		// Get queue position by token (public, from the read replica)
+		public.GET("/position/:token", lookupLimit, middleware.ReplicaReadsMiddleware(), queueHandler.GetQueuePosition)
 		
 		// Get queue entry by token (public)
-		public.GET("/token/:token", queueHandler.GetQueueEntryByToken)
+		public.GET("/token/:token", lookupLimit, queueHandler.GetQueueEntryByToken)
+		
+		// Get staged pickups by token (public)
+		public.GET("/token/:token/stages", lookupLimit, queueHandler.GetStagesByToken)
 		
-		// Get current queue state (public - for display)
-		public.GET("/current", queueHandler.GetCurrentQueue)
+		// How a token's position and ETA changed over time (public)
+		public.GET("/token/:token/history", lookupLimit, queueHandler.GetPositionHistoryByToken)
 		
-		// Get queue statistics (public - for display)
-		public.GET("/stats", queueHandler.GetQueueStatistics)
+		// Get each order's progress on a party token (public)
+		public.GET("/token/:token/orders", lookupLimit, queueHandler.GetLinkedOrdersByToken)
+		
+		// Explain why a token is at its position (public)
+		public.GET("/:id/why", lookupLimit, queueHandler.ExplainQueuePosition)
+		
+		// Get current queue state (public - for display, from the read replica;
+		// ETag for polling displays)
+		public.GET("/current", middleware.ReplicaReadsMiddleware(), middleware.ETagMiddleware(), queueHandler.GetCurrentQueue)
+		
+		// Get queue statistics (public - for display, from the read replica)
+		public.GET("/stats", middleware.ReplicaReadsMiddleware(), queueHandler.GetQueueStatistics)
+		
+		// Get kitchen load against MaxConcurrentOrders (public - for display)
+		public.GET("/load", queueHandler.GetKitchenLoad)
+		
+		// List locations (public - for kiosks and displays picking an outlet)
+		public.GET("/locations", queueHandler.ListLocations)
+		
+		// SMS delivery status callbacks (public - verified by provider signature)
+		public.POST("/notifications/sms/status", queueHandler.SMSStatusCallback)
+	}
+
+	// Real-time queue updates over WebSocket or SSE (public; a token in the
+	// Authorization header or access_token query adds personal details for staff).
+	// Following a ?token= counts as a token lookup.
+	live := api.Group("")
+	live.Use(middleware.OptionalAuthMiddleware(), middleware.LocationMiddleware(), lookupLimit)
+	{
+		live.GET("/ws", queueHandler.StreamQueueUpdatesWS)
+		live.GET("/events", queueHandler.StreamQueueUpdatesSSE)
 	}
 
 	// Protected routes (require authentication)
-	protected := router.Group("/api/queue")
-	protected.Use(middleware.AuthMiddleware())
+	protected := api.Group("")
+	protected.Use(middleware.TimeoutMiddleware(), middleware.AuthMiddleware(), middleware.LocationMiddleware())
 	{
-		// Create queue entry (authenticated users)
-		protected.POST("", queueHandler.CreateQueueEntry)
+		// Create queue entry (authenticated users; retries with the same
+		// Idempotency-Key get the first response back)
+		protected.POST("", middleware.IdempotencyMiddleware(), queueHandler.CreateQueueEntry)
 		
 		// Get queue entry by order ID
 		protected.GET("/order/:orderId", queueHandler.GetQueueEntryByOrderID)
 		
 		// Get user's own queue entries
 		protected.GET("/user/me", queueHandler.GetUserQueueEntries)
+		
+		// Notification channel preferences (SMS, email)
+		protected.GET("/notifications/preferences", queueHandler.GetNotificationPreferences)
+		protected.PUT("/notifications/preferences", queueHandler.UpdateNotificationPreferences)
+		
+		// Devices registered for push notifications
+		protected.POST("/notifications/devices", queueHandler.RegisterDevice)
+		protected.DELETE("/notifications/devices/:token", queueHandler.UnregisterDevice)
 	}
 
-	// Staff routes (require staff role)
-	staff := router.Group("/api/queue")
-	staff.Use(middleware.AuthMiddleware(), middleware.StaffOnlyMiddleware())
+	// Staff routes (require staff role; changes are audited)
+	staff := api.Group("")
+	staff.Use(middleware.TimeoutMiddleware(), middleware.AuthMiddleware(), middleware.StaffOnlyMiddleware(), middleware.LocationMiddleware(), middleware.AuditMiddleware())
 	{
+		// Queue a walk-in customer who has no order
+		staff.POST("/walk-in", middleware.IdempotencyMiddleware(), queueHandler.CreateWalkInEntry)
+		
+		// Entries waiting for a scheduled pickup, next to join the queue first
+		staff.GET("/scheduled", queueHandler.GetScheduledEntries)
+		
 		// Update queue status
-		staff.PATCH("/:id/status", queueHandler.UpdateQueueStatus)
+		staff.PATCH("/:id/status", middleware.IdempotencyMiddleware(), queueHandler.UpdateQueueStatus)
 		
 		// Update queue priority
 		staff.PUT("/:id/priority", queueHandler.UpdateQueuePriority)
 		
+		// Reorder a lane's waiting entries (drag and drop)
+		staff.PUT("/reorder", queueHandler.ReorderQueue)
+		
+		// Reinstate a no-show or expired entry whose customer came back
+		staff.POST("/:id/requeue", queueHandler.RequeueEntry)
+		
+		// Bump a waiting entry down while its customer isn't responding
+		staff.POST("/:id/skip", queueHandler.SkipEntry)
+		
+		// Call a ready token again on the display and the customer's device
+		staff.POST("/:id/recall", queueHandler.RecallEntry)
+		
 		// Assign staff to queue entry
 		staff.POST("/:id/assign", queueHandler.AssignStaff)
 		
 		// Advance queue
-		staff.POST("/advance", queueHandler.AdvanceQueue)
+		staff.POST("/advance", middleware.IdempotencyMiddleware(), queueHandler.AdvanceQueue)
+		
+		// Staff shifts (who is on duty, and at which counter)
+		staff.POST("/shifts/clock-in", queueHandler.ClockIn)
+		staff.POST("/shifts/clock-out", queueHandler.ClockOut)
+		staff.GET("/shifts/active", queueHandler.GetActiveShifts)
+		staff.GET("/shifts/:shiftId/logs", queueHandler.GetShiftActionLogs)
+		
+		// Staged readiness (staggered courses)
+		staff.POST("/:id/stages", queueHandler.DefineStages)
+		staff.GET("/:id/stages", queueHandler.GetEntryStages)
+		staff.PATCH("/:id/stages/:stageId/status", queueHandler.UpdateStageStatus)
+		
+		// Party tokens (separate orders collected together)
+		staff.POST("/:id/orders", queueHandler.LinkOrders)
+		staff.GET("/:id/orders", queueHandler.GetLinkedOrders)
+		staff.PATCH("/:id/orders/:orderId/status", queueHandler.UpdateLinkedOrderStatus)
+		
+		// Staff home screen (summary, at-risk entries, counters, stats, announcements)
+		staff.GET("/dashboard", queueHandler.GetDashboard)
+		
+		// Active entries that waited past the SLA of their priority
+		staff.GET("/sla/breached", queueHandler.GetSLABreaches)
+		
+		// Position, status and ETA changes of an entry
+		staff.GET("/:id/history", queueHandler.GetPositionHistory)
 		
 		// Get staff action logs
 		staff.GET("/:id/logs", queueHandler.GetStaffActionLogs)
 		
+		// Counters (closing a counter rebalances its entries)
+		staff.GET("/counters", queueHandler.GetCounters)
+		staff.POST("/counters/:counterId/open", queueHandler.OpenCounter)
+		staff.POST("/counters/:counterId/close", queueHandler.CloseCounter)
+		
+		// Compare KPIs across locations
+		staff.GET("/stats/compare", queueHandler.CompareLocationStatistics)
+		
+		// Hourly statistics with wait and preparation time percentiles
+		staff.GET("/stats/hourly", queueHandler.GetHourlyStatistics)
+		
+		// Sampled queue length and kitchen load through a day, with its peaks
+		staff.GET("/stats/load", queueHandler.GetLoadCurve)
+		
+		// Download daily and hourly statistics as CSV or XLSX
+		staff.GET("/stats/export", middleware.StreamTimeoutMiddleware(), queueHandler.ExportStatistics)
+		
+		// Custom KPIs and the daily KPI report
+		staff.GET("/kpis", queueHandler.ListKPIDefinitions)
+		staff.GET("/stats/kpis", queueHandler.GetKPIReport)
+		
 		// Get configuration
 		staff.GET("/config", queueHandler.GetConfiguration)
 		
-		// Recalculate positions
-		staff.POST("/recalculate", queueHandler.RecalculatePositions)
+		// Waiting depth limits per lane and priority
+		staff.GET("/depth-limits", queueHandler.ListDepthLimits)
+		
+		// Recalculate positions (from the admin networks only)
+		staff.POST("/recalculate", middleware.AdminNetworkMiddleware(), queueHandler.RecalculatePositions)
+		
+		// Kitchen display: identical items across adjacent orders
+		staff.GET("/kds/batches", queueHandler.GetBatchSuggestions)
 	}
 
-	// Admin routes (require admin role)
-	admin := router.Group("/api/queue")
-	admin.Use(middleware.AuthMiddleware(), middleware.AdminOnlyMiddleware())
+	// Admin routes (require admin role, from the admin networks; changes are audited)
+	admin := api.Group("")
+	admin.Use(middleware.TimeoutMiddleware(), middleware.AuthMiddleware(), middleware.AdminOnlyMiddleware(), middleware.AdminNetworkMiddleware(), middleware.LocationMiddleware(), middleware.AuditMiddleware())
 	{
-		// Update configuration
+		// Update configuration, or delete the location's own to use the default's
 		admin.PUT("/config", queueHandler.UpdateConfiguration)
+		admin.DELETE("/config", queueHandler.DeleteConfiguration)
+		
+		// Cap waiting entries per lane and priority (overflow falls back a level)
+		admin.PUT("/depth-limits", queueHandler.SetDepthLimit)
+		admin.DELETE("/depth-limits/:limitId", queueHandler.DeleteDepthLimit)
+		
+		// Close out the remaining entries (e.g. at close of business); the first
+		// call previews the reset and returns the token confirming it
+		admin.POST("/admin/reset", middleware.LongTimeoutMiddleware(), queueHandler.ResetQueue)
+		
+		// Re-send notifications in bulk (e.g. after a provider outage)
+		admin.POST("/admin/renotify", middleware.LongTimeoutMiddleware(), queueHandler.RenotifyEntries)
+		
+		// Download entries filtered by date, status, staff and counter (CSV or JSON)
+		admin.GET("/admin/entries/export", middleware.StreamTimeoutMiddleware(), queueHandler.ExportQueueEntries)
+		
+		// Delete or anonymize entries (leaves tombstones for sync consumers)
+		admin.DELETE("/:id", queueHandler.DeleteQueueEntry)
+		admin.POST("/:id/anonymize", queueHandler.AnonymizeQueueEntry)
+		admin.GET("/admin/tombstones", queueHandler.GetTombstones)
+		
+		// Delete display announcements
+		admin.DELETE("/admin/announcements/:announcementId", queueHandler.DeleteAnnouncement)
+		
+		// List and restore deleted entries, announcements and configurations
+		admin.GET("/admin/deleted/:kind", queueHandler.GetDeletedRecords)
+		admin.POST("/admin/deleted/:kind/:recordId/restore", queueHandler.RestoreDeletedRecord)
+		
+		// Inspect and re-drive messages that failed consumption
+		admin.GET("/admin/dlq", queueHandler.ListDeadLetters)
+		admin.POST("/admin/dlq/:messageId/redrive", queueHandler.RedriveDeadLetter)
+		
+		// Pause and resume order event consumption (e.g. during a DB migration)
+		admin.GET("/admin/consumer", queueHandler.GetConsumerStatus)
+		admin.POST("/admin/consumer/pause", queueHandler.PauseConsumer)
+		admin.POST("/admin/consumer/resume", queueHandler.ResumeConsumer)
+		
+		// Replay an offset range through the handlers (dry run unless apply is set)
+		admin.POST("/admin/replay", middleware.LongTimeoutMiddleware(), queueHandler.ReplayEvents)
+		
+		// Find (and optionally repair) inconsistent rows
+		admin.GET("/admin/integrity", middleware.LongTimeoutMiddleware(), queueHandler.GetIntegrityReport)
+		admin.POST("/admin/integrity/check", middleware.LongTimeoutMiddleware(), queueHandler.RunIntegrityCheck)
+		
+		// Inspect and move simulated time (standalone/test deployments)
+		admin.GET("/admin/clock", queueHandler.GetClock)
+		admin.POST("/admin/clock/advance", queueHandler.AdvanceClock)
+		
+		// Define custom KPIs
+		admin.POST("/kpis", queueHandler.CreateKPIDefinition)
+		admin.PUT("/kpis/:kpiId", queueHandler.UpdateKPIDefinition)
+		admin.DELETE("/kpis/:kpiId", queueHandler.DeleteKPIDefinition)
+		
+		// Manage counters
+		admin.POST("/counters", queueHandler.CreateCounter)
+		admin.PUT("/counters/:counterId", queueHandler.RenameCounter)
+		admin.DELETE("/counters/:counterId", queueHandler.DeleteCounter)
+		
+		// Manage locations (deactivating keeps their history)
+		admin.POST("/locations", queueHandler.CreateLocation)
+		admin.PUT("/locations/:locationId", queueHandler.UpdateLocation)
+		admin.DELETE("/locations/:locationId", queueHandler.DeactivateLocation)
+		
+		// Outbound webhook subscriptions and their delivery logs
+		admin.GET("/admin/webhooks", queueHandler.ListWebhooks)
+		admin.POST("/admin/webhooks", queueHandler.CreateWebhook)
+		admin.PUT("/admin/webhooks/:webhookId", queueHandler.UpdateWebhook)
+		admin.DELETE("/admin/webhooks/:webhookId", queueHandler.DeleteWebhook)
+		admin.GET("/admin/webhooks/:webhookId/deliveries", queueHandler.ListWebhookDeliveries)
+		admin.POST("/admin/webhooks/deliveries/:deliveryId/retry", queueHandler.RetryWebhookDelivery)
+
+		// Audit log of staff and admin changes
+		admin.GET("/admin/audit-log", queueHandler.ListAuditLog)
 	}
 }
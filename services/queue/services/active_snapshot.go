@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sort"
+	"sync"
+
+	"gin-quickstart/database"
+	"gin-quickstart/models"
+	"gin-quickstart/realtime"
+)
+
+// snapshotRefresh coalesces refresh requests: any number of mutations while a
+// refresh is pending result in a single reload
+var snapshotRefresh = make(chan struct{}, 1)
+
+// changedEntries collects the entries changed since the last refresh, whose
+// updates are broadcast to real-time clients once the snapshot is rebuilt
+var changedEntries = struct {
+	sync.Mutex
+	ids map[string]struct{}
+}{ids: make(map[string]struct{})}
+
+// queueChanged drops the active-queue snapshot after the entries were mutated,
+// so readers fall back to MySQL until the refresher has rebuilt it
+func (s *QueueService) queueChanged(ctx context.Context, entryIDs ...string) {
+	if rdb := database.GetRedis(); rdb != nil {
+		if err := rdb.Del(ctx, realtime.ActiveQueueSnapshotKey).Err(); err != nil {
+			log.Printf("Failed to drop active queue snapshot: %v", err)
+		}
+	}
+
+	changedEntries.Lock()
+	for _, id := range entryIDs {
+		changedEntries.ids[id] = struct{}{}
+	}
+	changedEntries.Unlock()
+
+	select {
+	case snapshotRefresh <- struct{}{}:
+	default:
+	}
+}
+
+// RefreshActiveSnapshot reloads the active entries from MySQL into the snapshot
+func (s *QueueService) RefreshActiveSnapshot(ctx context.Context) ([]models.QueueEntry, error) {
+	entries, err := s.loadActiveQueueEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if database.GetRedis() != nil {
+		if err := realtime.NewRealtimeService().SetActiveQueueSnapshot(ctx, entries); err != nil {
+			log.Printf("Failed to store active queue snapshot: %v", err)
+		}
+	}
+	return entries, nil
+}
+
+// StartActiveSnapshotRefresher rebuilds the snapshot after mutations until ctx is cancelled
+func (s *QueueService) StartActiveSnapshotRefresher(ctx context.Context) {
+	for {
+		select {
+		case <-snapshotRefresh:
+			entries, err := s.RefreshActiveSnapshot(ctx)
+			if err != nil {
+				log.Printf("Failed to refresh active queue snapshot: %v", err)
+				continue
+			}
+			s.broadcastChanges(ctx, entries)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// broadcastChanges publishes the entries changed since the last refresh, and
+// the updated statistics of their locations, to real-time clients. Entries
+// that left the active queue are loaded individually; deleted ones have
+// nothing left to publish.
+func (s *QueueService) broadcastChanges(ctx context.Context, active []models.QueueEntry) {
+	changedEntries.Lock()
+	ids := changedEntries.ids
+	changedEntries.ids = make(map[string]struct{})
+	changedEntries.Unlock()
+
+	if len(ids) == 0 || database.GetRedis() == nil {
+		return
+	}
+
+	rs := realtime.NewRealtimeService()
+	locations := make(map[string]struct{})
+	for i := range active {
+		if _, ok := ids[active[i].ID]; ok {
+			delete(ids, active[i].ID)
+			locations[active[i].LocationID] = struct{}{}
+			if err := rs.PublishQueueUpdate(ctx, &active[i]); err != nil {
+				log.Printf("Failed to broadcast queue update: %v", err)
+			}
+		}
+	}
+	for id := range ids {
+		var entry models.QueueEntry
+		if err := s.db.WithContext(ctx).Where("id = ?", id).First(&entry).Error; err != nil {
+			continue
+		}
+		locations[entry.LocationID] = struct{}{}
+		if err := rs.PublishQueueUpdate(ctx, &entry); err != nil {
+			log.Printf("Failed to broadcast queue update: %v", err)
+		}
+	}
+
+	for locationID := range locations {
+		if stats, err := s.GetQueueStatistics(WithLocation(ctx, locationID), nil); err == nil {
+			if err := rs.PublishQueueStats(ctx, stats); err != nil {
+				log.Printf("Failed to broadcast queue stats: %v", err)
+			}
+		}
+	}
+}
+
+// activeSnapshot returns the snapshot, or false on a miss
+func (s *QueueService) activeSnapshot(ctx context.Context) ([]models.QueueEntry, bool) {
+	if database.GetRedis() == nil {
+		return nil, false
+	}
+
+	entries, err := realtime.NewRealtimeService().GetActiveQueueSnapshot(ctx)
+	if err != nil {
+		return nil, false
+	}
+	return entries, true
+}
+
+// fillActiveSnapshot rebuilds a missing snapshot, sharing one MySQL load among
+// concurrent callers
+func (s *QueueService) fillActiveSnapshot(ctx context.Context) ([]models.QueueEntry, error) {
+	loaded, err, _ := cacheFills.Do(realtime.ActiveQueueSnapshotKey, func() (interface{}, error) {
+		return s.RefreshActiveSnapshot(context.WithoutCancel(ctx))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(loaded.([]models.QueueEntry)), nil
+}
+
+// entriesAtLocation keeps the snapshot entries of one location
+func entriesAtLocation(entries []models.QueueEntry, locationID string) []models.QueueEntry {
+	kept := make([]models.QueueEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.LocationID == locationID {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// currentQueueFromSnapshot splits the active entries the way GetCurrentQueue reports them
+func currentQueueFromSnapshot(entries []models.QueueEntry) *models.CurrentQueueResponse {
+	waiting := make([]models.QueueEntry, 0)
+	expressWaiting := make([]models.QueueEntry, 0)
+	inProgress := make([]models.QueueEntry, 0)
+	ready := make([]models.QueueEntry, 0)
+	for _, entry := range entries {
+		switch entry.Status {
+		case "WAITING":
+			if entry.IsExpressQueue {
+				expressWaiting = append(expressWaiting, entry)
+			} else {
+				waiting = append(waiting, entry)
+			}
+		case "IN_PROGRESS":
+			inProgress = append(inProgress, entry)
+		case "READY":
+			ready = append(ready, entry)
+		}
+	}
+
+	// Most recently ready first, as on the pickup display
+	sort.SliceStable(ready, func(i, j int) bool {
+		if ready[i].ActualReadyTime == nil || ready[j].ActualReadyTime == nil {
+			return ready[j].ActualReadyTime == nil && ready[i].ActualReadyTime != nil
+		}
+		return ready[i].ActualReadyTime.After(*ready[j].ActualReadyTime)
+	})
+	if len(ready) > 20 {
+		ready = ready[:20]
+	}
+
+	return &models.CurrentQueueResponse{
+		Waiting:        waiting,
+		ExpressWaiting: expressWaiting,
+		InProgress:     inProgress,
+		Ready:          ready,
+		TotalActive:    len(waiting) + len(expressWaiting) + len(inProgress) + len(ready),
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"gin-quickstart/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// defaultPrepTimeSmoothing is the weight of each entry in the learned
+	// average when the configuration doesn't set one
+	defaultPrepTimeSmoothing = 0.2
+	// maxLearnedPrepMinutesPerItem drops entries left in progress long after
+	// they were ready from the learned average
+	maxLearnedPrepMinutesPerItem = 60
+)
+
+// learnPrepTime moves the average preparation time per item of the entry's
+// configuration towards the entry's actual one, from when it started to
+// readyAt, when the location has adaptive preparation time enabled. A
+// manual configuration update that leaves out learned_prep_time_per_item
+// restarts the average from the configured value. Locations sharing the
+// default configuration learn together.
+func (s *QueueService) learnPrepTime(ctx context.Context, entry *models.QueueEntry, readyAt time.Time) {
+	if entry.ActualStartTime == nil || entry.ItemCount <= 0 {
+		return
+	}
+	perItem := readyAt.Sub(*entry.ActualStartTime).Minutes() / float64(entry.ItemCount)
+	if perItem <= 0 || perItem > maxLearnedPrepMinutesPerItem {
+		return
+	}
+
+	config, err := s.GetConfiguration(ctx)
+	if err != nil || !config.AdaptivePrepTimeEnabled {
+		return
+	}
+
+	changed := false
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the row so concurrent entries each count once
+		var current models.QueueConfiguration
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", config.ID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if !current.AdaptivePrepTimeEnabled {
+			return nil
+		}
+
+		learned := float64(current.AvgPreparationTimePerItem)
+		if current.LearnedPrepTimePerItem != nil {
+			learned = *current.LearnedPrepTimePerItem
+		}
+		smoothing := defaultPrepTimeSmoothing
+		if current.PrepTimeSmoothing != nil && *current.PrepTimeSmoothing > 0 && *current.PrepTimeSmoothing <= 1 {
+			smoothing = *current.PrepTimeSmoothing
+		}
+		learned += smoothing * (perItem - learned)
+
+		average := max(int(math.Round(learned)), 1)
+		changed = average != current.AvgPreparationTimePerItem
+		return tx.Model(&models.QueueConfiguration{}).Where("id = ?", current.ID).Updates(map[string]interface{}{
+			"learned_prep_time_per_item":    learned,
+			"avg_preparation_time_per_item": average,
+			"prep_time_learned_at":          s.clock.Now().UTC(),
+		}).Error
+	})
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to learn preparation time from %s: %v", entry.TokenNumber, err)
+		}
+		return
+	}
+
+	// The learned value is cached with the configuration; waits are only
+	// re-estimated once the rounded average moves
+	locations := s.configurationLocations(ctx, config.LocationID)
+	invalidateConfiguration(ctx, locations...)
+	if changed {
+		go func() {
+			for _, locationID := range locations {
+				s.RecalculatePositions(WithLocation(context.WithoutCancel(ctx), locationID))
+			}
+		}()
+	}
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gin-quickstart/models"
+
+	"gorm.io/gorm"
+)
+
+// archiveBatchSize is how many entries one archival transaction moves
+const archiveBatchSize = 500
+
+// archivedStatuses are the final statuses of entries that get archived
+var archivedStatuses = []string{"COMPLETED", "CANCELLED", "NO_SHOW"}
+
+// archivedTables pairs each table moved to the archive with its archive table
+var archivedTables = []struct{ table, archive, column string }{
+	{"queue_entries", "queue_entries_archive", "id"},
+	{"queue_position_history", "queue_position_history_archive", "queue_entry_id"},
+	{"staff_queue_actions_log", "staff_queue_actions_log_archive", "queue_entry_id"},
+}
+
+// ArchiveEntries moves finished and deleted entries created before olderThan
+// ago, with their position history and action logs, into the archive tables.
+// Each batch moves in one transaction, so an entry is never in both or neither.
+func (s *QueueService) ArchiveEntries(ctx context.Context, olderThan time.Duration) (int64, error) {
+	cutoff := s.clock.Now().UTC().Add(-olderThan)
+
+	var archived int64
+	for {
+		var ids []string
+		if err := s.db.WithContext(ctx).Unscoped().Model(&models.QueueEntry{}).
+			Where("(status IN ? OR deleted_at IS NOT NULL) AND created_at < ?", archivedStatuses, cutoff).
+			Order("created_at ASC").
+			Limit(archiveBatchSize).
+			Pluck("id", &ids).Error; err != nil {
+			return archived, err
+		}
+		if len(ids) == 0 {
+			return archived, nil
+		}
+
+		if err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// Dependents first, so the entries' foreign keys don't cascade
+			// away rows before they are copied
+			for i := len(archivedTables) - 1; i >= 0; i-- {
+				t := archivedTables[i]
+				if err := tx.Exec(fmt.Sprintf("INSERT INTO %s SELECT * FROM %s WHERE %s IN ?", t.archive, t.table, t.column), ids).Error; err != nil {
+					return fmt.Errorf("archive %s: %w", t.table, err)
+				}
+			}
+			for _, model := range []interface{}{
+				&models.QueueEntryStage{},
+				&models.QueueEntryOrder{},
+				&models.QueueEntryItem{},
+				&models.QueuePositionHistory{},
+				&models.QueueNotificationSent{},
+				&models.StaffQueueActionLog{},
+			} {
+				if err := tx.Where("queue_entry_id IN ?", ids).Delete(model).Error; err != nil {
+					return err
+				}
+			}
+			return tx.Unscoped().Where("id IN ?", ids).Delete(&models.QueueEntry{}).Error
+		}); err != nil {
+			return archived, err
+		}
+
+		archived += int64(len(ids))
+		if len(ids) < archiveBatchSize {
+			return archived, nil
+		}
+	}
+}
+
+// StartEntryArchiver periodically archives finished entries older than
+// olderThan until ctx is cancelled
+func (s *QueueService) StartEntryArchiver(ctx context.Context, olderThan, interval time.Duration) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			archived, err := s.ArchiveEntries(ctx, olderThan)
+			if err != nil {
+				log.Printf("Failed to archive entries: %v", err)
+			}
+			// A failed batch leaves the earlier batches archived
+			if archived > 0 {
+				log.Printf("Archived %d finished entries", archived)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+)
+
+// maxAuditLogPageSize caps a page of the audit log
+const maxAuditLogPageSize = 200
+
+// RecordAuditLog stores an audit log entry
+func (s *QueueService) RecordAuditLog(ctx context.Context, entry *models.AuditLogEntry) error {
+	if entry.ID == "" {
+		entry.ID = utils.GenerateUUID()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = s.clock.Now().UTC()
+	}
+	return s.db.WithContext(ctx).Create(entry).Error
+}
+
+// ListAuditLog lists a page of the audit log entries matching filter, newest
+// first. Requests scoped to a location only see its entries.
+func (s *QueueService) ListAuditLog(ctx context.Context, filter *models.AuditLogFilter, limit, offset int) (*models.AuditLogListResponse, error) {
+	if limit <= 0 || limit > maxAuditLogPageSize {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := inLocation(ctx, s.db.WithContext(ctx).Model(&models.AuditLogEntry{}))
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Method != "" {
+		query = query.Where("method = ?", filter.Method)
+	}
+	if filter.Route != "" {
+		query = query.Where("route = ?", filter.Route)
+	}
+	if filter.Failed != nil {
+		if *filter.Failed {
+			query = query.Where("status_code >= ?", 400)
+		} else {
+			query = query.Where("status_code < ?", 400)
+		}
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var entries []models.AuditLogEntry
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.AuditLogListResponse{
+		Entries: entries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+	}, nil
+}
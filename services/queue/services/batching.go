@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// batchWindowSize caps how many adjacent entries one suggestion may span
+	batchWindowSize = 5
+	// minBatchQuantity is the smallest combined quantity worth batching
+	minBatchQuantity = 3
+)
+
+// GetBatchSuggestions finds runs of adjacent waiting entries that share an
+// item, at the given location or else the request's
+func (s *QueueService) GetBatchSuggestions(ctx context.Context, locationID string) (*models.BatchSuggestionsResponse, error) {
+	if locationID == "" {
+		locationID = LocationFromContext(ctx)
+	}
+
+	suggestions, err := s.computeBatchSuggestions(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BatchSuggestionsResponse{
+		LocationID:  locationID,
+		Suggestions: suggestions,
+		GeneratedAt: s.clock.Now().UTC(),
+	}, nil
+}
+
+func (s *QueueService) computeBatchSuggestions(ctx context.Context, locationID string) ([]models.BatchSuggestion, error) {
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Where("location_id = ? AND status = ?", locationID, "WAITING").
+		Order("is_express_queue ASC, position ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	suggestions := []models.BatchSuggestion{}
+	if len(entries) < 2 {
+		return suggestions, nil
+	}
+
+	entryIDs := make([]string, len(entries))
+	for i, entry := range entries {
+		entryIDs[i] = entry.ID
+	}
+
+	var items []models.QueueEntryItem
+	if err := s.db.WithContext(ctx).Where("queue_entry_id IN ?", entryIDs).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	// Quantity per entry per menu item, plus a display name per menu item
+	quantities := make(map[string]map[string]int, len(entries))
+	names := make(map[string]*string)
+	var menuItemIDs []string
+	for _, item := range items {
+		if quantities[item.QueueEntryID] == nil {
+			quantities[item.QueueEntryID] = make(map[string]int)
+		}
+		quantities[item.QueueEntryID][item.MenuItemID] += item.Quantity
+		if _, seen := names[item.MenuItemID]; !seen {
+			menuItemIDs = append(menuItemIDs, item.MenuItemID)
+			names[item.MenuItemID] = item.ItemName
+		}
+	}
+
+	for _, menuItemID := range menuItemIDs {
+		var run []models.QueueEntry
+		total := 0
+
+		flush := func() {
+			if len(run) >= 2 && total >= minBatchQuantity {
+				suggestions = append(suggestions, newBatchSuggestion(menuItemID, names[menuItemID], run, total))
+			}
+			run = nil
+			total = 0
+		}
+
+		for _, entry := range entries {
+			qty := quantities[entry.ID][menuItemID]
+			if qty == 0 {
+				flush()
+				continue
+			}
+			// Batches stay within a lane, whose positions they span
+			if len(run) == batchWindowSize || (len(run) > 0 && run[0].IsExpressQueue != entry.IsExpressQueue) {
+				flush()
+			}
+			run = append(run, entry)
+			total += qty
+		}
+		flush()
+	}
+
+	return suggestions, nil
+}
+
+func newBatchSuggestion(menuItemID string, name *string, run []models.QueueEntry, total int) models.BatchSuggestion {
+	suggestion := models.BatchSuggestion{
+		MenuItemID:    menuItemID,
+		ItemName:      name,
+		TotalQuantity: total,
+		EntryIDs:      make([]string, len(run)),
+		TokenNumbers:  make([]string, len(run)),
+		StartPosition: run[0].Position,
+		EndPosition:   run[len(run)-1].Position,
+	}
+	for i, entry := range run {
+		suggestion.EntryIDs[i] = entry.ID
+		suggestion.TokenNumbers[i] = entry.TokenNumber
+	}
+	return suggestion
+}
+
+// suggestBatchesFor publishes suggestions that include a newly queued entry
+func (s *QueueService) suggestBatchesFor(ctx context.Context, entry *models.QueueEntry) {
+	if s.publisher == nil {
+		return
+	}
+
+	suggestions, err := s.computeBatchSuggestions(ctx, entry.LocationID)
+	if err != nil {
+		log.Printf("Failed to compute batch suggestions: %v", err)
+		return
+	}
+
+	for i := range suggestions {
+		if !containsString(suggestions[i].EntryIDs, entry.ID) {
+			continue
+		}
+		if err := s.publisher.PublishBatchSuggestion(ctx, entry.LocationID, &suggestions[i]); err != nil {
+			log.Printf("Failed to publish batch suggestion: %v", err)
+		}
+	}
+}
+
+// saveEntryItems records the line items of an entry in tx
+func (s *QueueService) saveEntryItems(tx *gorm.DB, entryID string, reqs []models.CreateQueueItemRequest) error {
+	now := s.clock.Now().UTC()
+	items := make([]models.QueueEntryItem, 0, len(reqs))
+	for _, req := range reqs {
+		quantity := req.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		items = append(items, models.QueueEntryItem{
+			ID:           utils.GenerateUUID(),
+			QueueEntryID: entryID,
+			MenuItemID:   req.MenuItemID,
+			ItemName:     utils.StringPtr(req.ItemName),
+			Quantity:     quantity,
+			CreatedAt:    now,
+		})
+	}
+	return tx.Create(&items).Error
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
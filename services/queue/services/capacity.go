@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAtCapacity is returned when starting an entry would put more than
+// MaxConcurrentOrders in progress at a location
+var ErrAtCapacity = newError(KindConflict, "AT_CAPACITY", "kitchen is at capacity")
+
+// maxConcurrentOrders returns how many entries the location of ctx may have in
+// progress at once; 0 means no limit
+func (s *QueueService) maxConcurrentOrders(ctx context.Context) int {
+	config, err := s.GetConfiguration(ctx)
+	if err != nil {
+		log.Printf("Failed to load configuration for capacity check: %v", err)
+		return 0
+	}
+	if config.MaxConcurrentOrders < 0 {
+		return 0
+	}
+	return config.MaxConcurrentOrders
+}
+
+// startWithinCapacity applies the updates moving an entry to IN_PROGRESS,
+// unless its location already has MaxConcurrentOrders in progress. The
+// location row is locked so concurrent starts can't both take the last slot.
+func (s *QueueService) startWithinCapacity(ctx context.Context, entry *models.QueueEntry, updates map[string]interface{}) error {
+	limit := s.maxConcurrentOrders(ctx)
+	if limit == 0 {
+		return s.db.WithContext(ctx).Model(entry).Updates(updates).Error
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var location models.QueueLocation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", entry.LocationID).
+			First(&location).Error; err != nil {
+			return err
+		}
+
+		var inProgress int64
+		if err := tx.Model(&models.QueueEntry{}).
+			Where("location_id = ? AND status = ?", entry.LocationID, "IN_PROGRESS").
+			Count(&inProgress).Error; err != nil {
+			return err
+		}
+		if inProgress >= int64(limit) {
+			return ErrAtCapacity
+		}
+
+		return tx.Model(entry).Updates(updates).Error
+	})
+}
+
+// deferStart marks an entry the kitchen tried to start at capacity. It stays
+// WAITING and starts once an in-progress entry moves on.
+func (s *QueueService) deferStart(ctx context.Context, entry *models.QueueEntry, staffID, staffName string, reason *string) error {
+	if entry.StartDeferredAt != nil {
+		return nil
+	}
+
+	now := s.clock.Now().UTC()
+	if err := s.db.WithContext(ctx).Model(entry).Updates(map[string]interface{}{
+		"start_deferred_at": now,
+		"updated_at":        now,
+	}).Error; err != nil {
+		return err
+	}
+	entry.StartDeferredAt = &now
+
+	s.LogStaffAction(ctx, entry.ID, staffID, staffName, "DEFER_START", &entry.Status, nil, nil, nil, reason)
+	utils.InvalidateQueueCache(ctx, entry.ID)
+	s.queueChanged(ctx, entry.ID)
+
+	log.Printf("Kitchen at capacity, deferred start of token=%s", entry.TokenNumber)
+	return nil
+}
+
+// startDeferred starts deferred entries of a location, oldest request first,
+// for as long as there is room
+func (s *QueueService) startDeferred(ctx context.Context, locationID string) {
+	ctx = WithLocation(ctx, locationID)
+
+	for {
+		var entry models.QueueEntry
+		if err := s.db.WithContext(ctx).
+			Where("location_id = ? AND status = ? AND start_deferred_at IS NOT NULL", locationID, "WAITING").
+			Order("start_deferred_at ASC").
+			First(&entry).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("Failed to load deferred entries of location %s: %v", locationID, err)
+			}
+			return
+		}
+
+		reason := "Kitchen capacity freed up"
+		req := &models.UpdateQueueStatusRequest{
+			Status: "IN_PROGRESS",
+			Reason: &reason,
+		}
+		if err := s.UpdateQueueStatus(ctx, entry.ID, req, "system", "System"); err != nil {
+			if !errors.Is(err, ErrAtCapacity) {
+				log.Printf("Failed to start deferred entry %s: %v", entry.ID, err)
+			}
+			return
+		}
+	}
+}
+
+// GetKitchenLoad reports how many entries are in progress at the request's
+// location against its MaxConcurrentOrders, and how many are waiting for room
+func (s *QueueService) GetKitchenLoad(ctx context.Context) (*models.KitchenLoadResponse, error) {
+	locationID := LocationFromContext(ctx)
+
+	var inProgress, deferred int64
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("location_id = ? AND status = ?", locationID, "IN_PROGRESS").
+		Count(&inProgress).Error; err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("location_id = ? AND status = ? AND start_deferred_at IS NOT NULL", locationID, "WAITING").
+		Count(&deferred).Error; err != nil {
+		return nil, err
+	}
+
+	load := &models.KitchenLoadResponse{
+		LocationID:          locationID,
+		InProgress:          int(inProgress),
+		MaxConcurrentOrders: s.maxConcurrentOrders(ctx),
+		Deferred:            int(deferred),
+	}
+	if load.MaxConcurrentOrders > 0 {
+		load.Available = max(load.MaxConcurrentOrders-load.InProgress, 0)
+		load.CurrentLoad = roundRate(float64(load.InProgress) / float64(load.MaxConcurrentOrders) * 100)
+		load.AtCapacity = load.Available == 0
+	}
+	return load, nil
+}
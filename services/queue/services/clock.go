@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/models"
+)
+
+// ErrClockNotSimulated is returned when moving time on the real clock
+var ErrClockNotSimulated = newError(KindConflict, "CLOCK_NOT_SIMULATED", "clock is not simulated; set CLOCK_MODE=simulated to move time")
+
+var serviceClock clock.Clock = clock.Real()
+
+// SetClock sets the clock used by queue services created afterwards
+func SetClock(c clock.Clock) {
+	serviceClock = c
+}
+
+// GetClockStatus reports the current service time
+func (s *QueueService) GetClockStatus(ctx context.Context) *models.ClockStatus {
+	_, simulated := s.clock.(*clock.Simulated)
+	return &models.ClockStatus{Now: s.clock.Now().UTC(), Simulated: simulated, Timezone: clock.Zone().String()}
+}
+
+// AdvanceClock moves simulated time forward, firing any workers that become due
+func (s *QueueService) AdvanceClock(ctx context.Context, d time.Duration) (*models.ClockStatus, error) {
+	simulated, ok := s.clock.(*clock.Simulated)
+	if !ok {
+		return nil, ErrClockNotSimulated
+	}
+	if d <= 0 {
+		return nil, fmt.Errorf("%w: duration must be positive", ErrInvalidRequest)
+	}
+
+	simulated.Advance(d)
+	return s.GetClockStatus(ctx), nil
+}
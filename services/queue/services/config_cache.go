@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"gin-quickstart/database"
+	"gin-quickstart/models"
+
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
+)
+
+// cacheFills collapses concurrent cache misses into one loader per key, so an
+// expiring hot key doesn't send every waiting request to MySQL at once
+var cacheFills singleflight.Group
+
+const configCacheTTL = 5 * time.Minute
+
+// ConfigInvalidationChannel carries the comma-separated location IDs whose
+// configuration changed, so every instance drops its in-memory copy
+const ConfigInvalidationChannel = "queue:config:invalidate"
+
+func configCacheKey(locationID string) string {
+	return fmt.Sprintf("queue:config:%s", locationID)
+}
+
+// cachedConfiguration reads a location's queue configuration from Redis
+func cachedConfiguration(ctx context.Context, locationID string) (*models.QueueConfiguration, bool) {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return nil, false
+	}
+
+	data, err := rdb.Get(ctx, configCacheKey(locationID)).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	var config models.QueueConfiguration
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, false
+	}
+	return &config, true
+}
+
+// loadConfiguration reads a location's queue configuration from MySQL and
+// caches it. Locations without their own configuration use the default
+// location's.
+func (s *QueueService) loadConfiguration(ctx context.Context, locationID string) (*models.QueueConfiguration, error) {
+	var config models.QueueConfiguration
+	err := s.db.WithContext(ctx).Where("location_id = ?", locationID).First(&config).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && locationID != models.DefaultLocationID {
+		err = s.db.WithContext(ctx).Where("location_id = ?", models.DefaultLocationID).First(&config).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if rdb := database.GetRedis(); rdb != nil {
+		if data, err := json.Marshal(&config); err == nil {
+			if err := rdb.Set(ctx, configCacheKey(locationID), data, configCacheTTL).Err(); err != nil {
+				log.Printf("Failed to cache queue configuration: %v", err)
+			}
+		}
+	}
+	return &config, nil
+}
+
+// invalidateConfiguration drops the cached configuration of locations after
+// an update, here and, through ConfigInvalidationChannel, on other instances
+func invalidateConfiguration(ctx context.Context, locationIDs ...string) {
+	localConfigs.drop(locationIDs...)
+
+	rdb := database.GetRedis()
+	if rdb == nil || len(locationIDs) == 0 {
+		return
+	}
+
+	keys := make([]string, len(locationIDs))
+	for i, locationID := range locationIDs {
+		keys[i] = configCacheKey(locationID)
+	}
+	if err := rdb.Del(ctx, keys...).Err(); err != nil {
+		log.Printf("Failed to invalidate queue configuration cache: %v", err)
+	}
+	if err := rdb.Publish(ctx, ConfigInvalidationChannel, strings.Join(locationIDs, ",")).Err(); err != nil {
+		log.Printf("Failed to publish queue configuration invalidation: %v", err)
+	}
+}
+
+// localConfigs keeps configurations in memory while this instance listens on
+// ConfigInvalidationChannel. Without the subscription it stays off, as it
+// couldn't hear of updates made elsewhere.
+var localConfigs = &localConfigCache{entries: make(map[string]localConfig)}
+
+type localConfig struct {
+	config    models.QueueConfiguration
+	expiresAt time.Time
+}
+
+type localConfigCache struct {
+	mu  sync.RWMutex
+	ttl time.Duration // zero while unsubscribed
+	// generation moves on with every drop, so a load that started before an
+	// invalidation doesn't store what it read
+	generation uint64
+	entries    map[string]localConfig
+}
+
+func (c *localConfigCache) get(locationID string, now time.Time) (*models.QueueConfiguration, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cached, ok := c.entries[locationID]
+	if !ok || c.ttl == 0 || now.After(cached.expiresAt) {
+		return nil, false
+	}
+	config := cached.config
+	return &config, true
+}
+
+func (c *localConfigCache) currentGeneration() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.generation
+}
+
+// set stores a configuration read during generation, unless it has moved on
+func (c *localConfigCache) set(locationID string, config *models.QueueConfiguration, generation uint64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ttl == 0 || c.generation != generation {
+		return
+	}
+	c.entries[locationID] = localConfig{config: *config, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *localConfigCache) drop(locationIDs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for _, locationID := range locationIDs {
+		delete(c.entries, locationID)
+	}
+}
+
+// enable turns the cache on for ttl, or off (and empties it) for zero
+func (c *localConfigCache) enable(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.ttl = ttl
+	if ttl == 0 {
+		c.entries = make(map[string]localConfig)
+	}
+}
+
+// StartConfigReloader keeps configurations in memory for ttl, listening on
+// ConfigInvalidationChannel for updates from any instance, until ctx is
+// cancelled. While the subscription is down configurations come from Redis.
+func (s *QueueService) StartConfigReloader(ctx context.Context, ttl time.Duration) {
+	rdb := database.GetRedis()
+	if rdb == nil || ttl <= 0 {
+		return
+	}
+	defer localConfigs.enable(0)
+
+	for {
+		err := s.listenForConfigChanges(ctx, ttl)
+		localConfigs.enable(0)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Subscription to queue configuration changes ended, retrying: %v", err)
+
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *QueueService) listenForConfigChanges(ctx context.Context, ttl time.Duration) error {
+	pubsub := database.GetRedis().Subscribe(ctx, ConfigInvalidationChannel)
+	defer pubsub.Close()
+
+	// Only cache once updates can reach us
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+	localConfigs.enable(ttl)
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return errors.New("channel closed")
+			}
+			localConfigs.drop(strings.Split(msg.Payload, ",")...)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
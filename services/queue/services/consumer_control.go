@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+
+	"gin-quickstart/models"
+)
+
+// ErrConsumerUnavailable is returned when no Kafka consumer is running
+var ErrConsumerUnavailable = newError(KindUnavailable, "CONSUMER_UNAVAILABLE", "kafka consumer is not running")
+
+// ConsumerController pauses and resumes consumption of order events
+type ConsumerController interface {
+	Pause(pausedBy string) models.ConsumerStatus
+	Resume() models.ConsumerStatus
+	Status() models.ConsumerStatus
+}
+
+var consumerController ConsumerController
+
+// SetConsumerController sets the consumer controlled by the admin endpoints.
+// It is read on each call because the consumer starts after the services.
+func SetConsumerController(controller ConsumerController) {
+	consumerController = controller
+}
+
+// PauseConsumer stops pulling order events until ResumeConsumer is called
+func (s *QueueService) PauseConsumer(ctx context.Context, userID string) (*models.ConsumerStatus, error) {
+	if consumerController == nil {
+		return nil, ErrConsumerUnavailable
+	}
+	status := consumerController.Pause(userID)
+	return &status, nil
+}
+
+// ResumeConsumer resumes pulling order events
+func (s *QueueService) ResumeConsumer(ctx context.Context) (*models.ConsumerStatus, error) {
+	if consumerController == nil {
+		return nil, ErrConsumerUnavailable
+	}
+	status := consumerController.Resume()
+	return &status, nil
+}
+
+// GetConsumerStatus reports whether order event consumption is paused
+func (s *QueueService) GetConsumerStatus(ctx context.Context) (*models.ConsumerStatus, error) {
+	if consumerController == nil {
+		return nil, ErrConsumerUnavailable
+	}
+	status := consumerController.Status()
+	return &status, nil
+}
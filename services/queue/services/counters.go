@@ -0,0 +1,465 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnknownCounter is returned when an entry is assigned to a counter that doesn't exist
+	ErrUnknownCounter = newError(KindInvalid, "UNKNOWN_COUNTER", "unknown counter")
+	// ErrCounterClosed is returned when an entry is assigned to a closed counter
+	ErrCounterClosed = newError(KindInvalid, "COUNTER_CLOSED", "counter is closed")
+	// ErrCounterExists is returned when a counter name is already taken
+	ErrCounterExists = newError(KindConflict, "COUNTER_EXISTS", "a counter with this name already exists")
+	// ErrCounterInUse is returned when deleting a counter that still has active entries
+	ErrCounterInUse = newError(KindConflict, "COUNTER_IN_USE", "counter still has active entries")
+)
+
+// GetCounters lists the location's counters with their current active load and the token they are serving
+func (s *QueueService) GetCounters(ctx context.Context) ([]models.CounterStatusResponse, error) {
+	var counters []models.QueueCounter
+	if err := s.db.WithContext(ctx).Where("location_id = ?", LocationFromContext(ctx)).Order("name ASC").Find(&counters).Error; err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(counters))
+	var servingIDs []string
+	for i, counter := range counters {
+		ids[i] = counter.ID
+		if counter.ServingEntryID != nil {
+			servingIDs = append(servingIDs, *counter.ServingEntryID)
+		}
+	}
+
+	loads, err := s.counterLoads(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := make(map[string]string, len(servingIDs))
+	if len(servingIDs) > 0 {
+		var serving []models.QueueEntry
+		if err := s.db.WithContext(ctx).Select("id", "token_number").Where("id IN ?", servingIDs).Find(&serving).Error; err != nil {
+			return nil, err
+		}
+		for _, entry := range serving {
+			tokens[entry.ID] = entry.TokenNumber
+		}
+	}
+
+	result := make([]models.CounterStatusResponse, len(counters))
+	for i, counter := range counters {
+		result[i] = models.CounterStatusResponse{
+			QueueCounter:  counter,
+			ActiveEntries: loads[counter.ID],
+		}
+		if counter.ServingEntryID != nil {
+			if token, ok := tokens[*counter.ServingEntryID]; ok {
+				result[i].NowServing = &token
+			}
+		}
+	}
+	return result, nil
+}
+
+// CreateCounter adds an open counter at the request's location
+func (s *QueueService) CreateCounter(ctx context.Context, req *models.CounterRequest) (*models.QueueCounter, error) {
+	now := s.clock.Now().UTC()
+	counter := &models.QueueCounter{
+		ID:         utils.GenerateUUID(),
+		LocationID: LocationFromContext(ctx),
+		Name:       req.Name,
+		IsOpen:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.db.WithContext(ctx).Create(counter).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrCounterExists
+		}
+		return nil, err
+	}
+	return counter, nil
+}
+
+// RenameCounter renames a counter, along with the counter name shown on its active entries
+func (s *QueueService) RenameCounter(ctx context.Context, counterID string, req *models.CounterRequest) (*models.QueueCounter, error) {
+	var counter models.QueueCounter
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", counterID).First(&counter).Error; err != nil {
+		return nil, err
+	}
+
+	var entryIDs []string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&counter).Updates(map[string]interface{}{
+			"name":       req.Name,
+			"updated_at": s.clock.Now().UTC(),
+		}).Error; err != nil {
+			return err
+		}
+
+		active := tx.Model(&models.QueueEntry{}).
+			Where("counter_id = ? AND status IN ?", counterID, []string{"WAITING", "IN_PROGRESS", "READY"})
+		if err := active.Pluck("id", &entryIDs).Error; err != nil {
+			return err
+		}
+		if len(entryIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&models.QueueEntry{}).Where("id IN ?", entryIDs).Update("assigned_counter", req.Name).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrCounterExists
+		}
+		return nil, err
+	}
+	counter.Name = req.Name
+
+	for _, entryID := range entryIDs {
+		utils.InvalidateQueueCache(ctx, entryID)
+	}
+	if len(entryIDs) > 0 {
+		s.queueChanged(ctx, entryIDs...)
+	}
+
+	return &counter, nil
+}
+
+// DeleteCounter removes a counter that no active entry is assigned to
+func (s *QueueService) DeleteCounter(ctx context.Context, counterID string) error {
+	var counter models.QueueCounter
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", counterID).First(&counter).Error; err != nil {
+		return err
+	}
+
+	// Ready orders still wait for pickup at the counter
+	var active int64
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("counter_id = ? AND status IN ?", counterID, []string{"WAITING", "IN_PROGRESS", "READY"}).
+		Count(&active).Error; err != nil {
+		return err
+	}
+	if active > 0 {
+		return ErrCounterInUse
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Past entries keep the counter name in assigned_counter
+		if err := tx.Model(&models.QueueEntry{}).Where("counter_id = ?", counterID).Update("counter_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&counter).Error
+	})
+}
+
+// resolveCounter finds the counter at the entry's location (that of ctx) an
+// entry is being assigned to, by ID or else by name. It returns nil when
+// neither is given.
+func (s *QueueService) resolveCounter(ctx context.Context, counterID, name *string) (*models.QueueCounter, error) {
+	query := s.db.WithContext(ctx).Where("location_id = ?", LocationFromContext(ctx))
+	switch {
+	case counterID != nil:
+		query = query.Where("id = ?", *counterID)
+	case name != nil:
+		query = query.Where("name = ?", *name)
+	default:
+		return nil, nil
+	}
+
+	var counter models.QueueCounter
+	if err := query.First(&counter).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnknownCounter
+		}
+		return nil, err
+	}
+	if !counter.IsOpen {
+		return nil, ErrCounterClosed
+	}
+	return &counter, nil
+}
+
+// updateCounterServing keeps track of which entry each counter is serving:
+// an entry being prepared or ready at its counter is served there until it
+// leaves the queue or moves to another counter
+func (s *QueueService) updateCounterServing(ctx context.Context, entry *models.QueueEntry) {
+	serving := entry.CounterID != nil && (entry.Status == "IN_PROGRESS" || entry.Status == "READY")
+
+	clear := s.db.WithContext(ctx).Model(&models.QueueCounter{}).Where("serving_entry_id = ?", entry.ID)
+	if serving {
+		clear = clear.Where("id <> ?", *entry.CounterID)
+	}
+	if err := clear.Updates(map[string]interface{}{
+		"serving_entry_id": nil,
+		"serving_since":    nil,
+	}).Error; err != nil {
+		log.Printf("Failed to clear counter serving %s: %v", entry.TokenNumber, err)
+	}
+
+	if !serving {
+		return
+	}
+	if err := s.db.WithContext(ctx).Model(&models.QueueCounter{}).
+		Where("id = ? AND (serving_entry_id IS NULL OR serving_entry_id <> ?)", *entry.CounterID, entry.ID).
+		Updates(map[string]interface{}{
+			"serving_entry_id": entry.ID,
+			"serving_since":    s.clock.Now().UTC(),
+		}).Error; err != nil {
+		log.Printf("Failed to record counter serving %s: %v", entry.TokenNumber, err)
+	}
+}
+
+// nowServing lists the token each open counter of the location is serving, for the display
+func (s *QueueService) nowServing(ctx context.Context, active []models.QueueEntry) []models.NowServing {
+	result := make([]models.NowServing, 0)
+
+	var counters []models.QueueCounter
+	if err := s.db.WithContext(ctx).
+		Where("location_id = ? AND is_open = ? AND serving_entry_id IS NOT NULL", LocationFromContext(ctx), true).
+		Order("name ASC").
+		Find(&counters).Error; err != nil {
+		log.Printf("Failed to load counters for display: %v", err)
+		return result
+	}
+	if len(counters) == 0 {
+		return result
+	}
+
+	byID := make(map[string]*models.QueueEntry, len(active))
+	for i := range active {
+		byID[active[i].ID] = &active[i]
+	}
+
+	for _, counter := range counters {
+		entry, ok := byID[*counter.ServingEntryID]
+		if !ok || counter.ServingSince == nil {
+			continue
+		}
+		result = append(result, models.NowServing{
+			CounterID:    counter.ID,
+			Counter:      counter.Name,
+			QueueEntryID: entry.ID,
+			TokenNumber:  entry.TokenNumber,
+			Status:       entry.Status,
+			Since:        *counter.ServingSince,
+		})
+	}
+	return result
+}
+
+// OpenCounter reopens a counter so it can receive entries again
+func (s *QueueService) OpenCounter(ctx context.Context, counterID string) (*models.QueueCounter, error) {
+	var counter models.QueueCounter
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", counterID).First(&counter).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Model(&counter).Updates(map[string]interface{}{
+		"is_open":    true,
+		"updated_at": s.clock.Now().UTC(),
+	}).Error; err != nil {
+		return nil, err
+	}
+	counter.IsOpen = true
+
+	return &counter, nil
+}
+
+// CloseCounter closes a counter and redistributes its active entries across open counters
+func (s *QueueService) CloseCounter(ctx context.Context, counterID string, staffID string, staffName string) (*models.CloseCounterResponse, error) {
+	var counter models.QueueCounter
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", counterID).First(&counter).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Model(&counter).Updates(map[string]interface{}{
+		"is_open":    false,
+		"updated_at": s.clock.Now().UTC(),
+	}).Error; err != nil {
+		return nil, err
+	}
+	counter.IsOpen = false
+
+	reassignments, err := s.rebalanceCounter(WithLocation(ctx, counter.LocationID), &counter, staffID, staffName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CloseCounterResponse{
+		Counter:       &counter,
+		Reassignments: reassignments,
+	}, nil
+}
+
+// rebalanceCounter moves WAITING/IN_PROGRESS entries off a closed counter,
+// always picking the open counter with the lowest current load
+func (s *QueueService) rebalanceCounter(ctx context.Context, closed *models.QueueCounter, staffID string, staffName string) ([]models.CounterReassignment, error) {
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Where("counter_id = ? AND status IN ?", closed.ID, []string{"WAITING", "IN_PROGRESS"}).
+		Order("position ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return []models.CounterReassignment{}, nil
+	}
+
+	var open []models.QueueCounter
+	if err := s.db.WithContext(ctx).Where("location_id = ? AND is_open = ?", closed.LocationID, true).Order("name ASC").Find(&open).Error; err != nil {
+		return nil, err
+	}
+
+	// Prefer counters someone is on shift at, unless nobody is
+	staffed, err := s.staffedCounterIDs(ctx, closed.LocationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(staffed) > 0 {
+		manned := open[:0]
+		for _, counter := range open {
+			if staffed[counter.ID] {
+				manned = append(manned, counter)
+			}
+		}
+		if len(manned) > 0 {
+			open = manned
+		}
+	}
+
+	openIDs := make([]string, len(open))
+	for i, counter := range open {
+		openIDs[i] = counter.ID
+	}
+	loads, err := s.counterLoads(ctx, openIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := s.GetConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reassignments := make([]models.CounterReassignment, 0, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+
+		var target *models.QueueCounter
+		if len(open) > 0 {
+			// Least loaded first, then by name
+			sort.Slice(open, func(a, b int) bool {
+				if loads[open[a].ID] != loads[open[b].ID] {
+					return loads[open[a].ID] < loads[open[b].ID]
+				}
+				return open[a].Name < open[b].Name
+			})
+			picked := open[0]
+			loads[picked.ID]++
+			target = &picked
+		}
+
+		// Waiting customers may now be behind a longer line
+		newWaitTime := entry.EstimatedWaitTime
+		if target != nil && entry.Status == "WAITING" {
+			counterWait := utils.CalculateEstimatedWaitTime(loads[target.ID], config.AvgPreparationTimePerItem, config.BufferTime)
+			if counterWait > newWaitTime {
+				newWaitTime = counterWait
+			}
+		}
+
+		var targetID, targetName *string
+		if target != nil {
+			targetID = &target.ID
+			targetName = &target.Name
+		}
+		updates := map[string]interface{}{
+			"assigned_counter": targetName,
+			"counter_id":       targetID,
+			"updated_at":       s.clock.Now().UTC(),
+		}
+		etaChanged := newWaitTime != entry.EstimatedWaitTime
+		if etaChanged {
+			readyTime := utils.CalculateEstimatedReadyTime(s.clock.Now(), newWaitTime)
+			updates["estimated_wait_time"] = newWaitTime
+			updates["estimated_ready_time"] = readyTime
+			entry.EstimatedReadyTime = &readyTime
+		}
+
+		if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).Where("id = ?", entry.ID).Updates(updates).Error; err != nil {
+			return reassignments, err
+		}
+
+		reason := fmt.Sprintf("Counter %s closed; unassigned", closed.Name)
+		if target != nil {
+			reason = fmt.Sprintf("Counter %s closed; reassigned to %s", closed.Name, target.Name)
+		}
+		s.LogStaffAction(ctx, entry.ID, staffID, staffName, "REASSIGN", nil, nil, nil, nil, &reason)
+		utils.InvalidateQueueCache(ctx, entry.ID)
+		s.queueChanged(ctx, entry.ID)
+
+		oldWaitTime := entry.EstimatedWaitTime
+		entry.AssignedCounter = targetName
+		entry.CounterID = targetID
+		entry.EstimatedWaitTime = newWaitTime
+		s.updateCounterServing(ctx, entry)
+
+		notified := false
+		if etaChanged && s.publisher != nil {
+			if err := s.publisher.PublishQueuePositionUpdate(ctx, entry); err != nil {
+				log.Printf("Failed to notify token=%s of ETA change: %v", entry.TokenNumber, err)
+			} else {
+				notified = true
+			}
+		}
+
+		reassignments = append(reassignments, models.CounterReassignment{
+			QueueEntryID:     entry.ID,
+			TokenNumber:      entry.TokenNumber,
+			FromCounter:      closed.Name,
+			ToCounter:        targetName,
+			OldWaitTime:      oldWaitTime,
+			NewWaitTime:      newWaitTime,
+			CustomerNotified: notified,
+		})
+	}
+
+	log.Printf("Rebalanced %d entries off closed counter %s", len(reassignments), closed.Name)
+	return reassignments, nil
+}
+
+// counterLoads counts active entries per counter ID
+func (s *QueueService) counterLoads(ctx context.Context, ids []string) (map[string]int, error) {
+	loads := make(map[string]int, len(ids))
+	if len(ids) == 0 {
+		return loads, nil
+	}
+
+	var rows []struct {
+		CounterID string
+		Count     int
+	}
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Select("counter_id, COUNT(*) AS count").
+		Where("counter_id IN ? AND status IN ?", ids, []string{"WAITING", "IN_PROGRESS"}).
+		Group("counter_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		loads[row.CounterID] = row.Count
+	}
+	return loads, nil
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/database"
+	"gin-quickstart/kpi"
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidKPIExpression is returned when a KPI expression doesn't parse or
+// references unknown counters
+var ErrInvalidKPIExpression = newError(KindInvalid, "INVALID_KPI_EXPRESSION", "invalid KPI expression")
+
+// kpiCountersTTL is how long a day's counters are reused by the stats
+// response, so polling it doesn't scan the day's entries every time
+const kpiCountersTTL = 30 * time.Second
+
+func kpiCountersKey(locationID string, date time.Time) string {
+	return fmt.Sprintf("queue:kpis:%s:%s", locationID, date.Format("2006-01-02"))
+}
+
+// KPICounterNames lists the counters custom KPI expressions may reference
+var KPICounterNames = []string{
+	"total",
+	"waiting",
+	"in_progress",
+	"ready",
+	"completed",
+	"cancelled",
+	"no_show",
+	"expired",
+	"express_total",
+	"express_completed",
+	"high_priority_total",
+	"avg_wait_time",
+	"avg_preparation_time",
+	"timed_ready",
+	"on_time_ready",
+}
+
+// ListKPIDefinitions lists all custom KPI definitions
+func (s *QueueService) ListKPIDefinitions(ctx context.Context) ([]models.KPIDefinition, error) {
+	var definitions []models.KPIDefinition
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&definitions).Error; err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// CreateKPIDefinition validates and stores a custom KPI
+func (s *QueueService) CreateKPIDefinition(ctx context.Context, req *models.CreateKPIDefinitionRequest, userID string) (*models.KPIDefinition, error) {
+	if err := validateKPIExpression(req.Expression); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now().UTC()
+	definition := &models.KPIDefinition{
+		ID:          utils.GenerateUUID(),
+		Name:        req.Name,
+		Expression:  req.Expression,
+		Description: req.Description,
+		IsActive:    true,
+		CreatedBy:   &userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.db.WithContext(ctx).Create(definition).Error; err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
+
+// UpdateKPIDefinition changes the expression, description or active flag of a custom KPI
+func (s *QueueService) UpdateKPIDefinition(ctx context.Context, kpiID string, req *models.UpdateKPIDefinitionRequest) (*models.KPIDefinition, error) {
+	var definition models.KPIDefinition
+	if err := s.db.WithContext(ctx).Where("id = ?", kpiID).First(&definition).Error; err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"updated_at": s.clock.Now().UTC(),
+	}
+	if req.Expression != nil {
+		if err := validateKPIExpression(*req.Expression); err != nil {
+			return nil, err
+		}
+		updates["expression"] = *req.Expression
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if err := s.db.WithContext(ctx).Model(&definition).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.db.WithContext(ctx).Where("id = ?", kpiID).First(&definition).Error; err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// DeleteKPIDefinition removes a custom KPI
+func (s *QueueService) DeleteKPIDefinition(ctx context.Context, kpiID string) error {
+	result := s.db.WithContext(ctx).Where("id = ?", kpiID).Delete(&models.KPIDefinition{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetKPIReport returns the daily counters and every active custom KPI for a day
+func (s *QueueService) GetKPIReport(ctx context.Context, date *time.Time) (*models.KPIReportResponse, error) {
+	targetDate := clock.Date(s.clock.Now())
+	if date != nil {
+		targetDate = date.Truncate(24 * time.Hour)
+	}
+
+	counters, err := s.kpiCounters(ctx, targetDate)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := s.evaluateCustomKPIs(ctx, counters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.KPIReportResponse{
+		Date:     targetDate.Format("2006-01-02"),
+		Counters: counters,
+		KPIs:     values,
+	}, nil
+}
+
+// customKPIsFor evaluates active custom KPIs for the stats response; failures only drop the KPIs
+func (s *QueueService) customKPIsFor(ctx context.Context, targetDate time.Time) []models.CustomKPIValue {
+	counters, err := s.cachedKPICounters(ctx, targetDate)
+	if err != nil {
+		log.Printf("Failed to compute KPI counters: %v", err)
+		return nil
+	}
+
+	values, err := s.evaluateCustomKPIs(ctx, counters)
+	if err != nil {
+		log.Printf("Failed to evaluate custom KPIs: %v", err)
+		return nil
+	}
+	return values
+}
+
+func (s *QueueService) evaluateCustomKPIs(ctx context.Context, counters map[string]float64) ([]models.CustomKPIValue, error) {
+	var definitions []models.KPIDefinition
+	if err := s.db.WithContext(ctx).Where("is_active = ?", true).Order("name ASC").Find(&definitions).Error; err != nil {
+		return nil, err
+	}
+
+	values := make([]models.CustomKPIValue, 0, len(definitions))
+	for _, definition := range definitions {
+		value := models.CustomKPIValue{
+			Name:       definition.Name,
+			Expression: definition.Expression,
+		}
+
+		expr, err := kpi.Parse(definition.Expression)
+		if err == nil {
+			var result float64
+			if result, err = expr.Eval(counters); err == nil {
+				value.Value = utils.Float64Ptr(roundRate(result))
+			}
+		}
+		if err != nil {
+			value.Error = err.Error()
+		}
+
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// cachedKPICounters returns the day's counters as computed within the last
+// kpiCountersTTL, sharing one computation among concurrent callers
+func (s *QueueService) cachedKPICounters(ctx context.Context, targetDate time.Time) (map[string]float64, error) {
+	key := kpiCountersKey(LocationFromContext(ctx), targetDate)
+	rdb := database.GetRedis()
+	if rdb != nil {
+		if data, err := rdb.Get(ctx, key).Bytes(); err == nil {
+			var counters map[string]float64
+			if err := json.Unmarshal(data, &counters); err == nil {
+				return counters, nil
+			}
+		}
+	}
+
+	loaded, err, _ := cacheFills.Do(key, func() (interface{}, error) {
+		ctx := context.WithoutCancel(ctx)
+		counters, err := s.kpiCounters(ctx, targetDate)
+		if err != nil {
+			return nil, err
+		}
+		if rdb != nil {
+			data, _ := json.Marshal(counters)
+			if err := rdb.Set(ctx, key, data, kpiCountersTTL).Err(); err != nil {
+				log.Printf("Failed to cache KPI counters: %v", err)
+			}
+		}
+		return counters, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loaded.(map[string]float64), nil
+}
+
+// kpiCounters computes the named counters for the location's entries created on a day
+func (s *QueueService) kpiCounters(ctx context.Context, targetDate time.Time) (map[string]float64, error) {
+	dayStart, dayEnd := clock.DayBounds(targetDate)
+
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Select("status", "priority", "is_express_queue", "estimated_ready_time", "actual_start_time", "actual_ready_time", "created_at").
+		Where("location_id = ? AND created_at >= ? AND created_at < ?", LocationFromContext(ctx), dayStart, dayEnd).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]float64, len(KPICounterNames))
+	for _, name := range KPICounterNames {
+		counters[name] = 0
+	}
+
+	var waitTotal, waitCount, prepTotal, prepCount float64
+	for _, entry := range entries {
+		counters["total"]++
+
+		switch entry.Status {
+		case "WAITING":
+			counters["waiting"]++
+		case "IN_PROGRESS":
+			counters["in_progress"]++
+		case "READY":
+			counters["ready"]++
+		case "COMPLETED":
+			counters["completed"]++
+		case "CANCELLED":
+			counters["cancelled"]++
+		case "NO_SHOW":
+			counters["no_show"]++
+		case "EXPIRED":
+			counters["expired"]++
+		}
+
+		if entry.IsExpressQueue {
+			counters["express_total"]++
+			if entry.Status == "COMPLETED" {
+				counters["express_completed"]++
+			}
+		}
+
+		switch entry.Priority {
+		case "HIGH", "URGENT", "VIP":
+			counters["high_priority_total"]++
+		}
+
+		if entry.ActualStartTime != nil {
+			waitTotal += entry.ActualStartTime.Sub(entry.CreatedAt).Minutes()
+			waitCount++
+			if entry.ActualReadyTime != nil {
+				prepTotal += entry.ActualReadyTime.Sub(*entry.ActualStartTime).Minutes()
+				prepCount++
+			}
+		}
+
+		if entry.ActualReadyTime != nil && entry.EstimatedReadyTime != nil {
+			counters["timed_ready"]++
+			if !entry.ActualReadyTime.After(*entry.EstimatedReadyTime) {
+				counters["on_time_ready"]++
+			}
+		}
+	}
+
+	if waitCount > 0 {
+		counters["avg_wait_time"] = roundRate(waitTotal / waitCount)
+	}
+	if prepCount > 0 {
+		counters["avg_preparation_time"] = roundRate(prepTotal / prepCount)
+	}
+
+	return counters, nil
+}
+
+// validateKPIExpression checks syntax and that every referenced counter exists
+func validateKPIExpression(expression string) error {
+	expr, err := kpi.Parse(expression)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidKPIExpression, err)
+	}
+
+	known := make(map[string]bool, len(KPICounterNames))
+	for _, name := range KPICounterNames {
+		known[name] = true
+	}
+
+	var unknown []string
+	for _, name := range expr.Variables() {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: unknown counters %v; available counters: %v", ErrInvalidKPIExpression, unknown, KPICounterNames)
+	}
+	return nil
+}
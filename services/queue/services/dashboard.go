@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"gin-quickstart/models"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// maxDashboardAtRisk caps the at-risk list on the dashboard
+const maxDashboardAtRisk = 20
+
+// GetDashboard assembles the staff home screen concurrently: queue summary,
+// at-risk entries, counters, today's stats and active announcements
+func (s *QueueService) GetDashboard(ctx context.Context) (*models.DashboardResponse, error) {
+	dashboard := &models.DashboardResponse{GeneratedAt: s.clock.Now().UTC()}
+
+	g, gctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		summary, err := s.queueSummary(gctx)
+		if err != nil {
+			return fmt.Errorf("queue summary: %w", err)
+		}
+		dashboard.Queue = *summary
+		return nil
+	})
+
+	g.Go(func() error {
+		atRisk, err := s.atRiskEntries(gctx, maxDashboardAtRisk)
+		if err != nil {
+			return fmt.Errorf("at-risk entries: %w", err)
+		}
+		dashboard.AtRisk = atRisk
+		return nil
+	})
+
+	g.Go(func() error {
+		counters, err := s.GetCounters(gctx)
+		if err != nil {
+			return fmt.Errorf("counters: %w", err)
+		}
+		dashboard.Counters = counters
+		return nil
+	})
+
+	g.Go(func() error {
+		stats, err := s.GetQueueStatistics(gctx, nil)
+		if err != nil {
+			return fmt.Errorf("statistics: %w", err)
+		}
+		dashboard.Stats = stats
+		return nil
+	})
+
+	g.Go(func() error {
+		announcements, err := s.activeAnnouncements(gctx)
+		if err != nil {
+			return fmt.Errorf("announcements: %w", err)
+		}
+		dashboard.Announcements = announcements
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return dashboard, nil
+}
+
+// queueSummary counts the location's active entries by status
+func (s *QueueService) queueSummary(ctx context.Context) (*models.QueueSummary, error) {
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Select("status, COUNT(*) AS count").
+		Where("location_id = ? AND status IN ?", LocationFromContext(ctx), []string{"WAITING", "IN_PROGRESS", "READY"}).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	summary := &models.QueueSummary{}
+	for _, row := range rows {
+		switch row.Status {
+		case "WAITING":
+			summary.WaitingCount = row.Count
+		case "IN_PROGRESS":
+			summary.InProgressCount = row.Count
+		case "READY":
+			summary.ReadyCount = row.Count
+		}
+		summary.TotalActive += row.Count
+	}
+	return summary, nil
+}
+
+// atRiskEntries lists the location's active entries that are past their estimated ready time
+// or whose projected total wait exceeds the configured wait alert, longest wait first
+func (s *QueueService) atRiskEntries(ctx context.Context, limit int) ([]models.AtRiskEntry, error) {
+	config, err := s.GetConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Where("location_id = ? AND status IN ?", LocationFromContext(ctx), []string{"WAITING", "IN_PROGRESS"}).
+		Order("created_at ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now().UTC()
+	atRisk := []models.AtRiskEntry{}
+	for _, entry := range entries {
+		waited := int(now.Sub(entry.CreatedAt).Minutes())
+		projected := waited
+		if entry.EstimatedReadyTime != nil && entry.EstimatedReadyTime.After(now) {
+			projected += int(entry.EstimatedReadyTime.Sub(now).Minutes())
+		}
+
+		var reason string
+		switch {
+		case entry.EstimatedReadyTime != nil && entry.EstimatedReadyTime.Before(now):
+			reason = fmt.Sprintf("Past estimated ready time by %d min", int(now.Sub(*entry.EstimatedReadyTime).Minutes()))
+		case config.MaxWaitTimeAlert > 0 && projected >= config.MaxWaitTimeAlert:
+			reason = fmt.Sprintf("Projected wait %d min exceeds alert of %d min", projected, config.MaxWaitTimeAlert)
+		default:
+			continue
+		}
+
+		atRisk = append(atRisk, models.AtRiskEntry{
+			QueueEntryID:       entry.ID,
+			TokenNumber:        entry.TokenNumber,
+			Status:             entry.Status,
+			Priority:           entry.Priority,
+			IsExpressQueue:     entry.IsExpressQueue,
+			Position:           entry.Position,
+			WaitedMinutes:      waited,
+			ProjectedMinutes:   projected,
+			EstimatedReadyTime: entry.EstimatedReadyTime,
+			Reason:             reason,
+		})
+	}
+
+	sort.SliceStable(atRisk, func(i, j int) bool {
+		return atRisk[i].WaitedMinutes > atRisk[j].WaitedMinutes
+	})
+	if len(atRisk) > limit {
+		atRisk = atRisk[:limit]
+	}
+	return atRisk, nil
+}
+
+// activeAnnouncements lists announcements currently on display, highest priority first
+func (s *QueueService) activeAnnouncements(ctx context.Context) ([]models.QueueDisplayAnnouncement, error) {
+	var announcements []models.QueueDisplayAnnouncement
+	if err := s.db.WithContext(ctx).
+		Where("is_active = ? AND (display_until IS NULL OR display_until > ?)", true, s.clock.Now().UTC()).
+		Order("priority DESC, created_at DESC").
+		Find(&announcements).Error; err != nil {
+		return nil, err
+	}
+	return announcements, nil
+}
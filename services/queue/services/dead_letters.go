@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+)
+
+const maxDeadLetterPageSize = 200
+
+// Re-drive errors
+var (
+	// ErrRedriveUnavailable is returned when there is no publisher to re-drive with
+	ErrRedriveUnavailable = newError(KindUnavailable, "REDRIVE_UNAVAILABLE", "event publisher not available")
+	// ErrDeadLetterDiscarded is returned when re-driving a discarded dead letter
+	ErrDeadLetterDiscarded = newError(KindConflict, "DEAD_LETTER_DISCARDED", "dead letter was discarded")
+)
+
+// RecordDeadLetter stores a failed message so it can be inspected and
+// re-driven. A payload the text column can't hold (invalid UTF-8 or NUL
+// bytes, as protobuf and Avro values often are) is stored base64-encoded.
+func (s *QueueService) RecordDeadLetter(ctx context.Context, message *models.DeadLetterMessage) error {
+	if message.ID == "" {
+		message.ID = utils.GenerateUUID()
+	}
+	if message.PayloadEncoding == "" {
+		message.PayloadEncoding = "TEXT"
+		if !utf8.ValidString(message.Payload) || strings.ContainsRune(message.Payload, 0) {
+			message.Payload = base64.StdEncoding.EncodeToString([]byte(message.Payload))
+			message.PayloadEncoding = "BASE64"
+		}
+	}
+	if message.Status == "" {
+		message.Status = "PENDING"
+	}
+	if message.FailedAt.IsZero() {
+		message.FailedAt = s.clock.Now().UTC()
+	}
+	return s.db.WithContext(ctx).Create(message).Error
+}
+
+// ListDeadLetters lists dead letter messages, newest first
+func (s *QueueService) ListDeadLetters(ctx context.Context, status, topic string, limit, offset int) (*models.DeadLetterListResponse, error) {
+	if limit <= 0 || limit > maxDeadLetterPageSize {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := s.db.WithContext(ctx).Model(&models.DeadLetterMessage{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if topic != "" {
+		query = query.Where("original_topic = ?", topic)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var messages []models.DeadLetterMessage
+	if err := query.Order("failed_at DESC").Limit(limit).Offset(offset).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.DeadLetterListResponse{
+		Messages: messages,
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	}, nil
+}
+
+// RedriveDeadLetter republishes a dead letter to its original topic
+func (s *QueueService) RedriveDeadLetter(ctx context.Context, messageID string, userID string) (*models.DeadLetterMessage, error) {
+	if s.publisher == nil {
+		return nil, ErrRedriveUnavailable
+	}
+
+	var message models.DeadLetterMessage
+	if err := s.db.WithContext(ctx).Where("id = ?", messageID).First(&message).Error; err != nil {
+		return nil, err
+	}
+
+	if message.Status == "DISCARDED" {
+		return nil, fmt.Errorf("%w: %s", ErrDeadLetterDiscarded, messageID)
+	}
+
+	payload := []byte(message.Payload)
+	if message.PayloadEncoding == "BASE64" {
+		decoded, err := base64.StdEncoding.DecodeString(message.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode dead letter payload: %w", err)
+		}
+		payload = decoded
+	}
+	if err := s.publisher.PublishRaw(ctx, message.OriginalTopic, message.MessageKey, payload); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now().UTC()
+	if err := s.db.WithContext(ctx).Model(&message).Updates(map[string]interface{}{
+		"status":           "REDRIVEN",
+		"redrive_count":    message.RedriveCount + 1,
+		"last_redriven_at": now,
+		"last_redriven_by": userID,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	message.Status = "REDRIVEN"
+	message.RedriveCount++
+	message.LastRedrivenAt = &now
+	message.LastRedrivenBy = &userID
+
+	return &message, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gin-quickstart/metrics"
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidDepthLimit is returned for a depth limit with nowhere to fall back to
+var ErrInvalidDepthLimit = newError(KindInvalid, "INVALID_DEPTH_LIMIT", "invalid depth limit")
+
+// priorityLadder orders priorities from highest to lowest. LOW is the floor
+// and cannot be limited, so every entry always has a place to land.
+var priorityLadder = []string{"VIP", "URGENT", "HIGH", "NORMAL", "LOW"}
+
+// ListDepthLimits lists the configured depth limits
+func (s *QueueService) ListDepthLimits(ctx context.Context) ([]models.QueueDepthLimit, error) {
+	var limits []models.QueueDepthLimit
+	if err := s.db.WithContext(ctx).Order("lane ASC, priority ASC").Find(&limits).Error; err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// SetDepthLimit creates or replaces the limit for a lane and priority
+func (s *QueueService) SetDepthLimit(ctx context.Context, req *models.SetDepthLimitRequest, userID string) (*models.QueueDepthLimit, error) {
+	lane := req.Lane
+	if lane == "" {
+		lane = models.LaneAny
+	}
+	priority := req.Priority
+	if priority == "" {
+		priority = models.LaneAny
+	}
+
+	// A lane-wide cap only makes sense for the express lane, which overflows
+	// into the regular lane; other lanes have nothing to fall back to
+	if priority == models.LaneAny && lane != models.LaneExpress {
+		return nil, fmt.Errorf("%w: a limit across all priorities is only supported for the EXPRESS lane", ErrInvalidDepthLimit)
+	}
+
+	now := s.clock.Now().UTC()
+	limit := &models.QueueDepthLimit{
+		ID:         utils.GenerateUUID(),
+		Lane:       lane,
+		Priority:   priority,
+		MaxWaiting: *req.MaxWaiting,
+		CreatedBy:  &userID,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "lane"}, {Name: "priority"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_waiting", "updated_at"}),
+	}).Create(limit).Error; err != nil {
+		return nil, err
+	}
+
+	// Re-read so an updated limit keeps its original ID
+	var stored models.QueueDepthLimit
+	if err := s.db.WithContext(ctx).Where("lane = ? AND priority = ?", lane, priority).First(&stored).Error; err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// DeleteDepthLimit removes a depth limit
+func (s *QueueService) DeleteDepthLimit(ctx context.Context, limitID string) error {
+	var limit models.QueueDepthLimit
+	if err := s.db.WithContext(ctx).Where("id = ?", limitID).First(&limit).Error; err != nil {
+		return err
+	}
+	return s.db.WithContext(ctx).Delete(&limit).Error
+}
+
+// applyDepthLimits places a new entry in the highest lane and priority at or
+// below the requested ones that still has room. The returned notes explain any
+// fallback. Limits are checked before insert, so concurrent creates may
+// overshoot a cap by a few entries.
+func (s *QueueService) applyDepthLimits(ctx context.Context, isExpress bool, priority string) (bool, string, []string, error) {
+	var limits []models.QueueDepthLimit
+	if err := s.db.WithContext(ctx).Find(&limits).Error; err != nil {
+		return isExpress, priority, nil, err
+	}
+	if len(limits) == 0 {
+		return isExpress, priority, nil, nil
+	}
+
+	var notes []string
+
+	if isExpress {
+		if limit := findDepthLimit(limits, models.LaneExpress, models.LaneAny); limit != nil {
+			waiting, err := s.countWaiting(ctx, models.LaneExpress, "")
+			if err != nil {
+				return isExpress, priority, nil, err
+			}
+			if waiting >= int64(limit.MaxWaiting) {
+				isExpress = false
+				notes = append(notes, fmt.Sprintf("Express lane full (%d waiting); placed in the regular lane", waiting))
+				metrics.DepthLimitFallbacksTotal.WithLabelValues("lane", models.LaneExpress).Inc()
+			}
+		}
+	}
+
+	lane := models.LaneRegular
+	if isExpress {
+		lane = models.LaneExpress
+	}
+
+	start := indexOf(priorityLadder, priority)
+	if start < 0 {
+		return isExpress, priority, notes, nil
+	}
+
+	for i := start; i < len(priorityLadder)-1; i++ {
+		p := priorityLadder[i]
+		full, waiting, err := s.priorityFull(ctx, limits, lane, p)
+		if err != nil {
+			return isExpress, priority, nil, err
+		}
+		if !full {
+			break
+		}
+
+		next := priorityLadder[i+1]
+		notes = append(notes, fmt.Sprintf("%s priority full (%d waiting); queued as %s", p, waiting, next))
+		metrics.DepthLimitFallbacksTotal.WithLabelValues("priority", p).Inc()
+		priority = next
+	}
+
+	if len(notes) > 0 {
+		log.Printf("Depth limit fallback: lane=%s, priority=%s, notes=%v", lane, priority, notes)
+	}
+	return isExpress, priority, notes, nil
+}
+
+// priorityFull reports whether a lane-specific or all-lane limit for the
+// priority is reached
+func (s *QueueService) priorityFull(ctx context.Context, limits []models.QueueDepthLimit, lane, priority string) (bool, int64, error) {
+	for _, scope := range []string{lane, models.LaneAny} {
+		limit := findDepthLimit(limits, scope, priority)
+		if limit == nil {
+			continue
+		}
+		waiting, err := s.countWaiting(ctx, scope, priority)
+		if err != nil {
+			return false, 0, err
+		}
+		if waiting >= int64(limit.MaxWaiting) {
+			return true, waiting, nil
+		}
+	}
+	return false, 0, nil
+}
+
+// countWaiting counts the location's WAITING entries in a lane (ANY for all)
+// and, if set, priority. Limits apply to each location's queue separately.
+func (s *QueueService) countWaiting(ctx context.Context, lane, priority string) (int64, error) {
+	query := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("location_id = ? AND status = ?", LocationFromContext(ctx), "WAITING")
+	switch lane {
+	case models.LaneExpress:
+		query = query.Where("is_express_queue = ?", true)
+	case models.LaneRegular:
+		query = query.Where("is_express_queue = ?", false)
+	}
+	if priority != "" {
+		query = query.Where("priority = ?", priority)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count, err
+}
+
+func findDepthLimit(limits []models.QueueDepthLimit, lane, priority string) *models.QueueDepthLimit {
+	for i := range limits {
+		if limits[i].Lane == lane && limits[i].Priority == priority {
+			return &limits[i]
+		}
+	}
+	return nil
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm/clause"
+)
+
+// RegisterDeviceToken registers a device of the customer for push
+// notifications. A token already registered to another user (a shared or
+// resold device) moves to this one.
+func (s *QueueService) RegisterDeviceToken(ctx context.Context, userID string, req *models.RegisterDeviceTokenRequest) (*models.DeviceToken, error) {
+	now := s.clock.Now().UTC()
+	device := &models.DeviceToken{
+		ID:        utils.GenerateUUID(),
+		UserID:    userID,
+		Token:     req.Token,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if req.Platform != "" {
+		device.Platform = utils.StringPtr(req.Platform)
+	}
+
+	err := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "token"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "updated_at"}),
+		}).
+		Create(device).Error
+	if err != nil {
+		return nil, err
+	}
+	return device, nil
+}
+
+// RemoveDeviceToken unregisters one of the customer's devices. It reports
+// false when the customer had no such device.
+func (s *QueueService) RemoveDeviceToken(ctx context.Context, userID, token string) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Where("user_id = ? AND token = ?", userID, token).
+		Delete(&models.DeviceToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// deviceTokensFor returns the push tokens of the customer's devices
+func (s *QueueService) deviceTokensFor(ctx context.Context, userID string) []string {
+	var tokens []string
+	if err := s.db.WithContext(ctx).Model(&models.DeviceToken{}).
+		Where("user_id = ?", userID).
+		Pluck("token", &tokens).Error; err != nil {
+		log.Printf("Failed to load device tokens for %s: %v", userID, err)
+	}
+	return tokens
+}
+
+// forgetDeviceTokens deletes tokens the push provider reported as invalid
+func (s *QueueService) forgetDeviceTokens(ctx context.Context, tokens []string) {
+	if len(tokens) == 0 {
+		return
+	}
+	if err := s.db.WithContext(ctx).Where("token IN ?", tokens).Delete(&models.DeviceToken{}).Error; err != nil {
+		log.Printf("Failed to delete %d invalid device tokens: %v", len(tokens), err)
+		return
+	}
+	log.Printf("Deleted %d invalid device tokens", len(tokens))
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"gin-quickstart/models"
+
+	"gorm.io/gorm"
+)
+
+// entryExportBatchSize is how many entries an export loads at once
+const entryExportBatchSize = 500
+
+// ErrInvalidExportFilter is returned for an export filter naming an unknown status
+var ErrInvalidExportFilter = newError(KindInvalid, "INVALID_EXPORT_FILTER", "invalid export filter")
+
+// entryStatuses are every status an entry can be in
+var entryStatuses = []string{
+	"PENDING_PAYMENT", "SCHEDULED", "WAITING", "IN_PROGRESS", "READY",
+	"COMPLETED", "CANCELLED", "NO_SHOW", "EXPIRED",
+}
+
+// StreamQueueEntries passes the entries matching filter to fn a batch at a
+// time, oldest first. Requests scoped to a location only export its entries.
+func (s *QueueService) StreamQueueEntries(ctx context.Context, filter *models.QueueEntryExportFilter, fn func([]models.QueueEntry) error) error {
+	for _, status := range filter.Statuses {
+		if !slices.Contains(entryStatuses, status) {
+			return fmt.Errorf("%w: unknown status %s", ErrInvalidExportFilter, status)
+		}
+	}
+
+	query := inLocation(ctx, s.db.WithContext(ctx))
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.StaffID != "" {
+		query = query.Where("assigned_staff = ?", filter.StaffID)
+	}
+	if filter.CounterID != "" {
+		query = query.Where("counter_id = ?", filter.CounterID)
+	}
+	query = query.Order("created_at ASC, id ASC").Limit(entryExportBatchSize).Session(&gorm.Session{})
+
+	// Entries created in the same instant are told apart by their ID
+	batch := []models.QueueEntry{}
+	for cursor := query; ; {
+		batch = batch[:0]
+		if err := cursor.Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < entryExportBatchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		cursor = query.Where("(created_at > ? OR (created_at = ? AND id > ?))", last.CreatedAt, last.CreatedAt, last.ID)
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrorKind classifies a domain error; the API layers map it to their own
+// status codes (HTTP status, gRPC code)
+type ErrorKind int
+
+const (
+	// KindInvalid is a request that can never succeed as sent
+	KindInvalid ErrorKind = iota + 1
+	// KindNotFound is a request for something that doesn't exist
+	KindNotFound
+	// KindConflict is a request the current state of the queue doesn't allow
+	KindConflict
+	// KindUnavailable is a request a disabled or stopped component can't serve
+	KindUnavailable
+	// KindRateLimited is a request refused until the caller slows down
+	KindRateLimited
+)
+
+// Error is a domain error with a stable, machine-readable code clients can
+// branch on. Each one is a package-level sentinel, so errors.Is keeps working
+// through wrapping; errors.As recovers its kind and code.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(kind ErrorKind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// RetryError is a domain error that can be retried after a while
+type RetryError struct {
+	Err        *Error
+	RetryAfter time.Duration
+}
+
+func (e *RetryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter returns how long to wait before retrying err, if it says
+func RetryAfter(err error) (time.Duration, bool) {
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		return 0, false
+	}
+	return retryErr.RetryAfter, true
+}
+
+// AsError returns the domain error in err's chain, if there is one
+func AsError(err error) (*Error, bool) {
+	var domainErr *Error
+	ok := errors.As(err, &domainErr)
+	return domainErr, ok
+}
+
+var (
+	// ErrInvalidRequest is returned for invalid input that has no more specific error
+	ErrInvalidRequest = newError(KindInvalid, "INVALID_REQUEST", "invalid request")
+	// ErrNotFound is returned when a queue entry or other record doesn't exist
+	ErrNotFound = newError(KindNotFound, "NOT_FOUND", "not found")
+	// ErrInvalidTransition is returned when a status change isn't allowed from the entry's current status
+	ErrInvalidTransition = newError(KindConflict, "INVALID_TRANSITION", "invalid status transition")
+)
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"gin-quickstart/models"
+)
+
+// EventPublisher publishes queue events to downstream services
+type EventPublisher interface {
+	PublishQueuePositionUpdate(ctx context.Context, entry *models.QueueEntry) error
+	PublishQueueAlmostReady(ctx context.Context, entry *models.QueueEntry) error
+	PublishQueueReady(ctx context.Context, entry *models.QueueEntry) error
+	PublishQueueCancelled(ctx context.Context, entry *models.QueueEntry, reason *string) error
+	PublishQueueEntryTombstone(ctx context.Context, tombstone *models.QueueEntryTombstone) error
+	PublishBatchSuggestion(ctx context.Context, locationID string, suggestion *models.BatchSuggestion) error
+	PublishRaw(ctx context.Context, topic string, key *string, payload []byte) error
+	PublishQueueStageReady(ctx context.Context, entry *models.QueueEntry, stage *models.QueueEntryStage, remainingStages int) error
+	PublishSLABreached(ctx context.Context, entry *models.QueueEntry, waitedMinutes, slaMinutes int) error
+	PublishDayClosed(ctx context.Context, rollover *models.QueueDayRollover) error
+}
+
+var eventPublisher EventPublisher
+
+// SetEventPublisher sets the publisher used by queue services
+func SetEventPublisher(publisher EventPublisher) {
+	eventPublisher = publisher
+}
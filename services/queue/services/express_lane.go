@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"gin-quickstart/database"
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+)
+
+// The express lane keeps its own position sequence. Staff serve the lanes in
+// turn, RegularServeRatio regular entries then ExpressServeRatio express ones,
+// and each entry's ETA counts the other lane's entries served in between.
+
+// laneName names the lane an entry is in
+func laneName(isExpress bool) string {
+	if isExpress {
+		return models.LaneExpress
+	}
+	return models.LaneRegular
+}
+
+// serveRatios returns how many regular and express entries are served per turn
+func serveRatios(config *models.QueueConfiguration) (int, int) {
+	return max(config.RegularServeRatio, 1), max(config.ExpressServeRatio, 1)
+}
+
+// laneLengths counts the active entries of each lane at a location
+func (s *QueueService) laneLengths(ctx context.Context, locationID string) (int, int) {
+	var rows []struct {
+		IsExpressQueue bool
+		Count          int
+	}
+	s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Select("is_express_queue, COUNT(*) AS count").
+		Where("location_id = ? AND status IN ?", locationID, activeStatuses).
+		Group("is_express_queue").
+		Scan(&rows)
+
+	var regular, express int
+	for _, row := range rows {
+		if row.IsExpressQueue {
+			express = row.Count
+		} else {
+			regular = row.Count
+		}
+	}
+	return regular, express
+}
+
+// nextLanePosition returns the position at the back of a lane
+func (s *QueueService) nextLanePosition(ctx context.Context, locationID string, isExpress bool) int {
+	var currentMaxPosition int
+	s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("location_id = ? AND is_express_queue = ? AND status IN ?", locationID, isExpress, activeStatuses).
+		Select("COALESCE(MAX(position), 0)").
+		Scan(&currentMaxPosition)
+	return currentMaxPosition + 1
+}
+
+// otherLaneAhead counts the entries of the other lane served before the entry
+// at position in its lane, given how many entries the other lane holds. Each
+// turn serves the regular lane's share first.
+func otherLaneAhead(config *models.QueueConfiguration, isExpress bool, position, otherLength int) int {
+	if position < 1 {
+		return 0
+	}
+	regularShare, expressShare := serveRatios(config)
+
+	var ahead int
+	if isExpress {
+		turn := (position + expressShare - 1) / expressShare
+		ahead = turn * regularShare
+	} else {
+		turn := (position + regularShare - 1) / regularShare
+		ahead = (turn - 1) * expressShare
+	}
+	return min(ahead, otherLength)
+}
+
+// laneWaitTime estimates the wait of the entry at position in its lane,
+// counting the interleaved entries of the other lane
+func laneWaitTime(config *models.QueueConfiguration, isExpress bool, position, otherLength, prepTimePerItem int) int {
+	served := position + otherLaneAhead(config, isExpress, position, otherLength)
+	return utils.CalculateEstimatedWaitTime(served, prepTimePerItem, config.BufferTime)
+}
+
+// nextLaneToServe picks the lane whose turn it is from the entries most
+// recently started at the location: the lane keeps its turn until it has
+// served its share in a row.
+func (s *QueueService) nextLaneToServe(ctx context.Context, locationID string, config *models.QueueConfiguration) bool {
+	regularShare, expressShare := serveRatios(config)
+
+	var recent []bool
+	s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("location_id = ? AND actual_start_time IS NOT NULL", locationID).
+		Order("actual_start_time DESC").
+		Limit(max(regularShare, expressShare)).
+		Pluck("is_express_queue", &recent)
+	if len(recent) == 0 {
+		return false
+	}
+
+	lastExpress := recent[0]
+	run := 0
+	for _, isExpress := range recent {
+		if isExpress != lastExpress {
+			break
+		}
+		run++
+	}
+
+	share := regularShare
+	if lastExpress {
+		share = expressShare
+	}
+	if run < share {
+		return lastExpress
+	}
+	return !lastExpress
+}
+
+// otherLaneAheadOf counts the other lane's entries served before an active
+// entry. The other lane's length comes from its position index when Redis is
+// available.
+func (s *QueueService) otherLaneAheadOf(ctx context.Context, config *models.QueueConfiguration, entry *models.QueueEntry) int {
+	if !isActiveStatus(entry.Status) {
+		return 0
+	}
+
+	if rdb := database.GetRedis(); rdb != nil {
+		length, err := rdb.ZCard(ctx, positionIndexKey(entry.LocationID, !entry.IsExpressQueue)).Result()
+		if err == nil {
+			return otherLaneAhead(config, entry.IsExpressQueue, entry.Position, int(length))
+		}
+		log.Printf("Failed to read lane length for %s: %v", entry.ID, err)
+	}
+	otherLength := s.otherLaneLength(ctx, entry.LocationID, entry.IsExpressQueue)
+	return otherLaneAhead(config, entry.IsExpressQueue, entry.Position, otherLength)
+}
+
+// otherLaneLength counts the active entries of the lane opposite isExpress
+func (s *QueueService) otherLaneLength(ctx context.Context, locationID string, isExpress bool) int {
+	regular, express := s.laneLengths(ctx, locationID)
+	if isExpress {
+		return regular
+	}
+	return express
+}
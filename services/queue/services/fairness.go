@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gin-quickstart/models"
+)
+
+// priorityRank mirrors the SQL order of priorityOrder
+var priorityRank = map[string]int{
+	"LOW":    0,
+	"NORMAL": 1,
+	"HIGH":   2,
+	"URGENT": 3,
+	"VIP":    4,
+}
+
+// priorityOrder ranks priorities in SQL, highest last, so "priorityOrder
+// DESC" serves VIP first. priority is a string column, which sorts by name.
+const priorityOrder = "CASE priority WHEN 'VIP' THEN 4 WHEN 'URGENT' THEN 3 WHEN 'HIGH' THEN 2 WHEN 'NORMAL' THEN 1 ELSE 0 END"
+
+// fairnessRules are shown to customers alongside their explanation
+var fairnessRules = []string{
+	"Orders are served by priority first (VIP, Urgent, High, Normal, Low), then in order of arrival.",
+	"Small orders qualify for the express lane, which has its own line and is served in turn with the regular lane so small orders don't wait behind large ones.",
+	"Staff may raise an order's priority for special handling; every change is logged.",
+}
+
+const maxFairnessHistory = 10
+
+// ExplainPosition builds a human-readable explanation of an entry's queue position
+func (s *QueueService) ExplainPosition(ctx context.Context, token string) (*models.QueueFairnessExplanation, error) {
+	entry, err := s.GetQueueEntryByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &models.QueueFairnessExplanation{
+		TokenNumber:     entry.TokenNumber,
+		Status:          entry.Status,
+		Priority:        entry.Priority,
+		IsExpressQueue:  entry.IsExpressQueue,
+		Position:        entry.Position,
+		AheadByPriority: make(map[string]int),
+		Reasons:         []string{},
+		Rules:           fairnessRules,
+	}
+
+	history, err := s.positionChanges(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	explanation.History = history
+
+	if entry.Status != "WAITING" && entry.Status != "IN_PROGRESS" {
+		explanation.Summary = fmt.Sprintf("Your order is %s and is no longer waiting in line.", humanizeStatus(entry.Status))
+		return explanation, nil
+	}
+
+	config, err := s.GetConfiguration(WithLocation(ctx, entry.LocationID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries ahead in the entry's own lane
+	var ahead []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Select("priority", "created_at").
+		Where("location_id = ? AND is_express_queue = ? AND status IN ? AND position < ?", entry.LocationID, entry.IsExpressQueue, []string{"WAITING", "IN_PROGRESS"}, entry.Position).
+		Find(&ahead).Error; err != nil {
+		return nil, err
+	}
+
+	// Entries of the other lane served in between
+	interleaved := s.otherLaneAheadOf(ctx, config, entry)
+	if !entry.IsExpressQueue {
+		explanation.ExpressAhead = interleaved
+	}
+
+	var priorityOvertakes, staffOvertakes int
+	overtakesByPriority := make(map[string]int)
+	for _, other := range ahead {
+		explanation.AheadByPriority[other.Priority]++
+
+		// Only entries that arrived later need explaining
+		if !other.CreatedAt.After(entry.CreatedAt) {
+			continue
+		}
+		explanation.JoinedLaterAhead++
+
+		switch {
+		case priorityRank[other.Priority] > priorityRank[entry.Priority]:
+			priorityOvertakes++
+			overtakesByPriority[other.Priority]++
+		default:
+			staffOvertakes++
+		}
+	}
+	explanation.PeopleAhead = len(ahead) + interleaved
+
+	line := "line"
+	if entry.IsExpressQueue {
+		line = "the express lane"
+	}
+	if entry.Status == "IN_PROGRESS" {
+		explanation.Summary = "Your order is being prepared."
+	} else {
+		explanation.Summary = fmt.Sprintf("You are number %d in %s with %s ahead of you.",
+			entry.Position, line, pluralize(explanation.PeopleAhead, "order"))
+	}
+
+	if explanation.JoinedLaterAhead == 0 {
+		explanation.Reasons = append(explanation.Reasons, "Nobody who arrived after you has been served ahead of you.")
+	}
+	if priorityOvertakes > 0 {
+		explanation.Reasons = append(explanation.Reasons, fmt.Sprintf(
+			"%s that arrived after you %s ahead because of higher priority (%s).",
+			pluralize(priorityOvertakes, "order"), isAre(priorityOvertakes), describePriorities(overtakesByPriority)))
+	}
+	if interleaved > 0 {
+		otherLane := "the express lane for small orders"
+		if entry.IsExpressQueue {
+			otherLane = "the regular lane"
+		}
+		regularShare, expressShare := serveRatios(config)
+		explanation.Reasons = append(explanation.Reasons, fmt.Sprintf(
+			"%s in %s %s served in turn with yours (%d regular to %d express).",
+			pluralize(interleaved, "order"), otherLane, isAre(interleaved), regularShare, expressShare))
+	}
+	if staffOvertakes > 0 {
+		explanation.Reasons = append(explanation.Reasons, fmt.Sprintf(
+			"%s that arrived after you %s moved ahead by staff.",
+			pluralize(staffOvertakes, "order"), wasWere(staffOvertakes)))
+	}
+	if priorityRank[entry.Priority] > priorityRank["NORMAL"] {
+		explanation.Reasons = append(explanation.Reasons, fmt.Sprintf(
+			"Your order has %s priority, so it is served ahead of normal orders.", strings.ToLower(entry.Priority)))
+	}
+
+	return explanation, nil
+}
+
+func (s *QueueService) positionChanges(ctx context.Context, entryID string) ([]models.PositionChangeEntry, error) {
+	var rows []models.QueuePositionHistory
+	if err := s.db.WithContext(ctx).
+		Where("queue_entry_id = ?", entryID).
+		Order("timestamp DESC").
+		Limit(maxFairnessHistory).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	// Staff reasons are left out, as in the customer's position history
+	changes := make([]models.PositionChangeEntry, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, models.PositionChangeEntry{
+			Timestamp:   row.Timestamp,
+			OldPosition: row.OldPosition,
+			NewPosition: row.NewPosition,
+			Explanation: describePositionChange(row),
+		})
+	}
+	return changes, nil
+}
+
+func describePriorities(counts map[string]int) string {
+	var parts []string
+	for _, priority := range []string{"VIP", "URGENT", "HIGH", "NORMAL"} {
+		if counts[priority] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[priority], strings.ToLower(priority)))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func humanizeStatus(status string) string {
+	return strings.ToLower(strings.ReplaceAll(status, "_", " "))
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func isAre(n int) string {
+	if n == 1 {
+		return "is"
+	}
+	return "are"
+}
+
+func wasWere(n int) string {
+	if n == 1 {
+		return "was"
+	}
+	return "were"
+}
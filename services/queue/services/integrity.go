@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+// integritySampleSize caps how many offending row IDs a report lists per check
+const integritySampleSize = 20
+
+// Child tables whose rows must point at an existing queue entry
+var integrityChildTables = []string{
+	(models.QueuePositionHistory{}).TableName(),
+	(models.QueueNotificationSent{}).TableName(),
+	(models.StaffQueueActionLog{}).TableName(),
+	(models.QueueEntryStage{}).TableName(),
+	(models.QueueEntryOrder{}).TableName(),
+	(models.QueueEntryItem{}).TableName(),
+}
+
+var (
+	integrityMu         sync.RWMutex
+	lastIntegrityReport *models.IntegrityReport
+)
+
+// CheckIntegrity looks for rows that break position recalculation or reporting
+// and, when repair is set, fixes them. The report is kept for GetIntegrityReport.
+func (s *QueueService) CheckIntegrity(ctx context.Context, repair bool) (*models.IntegrityReport, error) {
+	started := s.clock.Now()
+	report := &models.IntegrityReport{
+		CheckedAt: started.UTC(),
+		Repair:    repair,
+		Issues:    []models.IntegrityIssue{},
+	}
+
+	checks := []func(context.Context, bool) (*models.IntegrityIssue, error){
+		s.checkDuplicatePositions,
+		s.checkReadyWithoutTimestamp,
+	}
+	for _, table := range integrityChildTables {
+		checks = append(checks, s.orphanCheck(table))
+	}
+
+	for _, check := range checks {
+		issue, err := check(ctx, repair)
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil {
+			report.Issues = append(report.Issues, *issue)
+		}
+	}
+
+	report.Consistent = true
+	for _, issue := range report.Issues {
+		if issue.Repaired < issue.Count {
+			report.Consistent = false
+		}
+	}
+	report.DurationMs = s.clock.Since(started).Milliseconds()
+
+	integrityMu.Lock()
+	lastIntegrityReport = report
+	integrityMu.Unlock()
+
+	return report, nil
+}
+
+// GetIntegrityReport returns the most recent integrity report, running a
+// read-only check if none has run yet
+func (s *QueueService) GetIntegrityReport(ctx context.Context) (*models.IntegrityReport, error) {
+	integrityMu.RLock()
+	report := lastIntegrityReport
+	integrityMu.RUnlock()
+
+	if report != nil {
+		return report, nil
+	}
+	return s.CheckIntegrity(ctx, false)
+}
+
+// RunStartupIntegrityCheck runs the integrity check once and logs what it found
+func (s *QueueService) RunStartupIntegrityCheck(ctx context.Context, repair bool) {
+	report, err := s.CheckIntegrity(ctx, repair)
+	if err != nil {
+		log.Printf("Integrity check failed: %v", err)
+		return
+	}
+
+	for _, issue := range report.Issues {
+		log.Printf("Integrity issue: check=%s, count=%d, repaired=%d", issue.Check, issue.Count, issue.Repaired)
+	}
+	log.Printf("Integrity check finished: consistent=%t, issues=%d, repair=%t", report.Consistent, len(report.Issues), repair)
+}
+
+// checkDuplicatePositions finds active entries sharing a position in their
+// location's lane (regular or express)
+func (s *QueueService) checkDuplicatePositions(ctx context.Context, repair bool) (*models.IntegrityIssue, error) {
+	active := []string{"WAITING", "IN_PROGRESS"}
+	duplicated := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Select("location_id, is_express_queue, position").
+		Where("status IN ?", active).
+		Group("location_id, is_express_queue, position").
+		Having("COUNT(*) > 1")
+
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Select("id", "location_id").
+		Where("status IN ? AND (location_id, is_express_queue, position) IN (?)", active, duplicated).
+		Order("location_id ASC, position ASC, created_at ASC").
+		Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to check duplicate positions: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(entries))
+	var locationIDs []string
+	for i, entry := range entries {
+		ids[i] = entry.ID
+		if i == 0 || entry.LocationID != entries[i-1].LocationID {
+			locationIDs = append(locationIDs, entry.LocationID)
+		}
+	}
+
+	issue := newIntegrityIssue("duplicate_active_positions", "Active entries share a queue position", ids)
+	if repair {
+		for _, locationID := range locationIDs {
+			if err := s.RecalculatePositions(WithLocation(ctx, locationID)); err != nil {
+				return nil, fmt.Errorf("failed to repair duplicate positions: %w", err)
+			}
+		}
+		issue.Repaired = len(ids)
+	}
+	return issue, nil
+}
+
+// checkReadyWithoutTimestamp finds READY entries missing actual_ready_time
+func (s *QueueService) checkReadyWithoutTimestamp(ctx context.Context, repair bool) (*models.IntegrityIssue, error) {
+	var ids []string
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("status = ? AND actual_ready_time IS NULL", "READY").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to check ready timestamps: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	issue := newIntegrityIssue("ready_without_timestamp", "READY entries have no actual_ready_time", ids)
+	if repair {
+		// The last update is the closest record of when the entry became ready
+		result := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+			Where("id IN ? AND actual_ready_time IS NULL", ids).
+			UpdateColumn("actual_ready_time", gorm.Expr("updated_at"))
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to repair ready timestamps: %w", result.Error)
+		}
+		issue.Repaired = int(result.RowsAffected)
+		for _, id := range ids {
+			utils.InvalidateQueueCache(ctx, id)
+		}
+		s.queueChanged(ctx, ids...)
+	}
+	return issue, nil
+}
+
+// orphanCheck builds a check for rows in table whose queue entry no longer exists
+func (s *QueueService) orphanCheck(table string) func(context.Context, bool) (*models.IntegrityIssue, error) {
+	return func(ctx context.Context, repair bool) (*models.IntegrityIssue, error) {
+		orphaned := fmt.Sprintf("NOT EXISTS (SELECT 1 FROM queue_entries e WHERE e.id = %s.queue_entry_id)", table)
+
+		var ids []string
+		if err := s.db.WithContext(ctx).Table(table).Where(orphaned).Pluck("id", &ids).Error; err != nil {
+			return nil, fmt.Errorf("failed to check orphaned %s rows: %w", table, err)
+		}
+		if len(ids) == 0 {
+			return nil, nil
+		}
+
+		issue := newIntegrityIssue("orphaned_"+table, fmt.Sprintf("Rows in %s reference a missing queue entry", table), ids)
+		if repair {
+			result := s.db.WithContext(ctx).Exec(fmt.Sprintf("DELETE FROM %s WHERE id IN ?", table), ids)
+			if result.Error != nil {
+				return nil, fmt.Errorf("failed to delete orphaned %s rows: %w", table, result.Error)
+			}
+			issue.Repaired = int(result.RowsAffected)
+		}
+		return issue, nil
+	}
+}
+
+func newIntegrityIssue(check, description string, ids []string) *models.IntegrityIssue {
+	sample := ids
+	if len(sample) > integritySampleSize {
+		sample = sample[:integritySampleSize]
+	}
+	return &models.IntegrityIssue{
+		Check:       check,
+		Description: description,
+		Count:       len(ids),
+		SampleIDs:   sample,
+	}
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrCannotLink is returned when orders can't join an entry's party token
+	ErrCannotLink = newError(KindConflict, "CANNOT_LINK", "cannot link orders")
+	// ErrPartyNotReady is returned when a party token is marked ready or
+	// completed before all its orders are ready
+	ErrPartyNotReady = newError(KindConflict, "PARTY_NOT_READY", "not all orders of this party token are ready")
+	// ErrInvalidOrderStatus is returned for a linked order status change
+	// that isn't allowed
+	ErrInvalidOrderStatus = newError(KindInvalid, "INVALID_ORDER_STATUS", "invalid order status")
+)
+
+// ownOrderStatuses maps an entry's status to the status its own order
+// takes when the entry becomes a party token
+var ownOrderStatuses = map[string]string{
+	"PENDING_PAYMENT": "WAITING",
+	"SCHEDULED":       "WAITING",
+	"WAITING":         "WAITING",
+	"IN_PROGRESS":     "IN_PROGRESS",
+}
+
+// LinkOrders adds orders to an entry's pickup token, so a party ordering
+// separately collects everything together. The entry becomes ready only once
+// all its orders are. Orders must be linked before they're queued on their
+// own; an order queued later joins the party token instead of getting one.
+func (s *QueueService) LinkOrders(ctx context.Context, entryID string, req *models.LinkOrdersRequest, staffID, staffName string) (*models.LinkedOrdersResponse, error) {
+	var entry models.QueueEntry
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", entryID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	ctx = WithLocation(ctx, entry.LocationID)
+
+	ownStatus, ok := ownOrderStatuses[entry.Status]
+	if !ok {
+		return nil, fmt.Errorf("%w: the entry is %s; orders can only join a token that isn't ready yet", ErrCannotLink, humanizeStatus(entry.Status))
+	}
+
+	var linked []models.QueueEntryOrder
+	if err := s.db.WithContext(ctx).Where("queue_entry_id = ?", entry.ID).Find(&linked).Error; err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(linked)+1)
+	known[entry.OrderID] = true
+	for _, order := range linked {
+		known[order.OrderID] = true
+	}
+
+	var orderIDs []string
+	for _, orderID := range req.OrderIDs {
+		if !known[orderID] {
+			known[orderID] = true
+			orderIDs = append(orderIDs, orderID)
+		}
+	}
+	if len(orderIDs) == 0 {
+		return s.buildLinkedOrdersResponse(ctx, &entry)
+	}
+
+	var queued []models.QueueEntry
+	if err := s.db.WithContext(ctx).Where("order_id IN ?", orderIDs).Find(&queued).Error; err != nil {
+		return nil, err
+	}
+	if len(queued) > 0 {
+		return nil, fmt.Errorf("%w: order %s already has its own token %s", ErrCannotLink, queued[0].OrderID, queued[0].TokenNumber)
+	}
+
+	now := s.clock.Now().UTC()
+	rows := make([]models.QueueEntryOrder, 0, len(orderIDs)+1)
+	if len(linked) == 0 {
+		// The entry's own order is tracked alongside the ones joining it
+		rows = append(rows, models.QueueEntryOrder{
+			ID:              utils.GenerateUUID(),
+			QueueEntryID:    entry.ID,
+			OrderID:         entry.OrderID,
+			TokenNumber:     entry.TokenNumber,
+			Status:          ownStatus,
+			ActualStartTime: entry.ActualStartTime,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		})
+	}
+	for _, orderID := range orderIDs {
+		rows = append(rows, models.QueueEntryOrder{
+			ID:           utils.GenerateUUID(),
+			QueueEntryID: entry.ID,
+			OrderID:      orderID,
+			TokenNumber:  entry.TokenNumber,
+			Status:       "WAITING",
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+	}
+
+	if err := s.db.WithContext(ctx).Create(&rows).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, fmt.Errorf("%w: an order is already part of another party token", ErrCannotLink)
+		}
+		return nil, err
+	}
+
+	note := "Linked orders " + strings.Join(orderIDs, ", ")
+	s.LogStaffAction(ctx, entry.ID, staffID, staffName, "LINK_ORDERS", nil, nil, nil, nil, &note)
+	utils.InvalidateQueueCache(ctx, entry.ID)
+
+	return s.buildLinkedOrdersResponse(ctx, &entry)
+}
+
+// GetLinkedOrders gets the progress of each order of a party token
+func (s *QueueService) GetLinkedOrders(ctx context.Context, entryID string) (*models.LinkedOrdersResponse, error) {
+	entry, err := s.GetQueueEntryByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildLinkedOrdersResponse(ctx, entry)
+}
+
+// GetLinkedOrdersByToken gets the progress of each order of a party token by token number
+func (s *QueueService) GetLinkedOrdersByToken(ctx context.Context, token string) (*models.LinkedOrdersResponse, error) {
+	entry, err := s.GetQueueEntryByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildLinkedOrdersResponse(ctx, entry)
+}
+
+// GetOrderLink returns the party token row of an order, or
+// gorm.ErrRecordNotFound when the order isn't part of one
+func (s *QueueService) GetOrderLink(ctx context.Context, orderID string) (*models.QueueEntryOrder, error) {
+	var order models.QueueEntryOrder
+	if err := s.db.WithContext(ctx).Where("order_id = ?", orderID).First(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (s *QueueService) buildLinkedOrdersResponse(ctx context.Context, entry *models.QueueEntry) (*models.LinkedOrdersResponse, error) {
+	orders := []models.QueueEntryOrder{}
+	if err := s.db.WithContext(ctx).Where("queue_entry_id = ?", entry.ID).
+		Order("created_at ASC").
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	ready, remaining := countProgress(orderStatuses(orders))
+	return &models.LinkedOrdersResponse{
+		QueueEntryID:    entry.ID,
+		TokenNumber:     entry.TokenNumber,
+		Status:          entry.Status,
+		Orders:          orders,
+		ReadyOrders:     ready,
+		RemainingOrders: remaining,
+	}, nil
+}
+
+// UpdateLinkedOrderStatus updates one order of a party token and rolls the
+// result up to the entry: it starts with its first order, becomes ready with
+// its last one, and is cancelled when all its orders are
+func (s *QueueService) UpdateLinkedOrderStatus(ctx context.Context, entryID, orderID string, req *models.UpdateLinkedOrderStatusRequest, staffID, staffName string) (*models.QueueEntryOrder, error) {
+	var entry models.QueueEntry
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", entryID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	ctx = WithLocation(ctx, entry.LocationID)
+
+	var order models.QueueEntryOrder
+	if err := s.db.WithContext(ctx).Where("queue_entry_id = ? AND order_id = ?", entry.ID, orderID).First(&order).Error; err != nil {
+		return nil, err
+	}
+	if order.Status == req.Status {
+		return &order, nil
+	}
+
+	if order.Status == "COMPLETED" || order.Status == "CANCELLED" {
+		return nil, fmt.Errorf("%w: cannot move order from %s to %s", ErrInvalidOrderStatus, order.Status, req.Status)
+	}
+	if req.Status != "CANCELLED" {
+		newOrder, ok := stageStatusOrder[req.Status]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidOrderStatus, req.Status)
+		}
+		if newOrder < stageStatusOrder[order.Status] {
+			return nil, fmt.Errorf("%w: cannot move order from %s to %s", ErrInvalidOrderStatus, order.Status, req.Status)
+		}
+	}
+
+	now := s.clock.Now().UTC()
+	updates := map[string]interface{}{
+		"status":     req.Status,
+		"updated_at": now,
+	}
+	switch req.Status {
+	case "IN_PROGRESS":
+		if order.ActualStartTime == nil {
+			updates["actual_start_time"] = now
+		}
+	case "READY":
+		if order.ActualReadyTime == nil {
+			updates["actual_ready_time"] = now
+		}
+	case "COMPLETED":
+		if order.ActualCompletionTime == nil {
+			updates["actual_completion_time"] = now
+		}
+	}
+
+	if err := s.db.WithContext(ctx).Model(&order).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	order.Status = req.Status
+
+	var orders []models.QueueEntryOrder
+	if err := s.db.WithContext(ctx).Where("queue_entry_id = ?", entry.ID).Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	statuses := orderStatuses(orders)
+
+	reason := req.Reason
+	if reason == nil {
+		reason = utils.StringPtr(fmt.Sprintf("Order %s %s", order.OrderID, humanizeStatus(req.Status)))
+	}
+
+	// Every order cancelled leaves nothing to pick up
+	if allCancelled(statuses) && !IsFinalStatus(entry.Status) {
+		if _, _, err := s.cancelEntry(ctx, &entry, reason); err != nil {
+			return nil, err
+		}
+	} else if parentStatus := rollupProgress(entry.Status, statuses); parentStatus != "" {
+		parentReq := &models.UpdateQueueStatusRequest{
+			Status:      parentStatus,
+			Reason:      reason,
+			DeferIfFull: true,
+		}
+		if err := s.UpdateQueueStatus(ctx, entry.ID, parentReq, staffID, staffName); err != nil {
+			return nil, err
+		}
+	}
+
+	utils.InvalidateQueueCache(ctx, entry.ID)
+
+	return &order, nil
+}
+
+// cancelLinkedOrder cancels one order of a party token; the token is
+// cancelled with its last order. It reports false when the order was
+// already completed or cancelled.
+func (s *QueueService) cancelLinkedOrder(ctx context.Context, link *models.QueueEntryOrder, reason *string) (*models.QueueEntry, bool, error) {
+	if link.Status == "COMPLETED" || link.Status == "CANCELLED" {
+		entry, err := s.GetQueueEntryByID(ctx, link.QueueEntryID)
+		return entry, false, err
+	}
+
+	req := &models.UpdateLinkedOrderStatusRequest{
+		Status: "CANCELLED",
+		Reason: reason,
+	}
+	if _, err := s.UpdateLinkedOrderStatus(ctx, link.QueueEntryID, link.OrderID, req, "system", "System"); err != nil {
+		return nil, false, err
+	}
+
+	entry, err := s.GetQueueEntryByID(ctx, link.QueueEntryID)
+	if err != nil {
+		return nil, false, err
+	}
+	return entry, true, nil
+}
+
+// checkPartyReady returns ErrPartyNotReady while an order of the entry's
+// party token isn't ready
+func (s *QueueService) checkPartyReady(ctx context.Context, entryID string) error {
+	var pending int64
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntryOrder{}).
+		Where("queue_entry_id = ? AND status IN ?", entryID, []string{"WAITING", "IN_PROGRESS"}).
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending > 0 {
+		return fmt.Errorf("%w: %d still being prepared", ErrPartyNotReady, pending)
+	}
+	return nil
+}
+
+// closeLinkedOrders completes the ready orders of a party token once it is
+// picked up, and cancels its open orders once it is cancelled
+func (s *QueueService) closeLinkedOrders(ctx context.Context, entryID, status string) {
+	now := s.clock.Now().UTC()
+	var result *gorm.DB
+	switch status {
+	case "COMPLETED":
+		result = s.db.WithContext(ctx).Model(&models.QueueEntryOrder{}).
+			Where("queue_entry_id = ? AND status = ?", entryID, "READY").
+			Updates(map[string]interface{}{
+				"status":                 "COMPLETED",
+				"actual_completion_time": now,
+				"updated_at":             now,
+			})
+	case "CANCELLED":
+		result = s.db.WithContext(ctx).Model(&models.QueueEntryOrder{}).
+			Where("queue_entry_id = ? AND status IN ?", entryID, []string{"WAITING", "IN_PROGRESS", "READY"}).
+			Updates(map[string]interface{}{
+				"status":     "CANCELLED",
+				"updated_at": now,
+			})
+	default:
+		return
+	}
+	if result.Error != nil {
+		log.Printf("Failed to close linked orders of %s: %v", entryID, result.Error)
+	}
+}
+
+// orderStatuses lists the status of each linked order
+func orderStatuses(orders []models.QueueEntryOrder) []string {
+	statuses := make([]string, len(orders))
+	for i, order := range orders {
+		statuses[i] = order.Status
+	}
+	return statuses
+}
+
+// allCancelled reports whether there are statuses and all are CANCELLED
+func allCancelled(statuses []string) bool {
+	for _, status := range statuses {
+		if status != "CANCELLED" {
+			return false
+		}
+	}
+	return len(statuses) > 0
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxKitchenLoad is the largest load percentage the statistics columns hold
+const maxKitchenLoad = 999.99
+
+// SampleLoad records the queue length and kitchen load of every active
+// location and raises the day's and hour's peaks they exceed. Samples are
+// bucketed to interval, so instances sampling in the same bucket record one.
+func (s *QueueService) SampleLoad(ctx context.Context, interval time.Duration) error {
+	locationIDs, err := s.activeLocationIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	sampledAt := s.clock.Now().UTC().Truncate(interval)
+	var errs []error
+	for _, locationID := range locationIDs {
+		if err := s.sampleLocationLoad(WithLocation(ctx, locationID), sampledAt); err != nil {
+			errs = append(errs, fmt.Errorf("location %s: %w", locationID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *QueueService) sampleLocationLoad(ctx context.Context, sampledAt time.Time) error {
+	locationID := LocationFromContext(ctx)
+
+	var counts []struct {
+		Status string
+		Count  int
+	}
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Select("status, COUNT(*) AS count").
+		Where("location_id = ? AND status IN ?", locationID, activeStatuses).
+		Group("status").
+		Scan(&counts).Error; err != nil {
+		return err
+	}
+
+	sample := models.QueueLoadSample{
+		ID:                  utils.GenerateUUID(),
+		LocationID:          locationID,
+		SampledAt:           sampledAt,
+		MaxConcurrentOrders: s.maxConcurrentOrders(ctx),
+	}
+	for _, count := range counts {
+		switch count.Status {
+		case "WAITING":
+			sample.WaitingCount = count.Count
+		case "IN_PROGRESS":
+			sample.InProgressCount = count.Count
+		}
+	}
+	if sample.MaxConcurrentOrders > 0 {
+		sample.KitchenLoad = min(roundRate(float64(sample.InProgressCount)/float64(sample.MaxConcurrentOrders)*100), maxKitchenLoad)
+	}
+
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&sample)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Another instance sampled this bucket
+		return nil
+	}
+
+	return s.raiseLoadPeaks(ctx, &sample)
+}
+
+// raiseLoadPeaks raises the day's peak load and queue length, and the hour's
+// longest queue, to the sample's where it exceeds them. The times are set
+// before the peaks so they compare against the previous peak. Columns are
+// qualified with the table, as PostgreSQL's ON CONFLICT requires.
+func (s *QueueService) raiseLoadPeaks(ctx context.Context, sample *models.QueueLoadSample) error {
+	// The day, hour and clock time of the sample in the restaurant's time zone
+	date := clock.Date(sample.SampledAt)
+	localTime := clock.Local(sample.SampledAt)
+	clockTime := localTime.Format("15:04")
+	now := s.clock.Now().UTC()
+
+	daily := models.QueueStatistics{
+		ID:                  utils.GenerateUUID(),
+		LocationID:          sample.LocationID,
+		Date:                date,
+		PeakLoad:            sample.KitchenLoad,
+		PeakLoadTime:        &clockTime,
+		PeakQueueLength:     sample.WaitingCount,
+		PeakQueueLengthTime: &clockTime,
+		UpdatedAt:           now,
+	}
+	if err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "location_id"}, {Name: "date"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "peak_load_time"}, Value: gorm.Expr("CASE WHEN ? > queue_statistics.peak_load THEN ? ELSE queue_statistics.peak_load_time END", sample.KitchenLoad, clockTime)},
+			{Column: clause.Column{Name: "peak_load"}, Value: gorm.Expr("GREATEST(queue_statistics.peak_load, ?)", sample.KitchenLoad)},
+			{Column: clause.Column{Name: "peak_queue_length_time"}, Value: gorm.Expr("CASE WHEN ? > queue_statistics.peak_queue_length THEN ? ELSE queue_statistics.peak_queue_length_time END", sample.WaitingCount, clockTime)},
+			{Column: clause.Column{Name: "peak_queue_length"}, Value: gorm.Expr("GREATEST(queue_statistics.peak_queue_length, ?)", sample.WaitingCount)},
+		},
+	}).Create(&daily).Error; err != nil {
+		return err
+	}
+
+	hourly := models.QueueHourlyStatistics{
+		ID:           utils.GenerateUUID(),
+		LocationID:   sample.LocationID,
+		Date:         date,
+		Hour:         localTime.Hour(),
+		PeakPosition: sample.WaitingCount,
+		UpdatedAt:    now,
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "location_id"}, {Name: "date"}, {Name: "hour"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "peak_position"}, Value: gorm.Expr("GREATEST(queue_hourly_statistics.peak_position, ?)", sample.WaitingCount)},
+		},
+	}).Create(&hourly).Error
+}
+
+// GetLoadCurve gets the request location's load samples of a day (today
+// when date is nil) and the day's peaks
+func (s *QueueService) GetLoadCurve(ctx context.Context, date *time.Time) (*models.LoadCurveResponse, error) {
+	locationID := LocationFromContext(ctx)
+	targetDate := clock.Date(s.clock.Now())
+	if date != nil {
+		targetDate = date.Truncate(24 * time.Hour)
+	}
+	dayStart, dayEnd := clock.DayBounds(targetDate)
+
+	curve := &models.LoadCurveResponse{
+		LocationID: locationID,
+		Date:       targetDate.Format("2006-01-02"),
+		Samples:    []models.QueueLoadSample{},
+	}
+	if err := s.db.WithContext(ctx).
+		Where("location_id = ? AND sampled_at >= ? AND sampled_at < ?", locationID, dayStart, dayEnd).
+		Order("sampled_at ASC").
+		Find(&curve.Samples).Error; err != nil {
+		return nil, err
+	}
+
+	var stats models.QueueStatistics
+	err := s.db.WithContext(ctx).
+		Select("peak_load", "peak_load_time", "peak_queue_length", "peak_queue_length_time").
+		Where("location_id = ? AND date = ?", locationID, targetDate).
+		First(&stats).Error
+	switch {
+	case err == nil:
+		curve.PeakLoad = stats.PeakLoad
+		curve.PeakLoadTime = stats.PeakLoadTime
+		curve.PeakQueueLength = stats.PeakQueueLength
+		curve.PeakQueueLengthTime = stats.PeakQueueLengthTime
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+	return curve, nil
+}
+
+// PurgeLoadSamples deletes load samples older than the retention window
+func (s *QueueService) PurgeLoadSamples(ctx context.Context, retention time.Duration) (int64, error) {
+	cutoff := s.clock.Now().UTC().Add(-retention)
+	result := s.db.WithContext(ctx).Where("sampled_at < ?", cutoff).Delete(&models.QueueLoadSample{})
+	return result.RowsAffected, result.Error
+}
+
+// StartLoadSampler samples load every interval and purges samples older
+// than retention until ctx is cancelled
+func (s *QueueService) StartLoadSampler(ctx context.Context, interval, retention time.Duration) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			if err := s.SampleLoad(ctx, interval); err != nil {
+				log.Printf("Failed to sample queue load: %v", err)
+			}
+			if _, err := s.PurgeLoadSamples(ctx, retention); err != nil {
+				log.Printf("Failed to purge load samples: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"gin-quickstart/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnknownLocation is returned when a location doesn't exist or is inactive
+	ErrUnknownLocation = newError(KindInvalid, "UNKNOWN_LOCATION", "unknown location")
+	// ErrInvalidLocationID is returned for location IDs that can't be used in headers and keys
+	ErrInvalidLocationID = newError(KindInvalid, "INVALID_LOCATION_ID", "location IDs may only contain letters, digits, '-' and '_'")
+	// ErrLocationMismatch is returned when a request scoped to one location names another
+	ErrLocationMismatch = newError(KindInvalid, "LOCATION_MISMATCH", "location doesn't match the request's location")
+	// ErrLocationExists is returned when a location ID or token prefix is already taken
+	ErrLocationExists = newError(KindConflict, "LOCATION_EXISTS", "a location with this ID or token prefix already exists")
+	// ErrLocationInUse is returned when deactivating a location that still has active entries
+	ErrLocationInUse = newError(KindConflict, "LOCATION_IN_USE", "location still has active entries")
+	// ErrDefaultLocation is returned when deactivating the default location
+	ErrDefaultLocation = newError(KindConflict, "DEFAULT_LOCATION", "the default location can't be deactivated")
+)
+
+// locationIDPattern is what location IDs may look like in headers, paths and Redis keys
+var locationIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,36}$`)
+
+// ValidLocationID reports whether id is a well-formed location ID
+func ValidLocationID(id string) bool {
+	return locationIDPattern.MatchString(id)
+}
+
+type locationKey struct{}
+
+// WithLocation returns a context scoped to a location
+func WithLocation(ctx context.Context, locationID string) context.Context {
+	if locationID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, locationKey{}, locationID)
+}
+
+// LocationFromContext returns the location ctx is scoped to, or the default location
+func LocationFromContext(ctx context.Context) string {
+	if locationID, ok := scopedLocation(ctx); ok {
+		return locationID
+	}
+	return models.DefaultLocationID
+}
+
+// scopedLocation returns the location ctx is scoped to, if any
+func scopedLocation(ctx context.Context) (string, bool) {
+	locationID, ok := ctx.Value(locationKey{}).(string)
+	return locationID, ok
+}
+
+// inLocation restricts a lookup by ID to the location ctx is scoped to.
+// Requests that didn't name a location, and internal callers (consumers,
+// gRPC), reach records of every location.
+func inLocation(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if locationID, ok := scopedLocation(ctx); ok {
+		return db.Where("location_id = ?", locationID)
+	}
+	return db
+}
+
+// ListLocations lists all locations, active or not
+func (s *QueueService) ListLocations(ctx context.Context) ([]models.QueueLocation, error) {
+	var locations []models.QueueLocation
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// CreateLocation adds an active location. Until it is given its own
+// configuration it runs with the default location's.
+func (s *QueueService) CreateLocation(ctx context.Context, req *models.CreateLocationRequest) (*models.QueueLocation, error) {
+	if !ValidLocationID(req.ID) {
+		return nil, ErrInvalidLocationID
+	}
+
+	now := s.clock.Now().UTC()
+	location := &models.QueueLocation{
+		ID:          req.ID,
+		Name:        req.Name,
+		TokenPrefix: strings.ToUpper(req.TokenPrefix),
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.db.WithContext(ctx).Create(location).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrLocationExists
+		}
+		return nil, err
+	}
+	return location, nil
+}
+
+// UpdateLocation renames a location, changes its token prefix or (de)activates it
+func (s *QueueService) UpdateLocation(ctx context.Context, locationID string, req *models.UpdateLocationRequest) (*models.QueueLocation, error) {
+	var location models.QueueLocation
+	if err := s.db.WithContext(ctx).Where("id = ?", locationID).First(&location).Error; err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"updated_at": s.clock.Now().UTC(),
+	}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+		location.Name = *req.Name
+	}
+	if req.TokenPrefix != nil {
+		prefix := strings.ToUpper(*req.TokenPrefix)
+		updates["token_prefix"] = prefix
+		location.TokenPrefix = prefix
+	}
+	if req.IsActive != nil {
+		if !*req.IsActive {
+			if err := s.checkDeactivation(ctx, locationID); err != nil {
+				return nil, err
+			}
+		}
+		updates["is_active"] = *req.IsActive
+		location.IsActive = *req.IsActive
+	}
+
+	if err := s.db.WithContext(ctx).Model(&location).Updates(updates).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrLocationExists
+		}
+		return nil, err
+	}
+	return &location, nil
+}
+
+// DeactivateLocation stops a location from taking new entries. Its history
+// (entries, statistics) is kept.
+func (s *QueueService) DeactivateLocation(ctx context.Context, locationID string) error {
+	var location models.QueueLocation
+	if err := s.db.WithContext(ctx).Where("id = ?", locationID).First(&location).Error; err != nil {
+		return err
+	}
+	if err := s.checkDeactivation(ctx, locationID); err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Model(&location).Updates(map[string]interface{}{
+		"is_active":  false,
+		"updated_at": s.clock.Now().UTC(),
+	}).Error
+}
+
+// checkDeactivation refuses to deactivate the default location, or one whose
+// queue isn't empty
+func (s *QueueService) checkDeactivation(ctx context.Context, locationID string) error {
+	if locationID == models.DefaultLocationID {
+		return ErrDefaultLocation
+	}
+
+	var active int64
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("location_id = ? AND status IN ?", locationID, []string{"PENDING_PAYMENT", "SCHEDULED", "WAITING", "IN_PROGRESS", "READY"}).
+		Count(&active).Error; err != nil {
+		return err
+	}
+	if active > 0 {
+		return ErrLocationInUse
+	}
+	return nil
+}
+
+// activeLocation loads a location that can take new entries
+func (s *QueueService) activeLocation(ctx context.Context, locationID string) (*models.QueueLocation, error) {
+	var location models.QueueLocation
+	if err := s.db.WithContext(ctx).Where("id = ? AND is_active = ?", locationID, true).First(&location).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnknownLocation
+		}
+		return nil, err
+	}
+	return &location, nil
+}
+
+// activeLocationIDs lists the locations background jobs work through
+func (s *QueueService) activeLocationIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	if err := s.db.WithContext(ctx).Model(&models.QueueLocation{}).
+		Where("is_active = ?", true).
+		Order("id ASC").
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
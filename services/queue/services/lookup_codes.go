@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"strings"
+
+	"gin-quickstart/models"
+)
+
+// ErrLookupCodeRequired is returned for a public lookup by bare token, or by
+// a code that doesn't match its token, when lookup codes are required
+var ErrLookupCodeRequired = newError(KindInvalid, "LOOKUP_CODE_REQUIRED", "a valid lookup code is required")
+
+// lookupSignatureLength is the length of the signature part of a lookup code
+// (16 base32 characters, 80 bits)
+const lookupSignatureLength = 16
+
+// lookupCodes signs tokens so that only their holders can look them up;
+// without a secret, tokens aren't signed
+var lookupCodes struct {
+	secret   []byte
+	required bool
+}
+
+// SetLookupCodeSecret enables signed lookup codes. When required, public
+// lookups by bare token are refused.
+func SetLookupCodeSecret(secret string, required bool) {
+	lookupCodes.secret = []byte(secret)
+	lookupCodes.required = required && secret != ""
+}
+
+// LookupCode returns the code a customer looks the entry up with (its token
+// followed by a signature, e.g. A001-3QX7...), or "" when lookup codes are
+// disabled
+func LookupCode(entry *models.QueueEntry) string {
+	if len(lookupCodes.secret) == 0 {
+		return ""
+	}
+	return entry.TokenNumber + "-" + lookupSignature(entry.TokenNumber, entry.ID)
+}
+
+// lookupSignature signs a token together with the entry holding it, so the
+// code stops working once the token number is reissued to another entry
+func lookupSignature(token, entryID string) string {
+	mac := hmac.New(sha256.New, lookupCodes.secret)
+	mac.Write([]byte(token + ":" + entryID))
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(mac.Sum(nil))[:lookupSignatureLength]
+}
+
+// ResolveLookupCode returns the token a public lookup is for, given the
+// lookup code of the entry now holding it or, unless codes are required,
+// the bare token
+func (s *QueueService) ResolveLookupCode(ctx context.Context, value string) (string, error) {
+	if len(lookupCodes.secret) == 0 {
+		return value, nil
+	}
+
+	// Token prefixes may contain dashes; the signature follows the last one
+	if i := strings.LastIndex(value, "-"); i > 0 {
+		token, signature := value[:i], strings.ToUpper(value[i+1:])
+		if entry, err := s.GetQueueEntryByToken(ctx, token); err == nil &&
+			hmac.Equal([]byte(signature), []byte(lookupSignature(token, entry.ID))) {
+			return token, nil
+		}
+	}
+	if lookupCodes.required {
+		return "", ErrLookupCodeRequired
+	}
+	return value, nil
+}
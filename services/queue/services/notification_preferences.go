@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gin-quickstart/i18n"
+	"gin-quickstart/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoEmailAddress is returned when email is turned on without an address
+var ErrNoEmailAddress = newError(KindInvalid, "EMAIL_REQUIRED", "an email address is required to enable email notifications")
+
+// ErrUnsupportedLanguage is returned for a notification language with no message catalog
+var ErrUnsupportedLanguage = newError(KindInvalid, "UNSUPPORTED_LANGUAGE", "unsupported language")
+
+// defaultNotificationPreference applies to customers who never set preferences
+func defaultNotificationPreference(userID string) *models.NotificationPreference {
+	return &models.NotificationPreference{
+		UserID:     userID,
+		SMSEnabled: true,
+	}
+}
+
+// GetNotificationPreference returns the customer's notification channels,
+// falling back to the defaults when none are stored
+func (s *QueueService) GetNotificationPreference(ctx context.Context, userID string) (*models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+	err := s.db.WithContext(ctx).Where("user_id = ?", userID).First(&pref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return defaultNotificationPreference(userID), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pref, nil
+}
+
+// UpdateNotificationPreference changes the fields set in req. fallbackEmail
+// (the address on the customer's account) is stored when email is turned on
+// without one, fallbackLanguage (the language of the request) when no
+// notification language is set.
+func (s *QueueService) UpdateNotificationPreference(ctx context.Context, userID string, req *models.UpdateNotificationPreferenceRequest, fallbackEmail, fallbackLanguage string) (*models.NotificationPreference, error) {
+	pref, err := s.GetNotificationPreference(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Email != nil {
+		pref.Email = req.Email
+	}
+	if req.SMSEnabled != nil {
+		pref.SMSEnabled = *req.SMSEnabled
+	}
+	if req.EmailEnabled != nil {
+		pref.EmailEnabled = *req.EmailEnabled
+	}
+	if req.Language != nil {
+		pref.Language = nil
+		if *req.Language != "" {
+			language, ok := i18n.Match(*req.Language)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s (supported: %s)", ErrUnsupportedLanguage, *req.Language, strings.Join(i18n.Languages(), ", "))
+			}
+			pref.Language = &language
+		}
+	} else if pref.Language == nil && fallbackLanguage != "" {
+		pref.Language = &fallbackLanguage
+	}
+	if pref.EmailEnabled && (pref.Email == nil || *pref.Email == "") {
+		if fallbackEmail == "" {
+			return nil, ErrNoEmailAddress
+		}
+		pref.Email = &fallbackEmail
+	}
+	pref.UpdatedAt = s.clock.Now().UTC()
+
+	// Select all columns so false values are written on insert too
+	err = s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Select("*").
+		Create(pref).Error
+	if err != nil {
+		return nil, err
+	}
+	return pref, nil
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"gin-quickstart/models"
+	"gin-quickstart/notify"
+	"gin-quickstart/utils"
+)
+
+// Notifier delivers notifications straight to customers (SMS, email, push),
+// skipping channels in skip
+type Notifier interface {
+	Notify(ctx context.Context, notificationType string, entry *models.QueueEntry, to notify.Recipient, skip map[string]bool) []notify.Delivery
+}
+
+var notifier Notifier
+
+// SetNotifier sets the notifier used for customer notifications
+func SetNotifier(n Notifier) {
+	notifier = n
+}
+
+// customerNotifications maps an entry status to the notification it triggers
+var customerNotifications = map[string]string{
+	"WAITING":     notify.TypeConfirmed,
+	"IN_PROGRESS": notify.TypeAlmostReady,
+	"READY":       notify.TypeReady,
+}
+
+// notifyCustomer sends the notification for the entry's new status in the
+// background over the channels the customer has enabled. Each channel is used
+// at most once per entry and notification type.
+func (s *QueueService) notifyCustomer(ctx context.Context, entryID, status string) {
+	notificationType, ok := customerNotifications[status]
+	if !ok {
+		return
+	}
+	s.sendCustomerNotification(ctx, entryID, notificationType, true)
+}
+
+// sendCustomerNotification sends a notification in the background over the
+// channels the customer has enabled, skipping channels it already went out
+// on when once is set
+func (s *QueueService) sendCustomerNotification(ctx context.Context, entryID, notificationType string, once bool) {
+	if notifier == nil {
+		return
+	}
+
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+
+		entry, err := s.GetQueueEntryByID(ctx, entryID)
+		if err != nil {
+			log.Printf("Failed to load entry %s for notification: %v", entryID, err)
+			return
+		}
+
+		var sentChannels []string
+		if once {
+			s.db.WithContext(ctx).Model(&models.QueueNotificationSent{}).
+				Where("queue_entry_id = ? AND notification_type = ?", entryID, notificationType).
+				Pluck("channel", &sentChannels)
+		}
+		skip := make(map[string]bool, len(sentChannels)+2)
+		for _, channel := range sentChannels {
+			skip[channel] = true
+		}
+
+		pref, err := s.GetNotificationPreference(ctx, entry.UserID)
+		if err != nil {
+			log.Printf("Failed to load notification preferences for %s: %v", entry.UserID, err)
+			pref = defaultNotificationPreference(entry.UserID)
+		}
+		if !pref.SMSEnabled {
+			skip["SMS"] = true
+		}
+		if !pref.EmailEnabled {
+			skip["EMAIL"] = true
+		}
+
+		to := notify.Recipient{}
+		if entry.UserName != nil {
+			to.Name = *entry.UserName
+		}
+		if entry.UserPhone != nil {
+			to.Phone = *entry.UserPhone
+		}
+		if pref.Email != nil {
+			to.Email = *pref.Email
+		}
+		if pref.Language != nil {
+			to.Language = *pref.Language
+		}
+		if !skip["PUSH"] {
+			to.DeviceTokens = s.deviceTokensFor(ctx, entry.UserID)
+		}
+
+		for _, delivery := range notifier.Notify(ctx, notificationType, entry, to, skip) {
+			s.recordDelivery(ctx, entry.ID, notificationType, delivery)
+			s.forgetDeviceTokens(ctx, delivery.InvalidDeviceTokens)
+		}
+	}()
+}
+
+func (s *QueueService) recordDelivery(ctx context.Context, entryID, notificationType string, delivery notify.Delivery) {
+	status := delivery.Status
+	var deliveryError *string
+	if delivery.Err != nil {
+		status = "failed"
+		deliveryError = utils.StringPtr(truncate(delivery.Err.Error(), 255))
+	}
+
+	record := &models.QueueNotificationSent{
+		ID:               utils.GenerateUUID(),
+		QueueEntryID:     entryID,
+		NotificationType: notificationType,
+		Channel:          delivery.Channel,
+		SentAt:           s.clock.Now().UTC(),
+		DeliveryStatus:   utils.StringPtr(status),
+		DeliveryError:    deliveryError,
+	}
+	if delivery.ProviderMessageID != "" {
+		record.ProviderMessageID = utils.StringPtr(delivery.ProviderMessageID)
+	}
+
+	if err := s.db.WithContext(ctx).Create(record).Error; err != nil {
+		log.Printf("Failed to record %s notification for %s: %v", delivery.Channel, entryID, err)
+	}
+}
+
+// RecordDeliveryStatus applies a provider delivery status callback. It reports
+// false when no notification was sent with that provider message ID.
+func (s *QueueService) RecordDeliveryStatus(ctx context.Context, providerMessageID, status string, deliveryError *string) (bool, error) {
+	updates := map[string]interface{}{
+		"delivery_status":   status,
+		"status_updated_at": s.clock.Now().UTC(),
+	}
+	if deliveryError != nil {
+		updates["delivery_error"] = truncate(*deliveryError, 255)
+	}
+
+	result := s.db.WithContext(ctx).Model(&models.QueueNotificationSent{}).
+		Where("provider_message_id = ?", providerMessageID).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
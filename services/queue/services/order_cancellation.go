@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"gin-quickstart/models"
+)
+
+// IsFinalStatus reports whether an entry in status has left the queue
+func IsFinalStatus(status string) bool {
+	return status == "COMPLETED" || status == "CANCELLED" || status == "NO_SHOW"
+}
+
+// isTerminalStatus reports whether an entry has left the queue for good,
+// finished or expired at rollover
+func isTerminalStatus(status string) bool {
+	return IsFinalStatus(status) || status == "EXPIRED"
+}
+
+// CancelOrderEntry cancels the queue entry of a cancelled order, frees its
+// position and notifies the customer. It reports false when the entry had
+// already left the queue.
+func (s *QueueService) CancelOrderEntry(ctx context.Context, orderID string, reason *string) (*models.QueueEntry, bool, error) {
+	// An order sharing a party token only drops out of it
+	if link, err := s.GetOrderLink(ctx, orderID); err == nil {
+		return s.cancelLinkedOrder(ctx, link, reason)
+	}
+
+	entry, err := s.GetQueueEntryByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, false, err
+	}
+	if IsFinalStatus(entry.Status) {
+		return entry, false, nil
+	}
+	return s.cancelEntry(ctx, entry, reason)
+}
+
+// cancelEntry cancels an entry that is still in the queue and notifies the customer
+func (s *QueueService) cancelEntry(ctx context.Context, entry *models.QueueEntry, reason *string) (*models.QueueEntry, bool, error) {
+	req := &models.UpdateQueueStatusRequest{
+		Status: "CANCELLED",
+		Reason: reason,
+	}
+	if err := s.UpdateQueueStatus(ctx, entry.ID, req, "system", "System"); err != nil {
+		return nil, false, err
+	}
+	entry.Status = "CANCELLED"
+
+	if s.publisher != nil {
+		if err := s.publisher.PublishQueueCancelled(ctx, entry, reason); err != nil {
+			log.Printf("Failed to publish cancellation for %s: %v", entry.TokenNumber, err)
+		}
+	}
+
+	return entry, true, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+)
+
+// ActivatePaidEntry moves the PENDING_PAYMENT entry of a paid order to the
+// back of the active queue. It reports false when the entry was not waiting
+// for payment (already activated, or cancelled in the meantime).
+func (s *QueueService) ActivatePaidEntry(ctx context.Context, orderID string) (*models.QueueEntry, bool, error) {
+	entry, err := s.GetQueueEntryByOrderID(ctx, orderID)
+	if err != nil {
+		return nil, false, err
+	}
+	if entry.Status != "PENDING_PAYMENT" {
+		return entry, false, nil
+	}
+	ctx = WithLocation(ctx, entry.LocationID)
+
+	// A paid order for later waits for its preparation to be due
+	if entry.ScheduledActivateAt != nil && entry.ScheduledActivateAt.After(s.clock.Now()) {
+		return s.schedulePaidEntry(ctx, entry)
+	}
+
+	config, err := s.GetConfiguration(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	position := s.nextLanePosition(ctx, entry.LocationID, entry.IsExpressQueue)
+	otherLength := s.otherLaneLength(ctx, entry.LocationID, entry.IsExpressQueue)
+
+	now := s.clock.Now()
+	estimatedWaitTime := s.newWaitEstimator(ctx, config).laneWait(entry.IsExpressQueue, position, otherLength, config.AvgPreparationTimePerItem, entry.ItemCount)
+	estimatedReadyTime := utils.CalculateEstimatedReadyTime(now, estimatedWaitTime)
+
+	// Guard on the status so a concurrent cancellation wins
+	result := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("id = ? AND status = ?", entry.ID, "PENDING_PAYMENT").
+		Updates(map[string]interface{}{
+			"status":               "WAITING",
+			"position":             position,
+			"estimated_wait_time":  estimatedWaitTime,
+			"estimated_ready_time": estimatedReadyTime,
+			"updated_at":           now.UTC(),
+		})
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entry, false, nil
+	}
+
+	entry.Status = "WAITING"
+	entry.Position = position
+	entry.EstimatedWaitTime = estimatedWaitTime
+	entry.EstimatedReadyTime = &estimatedReadyTime
+
+	reason := "Payment completed"
+	s.RecordPositionHistory(ctx, entry.ID, 0, position, "PENDING_PAYMENT", "WAITING", &reason)
+	utils.InvalidateQueueCache(ctx, entry.ID)
+	s.indexPosition(ctx, entry)
+	s.queueChanged(ctx, entry.ID)
+	s.recordStatusTransition(ctx, entry, "PENDING_PAYMENT", "WAITING")
+	s.notifyCustomer(ctx, entry.ID, entry.Status)
+	s.emitWebhookEvent(ctx, WebhookEventStatusChanged, entry.ID, "PENDING_PAYMENT")
+
+	if s.publisher != nil {
+		if err := s.publisher.PublishQueuePositionUpdate(ctx, entry); err != nil {
+			log.Printf("Failed to publish activation of %s: %v", entry.TokenNumber, err)
+		}
+	}
+
+	return entry, true, nil
+}
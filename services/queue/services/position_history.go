@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"gin-quickstart/models"
+)
+
+// GetPositionHistory returns every recorded position, status and ETA change
+// of an entry, oldest first
+func (s *QueueService) GetPositionHistory(ctx context.Context, entryID string) (*models.PositionHistoryResponse, error) {
+	entry, err := s.GetQueueEntryByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.positionHistory(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PositionHistoryResponse{
+		QueueEntryID: entry.ID,
+		TokenNumber:  entry.TokenNumber,
+		History:      history,
+	}, nil
+}
+
+// GetPositionHistoryByToken returns how a token's place in line and ETA
+// changed over time. Staff reasons are left out; they can name other
+// customers or hold internal notes.
+func (s *QueueService) GetPositionHistoryByToken(ctx context.Context, token string) (*models.CustomerPositionHistoryResponse, error) {
+	entry, err := s.GetQueueEntryByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.positionHistory(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]models.CustomerPositionChange, 0, len(history))
+	for _, row := range history {
+		changes = append(changes, models.CustomerPositionChange{
+			Timestamp:          row.Timestamp,
+			Position:           row.NewPosition,
+			Status:             row.NewStatus,
+			EstimatedWaitTime:  row.EstimatedWaitTime,
+			EstimatedReadyTime: row.EstimatedReadyTime,
+			Description:        describePositionChange(row),
+		})
+	}
+
+	return &models.CustomerPositionHistoryResponse{
+		TokenNumber:        entry.TokenNumber,
+		Status:             entry.Status,
+		Position:           entry.Position,
+		EstimatedWaitTime:  entry.EstimatedWaitTime,
+		EstimatedReadyTime: entry.EstimatedReadyTime,
+		History:            changes,
+	}, nil
+}
+
+func (s *QueueService) positionHistory(ctx context.Context, entryID string) ([]models.QueuePositionHistory, error) {
+	history := []models.QueuePositionHistory{}
+	if err := s.db.WithContext(ctx).
+		Where("queue_entry_id = ?", entryID).
+		Order("timestamp ASC").
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// describePositionChange words a history row for the customer
+func describePositionChange(row models.QueuePositionHistory) string {
+	switch {
+	case row.OldStatus != row.NewStatus:
+		return fmt.Sprintf("Status changed from %s to %s.", humanizeStatus(row.OldStatus), humanizeStatus(row.NewStatus))
+	case row.NewPosition < row.OldPosition:
+		return fmt.Sprintf("Moved up from position %d to %d.", row.OldPosition, row.NewPosition)
+	case row.NewPosition > row.OldPosition:
+		return fmt.Sprintf("Moved back from position %d to %d.", row.OldPosition, row.NewPosition)
+	case row.EstimatedWaitTime != nil:
+		return fmt.Sprintf("Estimated wait updated to %s.", pluralize(*row.EstimatedWaitTime, "minute"))
+	default:
+		return "Queue updated."
+	}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"gin-quickstart/models"
+)
+
+// PrepTimeProvider returns the average preparation time (minutes) of menu items
+type PrepTimeProvider interface {
+	GetAveragePreparationTime(ctx context.Context, itemIDs []string) (int, error)
+}
+
+var prepTimeProvider PrepTimeProvider
+
+// SetPrepTimeProvider sets the menu preparation time source used by queue services
+func SetPrepTimeProvider(provider PrepTimeProvider) {
+	prepTimeProvider = provider
+}
+
+// prepTimePerItem returns the average preparation time per item of an order,
+// weighted by quantity. It falls back to the configured average when the
+// order has no line items or the menu lookup fails.
+func (s *QueueService) prepTimePerItem(ctx context.Context, items []models.CreateQueueItemRequest, fallback int) int {
+	if s.prepTimes == nil || len(items) == 0 {
+		return fallback
+	}
+
+	var itemIDs []string
+	for _, item := range items {
+		for i := 0; i < item.Quantity; i++ {
+			itemIDs = append(itemIDs, item.MenuItemID)
+		}
+	}
+	if len(itemIDs) == 0 {
+		return fallback
+	}
+
+	minutes, err := s.prepTimes.GetAveragePreparationTime(ctx, itemIDs)
+	if err != nil {
+		log.Printf("Menu prep time lookup failed, using configured average: %v", err)
+		return fallback
+	}
+	if minutes <= 0 {
+		return fallback
+	}
+	return minutes
+}
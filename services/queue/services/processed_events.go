@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gin-quickstart/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// ClaimEvent records an event as processed, returning false if it already was.
+// The insert is the dedup check, so concurrent re-deliveries can't both claim it.
+func (s *QueueService) ClaimEvent(ctx context.Context, eventKey, topic string, partition int32, offset int64) (bool, error) {
+	event := &models.ProcessedEvent{
+		EventKey:    eventKey,
+		Topic:       topic,
+		Partition:   partition,
+		Offset:      offset,
+		ProcessedAt: s.clock.Now().UTC(),
+	}
+
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseEvent forgets a claimed event so a failed message can be processed again
+func (s *QueueService) ReleaseEvent(ctx context.Context, eventKey string) error {
+	return s.db.WithContext(ctx).Where("event_key = ?", eventKey).Delete(&models.ProcessedEvent{}).Error
+}
+
+// PurgeProcessedEvents deletes dedup records older than the retention period
+func (s *QueueService) PurgeProcessedEvents(ctx context.Context, retention time.Duration) (int64, error) {
+	cutoff := s.clock.Now().UTC().Add(-retention)
+	result := s.db.WithContext(ctx).Where("processed_at < ?", cutoff).Delete(&models.ProcessedEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// StartProcessedEventPurger periodically purges old dedup records until ctx is cancelled
+func (s *QueueService) StartProcessedEventPurger(ctx context.Context, retention, interval time.Duration) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			purged, err := s.PurgeProcessedEvents(ctx, retention)
+			if err != nil {
+				log.Printf("Failed to purge processed events: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Purged %d processed events", purged)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"gin-quickstart/database"
+	"gin-quickstart/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// positionIndexKey holds a lane of a location's active queue as a sorted set
+// of entry IDs, ordered the way the lane is served, so "people ahead" is a
+// ZRANK instead of a COUNT.
+func positionIndexKey(locationID string, isExpress bool) string {
+	if isExpress {
+		return fmt.Sprintf("queue:positions:%s:express", locationID)
+	}
+	return fmt.Sprintf("queue:positions:%s", locationID)
+}
+
+// activeStatuses are the statuses that hold a place in the queue
+var activeStatuses = []string{"WAITING", "IN_PROGRESS"}
+
+// priorityRanks orders priorities as the queue serves them (VIP first)
+var priorityRanks = map[string]int{
+	"VIP":    0,
+	"URGENT": 1,
+	"HIGH":   2,
+	"NORMAL": 3,
+	"LOW":    4,
+}
+
+// prioritySpan separates priority bands; positions stay well below it
+const prioritySpan = 1e9
+
+// positionScore weights an entry's sequence by its priority band
+func positionScore(priority string, position int) float64 {
+	rank, ok := priorityRanks[priority]
+	if !ok {
+		rank = priorityRanks["NORMAL"]
+	}
+	return float64(rank)*prioritySpan + float64(position)
+}
+
+func isActiveStatus(status string) bool {
+	return status == "WAITING" || status == "IN_PROGRESS"
+}
+
+// indexPosition adds, moves or removes an entry in the position index after
+// its status, priority or position changed
+func (s *QueueService) indexPosition(ctx context.Context, entry *models.QueueEntry) {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return
+	}
+
+	var err error
+	if isActiveStatus(entry.Status) {
+		err = rdb.ZAdd(ctx, positionIndexKey(entry.LocationID, entry.IsExpressQueue), redis.Z{
+			Score:  positionScore(entry.Priority, entry.Position),
+			Member: entry.ID,
+		}).Err()
+	} else {
+		err = rdb.ZRem(ctx, positionIndexKey(entry.LocationID, entry.IsExpressQueue), entry.ID).Err()
+	}
+	if err != nil {
+		// The next recalculation rebuilds the index from MySQL
+		log.Printf("Failed to update position index for %s: %v", entry.ID, err)
+	}
+}
+
+// rebuildPositionIndex replaces a location's position indexes (one per lane)
+// with the given active entries
+func (s *QueueService) rebuildPositionIndex(ctx context.Context, locationID string, entries []models.QueueEntry) error {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return nil
+	}
+
+	members := make(map[bool][]redis.Z, 2)
+	for _, entry := range entries {
+		members[entry.IsExpressQueue] = append(members[entry.IsExpressQueue], redis.Z{
+			Score:  positionScore(entry.Priority, entry.Position),
+			Member: entry.ID,
+		})
+	}
+
+	pipe := rdb.TxPipeline()
+	for _, isExpress := range []bool{false, true} {
+		key := positionIndexKey(locationID, isExpress)
+		pipe.Del(ctx, key)
+		if len(members[isExpress]) > 0 {
+			pipe.ZAdd(ctx, key, members[isExpress]...)
+		}
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RebuildPositionIndex reloads a location's position index from its active entries in MySQL
+func (s *QueueService) RebuildPositionIndex(ctx context.Context, locationID string) error {
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).Select("id", "priority", "position", "is_express_queue").
+		Where("location_id = ? AND status IN ?", locationID, activeStatuses).
+		Find(&entries).Error; err != nil {
+		return err
+	}
+	return s.rebuildPositionIndex(ctx, locationID, entries)
+}
+
+// peopleAhead counts the active entries ahead of entry in its lane. It reads
+// the position index and falls back to counting in MySQL when Redis is
+// unavailable or the entry is missing from the index (which is then rebuilt).
+func (s *QueueService) peopleAhead(ctx context.Context, entry *models.QueueEntry) int {
+	if rdb := database.GetRedis(); rdb != nil && isActiveStatus(entry.Status) {
+		rank, err := rdb.ZRank(ctx, positionIndexKey(entry.LocationID, entry.IsExpressQueue), entry.ID).Result()
+		if err == nil {
+			return int(rank)
+		}
+		if errors.Is(err, redis.Nil) {
+			go func() {
+				if err := s.RebuildPositionIndex(context.WithoutCancel(ctx), entry.LocationID); err != nil {
+					log.Printf("Failed to rebuild position index: %v", err)
+				}
+			}()
+		}
+	}
+
+	var count int64
+	s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("location_id = ? AND is_express_queue = ? AND status IN ? AND position < ?", entry.LocationID, entry.IsExpressQueue, activeStatuses, entry.Position).
+		Count(&count)
+	return int(count)
+}
@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrResetNotConfirmed is returned when a reset's confirmation token is
+// missing or no longer matches the queue
+var ErrResetNotConfirmed = newError(KindConflict, "RESET_NOT_CONFIRMED", "confirm the reset with the confirmation_token of its preview")
+
+// resettableStatuses are the statuses a reset closes out
+var resettableStatuses = []string{"PENDING_PAYMENT", "SCHEDULED", "WAITING", "IN_PROGRESS", "READY"}
+
+// ResetQueue completes or expires every entry still open in the request's
+// location, clears their positions and, if asked, restarts the token
+// numbering. It only runs when req carries the confirmation token of the
+// queue as it is now; otherwise it returns ErrResetNotConfirmed along with a
+// preview holding the token.
+func (s *QueueService) ResetQueue(ctx context.Context, req *models.ResetQueueRequest, staffID, staffName string) (*models.QueueResetResult, *models.QueueResetPreview, error) {
+	locationID := LocationFromContext(ctx)
+	location, err := s.activeLocation(ctx, locationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outcome := req.Outcome
+	if outcome == "" {
+		outcome = "EXPIRED"
+	}
+	prefix := location.TokenPrefix
+	if req.TokenPrefix != nil {
+		prefix = strings.ToUpper(*req.TokenPrefix)
+	}
+
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Where("location_id = ? AND status IN ?", locationID, resettableStatuses).
+		Order("created_at ASC, id ASC").
+		Find(&entries).Error; err != nil {
+		return nil, nil, err
+	}
+
+	preview := &models.QueueResetPreview{
+		LocationID:        locationID,
+		Outcome:           outcome,
+		ActiveEntries:     len(entries),
+		ByStatus:          make(map[string]int),
+		ResetTokenCounter: req.ResetTokenCounter,
+		TokenPrefix:       prefix,
+	}
+	for _, entry := range entries {
+		preview.ByStatus[entry.Status]++
+	}
+	preview.ConfirmationToken = resetConfirmationToken(preview, entries)
+
+	if req.ConfirmationToken != preview.ConfirmationToken {
+		return nil, preview, ErrResetNotConfirmed
+	}
+
+	// The counter goes first: a taken prefix stops the reset before any entry changes
+	if req.ResetTokenCounter {
+		if err := s.resetTokenCounter(ctx, location, prefix); err != nil {
+			return nil, preview, err
+		}
+	}
+
+	reason := req.Reason
+	if reason == nil {
+		reason = utils.StringPtr("Queue reset by admin")
+	}
+	if err := s.closeOutEntries(ctx, locationID, entries, outcome, "RESET", staffID, staffName, reason); err != nil {
+		return nil, preview, err
+	}
+
+	return &models.QueueResetResult{
+		LocationID:        locationID,
+		Outcome:           outcome,
+		EntriesReset:      len(entries),
+		ByStatus:          preview.ByStatus,
+		TokenCounterReset: req.ResetTokenCounter,
+		TokenPrefix:       location.TokenPrefix,
+	}, nil, nil
+}
+
+// closeOutEntries moves open entries of a location to outcome, logging
+// action for each, and clears the location's positions
+func (s *QueueService) closeOutEntries(ctx context.Context, locationID string, entries []models.QueueEntry, outcome, action, staffID, staffName string, reason *string) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.updateClosedOut(tx, entries, outcome)
+	})
+	if err != nil {
+		return err
+	}
+	ids := s.afterCloseOut(ctx, entries, outcome, action, staffID, staffName, reason)
+
+	// Nothing is left in line
+	if err := s.rebuildPositionIndex(ctx, locationID, nil); err != nil {
+		return fmt.Errorf("failed to clear position index: %w", err)
+	}
+	s.queueChanged(ctx, ids...)
+	return nil
+}
+
+// updateClosedOut moves entries to outcome in tx, taking them out of line
+func (s *QueueService) updateClosedOut(tx *gorm.DB, entries []models.QueueEntry, outcome string) error {
+	now := s.clock.Now().UTC()
+	for _, entry := range entries {
+		updates := map[string]interface{}{
+			"status":            outcome,
+			"position":          0,
+			"start_deferred_at": nil,
+			"skip_restore_at":   nil,
+			"updated_at":        now,
+		}
+		if outcome == "COMPLETED" && entry.ActualCompletionTime == nil {
+			updates["actual_completion_time"] = now
+		}
+		if err := tx.Model(&models.QueueEntry{}).Where("id = ?", entry.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// afterCloseOut logs, notifies and counts entries once their move to
+// outcome is committed, returning their IDs
+func (s *QueueService) afterCloseOut(ctx context.Context, entries []models.QueueEntry, outcome, action, staffID, staffName string, reason *string) []string {
+	ids := make([]string, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		oldStatus := entry.Status
+		oldPosition := entry.Position
+		ids[i] = entry.ID
+
+		s.LogStaffAction(ctx, entry.ID, staffID, staffName, action, &oldStatus, &outcome, nil, nil, reason)
+		s.RecordPositionHistory(ctx, entry.ID, oldPosition, 0, oldStatus, outcome, reason)
+		utils.InvalidateQueueCache(ctx, entry.ID)
+
+		entry.Status = outcome
+		entry.Position = 0
+		s.updateCounterServing(ctx, entry)
+		s.closeLinkedOrders(ctx, entry.ID, outcome)
+		s.emitWebhookEvent(ctx, WebhookEventStatusChanged, entry.ID, oldStatus)
+		s.recordStatusTransition(ctx, entry, oldStatus, outcome)
+	}
+	return ids
+}
+
+// resetConfirmationToken fingerprints a reset and the entries it would
+// close, so a confirmation only holds while the queue stays as previewed
+func resetConfirmationToken(preview *models.QueueResetPreview, entries []models.QueueEntry) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|%s|%t|%s\n", preview.LocationID, preview.Outcome, preview.ResetTokenCounter, preview.TokenPrefix)
+	for _, entry := range entries {
+		fmt.Fprintf(hash, "%s:%s\n", entry.ID, entry.Status)
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16]
+}
@@ -3,8 +3,12 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
+	"gin-quickstart/clock"
 	"gin-quickstart/database"
 	"gin-quickstart/models"
 	"gin-quickstart/utils"
@@ -13,23 +17,52 @@ import (
 )
 
 type QueueService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	publisher     EventPublisher
+	prepTimes     PrepTimeProvider
+	waitPredictor WaitPredictor
+	clock         clock.Clock
 }
 
+// ErrDuplicateOrder is returned when an order already has a queue entry
+var ErrDuplicateOrder = newError(KindConflict, "DUPLICATE_ORDER", "order already in queue")
+
+// ErrQueueEmpty is returned when advancing a queue with no waiting entries
+var ErrQueueEmpty = newError(KindConflict, "QUEUE_EMPTY", "no entries in queue")
+
 func NewQueueService() *QueueService {
 	return &QueueService{
-		db: database.GetDB(),
+		db:            database.GetDB(),
+		publisher:     eventPublisher,
+		prepTimes:     prepTimeProvider,
+		waitPredictor: waitPredictor,
+		clock:         serviceClock,
 	}
 }
 
 // CreateQueueEntry creates a new queue entry
 func (s *QueueService) CreateQueueEntry(ctx context.Context, req *models.CreateQueueEntryRequest) (*models.QueueEntry, error) {
-	// Check if order already in queue
+	// Check if order already in queue. A deleted entry still holds its
+	// order, and is restored rather than queued again.
 	var existing models.QueueEntry
-	if err := s.db.Where("order_id = ?", req.OrderID).First(&existing).Error; err == nil {
-		return nil, errors.New("order already in queue")
+	if err := s.db.WithContext(ctx).Unscoped().Where("order_id = ?", req.OrderID).First(&existing).Error; err == nil {
+		return nil, ErrDuplicateOrder
 	}
 
+	// The order's location, else the one the request is scoped to
+	locationID := req.LocationID
+	if scoped, ok := scopedLocation(ctx); ok && locationID != "" && locationID != scoped {
+		return nil, ErrLocationMismatch
+	}
+	if locationID == "" {
+		locationID = LocationFromContext(ctx)
+	}
+	location, err := s.activeLocation(ctx, locationID)
+	if err != nil {
+		return nil, err
+	}
+	ctx = WithLocation(ctx, locationID)
+
 	// Get configuration
 	config, err := s.GetConfiguration(ctx)
 	if err != nil {
@@ -37,20 +70,11 @@ func (s *QueueService) CreateQueueEntry(ctx context.Context, req *models.CreateQ
 	}
 
 	// Generate token number
-	tokenNumber, err := utils.GenerateTokenNumber(s.db)
+	tokenNumber, err := s.nextTokenNumber(ctx, location)
 	if err != nil {
 		return nil, err
 	}
 
-	// Calculate position
-	var currentMaxPosition int
-	s.db.Model(&models.QueueEntry{}).
-		Where("status IN ?", []string{"WAITING", "IN_PROGRESS"}).
-		Select("COALESCE(MAX(position), 0)").
-		Scan(&currentMaxPosition)
-
-	newPosition := currentMaxPosition + 1
-
 	// Set defaults
 	tokenType := req.TokenType
 	if tokenType == "" {
@@ -62,72 +86,178 @@ func (s *QueueService) CreateQueueEntry(ctx context.Context, req *models.CreateQ
 		priority = "NORMAL"
 	}
 
-	// Calculate estimated times
-	estimatedWaitTime := utils.CalculateEstimatedWaitTime(
-		newPosition,
-		config.AvgPreparationTimePerItem,
-		config.BufferTime,
-	)
-	estimatedReadyTime := utils.CalculateEstimatedReadyTime(estimatedWaitTime)
+	// Overflow full lanes and priorities to the next one down
+	isExpress, priority, fallbackNotes, err := s.applyDepthLimits(ctx, req.IsExpressQueue, priority)
+	if err != nil {
+		return nil, err
+	}
+	if !isExpress && tokenType == "EXPRESS" {
+		tokenType = "REGULAR"
+	}
+
+	// Calculate position at the back of the entry's lane
+	newPosition := s.nextLanePosition(ctx, locationID, isExpress)
+	otherLength := s.otherLaneLength(ctx, locationID, isExpress)
+
+	// Calculate estimated times from the menu prep times of the ordered items,
+	// counting the other lane's entries served in between
+	prepTimePerItem := s.prepTimePerItem(ctx, req.Items, config.AvgPreparationTimePerItem)
+	estimatedWaitTime := s.newWaitEstimator(ctx, config).laneWait(isExpress, newPosition, otherLength, prepTimePerItem, req.ItemCount)
+	estimatedReadyTime := utils.CalculateEstimatedReadyTime(s.clock.Now(), estimatedWaitTime)
+
+	// Orders for later join the queue once their preparation is due
+	var activateAt *time.Time
+	if req.RequestedPickupTime != nil {
+		due := scheduledActivation(*req.RequestedPickupTime, prepTimePerItem*max(req.ItemCount, 1), config.BufferTime)
+		if due.After(s.clock.Now()) {
+			activateAt = utils.TimePtr(due.UTC())
+		}
+	}
+
+	// Unpaid and scheduled orders stay outside the active positions until
+	// payment completes or they're due
+	status := "WAITING"
+	readyTime := &estimatedReadyTime
+	if req.AwaitingPayment {
+		status = "PENDING_PAYMENT"
+		newPosition = 0
+		estimatedWaitTime = 0
+		readyTime = nil
+	} else if activateAt != nil {
+		status = "SCHEDULED"
+		newPosition = 0
+		estimatedWaitTime = 0
+		readyTime = req.RequestedPickupTime
+	}
 
 	// Create entry
 	entry := &models.QueueEntry{
 		ID:                         utils.GenerateUUID(),
 		OrderID:                    req.OrderID,
+		LocationID:                 locationID,
 		UserID:                     req.UserID,
 		UserName:                   utils.StringPtr(req.UserName),
 		UserPhone:                  utils.StringPtr(req.UserPhone),
 		TokenNumber:                tokenNumber,
 		TokenType:                  tokenType,
-		Status:                     "WAITING",
+		Status:                     status,
 		Priority:                   priority,
 		Position:                   newPosition,
+		ItemCount:                  req.ItemCount,
 		EstimatedWaitTime:          estimatedWaitTime,
-		EstimatedReadyTime:         &estimatedReadyTime,
-		IsExpressQueue:             req.IsExpressQueue,
+		EstimatedReadyTime:         readyTime,
+		RequestedPickupTime:        req.RequestedPickupTime,
+		ScheduledActivateAt:        activateAt,
+		IsExpressQueue:             isExpress,
 		SpecialHandling:            utils.StringPtr(req.SpecialHandling),
-		AverageItemPreparationTime: utils.IntPtr(config.AvgPreparationTimePerItem * req.ItemCount),
-		CreatedAt:                  time.Now().UTC(),
-		UpdatedAt:                  time.Now().UTC(),
+		AverageItemPreparationTime: utils.IntPtr(prepTimePerItem * req.ItemCount),
+		CreatedAt:                  s.clock.Now().UTC(),
+		UpdatedAt:                  s.clock.Now().UTC(),
+	}
+	if len(fallbackNotes) > 0 {
+		entry.Notes = utils.StringPtr(strings.Join(fallbackNotes, "; "))
 	}
 
-	if err := s.db.Create(entry).Error; err != nil {
+	// The entry, its stages (when the order is split across pickup times)
+	// and its line items are created together
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		if len(req.Stages) > 0 {
+			stages := s.newStages(entry, req.Stages)
+			if err := tx.Create(&stages).Error; err != nil {
+				return err
+			}
+		}
+		if len(req.Items) > 0 {
+			return s.saveEntryItems(tx, entry.ID, req.Items)
+		}
+		return nil
+	})
+	if err != nil {
+		// Lost a race with a concurrent create for the same order
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			if _, lookupErr := s.GetQueueEntryByOrderID(ctx, req.OrderID); lookupErr == nil {
+				return nil, ErrDuplicateOrder
+			}
+		}
 		return nil, err
 	}
 
+	// Look for batching opportunities with neighbouring orders
+	if len(req.Items) > 0 {
+		go s.suggestBatchesFor(context.WithoutCancel(ctx), entry)
+	}
+
 	// Cache in Redis
 	utils.CacheQueueEntry(ctx, entry)
+	s.indexPosition(ctx, entry)
+	s.queueChanged(ctx, entry.ID)
 
 	// Update statistics
-	go s.UpdateStatistics(ctx)
+	s.recordStatusTransition(ctx, entry, "", entry.Status)
+
+	s.notifyCustomer(ctx, entry.ID, entry.Status)
+	s.emitWebhookEvent(ctx, WebhookEventEntryCreated, entry.ID, "")
 
 	return entry, nil
 }
 
-// GetQueueEntryByToken retrieves queue entry by token number
+// EnqueueOrder creates a queue entry for an order, or returns the existing one.
+// created is false when the order was already queued.
+func (s *QueueService) EnqueueOrder(ctx context.Context, req *models.CreateQueueEntryRequest) (entry *models.QueueEntry, created bool, err error) {
+	// Orders linked to a party token are picked up with it
+	if link, err := s.GetOrderLink(ctx, req.OrderID); err == nil {
+		entry, err = s.GetQueueEntryByID(ctx, link.QueueEntryID)
+		return entry, false, err
+	}
+
+	entry, err = s.CreateQueueEntry(ctx, req)
+	if errors.Is(err, ErrDuplicateOrder) {
+		entry, err = s.GetQueueEntryByOrderID(ctx, req.OrderID)
+		return entry, false, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return entry, true, nil
+}
+
+// GetQueueEntryByToken retrieves queue entry by token number, from the cache when possible
 func (s *QueueService) GetQueueEntryByToken(ctx context.Context, token string) (*models.QueueEntry, error) {
+	if cached, err := utils.GetCachedQueueEntryByToken(ctx, token); err == nil {
+		return cached, nil
+	}
+
 	var entry models.QueueEntry
-	if err := s.db.Where("token_number = ?", token).First(&entry).Error; err != nil {
+	if err := s.db.WithContext(ctx).Where("token_number = ?", token).First(&entry).Error; err != nil {
 		return nil, err
 	}
+	utils.CacheQueueEntry(ctx, &entry)
 	return &entry, nil
 }
 
-// GetQueueEntryByID retrieves queue entry by ID
+// GetQueueEntryByID retrieves queue entry by ID, within the location ctx is scoped to
 func (s *QueueService) GetQueueEntryByID(ctx context.Context, id string) (*models.QueueEntry, error) {
 	var entry models.QueueEntry
-	if err := s.db.Where("id = ?", id).First(&entry).Error; err != nil {
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", id).First(&entry).Error; err != nil {
 		return nil, err
 	}
 	return &entry, nil
 }
 
-// GetQueueEntryByOrderID retrieves queue entry by order ID
+// GetQueueEntryByOrderID retrieves queue entry by order ID, from the cache when possible
 func (s *QueueService) GetQueueEntryByOrderID(ctx context.Context, orderID string) (*models.QueueEntry, error) {
+	if cached, err := utils.GetCachedQueueEntryByOrderID(ctx, orderID); err == nil {
+		return cached, nil
+	}
+
 	var entry models.QueueEntry
-	if err := s.db.Where("order_id = ?", orderID).First(&entry).Error; err != nil {
+	if err := s.db.WithContext(ctx).Where("order_id = ?", orderID).First(&entry).Error; err != nil {
 		return nil, err
 	}
+	utils.CacheQueueEntry(ctx, &entry)
 	return &entry, nil
 }
 
@@ -138,63 +268,75 @@ func (s *QueueService) GetQueuePosition(ctx context.Context, token string) (*mod
 		return nil, err
 	}
 
-	// Count people ahead
-	var peopleAhead int64
-	s.db.Model(&models.QueueEntry{}).
-		Where("status IN ? AND position < ?", []string{"WAITING", "IN_PROGRESS"}, entry.Position).
-		Count(&peopleAhead)
+	// Count people ahead in the entry's lane and those of the other lane served in between
+	peopleAhead := s.peopleAhead(ctx, entry)
+	if config, err := s.GetConfiguration(WithLocation(ctx, entry.LocationID)); err == nil {
+		peopleAhead += s.otherLaneAheadOf(ctx, config, entry)
+	}
 
 	return &models.QueuePositionResponse{
 		QueueEntry:         entry,
 		Position:           entry.Position,
 		EstimatedWaitTime:  entry.EstimatedWaitTime,
 		EstimatedReadyTime: entry.EstimatedReadyTime,
-		PeopleAhead:        int(peopleAhead),
+		PeopleAhead:        peopleAhead,
 	}, nil
 }
 
-// GetCurrentQueue gets current queue state
+// GetCurrentQueue gets current queue state from the active-queue snapshot
 func (s *QueueService) GetCurrentQueue(ctx context.Context) (*models.CurrentQueueResponse, error) {
-	var waiting, inProgress, ready []models.QueueEntry
-
-	s.db.Where("status = ?", "WAITING").Order("position ASC").Find(&waiting)
-	s.db.Where("status = ?", "IN_PROGRESS").Order("position ASC").Find(&inProgress)
-	s.db.Where("status = ?", "READY").Order("actual_ready_time DESC").Limit(20).Find(&ready)
+	entries, err := s.GetActiveQueueEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	return &models.CurrentQueueResponse{
-		Waiting:     waiting,
-		InProgress:  inProgress,
-		Ready:       ready,
-		TotalActive: len(waiting) + len(inProgress) + len(ready),
-	}, nil
+	queue := currentQueueFromSnapshot(entries)
+	queue.NowServing = s.nowServing(ctx, entries)
+	return queue, nil
 }
 
 // UpdateQueueStatus updates queue entry status
 func (s *QueueService) UpdateQueueStatus(ctx context.Context, entryID string, req *models.UpdateQueueStatusRequest, staffID string, staffName string) error {
 	var entry models.QueueEntry
-	if err := s.db.Where("id = ?", entryID).First(&entry).Error; err != nil {
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", entryID).First(&entry).Error; err != nil {
 		return err
 	}
+	ctx = WithLocation(ctx, entry.LocationID)
 
 	oldStatus := entry.Status
 	oldPosition := entry.Position
 
+	// An entry that left the queue only comes back through a requeue
+	if isTerminalStatus(oldStatus) && req.Status != oldStatus {
+		return fmt.Errorf("%w: %s entries can't become %s", ErrInvalidTransition, oldStatus, req.Status)
+	}
+
+	// A party token is ready once all its orders are
+	if (req.Status == "READY" || req.Status == "COMPLETED") && req.Status != oldStatus {
+		if err := s.checkPartyReady(ctx, entry.ID); err != nil {
+			return err
+		}
+	}
+
+	counter, err := s.resolveCounter(ctx, req.CounterID, req.AssignedCounter)
+	if err != nil {
+		return err
+	}
+
 	// Update status
 	updates := map[string]interface{}{
 		"status":     req.Status,
-		"updated_at": time.Now().UTC(),
+		"updated_at": s.clock.Now().UTC(),
 	}
 
 	// Set timestamps based on status
-	now := time.Now().UTC()
+	now := s.clock.Now().UTC()
+	firstReady := req.Status == "READY" && entry.ActualReadyTime == nil
 	switch req.Status {
 	case "IN_PROGRESS":
 		if entry.ActualStartTime == nil {
 			updates["actual_start_time"] = now
 		}
-		if req.AssignedCounter != nil {
-			updates["assigned_counter"] = *req.AssignedCounter
-		}
 		if req.AssignedStaff != nil {
 			updates["assigned_staff"] = *req.AssignedStaff
 		}
@@ -208,11 +350,30 @@ func (s *QueueService) UpdateQueueStatus(ctx context.Context, entryID string, re
 		}
 	}
 
+	if counter != nil {
+		updates["counter_id"] = counter.ID
+		updates["assigned_counter"] = counter.Name
+		entry.CounterID = &counter.ID
+		entry.AssignedCounter = &counter.Name
+	}
+
 	if req.Notes != nil {
 		updates["notes"] = *req.Notes
 	}
+	if entry.StartDeferredAt != nil && req.Status != "WAITING" {
+		updates["start_deferred_at"] = nil
+	}
 
-	if err := s.db.Model(&entry).Updates(updates).Error; err != nil {
+	// Starting an entry takes one of the location's MaxConcurrentOrders slots
+	if req.Status == "IN_PROGRESS" && oldStatus != "IN_PROGRESS" {
+		err = s.startWithinCapacity(ctx, &entry, updates)
+		if errors.Is(err, ErrAtCapacity) && req.DeferIfFull {
+			return s.deferStart(ctx, &entry, staffID, staffName, req.Reason)
+		}
+	} else {
+		err = s.db.WithContext(ctx).Model(&entry).Updates(updates).Error
+	}
+	if err != nil {
 		return err
 	}
 
@@ -224,14 +385,35 @@ func (s *QueueService) UpdateQueueStatus(ctx context.Context, entryID string, re
 
 	// Invalidate cache
 	utils.InvalidateQueueCache(ctx, entryID)
+	entry.Status = req.Status
+	s.indexPosition(ctx, &entry)
+	s.updateCounterServing(ctx, &entry)
+	s.queueChanged(ctx, entryID)
+
+	// Text/email/push the customer when their order is almost ready or ready
+	if req.Status != oldStatus {
+		s.notifyCustomer(ctx, entryID, req.Status)
+		s.emitWebhookEvent(ctx, WebhookEventStatusChanged, entryID, oldStatus)
+		s.closeLinkedOrders(ctx, entryID, req.Status)
+	}
+
+	// The actual preparation time feeds the learned average
+	if firstReady {
+		s.learnPrepTime(ctx, &entry, now)
+	}
 
 	// Recalculate positions if needed
-	if req.Status == "COMPLETED" || req.Status == "CANCELLED" || req.Status == "NO_SHOW" {
-		go s.RecalculatePositions(ctx)
+	if IsFinalStatus(req.Status) {
+		go s.RecalculatePositions(context.WithoutCancel(ctx))
+	}
+
+	// A freed slot starts the next deferred entry
+	if oldStatus == "IN_PROGRESS" && req.Status != "IN_PROGRESS" {
+		go s.startDeferred(context.WithoutCancel(ctx), entry.LocationID)
 	}
 
 	// Update statistics
-	go s.UpdateStatistics(ctx)
+	s.recordStatusTransition(ctx, &entry, oldStatus, req.Status)
 
 	return nil
 }
@@ -239,18 +421,19 @@ func (s *QueueService) UpdateQueueStatus(ctx context.Context, entryID string, re
 // UpdateQueuePriority updates queue entry priority
 func (s *QueueService) UpdateQueuePriority(ctx context.Context, entryID string, req *models.UpdateQueuePriorityRequest, staffID string, staffName string) error {
 	var entry models.QueueEntry
-	if err := s.db.Where("id = ?", entryID).First(&entry).Error; err != nil {
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", entryID).First(&entry).Error; err != nil {
 		return err
 	}
+	ctx = WithLocation(ctx, entry.LocationID)
 
 	oldPriority := entry.Priority
 
 	updates := map[string]interface{}{
 		"priority":   req.Priority,
-		"updated_at": time.Now().UTC(),
+		"updated_at": s.clock.Now().UTC(),
 	}
 
-	if err := s.db.Model(&entry).Updates(updates).Error; err != nil {
+	if err := s.db.WithContext(ctx).Model(&entry).Updates(updates).Error; err != nil {
 		return err
 	}
 
@@ -259,64 +442,130 @@ func (s *QueueService) UpdateQueuePriority(ctx context.Context, entryID string,
 
 	// Invalidate cache
 	utils.InvalidateQueueCache(ctx, entryID)
+	entry.Priority = req.Priority
+	s.indexPosition(ctx, &entry)
+	s.queueChanged(ctx, entryID)
 
 	// Recalculate wait times
-	go s.RecalculatePositions(ctx)
+	go s.RecalculatePositions(context.WithoutCancel(ctx))
 
 	return nil
 }
 
 // AssignStaff assigns staff to queue entry
 func (s *QueueService) AssignStaff(ctx context.Context, entryID string, req *models.AssignStaffRequest, staffID string, staffName string) error {
+	var entry models.QueueEntry
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", entryID).First(&entry).Error; err != nil {
+		return err
+	}
+	ctx = WithLocation(ctx, entry.LocationID)
+
+	counter, err := s.resolveCounter(ctx, req.CounterID, req.Counter)
+	if err != nil {
+		return err
+	}
+
 	updates := map[string]interface{}{
 		"assigned_staff":      req.StaffID,
 		"assigned_staff_name": req.StaffName,
-		"updated_at":          time.Now().UTC(),
+		"updated_at":          s.clock.Now().UTC(),
 	}
 
-	if req.Counter != nil {
-		updates["assigned_counter"] = *req.Counter
+	if counter != nil {
+		updates["counter_id"] = counter.ID
+		updates["assigned_counter"] = counter.Name
 	}
 
-	if err := s.db.Model(&models.QueueEntry{}).Where("id = ?", entryID).Updates(updates).Error; err != nil {
+	if err := s.db.WithContext(ctx).Model(&entry).Updates(updates).Error; err != nil {
 		return err
 	}
 
+	if counter != nil {
+		entry.CounterID = &counter.ID
+		entry.AssignedCounter = &counter.Name
+		s.updateCounterServing(ctx, &entry)
+	}
+
 	// Log action
 	s.LogStaffAction(ctx, entryID, staffID, staffName, "REASSIGN", nil, nil, nil, nil, utils.StringPtr("Staff assigned"))
 
 	// Invalidate cache
 	utils.InvalidateQueueCache(ctx, entryID)
+	s.queueChanged(ctx, entryID)
 
 	return nil
 }
 
-// AdvanceQueue advances the queue (staff action)
+// AdvanceQueue starts the next entry of the location's queue (staff action).
+// Only staff on shift there can advance it; the entry is assigned to them and
+// the counter they clocked in at.
 func (s *QueueService) AdvanceQueue(ctx context.Context, staffID string, staffName string) error {
-	// Get next waiting entry
+	shift, err := s.activeShift(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotOnDuty
+		}
+		return err
+	}
+	if scoped, ok := scopedLocation(ctx); ok && scoped != shift.LocationID {
+		return ErrNotOnDuty
+	}
+	ctx = WithLocation(ctx, shift.LocationID)
+
+	config, err := s.GetConfiguration(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Get next waiting entry of the lane whose turn it is, else of the other lane
+	locationID := LocationFromContext(ctx)
+	express := s.nextLaneToServe(ctx, locationID, config)
 	var entry models.QueueEntry
-	if err := s.db.Where("status = ?", "WAITING").
-		Order("priority DESC, position ASC").
-		First(&entry).Error; err != nil {
+	err = s.db.WithContext(ctx).Where("location_id = ? AND is_express_queue = ? AND status = ?", locationID, express, "WAITING").
+		Order(priorityOrder + " DESC, position ASC").
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = s.db.WithContext(ctx).Where("location_id = ? AND is_express_queue = ? AND status = ?", locationID, !express, "WAITING").
+			Order(priorityOrder + " DESC, position ASC").
+			First(&entry).Error
+	}
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("no entries in queue")
+			return ErrQueueEmpty
 		}
 		return err
 	}
 
 	// Move to IN_PROGRESS
 	req := &models.UpdateQueueStatusRequest{
-		Status: "IN_PROGRESS",
+		Status:        "IN_PROGRESS",
+		AssignedStaff: &staffID,
+	}
+	// A counter closed since clocking in leaves the entry unassigned
+	if shift.CounterID != nil {
+		if _, err := s.resolveCounter(ctx, shift.CounterID, nil); err == nil {
+			req.CounterID = shift.CounterID
+		}
 	}
 
 	return s.UpdateQueueStatus(ctx, entry.ID, req, staffID, staffName)
 }
 
-// RecalculatePositions recalculates all positions and estimated times
+// RecalculatePositions recalculates all positions and estimated times of a
+// location's queue, numbering each lane separately
 func (s *QueueService) RecalculatePositions(ctx context.Context) error {
+	return s.recalculatePositions(ctx, nil, nil)
+}
+
+// recalculatePositions recalculates the location's queue. movedFrom holds the
+// positions of entries already moved by the caller, which their history
+// records as the old position along with the reason.
+func (s *QueueService) recalculatePositions(ctx context.Context, movedFrom map[string]int, reason *string) error {
+	locationID := LocationFromContext(ctx)
+
 	var entries []models.QueueEntry
-	if err := s.db.Where("status IN ?", []string{"WAITING", "IN_PROGRESS"}).
-		Order("priority DESC, position ASC").
+	if err := s.db.WithContext(ctx).Where("location_id = ? AND status IN ?", locationID, []string{"WAITING", "IN_PROGRESS"}).
+		Order("is_express_queue ASC, " + priorityOrder + " DESC, position ASC").
 		Find(&entries).Error; err != nil {
 		return err
 	}
@@ -326,46 +575,140 @@ func (s *QueueService) RecalculatePositions(ctx context.Context) error {
 		return err
 	}
 
-	for i, entry := range entries {
-		newPosition := i + 1
-		estimatedWaitTime := utils.CalculateEstimatedWaitTime(newPosition, config.AvgPreparationTimePerItem, config.BufferTime)
-		estimatedReadyTime := utils.CalculateEstimatedReadyTime(estimatedWaitTime)
+	laneLength := make(map[bool]int, 2)
+	for _, entry := range entries {
+		laneLength[entry.IsExpressQueue]++
+	}
+	estimator := s.newWaitEstimator(ctx, config)
 
-		s.db.Model(&models.QueueEntry{}).Where("id = ?", entry.ID).Updates(map[string]interface{}{
+	ids := make([]string, len(entries))
+	lanePosition := make(map[bool]int, 2)
+	for i, entry := range entries {
+		lanePosition[entry.IsExpressQueue]++
+		newPosition := lanePosition[entry.IsExpressQueue]
+		entries[i].Position = newPosition
+		ids[i] = entry.ID
+		estimatedWaitTime := estimator.laneWait(entry.IsExpressQueue, newPosition, laneLength[!entry.IsExpressQueue], config.AvgPreparationTimePerItem, entry.ItemCount)
+		estimatedReadyTime := utils.CalculateEstimatedReadyTime(s.clock.Now(), estimatedWaitTime)
+
+		s.db.WithContext(ctx).Model(&models.QueueEntry{}).Where("id = ?", entry.ID).Updates(map[string]interface{}{
 			"position":              newPosition,
 			"estimated_wait_time":   estimatedWaitTime,
 			"estimated_ready_time":  estimatedReadyTime,
-			"updated_at":            time.Now().UTC(),
+			"updated_at":            s.clock.Now().UTC(),
 		})
+		utils.InvalidateQueueCache(ctx, entry.ID)
+
+		// Keep the moves and ETA changes customers see in their history
+		oldPosition, moved := movedFrom[entry.ID]
+		if !moved {
+			oldPosition = entry.Position
+		}
+		if newPosition != oldPosition || estimatedWaitTime != entry.EstimatedWaitTime {
+			history := &models.QueuePositionHistory{
+				ID:                 utils.GenerateUUID(),
+				QueueEntryID:       entry.ID,
+				OldPosition:        oldPosition,
+				NewPosition:        newPosition,
+				OldStatus:          entry.Status,
+				NewStatus:          entry.Status,
+				EstimatedWaitTime:  &estimatedWaitTime,
+				EstimatedReadyTime: &estimatedReadyTime,
+				Timestamp:          s.clock.Now().UTC(),
+			}
+			if moved {
+				history.Reason = reason
+			}
+			s.db.WithContext(ctx).Create(history)
+		}
+	}
+
+	// Replace the position index so it matches the recalculated order
+	if err := s.rebuildPositionIndex(ctx, locationID, entries); err != nil {
+		log.Printf("Failed to rebuild position index: %v", err)
 	}
+	s.queueChanged(ctx, ids...)
 
 	return nil
 }
 
 // GetConfiguration gets queue configuration
 func (s *QueueService) GetConfiguration(ctx context.Context) (*models.QueueConfiguration, error) {
-	var config models.QueueConfiguration
-	if err := s.db.First(&config).Error; err != nil {
+	locationID := LocationFromContext(ctx)
+	if config, ok := localConfigs.get(locationID, s.clock.Now()); ok {
+		return config, nil
+	}
+
+	generation := localConfigs.currentGeneration()
+	if config, ok := cachedConfiguration(ctx, locationID); ok {
+		localConfigs.set(locationID, config, generation, s.clock.Now())
+		return config, nil
+	}
+
+	loaded, err, _ := cacheFills.Do(configCacheKey(locationID), func() (interface{}, error) {
+		return s.loadConfiguration(context.WithoutCancel(ctx), locationID)
+	})
+	if err != nil {
 		return nil, err
 	}
+
+	// Each caller gets its own copy of the shared result
+	config := *loaded.(*models.QueueConfiguration)
+	localConfigs.set(locationID, &config, generation, s.clock.Now())
 	return &config, nil
 }
 
-// UpdateConfiguration updates queue configuration
+// UpdateConfiguration updates the queue configuration of the request's
+// location, giving the location its own configuration if it used the default's
 func (s *QueueService) UpdateConfiguration(ctx context.Context, config *models.QueueConfiguration, userID string) error {
-	config.UpdatedAt = time.Now().UTC()
+	config.LocationID = LocationFromContext(ctx)
+	config.UpdatedAt = s.clock.Now().UTC()
 	config.UpdatedBy = &userID
+
+	// A deleted configuration is brought back by the update
+	var existing models.QueueConfiguration
+	err := s.db.WithContext(ctx).Unscoped().Select("id").Where("location_id = ?", config.LocationID).First(&existing).Error
+	switch {
+	case err == nil:
+		config.ID = existing.ID
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		config.ID = utils.GenerateUUID()
+	default:
+		return err
+	}
 	
-	if err := s.db.Save(config).Error; err != nil {
+	if err := s.db.WithContext(ctx).Unscoped().Save(config).Error; err != nil {
 		return err
 	}
+
+	invalidated := s.configurationLocations(ctx, config.LocationID)
+	invalidateConfiguration(ctx, invalidated...)
 	
 	// Recalculate all positions with new config
-	go s.RecalculatePositions(ctx)
+	go func() {
+		for _, locationID := range invalidated {
+			s.RecalculatePositions(WithLocation(context.WithoutCancel(ctx), locationID))
+			// A raised MaxConcurrentOrders makes room for deferred entries
+			s.startDeferred(context.WithoutCancel(ctx), locationID)
+		}
+	}()
 	
 	return nil
 }
 
+// configurationLocations lists the locations using a location's
+// configuration: locations without their own configuration use the default's
+func (s *QueueService) configurationLocations(ctx context.Context, locationID string) []string {
+	locations := []string{locationID}
+	if locationID == models.DefaultLocationID {
+		if err := s.db.WithContext(ctx).Model(&models.QueueLocation{}).Pluck("id", &locations).Error; err != nil {
+			log.Printf("Failed to list locations for configuration invalidation: %v", err)
+			locations = []string{locationID}
+		}
+	}
+	return locations
+}
+
 // LogStaffAction logs staff action
 func (s *QueueService) LogStaffAction(ctx context.Context, entryID, staffID, staffName, action string, oldStatus, newStatus, oldPriority, newPriority, reason *string) error {
 	log := &models.StaffQueueActionLog{
@@ -373,19 +716,21 @@ func (s *QueueService) LogStaffAction(ctx context.Context, entryID, staffID, sta
 		QueueEntryID: entryID,
 		StaffID:      staffID,
 		StaffName:    &staffName,
+		ShiftID:      s.activeShiftID(ctx, staffID),
 		Action:       action,
 		OldStatus:    oldStatus,
 		NewStatus:    newStatus,
 		OldPriority:  oldPriority,
 		NewPriority:  newPriority,
 		Reason:       reason,
-		Timestamp:    time.Now().UTC(),
+		Timestamp:    s.clock.Now().UTC(),
 	}
 
-	return s.db.Create(log).Error
+	return s.db.WithContext(ctx).Create(log).Error
 }
 
-// RecordPositionHistory records position change
+// RecordPositionHistory records position change, along with the entry's
+// estimate at the time
 func (s *QueueService) RecordPositionHistory(ctx context.Context, entryID string, oldPos, newPos int, oldStatus, newStatus string, reason *string) error {
 	history := &models.QueuePositionHistory{
 		ID:           utils.GenerateUUID(),
@@ -395,16 +740,29 @@ func (s *QueueService) RecordPositionHistory(ctx context.Context, entryID string
 		OldStatus:    oldStatus,
 		NewStatus:    newStatus,
 		Reason:       reason,
-		Timestamp:    time.Now().UTC(),
+		Timestamp:    s.clock.Now().UTC(),
 	}
 
-	return s.db.Create(history).Error
+	var estimate models.QueueEntry
+	if err := s.db.WithContext(ctx).Select("estimated_wait_time", "estimated_ready_time").
+		Where("id = ?", entryID).First(&estimate).Error; err == nil {
+		history.EstimatedWaitTime = &estimate.EstimatedWaitTime
+		history.EstimatedReadyTime = estimate.EstimatedReadyTime
+	}
+
+	return s.db.WithContext(ctx).Create(history).Error
 }
 
-// GetStaffActionLogs gets staff action logs
+// GetStaffActionLogs gets the staff action logs of an entry in the request's
+// location
 func (s *QueueService) GetStaffActionLogs(ctx context.Context, entryID string) ([]models.StaffQueueActionLog, error) {
+	// Entries of other locations are not found, like their logs
+	if _, err := s.GetQueueEntryByID(ctx, entryID); err != nil {
+		return nil, err
+	}
+
 	var logs []models.StaffQueueActionLog
-	if err := s.db.Where("queue_entry_id = ?", entryID).
+	if err := s.db.WithContext(ctx).Where("queue_entry_id = ?", entryID).
 		Order("timestamp DESC").
 		Find(&logs).Error; err != nil {
 		return nil, err
@@ -412,25 +770,43 @@ func (s *QueueService) GetStaffActionLogs(ctx context.Context, entryID string) (
 	return logs, nil
 }
 
-// GetQueueStatistics gets queue statistics
+// GetQueueStatistics gets the queue statistics of the request's location
 func (s *QueueService) GetQueueStatistics(ctx context.Context, date *time.Time) (*models.QueueStatsResponse, error) {
-	targetDate := time.Now().UTC().Truncate(24 * time.Hour)
+	locationID := LocationFromContext(ctx)
+	today := clock.Date(s.clock.Now())
+	targetDate := today
 	if date != nil {
 		targetDate = date.Truncate(24 * time.Hour)
 	}
 
+	// Serve from the incrementally maintained summary; today's is rebuilt if missing
+	summary, ok := s.getStatsSummary(ctx, locationID, targetDate)
+	if !ok && targetDate.Equal(today) {
+		if rebuilt, err := s.RebuildStatsSummary(ctx, locationID, targetDate); err == nil {
+			summary, ok = rebuilt, true
+		}
+	}
+	if ok {
+		response := statsResponseFromSummary(locationID, targetDate, summary)
+		response.CustomKPIs = s.customKPIsFor(ctx, targetDate)
+		return response, nil
+	}
+
 	var stats models.QueueStatistics
-	if err := s.db.Where("date = ?", targetDate).First(&stats).Error; err != nil {
+	if err := s.db.WithContext(ctx).Where("location_id = ? AND date = ?", locationID, targetDate).First(&stats).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Return empty stats
 			return &models.QueueStatsResponse{
-				Date: targetDate.Format("2006-01-02"),
+				LocationID: locationID,
+				Date:       targetDate.Format("2006-01-02"),
+				CustomKPIs: s.customKPIsFor(ctx, targetDate),
 			}, nil
 		}
 		return nil, err
 	}
 
 	return &models.QueueStatsResponse{
+		LocationID:           stats.LocationID,
 		Date:                 stats.Date.Format("2006-01-02"),
 		TotalInQueue:         stats.TotalInQueue,
 		WaitingCount:         stats.WaitingCount,
@@ -438,47 +814,40 @@ func (s *QueueService) GetQueueStatistics(ctx context.Context, date *time.Time)
 		ReadyCount:           stats.ReadyCount,
 		CompletedToday:       stats.CompletedToday,
 		CancelledToday:       stats.CancelledToday,
+		WalkInsToday:         stats.WalkInsToday,
 		AvgWaitTime:          stats.AvgWaitTime,
 		AvgPreparationTime:   stats.AvgPreparationTime,
+		TimePercentiles:      stats.TimePercentiles,
+		LongestWaitTime:      stats.LongestWaitTime,
+		ShortestWaitTime:     stats.ShortestWaitTime,
 		CurrentLoad:          stats.CurrentLoad,
 		OnTimeCompletionRate: stats.OnTimeCompletionRate,
+		NoShowRate:           stats.NoShowRate,
+		CustomKPIs:           s.customKPIsFor(ctx, targetDate),
 	}, nil
 }
 
-// UpdateStatistics updates daily statistics
+// UpdateStatistics persists today's statistics summary of every active location to MySQL
 func (s *QueueService) UpdateStatistics(ctx context.Context) error {
-	today := time.Now().UTC().Truncate(24 * time.Hour)
-
-	var stats models.QueueStatistics
-	result := s.db.Where("date = ?", today).First(&stats)
-
-	if result.Error != nil {
-		stats = models.QueueStatistics{
-			ID:   utils.GenerateUUID(),
-			Date: today,
-		}
+	locationIDs, err := s.activeLocationIDs(ctx)
+	if err != nil {
+		return err
 	}
 
-	// Count by status
-	s.db.Model(&models.QueueEntry{}).Where("status = ? AND DATE(created_at) = ?", "WAITING", today).Count(&[]int64{int64(stats.WaitingCount)}[0])
-	s.db.Model(&models.QueueEntry{}).Where("status = ? AND DATE(created_at) = ?", "IN_PROGRESS", today).Count(&[]int64{int64(stats.InProgressCount)}[0])
-	s.db.Model(&models.QueueEntry{}).Where("status = ? AND DATE(created_at) = ?", "READY", today).Count(&[]int64{int64(stats.ReadyCount)}[0])
-	s.db.Model(&models.QueueEntry{}).Where("status = ? AND DATE(created_at) = ?", "COMPLETED", today).Count(&[]int64{int64(stats.CompletedToday)}[0])
-	s.db.Model(&models.QueueEntry{}).Where("status = ? AND DATE(created_at) = ?", "CANCELLED", today).Count(&[]int64{int64(stats.CancelledToday)}[0])
-
-	stats.TotalInQueue = stats.WaitingCount + stats.InProgressCount + stats.ReadyCount
-	stats.UpdatedAt = time.Now().UTC()
-
-	if result.Error != nil {
-		return s.db.Create(&stats).Error
+	today := clock.Date(s.clock.Now())
+	var errs []error
+	for _, locationID := range locationIDs {
+		if err := s.flushStatsSummary(ctx, locationID, today); err != nil {
+			errs = append(errs, fmt.Errorf("location %s: %w", locationID, err))
+		}
 	}
-	return s.db.Save(&stats).Error
+	return errors.Join(errs...)
 }
 
 // GetUserQueueEntries gets all queue entries for a user
 func (s *QueueService) GetUserQueueEntries(ctx context.Context, userID string) ([]models.QueueEntry, error) {
 	var entries []models.QueueEntry
-	if err := s.db.Where("user_id = ?", userID).
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).
 		Order("created_at DESC").
 		Find(&entries).Error; err != nil {
 		return nil, err
@@ -486,10 +855,23 @@ func (s *QueueService) GetUserQueueEntries(ctx context.Context, userID string) (
 	return entries, nil
 }
 
-// GetActiveQueueEntries gets all active entries
+// GetActiveQueueEntries gets the active entries of the request's location, from
+// the active-queue snapshot (which holds every location) when possible
 func (s *QueueService) GetActiveQueueEntries(ctx context.Context) ([]models.QueueEntry, error) {
+	entries, ok := s.activeSnapshot(ctx)
+	if !ok {
+		var err error
+		if entries, err = s.fillActiveSnapshot(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return entriesAtLocation(entries, LocationFromContext(ctx)), nil
+}
+
+// loadActiveQueueEntries reads the active entries from MySQL
+func (s *QueueService) loadActiveQueueEntries(ctx context.Context) ([]models.QueueEntry, error) {
 	var entries []models.QueueEntry
-	if err := s.db.Where("status IN ?", []string{"WAITING", "IN_PROGRESS", "READY"}).
+	if err := s.db.WithContext(ctx).Where("status IN ?", []string{"WAITING", "IN_PROGRESS", "READY"}).
 		Order("position ASC").
 		Find(&entries).Error; err != nil {
 		return nil, err
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrStagesNotAllowed is returned when defining stages for an entry that is no longer waiting or in progress
+	ErrStagesNotAllowed = newError(KindInvalid, "STAGES_NOT_ALLOWED", "stages can only be defined for waiting or in-progress entries")
+	// ErrStagesStarted is returned when redefining stages after one of them has started
+	ErrStagesStarted = newError(KindConflict, "STAGES_STARTED", "stages already in progress")
+	// ErrInvalidStageStatus is returned for a stage status that doesn't exist
+	ErrInvalidStageStatus = newError(KindInvalid, "INVALID_STAGE_STATUS", "invalid stage status")
+	// ErrInvalidStageTransition is returned when a stage would move backwards
+	ErrInvalidStageTransition = newError(KindInvalid, "INVALID_STAGE_TRANSITION", "stages can't move back to an earlier status")
+	// ErrStageFinished is returned when changing a stage that was already completed or cancelled
+	ErrStageFinished = newError(KindConflict, "STAGE_FINISHED", "stage is already completed or cancelled")
+)
+
+// stageStatusOrder defines allowed forward transitions for stages
+var stageStatusOrder = map[string]int{
+	"WAITING":     0,
+	"IN_PROGRESS": 1,
+	"READY":       2,
+	"COMPLETED":   3,
+}
+
+// DefineStages splits a queue entry into staged sub-entries sharing its token
+func (s *QueueService) DefineStages(ctx context.Context, entryID string, stages []models.CreateQueueStageRequest) ([]models.QueueEntryStage, error) {
+	entry, err := s.GetQueueEntryByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status != "WAITING" && entry.Status != "IN_PROGRESS" {
+		return nil, fmt.Errorf("%w: entry is %s", ErrStagesNotAllowed, entry.Status)
+	}
+
+	created := s.newStages(entry, stages)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Replace any previously defined stages that haven't started
+		var started int64
+		if err := tx.Model(&models.QueueEntryStage{}).
+			Where("queue_entry_id = ? AND status <> ?", entry.ID, "WAITING").
+			Count(&started).Error; err != nil {
+			return err
+		}
+		if started > 0 {
+			return ErrStagesStarted
+		}
+
+		if err := tx.Where("queue_entry_id = ?", entry.ID).Delete(&models.QueueEntryStage{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&created).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// newStages builds the stages of an entry, each becoming ready relative to
+// the previous one
+func (s *QueueService) newStages(entry *models.QueueEntry, stages []models.CreateQueueStageRequest) []models.QueueEntryStage {
+	now := s.clock.Now().UTC()
+	readyTime := now
+	if entry.EstimatedReadyTime != nil {
+		readyTime = *entry.EstimatedReadyTime
+	}
+
+	created := make([]models.QueueEntryStage, 0, len(stages))
+	for i, req := range stages {
+		readyTime = readyTime.Add(time.Duration(req.ReadyAfterMinutes) * time.Minute)
+		created = append(created, models.QueueEntryStage{
+			ID:                 utils.GenerateUUID(),
+			QueueEntryID:       entry.ID,
+			TokenNumber:        entry.TokenNumber,
+			Sequence:           i + 1,
+			Name:               req.Name,
+			ItemCount:          req.ItemCount,
+			Status:             "WAITING",
+			ReadyAfterMinutes:  req.ReadyAfterMinutes,
+			EstimatedReadyTime: utils.TimePtr(readyTime),
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		})
+	}
+	return created
+}
+
+// GetEntryStages gets the stages of a queue entry
+func (s *QueueService) GetEntryStages(ctx context.Context, entryID string) (*models.QueueStagesResponse, error) {
+	entry, err := s.GetQueueEntryByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildStagesResponse(ctx, entry)
+}
+
+// GetStagesByToken gets the stages of a queue entry by token number
+func (s *QueueService) GetStagesByToken(ctx context.Context, token string) (*models.QueueStagesResponse, error) {
+	entry, err := s.GetQueueEntryByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildStagesResponse(ctx, entry)
+}
+
+func (s *QueueService) buildStagesResponse(ctx context.Context, entry *models.QueueEntry) (*models.QueueStagesResponse, error) {
+	var stages []models.QueueEntryStage
+	if err := s.db.WithContext(ctx).Where("queue_entry_id = ?", entry.ID).
+		Order("sequence ASC").
+		Find(&stages).Error; err != nil {
+		return nil, err
+	}
+
+	ready, remaining := countProgress(stageStatuses(stages))
+	return &models.QueueStagesResponse{
+		QueueEntryID:    entry.ID,
+		TokenNumber:     entry.TokenNumber,
+		Stages:          stages,
+		ReadyStages:     ready,
+		RemainingStages: remaining,
+	}, nil
+}
+
+// UpdateStageStatus updates a stage status and rolls the result up to the parent entry
+func (s *QueueService) UpdateStageStatus(ctx context.Context, entryID, stageID string, req *models.UpdateQueueStageStatusRequest, staffID string, staffName string) (*models.QueueEntryStage, error) {
+	entry, err := s.GetQueueEntryByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	var stage models.QueueEntryStage
+	if err := s.db.WithContext(ctx).Where("id = ? AND queue_entry_id = ?", stageID, entryID).First(&stage).Error; err != nil {
+		return nil, err
+	}
+
+	// Completed and cancelled stages are final
+	if stage.Status == "COMPLETED" || stage.Status == "CANCELLED" {
+		return nil, fmt.Errorf("%w: stage is %s", ErrStageFinished, stage.Status)
+	}
+	if req.Status != "CANCELLED" {
+		newOrder, ok := stageStatusOrder[req.Status]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidStageStatus, req.Status)
+		}
+		if oldOrder, ok := stageStatusOrder[stage.Status]; !ok || newOrder < oldOrder {
+			return nil, fmt.Errorf("%w: %s to %s", ErrInvalidStageTransition, stage.Status, req.Status)
+		}
+	}
+
+	now := s.clock.Now().UTC()
+	updates := map[string]interface{}{
+		"status":     req.Status,
+		"updated_at": now,
+	}
+	switch req.Status {
+	case "IN_PROGRESS":
+		if stage.ActualStartTime == nil {
+			updates["actual_start_time"] = now
+		}
+	case "READY":
+		if stage.ActualReadyTime == nil {
+			updates["actual_ready_time"] = now
+		}
+	case "COMPLETED":
+		if stage.ActualCompletionTime == nil {
+			updates["actual_completion_time"] = now
+		}
+	}
+
+	if err := s.db.WithContext(ctx).Model(&stage).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	stage.Status = req.Status
+
+	var stages []models.QueueEntryStage
+	if err := s.db.WithContext(ctx).Where("queue_entry_id = ?", entryID).Find(&stages).Error; err != nil {
+		return nil, err
+	}
+	_, remaining := countProgress(stageStatuses(stages))
+
+	// Per-stage notification so the customer can collect this course now
+	if req.Status == "READY" && s.publisher != nil {
+		if err := s.publisher.PublishQueueStageReady(ctx, entry, &stage, remaining); err != nil {
+			log.Printf("Failed to publish stage ready event: %v", err)
+		}
+	}
+
+	// Roll stage progress up to the parent entry
+	if parentStatus := rollupProgress(entry.Status, stageStatuses(stages)); parentStatus != "" {
+		reason := fmt.Sprintf("Stage %d (%s) %s", stage.Sequence, stage.Name, req.Status)
+		parentReq := &models.UpdateQueueStatusRequest{
+			Status:      parentStatus,
+			Reason:      &reason,
+			DeferIfFull: true,
+		}
+		if err := s.UpdateQueueStatus(ctx, entryID, parentReq, staffID, staffName); err != nil {
+			return nil, err
+		}
+	}
+
+	utils.InvalidateQueueCache(ctx, entryID)
+
+	return &stage, nil
+}
+
+// stageStatuses lists the status of each stage
+func stageStatuses(stages []models.QueueEntryStage) []string {
+	statuses := make([]string, len(stages))
+	for i, stage := range stages {
+		statuses[i] = stage.Status
+	}
+	return statuses
+}
+
+// countProgress returns how many of the parts (stages or linked orders) are
+// ready and how many are still pending
+func countProgress(statuses []string) (int, int) {
+	ready, remaining := 0, 0
+	for _, status := range statuses {
+		switch status {
+		case "READY", "COMPLETED":
+			ready++
+		case "WAITING", "IN_PROGRESS":
+			remaining++
+		}
+	}
+	return ready, remaining
+}
+
+// rollupProgress determines the parent status implied by the status of its
+// parts (stages or linked orders), returning an empty string when the parent
+// should not change
+func rollupProgress(current string, statuses []string) string {
+	active, started, ready, completed := 0, 0, 0, 0
+	for _, status := range statuses {
+		if status == "CANCELLED" {
+			continue
+		}
+		active++
+		switch status {
+		case "IN_PROGRESS":
+			started++
+		case "READY":
+			started++
+			ready++
+		case "COMPLETED":
+			started++
+			ready++
+			completed++
+		}
+	}
+
+	if active == 0 {
+		return ""
+	}
+
+	var target string
+	switch {
+	case completed == active:
+		target = "COMPLETED"
+	case ready == active:
+		target = "READY"
+	case started > 0:
+		target = "IN_PROGRESS"
+	default:
+		return ""
+	}
+
+	if target == current {
+		return ""
+	}
+	return target
+}
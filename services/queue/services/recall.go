@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gin-quickstart/models"
+	"gin-quickstart/notify"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotRecallable is returned when recalling an entry that isn't ready
+var ErrNotRecallable = newError(KindConflict, "NOT_RECALLABLE", "only ready entries can be recalled")
+
+// RecallEntry calls a ready token again when its customer hasn't collected
+// it. The display receives the entry with its new recall count and time, to
+// flash the token, and the customer gets the ready event again plus a
+// reminder over their direct channels.
+func (s *QueueService) RecallEntry(ctx context.Context, entryID, staffID, staffName string) (*models.QueueEntry, error) {
+	var entry models.QueueEntry
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", entryID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	ctx = WithLocation(ctx, entry.LocationID)
+
+	if entry.Status != "READY" {
+		return nil, ErrNotRecallable
+	}
+
+	// Guard on the status so a recall racing a pickup doesn't count
+	now := s.clock.Now().UTC()
+	result := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("id = ? AND status = ?", entry.ID, "READY").
+		Updates(map[string]interface{}{
+			"recall_count":     gorm.Expr("recall_count + 1"),
+			"last_recalled_at": now,
+			"updated_at":       now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotRecallable
+	}
+	utils.InvalidateQueueCache(ctx, entry.ID)
+	s.queueChanged(ctx, entry.ID)
+
+	recalled, err := s.GetQueueEntryByID(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	note := fmt.Sprintf("Recall #%d", recalled.RecallCount)
+	s.LogStaffAction(ctx, entry.ID, staffID, staffName, "RECALL", nil, nil, nil, nil, &note)
+
+	if s.publisher != nil {
+		if err := s.renotifyEntry(ctx, recalled); err != nil {
+			log.Printf("Failed to publish recall of %s: %v", recalled.TokenNumber, err)
+		}
+	}
+	s.sendCustomerNotification(ctx, entry.ID, notify.TypeReminder, false)
+
+	return recalled, nil
+}
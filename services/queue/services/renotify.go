@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+)
+
+const (
+	defaultRenotifyBatchSize     = 20
+	maxRenotifyBatchSize         = 100
+	defaultRenotifyBatchInterval = 1000 // milliseconds
+)
+
+var (
+	// ErrRenotifyUnavailable is returned when notifications can't be sent
+	ErrRenotifyUnavailable = newError(KindUnavailable, "RENOTIFY_UNAVAILABLE", "notification publisher not available")
+	// ErrInvalidRenotifyStatus is returned for a status whose entries can't be renotified
+	ErrInvalidRenotifyStatus = newError(KindInvalid, "INVALID_RENOTIFY_STATUS", "only ready, in-progress and waiting entries can be renotified")
+)
+
+// renotifyTypes maps entry status to the notification that gets re-sent
+var renotifyTypes = map[string]string{
+	"READY":       "READY",
+	"IN_PROGRESS": "ALMOST_READY",
+	"WAITING":     "POSITION_UPDATE",
+}
+
+// RenotifyEntries re-sends notifications to entries matching the status filters.
+// Notifications go out in rate-limited batches in the background.
+func (s *QueueService) RenotifyEntries(ctx context.Context, req *models.RenotifyRequest) (*models.RenotifyResponse, error) {
+	if s.publisher == nil {
+		return nil, ErrRenotifyUnavailable
+	}
+
+	statuses := req.Statuses
+	if len(statuses) == 0 {
+		statuses = []string{"READY"}
+	}
+	for _, status := range statuses {
+		if _, ok := renotifyTypes[status]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRenotifyStatus, status)
+		}
+	}
+
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultRenotifyBatchSize
+	}
+	if batchSize > maxRenotifyBatchSize {
+		batchSize = maxRenotifyBatchSize
+	}
+
+	interval := req.BatchIntervalMs
+	if interval <= 0 {
+		interval = defaultRenotifyBatchInterval
+	}
+
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).Where("location_id = ? AND status IN ?", LocationFromContext(ctx), statuses).
+		Order("position ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	batches := (len(entries) + batchSize - 1) / batchSize
+
+	go s.sendRenotifyBatches(context.WithoutCancel(ctx), entries, batchSize, time.Duration(interval)*time.Millisecond)
+
+	return &models.RenotifyResponse{
+		Matched:         len(entries),
+		Batches:         batches,
+		BatchSize:       batchSize,
+		BatchIntervalMs: interval,
+		Statuses:        statuses,
+	}, nil
+}
+
+func (s *QueueService) sendRenotifyBatches(ctx context.Context, entries []models.QueueEntry, batchSize int, interval time.Duration) {
+	sent, failed := 0, 0
+	for start := 0; start < len(entries); start += batchSize {
+		if start > 0 {
+			time.Sleep(interval)
+		}
+
+		end := start + batchSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+
+		for i := start; i < end; i++ {
+			if err := s.renotifyEntry(ctx, &entries[i]); err != nil {
+				log.Printf("Failed to renotify token=%s: %v", entries[i].TokenNumber, err)
+				failed++
+				continue
+			}
+			sent++
+		}
+	}
+
+	log.Printf("Bulk renotify finished: sent=%d, failed=%d", sent, failed)
+}
+
+func (s *QueueService) renotifyEntry(ctx context.Context, entry *models.QueueEntry) error {
+	notificationType := renotifyTypes[entry.Status]
+
+	var err error
+	switch notificationType {
+	case "READY":
+		err = s.publisher.PublishQueueReady(ctx, entry)
+	case "ALMOST_READY":
+		err = s.publisher.PublishQueueAlmostReady(ctx, entry)
+	default:
+		err = s.publisher.PublishQueuePositionUpdate(ctx, entry)
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Create(&models.QueueNotificationSent{
+		ID:               utils.GenerateUUID(),
+		QueueEntryID:     entry.ID,
+		NotificationType: notificationType,
+		Channel:          "IN_APP",
+		SentAt:           s.clock.Now().UTC(),
+	}).Error
+}
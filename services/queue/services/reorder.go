@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidReorder is returned when a reorder doesn't list the waiting
+// entries of one lane in an order the queue can keep
+var ErrInvalidReorder = newError(KindInvalid, "INVALID_REORDER", "invalid reorder")
+
+// ReorderQueue puts the waiting entries of one lane at the request's location
+// in the given order, front first. The list must hold every waiting entry of
+// the lane once, and keep higher priorities ahead of lower ones (priority
+// changes move entries across priorities). Returns the lane's waiting entries
+// in their new order.
+func (s *QueueService) ReorderQueue(ctx context.Context, req *models.ReorderQueueRequest, staffID, staffName string) ([]models.QueueEntry, error) {
+	locationID := LocationFromContext(ctx)
+	ctx = WithLocation(ctx, locationID)
+
+	rank := make(map[string]int, len(req.EntryIDs))
+	for i, id := range req.EntryIDs {
+		if _, listed := rank[id]; listed {
+			return nil, fmt.Errorf("%w: entry %s is listed more than once", ErrInvalidReorder, id)
+		}
+		rank[id] = i
+	}
+
+	var isExpress bool
+	oldPositions := make(map[string]int, len(req.EntryIDs))
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var entries []models.QueueEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND location_id = ?", req.EntryIDs, locationID).
+			Find(&entries).Error; err != nil {
+			return err
+		}
+		if len(entries) != len(req.EntryIDs) {
+			return fmt.Errorf("%w: some entries aren't in this location's queue", ErrInvalidReorder)
+		}
+
+		isExpress = entries[0].IsExpressQueue
+		positions := make([]int, len(entries))
+		for i, entry := range entries {
+			if entry.Status != "WAITING" {
+				return fmt.Errorf("%w: %s is %s, not waiting", ErrInvalidReorder, entry.TokenNumber, humanizeStatus(entry.Status))
+			}
+			if entry.IsExpressQueue != isExpress {
+				return fmt.Errorf("%w: entries of the regular and express lanes can't be mixed", ErrInvalidReorder)
+			}
+			positions[i] = entry.Position
+		}
+
+		var waiting int64
+		if err := tx.Model(&models.QueueEntry{}).
+			Where("location_id = ? AND is_express_queue = ? AND status = ?", locationID, isExpress, "WAITING").
+			Count(&waiting).Error; err != nil {
+			return err
+		}
+		if int(waiting) != len(entries) {
+			return fmt.Errorf("%w: list all %d waiting entries of the %s lane", ErrInvalidReorder, waiting, humanizeStatus(laneName(isExpress)))
+		}
+
+		slices.SortFunc(entries, func(a, b models.QueueEntry) int {
+			return rank[a.ID] - rank[b.ID]
+		})
+		for i := 1; i < len(entries); i++ {
+			if priorityRank[entries[i].Priority] > priorityRank[entries[i-1].Priority] {
+				return fmt.Errorf("%w: %s has %s priority and can't go behind %s; change its priority instead",
+					ErrInvalidReorder, entries[i].TokenNumber, humanizeStatus(entries[i].Priority), entries[i-1].TokenNumber)
+			}
+		}
+
+		// The lane's waiting positions, handed out in the new order
+		slices.Sort(positions)
+		now := s.clock.Now().UTC()
+		for i, entry := range entries {
+			oldPositions[entry.ID] = entry.Position
+			if entry.Position == positions[i] {
+				continue
+			}
+			if err := tx.Model(&models.QueueEntry{}).Where("id = ?", entry.ID).Updates(map[string]interface{}{
+				"position":   positions[i],
+				"updated_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Renumber and re-estimate the location's queue, which keeps the new order
+	if err := s.RecalculatePositions(ctx); err != nil {
+		return nil, err
+	}
+
+	var lane []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Where("location_id = ? AND is_express_queue = ? AND status = ?", locationID, isExpress, "WAITING").
+		Order("position ASC").
+		Find(&lane).Error; err != nil {
+		return nil, err
+	}
+
+	reason := req.Reason
+	if reason == nil {
+		reason = utils.StringPtr("Reordered by staff")
+	}
+	for i := range lane {
+		entry := &lane[i]
+		oldPosition, listed := oldPositions[entry.ID]
+		if !listed || oldPosition == entry.Position {
+			continue
+		}
+
+		s.RecordPositionHistory(ctx, entry.ID, oldPosition, entry.Position, "WAITING", "WAITING", reason)
+		s.LogStaffAction(ctx, entry.ID, staffID, staffName, "REORDER", nil, nil, nil, nil, reason)
+		if s.publisher != nil {
+			if err := s.publisher.PublishQueuePositionUpdate(ctx, entry); err != nil {
+				log.Printf("Failed to publish reorder of %s: %v", entry.TokenNumber, err)
+			}
+		}
+	}
+
+	return lane, nil
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"gin-quickstart/models"
+)
+
+// Replay errors
+var (
+	ErrReplayUnavailable = newError(KindUnavailable, "REPLAY_UNAVAILABLE", "event replay is not available")
+	ErrInvalidReplay     = newError(KindInvalid, "INVALID_REPLAY", "invalid replay request")
+)
+
+// EventReplayer replays consumed events through the queue handlers
+type EventReplayer interface {
+	Replay(ctx context.Context, req *models.ReplayEventsRequest) (*models.ReplayReport, error)
+}
+
+var eventReplayer EventReplayer
+
+// SetEventReplayer sets the replayer used by the admin replay endpoint
+func SetEventReplayer(replayer EventReplayer) {
+	eventReplayer = replayer
+}
+
+// ReplayEvents replays a Kafka offset range, in dry-run mode unless req.Apply is set
+func (s *QueueService) ReplayEvents(ctx context.Context, req *models.ReplayEventsRequest) (*models.ReplayReport, error) {
+	if eventReplayer == nil {
+		return nil, ErrReplayUnavailable
+	}
+	return eventReplayer.Replay(ctx, req)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+)
+
+// ErrNotRequeueable is returned when requeueing an entry that isn't NO_SHOW or EXPIRED
+var ErrNotRequeueable = newError(KindConflict, "NOT_REQUEUEABLE", "only no-show or expired entries can be requeued")
+
+// RequeueEntry reinstates a NO_SHOW or EXPIRED entry whose customer came back.
+// It keeps its token and goes back to WAITING, at the back of its lane or at
+// the front of its priority, as the request or the location's configuration
+// says.
+func (s *QueueService) RequeueEntry(ctx context.Context, entryID string, req *models.RequeueRequest, staffID, staffName string) (*models.QueueEntry, error) {
+	var entry models.QueueEntry
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", entryID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	ctx = WithLocation(ctx, entry.LocationID)
+
+	if entry.Status != "NO_SHOW" && entry.Status != "EXPIRED" {
+		return nil, ErrNotRequeueable
+	}
+	oldStatus := entry.Status
+	oldPosition := entry.Position
+
+	config, err := s.GetConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	placement := req.Placement
+	if placement == "" {
+		placement = config.RequeuePlacement
+	}
+
+	// Position 0 sorts first within the entry's priority on recalculation
+	position := 0
+	if placement != models.RequeuePriority {
+		placement = models.RequeueBack
+		position = s.nextLanePosition(ctx, entry.LocationID, entry.IsExpressQueue)
+	}
+
+	// Guard on the status so a concurrent requeue only applies once
+	now := s.clock.Now().UTC()
+	result := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("id = ? AND status = ?", entry.ID, oldStatus).
+		Updates(map[string]interface{}{
+			"status":                 "WAITING",
+			"position":               position,
+			"actual_start_time":      nil,
+			"actual_ready_time":      nil,
+			"actual_completion_time": nil,
+			"start_deferred_at":      nil,
+			"updated_at":             now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotRequeueable
+	}
+
+	reason := req.Reason
+	if reason == nil {
+		reason = utils.StringPtr(fmt.Sprintf("Requeued from %s (%s)", humanizeStatus(oldStatus), humanizeStatus(placement)))
+	}
+	s.LogStaffAction(ctx, entry.ID, staffID, staffName, "REQUEUE", &oldStatus, utils.StringPtr("WAITING"), nil, nil, reason)
+
+	entry.Status = "WAITING"
+	entry.Position = position
+	entry.ActualStartTime = nil
+	entry.ActualReadyTime = nil
+	entry.ActualCompletionTime = nil
+	entry.StartDeferredAt = nil
+	utils.InvalidateQueueCache(ctx, entry.ID)
+	s.recordStatusTransition(ctx, &entry, oldStatus, "WAITING")
+
+	// Number the lane and estimate the entry's wait from its new place
+	if err := s.RecalculatePositions(ctx); err != nil {
+		return nil, err
+	}
+	requeued, err := s.GetQueueEntryByID(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.RecordPositionHistory(ctx, entry.ID, oldPosition, requeued.Position, oldStatus, "WAITING", reason)
+	s.emitWebhookEvent(ctx, WebhookEventStatusChanged, entry.ID, oldStatus)
+
+	// Tell the customer their new place; the confirmation was sent when they first queued
+	if s.publisher != nil {
+		if err := s.publisher.PublishQueuePositionUpdate(ctx, requeued); err != nil {
+			log.Printf("Failed to publish requeue of %s: %v", requeued.TokenNumber, err)
+		}
+	}
+
+	return requeued, nil
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/database"
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+// rolloverStatuses are left over at closing time and expire with the day.
+// Scheduled entries only expire when their pickup time has passed.
+var rolloverStatuses = []string{"PENDING_PAYMENT", "WAITING", "IN_PROGRESS", "READY"}
+
+// closingTime returns when a location's business day of date closes, from
+// the working hours of its configuration, which are in the restaurant's
+// time zone. A day the location is closed on closes as it starts. Closing
+// times at or before opening time are after midnight. It reports false when
+// the location has no hours for the day.
+func (s *QueueService) closingTime(ctx context.Context, locationID string, date time.Time) (time.Time, bool, error) {
+	config, err := s.GetConfiguration(WithLocation(ctx, locationID))
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	var hours models.QueueWorkingHours
+	err = s.db.WithContext(ctx).
+		Where("configuration_id = ? AND day = ?", config.ID, strings.ToUpper(date.Weekday().String())).
+		First(&hours).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if !hours.IsOpen {
+		return clock.DayStart(date), true, nil
+	}
+
+	open, err := time.Parse("15:04", hours.OpenTime)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid open time %q on %s: %w", hours.OpenTime, hours.Day, err)
+	}
+	close, err := time.Parse("15:04", hours.CloseTime)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid close time %q on %s: %w", hours.CloseTime, hours.Day, err)
+	}
+
+	year, month, day := date.Date()
+	if !close.After(open) {
+		day++
+	}
+	return time.Date(year, month, day, close.Hour(), close.Minute(), 0, 0, clock.Zone()).UTC(), true, nil
+}
+
+// RollOverDueDays rolls over every active location whose business day
+// (yesterday's or today's) closed more than grace ago and wasn't rolled
+// over yet
+func (s *QueueService) RollOverDueDays(ctx context.Context, grace time.Duration, rotatePrefix bool) error {
+	locationIDs, err := s.activeLocationIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := s.clock.Now().UTC()
+	today := clock.Date(now)
+
+	var errs []error
+	for _, locationID := range locationIDs {
+		for _, date := range []time.Time{today.AddDate(0, 0, -1), today} {
+			closesAt, ok, err := s.closingTime(ctx, locationID, date)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("location %s: %w", locationID, err))
+				break
+			}
+			if !ok || now.Before(closesAt.Add(grace)) {
+				continue
+			}
+
+			var done int64
+			if err := s.db.WithContext(ctx).Model(&models.QueueDayRollover{}).
+				Where("location_id = ? AND business_date = ?", locationID, date).
+				Count(&done).Error; err != nil {
+				errs = append(errs, fmt.Errorf("location %s: %w", locationID, err))
+				break
+			}
+			if done > 0 {
+				continue
+			}
+
+			rollover, err := s.RollOverDay(ctx, locationID, date, closesAt, rotatePrefix)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("location %s, %s: %w", locationID, date.Format("2006-01-02"), err))
+				continue
+			}
+			if rollover != nil {
+				log.Printf("Rolled over %s of location %s: %d entries expired, %d tokens issued, next prefix %s",
+					date.Format("2006-01-02"), locationID, rollover.ExpiredEntries, rollover.TokensIssued, rollover.NextTokenPrefix)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RollOverDay closes a location's business day: entries of the day (or
+// earlier) still open, and scheduled ones due by closesAt, expire, the
+// day's statistics are finalized, the token counter is persisted (and its
+// prefix rotated when rotatePrefix is set) and queue.day.closed is
+// published. It returns nil when another instance already rolled the day
+// over.
+func (s *QueueService) RollOverDay(ctx context.Context, locationID string, date, closesAt time.Time, rotatePrefix bool) (*models.QueueDayRollover, error) {
+	ctx = WithLocation(ctx, locationID)
+	date = date.UTC().Truncate(24 * time.Hour)
+
+	var location models.QueueLocation
+	if err := s.db.WithContext(ctx).Where("id = ?", locationID).First(&location).Error; err != nil {
+		return nil, err
+	}
+
+	// Claim the day, expire its leftovers and count its tokens together, so
+	// that a failure leaves the day to be rolled over again
+	rollover := &models.QueueDayRollover{
+		ID:              utils.GenerateUUID(),
+		LocationID:      locationID,
+		BusinessDate:    date,
+		ClosedAt:        s.clock.Now().UTC(),
+		TokenPrefix:     location.TokenPrefix,
+		NextTokenPrefix: location.TokenPrefix,
+	}
+	dayStart, dayEnd := clock.DayBounds(date)
+	var leftovers []models.QueueEntry
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rollover).Error; err != nil {
+			return err
+		}
+
+		// Entries of later days (rolling yesterday over after midnight) stay
+		// in line; those of earlier days that were never rolled over go too
+		if err := tx.
+			Where("location_id = ? AND created_at < ? AND (status IN ? OR (status = ? AND requested_pickup_time < ?))",
+				locationID, dayEnd, rolloverStatuses, "SCHEDULED", closesAt).
+			Order("created_at ASC").
+			Find(&leftovers).Error; err != nil {
+			return err
+		}
+		if err := s.updateClosedOut(tx, leftovers, "EXPIRED"); err != nil {
+			return err
+		}
+
+		var issued int64
+		if err := tx.Unscoped().Model(&models.QueueEntry{}).
+			Where("location_id = ? AND created_at >= ? AND created_at < ?", locationID, dayStart, dayEnd).
+			Count(&issued).Error; err != nil {
+			return err
+		}
+		rollover.ExpiredEntries = len(leftovers)
+		rollover.TokensIssued = int(issued)
+		return tx.Model(rollover).Updates(map[string]interface{}{
+			"expired_entries": rollover.ExpiredEntries,
+			"tokens_issued":   rollover.TokensIssued,
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := s.afterCloseOut(ctx, leftovers, "EXPIRED", "MARK_EXPIRED", "system", "System", utils.StringPtr("Expired at end of day"))
+	s.queueChanged(ctx, ids...)
+	// Renumber the entries of later days still in line
+	if err := s.RecalculatePositions(ctx); err != nil {
+		log.Printf("Failed to recalculate positions of location %s: %v", locationID, err)
+	}
+
+	// The day's statistics, now that every entry of it has left the queue
+	summary, err := s.RebuildStatsSummary(ctx, locationID, date)
+	if err == nil {
+		err = s.persistStatsSummary(ctx, locationID, date, summary)
+	}
+	if err != nil {
+		log.Printf("Failed to finalize statistics of %s for location %s: %v", date.Format("2006-01-02"), locationID, err)
+	}
+
+	if database.GetRedis() != nil {
+		if err := s.persistTokenCounter(ctx, &location); err != nil {
+			log.Printf("Failed to persist token counter of location %s: %v", locationID, err)
+		}
+	}
+	if rotatePrefix {
+		if err := s.rotateTokenPrefix(ctx, &location); err != nil {
+			log.Printf("Failed to rotate token prefix of location %s: %v", locationID, err)
+		}
+		rollover.NextTokenPrefix = location.TokenPrefix
+		if err := s.db.WithContext(ctx).Model(rollover).Update("next_token_prefix", rollover.NextTokenPrefix).Error; err != nil {
+			log.Printf("Failed to record next token prefix of location %s: %v", locationID, err)
+		}
+	}
+
+	if s.publisher != nil {
+		if err := s.publisher.PublishDayClosed(ctx, rollover); err != nil {
+			log.Printf("Failed to publish day closed for location %s: %v", locationID, err)
+		}
+	}
+	return rollover, nil
+}
+
+// rotateTokenPrefix moves a location on to the next free prefix, advancing
+// its last letter (A -> B, ..., Z -> A), and restarts the numbering under it
+func (s *QueueService) rotateTokenPrefix(ctx context.Context, location *models.QueueLocation) error {
+	prefix := location.TokenPrefix
+	for range 25 {
+		prefix = nextTokenPrefix(prefix)
+		err := s.resetTokenCounter(ctx, location, prefix)
+		if errors.Is(err, ErrTokenNumbersTaken) || errors.Is(err, ErrLocationExists) {
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("%w after %s", ErrNoFreeTokenPrefix, location.TokenPrefix)
+}
+
+// nextTokenPrefix advances the last letter of prefix, wrapping Z to A
+func nextTokenPrefix(prefix string) string {
+	if prefix == "" {
+		return "A"
+	}
+	last := prefix[len(prefix)-1]
+	next := byte('A')
+	if last >= 'A' && last < 'Z' {
+		next = last + 1
+	}
+	return prefix[:len(prefix)-1] + string(next)
+}
+
+// StartDayRollover checks every interval for locations whose business day
+// is due to roll over, until ctx is cancelled
+func (s *QueueService) StartDayRollover(ctx context.Context, interval, grace time.Duration, rotatePrefix bool) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			if err := s.RollOverDueDays(ctx, grace, rotatePrefix); err != nil {
+				log.Printf("Failed to roll over business days: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
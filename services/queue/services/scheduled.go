@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+// scheduledActivation is when an entry to be picked up at pickupTime joins
+// the queue: its preparation and the buffer ahead of the pickup
+func scheduledActivation(pickupTime time.Time, prepMinutes, bufferMinutes int) time.Time {
+	return pickupTime.Add(-time.Duration(prepMinutes+bufferMinutes) * time.Minute)
+}
+
+// GetScheduledEntries lists the request location's entries waiting for their
+// scheduled pickup, soonest to join the queue first
+func (s *QueueService) GetScheduledEntries(ctx context.Context) ([]models.QueueEntry, error) {
+	entries := []models.QueueEntry{}
+	if err := s.db.WithContext(ctx).
+		Where("location_id = ? AND status = ?", LocationFromContext(ctx), "SCHEDULED").
+		Order("scheduled_activate_at ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ActivateScheduledEntries moves scheduled entries whose preparation is due
+// into the active queue
+func (s *QueueService) ActivateScheduledEntries(ctx context.Context) (int, error) {
+	var due []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND scheduled_activate_at <= ?", "SCHEDULED", s.clock.Now().UTC()).
+		Order("scheduled_activate_at ASC").
+		Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	activated := 0
+	var errs []error
+	for i := range due {
+		ok, err := s.activateScheduled(ctx, &due[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", due[i].ID, err))
+			continue
+		}
+		if ok {
+			activated++
+		}
+	}
+	return activated, errors.Join(errs...)
+}
+
+// activateScheduled puts a due entry in its lane where it can still be ready
+// by the requested pickup time: at the back when there is time, further
+// forward when the queue has grown since it was scheduled. Priorities still
+// come first. It reports false when the entry was no longer scheduled.
+func (s *QueueService) activateScheduled(ctx context.Context, entry *models.QueueEntry) (bool, error) {
+	ctx = WithLocation(ctx, entry.LocationID)
+
+	config, err := s.GetConfiguration(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	now := s.clock.Now()
+	minutesLeft := 0
+	if entry.RequestedPickupTime != nil {
+		minutesLeft = int(entry.RequestedPickupTime.Sub(now).Minutes())
+	}
+	otherLength := s.otherLaneLength(ctx, entry.LocationID, entry.IsExpressQueue)
+	position := s.nextLanePosition(ctx, entry.LocationID, entry.IsExpressQueue)
+	estimator := s.newWaitEstimator(ctx, config)
+	for position > 1 && estimator.laneWait(entry.IsExpressQueue, position, otherLength, config.AvgPreparationTimePerItem, entry.ItemCount) > minutesLeft {
+		position--
+	}
+
+	activated := false
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Guard on the status so a concurrent cancellation wins
+		result := tx.Model(&models.QueueEntry{}).
+			Where("id = ? AND status = ?", entry.ID, "SCHEDULED").
+			Updates(map[string]interface{}{
+				"status":     "WAITING",
+				"position":   position,
+				"updated_at": now.UTC(),
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		activated = true
+
+		// Make room for the entry
+		return tx.Model(&models.QueueEntry{}).
+			Where("location_id = ? AND is_express_queue = ? AND status = ? AND position >= ? AND id <> ?",
+				entry.LocationID, entry.IsExpressQueue, "WAITING", position, entry.ID).
+			Updates(map[string]interface{}{
+				"position":   gorm.Expr("position + 1"),
+				"updated_at": now.UTC(),
+			}).Error
+	})
+	if err != nil || !activated {
+		return false, err
+	}
+
+	// Number the lane and estimate the entry's wait from its place
+	if err := s.RecalculatePositions(ctx); err != nil {
+		return false, err
+	}
+	active, err := s.GetQueueEntryByID(ctx, entry.ID)
+	if err != nil {
+		return false, err
+	}
+
+	reason := "Preparation for the scheduled pickup is due"
+	s.RecordPositionHistory(ctx, entry.ID, 0, active.Position, "SCHEDULED", "WAITING", &reason)
+	utils.InvalidateQueueCache(ctx, entry.ID)
+	s.recordStatusTransition(ctx, active, "SCHEDULED", "WAITING")
+	s.notifyCustomer(ctx, entry.ID, active.Status)
+	s.emitWebhookEvent(ctx, WebhookEventStatusChanged, entry.ID, "SCHEDULED")
+
+	if s.publisher != nil {
+		if err := s.publisher.PublishQueuePositionUpdate(ctx, active); err != nil {
+			log.Printf("Failed to publish activation of %s: %v", active.TokenNumber, err)
+		}
+	}
+
+	return true, nil
+}
+
+// schedulePaidEntry moves a paid PENDING_PAYMENT entry for later to SCHEDULED
+func (s *QueueService) schedulePaidEntry(ctx context.Context, entry *models.QueueEntry) (*models.QueueEntry, bool, error) {
+	result := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("id = ? AND status = ?", entry.ID, "PENDING_PAYMENT").
+		Updates(map[string]interface{}{
+			"status":               "SCHEDULED",
+			"estimated_ready_time": entry.RequestedPickupTime,
+			"updated_at":           s.clock.Now().UTC(),
+		})
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return entry, false, nil
+	}
+
+	entry.Status = "SCHEDULED"
+	entry.EstimatedReadyTime = entry.RequestedPickupTime
+	utils.InvalidateQueueCache(ctx, entry.ID)
+	s.queueChanged(ctx, entry.ID)
+	s.recordStatusTransition(ctx, entry, "PENDING_PAYMENT", "SCHEDULED")
+	s.emitWebhookEvent(ctx, WebhookEventStatusChanged, entry.ID, "PENDING_PAYMENT")
+
+	return entry, true, nil
+}
+
+// StartScheduledActivator periodically activates due scheduled entries until ctx is cancelled
+func (s *QueueService) StartScheduledActivator(ctx context.Context, interval time.Duration) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			activated, err := s.ActivateScheduledEntries(ctx)
+			if err != nil {
+				log.Printf("Failed to activate scheduled entries: %v", err)
+			}
+			if activated > 0 {
+				log.Printf("Activated %d scheduled entries", activated)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAlreadyClockedIn is returned when a staff member with an open shift clocks in
+	ErrAlreadyClockedIn = newError(KindConflict, "ALREADY_CLOCKED_IN", "already clocked in")
+	// ErrNotClockedIn is returned when a staff member without an open shift clocks out
+	ErrNotClockedIn = newError(KindConflict, "NOT_CLOCKED_IN", "not clocked in")
+	// ErrNotOnDuty is returned when staff act on a queue they aren't on shift at
+	ErrNotOnDuty = newError(KindConflict, "NOT_ON_DUTY", "clock in at this location first")
+)
+
+// ClockIn opens a shift for a staff member at the request's location,
+// optionally at one of its counters
+func (s *QueueService) ClockIn(ctx context.Context, staffID, staffName string, req *models.ClockInRequest) (*models.StaffShift, error) {
+	if req.CounterID != nil {
+		if _, err := s.resolveCounter(ctx, req.CounterID, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	now := s.clock.Now().UTC()
+	shift := &models.StaffShift{
+		ID:         utils.GenerateUUID(),
+		StaffID:    staffID,
+		StaffName:  utils.StringPtr(staffName),
+		LocationID: LocationFromContext(ctx),
+		CounterID:  req.CounterID,
+		ClockInAt:  now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	// A unique index on open shifts rejects a second one
+	if err := s.db.WithContext(ctx).Create(shift).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, ErrAlreadyClockedIn
+		}
+		return nil, err
+	}
+	return shift, nil
+}
+
+// ClockOut closes a staff member's open shift
+func (s *QueueService) ClockOut(ctx context.Context, staffID string) (*models.StaffShift, error) {
+	shift, err := s.activeShift(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotClockedIn
+		}
+		return nil, err
+	}
+
+	now := s.clock.Now().UTC()
+	result := s.db.WithContext(ctx).Model(shift).
+		Where("clock_out_at IS NULL").
+		Updates(map[string]interface{}{
+			"clock_out_at": now,
+			"updated_at":   now,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	// Clocked out concurrently
+	if result.RowsAffected == 0 {
+		return nil, ErrNotClockedIn
+	}
+	shift.ClockOutAt = &now
+
+	return shift, nil
+}
+
+// GetActiveShifts lists the staff on duty at the request's location
+func (s *QueueService) GetActiveShifts(ctx context.Context) ([]models.StaffShift, error) {
+	var shifts []models.StaffShift
+	if err := s.db.WithContext(ctx).
+		Where("location_id = ? AND clock_out_at IS NULL", LocationFromContext(ctx)).
+		Order("clock_in_at ASC").
+		Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+	return shifts, nil
+}
+
+// GetShiftActionLogs lists the staff actions taken during a shift
+func (s *QueueService) GetShiftActionLogs(ctx context.Context, shiftID string) ([]models.StaffQueueActionLog, error) {
+	var shift models.StaffShift
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", shiftID).First(&shift).Error; err != nil {
+		return nil, err
+	}
+
+	var logs []models.StaffQueueActionLog
+	if err := s.db.WithContext(ctx).Where("shift_id = ?", shiftID).
+		Order("timestamp ASC").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// activeShift finds the open shift of a staff member
+func (s *QueueService) activeShift(ctx context.Context, staffID string) (*models.StaffShift, error) {
+	var shift models.StaffShift
+	if err := s.db.WithContext(ctx).
+		Where("staff_id = ? AND clock_out_at IS NULL", staffID).
+		First(&shift).Error; err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// activeShiftID returns the ID of a staff member's open shift, if any, for
+// correlating their actions with it
+func (s *QueueService) activeShiftID(ctx context.Context, staffID string) *string {
+	shift, err := s.activeShift(ctx, staffID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Failed to look up shift of staff %s: %v", staffID, err)
+		}
+		return nil
+	}
+	return &shift.ID
+}
+
+// staffedCounterIDs lists the counters of a location someone is on shift at
+func (s *QueueService) staffedCounterIDs(ctx context.Context, locationID string) (map[string]bool, error) {
+	var ids []string
+	if err := s.db.WithContext(ctx).Model(&models.StaffShift{}).
+		Where("location_id = ? AND clock_out_at IS NULL AND counter_id IS NOT NULL", locationID).
+		Distinct().
+		Pluck("counter_id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	staffed := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		staffed[id] = true
+	}
+	return staffed, nil
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNotSkippable is returned when skipping an entry that isn't waiting
+var ErrNotSkippable = newError(KindConflict, "NOT_SKIPPABLE", "only waiting entries can be skipped")
+
+const (
+	defaultSkipPositions      = 3
+	defaultSkipTimeoutMinutes = 5
+)
+
+// SkipEntry bumps a waiting entry whose customer isn't responding down a few
+// places. It stays within its priority, which the queue keeps together, and
+// moves back up as many places once the timeout passes. Skipping again
+// before then adds to the places to restore and restarts the timeout.
+func (s *QueueService) SkipEntry(ctx context.Context, entryID string, req *models.SkipEntryRequest, staffID, staffName string) (*models.QueueEntry, error) {
+	var entry models.QueueEntry
+	if err := inLocation(ctx, s.db.WithContext(ctx)).Where("id = ?", entryID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	ctx = WithLocation(ctx, entry.LocationID)
+
+	if entry.Status != "WAITING" {
+		return nil, ErrNotSkippable
+	}
+
+	config, err := s.GetConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+	positions := config.SkipPositions
+	if req.Positions != nil {
+		positions = *req.Positions
+	}
+	if positions < 1 {
+		positions = defaultSkipPositions
+	}
+	timeoutMinutes := config.SkipTimeoutMinutes
+	if req.TimeoutMinutes != nil {
+		timeoutMinutes = *req.TimeoutMinutes
+	}
+	if timeoutMinutes < 1 {
+		timeoutMinutes = defaultSkipTimeoutMinutes
+	}
+
+	reason := req.Reason
+	if reason == nil {
+		reason = utils.StringPtr("Customer not responding at the counter")
+	}
+
+	moved, err := s.moveInLane(ctx, &entry, positions, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now().UTC()
+	if err := s.db.WithContext(ctx).Model(&entry).Updates(map[string]interface{}{
+		"skipped_at":        now,
+		"skipped_positions": gorm.Expr("skipped_positions + ?", moved),
+		"skip_restore_at":   now.Add(time.Duration(timeoutMinutes) * time.Minute),
+		"updated_at":        now,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	note := fmt.Sprintf("%s (moved down %d of %d places for %d min)", *reason, moved, positions, timeoutMinutes)
+	s.LogStaffAction(ctx, entry.ID, staffID, staffName, "SKIP", nil, nil, nil, nil, &note)
+	utils.InvalidateQueueCache(ctx, entry.ID)
+
+	return s.GetQueueEntryByID(ctx, entry.ID)
+}
+
+// RestoreSkippedEntries moves entries whose skip timed out back up the places
+// they were skipped. Entries that left the queue meanwhile just lose their skip.
+func (s *QueueService) RestoreSkippedEntries(ctx context.Context) (int, error) {
+	var due []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Where("skip_restore_at IS NOT NULL AND skip_restore_at <= ?", s.clock.Now().UTC()).
+		Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	restored := 0
+	var errs []error
+	for i := range due {
+		ok, err := s.restoreSkip(ctx, &due[i])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", due[i].ID, err))
+			continue
+		}
+		if ok {
+			restored++
+		}
+	}
+	return restored, errors.Join(errs...)
+}
+
+// restoreSkip claims an entry's due skip, so only one instance restores it,
+// and moves the entry back up if it is still waiting
+func (s *QueueService) restoreSkip(ctx context.Context, entry *models.QueueEntry) (bool, error) {
+	ctx = WithLocation(ctx, entry.LocationID)
+
+	result := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("id = ? AND skip_restore_at = ?", entry.ID, entry.SkipRestoreAt).
+		Updates(map[string]interface{}{
+			"skip_restore_at":   nil,
+			"skipped_positions": 0,
+			"updated_at":        s.clock.Now().UTC(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	utils.InvalidateQueueCache(ctx, entry.ID)
+	if result.RowsAffected == 0 || entry.Status != "WAITING" || entry.SkippedPositions == 0 {
+		return false, nil
+	}
+
+	reason := fmt.Sprintf("Skip timed out; moved back up %d places", entry.SkippedPositions)
+	if _, err := s.moveInLane(ctx, entry, -entry.SkippedPositions, &reason); err != nil {
+		return false, err
+	}
+	s.LogStaffAction(ctx, entry.ID, "system", "System", "RESTORE_SKIP", nil, nil, nil, nil, &reason)
+	return true, nil
+}
+
+// StartSkipRestorer periodically restores skipped entries until ctx is cancelled
+func (s *QueueService) StartSkipRestorer(ctx context.Context, interval time.Duration) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			restored, err := s.RestoreSkippedEntries(ctx)
+			if err != nil {
+				log.Printf("Failed to restore skipped entries: %v", err)
+			}
+			if restored > 0 {
+				log.Printf("Restored %d skipped entries", restored)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// moveInLane moves a waiting entry offset places back (or forward, when
+// negative) among the waiting entries of its lane and priority, records the
+// position history of every entry that moved and tells their customers.
+// Returns how many places the entry moved.
+func (s *QueueService) moveInLane(ctx context.Context, entry *models.QueueEntry, offset int, reason *string) (int, error) {
+	moved := 0
+	oldPositions := make(map[string]int)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var band []models.QueueEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("location_id = ? AND is_express_queue = ? AND priority = ? AND status = ?",
+				entry.LocationID, entry.IsExpressQueue, entry.Priority, "WAITING").
+			Order("position ASC").
+			Find(&band).Error; err != nil {
+			return err
+		}
+
+		from := slices.IndexFunc(band, func(other models.QueueEntry) bool { return other.ID == entry.ID })
+		if from < 0 {
+			return ErrNotSkippable
+		}
+		to := min(max(from+offset, 0), len(band)-1)
+		moved = to - from
+		if moved == 0 {
+			return nil
+		}
+
+		// The band's positions, handed out in the new order
+		positions := make([]int, len(band))
+		for i, other := range band {
+			positions[i] = other.Position
+		}
+		moving := band[from]
+		band = slices.Insert(slices.Delete(band, from, from+1), to, moving)
+
+		now := s.clock.Now().UTC()
+		for i, other := range band {
+			if other.Position == positions[i] {
+				continue
+			}
+			oldPositions[other.ID] = other.Position
+			if err := tx.Model(&models.QueueEntry{}).Where("id = ?", other.ID).Updates(map[string]interface{}{
+				"position":   positions[i],
+				"updated_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil || moved == 0 {
+		return moved, err
+	}
+
+	// Renumber and re-estimate the location's queue, which keeps the new order
+	// and records each move in the history
+	if err := s.recalculatePositions(ctx, oldPositions, reason); err != nil {
+		return moved, err
+	}
+
+	ids := make([]string, 0, len(oldPositions))
+	for id := range oldPositions {
+		ids = append(ids, id)
+	}
+	var changed []models.QueueEntry
+	if err := s.db.WithContext(ctx).Where("id IN ?", ids).Find(&changed).Error; err != nil {
+		return moved, err
+	}
+	for i := range changed {
+		other := &changed[i]
+		if s.publisher != nil {
+			if err := s.publisher.PublishQueuePositionUpdate(ctx, other); err != nil {
+				log.Printf("Failed to publish position of %s: %v", other.TokenNumber, err)
+			}
+		}
+	}
+	return moved, nil
+}
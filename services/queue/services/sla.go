@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+)
+
+// slaMinutes returns the SLA of a priority in minutes, or 0 when the
+// priority has none. Priorities without their own SLA use the wait alert.
+func slaMinutes(config *models.QueueConfiguration, priority string) int {
+	var sla *int
+	switch priority {
+	case "VIP":
+		sla = config.SLAMinutesVIP
+	case "URGENT":
+		sla = config.SLAMinutesUrgent
+	case "HIGH":
+		sla = config.SLAMinutesHigh
+	case "NORMAL":
+		sla = config.SLAMinutesNormal
+	case "LOW":
+		sla = config.SLAMinutesLow
+	}
+
+	minutes := config.MaxWaitTimeAlert
+	if sla != nil {
+		minutes = *sla
+	}
+	return max(minutes, 0)
+}
+
+// queueJoinedAt is when an entry's wait started: scheduled entries only
+// join the queue once their preparation is due
+func queueJoinedAt(entry *models.QueueEntry) time.Time {
+	if entry.ScheduledActivateAt != nil && entry.ScheduledActivateAt.After(entry.CreatedAt) {
+		return *entry.ScheduledActivateAt
+	}
+	return entry.CreatedAt
+}
+
+// CheckSLABreaches flags the active entries that have waited longer than the
+// SLA of their priority and publishes a breach for each. An entry is only
+// flagged once, however long it goes on waiting.
+func (s *QueueService) CheckSLABreaches(ctx context.Context) (int, error) {
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Where("status IN ? AND sla_breached_at IS NULL", activeStatuses).
+		Find(&entries).Error; err != nil {
+		return 0, err
+	}
+
+	now := s.clock.Now().UTC()
+	configs := make(map[string]*models.QueueConfiguration)
+	breached := 0
+	var errs []error
+	for i := range entries {
+		entry := &entries[i]
+		config, ok := configs[entry.LocationID]
+		if !ok {
+			loaded, err := s.GetConfiguration(WithLocation(ctx, entry.LocationID))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("location %s: %w", entry.LocationID, err))
+				continue
+			}
+			config = loaded
+			configs[entry.LocationID] = config
+		}
+
+		sla := slaMinutes(config, entry.Priority)
+		waited := int(now.Sub(queueJoinedAt(entry)).Minutes())
+		if sla == 0 || waited < sla {
+			continue
+		}
+
+		ok, err := s.flagSLABreach(ctx, entry, now, waited, sla)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entry %s: %w", entry.ID, err))
+			continue
+		}
+		if ok {
+			breached++
+		}
+	}
+	return breached, errors.Join(errs...)
+}
+
+// flagSLABreach claims an entry's breach, so only one instance publishes it,
+// and publishes it if the entry is still active
+func (s *QueueService) flagSLABreach(ctx context.Context, entry *models.QueueEntry, now time.Time, waited, sla int) (bool, error) {
+	ctx = WithLocation(ctx, entry.LocationID)
+
+	result := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("id = ? AND status IN ? AND sla_breached_at IS NULL", entry.ID, activeStatuses).
+		Updates(map[string]interface{}{
+			"sla_breached_at": now,
+			"updated_at":      now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	entry.SLABreachedAt = &now
+
+	utils.InvalidateQueueCache(ctx, entry.ID)
+	s.queueChanged(ctx, entry.ID)
+	log.Printf("Entry %s (%s) breached its SLA: waited %d min, SLA %d min", entry.TokenNumber, entry.Priority, waited, sla)
+
+	if s.publisher != nil {
+		if err := s.publisher.PublishSLABreached(ctx, entry, waited, sla); err != nil {
+			log.Printf("Failed to publish SLA breach of %s: %v", entry.TokenNumber, err)
+		}
+	}
+	return true, nil
+}
+
+// GetSLABreaches lists the request location's active entries that breached
+// their SLA, longest breached first
+func (s *QueueService) GetSLABreaches(ctx context.Context) ([]models.SLABreach, error) {
+	config, err := s.GetConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Where("location_id = ? AND status IN ? AND sla_breached_at IS NOT NULL", LocationFromContext(ctx), activeStatuses).
+		Order("sla_breached_at ASC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now().UTC()
+	breaches := make([]models.SLABreach, 0, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		breaches = append(breaches, models.SLABreach{
+			QueueEntryID:   entry.ID,
+			TokenNumber:    entry.TokenNumber,
+			Status:         entry.Status,
+			Priority:       entry.Priority,
+			IsExpressQueue: entry.IsExpressQueue,
+			Position:       entry.Position,
+			WaitedMinutes:  int(now.Sub(queueJoinedAt(entry)).Minutes()),
+			SLAMinutes:     slaMinutes(config, entry.Priority),
+			BreachedAt:     *entry.SLABreachedAt,
+		})
+	}
+	return breaches, nil
+}
+
+// StartSLAMonitor periodically checks active entries against their SLA
+// until ctx is cancelled
+func (s *QueueService) StartSLAMonitor(ctx context.Context, interval time.Duration) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			breached, err := s.CheckSLABreaches(ctx)
+			if err != nil {
+				log.Printf("Failed to check SLA breaches: %v", err)
+			}
+			if breached > 0 {
+				log.Printf("Flagged %d entries past their SLA", breached)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
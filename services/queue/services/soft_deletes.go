@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+// GetDeletedQueueEntries lists the request location's deleted entries, most
+// recently deleted first
+func (s *QueueService) GetDeletedQueueEntries(ctx context.Context) ([]models.QueueEntry, error) {
+	var entries []models.QueueEntry
+	if err := inLocation(ctx, s.db.WithContext(ctx).Unscoped()).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// RestoreQueueEntry brings back a deleted entry and drops its tombstone, so
+// consumers syncing from now on keep their copy
+func (s *QueueService) RestoreQueueEntry(ctx context.Context, entryID string) (*models.QueueEntry, error) {
+	var entry models.QueueEntry
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := inLocation(ctx, tx.Unscoped()).Where("id = ? AND deleted_at IS NOT NULL", entryID).First(&entry).Error; err != nil {
+			return err
+		}
+		entry.DeletedAt = gorm.DeletedAt{}
+		entry.UpdatedAt = s.clock.Now().UTC()
+		if err := tx.Unscoped().Model(&models.QueueEntry{}).Where("id = ?", entry.ID).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"updated_at": entry.UpdatedAt,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("queue_entry_id = ? AND action = ?", entry.ID, "DELETED").Delete(&models.QueueEntryTombstone{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.InvalidateQueueCache(ctx, entry.ID)
+	s.queueChanged(ctx, entry.ID)
+
+	// Make room for the entry again
+	go s.RecalculatePositions(WithLocation(context.WithoutCancel(ctx), entry.LocationID))
+
+	return &entry, nil
+}
+
+// DeleteAnnouncement deletes a display announcement
+func (s *QueueService) DeleteAnnouncement(ctx context.Context, announcementID string) error {
+	result := s.db.WithContext(ctx).Where("id = ?", announcementID).Delete(&models.QueueDisplayAnnouncement{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetDeletedAnnouncements lists deleted display announcements, most recently
+// deleted first
+func (s *QueueService) GetDeletedAnnouncements(ctx context.Context) ([]models.QueueDisplayAnnouncement, error) {
+	var announcements []models.QueueDisplayAnnouncement
+	if err := s.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&announcements).Error; err != nil {
+		return nil, err
+	}
+	return announcements, nil
+}
+
+// RestoreAnnouncement brings back a deleted display announcement
+func (s *QueueService) RestoreAnnouncement(ctx context.Context, announcementID string) (*models.QueueDisplayAnnouncement, error) {
+	var announcement models.QueueDisplayAnnouncement
+	if err := s.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", announcementID).First(&announcement).Error; err != nil {
+		return nil, err
+	}
+
+	announcement.DeletedAt = gorm.DeletedAt{}
+	announcement.UpdatedAt = s.clock.Now().UTC()
+	if err := s.db.WithContext(ctx).Unscoped().Model(&models.QueueDisplayAnnouncement{}).Where("id = ?", announcement.ID).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"updated_at": announcement.UpdatedAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// DeleteConfiguration deletes the request location's own configuration, so
+// it falls back to the default's. The default configuration can't be deleted.
+func (s *QueueService) DeleteConfiguration(ctx context.Context) error {
+	locationID := LocationFromContext(ctx)
+	if locationID == models.DefaultLocationID {
+		return ErrDefaultLocation
+	}
+
+	result := s.db.WithContext(ctx).Where("location_id = ?", locationID).Delete(&models.QueueConfiguration{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: the location uses the default configuration", ErrNotFound)
+	}
+
+	s.configurationReplaced(ctx, locationID)
+	return nil
+}
+
+// GetDeletedConfigurations lists deleted location configurations, most
+// recently deleted first
+func (s *QueueService) GetDeletedConfigurations(ctx context.Context) ([]models.QueueConfiguration, error) {
+	var configs []models.QueueConfiguration
+	if err := inLocation(ctx, s.db.WithContext(ctx).Unscoped()).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&configs).Error; err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+// RestoreConfiguration brings back a deleted location configuration
+func (s *QueueService) RestoreConfiguration(ctx context.Context, configID string) (*models.QueueConfiguration, error) {
+	var config models.QueueConfiguration
+	if err := inLocation(ctx, s.db.WithContext(ctx).Unscoped()).Where("id = ? AND deleted_at IS NOT NULL", configID).First(&config).Error; err != nil {
+		return nil, err
+	}
+
+	config.DeletedAt = gorm.DeletedAt{}
+	if err := s.db.WithContext(ctx).Unscoped().Model(&models.QueueConfiguration{}).Where("id = ?", config.ID).
+		Update("deleted_at", nil).Error; err != nil {
+		return nil, err
+	}
+
+	s.configurationReplaced(ctx, config.LocationID)
+	return &config, nil
+}
+
+// configurationReplaced drops the cached configuration of a location whose
+// configuration row was deleted or restored, and requeues it under the
+// configuration now in effect
+func (s *QueueService) configurationReplaced(ctx context.Context, locationID string) {
+	invalidateConfiguration(ctx, locationID)
+
+	go func() {
+		ctx := WithLocation(context.WithoutCancel(ctx), locationID)
+		s.RecalculatePositions(ctx)
+		s.startDeferred(ctx, locationID)
+	}()
+}
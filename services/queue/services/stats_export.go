@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"gin-quickstart/models"
+
+	"gorm.io/gorm"
+)
+
+// statsExportBatchSize is how many statistics rows an export loads at once
+const statsExportBatchSize = 500
+
+// StreamDailyStatistics passes the request location's daily statistics from
+// from to to, inclusive, to fn a batch at a time, oldest first
+func (s *QueueService) StreamDailyStatistics(ctx context.Context, from, to time.Time, fn func([]models.QueueStatistics) error) error {
+	from, to = from.Truncate(24*time.Hour), to.Truncate(24*time.Hour)
+	query := s.db.WithContext(ctx).
+		Where("location_id = ? AND date <= ?", LocationFromContext(ctx), to).
+		Order("date ASC").
+		Limit(statsExportBatchSize).
+		Session(&gorm.Session{})
+
+	// Each location has one row a day, so the date picks up where a batch ended
+	batch := []models.QueueStatistics{}
+	for cursor := query.Where("date >= ?", from); ; {
+		batch = batch[:0]
+		if err := cursor.Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < statsExportBatchSize {
+			return nil
+		}
+		cursor = query.Where("date > ?", batch[len(batch)-1].Date)
+	}
+}
+
+// StreamHourlyStatistics passes the request location's hourly statistics
+// from from to to, inclusive, to fn a batch at a time, oldest first
+func (s *QueueService) StreamHourlyStatistics(ctx context.Context, from, to time.Time, fn func([]models.QueueHourlyStatistics) error) error {
+	from, to = from.Truncate(24*time.Hour), to.Truncate(24*time.Hour)
+	query := s.db.WithContext(ctx).
+		Where("location_id = ? AND date <= ?", LocationFromContext(ctx), to).
+		Order("date ASC, hour ASC").
+		Limit(statsExportBatchSize).
+		Session(&gorm.Session{})
+
+	batch := []models.QueueHourlyStatistics{}
+	for cursor := query.Where("date >= ?", from); ; {
+		batch = batch[:0]
+		if err := cursor.Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if len(batch) < statsExportBatchSize {
+			return nil
+		}
+		last := batch[len(batch)-1]
+		cursor = query.Where("(date > ? OR (date = ? AND hour > ?))", last.Date, last.Date, last.Hour)
+	}
+}
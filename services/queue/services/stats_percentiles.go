@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm/clause"
+)
+
+// percentileSummaryFields are the summary hash fields of the day's percentiles
+var percentileSummaryFields = []string{
+	"wait_time_p50", "wait_time_p90", "wait_time_p99",
+	"prep_time_p50", "prep_time_p90", "prep_time_p99",
+}
+
+// percentile returns the nearest-rank pth percentile of sorted values, 0 when empty
+func percentile(sorted []int, p float64) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+// computeTimePercentiles computes the wait and preparation time percentiles
+// of entries, counting the entries that started and became ready
+func computeTimePercentiles(entries []models.QueueEntry) models.TimePercentiles {
+	var waits, preps []int
+	for _, entry := range entries {
+		if entry.ActualStartTime == nil {
+			continue
+		}
+		waits = append(waits, int(entry.ActualStartTime.Sub(entry.CreatedAt).Minutes()))
+		if entry.ActualReadyTime != nil {
+			preps = append(preps, int(entry.ActualReadyTime.Sub(*entry.ActualStartTime).Minutes()))
+		}
+	}
+	slices.Sort(waits)
+	slices.Sort(preps)
+
+	return models.TimePercentiles{
+		WaitTimeP50: percentile(waits, 50),
+		WaitTimeP90: percentile(waits, 90),
+		WaitTimeP99: percentile(waits, 99),
+		PrepTimeP50: percentile(preps, 50),
+		PrepTimeP90: percentile(preps, 90),
+		PrepTimeP99: percentile(preps, 99),
+	}
+}
+
+// storePercentiles writes percentiles into a summary hash
+func storePercentiles(summary map[string]string, percentiles models.TimePercentiles) {
+	summary["wait_time_p50"] = strconv.Itoa(percentiles.WaitTimeP50)
+	summary["wait_time_p90"] = strconv.Itoa(percentiles.WaitTimeP90)
+	summary["wait_time_p99"] = strconv.Itoa(percentiles.WaitTimeP99)
+	summary["prep_time_p50"] = strconv.Itoa(percentiles.PrepTimeP50)
+	summary["prep_time_p90"] = strconv.Itoa(percentiles.PrepTimeP90)
+	summary["prep_time_p99"] = strconv.Itoa(percentiles.PrepTimeP99)
+}
+
+// flushHourlyStatistics persists a location's statistics of each hour of a
+// day that entries joined its queue in
+func (s *QueueService) flushHourlyStatistics(ctx context.Context, locationID string, date time.Time) error {
+	dayStart, dayEnd := clock.DayBounds(date)
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Select("status", "actual_start_time", "actual_ready_time", "created_at").
+		Where("location_id = ? AND created_at >= ? AND created_at < ?", locationID, dayStart, dayEnd).
+		Find(&entries).Error; err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	byHour := make(map[int][]models.QueueEntry)
+	for _, entry := range entries {
+		hour := clock.Local(entry.CreatedAt).Hour()
+		byHour[hour] = append(byHour[hour], entry)
+	}
+
+	now := s.clock.Now().UTC()
+	rows := make([]models.QueueHourlyStatistics, 0, len(byHour))
+	for hour, hourEntries := range byHour {
+		kpis := computeLocationKPIs(locationID, hourEntries)
+		rows = append(rows, models.QueueHourlyStatistics{
+			ID:                 utils.GenerateUUID(),
+			LocationID:         locationID,
+			Date:               date,
+			Hour:               hour,
+			OrderCount:         kpis.TotalEntries,
+			AvgWaitTime:        kpis.AvgWaitTime,
+			AvgPreparationTime: kpis.AvgPreparationTime,
+			TimePercentiles:    computeTimePercentiles(hourEntries),
+			CompletedCount:     kpis.CompletedCount,
+			CancelledCount:     kpis.CancelledCount,
+			UpdatedAt:          now,
+		})
+	}
+
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "location_id"}, {Name: "date"}, {Name: "hour"}},
+		DoUpdates: clause.AssignmentColumns(append([]string{
+			"order_count", "avg_wait_time", "avg_preparation_time", "completed_count", "cancelled_count",
+			"updated_at",
+		}, percentileSummaryFields...)),
+	}).Create(&rows).Error
+}
+
+// GetHourlyStatistics gets the request location's statistics of each hour of
+// a day (today when date is nil) that entries joined the queue in
+func (s *QueueService) GetHourlyStatistics(ctx context.Context, date *time.Time) ([]models.QueueHourlyStatistics, error) {
+	targetDate := clock.Date(s.clock.Now())
+	if date != nil {
+		targetDate = date.Truncate(24 * time.Hour)
+	}
+
+	stats := []models.QueueHourlyStatistics{}
+	if err := s.db.WithContext(ctx).
+		Where("location_id = ? AND date = ?", LocationFromContext(ctx), targetDate).
+		Order("hour ASC").
+		Find(&stats).Error; err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/models"
+)
+
+// statsReconcileCheckInterval is how often the reconciler checks whether
+// the day's run is due
+const statsReconcileCheckInterval = 10 * time.Minute
+
+// waitTimeRange returns the longest and shortest wait, joined to started, in
+// minutes of the entries that started
+func waitTimeRange(entries []models.QueueEntry) (longest, shortest int) {
+	first := true
+	for _, entry := range entries {
+		if entry.ActualStartTime == nil {
+			continue
+		}
+		wait := int(entry.ActualStartTime.Sub(entry.CreatedAt).Minutes())
+		if first || wait > longest {
+			longest = wait
+		}
+		if first || wait < shortest {
+			shortest = wait
+		}
+		first = false
+	}
+	return longest, shortest
+}
+
+// noShowRate is the percentage of a summary's entries that joined the queue
+// and were never picked up
+func noShowRate(summary map[string]string) float64 {
+	total := 0
+	for _, field := range statsSummaryFields {
+		count, _ := strconv.Atoi(summary[field])
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+	noShows, _ := strconv.Atoi(summary["no_show_today"])
+	return roundRate(float64(noShows) / float64(total) * 100)
+}
+
+// ReconcileStatistics recomputes the daily and hourly statistics of a day
+// from queue_entries for every location with entries that day, replacing
+// whatever the summaries accumulated. Peak load and queue length, which
+// come from load samples, are kept.
+func (s *QueueService) ReconcileStatistics(ctx context.Context, date time.Time) error {
+	date = date.UTC().Truncate(24 * time.Hour)
+	dayStart, dayEnd := clock.DayBounds(date)
+
+	var locationIDs []string
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("created_at >= ? AND created_at < ?", dayStart, dayEnd).
+		Distinct().
+		Pluck("location_id", &locationIDs).Error; err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, locationID := range locationIDs {
+		ctx := WithLocation(ctx, locationID)
+		summary, err := s.RebuildStatsSummary(ctx, locationID, date)
+		if err == nil {
+			err = s.persistStatsSummary(ctx, locationID, date, summary)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("location %s: %w", locationID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StartStatsReconciler reconciles the statistics of the days before today,
+// going back days, once a day after hour (in the restaurant's time zone)
+// until ctx is cancelled
+func (s *QueueService) StartStatsReconciler(ctx context.Context, hour, days int) {
+	ticker := s.clock.NewTicker(statsReconcileCheckInterval)
+	defer ticker.Stop()
+
+	var lastRun time.Time
+	for {
+		select {
+		case <-ticker.C():
+			now := s.clock.Now()
+			today := clock.Date(now)
+			if clock.Local(now).Hour() < hour || !lastRun.Before(today) {
+				continue
+			}
+			lastRun = today
+
+			for i := 1; i <= days; i++ {
+				date := today.AddDate(0, 0, -i)
+				if err := s.ReconcileStatistics(ctx, date); err != nil {
+					log.Printf("Failed to reconcile statistics of %s: %v", date.Format("2006-01-02"), err)
+					continue
+				}
+				log.Printf("Reconciled statistics of %s", date.Format("2006-01-02"))
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/database"
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm/clause"
+)
+
+// statsSummaryTTL keeps yesterday's summary around for late transitions
+const statsSummaryTTL = 48 * time.Hour
+
+// statsSummaryFields maps an entry status to its counter in the summary hash
+var statsSummaryFields = map[string]string{
+	"WAITING":     "waiting_count",
+	"IN_PROGRESS": "in_progress_count",
+	"READY":       "ready_count",
+	"COMPLETED":   "completed_today",
+	"CANCELLED":   "cancelled_today",
+	"NO_SHOW":     "no_show_today",
+	"EXPIRED":     "expired_today",
+}
+
+// applyStatsDelta only increments an existing summary; a missing one must be rebuilt
+// from MySQL, otherwise a partial hash would be served as the day's totals.
+var applyStatsDelta = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+for i = 1, #ARGV, 2 do
+	redis.call('HINCRBY', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+return 1
+`)
+
+func statsSummaryKey(locationID string, date time.Time) string {
+	return fmt.Sprintf("queue:stats:%s:%s", locationID, date.Format("2006-01-02"))
+}
+
+// recordStatusTransition applies an entry's status change to the summary of its
+// location and the day it was created. Either status may be empty for
+// creations and deletions.
+func (s *QueueService) recordStatusTransition(ctx context.Context, entry *models.QueueEntry, oldStatus, newStatus string) {
+	rdb := database.GetRedis()
+	if rdb == nil || oldStatus == newStatus {
+		return
+	}
+
+	var args []interface{}
+	if field, ok := statsSummaryFields[oldStatus]; ok {
+		args = append(args, field, -1)
+	}
+	if field, ok := statsSummaryFields[newStatus]; ok {
+		args = append(args, field, 1)
+	}
+	// Walk-ins count once, from their creation
+	if entry.TokenType == models.TokenTypeWalkIn {
+		switch {
+		case oldStatus == "":
+			args = append(args, "walk_ins_today", 1)
+		case newStatus == "":
+			args = append(args, "walk_ins_today", -1)
+		}
+	}
+	if len(args) == 0 {
+		return
+	}
+
+	locationID := entry.LocationID
+	date := clock.Date(entry.CreatedAt)
+	applied, err := applyStatsDelta.Run(ctx, rdb, []string{statsSummaryKey(locationID, date)}, args...).Int()
+	if err != nil {
+		log.Printf("Failed to update stats summary: %v", err)
+	}
+	if err != nil || applied == 0 {
+		// The mutation is already committed, so a rebuild from MySQL includes it
+		go func() {
+			if _, err := s.RebuildStatsSummary(context.WithoutCancel(ctx), locationID, date); err != nil {
+				log.Printf("Failed to rebuild stats summary: %v", err)
+			}
+		}()
+	}
+}
+
+// statsRebuildAttempts bounds how often a rebuild recounts because the
+// summary changed while it was counting
+const statsRebuildAttempts = 5
+
+// RebuildStatsSummary recounts a location's entries of a day from MySQL into
+// the summary hash. The hash is watched while counting, so an increment
+// applied in the meantime makes it count again rather than be overwritten.
+func (s *QueueService) RebuildStatsSummary(ctx context.Context, locationID string, date time.Time) (map[string]string, error) {
+	date = date.UTC().Truncate(24 * time.Hour)
+
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return s.countStatsSummary(ctx, locationID, date)
+	}
+
+	key := statsSummaryKey(locationID, date)
+	for attempt := 0; attempt < statsRebuildAttempts; attempt++ {
+		err := rdb.Watch(ctx, func(tx *redis.Tx) error {
+			summary, err := s.countStatsSummary(ctx, locationID, date)
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.HSet(ctx, key, summary)
+				pipe.Expire(ctx, key, statsSummaryTTL)
+				return nil
+			})
+			return err
+		}, key)
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Keep previously flushed averages, if any
+		return rdb.HGetAll(ctx, key).Result()
+	}
+	return nil, fmt.Errorf("stats summary %s kept changing during rebuild", key)
+}
+
+// countStatsSummary counts a location's entries of a day by status, and its walk-ins
+func (s *QueueService) countStatsSummary(ctx context.Context, locationID string, date time.Time) (map[string]string, error) {
+	dayStart, dayEnd := clock.DayBounds(date)
+
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Select("status, COUNT(*) AS count").
+		Where("location_id = ? AND created_at >= ? AND created_at < ?", locationID, dayStart, dayEnd).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	var walkIns int64
+	if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+		Where("location_id = ? AND token_type = ? AND created_at >= ? AND created_at < ?", locationID, models.TokenTypeWalkIn, dayStart, dayEnd).
+		Count(&walkIns).Error; err != nil {
+		return nil, err
+	}
+
+	summary := make(map[string]string, len(statsSummaryFields)+1)
+	for _, field := range statsSummaryFields {
+		summary[field] = "0"
+	}
+	for _, row := range rows {
+		if field, ok := statsSummaryFields[row.Status]; ok {
+			summary[field] = strconv.Itoa(row.Count)
+		}
+	}
+	summary["walk_ins_today"] = strconv.FormatInt(walkIns, 10)
+	return summary, nil
+}
+
+// getStatsSummary reads a location's summary hash of a day; ok is false when it doesn't exist
+func (s *QueueService) getStatsSummary(ctx context.Context, locationID string, date time.Time) (map[string]string, bool) {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return nil, false
+	}
+
+	summary, err := rdb.HGetAll(ctx, statsSummaryKey(locationID, date)).Result()
+	if err != nil || len(summary) == 0 {
+		return nil, false
+	}
+	return summary, true
+}
+
+func statsResponseFromSummary(locationID string, date time.Time, summary map[string]string) *models.QueueStatsResponse {
+	intField := func(name string) int {
+		value, _ := strconv.Atoi(summary[name])
+		return value
+	}
+	floatField := func(name string) float64 {
+		value, _ := strconv.ParseFloat(summary[name], 64)
+		return value
+	}
+
+	stats := &models.QueueStatsResponse{
+		LocationID:           locationID,
+		Date:                 date.Format("2006-01-02"),
+		WaitingCount:         intField("waiting_count"),
+		InProgressCount:      intField("in_progress_count"),
+		ReadyCount:           intField("ready_count"),
+		CompletedToday:       intField("completed_today"),
+		CancelledToday:       intField("cancelled_today"),
+		WalkInsToday:         intField("walk_ins_today"),
+		AvgWaitTime:          intField("avg_wait_time"),
+		AvgPreparationTime:   intField("avg_preparation_time"),
+		LongestWaitTime:      intField("longest_wait_time"),
+		ShortestWaitTime:     intField("shortest_wait_time"),
+		CurrentLoad:          floatField("current_load"),
+		OnTimeCompletionRate: floatField("on_time_completion_rate"),
+		NoShowRate:           floatField("no_show_rate"),
+	}
+	stats.TotalInQueue = stats.WaitingCount + stats.InProgressCount + stats.ReadyCount
+	stats.TimePercentiles = models.TimePercentiles{
+		WaitTimeP50: intField("wait_time_p50"),
+		WaitTimeP90: intField("wait_time_p90"),
+		WaitTimeP99: intField("wait_time_p99"),
+		PrepTimeP50: intField("prep_time_p50"),
+		PrepTimeP90: intField("prep_time_p90"),
+		PrepTimeP99: intField("prep_time_p99"),
+	}
+	return stats
+}
+
+// flushStatsSummary persists a location's summary of the day, rebuilding it
+// from MySQL first when it doesn't exist
+func (s *QueueService) flushStatsSummary(ctx context.Context, locationID string, date time.Time) error {
+	ctx = WithLocation(ctx, locationID)
+	summary, ok := s.getStatsSummary(ctx, locationID, date)
+	if !ok {
+		var err error
+		if summary, err = s.RebuildStatsSummary(ctx, locationID, date); err != nil {
+			return err
+		}
+	}
+	return s.persistStatsSummary(ctx, locationID, date, summary)
+}
+
+// persistStatsSummary computes a location's averages, wait time range,
+// rates and percentiles of the day, stores them in the summary and persists
+// the whole summary as the location's queue_statistics row of the day,
+// along with its hourly rows.
+func (s *QueueService) persistStatsSummary(ctx context.Context, locationID string, date time.Time, summary map[string]string) error {
+	dayStart, dayEnd := clock.DayBounds(date)
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Select("estimated_ready_time", "actual_start_time", "actual_ready_time", "created_at").
+		Where("location_id = ? AND created_at >= ? AND created_at < ? AND actual_start_time IS NOT NULL", locationID, dayStart, dayEnd).
+		Find(&entries).Error; err != nil {
+		return err
+	}
+	averages := computeLocationKPIs("", entries)
+	longestWait, shortestWait := waitTimeRange(entries)
+
+	var currentLoad float64
+	if config, err := s.GetConfiguration(ctx); err == nil && config.MaxConcurrentOrders > 0 {
+		inProgress, _ := strconv.Atoi(summary["in_progress_count"])
+		currentLoad = roundRate(float64(inProgress) / float64(config.MaxConcurrentOrders) * 100)
+	}
+
+	summary["avg_wait_time"] = strconv.Itoa(averages.AvgWaitTime)
+	summary["avg_preparation_time"] = strconv.Itoa(averages.AvgPreparationTime)
+	summary["on_time_completion_rate"] = strconv.FormatFloat(averages.SLAComplianceRate, 'f', 2, 64)
+	summary["current_load"] = strconv.FormatFloat(currentLoad, 'f', 2, 64)
+	summary["longest_wait_time"] = strconv.Itoa(longestWait)
+	summary["shortest_wait_time"] = strconv.Itoa(shortestWait)
+	summary["no_show_rate"] = strconv.FormatFloat(noShowRate(summary), 'f', 2, 64)
+	storePercentiles(summary, computeTimePercentiles(entries))
+
+	if rdb := database.GetRedis(); rdb != nil {
+		computed := []interface{}{
+			"avg_wait_time", summary["avg_wait_time"],
+			"avg_preparation_time", summary["avg_preparation_time"],
+			"on_time_completion_rate", summary["on_time_completion_rate"],
+			"current_load", summary["current_load"],
+			"longest_wait_time", summary["longest_wait_time"],
+			"shortest_wait_time", summary["shortest_wait_time"],
+			"no_show_rate", summary["no_show_rate"],
+		}
+		for _, field := range percentileSummaryFields {
+			computed = append(computed, field, summary[field])
+		}
+		if err := rdb.HSet(ctx, statsSummaryKey(locationID, date), computed...).Err(); err != nil {
+			log.Printf("Failed to store stats averages: %v", err)
+		}
+	}
+
+	response := statsResponseFromSummary(locationID, date, summary)
+	noShow, _ := strconv.Atoi(summary["no_show_today"])
+	expired, _ := strconv.Atoi(summary["expired_today"])
+
+	stats := models.QueueStatistics{
+		ID:                   utils.GenerateUUID(),
+		LocationID:           locationID,
+		Date:                 date,
+		TotalInQueue:         response.TotalInQueue,
+		WaitingCount:         response.WaitingCount,
+		InProgressCount:      response.InProgressCount,
+		ReadyCount:           response.ReadyCount,
+		CompletedToday:       response.CompletedToday,
+		CancelledToday:       response.CancelledToday,
+		NoShowToday:          noShow,
+		ExpiredToday:         expired,
+		WalkInsToday:         response.WalkInsToday,
+		AvgWaitTime:          response.AvgWaitTime,
+		AvgPreparationTime:   response.AvgPreparationTime,
+		TimePercentiles:      response.TimePercentiles,
+		LongestWaitTime:      response.LongestWaitTime,
+		ShortestWaitTime:     response.ShortestWaitTime,
+		CurrentLoad:          response.CurrentLoad,
+		OnTimeCompletionRate: response.OnTimeCompletionRate,
+		NoShowRate:           response.NoShowRate,
+		UpdatedAt:            s.clock.Now().UTC(),
+	}
+
+	if err := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "location_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns(append([]string{
+			"total_in_queue", "waiting_count", "in_progress_count", "ready_count",
+			"completed_today", "cancelled_today", "no_show_today", "expired_today",
+			"walk_ins_today", "avg_wait_time", "avg_preparation_time", "longest_wait_time", "shortest_wait_time",
+			"current_load", "on_time_completion_rate", "no_show_rate", "updated_at",
+		}, percentileSummaryFields...)),
+	}).Create(&stats).Error; err != nil {
+		return err
+	}
+
+	return s.flushHourlyStatistics(ctx, locationID, date)
+}
+
+// StartStatsFlusher periodically persists today's summaries until ctx is cancelled
+func (s *QueueService) StartStatsFlusher(ctx context.Context, interval time.Duration) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			if err := s.UpdateStatistics(ctx); err != nil {
+				log.Printf("Failed to flush statistics: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
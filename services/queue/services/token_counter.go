@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/database"
+	"gin-quickstart/models"
+	"gin-quickstart/realtime"
+	"gin-quickstart/utils"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// tokenCounterTTL matches the expiry IncrementTokenCounter sets
+const tokenCounterTTL = 48 * time.Hour
+
+func tokenCounterKey(locationID string, date time.Time) string {
+	return fmt.Sprintf("queue:token:counter:%s:%s", locationID, date.Format("2006-01-02"))
+}
+
+// nextTokenNumber hands out the location's next token of the day with a Redis
+// INCR, so concurrent creates never share a number. Each location numbers its
+// tokens under its own prefix (A001, B001, ...). queue_token_counter is only a
+// periodically persisted copy, used to seed the Redis counter; without Redis
+// queue_token_counter is incremented directly under a row lock.
+func (s *QueueService) nextTokenNumber(ctx context.Context, location *models.QueueLocation) (string, error) {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return utils.GenerateTokenNumber(ctx, s.db, s.clock.Now(), location.ID, location.TokenPrefix)
+	}
+
+	today := clock.Date(s.clock.Now())
+	if err := s.seedTokenCounter(ctx, location, today); err != nil {
+		return "", err
+	}
+
+	number, err := realtime.NewRealtimeService().IncrementTokenCounter(ctx, location.ID, today.Format("2006-01-02"))
+	if err != nil {
+		return "", fmt.Errorf("failed to increment token counter: %w", err)
+	}
+	return fmt.Sprintf("%s%03d", location.TokenPrefix, number), nil
+}
+
+// seedTokenCounter initializes a missing Redis counter (first token of the day,
+// or Redis lost its data) from MySQL, so numbers already handed out are skipped.
+// Tokens issued after the last persist are covered by counting today's entries.
+func (s *QueueService) seedTokenCounter(ctx context.Context, location *models.QueueLocation, date time.Time) error {
+	rdb := database.GetRedis()
+	key := tokenCounterKey(location.ID, date)
+
+	exists, err := rdb.Exists(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read token counter: %w", err)
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	var persisted int64
+	s.db.WithContext(ctx).Model(&models.QueueTokenCounter{}).
+		Where("location_id = ? AND date = ?", location.ID, date).
+		Select("COALESCE(MAX(current_number), 0)").
+		Scan(&persisted)
+
+	// Only tokens under the current prefix count; a reset starts a new one
+	var issued int64
+	dayStart, dayEnd := clock.DayBounds(date)
+	s.db.WithContext(ctx).Unscoped().Model(&models.QueueEntry{}).
+		Where("location_id = ? AND token_number LIKE ? AND created_at >= ? AND created_at < ?",
+			location.ID, location.TokenPrefix+"%", dayStart, dayEnd).
+		Count(&issued)
+
+	// SETNX: another replica may have seeded it in the meantime
+	if err := rdb.SetNX(ctx, key, max(persisted, issued), tokenCounterTTL).Err(); err != nil {
+		return fmt.Errorf("failed to seed token counter: %w", err)
+	}
+	return nil
+}
+
+// ErrTokenNumbersTaken is returned when resetting the token counter would
+// hand out numbers already issued today
+var ErrTokenNumbersTaken = newError(KindConflict, "TOKEN_NUMBERS_TAKEN", "token numbers under this prefix were already issued today; choose a new token prefix")
+
+// ErrNoFreeTokenPrefix is returned when rotating a location's token prefix
+// finds every other prefix taken
+var ErrNoFreeTokenPrefix = newError(KindConflict, "NO_FREE_TOKEN_PREFIX", "no free token prefix")
+
+// resetTokenCounter restarts a location's numbering for today at 1 under
+// prefix, which becomes the location's token prefix. Token numbers are
+// unique, so the prefix must not have been used for today's tokens yet.
+func (s *QueueService) resetTokenCounter(ctx context.Context, location *models.QueueLocation, prefix string) error {
+	now := s.clock.Now().UTC()
+	today := clock.Date(now)
+
+	var taken int64
+	if err := s.db.WithContext(ctx).Unscoped().Model(&models.QueueEntry{}).
+		Where("token_number LIKE ? AND created_at >= ?", prefix+"%", clock.DayStart(today)).
+		Count(&taken).Error; err != nil {
+		return err
+	}
+	if taken > 0 {
+		return ErrTokenNumbersTaken
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if prefix != location.TokenPrefix {
+			if err := tx.Model(location).Updates(map[string]interface{}{
+				"token_prefix": prefix,
+				"updated_at":   now,
+			}).Error; err != nil {
+				if errors.Is(err, gorm.ErrDuplicatedKey) {
+					return ErrLocationExists
+				}
+				return err
+			}
+			location.TokenPrefix = prefix
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "location_id"}, {Name: "date"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"current_number": 0,
+				"prefix":         prefix,
+				"last_reset_at":  now,
+			}),
+		}).Create(&models.QueueTokenCounter{
+			ID:          utils.GenerateUUID(),
+			LocationID:  location.ID,
+			Date:        today,
+			Prefix:      prefix,
+			LastResetAt: now,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// The next token reseeds the Redis counter from the reset row
+	if rdb := database.GetRedis(); rdb != nil {
+		if err := rdb.Del(ctx, tokenCounterKey(location.ID, today)).Err(); err != nil {
+			return fmt.Errorf("failed to reset token counter: %w", err)
+		}
+	}
+	return nil
+}
+
+// PersistTokenCounter copies today's Redis token counters of the active
+// locations to queue_token_counter. The stored numbers never go backwards.
+func (s *QueueService) PersistTokenCounter(ctx context.Context) error {
+	if database.GetRedis() == nil {
+		return nil
+	}
+
+	var locations []models.QueueLocation
+	if err := s.db.WithContext(ctx).Where("is_active = ?", true).Find(&locations).Error; err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range locations {
+		if err := s.persistTokenCounter(ctx, &locations[i]); err != nil {
+			errs = append(errs, fmt.Errorf("location %s: %w", locations[i].ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *QueueService) persistTokenCounter(ctx context.Context, location *models.QueueLocation) error {
+	now := s.clock.Now().UTC()
+	today := clock.Date(now)
+	current, err := database.GetRedis().Get(ctx, tokenCounterKey(location.ID, today)).Int()
+	if err != nil {
+		// Nothing handed out yet today
+		if errors.Is(err, redis.Nil) {
+			return nil
+		}
+		return err
+	}
+
+	counter := models.QueueTokenCounter{
+		ID:            utils.GenerateUUID(),
+		LocationID:    location.ID,
+		Date:          today,
+		CurrentNumber: current,
+		Prefix:        location.TokenPrefix,
+		LastResetAt:   now,
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "location_id"}, {Name: "date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"current_number": gorm.Expr("GREATEST(queue_token_counter.current_number, ?)", current),
+		}),
+	}).Create(&counter).Error
+}
+
+// StartTokenCounterPersister periodically persists the token counter until ctx
+// is cancelled, persisting once more on the way out
+func (s *QueueService) StartTokenCounterPersister(ctx context.Context, interval time.Duration) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			if err := s.PersistTokenCounter(ctx); err != nil {
+				log.Printf("Failed to persist token counter: %v", err)
+			}
+		case <-ctx.Done():
+			if err := s.PersistTokenCounter(context.WithoutCancel(ctx)); err != nil {
+				log.Printf("Failed to persist token counter: %v", err)
+			}
+			return
+		}
+	}
+}
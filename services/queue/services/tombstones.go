@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+const anonymizedUserID = "anonymized"
+
+// DeleteQueueEntry soft-deletes an entry, leaving a tombstone. Its dependent
+// rows are kept so it can be restored.
+func (s *QueueService) DeleteQueueEntry(ctx context.Context, entryID string, reason *string, performedBy string) (*models.QueueEntryTombstone, error) {
+	entry, err := s.GetQueueEntryByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	tombstone := s.newTombstone(entry, "DELETED", reason, performedBy)
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.QueueEntry{}, "id = ?", entry.ID).Error; err != nil {
+			return err
+		}
+		return tx.Create(tombstone).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.afterTombstone(ctx, tombstone)
+	s.recordStatusTransition(ctx, entry, entry.Status, "")
+
+	// Close the gap left in the queue
+	go s.RecalculatePositions(WithLocation(context.WithoutCancel(ctx), entry.LocationID))
+
+	return tombstone, nil
+}
+
+// AnonymizeQueueEntry strips personal data from an entry, leaving a tombstone
+func (s *QueueService) AnonymizeQueueEntry(ctx context.Context, entryID string, reason *string, performedBy string) (*models.QueueEntryTombstone, error) {
+	entry, err := s.GetQueueEntryByID(ctx, entryID)
+	if err != nil {
+		return nil, err
+	}
+
+	tombstone := s.newTombstone(entry, "ANONYMIZED", reason, performedBy)
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.QueueEntry{}).Where("id = ?", entry.ID).Updates(map[string]interface{}{
+			"user_id":          anonymizedUserID,
+			"user_name":        nil,
+			"user_phone":       nil,
+			"notes":            nil,
+			"special_handling": nil,
+			"updated_at":       s.clock.Now().UTC(),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Create(tombstone).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.afterTombstone(ctx, tombstone)
+
+	return tombstone, nil
+}
+
+// GetTombstones lists tombstones created since the given time
+func (s *QueueService) GetTombstones(ctx context.Context, since *time.Time) ([]models.QueueEntryTombstone, error) {
+	query := s.db.WithContext(ctx).Order("created_at ASC")
+	if since != nil {
+		query = query.Where("created_at >= ?", *since)
+	}
+
+	var tombstones []models.QueueEntryTombstone
+	if err := query.Find(&tombstones).Error; err != nil {
+		return nil, err
+	}
+	return tombstones, nil
+}
+
+// PurgeExpiredTombstones removes tombstones older than the retention period
+func (s *QueueService) PurgeExpiredTombstones(ctx context.Context, retention time.Duration) (int64, error) {
+	cutoff := s.clock.Now().UTC().Add(-retention)
+	result := s.db.WithContext(ctx).Where("created_at < ?", cutoff).Delete(&models.QueueEntryTombstone{})
+	return result.RowsAffected, result.Error
+}
+
+// StartTombstonePurger periodically purges expired tombstones until ctx is cancelled
+func (s *QueueService) StartTombstonePurger(ctx context.Context, retention, interval time.Duration) {
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			purged, err := s.PurgeExpiredTombstones(ctx, retention)
+			if err != nil {
+				log.Printf("Failed to purge tombstones: %v", err)
+				continue
+			}
+			if purged > 0 {
+				log.Printf("Purged %d expired tombstones", purged)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *QueueService) afterTombstone(ctx context.Context, tombstone *models.QueueEntryTombstone) {
+	utils.InvalidateQueueCache(ctx, tombstone.QueueEntryID)
+	s.queueChanged(ctx, tombstone.QueueEntryID)
+
+	if s.publisher != nil {
+		if err := s.publisher.PublishQueueEntryTombstone(ctx, tombstone); err != nil {
+			log.Printf("Failed to publish tombstone event: %v", err)
+		}
+	}
+}
+
+func (s *QueueService) newTombstone(entry *models.QueueEntry, action string, reason *string, performedBy string) *models.QueueEntryTombstone {
+	return &models.QueueEntryTombstone{
+		ID:           utils.GenerateUUID(),
+		QueueEntryID: entry.ID,
+		OrderID:      entry.OrderID,
+		TokenNumber:  entry.TokenNumber,
+		Action:       action,
+		Reason:       reason,
+		PerformedBy:  performedBy,
+		CreatedAt:    s.clock.Now().UTC(),
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"gin-quickstart/models"
+)
+
+var (
+	// ErrTooManyActiveEntries is returned when a user already has as many
+	// unfinished queue entries as allowed
+	ErrTooManyActiveEntries = newError(KindRateLimited, "TOO_MANY_ACTIVE_ENTRIES", "too many active queue entries")
+	// ErrEntryRateLimited is returned when a user created too many queue
+	// entries in the last minute
+	ErrEntryRateLimited = newError(KindRateLimited, "ENTRY_RATE_LIMITED", "too many queue entries created; slow down")
+)
+
+// entryRateWindow is the window creations per user are counted over
+const entryRateWindow = time.Minute
+
+// unfinishedStatuses are the statuses of entries a customer is still waiting on
+var unfinishedStatuses = []string{"PENDING_PAYMENT", "SCHEDULED", "WAITING", "IN_PROGRESS", "READY"}
+
+// userEntryLimits caps entries per user; zero disables a cap
+var userEntryLimits struct {
+	maxActive int
+	perMinute int
+}
+
+// SetUserEntryLimits sets how many unfinished entries a user may have and how
+// many they may create per minute; zero disables a limit
+func SetUserEntryLimits(maxActive, perMinute int) {
+	userEntryLimits.maxActive = maxActive
+	userEntryLimits.perMinute = perMinute
+}
+
+// CheckUserEntryLimits refuses a new entry for a user at either limit, with
+// the time to wait before trying again. Limits are checked before insert, so
+// concurrent creates may overshoot them by a few entries.
+func (s *QueueService) CheckUserEntryLimits(ctx context.Context, userID string) error {
+	if userEntryLimits.perMinute > 0 {
+		since := s.clock.Now().UTC().Add(-entryRateWindow)
+		var recent []time.Time
+		if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+			Where("user_id = ? AND created_at > ?", userID, since).
+			Order("created_at ASC").
+			Limit(userEntryLimits.perMinute).
+			Pluck("created_at", &recent).Error; err != nil {
+			return err
+		}
+		if len(recent) >= userEntryLimits.perMinute {
+			// One more is allowed once the oldest creation leaves the window
+			return &RetryError{Err: ErrEntryRateLimited, RetryAfter: recent[0].Sub(since)}
+		}
+	}
+
+	if userEntryLimits.maxActive > 0 {
+		var active int64
+		if err := s.db.WithContext(ctx).Model(&models.QueueEntry{}).
+			Where("user_id = ? AND status IN ?", userID, unfinishedStatuses).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active >= int64(userEntryLimits.maxActive) {
+			// An entry finishing frees a place; there's no telling when
+			return &RetryError{Err: ErrTooManyActiveEntries, RetryAfter: entryRateWindow}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gin-quickstart/clock"
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+)
+
+const (
+	// waitModelMaxSamples caps the completed entries a training run loads
+	waitModelMaxSamples = 5000
+	// waitModelMaxMinutes drops abandoned or forgotten entries from training
+	waitModelMaxMinutes = 240
+	// waitModelQueueWindow is how long before an entry joined the entries
+	// still ahead of it can have joined
+	waitModelQueueWindow = 12 * time.Hour
+)
+
+// WaitFeatures describe an entry's place in the queue when predicting its wait
+type WaitFeatures struct {
+	// Served counts the entries served up to and including this one,
+	// across both lanes
+	Served     int
+	ItemCount  int
+	HourOfDay  int // in the restaurant's time zone
+	StaffCount int // staff on shift at the location
+}
+
+// WaitPredictor predicts the minutes until an entry is ready. It reports
+// false when it has no prediction, and the linear formula answers instead.
+type WaitPredictor interface {
+	PredictWait(features WaitFeatures) (int, bool)
+}
+
+// TrainableWaitPredictor is a WaitPredictor refitted from completed entries
+type TrainableWaitPredictor interface {
+	WaitPredictor
+	Fit(samples []WaitSample) (*WaitModelFit, error)
+}
+
+// WaitSample is a completed entry: its features when it joined, how long it
+// waited until ready, and what the linear formula would have estimated
+type WaitSample struct {
+	Features      WaitFeatures
+	ActualMinutes float64
+	LinearMinutes float64
+}
+
+// WaitModelFit summarises a training run
+type WaitModelFit struct {
+	Samples int
+	// Mean absolute errors in minutes on entries held out of the fit
+	MeanAbsError       float64
+	LinearMeanAbsError float64
+	// Active is whether the model now predicts waits
+	Active bool
+}
+
+var waitPredictor WaitPredictor
+
+// SetWaitPredictor sets the wait time predictor used by queue services
+func SetWaitPredictor(predictor WaitPredictor) {
+	waitPredictor = predictor
+}
+
+// waitEstimator predicts the waits of a location's entries at one moment
+type waitEstimator struct {
+	predictor  WaitPredictor
+	config     *models.QueueConfiguration
+	hourOfDay  int
+	staffCount int
+}
+
+// newWaitEstimator captures the time of day and staff on shift at the
+// request's location for predicting waits
+func (s *QueueService) newWaitEstimator(ctx context.Context, config *models.QueueConfiguration) *waitEstimator {
+	estimator := &waitEstimator{
+		predictor: s.waitPredictor,
+		config:    config,
+		hourOfDay: clock.Local(s.clock.Now()).Hour(),
+	}
+	if estimator.predictor != nil {
+		var staff int64
+		s.db.WithContext(ctx).Model(&models.StaffShift{}).
+			Where("location_id = ? AND clock_out_at IS NULL", LocationFromContext(ctx)).
+			Count(&staff)
+		estimator.staffCount = int(staff)
+	}
+	return estimator
+}
+
+// laneWait estimates the wait of an entry of itemCount items at position in
+// its lane, falling back to the linear formula when the predictor can't
+func (e *waitEstimator) laneWait(isExpress bool, position, otherLength, prepTimePerItem, itemCount int) int {
+	if e.predictor != nil {
+		features := WaitFeatures{
+			Served:     position + otherLaneAhead(e.config, isExpress, position, otherLength),
+			ItemCount:  itemCount,
+			HourOfDay:  e.hourOfDay,
+			StaffCount: e.staffCount,
+		}
+		if minutes, ok := e.predictor.PredictWait(features); ok {
+			return minutes
+		}
+	}
+	return laneWaitTime(e.config, isExpress, position, otherLength, prepTimePerItem)
+}
+
+// TrainWaitModel refits a trainable wait predictor from the entries that
+// joined the queue within lookback and became ready. The model takes over
+// from the linear formula only while it beats it on entries held out of
+// the fit.
+func (s *QueueService) TrainWaitModel(ctx context.Context, lookback time.Duration) (*WaitModelFit, error) {
+	trainable, ok := s.waitPredictor.(TrainableWaitPredictor)
+	if !ok {
+		return &WaitModelFit{}, nil
+	}
+
+	samples, err := s.waitSamples(ctx, s.clock.Now().UTC().Add(-lookback))
+	if err != nil {
+		return nil, err
+	}
+	return trainable.Fit(samples)
+}
+
+// waitSamples builds a training sample from each entry that joined since
+// and became ready. Entries ahead of one are those that joined before it and
+// became ready after it joined; scheduled entries, which joined later than
+// they were created, are left out.
+func (s *QueueService) waitSamples(ctx context.Context, since time.Time) ([]WaitSample, error) {
+	var entries []models.QueueEntry
+	if err := s.db.WithContext(ctx).
+		Select("id", "location_id", "item_count", "created_at", "actual_ready_time").
+		Where("created_at >= ? AND actual_ready_time IS NOT NULL AND requested_pickup_time IS NULL", since.Add(-waitModelQueueWindow)).
+		Order("created_at DESC").
+		Limit(waitModelMaxSamples).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	// Oldest first, so the entries ahead of one come before it
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	var shifts []models.StaffShift
+	if err := s.db.WithContext(ctx).
+		Where("clock_in_at <= ? AND (clock_out_at IS NULL OR clock_out_at >= ?)", s.clock.Now().UTC(), entries[0].CreatedAt).
+		Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+
+	configs := make(map[string]*models.QueueConfiguration)
+	samples := make([]WaitSample, 0, len(entries))
+	for i, entry := range entries {
+		if entry.CreatedAt.Before(since) {
+			continue
+		}
+		waited := entry.ActualReadyTime.Sub(entry.CreatedAt).Minutes()
+		if waited <= 0 || waited > waitModelMaxMinutes {
+			continue
+		}
+
+		served := 1
+		for j := i - 1; j >= 0 && entries[j].CreatedAt.After(entry.CreatedAt.Add(-waitModelQueueWindow)); j-- {
+			ahead := entries[j]
+			if ahead.LocationID == entry.LocationID && ahead.ActualReadyTime.After(entry.CreatedAt) {
+				served++
+			}
+		}
+
+		staff := 0
+		for _, shift := range shifts {
+			if shift.LocationID == entry.LocationID && !shift.ClockInAt.After(entry.CreatedAt) &&
+				(shift.ClockOutAt == nil || shift.ClockOutAt.After(entry.CreatedAt)) {
+				staff++
+			}
+		}
+
+		config, ok := configs[entry.LocationID]
+		if !ok {
+			loaded, err := s.GetConfiguration(WithLocation(ctx, entry.LocationID))
+			if err != nil {
+				return nil, err
+			}
+			config = loaded
+			configs[entry.LocationID] = config
+		}
+
+		samples = append(samples, WaitSample{
+			Features: WaitFeatures{
+				Served:     served,
+				ItemCount:  entry.ItemCount,
+				HourOfDay:  clock.Local(entry.CreatedAt).Hour(),
+				StaffCount: staff,
+			},
+			ActualMinutes: waited,
+			LinearMinutes: float64(utils.CalculateEstimatedWaitTime(served, config.AvgPreparationTimePerItem, config.BufferTime)),
+		})
+	}
+	return samples, nil
+}
+
+// StartWaitModelTrainer trains the wait predictor now and then every
+// interval until ctx is cancelled
+func (s *QueueService) StartWaitModelTrainer(ctx context.Context, interval, lookback time.Duration) {
+	if _, ok := s.waitPredictor.(TrainableWaitPredictor); !ok {
+		return
+	}
+
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		fit, err := s.TrainWaitModel(ctx, lookback)
+		if err != nil {
+			log.Printf("Failed to train wait model: %v", err)
+		} else if fit.Active {
+			log.Printf("Wait model trained on %d entries: mean error %.1f min (linear formula %.1f min)",
+				fit.Samples, fit.MeanAbsError, fit.LinearMeanAbsError)
+		} else {
+			log.Printf("Wait model inactive after training on %d entries; using the linear formula", fit.Samples)
+		}
+
+		select {
+		case <-ticker.C():
+		case <-ctx.Done():
+			return
+		}
+	}
+}
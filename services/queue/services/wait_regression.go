@@ -0,0 +1,176 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// waitModelRidge damps the regression weights so a feature that barely
+// varies (say, staff on a single-person counter) can't blow up the fit
+const waitModelRidge = 1e-3
+
+// waitModelHoldoutEvery holds every nth sample out of the fit to compare
+// the model with the linear formula
+const waitModelHoldoutEvery = 5
+
+// RegressionWaitPredictor predicts waits with a least-squares regression
+// over the entries served ahead, item count, hour of day and staff on shift,
+// fitted to completed entries
+type RegressionWaitPredictor struct {
+	minSamples int
+
+	mu sync.RWMutex
+	// weights is nil while the linear formula predicts better
+	weights []float64
+}
+
+// NewRegressionWaitPredictor creates an untrained predictor that fits once
+// at least minSamples completed entries are available
+func NewRegressionWaitPredictor(minSamples int) *RegressionWaitPredictor {
+	return &RegressionWaitPredictor{minSamples: max(minSamples, waitModelHoldoutEvery*len(waitRegressors(WaitFeatures{})))}
+}
+
+// waitRegressors expands features into the model's inputs. The hour goes
+// round the clock as a sine/cosine pair so 23:00 and 00:00 are neighbours,
+// and the entries ahead are also counted per member of staff sharing them.
+func waitRegressors(features WaitFeatures) []float64 {
+	angle := 2 * math.Pi * float64(features.HourOfDay) / 24
+	served := float64(features.Served)
+	return []float64{
+		1,
+		served,
+		float64(features.ItemCount),
+		math.Sin(angle),
+		math.Cos(angle),
+		float64(features.StaffCount),
+		served / float64(max(features.StaffCount, 1)),
+	}
+}
+
+// PredictWait predicts an entry's wait, or reports false while untrained
+func (p *RegressionWaitPredictor) PredictWait(features WaitFeatures) (int, bool) {
+	p.mu.RLock()
+	weights := p.weights
+	p.mu.RUnlock()
+	if weights == nil {
+		return 0, false
+	}
+
+	minutes := predictRegression(weights, waitRegressors(features))
+	if math.IsNaN(minutes) || minutes < 1 {
+		return 0, false
+	}
+	return int(math.Round(minutes)), true
+}
+
+// Fit refits the model, keeping it only if it beats the linear formula on
+// the held-out samples. With too few samples the current model stays.
+func (p *RegressionWaitPredictor) Fit(samples []WaitSample) (*WaitModelFit, error) {
+	fit := &WaitModelFit{Samples: len(samples)}
+	if len(samples) < p.minSamples {
+		p.mu.RLock()
+		fit.Active = p.weights != nil
+		p.mu.RUnlock()
+		return fit, nil
+	}
+
+	var train, holdout []WaitSample
+	for i, sample := range samples {
+		if i%waitModelHoldoutEvery == 0 {
+			holdout = append(holdout, sample)
+		} else {
+			train = append(train, sample)
+		}
+	}
+
+	weights, err := fitRegression(train)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sample := range holdout {
+		fit.MeanAbsError += math.Abs(predictRegression(weights, waitRegressors(sample.Features)) - sample.ActualMinutes)
+		fit.LinearMeanAbsError += math.Abs(sample.LinearMinutes - sample.ActualMinutes)
+	}
+	fit.MeanAbsError /= float64(len(holdout))
+	fit.LinearMeanAbsError /= float64(len(holdout))
+	fit.Active = fit.MeanAbsError < fit.LinearMeanAbsError
+
+	p.mu.Lock()
+	if fit.Active {
+		p.weights = weights
+	} else {
+		p.weights = nil
+	}
+	p.mu.Unlock()
+	return fit, nil
+}
+
+func predictRegression(weights, regressors []float64) float64 {
+	sum := 0.0
+	for i, weight := range weights {
+		sum += weight * regressors[i]
+	}
+	return sum
+}
+
+// fitRegression solves the ridge-regularised normal equations
+// (XᵀX + λI)w = Xᵀy, leaving the intercept unregularised
+func fitRegression(samples []WaitSample) ([]float64, error) {
+	n := len(waitRegressors(WaitFeatures{}))
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = make([]float64, n+1)
+	}
+
+	for _, sample := range samples {
+		x := waitRegressors(sample.Features)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				a[i][j] += x[i] * x[j]
+			}
+			a[i][n] += x[i] * sample.ActualMinutes
+		}
+	}
+	for i := 1; i < n; i++ {
+		a[i][i] += waitModelRidge * float64(len(samples))
+	}
+
+	return solveLinearSystem(a)
+}
+
+// solveLinearSystem solves the augmented system a by Gaussian elimination
+// with partial pivoting
+func solveLinearSystem(a [][]float64) ([]float64, error) {
+	n := len(a)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, errors.New("wait model features are degenerate")
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k <= n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+		}
+	}
+
+	weights := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := a[row][n]
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * weights[k]
+		}
+		weights[row] = sum / a[row][row]
+	}
+	return weights, nil
+}
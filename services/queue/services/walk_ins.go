@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+)
+
+// CreateWalkInEntry queues a walk-in customer who has no upstream order. The
+// entry gets the WALK_IN token type, which keeps it apart in the statistics,
+// and a generated order ID. With no account behind it, the customer hears
+// about the order by text.
+func (s *QueueService) CreateWalkInEntry(ctx context.Context, req *models.CreateWalkInRequest) (*models.QueueEntry, error) {
+	return s.CreateQueueEntry(ctx, &models.CreateQueueEntryRequest{
+		OrderID:         utils.GenerateUUID(),
+		LocationID:      req.LocationID,
+		UserName:        req.UserName,
+		UserPhone:       req.UserPhone,
+		TokenType:       models.TokenTypeWalkIn,
+		SpecialHandling: req.SpecialHandling,
+		ItemCount:       req.ItemCount,
+	})
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gin-quickstart/models"
+	"gin-quickstart/utils"
+
+	"gorm.io/gorm"
+)
+
+// Webhook event types
+const (
+	WebhookEventEntryCreated  = "queue.entry.created"
+	WebhookEventStatusChanged = "queue.status.changed"
+)
+
+const (
+	maxWebhookDeliveryPageSize = 200
+	// webhookBatchSize bounds the deliveries attempted per dispatcher run
+	webhookBatchSize = 100
+	// webhookLease keeps other instances off a delivery while it is attempted
+	webhookLease      = 2 * time.Minute
+	webhookRetryBase  = 30 * time.Second
+	webhookRetryLimit = time.Hour
+)
+
+// webhookWake starts a dispatcher run as soon as new deliveries are queued
+var webhookWake = make(chan struct{}, 1)
+
+// ListWebhooks lists webhook subscriptions
+func (s *QueueService) ListWebhooks(ctx context.Context) ([]models.WebhookSubscription, error) {
+	var webhooks []models.WebhookSubscription
+	if err := s.db.WithContext(ctx).Order("created_at ASC").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// CreateWebhook subscribes a URL to queue events. The generated signing
+// secret is only returned here.
+func (s *QueueService) CreateWebhook(ctx context.Context, req *models.CreateWebhookRequest, userID string) (*models.WebhookCreatedResponse, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now().UTC()
+	webhook := models.WebhookSubscription{
+		ID:          utils.GenerateUUID(),
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		Secret:      secret,
+		Description: req.Description,
+		IsActive:    true,
+		CreatedBy:   &userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if err := s.db.WithContext(ctx).Create(&webhook).Error; err != nil {
+		return nil, err
+	}
+	return &models.WebhookCreatedResponse{WebhookSubscription: webhook, Secret: secret}, nil
+}
+
+// UpdateWebhook changes the URL, events, description or active flag of a subscription
+func (s *QueueService) UpdateWebhook(ctx context.Context, webhookID string, req *models.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	var webhook models.WebhookSubscription
+	if err := s.db.WithContext(ctx).Where("id = ?", webhookID).First(&webhook).Error; err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		webhook.URL = *req.URL
+	}
+	if len(req.EventTypes) > 0 {
+		webhook.EventTypes = req.EventTypes
+	}
+	if req.Description != nil {
+		webhook.Description = req.Description
+	}
+	if req.IsActive != nil {
+		webhook.IsActive = *req.IsActive
+	}
+	webhook.UpdatedAt = s.clock.Now().UTC()
+
+	if err := s.db.WithContext(ctx).Save(&webhook).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// DeleteWebhook removes a subscription along with its delivery log
+func (s *QueueService) DeleteWebhook(ctx context.Context, webhookID string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("subscription_id = ?", webhookID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id = ?", webhookID).Delete(&models.WebhookSubscription{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// ListWebhookDeliveries lists the deliveries of a subscription, newest first
+func (s *QueueService) ListWebhookDeliveries(ctx context.Context, webhookID, status string, limit, offset int) (*models.WebhookDeliveryListResponse, error) {
+	if limit <= 0 || limit > maxWebhookDeliveryPageSize {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var webhook models.WebhookSubscription
+	if err := s.db.WithContext(ctx).Where("id = ?", webhookID).First(&webhook).Error; err != nil {
+		return nil, err
+	}
+
+	query := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("subscription_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	return &models.WebhookDeliveryListResponse{
+		Deliveries: deliveries,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	}, nil
+}
+
+// RetryWebhookDelivery queues a delivery to be sent again with a fresh set of attempts
+func (s *QueueService) RetryWebhookDelivery(ctx context.Context, deliveryID string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := s.db.WithContext(ctx).Where("id = ?", deliveryID).First(&delivery).Error; err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now().UTC()
+	delivery.Status = "PENDING"
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	if err := s.db.WithContext(ctx).Model(&delivery).Updates(map[string]interface{}{
+		"status":          delivery.Status,
+		"attempts":        delivery.Attempts,
+		"next_attempt_at": now,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	wakeWebhookDispatcher()
+	return &delivery, nil
+}
+
+// emitWebhookEvent queues the event for every active subscription to it in
+// the background; the dispatcher sends it
+func (s *QueueService) emitWebhookEvent(ctx context.Context, eventType, entryID, previousStatus string) {
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+
+		var webhooks []models.WebhookSubscription
+		if err := s.db.WithContext(ctx).Where("is_active = ?", true).Find(&webhooks).Error; err != nil {
+			log.Printf("Failed to load webhooks for %s: %v", eventType, err)
+			return
+		}
+
+		var subscribed []models.WebhookSubscription
+		for _, webhook := range webhooks {
+			for _, subscribedType := range webhook.EventTypes {
+				if subscribedType == eventType {
+					subscribed = append(subscribed, webhook)
+					break
+				}
+			}
+		}
+		if len(subscribed) == 0 {
+			return
+		}
+
+		entry, err := s.GetQueueEntryByID(ctx, entryID)
+		if err != nil {
+			log.Printf("Failed to load entry %s for %s webhook: %v", entryID, eventType, err)
+			return
+		}
+
+		now := s.clock.Now().UTC()
+		event := models.WebhookEvent{
+			ID:        utils.GenerateUUID(),
+			Type:      eventType,
+			CreatedAt: now,
+			Data:      entry,
+		}
+		if eventType == WebhookEventStatusChanged {
+			event.Data = models.WebhookStatusChange{
+				PreviousStatus: previousStatus,
+				Status:         entry.Status,
+				Entry:          entry,
+			}
+		}
+		payload, err := json.Marshal(event)
+		if err != nil {
+			log.Printf("Failed to encode %s webhook for %s: %v", eventType, entryID, err)
+			return
+		}
+
+		deliveries := make([]models.WebhookDelivery, 0, len(subscribed))
+		for _, webhook := range subscribed {
+			deliveries = append(deliveries, models.WebhookDelivery{
+				ID:             utils.GenerateUUID(),
+				SubscriptionID: webhook.ID,
+				EventID:        event.ID,
+				EventType:      eventType,
+				Payload:        string(payload),
+				Status:         "PENDING",
+				NextAttemptAt:  &now,
+				CreatedAt:      now,
+			})
+		}
+		if err := s.db.WithContext(ctx).Create(&deliveries).Error; err != nil {
+			log.Printf("Failed to queue %s webhooks for %s: %v", eventType, entryID, err)
+			return
+		}
+
+		wakeWebhookDispatcher()
+	}()
+}
+
+func wakeWebhookDispatcher() {
+	select {
+	case webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// StartWebhookDispatcher sends pending webhook deliveries as they are queued
+// and retries failed ones every interval until maxAttempts is reached
+func (s *QueueService) StartWebhookDispatcher(ctx context.Context, interval, timeout time.Duration, maxAttempts int) {
+	client := &http.Client{Timeout: timeout}
+	ticker := s.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			s.dispatchWebhooks(ctx, client, maxAttempts)
+		case <-webhookWake:
+			s.dispatchWebhooks(ctx, client, maxAttempts)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *QueueService) dispatchWebhooks(ctx context.Context, client *http.Client, maxAttempts int) {
+	now := s.clock.Now().UTC()
+
+	var due []models.WebhookDelivery
+	if err := s.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", "PENDING", now).
+		Order("next_attempt_at ASC").
+		Limit(webhookBatchSize).
+		Find(&due).Error; err != nil {
+		log.Printf("Failed to load pending webhook deliveries: %v", err)
+		return
+	}
+
+	webhooks := make(map[string]*models.WebhookSubscription)
+	for i := range due {
+		delivery := &due[i]
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Claim the delivery so another instance doesn't send it too
+		lease := now.Add(webhookLease)
+		claim := s.db.WithContext(ctx).Model(&models.WebhookDelivery{}).
+			Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, "PENDING", delivery.NextAttemptAt).
+			Update("next_attempt_at", lease)
+		if claim.Error != nil || claim.RowsAffected == 0 {
+			continue
+		}
+
+		webhook, ok := webhooks[delivery.SubscriptionID]
+		if !ok {
+			webhook = &models.WebhookSubscription{}
+			if err := s.db.WithContext(ctx).Where("id = ?", delivery.SubscriptionID).First(webhook).Error; err != nil {
+				webhook = nil
+			}
+			webhooks[delivery.SubscriptionID] = webhook
+		}
+		if webhook == nil || !webhook.IsActive {
+			s.finishWebhookDelivery(ctx, delivery, "FAILED", nil, utils.StringPtr("webhook subscription is inactive or deleted"))
+			continue
+		}
+
+		s.attemptWebhookDelivery(ctx, client, webhook, delivery, maxAttempts)
+	}
+}
+
+// attemptWebhookDelivery POSTs the payload signed with the subscription's
+// secret. Receivers verify X-Webhook-Signature, "sha256=" followed by the hex
+// HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>".
+func (s *QueueService) attemptWebhookDelivery(ctx context.Context, client *http.Client, webhook *models.WebhookSubscription, delivery *models.WebhookDelivery, maxAttempts int) {
+	timestamp := strconv.FormatInt(s.clock.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write([]byte(timestamp + "." + delivery.Payload))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	var responseStatus *int
+	var deliveryErr error
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		deliveryErr = err
+	} else {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "queue-service-webhooks")
+		req.Header.Set("X-Webhook-Id", delivery.EventID)
+		req.Header.Set("X-Webhook-Event", delivery.EventType)
+		req.Header.Set("X-Webhook-Timestamp", timestamp)
+		req.Header.Set("X-Webhook-Signature", signature)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			deliveryErr = err
+		} else {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			resp.Body.Close()
+			responseStatus = &resp.StatusCode
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				deliveryErr = fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+			}
+		}
+	}
+
+	delivery.Attempts++
+	delivery.ResponseStatus = responseStatus
+	if deliveryErr == nil {
+		s.finishWebhookDelivery(ctx, delivery, "SUCCEEDED", responseStatus, nil)
+		return
+	}
+
+	lastError := utils.StringPtr(deliveryErr.Error())
+	if delivery.Attempts >= maxAttempts {
+		log.Printf("Webhook delivery %s to %s failed after %d attempts: %v", delivery.ID, webhook.URL, delivery.Attempts, deliveryErr)
+		s.finishWebhookDelivery(ctx, delivery, "FAILED", responseStatus, lastError)
+		return
+	}
+
+	// Back off exponentially: 30s, 1m, 2m, ... capped at an hour
+	backoff := webhookRetryBase << (delivery.Attempts - 1)
+	if backoff > webhookRetryLimit || backoff <= 0 {
+		backoff = webhookRetryLimit
+	}
+	if err := s.db.WithContext(ctx).Model(delivery).Updates(map[string]interface{}{
+		"attempts":        delivery.Attempts,
+		"response_status": responseStatus,
+		"last_error":      lastError,
+		"next_attempt_at": s.clock.Now().UTC().Add(backoff),
+	}).Error; err != nil {
+		log.Printf("Failed to record webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+func (s *QueueService) finishWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery, status string, responseStatus *int, lastError *string) {
+	updates := map[string]interface{}{
+		"status":          status,
+		"attempts":        delivery.Attempts,
+		"response_status": responseStatus,
+		"last_error":      lastError,
+		"next_attempt_at": nil,
+	}
+	if status == "SUCCEEDED" {
+		updates["delivered_at"] = s.clock.Now().UTC()
+	}
+	if err := s.db.WithContext(ctx).Model(delivery).Updates(updates).Error; err != nil {
+		log.Printf("Failed to record webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+func newWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
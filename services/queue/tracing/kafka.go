@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// producerMessageCarrier adapts sarama producer headers to a TextMapCarrier
+type producerMessageCarrier struct {
+	msg *sarama.ProducerMessage
+}
+
+func (c producerMessageCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c producerMessageCarrier) Set(key, value string) {
+	// Replace existing header so re-sent messages don't carry stale context
+	for i, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c producerMessageCarrier) Keys() []string {
+	keys := make([]string, len(c.msg.Headers))
+	for i, h := range c.msg.Headers {
+		keys[i] = string(h.Key)
+	}
+	return keys
+}
+
+// consumerMessageCarrier adapts sarama consumer headers to a TextMapCarrier
+type consumerMessageCarrier struct {
+	msg *sarama.ConsumerMessage
+}
+
+func (c consumerMessageCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c consumerMessageCarrier) Set(key, value string) {}
+
+func (c consumerMessageCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+	return keys
+}
+
+var (
+	_ propagation.TextMapCarrier = producerMessageCarrier{}
+	_ propagation.TextMapCarrier = consumerMessageCarrier{}
+)
+
+// StartProducerSpan starts a producer span and injects its context into the message headers
+func StartProducerSpan(ctx context.Context, msg *sarama.ProducerMessage) (context.Context, trace.Span) {
+	ctx, span := Tracer().Start(ctx, msg.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", msg.Topic),
+		),
+	)
+	otel.GetTextMapPropagator().Inject(ctx, producerMessageCarrier{msg: msg})
+	return ctx, span
+}
+
+// StartConsumerSpan extracts the upstream context from the message headers and starts a consumer span
+func StartConsumerSpan(ctx context.Context, msg *sarama.ConsumerMessage) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, consumerMessageCarrier{msg: msg})
+	return Tracer().Start(ctx, msg.Topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", msg.Topic),
+			attribute.Int("messaging.kafka.partition", int(msg.Partition)),
+			attribute.Int64("messaging.kafka.offset", msg.Offset),
+		),
+	)
+}
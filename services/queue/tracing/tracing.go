@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gin-quickstart/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "gin-quickstart"
+
+// Init configures the global tracer provider and propagators.
+// The returned function flushes and stops the exporter.
+func Init(cfg *config.Config) (func(context.Context) error, error) {
+	// Always propagate context so upstream traces pass through even when export is off
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.TracingEnabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(context.Background(),
+		otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint),
+		otlptracegrpc.WithInsecure(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+	)
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracingSampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	log.Printf("Tracing enabled: exporting to %s", cfg.OTLPEndpoint)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the service tracer
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
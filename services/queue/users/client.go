@@ -0,0 +1,83 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gin-quickstart/config"
+)
+
+// ErrUserNotFound is returned when the user service doesn't know a customer
+var ErrUserNotFound = errors.New("user not found")
+
+// Client looks customers up in the auth/user service
+type Client struct {
+	baseURL  string
+	token    string
+	vipTiers map[string]bool
+	client   *http.Client
+}
+
+// NewClient creates the user service client. Lookups share one timeout, so a
+// slow user service delays an order by at most USER_SERVICE_TIMEOUT_MS.
+func NewClient(cfg *config.Config) *Client {
+	vipTiers := make(map[string]bool)
+	for _, tier := range strings.Split(cfg.VIPCustomerTiers, ",") {
+		if tier = strings.ToUpper(strings.TrimSpace(tier)); tier != "" {
+			vipTiers[tier] = true
+		}
+	}
+
+	return &Client{
+		baseURL:  strings.TrimRight(cfg.UserServiceURL, "/"),
+		token:    cfg.UserServiceToken,
+		vipTiers: vipTiers,
+		client:   &http.Client{Timeout: time.Duration(cfg.UserServiceTimeoutMs) * time.Millisecond},
+	}
+}
+
+// GetCustomerTier returns the loyalty tier of a customer, upper-cased, or ""
+// when the customer has none
+func (c *Client) GetCustomerTier(ctx context.Context, userID string) (string, error) {
+	endpoint := fmt.Sprintf("%s/api/users/%s/tier", c.baseURL, url.PathEscape(userID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("user service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("user service returned HTTP %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Tier string `json:"tier"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid user service response: %w", err)
+	}
+	return strings.ToUpper(strings.TrimSpace(result.Tier)), nil
+}
+
+// IsVIPTier reports whether customers of a tier are served as VIP
+func (c *Client) IsVIPTier(tier string) bool {
+	return c.vipTiers[tier]
+}
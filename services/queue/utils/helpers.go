@@ -3,13 +3,17 @@ package utils
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"gin-quickstart/clock"
 	"gin-quickstart/database"
 	"gin-quickstart/models"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GenerateUUID generates a new UUID
@@ -17,49 +21,106 @@ func GenerateUUID() string {
 	return uuid.New().String()
 }
 
-// GenerateTokenNumber generates a sequential token number
-func GenerateTokenNumber(db interface{}) (string, error) {
-	// Implementation for token generation
-	today := time.Now().UTC().Truncate(24 * time.Hour)
-	
+// GenerateTokenNumber generates a location's sequential token number for the
+// day of now. Today's counter row is locked while it is incremented, so
+// concurrent callers never mint the same number.
+func GenerateTokenNumber(ctx context.Context, db *gorm.DB, now time.Time, locationID, prefix string) (string, error) {
+	today := clock.Date(now)
+
 	var counter models.QueueTokenCounter
-	result := database.GetDB().Where("date = ?", today).First(&counter)
-	
-	if result.Error != nil {
-		// Create new counter for today
-		counter = models.QueueTokenCounter{
-			ID:            GenerateUUID(),
-			Date:          today,
-			CurrentNumber: 1,
-			Prefix:        "A",
-			LastResetAt:   time.Now().UTC(),
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Create today's counter at zero unless another caller already has
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.QueueTokenCounter{
+			ID:          GenerateUUID(),
+			LocationID:  locationID,
+			Date:        today,
+			Prefix:      prefix,
+			LastResetAt: now.UTC(),
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("location_id = ? AND date = ?", locationID, today).
+			First(&counter).Error; err != nil {
+			return err
 		}
-		database.GetDB().Create(&counter)
-		return fmt.Sprintf("%s%03d", counter.Prefix, counter.CurrentNumber), nil
+
+		counter.CurrentNumber++
+		return tx.Model(&counter).Updates(map[string]interface{}{
+			"current_number": counter.CurrentNumber,
+			"prefix":         prefix,
+		}).Error
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token number: %w", err)
 	}
-	
-	// Increment counter
-	counter.CurrentNumber++
-	database.GetDB().Save(&counter)
-	
-	return fmt.Sprintf("%s%03d", counter.Prefix, counter.CurrentNumber), nil
+
+	return fmt.Sprintf("%s%03d", prefix, counter.CurrentNumber), nil
 }
 
-// CacheQueueEntry caches queue entry in Redis
+// CacheQueueEntry caches queue entry in Redis, along with the token and order
+// lookups that resolve to it
 func CacheQueueEntry(ctx context.Context, entry *models.QueueEntry) error {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return nil
+	}
+
 	data, err := json.Marshal(entry)
 	if err != nil {
 		return err
 	}
-	
-	key := fmt.Sprintf("queue:entry:%s", entry.ID)
-	return database.GetRedis().Set(ctx, key, data, 1*time.Hour).Err()
+
+	// Token numbers restart every day, so a token lookup must not outlive it
+	tokenTTL := 1 * time.Hour
+	if untilRollover := time.Until(clock.DayStart(clock.Date(entry.CreatedAt).AddDate(0, 0, 1))); untilRollover < tokenTTL {
+		tokenTTL = untilRollover
+	}
+
+	pipe := rdb.Pipeline()
+	pipe.Set(ctx, fmt.Sprintf("queue:entry:%s", entry.ID), data, 1*time.Hour)
+	pipe.Set(ctx, fmt.Sprintf("queue:order:%s", entry.OrderID), entry.ID, 1*time.Hour)
+	if tokenTTL > 0 {
+		pipe.Set(ctx, fmt.Sprintf("queue:token:%s", entry.TokenNumber), entry.ID, tokenTTL)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetCachedQueueEntryByToken retrieves cached queue entry by token number
+func GetCachedQueueEntryByToken(ctx context.Context, token string) (*models.QueueEntry, error) {
+	return getCachedQueueEntryVia(ctx, fmt.Sprintf("queue:token:%s", token))
+}
+
+// GetCachedQueueEntryByOrderID retrieves cached queue entry by order ID
+func GetCachedQueueEntryByOrderID(ctx context.Context, orderID string) (*models.QueueEntry, error) {
+	return getCachedQueueEntryVia(ctx, fmt.Sprintf("queue:order:%s", orderID))
+}
+
+// getCachedQueueEntryVia resolves an entry ID stored under key, then the entry
+func getCachedQueueEntryVia(ctx context.Context, key string) (*models.QueueEntry, error) {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return nil, errors.New("redis not initialized")
+	}
+
+	entryID, err := rdb.Get(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	return GetCachedQueueEntry(ctx, entryID)
 }
 
 // GetCachedQueueEntry retrieves cached queue entry from Redis
 func GetCachedQueueEntry(ctx context.Context, entryID string) (*models.QueueEntry, error) {
+	rdb := database.GetRedis()
+	if rdb == nil {
+		return nil, errors.New("redis not initialized")
+	}
+
 	key := fmt.Sprintf("queue:entry:%s", entryID)
-	data, err := database.GetRedis().Get(ctx, key).Result()
+	data, err := rdb.Get(ctx, key).Result()
 	if err != nil {
 		return nil, err
 	}
@@ -72,8 +133,13 @@ func GetCachedQueueEntry(ctx context.Context, entryID string) (*models.QueueEntr
 	return &entry, nil
 }
 
-// InvalidateQueueCache invalidates queue cache
+// InvalidateQueueCache invalidates queue cache. Token and order lookups are
+// left in place; they resolve to the entry, which is reloaded on the next read.
 func InvalidateQueueCache(ctx context.Context, entryID string) error {
+	if database.GetRedis() == nil {
+		return nil
+	}
+
 	key := fmt.Sprintf("queue:entry:%s", entryID)
 	return database.GetRedis().Del(ctx, key).Err()
 }
@@ -83,9 +149,9 @@ func CalculateEstimatedWaitTime(position int, avgPrepTimePerItem int, bufferTime
 	return (position * avgPrepTimePerItem) + bufferTime
 }
 
-// CalculateEstimatedReadyTime calculates estimated ready time
-func CalculateEstimatedReadyTime(estimatedWaitTime int) time.Time {
-	return time.Now().UTC().Add(time.Duration(estimatedWaitTime) * time.Minute)
+// CalculateEstimatedReadyTime calculates estimated ready time from now
+func CalculateEstimatedReadyTime(now time.Time, estimatedWaitTime int) time.Time {
+	return now.UTC().Add(time.Duration(estimatedWaitTime) * time.Minute)
 }
 
 // StringPtr returns pointer to string
@@ -102,3 +168,8 @@ func IntPtr(i int) *int {
 func TimePtr(t time.Time) *time.Time {
 	return &t
 }
+
+// Float64Ptr returns pointer to float64
+func Float64Ptr(f float64) *float64 {
+	return &f
+}